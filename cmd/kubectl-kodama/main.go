@@ -1,25 +1,47 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/illumination-k/kodama/pkg/application"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
 	"github.com/illumination-k/kodama/pkg/presentation/commands"
 )
 
 func main() {
+	// Cancel the command context on Ctrl+C / SIGTERM so cleanup can run
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		// Restore default handling so a second Ctrl+C exits immediately
+		stop()
+	}()
+
+	// Let kubectl subprocesses use KODAMA_K8S_* token auth as well
+	cleanupKubeconfig, err := kubernetes.ExportTokenKubeconfig()
+	if err != nil {
+		stop()
+		os.Exit(commands.RenderError(os.Stderr, err))
+	}
+
 	// Initialize application with all dependencies
 	app, err := application.NewApp("")
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error initializing application: %v\n", err)
-		os.Exit(1)
+		stop()
+		cleanupKubeconfig()
+		os.Exit(commands.RenderError(os.Stderr, fmt.Errorf("failed to initialize: %w", err)))
 	}
 
 	// Create and execute root command with dependency injection
 	rootCmd := commands.NewRootCommand(app)
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	err = rootCmd.ExecuteContext(ctx)
+	stop()
+	cleanupKubeconfig()
+	if err != nil {
+		os.Exit(commands.RenderError(os.Stderr, err))
 	}
 }
@@ -0,0 +1,12 @@
+// Package e2e runs kodama sessions against a real cluster, such as kind or
+// minikube: go test -tags e2e ./e2e
+//
+// The suite uses the current kubeconfig context. With KODAMA_E2E_KIND_CLUSTER
+// set, it creates that kind cluster first (unless it exists) and deletes it
+// afterwards (unless KODAMA_E2E_KEEP_CLUSTER is set). Each run works in a
+// fresh namespace and a temporary HOME, so ~/.kodama is never touched.
+//
+// KODAMA_E2E_IMAGE selects the session image (default: ubuntu:24.04). Session
+// pods install a stub Claude Code CLI from a ConfigMap, so no credentials or
+// downloads are needed.
+package e2e
@@ -0,0 +1,201 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+// claudeStub replaces the Claude Code CLI in session pods, so agent runs need
+// neither a download nor credentials
+const claudeStub = "#!/bin/sh\nif [ \"$1\" = --version ]; then echo '0.0.0 (kodama e2e stub)'; exit 0; fi\necho \"claude stub: $*\"\n"
+
+// bundleConfigMap holds the stub installed through the configmap installer source
+const bundleConfigMap = "kodama-e2e-bundle"
+
+var (
+	// namespace is created for the run and deleted afterwards
+	namespace string
+
+	// kubeconfig is passed to usecases; kubectl reads it from KUBECONFIG
+	kubeconfig string
+
+	// image is the session image (KODAMA_E2E_IMAGE, default ubuntu:24.04)
+	image string
+
+	// clientset inspects what kodama created, independently of pkg/kubernetes
+	clientset kubernetes.Interface
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+func run(m *testing.M) int {
+	ctx := context.Background()
+
+	var err error
+	kubeconfig, err = resolveKubeconfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: %v\n", err)
+		return 1
+	}
+	if cluster := os.Getenv("KODAMA_E2E_KIND_CLUSTER"); cluster != "" {
+		cleanup, kindErr := ensureKindCluster(cluster)
+		if kindErr != nil {
+			fmt.Fprintf(os.Stderr, "e2e: %v\n", kindErr)
+			return 1
+		}
+		defer cleanup()
+	}
+	// kubectl subprocesses (exec, sync) use the same cluster as the API client
+	_ = os.Setenv("KUBECONFIG", kubeconfig)
+
+	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: failed to load kubeconfig %s: %v\n", kubeconfig, err)
+		return 1
+	}
+	clientset, err = kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: failed to create clientset: %v\n", err)
+		return 1
+	}
+
+	image = os.Getenv("KODAMA_E2E_IMAGE")
+	if image == "" {
+		image = "ubuntu:24.04"
+	}
+
+	// 1. A namespace of our own, so cleanup assertions see only this run
+	namespace = fmt.Sprintf("kodama-e2e-%d", time.Now().Unix())
+	_, err = clientset.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: failed to create namespace (is a cluster reachable?): %v\n", err)
+		return 1
+	}
+	defer func() {
+		_ = clientset.CoreV1().Namespaces().Delete(context.Background(), namespace, metav1.DeleteOptions{})
+	}()
+
+	// 2. The Claude Code stub, installed by the bundle installer
+	_, err = clientset.CoreV1().ConfigMaps(namespace).Create(ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: bundleConfigMap},
+		BinaryData: map[string][]byte{"claude": []byte(claudeStub)},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: failed to create stub bundle: %v\n", err)
+		return 1
+	}
+
+	// 3. A private HOME with a global config pointing at the namespace
+	home, err := os.MkdirTemp("", "kodama-e2e-home-")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: %v\n", err)
+		return 1
+	}
+	defer func() { _ = os.RemoveAll(home) }()
+	_ = os.Setenv("HOME", home)
+	if err := writeGlobalConfig(); err != nil {
+		fmt.Fprintf(os.Stderr, "e2e: %v\n", err)
+		return 1
+	}
+
+	return m.Run()
+}
+
+// resolveKubeconfig returns the kubeconfig in use before HOME is replaced
+func resolveKubeconfig() (string, error) {
+	if path := os.Getenv("KUBECONFIG"); path != "" {
+		return filepath.SplitList(path)[0], nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".kube", "config"), nil
+}
+
+// ensureKindCluster creates the kind cluster unless it exists and points
+// kubeconfig at it; the returned func deletes a cluster created here
+func ensureKindCluster(name string) (func(), error) {
+	if _, err := exec.LookPath("kind"); err != nil {
+		return nil, fmt.Errorf("KODAMA_E2E_KIND_CLUSTER is set but kind is not installed: %w", err)
+	}
+
+	out, err := exec.Command("kind", "get", "clusters").Output()
+	if err != nil {
+		return nil, fmt.Errorf("kind get clusters failed: %w", err)
+	}
+	created := false
+	if !slices.Contains(strings.Fields(string(out)), name) {
+		//#nosec G204 -- kind with the cluster name from the environment
+		cmd := exec.Command("kind", "create", "cluster", "--name", name, "--wait", "120s")
+		cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("kind create cluster failed: %w", err)
+		}
+		created = true
+	}
+
+	//#nosec G204 -- kind with the cluster name from the environment
+	config, err := exec.Command("kind", "get", "kubeconfig", "--name", name).Output()
+	if err != nil {
+		return nil, fmt.Errorf("kind get kubeconfig failed: %w", err)
+	}
+	f, err := os.CreateTemp("", "kodama-e2e-kubeconfig-")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(config); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	_ = f.Close()
+	kubeconfig = f.Name()
+
+	return func() {
+		_ = os.Remove(f.Name())
+		if created && os.Getenv("KODAMA_E2E_KEEP_CLUSTER") == "" {
+			//#nosec G204 -- kind with the cluster name from the environment
+			_ = exec.Command("kind", "delete", "cluster", "--name", name).Run()
+		}
+	}, nil
+}
+
+// writeGlobalConfig writes ~/.kodama/config.yaml for the run: small pods, no
+// ttyd, and the stub CLI instead of a download
+func writeGlobalConfig() error {
+	store, err := config.NewStore()
+	if err != nil {
+		return err
+	}
+	if err := store.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	cfg := config.DefaultGlobalConfig()
+	ttydEnabled := false
+	cfg.Defaults.Namespace = namespace
+	cfg.Defaults.Image = image
+	cfg.Defaults.Resources.CPU = "250m"
+	cfg.Defaults.Resources.Memory = "256Mi"
+	cfg.Defaults.Ttyd.Enabled = &ttydEnabled
+	cfg.Installer = config.InstallerConfig{Source: config.InstallerSourceConfigMap, ConfigMap: bundleConfigMap}
+	return store.SaveGlobalConfig(cfg)
+}
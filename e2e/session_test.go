@@ -0,0 +1,175 @@
+//go:build e2e
+
+package e2e
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// sessionTimeout bounds one test, including image pulls on a cold node
+const sessionTimeout = 8 * time.Minute
+
+// execInPod runs command in the session pod and returns its stdout
+func execInPod(t *testing.T, ctx context.Context, podName string, command ...string) string {
+	t.Helper()
+	stdout, stderr, err := kubernetes.NewKubectlExecutor().ExecInPod(ctx, namespace, podName, command)
+	require.NoError(t, err, stderr)
+	return stdout
+}
+
+// writeFile writes content to dir/name
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+// requireGone fails unless get reports NotFound, or the object is terminating
+func requireGone(t *testing.T, what string, get func() (metav1.Object, error)) {
+	t.Helper()
+	obj, err := get()
+	if apierrors.IsNotFound(err) {
+		return
+	}
+	require.NoError(t, err)
+	assert.NotNil(t, obj.GetDeletionTimestamp(), "%s was not deleted", what)
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionTimeout)
+	defer cancel()
+
+	const name = "e2e-lifecycle"
+	local := t.TempDir()
+	writeFile(t, local, "README.md", "# e2e\n")
+	envFile := writeFile(t, t.TempDir(), ".env", "E2E_TOKEN=from-dotenv\n")
+
+	// 1. Start: sync, env secret and an agent run on the stub CLI
+	session, err := usecase.StartSession(ctx, usecase.StartSessionOptions{
+		Name:           name,
+		SyncPath:       local,
+		EnvFiles:       []string{envFile},
+		KubeconfigPath: kubeconfig,
+		Prompt:         "say hello",
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = usecase.DeleteSession(context.Background(), usecase.DeleteSessionOptions{Name: name, KubeconfigPath: kubeconfig})
+	})
+	assert.Equal(t, config.StatusRunning, session.Status)
+	assert.Equal(t, namespace, session.Namespace)
+
+	// 2. The pod matches the session
+	pod, err := clientset.CoreV1().Pods(namespace).Get(ctx, session.PodName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "kodama", "session": session.PodName}, pod.Labels)
+	assert.Equal(t, corev1.PodRunning, pod.Status.Phase)
+
+	mainContainer := pod.Spec.Containers[0]
+	assert.Equal(t, kubernetes.MainContainerName, mainContainer.Name)
+	assert.Equal(t, image, mainContainer.Image)
+	assert.Equal(t, "/workspace", mainContainer.WorkingDir)
+	assert.True(t, mainContainer.Resources.Limits.Cpu().Equal(resource.MustParse("250m")), "cpu limit %s", mainContainer.Resources.Limits.Cpu())
+	assert.True(t, mainContainer.Resources.Limits.Memory().Equal(resource.MustParse("256Mi")), "memory limit %s", mainContainer.Resources.Limits.Memory())
+	require.NotEmpty(t, mainContainer.EnvFrom)
+	assert.Equal(t, session.Env.SecretName, mainContainer.EnvFrom[0].SecretRef.Name)
+
+	require.NotEmpty(t, pod.Spec.InitContainers)
+	assert.Equal(t, kubernetes.ToolsInstallerName, pod.Spec.InitContainers[0].Name)
+
+	// 3. Exec: synced files, injected environment and the stub CLI
+	assert.Equal(t, "# e2e\n", execInPod(t, ctx, session.PodName, "cat", "/workspace/README.md"))
+	assert.Equal(t, "from-dotenv\n", execInPod(t, ctx, session.PodName, "printenv", "E2E_TOKEN"))
+	assert.Contains(t, execInPod(t, ctx, session.PodName, "claude", "--version"), "kodama e2e stub")
+
+	// 4. The agent run was recorded and logged in the pod
+	execution := session.GetLastAgentExecution()
+	require.NotNil(t, execution)
+	assert.Equal(t, "completed", execution.Status)
+	assert.Contains(t, execInPod(t, ctx, session.PodName, "cat", execution.LogPath), "say hello")
+
+	// 5. Sync pushes local edits
+	writeFile(t, local, "README.md", "# e2e, edited\n")
+	writeFile(t, local, "new.txt", "added\n")
+	_, err = usecase.SyncOnce(ctx, usecase.SyncOnceOptions{Name: name})
+	require.NoError(t, err)
+	assert.Equal(t, "# e2e, edited\n", execInPod(t, ctx, session.PodName, "cat", "/workspace/README.md"))
+	assert.Equal(t, "added\n", execInPod(t, ctx, session.PodName, "cat", "/workspace/new.txt"))
+
+	// 6. Delete removes the pod, the secret and the session config
+	require.NoError(t, usecase.DeleteSession(ctx, usecase.DeleteSessionOptions{Name: name, KubeconfigPath: kubeconfig}))
+	requireGone(t, "pod", func() (metav1.Object, error) {
+		return clientset.CoreV1().Pods(namespace).Get(ctx, session.PodName, metav1.GetOptions{})
+	})
+	requireGone(t, "env secret", func() (metav1.Object, error) {
+		return clientset.CoreV1().Secrets(namespace).Get(ctx, session.Env.SecretName, metav1.GetOptions{})
+	})
+	store, err := config.NewStore()
+	require.NoError(t, err)
+	assert.False(t, store.SessionExists(name), "session config was not deleted")
+}
+
+// TestStartSession_InterruptedCleansUp cancels a start once its pod exists and
+// checks that nothing is left behind but the Failed session config
+func TestStartSession_InterruptedCleansUp(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), sessionTimeout)
+	defer cancel()
+
+	const name = "e2e-interrupted"
+	envFile := writeFile(t, t.TempDir(), ".env", "E2E_TOKEN=from-dotenv\n")
+	t.Cleanup(func() {
+		_ = usecase.DeleteSession(context.Background(), usecase.DeleteSessionOptions{Name: name, KubeconfigPath: kubeconfig})
+	})
+
+	startCtx, interrupt := context.WithCancel(ctx)
+	defer interrupt()
+	go func() {
+		// Interrupt like Ctrl+C as soon as the pod is created
+		for startCtx.Err() == nil {
+			if _, err := clientset.CoreV1().Pods(namespace).Get(startCtx, "kodama-"+name, metav1.GetOptions{}); err == nil {
+				interrupt()
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}()
+
+	_, err := usecase.StartSession(startCtx, usecase.StartSessionOptions{
+		Name:           name,
+		NoSync:         true,
+		EnvFiles:       []string{envFile},
+		KubeconfigPath: kubeconfig,
+	})
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled), "start error = %v", err)
+
+	requireGone(t, "pod", func() (metav1.Object, error) {
+		return clientset.CoreV1().Pods(namespace).Get(ctx, "kodama-"+name, metav1.GetOptions{})
+	})
+	requireGone(t, "env secret", func() (metav1.Object, error) {
+		return clientset.CoreV1().Secrets(namespace).Get(ctx, "kodama-env-"+name, metav1.GetOptions{})
+	})
+
+	store, err := config.NewStore()
+	require.NoError(t, err)
+	session, err := store.LoadSession(name)
+	require.NoError(t, err)
+	assert.Equal(t, config.StatusFailed, session.Status)
+}
@@ -0,0 +1,191 @@
+// Package krew generates krew plugin manifests and archives for kubectl-kodama
+package krew
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// PluginName is the name users install with `kubectl krew install kodama`
+const PluginName = "kodama"
+
+// BinaryName is the plugin executable inside release archives
+const BinaryName = "kubectl-kodama"
+
+// Homepage is the project page shown by `kubectl krew info kodama`
+const Homepage = "https://github.com/illumination-k/kodama"
+
+// SupportedPlatforms are the os/arch pairs published in releases
+var SupportedPlatforms = [][2]string{
+	{"linux", "amd64"},
+	{"linux", "arm64"},
+	{"darwin", "amd64"},
+	{"darwin", "arm64"},
+}
+
+// Platform is a release archive for one os/arch pair
+type Platform struct {
+	OS     string
+	Arch   string
+	URI    string
+	SHA256 string
+}
+
+// ArchiveName returns the release archive file name for a platform
+func ArchiveName(version, goos, goarch string) string {
+	return fmt.Sprintf("%s_%s_%s_%s.tar.gz", BinaryName, strings.TrimPrefix(version, "v"), goos, goarch)
+}
+
+// ReleasePlatforms returns the supported platforms of a GitHub release,
+// with checksums looked up by archive name
+func ReleasePlatforms(version string, checksums map[string]string) ([]Platform, error) {
+	platforms := make([]Platform, 0, len(SupportedPlatforms))
+	for _, p := range SupportedPlatforms {
+		archive := ArchiveName(version, p[0], p[1])
+		sum, ok := checksums[archive]
+		if !ok {
+			return nil, fmt.Errorf("no checksum for %s", archive)
+		}
+		platforms = append(platforms, Platform{
+			OS:     p[0],
+			Arch:   p[1],
+			URI:    fmt.Sprintf("%s/releases/download/%s/%s", Homepage, version, archive),
+			SHA256: sum,
+		})
+	}
+	return platforms, nil
+}
+
+type manifest struct {
+	APIVersion string       `json:"apiVersion"`
+	Kind       string       `json:"kind"`
+	Metadata   manifestMeta `json:"metadata"`
+	Spec       manifestSpec `json:"spec"`
+}
+
+type manifestMeta struct {
+	Name string `json:"name"`
+}
+
+type manifestSpec struct {
+	Version          string             `json:"version"`
+	Homepage         string             `json:"homepage"`
+	ShortDescription string             `json:"shortDescription"`
+	Description      string             `json:"description"`
+	Platforms        []manifestPlatform `json:"platforms"`
+}
+
+type manifestPlatform struct {
+	Selector struct {
+		MatchLabels map[string]string `json:"matchLabels"`
+	} `json:"selector"`
+	URI    string         `json:"uri"`
+	SHA256 string         `json:"sha256"`
+	Bin    string         `json:"bin"`
+	Files  []manifestFile `json:"files"`
+}
+
+type manifestFile struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Manifest renders a krew v1alpha2 Plugin manifest
+func Manifest(version string, platforms []Platform) ([]byte, error) {
+	if len(platforms) == 0 {
+		return nil, fmt.Errorf("at least one platform is required")
+	}
+
+	m := manifest{
+		APIVersion: "krew.googlecontainertools.github.com/v1alpha2",
+		Kind:       "Plugin",
+		Metadata:   manifestMeta{Name: PluginName},
+		Spec: manifestSpec{
+			Version:          version,
+			Homepage:         Homepage,
+			ShortDescription: "Manage Claude Code sessions in Kubernetes",
+			Description: "Start, attach to and manage containerized Claude Code development\n" +
+				"sessions in your cluster, with workspace sync, web terminals and batch runs.\n",
+		},
+	}
+	for _, p := range platforms {
+		var mp manifestPlatform
+		mp.Selector.MatchLabels = map[string]string{"os": p.OS, "arch": p.Arch}
+		mp.URI = p.URI
+		mp.SHA256 = p.SHA256
+		mp.Bin = BinaryName
+		mp.Files = []manifestFile{{From: BinaryName, To: "."}}
+		m.Spec.Platforms = append(m.Spec.Platforms, mp)
+	}
+
+	return yaml.Marshal(m)
+}
+
+// ParseChecksums parses a sha256sum-style checksums file ("<sha256>  <file>")
+func ParseChecksums(r io.Reader) (map[string]string, error) {
+	checksums := make(map[string]string)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 || len(fields[0]) != sha256.Size*2 {
+			return nil, fmt.Errorf("invalid checksum line: %q", scanner.Text())
+		}
+		checksums[strings.TrimPrefix(fields[1], "*")] = fields[0]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read checksums: %w", err)
+	}
+	return checksums, nil
+}
+
+// WriteArchive writes a tar.gz containing binaryPath as BinaryName and
+// returns its sha256 checksum
+func WriteArchive(w io.Writer, binaryPath string) (string, error) {
+	// #nosec G304 -- path of the running executable or a build artifact
+	f, err := os.Open(binaryPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to open binary: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("failed to stat binary: %w", err)
+	}
+
+	hash := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(w, hash))
+	tw := tar.NewWriter(gz)
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    BinaryName,
+		Mode:    0o755,
+		Size:    info.Size(),
+		ModTime: info.ModTime(),
+	}); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return "", fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
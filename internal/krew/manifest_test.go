@@ -0,0 +1,83 @@
+package krew
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func fakeChecksums(version string) string {
+	var b strings.Builder
+	for i, p := range SupportedPlatforms {
+		b.WriteString(strings.Repeat(string(rune('a'+i)), 64) + "  " + ArchiveName(version, p[0], p[1]) + "\n")
+	}
+	return b.String()
+}
+
+func TestReleaseManifest(t *testing.T) {
+	checksums, err := ParseChecksums(strings.NewReader(fakeChecksums("v0.5.0")))
+	require.NoError(t, err)
+
+	platforms, err := ReleasePlatforms("v0.5.0", checksums)
+	require.NoError(t, err)
+	require.Len(t, platforms, len(SupportedPlatforms))
+	assert.Equal(t, "https://github.com/illumination-k/kodama/releases/download/v0.5.0/kubectl-kodama_0.5.0_linux_amd64.tar.gz", platforms[0].URI)
+	assert.Equal(t, strings.Repeat("a", 64), platforms[0].SHA256)
+
+	data, err := Manifest("v0.5.0", platforms)
+	require.NoError(t, err)
+
+	var m map[string]interface{}
+	require.NoError(t, yaml.Unmarshal(data, &m))
+	assert.Equal(t, "Plugin", m["kind"])
+	assert.Equal(t, "kodama", m["metadata"].(map[string]interface{})["name"])
+	spec := m["spec"].(map[string]interface{})
+	assert.Equal(t, "v0.5.0", spec["version"])
+	platform := spec["platforms"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "kubectl-kodama", platform["bin"])
+	assert.Equal(t, map[string]interface{}{"os": "linux", "arch": "amd64"}, platform["selector"].(map[string]interface{})["matchLabels"])
+}
+
+func TestReleasePlatforms_MissingChecksum(t *testing.T) {
+	_, err := ReleasePlatforms("v0.5.0", map[string]string{})
+	assert.ErrorContains(t, err, "kubectl-kodama_0.5.0_linux_amd64.tar.gz")
+}
+
+func TestParseChecksums_Invalid(t *testing.T) {
+	_, err := ParseChecksums(strings.NewReader("abc kubectl-kodama.tar.gz\n"))
+	assert.Error(t, err)
+}
+
+func TestWriteArchive(t *testing.T) {
+	binaryPath := filepath.Join(t.TempDir(), "kubectl-kodama-build")
+	require.NoError(t, os.WriteFile(binaryPath, []byte("#!/bin/sh\n"), 0o600))
+
+	var buf bytes.Buffer
+	sum, err := WriteArchive(&buf, binaryPath)
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(buf.Bytes())
+	assert.Equal(t, hex.EncodeToString(digest[:]), sum)
+
+	gz, err := gzip.NewReader(&buf)
+	require.NoError(t, err)
+	tr := tar.NewReader(gz)
+	header, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, BinaryName, header.Name)
+	assert.Equal(t, int64(0o755), header.Mode)
+	content, err := io.ReadAll(tr)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\n", string(content))
+}
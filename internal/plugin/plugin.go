@@ -0,0 +1,37 @@
+// Package plugin detects whether kodama runs as a kubectl plugin or standalone
+package plugin
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// kubectlPluginPrefix is the executable name prefix kubectl uses to discover plugins
+const kubectlPluginPrefix = "kubectl-"
+
+// IsKubectlPlugin reports whether the binary was invoked under its plugin name
+// (kubectl-kodama, as installed by krew or found on PATH by kubectl) rather than
+// a standalone name such as kodama
+func IsKubectlPlugin() bool {
+	return isPluginExecutable(os.Args[0])
+}
+
+func isPluginExecutable(arg0 string) bool {
+	name := strings.TrimSuffix(filepath.Base(arg0), ".exe")
+	return strings.HasPrefix(name, kubectlPluginPrefix)
+}
+
+// CommandName returns how users invoke the binary: "kubectl kodama" as a plugin,
+// otherwise the executable name (e.g. "kodama")
+func CommandName() string {
+	return commandName(os.Args[0])
+}
+
+func commandName(arg0 string) string {
+	name := strings.TrimSuffix(filepath.Base(arg0), ".exe")
+	if strings.HasPrefix(name, kubectlPluginPrefix) {
+		return "kubectl " + strings.TrimPrefix(name, kubectlPluginPrefix)
+	}
+	return name
+}
@@ -0,0 +1,27 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandName(t *testing.T) {
+	tests := []struct {
+		arg0       string
+		wantName   string
+		wantPlugin bool
+	}{
+		{arg0: "/home/me/.krew/bin/kubectl-kodama", wantName: "kubectl kodama", wantPlugin: true},
+		{arg0: "kubectl-kodama.exe", wantName: "kubectl kodama", wantPlugin: true},
+		{arg0: "/usr/local/bin/kodama", wantName: "kodama", wantPlugin: false},
+		{arg0: "./bin/kodama", wantName: "kodama", wantPlugin: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg0, func(t *testing.T) {
+			assert.Equal(t, tt.wantName, commandName(tt.arg0))
+			assert.Equal(t, tt.wantPlugin, isPluginExecutable(tt.arg0))
+		})
+	}
+}
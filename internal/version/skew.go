@@ -0,0 +1,88 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Supported Kubernetes server minor versions (client-go v0.32)
+const (
+	MinSupportedServerMinor = 29
+	MaxSupportedServerMinor = 33
+)
+
+// MaxKubectlSkew is the number of minor versions kubectl may differ from the server
+// (https://kubernetes.io/releases/version-skew-policy/#kubectl)
+const MaxKubectlSkew = 1
+
+// Semver is a parsed major.minor.patch version
+type Semver struct {
+	Major, Minor, Patch int
+}
+
+// ParseSemver parses versions such as "v1.32.1", "1.31" or "v1.30.2-eks-1234".
+// Pre-release and build suffixes are ignored.
+func ParseSemver(s string) (Semver, error) {
+	trimmed := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(trimmed, "-+"); i >= 0 {
+		trimmed = trimmed[:i]
+	}
+
+	parts := strings.Split(trimmed, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return Semver{}, fmt.Errorf("invalid version: %q", s)
+	}
+
+	var nums [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return Semver{}, fmt.Errorf("invalid version: %q", s)
+		}
+		nums[i] = n
+	}
+	return Semver{Major: nums[0], Minor: nums[1], Patch: nums[2]}, nil
+}
+
+// Compare returns -1, 0 or 1 when v is older than, equal to or newer than other
+func (v Semver) Compare(other Semver) int {
+	for _, d := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if d[0] < d[1] {
+			return -1
+		}
+		if d[0] > d[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// CheckServerSkew returns a warning when the cluster version is outside the supported range
+func CheckServerSkew(serverVersion string) string {
+	server, err := ParseSemver(serverVersion)
+	if err != nil {
+		return fmt.Sprintf("could not parse Kubernetes server version %q", serverVersion)
+	}
+	if server.Major != 1 || server.Minor < MinSupportedServerMinor || server.Minor > MaxSupportedServerMinor {
+		return fmt.Sprintf("Kubernetes server %s is outside the supported range 1.%d-1.%d", serverVersion, MinSupportedServerMinor, MaxSupportedServerMinor)
+	}
+	return ""
+}
+
+// CheckKubectlSkew returns a warning when kubectl is more than MaxKubectlSkew minor versions from the server
+func CheckKubectlSkew(kubectlVersion, serverVersion string) string {
+	kubectl, err := ParseSemver(kubectlVersion)
+	if err != nil {
+		return fmt.Sprintf("could not parse kubectl version %q", kubectlVersion)
+	}
+	server, err := ParseSemver(serverVersion)
+	if err != nil {
+		return ""
+	}
+	skew := kubectl.Minor - server.Minor
+	if kubectl.Major != server.Major || skew > MaxKubectlSkew || skew < -MaxKubectlSkew {
+		return fmt.Sprintf("kubectl %s is more than %d minor version from the Kubernetes server %s", kubectlVersion, MaxKubectlSkew, serverVersion)
+	}
+	return ""
+}
@@ -0,0 +1,45 @@
+package version
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Semver
+	}{
+		{in: "v1.32.1", want: Semver{1, 32, 1}},
+		{in: "1.31", want: Semver{1, 31, 0}},
+		{in: "v1.30.2-eks-1234", want: Semver{1, 30, 2}},
+		{in: "v0.5.0+dirty", want: Semver{0, 5, 0}},
+	}
+	for _, tt := range tests {
+		got, err := ParseSemver(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+
+	for _, invalid := range []string{"dev", "v1", "1.x.0", "1.2.3.4"} {
+		_, err := ParseSemver(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestCheckServerSkew(t *testing.T) {
+	assert.Empty(t, CheckServerSkew("v1.32.1"))
+	assert.Empty(t, CheckServerSkew("v1.29.0-gke.100"))
+	assert.Contains(t, CheckServerSkew("v1.25.0"), "outside the supported range")
+	assert.Contains(t, CheckServerSkew("v1.40.0"), "outside the supported range")
+}
+
+func TestCheckKubectlSkew(t *testing.T) {
+	assert.Empty(t, CheckKubectlSkew("v1.32.0", "v1.32.3"))
+	assert.Empty(t, CheckKubectlSkew("v1.33.0", "v1.32.3"))
+	assert.Empty(t, CheckKubectlSkew("v1.31.0", "v1.32.3"))
+	assert.NotEmpty(t, CheckKubectlSkew("v1.34.0", "v1.32.3"))
+	assert.NotEmpty(t, CheckKubectlSkew("v1.29.0", "v1.32.3"))
+}
@@ -0,0 +1,68 @@
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// releasesURL is the GitHub API endpoint for the latest release
+var releasesURL = "https://api.github.com/repos/illumination-k/kodama/releases/latest"
+
+// Release describes a published release
+type Release struct {
+	Version string // Tag name, e.g. "v0.5.0"
+	URL     string // Release page
+}
+
+// LatestRelease fetches the latest published release from GitHub
+func LatestRelease(ctx context.Context) (*Release, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, releasesURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for updates: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("update check failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		TagName string `json:"tag_name"`
+		HTMLURL string `json:"html_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("failed to decode release response: %w", err)
+	}
+	if payload.TagName == "" {
+		return nil, fmt.Errorf("release response did not contain a tag")
+	}
+
+	return &Release{Version: payload.TagName, URL: payload.HTMLURL}, nil
+}
+
+// IsNewer reports whether latest is a newer release than current.
+// Development builds and unparsable versions are never considered outdated.
+func IsNewer(latest, current string) bool {
+	latestVersion, err := ParseSemver(latest)
+	if err != nil {
+		return false
+	}
+	currentVersion, err := ParseSemver(current)
+	if err != nil {
+		return false
+	}
+	return latestVersion.Compare(currentVersion) > 0
+}
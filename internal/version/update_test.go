@@ -0,0 +1,50 @@
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLatestRelease(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tag_name":"v0.6.0","html_url":"https://github.com/illumination-k/kodama/releases/tag/v0.6.0"}`))
+	}))
+	defer server.Close()
+
+	original := releasesURL
+	releasesURL = server.URL
+	defer func() { releasesURL = original }()
+
+	release, err := LatestRelease(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "v0.6.0", release.Version)
+	assert.Equal(t, "https://github.com/illumination-k/kodama/releases/tag/v0.6.0", release.URL)
+}
+
+func TestLatestRelease_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	original := releasesURL
+	releasesURL = server.URL
+	defer func() { releasesURL = original }()
+
+	_, err := LatestRelease(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rate limited")
+}
+
+func TestIsNewer(t *testing.T) {
+	assert.True(t, IsNewer("v0.6.0", "v0.5.9"))
+	assert.True(t, IsNewer("v1.0.0", "0.9.0"))
+	assert.False(t, IsNewer("v0.5.0", "v0.5.0"))
+	assert.False(t, IsNewer("v0.4.0", "v0.5.0"))
+	assert.False(t, IsNewer("v0.6.0", "dev"), "development builds are never outdated")
+}
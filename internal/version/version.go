@@ -2,3 +2,6 @@ package version
 
 // Version is set during build via ldflags
 var Version = "dev"
+
+// Commit is the git commit the binary was built from, set during build via ldflags
+var Commit = "unknown"
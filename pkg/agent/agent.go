@@ -2,13 +2,41 @@ package agent
 
 import (
 	"context"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
 )
 
+// RunsDir is where agent task output is written in the pod
+const RunsDir = kubernetes.RecordingsParentDir + "/agent-runs"
+
+// RunLogPath returns the pod path of the log for a task started at startedAt
+func RunLogPath(startedAt time.Time) string {
+	return path.Join(RunsDir, startedAt.UTC().Format("20060102-150405")+".log")
+}
+
+// LatestRunLogScript returns POSIX sh printing the last lines of the newest
+// task log in RunsDir. With follow, it waits for the first task and then
+// follows its log, instead of failing when no task has run yet.
+func LatestRunLogScript(lines int, follow bool) string {
+	latest := `log=$(ls -t ` + RunsDir + `/*.log 2>/dev/null | head -n 1); `
+	tail := `tail -n ` + strconv.Itoa(lines)
+	if !follow {
+		return latest + `[ -n "$log" ] || { echo "no agent task has run yet" >&2; exit 1; }; ` + tail + ` "$log"`
+	}
+	return `echo "Waiting for an agent task..."; while :; do ` + latest +
+		`if [ -n "$log" ]; then echo "==> $log <=="; exec ` + tail + ` -F "$log"; fi; sleep 2; done`
+}
+
 // CodingAgentExecutor abstracts coding agent operations for testing
 type CodingAgentExecutor interface {
-	// TaskStart initiates a new coding task with the given prompt
+	// TaskStart runs a new coding task with the given prompt and returns when it finishes
+	// Its stdout and stderr are written to logPath in the pod, unless logPath is empty.
+	// A deadline on ctx stops the task, in the pod as well.
 	// Returns task ID and error
-	TaskStart(ctx context.Context, namespace, podName, prompt string) (taskID string, err error)
+	TaskStart(ctx context.Context, namespace, podName, prompt, logPath string) (taskID string, err error)
 
 	// Additional methods for future expansion:
 	// TaskStatus(ctx context.Context, taskID string) (*TaskStatus, error)
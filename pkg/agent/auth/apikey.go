@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// apiKeyBackends maps API key providers to the environment variables their
+// clients read the key and the endpoint from
+var apiKeyBackends = map[string]struct{ keyEnvVar, baseURLEnvVar string }{
+	"openai":     {"OPENAI_API_KEY", "OPENAI_BASE_URL"},
+	"gemini":     {"GEMINI_API_KEY", "GOOGLE_GEMINI_BASE_URL"},
+	"anthropic":  {"ANTHROPIC_API_KEY", "ANTHROPIC_BASE_URL"},
+	"openrouter": {"OPENROUTER_API_KEY", "OPENAI_BASE_URL"},
+}
+
+// APIKeyProvider implements authentication using a static API key of an
+// OpenAI-compatible or Gemini backend
+type APIKeyProvider struct {
+	config        APIKeyConfig
+	envVar        string
+	baseURLEnvVar string
+}
+
+// NewAPIKeyProvider creates a new API key authentication provider
+// Providers without a known environment variable need config.EnvVar.
+func NewAPIKeyProvider(config APIKeyConfig) (*APIKeyProvider, error) {
+	if config.Provider == "" {
+		config.Provider = "openai"
+	}
+	backend, known := apiKeyBackends[config.Provider]
+	envVar := config.EnvVar
+	if envVar == "" {
+		if !known {
+			return nil, fmt.Errorf("api-key provider %q needs envVar, the variable holding its key", config.Provider)
+		}
+		envVar = backend.keyEnvVar
+	}
+	baseURLEnvVar := backend.baseURLEnvVar
+	if baseURLEnvVar == "" {
+		baseURLEnvVar = "OPENAI_BASE_URL" // Unknown providers are OpenAI-compatible
+	}
+	return &APIKeyProvider{config: config, envVar: envVar, baseURLEnvVar: baseURLEnvVar}, nil
+}
+
+// GetCredentials retrieves the API key
+// Priority: Direct key > Env var
+func (p *APIKeyProvider) GetCredentials(ctx context.Context) (*Credentials, error) {
+	key := p.config.Key
+	if key == "" {
+		key = os.Getenv(p.envVar)
+	}
+	if key == "" {
+		return nil, fmt.Errorf("no %s API key available: set %s", p.config.Provider, p.envVar)
+	}
+
+	metadata := map[string]string{"provider": p.config.Provider}
+	if p.config.BaseURL != "" {
+		metadata["baseUrl"] = p.config.BaseURL
+		metadata["baseUrlEnvVar"] = p.baseURLEnvVar
+	}
+	return &Credentials{
+		Token:    key,
+		EnvVar:   p.envVar,
+		Metadata: metadata,
+	}, nil
+}
+
+// Type returns the authentication type
+func (p *APIKeyProvider) Type() AuthType {
+	return AuthTypeAPIKey
+}
+
+// NeedsRefresh indicates if credentials need to be refreshed
+func (p *APIKeyProvider) NeedsRefresh() bool {
+	return false // Static keys don't expire
+}
+
+// Refresh attempts to refresh the credentials
+func (p *APIKeyProvider) Refresh(ctx context.Context) error {
+	return nil // No refresh needed for static keys
+}
@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAPIKeyProvider_GetCredentials(t *testing.T) {
+	t.Setenv("OPENAI_API_KEY", "sk-openai")
+	t.Setenv("GEMINI_API_KEY", "gemini-key")
+	t.Setenv("VLLM_KEY", "vllm-key")
+
+	tests := []struct {
+		name       string
+		config     APIKeyConfig
+		wantToken  string
+		wantEnvVar string
+		wantErr    bool
+	}{
+		{name: "openai by default", config: APIKeyConfig{}, wantToken: "sk-openai", wantEnvVar: "OPENAI_API_KEY"},
+		{name: "gemini", config: APIKeyConfig{Provider: "gemini"}, wantToken: "gemini-key", wantEnvVar: "GEMINI_API_KEY"},
+		{name: "custom env var", config: APIKeyConfig{Provider: "vllm", EnvVar: "VLLM_KEY", BaseURL: "http://vllm:8000/v1"}, wantToken: "vllm-key", wantEnvVar: "VLLM_KEY"},
+		{name: "direct key", config: APIKeyConfig{Provider: "anthropic", Key: "direct"}, wantToken: "direct", wantEnvVar: "ANTHROPIC_API_KEY"},
+		{name: "key not set", config: APIKeyConfig{Provider: "openrouter"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := NewAPIKeyProvider(tt.config)
+			if err != nil {
+				t.Fatalf("NewAPIKeyProvider() error = %v", err)
+			}
+			creds, err := provider.GetCredentials(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCredentials() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if creds.Token != tt.wantToken || creds.EnvVar != tt.wantEnvVar {
+				t.Errorf("GetCredentials() = %s in %s, want %s in %s", creds.Token, creds.EnvVar, tt.wantToken, tt.wantEnvVar)
+			}
+		})
+	}
+}
+
+func TestAPIKeyProvider_BaseURL(t *testing.T) {
+	provider, err := NewAPIKeyProvider(APIKeyConfig{Provider: "vllm", EnvVar: "VLLM_KEY", BaseURL: "http://vllm:8000/v1", Key: "k"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	creds, err := provider.GetCredentials(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.Metadata["baseUrl"] != "http://vllm:8000/v1" || creds.Metadata["baseUrlEnvVar"] != "OPENAI_BASE_URL" {
+		t.Errorf("Metadata = %v, want the base URL as OPENAI_BASE_URL", creds.Metadata)
+	}
+}
+
+func TestNewAPIKeyProvider_UnknownProviderNeedsEnvVar(t *testing.T) {
+	if _, err := NewAPIKeyProvider(APIKeyConfig{Provider: "vllm"}); err == nil {
+		t.Error("NewAPIKeyProvider() should fail without envVar for an unknown provider")
+	}
+	if _, err := NewAuthProvider(AuthConfig{Type: AuthTypeAPIKey, APIKeySource: APIKeyConfig{Provider: "vllm"}}); err == nil {
+		t.Error("NewAuthProvider() should return the api-key error")
+	}
+}
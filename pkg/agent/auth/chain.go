@@ -0,0 +1,115 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// AuthTypeChain is the type of a ChainProvider
+const AuthTypeChain AuthType = "chain"
+
+// ChainProvider tries providers in order and uses the first one that returns
+// credentials, e.g. Google ADC, then a cached auth file, then a static token.
+// A provider that fails, or fails to refresh, falls back to the next one.
+type ChainProvider struct {
+	providers []AuthProvider
+	active    AuthProvider
+}
+
+// NewChainProvider creates a provider falling back through providers in order
+func NewChainProvider(providers ...AuthProvider) *ChainProvider {
+	return &ChainProvider{providers: providers}
+}
+
+// Providers returns the providers of the chain in order
+func (c *ChainProvider) Providers() []AuthProvider {
+	return c.providers
+}
+
+// Active returns the provider that returned the last credentials, or nil
+func (c *ChainProvider) Active() AuthProvider {
+	return c.active
+}
+
+// GetCredentials returns the credentials of the first provider that has them
+func (c *ChainProvider) GetCredentials(ctx context.Context) (*Credentials, error) {
+	var errs []error
+	for _, provider := range c.providers {
+		if provider.NeedsRefresh() {
+			if err := provider.Refresh(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("%s: refresh failed: %w", provider.Type(), err))
+				continue
+			}
+		}
+		creds, err := provider.GetCredentials(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", provider.Type(), err))
+			continue
+		}
+		c.active = provider
+		return creds, nil
+	}
+	c.active = nil
+	return nil, fmt.Errorf("no auth provider in the chain has credentials: %w", errors.Join(errs...))
+}
+
+// Type returns the authentication type
+func (c *ChainProvider) Type() AuthType {
+	return AuthTypeChain
+}
+
+// NeedsRefresh reports whether the active provider needs a refresh
+func (c *ChainProvider) NeedsRefresh() bool {
+	return c.active != nil && c.active.NeedsRefresh()
+}
+
+// Refresh refreshes the active provider. When that fails, the next
+// GetCredentials falls back to the following providers instead of failing.
+func (c *ChainProvider) Refresh(ctx context.Context) error {
+	if c.active == nil {
+		return nil
+	}
+	if err := c.active.Refresh(ctx); err != nil {
+		c.active = nil
+	}
+	return nil
+}
+
+// ProviderStatus reports the health of one auth provider
+type ProviderStatus struct {
+	Type         AuthType
+	Active       bool       // The provider credentials come from: the first available one
+	Available    bool       // Credentials could be retrieved
+	ExpiresAt    *time.Time // Expiry of the credentials, if they expire
+	RefreshError string     // Why a due refresh failed
+	Error        string     // Why no credentials could be retrieved
+}
+
+// CheckProviders retrieves credentials from every provider, refreshing those
+// that are due, and reports which one a chain of them would use
+func CheckProviders(ctx context.Context, providers []AuthProvider) []ProviderStatus {
+	statuses := make([]ProviderStatus, 0, len(providers))
+	activeFound := false
+	for _, provider := range providers {
+		status := ProviderStatus{Type: provider.Type()}
+		if provider.NeedsRefresh() {
+			if err := provider.Refresh(ctx); err != nil {
+				status.RefreshError = err.Error()
+			}
+		}
+		creds, err := provider.GetCredentials(ctx)
+		if err != nil {
+			status.Error = err.Error()
+		} else {
+			status.Available = true
+			status.ExpiresAt = creds.ExpiresAt
+			if !activeFound {
+				status.Active, activeFound = true, true
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeProvider returns a fixed token or error and counts refreshes
+type fakeProvider struct {
+	authType   AuthType
+	token      string
+	err        error
+	refresh    bool
+	refreshErr error
+	refreshes  int
+}
+
+func (f *fakeProvider) GetCredentials(ctx context.Context) (*Credentials, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	expiresAt := time.Now().Add(time.Hour)
+	return &Credentials{Token: f.token, ExpiresAt: &expiresAt}, nil
+}
+
+func (f *fakeProvider) Type() AuthType     { return f.authType }
+func (f *fakeProvider) NeedsRefresh() bool { return f.refresh }
+
+func (f *fakeProvider) Refresh(ctx context.Context) error {
+	f.refreshes++
+	return f.refreshErr
+}
+
+func TestChainProvider_FallsBack(t *testing.T) {
+	adc := &fakeProvider{authType: AuthTypeGoogleADC, err: errors.New("metadata server unreachable")}
+	file := &fakeProvider{authType: AuthTypeFile, token: "file-token", refresh: true, refreshErr: errors.New("refresh URL down")}
+	token := &fakeProvider{authType: AuthTypeToken, token: "static-token"}
+	chain := NewChainProvider(adc, file, token)
+
+	creds, err := chain.GetCredentials(context.Background())
+	if err != nil {
+		t.Fatalf("GetCredentials() error = %v", err)
+	}
+	if creds.Token != "static-token" || chain.Active() != token {
+		t.Errorf("GetCredentials() = %s, want the static token after the ADC and file providers fail", creds.Token)
+	}
+	if file.refreshes != 1 {
+		t.Errorf("file refreshes = %d, want 1", file.refreshes)
+	}
+
+	// Once the first provider works again it is preferred
+	adc.err = nil
+	adc.token = "adc-token"
+	if creds, _ := chain.GetCredentials(context.Background()); creds.Token != "adc-token" {
+		t.Errorf("GetCredentials() = %s, want adc-token", creds.Token)
+	}
+}
+
+func TestChainProvider_RefreshFailureFallsBack(t *testing.T) {
+	file := &fakeProvider{authType: AuthTypeFile, token: "file-token"}
+	chain := NewChainProvider(file, &fakeProvider{authType: AuthTypeToken, token: "static-token"})
+	if _, err := chain.GetCredentials(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	file.refresh, file.refreshErr = true, errors.New("expired")
+	if !chain.NeedsRefresh() {
+		t.Fatal("NeedsRefresh() should follow the active provider")
+	}
+	if err := chain.Refresh(context.Background()); err != nil {
+		t.Errorf("Refresh() error = %v, want the failure left to fallback", err)
+	}
+	if creds, _ := chain.GetCredentials(context.Background()); creds.Token != "static-token" {
+		t.Errorf("GetCredentials() = %s, want the static token", creds.Token)
+	}
+}
+
+func TestChainProvider_AllFail(t *testing.T) {
+	chain := NewChainProvider(
+		&fakeProvider{authType: AuthTypeFile, err: errors.New("no auth file")},
+		&fakeProvider{authType: AuthTypeToken, err: errors.New("no token")},
+	)
+	_, err := chain.GetCredentials(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "file: no auth file") || !strings.Contains(err.Error(), "token: no token") {
+		t.Errorf("GetCredentials() error = %v, want every provider's error", err)
+	}
+	if chain.Active() != nil {
+		t.Error("Active() should be nil when no provider has credentials")
+	}
+}
+
+func TestCheckProviders(t *testing.T) {
+	statuses := CheckProviders(context.Background(), []AuthProvider{
+		&fakeProvider{authType: AuthTypeGoogleADC, err: errors.New("no credentials")},
+		&fakeProvider{authType: AuthTypeFile, token: "a", refresh: true, refreshErr: errors.New("refresh URL down")},
+		&fakeProvider{authType: AuthTypeToken, token: "b"},
+	})
+	if len(statuses) != 3 {
+		t.Fatalf("len(statuses) = %d, want 3", len(statuses))
+	}
+	if statuses[0].Available || statuses[0].Error != "no credentials" {
+		t.Errorf("statuses[0] = %+v, want unavailable", statuses[0])
+	}
+	if !statuses[1].Active || statuses[1].RefreshError != "refresh URL down" || statuses[1].ExpiresAt == nil {
+		t.Errorf("statuses[1] = %+v, want active with a failed refresh", statuses[1])
+	}
+	if statuses[2].Active || !statuses[2].Available {
+		t.Errorf("statuses[2] = %+v, want available but not active", statuses[2])
+	}
+}
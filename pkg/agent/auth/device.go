@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DeviceFlowConfig configures the OAuth 2.0 device authorization grant
+// (RFC 8628) against an identity provider
+type DeviceFlowConfig struct {
+	DeviceAuthURL string   // Device authorization endpoint
+	TokenURL      string   // Token endpoint, also used to refresh
+	ClientID      string   // Public client registered for the device flow
+	Scopes        []string // Requested scopes; include offline_access for a refresh token at most IdPs
+}
+
+// Validate checks that the endpoints and client are set
+func (c DeviceFlowConfig) Validate() error {
+	var missing []string
+	if c.DeviceAuthURL == "" {
+		missing = append(missing, "deviceAuthURL")
+	}
+	if c.TokenURL == "" {
+		missing = append(missing, "tokenURL")
+	}
+	if c.ClientID == "" {
+		missing = append(missing, "clientID")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("device login needs %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// DeviceAuthorization is the identity provider's answer to a device code request
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// OAuthToken is a token response of an OAuth token endpoint
+type OAuthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Error        string `json:"error"`
+	Description  string `json:"error_description"`
+}
+
+// ExpiresAt returns when the access token expires, or nil if it does not say
+func (t *OAuthToken) ExpiresAt(now time.Time) *time.Time {
+	if t.ExpiresIn <= 0 {
+		return nil
+	}
+	expiresAt := now.Add(time.Duration(t.ExpiresIn) * time.Second)
+	return &expiresAt
+}
+
+// ErrDeviceAuthorizationDenied is returned when the user declines the login
+var ErrDeviceAuthorizationDenied = errors.New("login was denied")
+
+// ErrDeviceCodeExpired is returned when the user did not finish the login in time
+var ErrDeviceCodeExpired = errors.New("login code expired before it was used")
+
+// oauthHTTPClient bounds each request to the identity provider
+var oauthHTTPClient = &http.Client{Timeout: 30 * time.Second}
+
+// RequestDeviceCode starts a device login and returns the code the user enters
+func RequestDeviceCode(ctx context.Context, config DeviceFlowConfig) (*DeviceAuthorization, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	form := url.Values{"client_id": {config.ClientID}}
+	if len(config.Scopes) > 0 {
+		form.Set("scope", strings.Join(config.Scopes, " "))
+	}
+
+	var device DeviceAuthorization
+	status, body, err := postForm(ctx, config.DeviceAuthURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("device code request failed: %w", err)
+	}
+	if err := json.Unmarshal(body, &device); err != nil || status != http.StatusOK || device.DeviceCode == "" {
+		return nil, fmt.Errorf("device code request failed: %s", oauthError(status, body))
+	}
+	if device.Interval <= 0 {
+		device.Interval = 5
+	}
+	return &device, nil
+}
+
+// PollDeviceToken polls the token endpoint until the user finishes the login,
+// declines it, or the code expires. slow_down answers add 5 seconds to the
+// interval, as RFC 8628 asks.
+func PollDeviceToken(ctx context.Context, config DeviceFlowConfig, device *DeviceAuthorization) (*OAuthToken, error) {
+	interval := time.Duration(device.Interval) * time.Second
+	var deadline <-chan time.Time
+	if device.ExpiresIn > 0 {
+		timer := time.NewTimer(time.Duration(device.ExpiresIn) * time.Second)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+
+	form := url.Values{
+		"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+		"device_code": {device.DeviceCode},
+		"client_id":   {config.ClientID},
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-deadline:
+			return nil, ErrDeviceCodeExpired
+		case <-time.After(interval):
+		}
+
+		status, body, err := postForm(ctx, config.TokenURL, form)
+		if err != nil {
+			return nil, fmt.Errorf("token request failed: %w", err)
+		}
+		var token OAuthToken
+		_ = json.Unmarshal(body, &token) // Error responses may not be JSON
+		if status == http.StatusOK && token.AccessToken != "" {
+			return &token, nil
+		}
+		switch token.Error {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "access_denied":
+			return nil, ErrDeviceAuthorizationDenied
+		case "expired_token":
+			return nil, ErrDeviceCodeExpired
+		default:
+			return nil, fmt.Errorf("token request failed: %s", oauthError(status, body))
+		}
+	}
+}
+
+// RefreshOAuthToken exchanges a refresh token for a new access token. IdPs that
+// rotate refresh tokens return a new one, which replaces the old.
+func RefreshOAuthToken(ctx context.Context, tokenURL, clientID, refreshToken string) (*OAuthToken, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	}
+	if clientID != "" {
+		form.Set("client_id", clientID)
+	}
+	status, body, err := postForm(ctx, tokenURL, form)
+	if err != nil {
+		return nil, fmt.Errorf("token refresh failed: %w", err)
+	}
+	var token OAuthToken
+	if err := json.Unmarshal(body, &token); err != nil || status != http.StatusOK || token.AccessToken == "" {
+		return nil, fmt.Errorf("token refresh failed: %s", oauthError(status, body))
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return &token, nil
+}
+
+// postForm posts a form and returns the status code and body
+func postForm(ctx context.Context, endpoint string, form url.Values) (int, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := oauthHTTPClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	return resp.StatusCode, body, err
+}
+
+// oauthError describes a failed OAuth response by its error fields, never its tokens
+func oauthError(status int, body []byte) string {
+	var resp OAuthToken
+	if json.Unmarshal(body, &resp) == nil && resp.Error != "" {
+		if resp.Description != "" {
+			return resp.Error + ": " + resp.Description
+		}
+		return resp.Error
+	}
+	return fmt.Sprintf("HTTP %d", status)
+}
@@ -0,0 +1,141 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// deviceFlowServer answers device code requests, then token polls with the
+// given responses in order
+func deviceFlowServer(t *testing.T, polls ...string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("client_id") != "kodama" {
+			t.Errorf("device request form = %v", r.PostForm)
+		}
+		_, _ = w.Write([]byte(`{"device_code":"dev-123","user_code":"ABCD-EFGH","verification_uri":"https://idp.example.com/device","expires_in":600}`))
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		if r.PostForm.Get("grant_type") == "refresh_token" {
+			if r.PostForm.Get("refresh_token") != "refresh-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"refreshed","expires_in":3600}`))
+			return
+		}
+		if r.PostForm.Get("device_code") != "dev-123" {
+			t.Errorf("device_code = %q", r.PostForm.Get("device_code"))
+		}
+		response := polls[0]
+		polls = polls[1:]
+		if response[2:7] == "error" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		_, _ = w.Write([]byte(response))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDeviceFlow(t *testing.T) {
+	server := deviceFlowServer(t,
+		`{"error":"authorization_pending"}`,
+		`{"access_token":"access-1","refresh_token":"refresh-1","expires_in":3600}`,
+	)
+	config := DeviceFlowConfig{DeviceAuthURL: server.URL + "/device", TokenURL: server.URL + "/token", ClientID: "kodama"}
+
+	device, err := RequestDeviceCode(context.Background(), config)
+	if err != nil {
+		t.Fatalf("RequestDeviceCode() error = %v", err)
+	}
+	if device.UserCode != "ABCD-EFGH" || device.Interval != 5 {
+		t.Errorf("device = %+v, want user code and the default interval", device)
+	}
+
+	device.Interval = 0 // Poll without waiting
+	token, err := PollDeviceToken(context.Background(), config, device)
+	if err != nil {
+		t.Fatalf("PollDeviceToken() error = %v", err)
+	}
+	if token.AccessToken != "access-1" || token.RefreshToken != "refresh-1" {
+		t.Errorf("token = %+v", token)
+	}
+}
+
+func TestPollDeviceTokenErrors(t *testing.T) {
+	tests := []struct {
+		response string
+		want     error
+	}{
+		{`{"error":"access_denied"}`, ErrDeviceAuthorizationDenied},
+		{`{"error":"expired_token"}`, ErrDeviceCodeExpired},
+	}
+	for _, tt := range tests {
+		server := deviceFlowServer(t, tt.response)
+		config := DeviceFlowConfig{DeviceAuthURL: server.URL + "/device", TokenURL: server.URL + "/token", ClientID: "kodama"}
+		_, err := PollDeviceToken(context.Background(), config, &DeviceAuthorization{DeviceCode: "dev-123"})
+		if !errors.Is(err, tt.want) {
+			t.Errorf("PollDeviceToken(%s) error = %v, want %v", tt.response, err, tt.want)
+		}
+	}
+}
+
+func TestDeviceFlowConfigValidate(t *testing.T) {
+	err := DeviceFlowConfig{TokenURL: "https://idp.example.com/token"}.Validate()
+	if err == nil || err.Error() != "device login needs deviceAuthURL, clientID" {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestFileProviderRefreshesWithRefreshToken(t *testing.T) {
+	server := deviceFlowServer(t)
+	path := filepath.Join(t.TempDir(), "claude-auth.json")
+	data, err := json.Marshal(AuthFile{DefaultProfile: "default", Profiles: map[string]Profile{
+		"default": {
+			Token:        "expired",
+			ExpiresAt:    time.Now().Add(-time.Minute).UTC().Format(time.RFC3339),
+			RefreshURL:   server.URL + "/token",
+			RefreshToken: "refresh-1",
+			ClientID:     "kodama",
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	provider := NewFileProvider(FileConfig{Path: path})
+	creds, err := provider.GetCredentials(context.Background())
+	if err != nil {
+		t.Fatalf("GetCredentials() error = %v", err)
+	}
+	if creds.Token != "refreshed" {
+		t.Errorf("Token = %q, want the refreshed token", creds.Token)
+	}
+
+	// The refreshed token is written back, keeping the refresh token
+	authFile, err := provider.readAuthFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := authFile.Profiles["default"]
+	if profile.Token != "refreshed" || profile.RefreshToken != "refresh-1" {
+		t.Errorf("stored profile = %+v", profile)
+	}
+}
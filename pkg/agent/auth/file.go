@@ -7,13 +7,18 @@ import (
 	"os"
 	"path/filepath"
 	"time"
+
+	"github.com/illumination-k/kodama/pkg/encryption"
 )
 
 // FileProvider implements authentication using credentials stored in a file
+// Profiles with a refresh token are refreshed when they expire, and the new
+// token is written back to the file.
 type FileProvider struct {
 	config        FileConfig
 	lastRead      time.Time
 	cachedProfile *Profile
+	cachedName    string
 }
 
 // NewFileProvider creates a new file-based authentication provider
@@ -45,8 +50,17 @@ func (p *FileProvider) GetCredentials(ctx context.Context) (*Credentials, error)
 
 	// Cache the profile for refresh checks
 	p.cachedProfile = &profile
+	p.cachedName = profileName
 	p.lastRead = time.Now()
 
+	// Refresh an expiring token when the profile has a refresh token
+	if p.NeedsRefresh() && profile.RefreshToken != "" {
+		if err := p.Refresh(ctx); err != nil {
+			return nil, err
+		}
+		profile = *p.cachedProfile
+	}
+
 	// Parse expiration time if provided
 	var expiresAt *time.Time
 	if profile.ExpiresAt != "" {
@@ -96,25 +110,46 @@ func (p *FileProvider) NeedsRefresh() bool {
 	return time.Until(expiresAt) < 5*time.Minute
 }
 
-// Refresh attempts to refresh the token
+// Refresh exchanges the profile's refresh token at its refresh URL and writes
+// the new token back to the auth file
 func (p *FileProvider) Refresh(ctx context.Context) error {
 	if p.cachedProfile == nil || p.cachedProfile.RefreshURL == "" {
 		return fmt.Errorf("token refresh not supported: no refresh URL configured")
 	}
+	if p.cachedProfile.RefreshToken == "" {
+		return fmt.Errorf("token refresh not supported: profile %q has no refresh token, run 'kubectl kodama auth login'", p.cachedName)
+	}
 
-	// TODO: Implement HTTP call to refresh URL
-	// For now, return error indicating manual refresh is needed
-	return fmt.Errorf("automatic token refresh not yet implemented, please manually update the auth file")
+	token, err := RefreshOAuthToken(ctx, p.cachedProfile.RefreshURL, p.cachedProfile.ClientID, p.cachedProfile.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("%w; run 'kubectl kodama auth login' again", err)
+	}
+
+	profile := *p.cachedProfile
+	profile.Token = token.AccessToken
+	profile.RefreshToken = token.RefreshToken
+	profile.ExpiresAt = ""
+	if expiresAt := token.ExpiresAt(time.Now()); expiresAt != nil {
+		profile.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	}
+	p.cachedProfile = &profile
+
+	authFile, err := p.readAuthFile()
+	if err != nil {
+		return fmt.Errorf("failed to read auth file: %w", err)
+	}
+	authFile.Profiles[p.cachedName] = profile
+	return p.writeAuthFile(authFile)
 }
 
-// readAuthFile reads and parses the auth file
-func (p *FileProvider) readAuthFile() (*AuthFile, error) {
+// path returns the auth file path with ~ expanded
+func (p *FileProvider) path() (string, error) {
 	path := p.config.Path
 	if path == "" {
 		// Default to ~/.kodama/claude-auth.json
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+			return "", fmt.Errorf("failed to get home directory: %w", err)
 		}
 		path = filepath.Join(homeDir, ".kodama", "claude-auth.json")
 	}
@@ -123,10 +158,43 @@ func (p *FileProvider) readAuthFile() (*AuthFile, error) {
 	if len(path) > 0 && path[0] == '~' {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
-			return nil, fmt.Errorf("failed to get home directory: %w", err)
+			return "", fmt.Errorf("failed to get home directory: %w", err)
 		}
 		path = filepath.Join(homeDir, path[1:])
 	}
+	return path, nil
+}
+
+// writeAuthFile writes the auth file back, encrypted if it was encrypted before
+func (p *FileProvider) writeAuthFile(authFile *AuthFile) error {
+	path, err := p.path()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(authFile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal auth file: %w", err)
+	}
+
+	existing, err := os.ReadFile(path) // #nosec G304
+	if err == nil && encryption.IsEncrypted(existing) {
+		c, err := p.authFileCipher(path)
+		if err != nil {
+			return err
+		}
+		if data, err = c.Encrypt(data); err != nil {
+			return fmt.Errorf("failed to encrypt auth file: %w", err)
+		}
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// readAuthFile reads and parses the auth file
+func (p *FileProvider) readAuthFile() (*AuthFile, error) {
+	path, err := p.path()
+	if err != nil {
+		return nil, err
+	}
 
 	// Read file
 	data, err := os.ReadFile(path) // #nosec G304
@@ -134,6 +202,14 @@ func (p *FileProvider) readAuthFile() (*AuthFile, error) {
 		return nil, fmt.Errorf("failed to read file %s: %w", path, err)
 	}
 
+	// Decrypt if the auth cache was encrypted at rest (kodama store encrypt)
+	if encryption.IsEncrypted(data) {
+		data, err = p.decryptAuthFile(path, data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Parse JSON
 	var authFile AuthFile
 	if err := json.Unmarshal(data, &authFile); err != nil {
@@ -142,3 +218,33 @@ func (p *FileProvider) readAuthFile() (*AuthFile, error) {
 
 	return &authFile, nil
 }
+
+// decryptAuthFile decrypts an encrypted auth file with the store key
+func (p *FileProvider) decryptAuthFile(path string, data []byte) ([]byte, error) {
+	c, err := p.authFileCipher(path)
+	if err != nil {
+		return nil, err
+	}
+	return c.Decrypt(data)
+}
+
+// authFileCipher returns the cipher of an encrypted auth file
+// Without configured Keys, the key of the store the auth file belongs to is used.
+func (p *FileProvider) authFileCipher(path string) (*encryption.Cipher, error) {
+	keys := p.config.Keys
+	if keys == nil {
+		dir := filepath.Dir(path)
+		keys = &encryption.KeyStore{
+			Source:     encryption.KeySourceKeychain,
+			Account:    dir,
+			LegacyPath: filepath.Join(dir, "store.key"),
+		}
+	}
+
+	key, err := keys.Load()
+	if err != nil {
+		return nil, fmt.Errorf("auth file is encrypted but no key is available: %w", err)
+	}
+
+	return encryption.NewCipher(key)
+}
@@ -0,0 +1,297 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	googleMetadataTokenURL   = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+	googleTokenURL           = "https://oauth2.googleapis.com/token"
+	googleCloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+	defaultGoogleTokenEnvVar = "GOOGLE_OAUTH_ACCESS_TOKEN"
+)
+
+// GoogleADCProvider implements authentication with Google Application Default
+// Credentials: a user or service account credentials file, or the metadata
+// server of GCE and GKE (workload identity). Access tokens are cached until
+// shortly before they expire.
+type GoogleADCProvider struct {
+	config    GoogleADCConfig
+	client    *http.Client
+	token     string
+	source    string
+	expiresAt time.Time
+}
+
+// NewGoogleADCProvider creates a new Google ADC authentication provider
+func NewGoogleADCProvider(config GoogleADCConfig) *GoogleADCProvider {
+	return &GoogleADCProvider{config: config, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// googleCredentialsFile is the subset of an ADC JSON file kodama reads
+type googleCredentialsFile struct {
+	Type         string `json:"type"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+	RefreshToken string `json:"refresh_token"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// googleTokenResponse is the token response of the OAuth and metadata endpoints
+type googleTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int64  `json:"expires_in"`
+	Error       string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+// GetCredentials returns a cached access token, fetching a new one first when
+// there is none or it is about to expire
+func (p *GoogleADCProvider) GetCredentials(ctx context.Context) (*Credentials, error) {
+	if p.token == "" || p.NeedsRefresh() {
+		if err := p.Refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	envVar := p.config.EnvVar
+	if envVar == "" {
+		envVar = defaultGoogleTokenEnvVar
+	}
+	expiresAt := p.expiresAt
+	return &Credentials{
+		Token:     p.token,
+		EnvVar:    envVar,
+		ExpiresAt: &expiresAt,
+		Metadata:  map[string]string{"source": p.source},
+	}, nil
+}
+
+// Type returns the authentication type
+func (p *GoogleADCProvider) Type() AuthType {
+	return AuthTypeGoogleADC
+}
+
+// NeedsRefresh checks if the access token expires within 5 minutes
+func (p *GoogleADCProvider) NeedsRefresh() bool {
+	if p.token == "" {
+		return false
+	}
+	return time.Until(p.expiresAt) < 5*time.Minute
+}
+
+// Refresh fetches a new access token from the credentials file or, without
+// one, from the metadata server
+func (p *GoogleADCProvider) Refresh(ctx context.Context) error {
+	path, err := p.credentialsFile()
+	if err != nil {
+		return err
+	}
+
+	var resp *googleTokenResponse
+	if path == "" {
+		p.source = "metadata"
+		resp, err = p.fetchMetadataToken(ctx)
+	} else {
+		p.source = path
+		resp, err = p.fetchFileToken(ctx, path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get Google access token from %s: %w", p.source, err)
+	}
+
+	p.token = resp.AccessToken
+	p.expiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	return nil
+}
+
+// credentialsFile returns the ADC file to use, or "" for the metadata server
+// Priority: CredentialsFile > $GOOGLE_APPLICATION_CREDENTIALS > gcloud ADC file
+func (p *GoogleADCProvider) credentialsFile() (string, error) {
+	path := p.config.CredentialsFile
+	if path == "" {
+		path = os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	}
+	if path != "" {
+		// Expand ~ to home directory
+		if path[0] == '~' {
+			homeDir, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("failed to get home directory: %w", err)
+			}
+			path = filepath.Join(homeDir, path[1:])
+		}
+		return path, nil
+	}
+
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		gcloud := filepath.Join(homeDir, ".config", "gcloud", "application_default_credentials.json")
+		if _, err := os.Stat(gcloud); err == nil {
+			return gcloud, nil
+		}
+	}
+	return "", nil
+}
+
+// scopes returns the requested scopes (default: cloud-platform)
+func (p *GoogleADCProvider) scopes() []string {
+	if len(p.config.Scopes) > 0 {
+		return p.config.Scopes
+	}
+	return []string{googleCloudPlatformScope}
+}
+
+// fetchMetadataToken asks the metadata server for a token of the instance's
+// service account, which is the Kubernetes service account's with GKE workload identity
+func (p *GoogleADCProvider) fetchMetadataToken(ctx context.Context) (*googleTokenResponse, error) {
+	endpoint := p.config.MetadataURL
+	if endpoint == "" {
+		endpoint = googleMetadataTokenURL
+	}
+	endpoint += "?" + url.Values{"scopes": {strings.Join(p.scopes(), ",")}}.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	return p.doTokenRequest(req)
+}
+
+// fetchFileToken exchanges an authorized_user refresh token or a signed
+// service_account assertion for an access token
+func (p *GoogleADCProvider) fetchFileToken(ctx context.Context, path string) (*googleTokenResponse, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-configured credentials file
+	if err != nil {
+		return nil, err
+	}
+	var creds googleCredentialsFile
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+
+	tokenURL := p.config.TokenURL
+	if tokenURL == "" {
+		tokenURL = creds.TokenURI
+	}
+	if tokenURL == "" {
+		tokenURL = googleTokenURL
+	}
+
+	var form url.Values
+	switch creds.Type {
+	case "authorized_user":
+		form = url.Values{
+			"grant_type":    {"refresh_token"},
+			"client_id":     {creds.ClientID},
+			"client_secret": {creds.ClientSecret},
+			"refresh_token": {creds.RefreshToken},
+		}
+	case "service_account":
+		assertion, err := signServiceAccountJWT(creds, tokenURL, p.scopes(), time.Now())
+		if err != nil {
+			return nil, err
+		}
+		form = url.Values{
+			"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			"assertion":  {assertion},
+		}
+	default:
+		return nil, fmt.Errorf("unsupported credentials type %q (want authorized_user or service_account; use the metadata server for workload identity)", creds.Type)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return p.doTokenRequest(req)
+}
+
+// doTokenRequest sends a token request and decodes the response
+func (p *GoogleADCProvider) doTokenRequest(req *http.Request) (*googleTokenResponse, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	var token googleTokenResponse
+	_ = json.Unmarshal(body, &token) // Error responses may not be JSON
+	if resp.StatusCode != http.StatusOK {
+		if token.Error != "" {
+			return nil, fmt.Errorf("%s: %s %s", resp.Status, token.Error, token.Description)
+		}
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("response has no access token")
+	}
+	return &token, nil
+}
+
+// signServiceAccountJWT returns the RS256-signed assertion of the OAuth JWT
+// bearer grant for a service account key
+func signServiceAccountJWT(creds googleCredentialsFile, audience string, scopes []string, now time.Time) (string, error) {
+	block, _ := pem.Decode([]byte(creds.PrivateKey))
+	if block == nil {
+		return "", errors.New("service account private_key is not PEM")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return "", fmt.Errorf("failed to parse service account private_key: %w", err)
+		}
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return "", errors.New("service account private_key is not an RSA key")
+	}
+
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": creds.PrivateKeyID})
+	if err != nil {
+		return "", err
+	}
+	claims, err := json.Marshal(map[string]interface{}{
+		"iss":   creds.ClientEmail,
+		"scope": strings.Join(scopes, " "),
+		"aud":   audience,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign service account assertion: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
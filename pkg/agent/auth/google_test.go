@@ -0,0 +1,164 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// googleTokenServer answers token requests and records their forms
+func googleTokenServer(t *testing.T, forms *[]map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			if r.Header.Get("Metadata-Flavor") != "Google" {
+				http.Error(w, "missing Metadata-Flavor", http.StatusForbidden)
+				return
+			}
+			_, _ = w.Write([]byte(`{"access_token":"metadata-token","expires_in":3600}`))
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm() error = %v", err)
+		}
+		form := map[string]string{}
+		for key := range r.PostForm {
+			form[key] = r.PostForm.Get(key)
+		}
+		*forms = append(*forms, form)
+		if form["refresh_token"] == "revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"Token has been revoked"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"file-token","expires_in":3600}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func writeCredentialsFile(t *testing.T, creds map[string]string) string {
+	t.Helper()
+	data, err := json.Marshal(creds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "adc.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGoogleADCProvider_AuthorizedUser(t *testing.T) {
+	var forms []map[string]string
+	server := googleTokenServer(t, &forms)
+	path := writeCredentialsFile(t, map[string]string{
+		"type": "authorized_user", "client_id": "id", "client_secret": "secret", "refresh_token": "refresh",
+	})
+
+	provider := NewGoogleADCProvider(GoogleADCConfig{CredentialsFile: path, TokenURL: server.URL})
+	creds, err := provider.GetCredentials(context.Background())
+	if err != nil {
+		t.Fatalf("GetCredentials() error = %v", err)
+	}
+	if creds.Token != "file-token" || creds.EnvVar != "GOOGLE_OAUTH_ACCESS_TOKEN" {
+		t.Errorf("GetCredentials() = %s in %s, want file-token in GOOGLE_OAUTH_ACCESS_TOKEN", creds.Token, creds.EnvVar)
+	}
+	if creds.ExpiresAt == nil || time.Until(*creds.ExpiresAt) < 59*time.Minute {
+		t.Errorf("ExpiresAt = %v, want about an hour from now", creds.ExpiresAt)
+	}
+	if len(forms) != 1 || forms[0]["grant_type"] != "refresh_token" || forms[0]["refresh_token"] != "refresh" {
+		t.Errorf("token requests = %v, want one refresh_token grant", forms)
+	}
+
+	// The cached token is reused until it nears expiry
+	if _, err := provider.GetCredentials(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(forms) != 1 {
+		t.Errorf("token requests = %d, want the cached token reused", len(forms))
+	}
+	provider.expiresAt = time.Now().Add(time.Minute)
+	if !provider.NeedsRefresh() {
+		t.Error("NeedsRefresh() should be true within 5 minutes of expiry")
+	}
+}
+
+func TestGoogleADCProvider_Errors(t *testing.T) {
+	var forms []map[string]string
+	server := googleTokenServer(t, &forms)
+
+	revoked := writeCredentialsFile(t, map[string]string{"type": "authorized_user", "refresh_token": "revoked"})
+	_, err := NewGoogleADCProvider(GoogleADCConfig{CredentialsFile: revoked, TokenURL: server.URL}).GetCredentials(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "invalid_grant Token has been revoked") {
+		t.Errorf("GetCredentials() error = %v, want the OAuth error", err)
+	}
+
+	external := writeCredentialsFile(t, map[string]string{"type": "external_account"})
+	_, err = NewGoogleADCProvider(GoogleADCConfig{CredentialsFile: external, TokenURL: server.URL}).GetCredentials(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "unsupported credentials type") {
+		t.Errorf("GetCredentials() error = %v, want unsupported credentials type", err)
+	}
+}
+
+func TestGoogleADCProvider_ServiceAccount(t *testing.T) {
+	var forms []map[string]string
+	server := googleTokenServer(t, &forms)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := writeCredentialsFile(t, map[string]string{
+		"type":         "service_account",
+		"client_email": "agent@project.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    server.URL,
+	})
+
+	provider := NewGoogleADCProvider(GoogleADCConfig{CredentialsFile: path, EnvVar: "GEMINI_ACCESS_TOKEN"})
+	creds, err := provider.GetCredentials(context.Background())
+	if err != nil {
+		t.Fatalf("GetCredentials() error = %v", err)
+	}
+	if creds.Token != "file-token" || creds.EnvVar != "GEMINI_ACCESS_TOKEN" {
+		t.Errorf("GetCredentials() = %s in %s, want file-token in GEMINI_ACCESS_TOKEN", creds.Token, creds.EnvVar)
+	}
+	if len(forms) != 1 || forms[0]["grant_type"] != "urn:ietf:params:oauth:grant-type:jwt-bearer" || strings.Count(forms[0]["assertion"], ".") != 2 {
+		t.Errorf("token requests = %v, want one JWT bearer grant", forms)
+	}
+}
+
+func TestGoogleADCProvider_Metadata(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", "")
+	var forms []map[string]string
+	server := googleTokenServer(t, &forms)
+
+	provider := NewGoogleADCProvider(GoogleADCConfig{MetadataURL: server.URL})
+	creds, err := provider.GetCredentials(context.Background())
+	if err != nil {
+		t.Fatalf("GetCredentials() error = %v", err)
+	}
+	if creds.Token != "metadata-token" || creds.Metadata["source"] != "metadata" {
+		t.Errorf("GetCredentials() = %s from %s, want metadata-token from metadata", creds.Token, creds.Metadata["source"])
+	}
+	if provider.Type() != AuthTypeGoogleADC {
+		t.Errorf("Type() = %v, want %v", provider.Type(), AuthTypeGoogleADC)
+	}
+}
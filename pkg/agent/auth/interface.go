@@ -3,14 +3,18 @@ package auth
 import (
 	"context"
 	"time"
+
+	"github.com/illumination-k/kodama/pkg/encryption"
 )
 
 // AuthType represents the type of authentication
 type AuthType string
 
 const (
-	AuthTypeToken AuthType = "token"
-	AuthTypeFile  AuthType = "file"
+	AuthTypeToken     AuthType = "token"
+	AuthTypeFile      AuthType = "file"
+	AuthTypeGoogleADC AuthType = "google-adc" // Google Application Default Credentials, e.g. for Gemini
+	AuthTypeAPIKey    AuthType = "api-key"    // Static key of an OpenAI-compatible or Gemini API
 )
 
 // AuthProvider is the main interface for authentication providers
@@ -18,7 +22,7 @@ type AuthProvider interface {
 	// GetCredentials returns the authentication credentials
 	GetCredentials(ctx context.Context) (*Credentials, error)
 
-	// Type returns the authentication type (token, file)
+	// Type returns the authentication type (token, file, google-adc, api-key)
 	Type() AuthType
 
 	// NeedsRefresh indicates if credentials need to be refreshed
@@ -31,15 +35,18 @@ type AuthProvider interface {
 // Credentials holds authentication information
 type Credentials struct {
 	Token     string            // Bearer token or API key
+	EnvVar    string            // Environment variable the agent backend reads Token from (empty = CLAUDE_CODE_AUTH_TOKEN)
 	ExpiresAt *time.Time        // Optional expiration time
 	Metadata  map[string]string // Additional auth metadata
 }
 
 // AuthConfig represents the configuration for authentication
 type AuthConfig struct {
-	Type        AuthType    // Authentication type
-	TokenSource TokenConfig // For token auth
-	FileSource  FileConfig  // For file auth
+	Type            AuthType        // Authentication type
+	TokenSource     TokenConfig     // For token auth
+	FileSource      FileConfig      // For file auth
+	GoogleADCSource GoogleADCConfig // For google-adc auth
+	APIKeySource    APIKeyConfig    // For api-key auth
 }
 
 // TokenConfig represents configuration for token-based authentication
@@ -62,6 +69,46 @@ type FileConfig struct {
 
 	// Profile name (for multi-profile auth files)
 	Profile string
+
+	// Keys holds the store key for an auth file encrypted at rest
+	// (default: the key of the store in the auth file's directory)
+	Keys *encryption.KeyStore
+}
+
+// GoogleADCConfig represents configuration for Google Application Default Credentials
+type GoogleADCConfig struct {
+	// CredentialsFile is an authorized_user or service_account JSON file
+	// (default: $GOOGLE_APPLICATION_CREDENTIALS, then the gcloud ADC file).
+	// Without one, tokens come from the metadata server, as with GKE workload identity.
+	CredentialsFile string
+
+	// Scopes requested for the access token (default: cloud-platform)
+	Scopes []string
+
+	// EnvVar the access token is exposed as (default: GOOGLE_OAUTH_ACCESS_TOKEN)
+	EnvVar string
+
+	// MetadataURL and TokenURL override the Google endpoints (for testing only)
+	MetadataURL string
+	TokenURL    string
+}
+
+// APIKeyConfig represents configuration for static API keys of OpenAI-compatible
+// and Gemini backends
+type APIKeyConfig struct {
+	// Provider names the backend: openai (default), gemini, anthropic or
+	// openrouter. Other OpenAI-compatible servers need EnvVar.
+	Provider string
+
+	// EnvVar holds the key locally and names it for the agent
+	// (default: the provider's, e.g. OPENAI_API_KEY)
+	EnvVar string
+
+	// BaseURL of an OpenAI-compatible server, e.g. http://vllm:8000/v1
+	BaseURL string
+
+	// Direct key value (for testing only)
+	Key string
 }
 
 // AuthFile represents the structure of the auth file
@@ -72,7 +119,9 @@ type AuthFile struct {
 
 // Profile represents a single authentication profile in the auth file
 type Profile struct {
-	Token      string `json:"token"`
-	ExpiresAt  string `json:"expiresAt,omitempty"`
-	RefreshURL string `json:"refreshUrl,omitempty"` // For token refresh
+	Token        string `json:"token"`
+	ExpiresAt    string `json:"expiresAt,omitempty"`
+	RefreshURL   string `json:"refreshUrl,omitempty"`   // OAuth token endpoint for token refresh
+	RefreshToken string `json:"refreshToken,omitempty"` // Written by kodama auth login
+	ClientID     string `json:"clientId,omitempty"`     // OAuth client the refresh token belongs to
 }
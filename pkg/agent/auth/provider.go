@@ -13,8 +13,17 @@ func NewAuthProvider(config AuthConfig) (AuthProvider, error) {
 		return NewTokenProvider(config.TokenSource), nil
 	case AuthTypeFile:
 		return NewFileProvider(config.FileSource), nil
+	case AuthTypeGoogleADC:
+		return NewGoogleADCProvider(config.GoogleADCSource), nil
+	case AuthTypeAPIKey:
+		provider, err := NewAPIKeyProvider(config.APIKeySource)
+		if err != nil {
+			return nil, err
+		}
+		return provider, nil
 	default:
-		return nil, fmt.Errorf("unsupported auth type: %s", config.Type)
+		return nil, fmt.Errorf("unsupported auth type %q (want %s, %s, %s or %s)", config.Type,
+			AuthTypeToken, AuthTypeFile, AuthTypeGoogleADC, AuthTypeAPIKey)
 	}
 }
 
@@ -0,0 +1,60 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// DiffStatScript prints 'git diff --stat' for the workspace against the commit
+// in $1 (default HEAD), listing untracked files as " new: <path>"
+const DiffStatScript = `cd /workspace && git diff --stat "${1:-HEAD}" && git ls-files --others --exclude-standard | sed 's/^/ new: /'`
+
+// changedFilesScript prints the files changed since the commit in $1 (default
+// HEAD), committed or not, and the untracked files, one per line
+const changedFilesScript = `cd /workspace && git diff --name-only "${1:-HEAD}" && git ls-files --others --exclude-standard`
+
+// headScript prints the commit checked out in the workspace
+const headScript = "cd /workspace && git rev-parse HEAD"
+
+// Changes summarizes the changes in a session workspace since a base commit
+type Changes struct {
+	Stat  string   // 'git diff --stat' output, empty when nothing changed
+	Files []string // Modified and untracked files, relative to /workspace
+}
+
+// HeadCommit returns the commit checked out in the workspace in the pod
+// Record it before a task starts, so CollectChanges also sees what the agent commits.
+func HeadCommit(ctx context.Context, executor kubernetes.CommandExecutor, namespace, podName string) (string, error) {
+	stdout, stderr, err := executor.ExecInPod(ctx, namespace, podName, []string{"sh", "-c", headScript})
+	if err != nil {
+		return "", fmt.Errorf("failed to read workspace HEAD: %s: %w", strings.TrimSpace(stderr), err)
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
+// CollectChanges computes the diff summary and changed files of the workspace in
+// the pod since base, including commits made after it (empty base = HEAD)
+func CollectChanges(ctx context.Context, executor kubernetes.CommandExecutor, namespace, podName, base string) (*Changes, error) {
+	stat, stderr, err := executor.ExecInPod(ctx, namespace, podName, []string{"sh", "-c", DiffStatScript, "sh", base})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get diff summary: %s: %w", strings.TrimSpace(stderr), err)
+	}
+
+	files, stderr, err := executor.ExecInPod(ctx, namespace, podName, []string{"sh", "-c", changedFilesScript, "sh", base})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed files: %s: %w", strings.TrimSpace(stderr), err)
+	}
+
+	changes := &Changes{Stat: strings.TrimRight(stat, " \n")}
+	seen := map[string]bool{}
+	for _, file := range strings.Split(files, "\n") {
+		if file = strings.TrimSpace(file); file != "" && !seen[file] {
+			seen[file] = true
+			changes.Files = append(changes.Files, file)
+		}
+	}
+	return changes, nil
+}
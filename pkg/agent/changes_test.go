@@ -0,0 +1,38 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestCollectChanges(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("sh -c "+DiffStatScript, " main.go | 2 +-\n 1 file changed\n new: notes.md\n", "", nil)
+	executor.SetResponse("sh -c "+changedFilesScript, "main.go\nnotes.md\n", "", nil)
+
+	changes, err := CollectChanges(context.Background(), executor, "dev", "kodama-work", "")
+	require.NoError(t, err)
+	assert.Equal(t, " main.go | 2 +-\n 1 file changed\n new: notes.md", changes.Stat)
+	assert.Equal(t, []string{"main.go", "notes.md"}, changes.Files)
+}
+
+func TestCollectChanges_NoChanges(t *testing.T) {
+	changes, err := CollectChanges(context.Background(), kubernetes.NewMockExecutor(), "dev", "kodama-work", "")
+	require.NoError(t, err)
+	assert.Empty(t, changes.Stat)
+	assert.Empty(t, changes.Files)
+}
+
+func TestCollectChanges_Error(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("sh -c "+DiffStatScript, "", "fatal: not a git repository", errors.New("exit status 128"))
+
+	_, err := CollectChanges(context.Background(), executor, "dev", "kodama-work", "")
+	assert.ErrorContains(t, err, "not a git repository")
+}
@@ -0,0 +1,72 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// ContextDir is where files attached to a prompt are uploaded in the pod
+// Like RunsDir, it is git-ignored so attachments are never committed.
+const ContextDir = kubernetes.RecordingsParentDir + "/context"
+
+// uploadContextScript writes stdin to the file in $1, creating ContextDir
+const uploadContextScript = `mkdir -p ` + ContextDir + `
+[ -f ` + ContextDir + `/.gitignore ] || printf '*\n' > ` + ContextDir + `/.gitignore
+cat > "$1"`
+
+// ContextFilePaths returns the pod paths of local files attached to a prompt
+// Files keep their base name; later files with a name already taken get a
+// numeric prefix, e.g. "2-error.log".
+func ContextFilePaths(files []string) []string {
+	paths := make([]string, len(files))
+	taken := make(map[string]bool, len(files))
+	for i, file := range files {
+		name := filepath.Base(file)
+		for n := 2; taken[name]; n++ {
+			name = strconv.Itoa(n) + "-" + filepath.Base(file)
+		}
+		taken[name] = true
+		paths[i] = path.Join(ContextDir, name)
+	}
+	return paths
+}
+
+// UploadContextFiles copies local files into ContextDir in the pod and returns
+// their pod paths, see ContextFilePaths
+func UploadContextFiles(ctx context.Context, executor kubernetes.InputExecutor, namespace, podName string, files []string) ([]string, error) {
+	paths := ContextFilePaths(files)
+	for i, file := range files {
+		content, err := os.ReadFile(file) // #nosec G304 -- file attached by the user
+		if err != nil {
+			return nil, fmt.Errorf("failed to read attached file: %w", err)
+		}
+		_, stderr, err := executor.ExecInPodWithInput(ctx, namespace, podName,
+			[]string{"sh", "-c", uploadContextScript, "sh", paths[i]}, bytes.NewReader(content))
+		if err != nil {
+			return nil, fmt.Errorf("failed to upload %s: %s: %w", file, strings.TrimSpace(stderr), err)
+		}
+	}
+	return paths, nil
+}
+
+// PromptWithContextFiles appends references to the attached files at paths to prompt
+func PromptWithContextFiles(prompt string, paths []string) string {
+	if len(paths) == 0 {
+		return prompt
+	}
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(prompt, "\n"))
+	b.WriteString("\n\nAttached context files (read them before you start):\n")
+	for _, p := range paths {
+		b.WriteString("- " + p + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestContextFilePaths(t *testing.T) {
+	paths := ContextFilePaths([]string{"docs/design.md", "logs/error.log", "ci/error.log", "2-error.log"})
+	assert.Equal(t, []string{
+		ContextDir + "/design.md",
+		ContextDir + "/error.log",
+		ContextDir + "/2-error.log",
+		ContextDir + "/2-2-error.log",
+	}, paths)
+}
+
+func TestUploadContextFiles(t *testing.T) {
+	dir := t.TempDir()
+	design := filepath.Join(dir, "design.md")
+	require.NoError(t, os.WriteFile(design, []byte("# Design\n"), 0o600))
+
+	executor := kubernetes.NewMockExecutor()
+	paths, err := UploadContextFiles(context.Background(), executor, "dev", "kodama-work", []string{design})
+	require.NoError(t, err)
+	assert.Equal(t, []string{ContextDir + "/design.md"}, paths)
+
+	require.Len(t, executor.Commands, 1)
+	assert.Equal(t, []string{"sh", "-c", uploadContextScript, "sh", ContextDir + "/design.md"}, executor.Commands[0].Command)
+	assert.Equal(t, "# Design\n", executor.Commands[0].Input)
+
+	_, err = UploadContextFiles(context.Background(), executor, "dev", "kodama-work", []string{filepath.Join(dir, "missing.log")})
+	assert.ErrorContains(t, err, "failed to read attached file")
+}
+
+func TestPromptWithContextFiles(t *testing.T) {
+	assert.Equal(t, "Fix the bug", PromptWithContextFiles("Fix the bug", nil))
+	assert.Equal(t, "Fix the bug\n\nAttached context files (read them before you start):\n"+
+		"- /workspace/.kodama/context/error.log\n",
+		PromptWithContextFiles("Fix the bug\n", []string{"/workspace/.kodama/context/error.log"}))
+}
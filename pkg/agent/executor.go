@@ -3,10 +3,15 @@ package agent
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/illumination-k/kodama/pkg/agent/auth"
 	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/metrics"
+	"github.com/illumination-k/kodama/pkg/shellutil"
 )
 
 // realCodingAgentExecutor implements CodingAgentExecutor using kubectl exec
@@ -28,9 +33,13 @@ func NewCodingAgentExecutor() CodingAgentExecutor {
 }
 
 // NewCodingAgentExecutorWithAuth creates executor with specified auth provider
-func NewCodingAgentExecutorWithAuth(authProvider auth.AuthProvider) CodingAgentExecutor {
+// A nil cmdExec runs commands with kubectl exec.
+func NewCodingAgentExecutorWithAuth(authProvider auth.AuthProvider, cmdExec kubernetes.CommandExecutor) CodingAgentExecutor {
+	if cmdExec == nil {
+		cmdExec = kubernetes.NewKubectlExecutor()
+	}
 	return &realCodingAgentExecutor{
-		commandExecutor: kubernetes.NewKubectlExecutor(),
+		commandExecutor: cmdExec,
 		authProvider:    authProvider,
 		sanitizer:       auth.NewSanitizer(),
 	}
@@ -48,9 +57,11 @@ func NewCodingAgentExecutorWithCommandExecutor(cmdExec kubernetes.CommandExecuto
 }
 
 // TaskStart initiates a coding task in the pod
-func (r *realCodingAgentExecutor) TaskStart(ctx context.Context, namespace, podName, prompt string) (string, error) {
+func (r *realCodingAgentExecutor) TaskStart(ctx context.Context, namespace, podName, prompt, logPath string) (taskID string, err error) {
+	defer func(start time.Time) { metrics.ObserveAgentExecution(start, err) }(time.Now())
+
 	// Get authentication credentials if auth provider is available
-	var token string
+	var token, tokenEnvVar string
 	if r.authProvider != nil {
 		// Check if token needs refresh
 		if r.authProvider.NeedsRefresh() {
@@ -65,32 +76,46 @@ func (r *realCodingAgentExecutor) TaskStart(ctx context.Context, namespace, podN
 			return "", r.sanitizer.SanitizeError(fmt.Errorf("failed to get credentials: %w", err))
 		}
 
-		token = creds.Token
+		token, tokenEnvVar = creds.Token, creds.EnvVar
+		if tokenEnvVar == "" {
+			tokenEnvVar = "CLAUDE_CODE_AUTH_TOKEN"
+		}
 		r.sanitizer.AddToken(token)
 	}
 
+	// The CLI lives in an emptyDir; reinstall it if a container restart wiped it
+	if _, err := kubernetes.EnsureClaude(ctx, r.commandExecutor, namespace, podName); err != nil {
+		return "", r.sanitizer.SanitizeError(err)
+	}
+
 	// For now, this is a placeholder that echoes the prompt
 	// Future implementation will invoke actual claude-code agent
 	// Example: claude-code agent --token "$TOKEN" --prompt "$PROMPT"
 
-	// Escape single quotes in prompt for shell safety
-	escapedPrompt := strings.ReplaceAll(prompt, "'", "'\\''")
-
 	var command []string
 	if token != "" {
 		// If we have a token, we could pass it to claude-code
 		// For now, just echo that we have authentication
 		command = []string{
 			"sh", "-c",
-			fmt.Sprintf("echo 'Task started with prompt: %s (authenticated)' && echo 'task-placeholder-id'", escapedPrompt),
+			fmt.Sprintf("echo %s && echo 'task-placeholder-id'", shellutil.Quote("Task started with prompt: "+prompt+" (authenticated with "+tokenEnvVar+")")),
 		}
 	} else {
 		command = []string{
 			"sh", "-c",
-			fmt.Sprintf("echo 'Task started with prompt: %s' && echo 'task-placeholder-id'", escapedPrompt),
+			fmt.Sprintf("echo %s && echo 'task-placeholder-id'", shellutil.Quote("Task started with prompt: "+prompt)),
 		}
 	}
 
+	if logPath != "" {
+		command = captureOutput(command, logPath)
+	}
+
+	// Bound the task in the pod as well, e.g. by the session's agent time budget
+	if deadline, ok := ctx.Deadline(); ok {
+		command = withTimeout(command, time.Until(deadline))
+	}
+
 	stdout, stderr, err := r.commandExecutor.ExecInPod(ctx, namespace, podName, command)
 	if err != nil {
 		return "", r.sanitizer.SanitizeError(fmt.Errorf("failed to start task: %s: %w", stderr, err))
@@ -106,3 +131,34 @@ func (r *realCodingAgentExecutor) TaskStart(ctx context.Context, namespace, podN
 
 	return "task-placeholder-id", nil
 }
+
+// withTimeout runs command under timeout(1), so it is stopped in the pod after d
+// even if the exec connection is gone
+func withTimeout(command []string, d time.Duration) []string {
+	seconds := int64(math.Ceil(d.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return append([]string{"timeout", strconv.FormatInt(seconds, 10)}, command...)
+}
+
+// captureOutput wraps an "sh -c" command so its stdout and stderr are also written to logPath
+// The log is printed to stdout on success and to stderr on failure, keeping the exit status.
+// The log directory gets a ".gitignore" of "*" so that logs written inside the
+// workspace are never committed or reported as changes.
+func captureOutput(command []string, logPath string) []string {
+	log := shellutil.Quote(logPath)
+	return []string{
+		"sh", "-c",
+		fmt.Sprintf(`dir="$(dirname %[1]s)"
+mkdir -p "$dir"
+[ -f "$dir/.gitignore" ] || printf '*\n' > "$dir/.gitignore"
+if ( %[2]s ) >%[1]s 2>&1; then
+    cat %[1]s
+else
+    status=$?
+    cat %[1]s >&2
+    exit $status
+fi`, log, command[len(command)-1]),
+	}
+}
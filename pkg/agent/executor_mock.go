@@ -7,7 +7,7 @@ import (
 
 // MockCodingAgentExecutor is a mock implementation for testing
 type MockCodingAgentExecutor struct {
-	TaskStartFunc  func(ctx context.Context, namespace, podName, prompt string) (string, error)
+	TaskStartFunc  func(ctx context.Context, namespace, podName, prompt, logPath string) (string, error)
 	TaskStartCalls []TaskStartCall
 	NextTaskID     int
 }
@@ -17,6 +17,7 @@ type TaskStartCall struct {
 	Namespace string
 	PodName   string
 	Prompt    string
+	LogPath   string
 }
 
 // NewMockCodingAgentExecutor creates a new mock executor
@@ -28,17 +29,18 @@ func NewMockCodingAgentExecutor() *MockCodingAgentExecutor {
 }
 
 // TaskStart records the call and returns a mock task ID
-func (m *MockCodingAgentExecutor) TaskStart(ctx context.Context, namespace, podName, prompt string) (string, error) {
+func (m *MockCodingAgentExecutor) TaskStart(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
 	// Record the call
 	m.TaskStartCalls = append(m.TaskStartCalls, TaskStartCall{
 		Namespace: namespace,
 		PodName:   podName,
 		Prompt:    prompt,
+		LogPath:   logPath,
 	})
 
 	// Use custom function if provided
 	if m.TaskStartFunc != nil {
-		return m.TaskStartFunc(ctx, namespace, podName, prompt)
+		return m.TaskStartFunc(ctx, namespace, podName, prompt, logPath)
 	}
 
 	// Default behavior: return sequential task IDs
@@ -3,7 +3,12 @@ package agent
 import (
 	"context"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,7 +18,7 @@ func TestMockCodingAgentExecutor_TaskStart_Default(t *testing.T) {
 	mock := NewMockCodingAgentExecutor()
 	ctx := context.Background()
 
-	taskID, err := mock.TaskStart(ctx, "test-ns", "test-pod", "test prompt")
+	taskID, err := mock.TaskStart(ctx, "test-ns", "test-pod", "test prompt", "")
 
 	require.NoError(t, err)
 	assert.Equal(t, "task-1", taskID)
@@ -29,11 +34,11 @@ func TestMockCodingAgentExecutor_TaskStart_SequentialIDs(t *testing.T) {
 	mock := NewMockCodingAgentExecutor()
 	ctx := context.Background()
 
-	taskID1, err := mock.TaskStart(ctx, "ns1", "pod1", "prompt1")
+	taskID1, err := mock.TaskStart(ctx, "ns1", "pod1", "prompt1", "")
 	require.NoError(t, err)
 	assert.Equal(t, "task-1", taskID1)
 
-	taskID2, err := mock.TaskStart(ctx, "ns2", "pod2", "prompt2")
+	taskID2, err := mock.TaskStart(ctx, "ns2", "pod2", "prompt2", "")
 	require.NoError(t, err)
 	assert.Equal(t, "task-2", taskID2)
 
@@ -43,12 +48,12 @@ func TestMockCodingAgentExecutor_TaskStart_SequentialIDs(t *testing.T) {
 
 func TestMockCodingAgentExecutor_TaskStart_CustomFunc(t *testing.T) {
 	mock := NewMockCodingAgentExecutor()
-	mock.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt string) (string, error) {
+	mock.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
 		return "custom-task-id", nil
 	}
 
 	ctx := context.Background()
-	taskID, err := mock.TaskStart(ctx, "ns", "pod", "prompt")
+	taskID, err := mock.TaskStart(ctx, "ns", "pod", "prompt", "")
 
 	require.NoError(t, err)
 	assert.Equal(t, "custom-task-id", taskID)
@@ -56,12 +61,12 @@ func TestMockCodingAgentExecutor_TaskStart_CustomFunc(t *testing.T) {
 
 func TestMockCodingAgentExecutor_TaskStart_Error(t *testing.T) {
 	mock := NewMockCodingAgentExecutor()
-	mock.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt string) (string, error) {
+	mock.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
 		return "", fmt.Errorf("simulated error")
 	}
 
 	ctx := context.Background()
-	taskID, err := mock.TaskStart(ctx, "ns", "pod", "prompt")
+	taskID, err := mock.TaskStart(ctx, "ns", "pod", "prompt", "")
 
 	assert.Error(t, err)
 	assert.Empty(t, taskID)
@@ -71,8 +76,8 @@ func TestMockCodingAgentExecutor_TaskStart_Error(t *testing.T) {
 func TestMockCodingAgentExecutor_Reset(t *testing.T) {
 	mock := NewMockCodingAgentExecutor()
 
-	_, _ = mock.TaskStart(context.Background(), "ns1", "pod1", "prompt1")
-	_, _ = mock.TaskStart(context.Background(), "ns2", "pod2", "prompt2")
+	_, _ = mock.TaskStart(context.Background(), "ns1", "pod1", "prompt1", "")
+	_, _ = mock.TaskStart(context.Background(), "ns2", "pod2", "prompt2", "")
 
 	require.Len(t, mock.GetTaskStartCalls(), 2)
 
@@ -86,9 +91,9 @@ func TestMockCodingAgentExecutor_RecordsAllCalls(t *testing.T) {
 	mock := NewMockCodingAgentExecutor()
 	ctx := context.Background()
 
-	_, _ = mock.TaskStart(ctx, "ns1", "pod1", "prompt1")
-	_, _ = mock.TaskStart(ctx, "ns2", "pod2", "prompt2")
-	_, _ = mock.TaskStart(ctx, "ns3", "pod3", "prompt3")
+	_, _ = mock.TaskStart(ctx, "ns1", "pod1", "prompt1", "")
+	_, _ = mock.TaskStart(ctx, "ns2", "pod2", "prompt2", "")
+	_, _ = mock.TaskStart(ctx, "ns3", "pod3", "prompt3", "")
 
 	calls := mock.GetTaskStartCalls()
 	require.Len(t, calls, 3)
@@ -105,3 +110,56 @@ func TestMockCodingAgentExecutor_RecordsAllCalls(t *testing.T) {
 	assert.Equal(t, "pod3", calls[2].PodName)
 	assert.Equal(t, "prompt3", calls[2].Prompt)
 }
+
+func TestCaptureOutput_IgnoresLogDir(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "agent-runs", "20250101-000000.log")
+	command := captureOutput([]string{"sh", "-c", "echo hello"}, logPath)
+
+	out, err := exec.Command(command[0], command[1:]...).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(out))
+
+	logData, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Equal(t, "hello\n", string(logData))
+
+	ignore, err := os.ReadFile(filepath.Join(filepath.Dir(logPath), ".gitignore"))
+	require.NoError(t, err)
+	assert.Equal(t, "*\n", string(ignore))
+}
+
+func TestCaptureOutput_KeepsExitStatus(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "run.log")
+	command := captureOutput([]string{"sh", "-c", "echo broken >&2; exit 3"}, logPath)
+
+	err := exec.Command(command[0], command[1:]...).Run()
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.ExitCode())
+}
+
+func TestLatestRunLogScript(t *testing.T) {
+	dir := t.TempDir()
+	script := strings.ReplaceAll(LatestRunLogScript(2, false), RunsDir, dir)
+
+	err := exec.Command("sh", "-c", script).Run()
+	require.Error(t, err, "no task has run yet")
+
+	older := time.Now().Add(-time.Hour)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20250101-000000.log"), []byte("old\n"), 0o600))
+	require.NoError(t, os.Chtimes(filepath.Join(dir, "20250101-000000.log"), older, older))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "20250102-000000.log"), []byte("one\ntwo\nthree\n"), 0o600))
+
+	out, err := exec.Command("sh", "-c", script).Output()
+	require.NoError(t, err)
+	assert.Equal(t, "two\nthree\n", string(out))
+
+	assert.Contains(t, LatestRunLogScript(200, true), `tail -n 200 -F "$log"`)
+}
+
+func TestWithTimeout(t *testing.T) {
+	command := []string{"sh", "-c", "echo hi"}
+
+	assert.Equal(t, []string{"timeout", "91", "sh", "-c", "echo hi"}, withTimeout(command, 90*time.Second+time.Millisecond))
+	assert.Equal(t, []string{"timeout", "1", "sh", "-c", "echo hi"}, withTimeout(command, -time.Second), "an expired deadline still runs under a timeout")
+}
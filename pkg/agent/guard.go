@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// revertScript restores each argument after $1 to the commit in $1 (default
+// HEAD), deleting files that commit does not have
+const revertScript = `cd /workspace && base="${1:-HEAD}" && shift && for f in "$@"; do
+    if git cat-file -e "$base:$f" 2>/dev/null; then git checkout "$base" -- "$f"; else git rm -q --cached --ignore-unmatch -- "$f"; rm -f -- "$f"; fi
+done`
+
+// MatchProtectedPaths returns the files matching any of the glob patterns
+// In patterns, "*" and "?" do not cross "/", while "**" matches any number of directories.
+func MatchProtectedPaths(patterns, files []string) []string {
+	if len(patterns) == 0 {
+		return nil
+	}
+
+	regexps := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		regexps = append(regexps, globRegexp(pattern))
+	}
+
+	var matched []string
+	for _, file := range files {
+		for _, re := range regexps {
+			if re.MatchString(file) {
+				matched = append(matched, file)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// globRegexp converts a glob pattern to an anchored regular expression
+func globRegexp(pattern string) *regexp.Regexp {
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			sb.WriteString("(.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+// RevertFiles restores files in the workspace to their state at base (empty = HEAD)
+// Files that do not exist at base are deleted. Commits made since base are kept;
+// the restored content shows up as uncommitted changes.
+func RevertFiles(ctx context.Context, executor kubernetes.CommandExecutor, namespace, podName, base string, files []string) error {
+	command := append([]string{"sh", "-c", revertScript, "sh", base}, files...)
+	if _, stderr, err := executor.ExecInPod(ctx, namespace, podName, command); err != nil {
+		return fmt.Errorf("failed to revert %s: %s: %w", strings.Join(files, ", "), strings.TrimSpace(stderr), err)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestMatchProtectedPaths(t *testing.T) {
+	files := []string{
+		"infra/main.tf",
+		"infra/modules/vpc/main.tf",
+		"infrastructure.md",
+		".github/workflows/ci.yml",
+		".github/CODEOWNERS",
+		"src/app.go",
+		"deploy/secrets.env",
+		"nested/deploy/secrets.env",
+	}
+
+	tests := []struct {
+		patterns []string
+		want     []string
+	}{
+		{patterns: []string{"infra/**"}, want: []string{"infra/main.tf", "infra/modules/vpc/main.tf"}},
+		{patterns: []string{".github/workflows/**"}, want: []string{".github/workflows/ci.yml"}},
+		{patterns: []string{"infra/*.tf"}, want: []string{"infra/main.tf"}},
+		{patterns: []string{"**/secrets.env"}, want: []string{"deploy/secrets.env", "nested/deploy/secrets.env"}},
+		{patterns: []string{"/src/app.go", ".github/CODEOWNERS"}, want: []string{".github/CODEOWNERS", "src/app.go"}},
+		{patterns: nil, want: nil},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MatchProtectedPaths(tt.patterns, files), "%v", tt.patterns)
+	}
+}
+
+func TestRevertFiles(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+
+	err := RevertFiles(context.Background(), executor, "dev", "kodama-work", "abc123", []string{"infra/main.tf", "infra/new.tf"})
+	require.NoError(t, err)
+
+	commands := executor.GetCommands()
+	require.Len(t, commands, 1)
+	assert.Equal(t, []string{"sh", "-c", revertScript, "sh", "abc123", "infra/main.tf", "infra/new.tf"}, commands[0].Command)
+}
+
+// workspaceExecutor runs pod commands locally, with /workspace mapped to dir
+type workspaceExecutor struct {
+	dir string
+}
+
+func (w workspaceExecutor) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	args := append([]string{}, command...)
+	args[2] = strings.ReplaceAll(args[2], "/workspace", w.dir)
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestProtectedPaths_CommittedByAgent(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	write := func(name, content string) {
+		t.Helper()
+		require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o750))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+	}
+
+	git("init", "-q")
+	write("infra/main.tf", "original\n")
+	write("src/app.go", "package app\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	ctx := context.Background()
+	executor := workspaceExecutor{dir: dir}
+	base, err := HeadCommit(ctx, executor, "dev", "kodama-work")
+	require.NoError(t, err)
+
+	// The agent commits a protected change and leaves another one uncommitted
+	write("infra/main.tf", "changed by agent\n")
+	write("src/app.go", "package app\n\nfunc Run() {}\n")
+	git("commit", "-q", "-am", "agent work")
+	write("infra/extra.tf", "new\n")
+
+	changes, err := CollectChanges(ctx, executor, "dev", "kodama-work", base)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"infra/main.tf", "src/app.go", "infra/extra.tf"}, changes.Files)
+	assert.Contains(t, changes.Stat, "infra/main.tf")
+
+	protected := MatchProtectedPaths([]string{"infra/**"}, changes.Files)
+	assert.ElementsMatch(t, []string{"infra/main.tf", "infra/extra.tf"}, protected)
+
+	require.NoError(t, RevertFiles(ctx, executor, "dev", "kodama-work", base, protected))
+	data, err := os.ReadFile(filepath.Join(dir, "infra/main.tf"))
+	require.NoError(t, err)
+	assert.Equal(t, "original\n", string(data))
+	assert.NoFileExists(t, filepath.Join(dir, "infra/extra.tf"))
+
+	changes, err = CollectChanges(ctx, executor, "dev", "kodama-work", base)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"src/app.go"}, changes.Files)
+}
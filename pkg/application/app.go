@@ -1,40 +1,48 @@
 package application
 
 import (
+	"context"
 	"fmt"
+	"sync"
 
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/application/port"
 	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/config"
 	agentAdapter "github.com/illumination-k/kodama/pkg/infrastructure/agent"
 	kubernetesAdapter "github.com/illumination-k/kodama/pkg/infrastructure/kubernetes"
 	"github.com/illumination-k/kodama/pkg/infrastructure/repository"
 	syncAdapter "github.com/illumination-k/kodama/pkg/infrastructure/sync"
+	"github.com/illumination-k/kodama/pkg/usecase"
 )
 
 // App holds all application services and dependencies
 type App struct {
 	SessionService *service.SessionService
+	Dependencies   *Dependencies
 }
 
 // NewApp creates and wires up the entire application with all dependencies
+// The store and Kubernetes client are shared with the usecase layer.
 func NewApp(kubeconfigPath string) (*App, error) {
+	deps := NewDependencies()
+
 	// Create infrastructure adapters
-	k8sClient, err := kubernetesAdapter.NewAdapter(kubeconfigPath)
+	client, err := deps.KubernetesClient(kubeconfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
+	k8sClient := kubernetesAdapter.NewAdapterWithClient(client)
 
 	syncMgr := syncAdapter.NewAdapter()
-	agentExec := agentAdapter.NewAdapter()
 
-	sessionRepo, err := repository.NewSessionFileRepository()
+	store, err := deps.Store()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create session repository: %w", err)
-	}
-
-	configRepo, err := repository.NewConfigFileRepository()
-	if err != nil {
-		return nil, fmt.Errorf("failed to create config repository: %w", err)
+		return nil, fmt.Errorf("failed to create config store: %w", err)
 	}
+	agentExec := &lazyAgentAdapter{store: store}
+	sessionRepo := repository.NewSessionFileRepositoryWithStore(store)
+	configRepo := repository.NewConfigFileRepositoryWithStore(store)
 
 	// Wire services
 	sessionService := service.NewSessionService(
@@ -45,7 +53,48 @@ func NewApp(kubeconfigPath string) (*App, error) {
 		agentExec,
 	)
 
+	usecase.SetDependencies(deps)
+
 	return &App{
 		SessionService: sessionService,
+		Dependencies:   deps,
 	}, nil
 }
+
+// lazyAgentAdapter builds the agent adapter when an agent first runs, so an
+// invalid claudeAuth only fails the commands that run the coding agent
+type lazyAgentAdapter struct {
+	store *config.Store
+
+	once    sync.Once
+	adapter port.AgentExecutor
+	err     error
+}
+
+// TaskStart implements port.AgentExecutor
+func (a *lazyAgentAdapter) TaskStart(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
+	a.once.Do(func() {
+		a.adapter, a.err = newAgentAdapter(a.store)
+	})
+	if a.err != nil {
+		return "", a.err
+	}
+	return a.adapter.TaskStart(ctx, namespace, podName, prompt, logPath)
+}
+
+// newAgentAdapter returns the agent adapter, authenticated as claudeAuth in the
+// global config selects
+func newAgentAdapter(store *config.Store) (port.AgentExecutor, error) {
+	globalConfig, err := store.LoadGlobalConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global config: %w", err)
+	}
+	provider, err := globalConfig.ClaudeAuth.NewProvider()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", config.ErrInvalidClaudeAuth, err)
+	}
+	if provider == nil {
+		return agentAdapter.NewAdapter(), nil
+	}
+	return agentAdapter.NewAdapterWithExecutor(agent.NewCodingAgentExecutorWithAuth(provider, nil)), nil
+}
@@ -0,0 +1,52 @@
+package application
+
+import (
+	"sync"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// Dependencies lazily constructs the config store and Kubernetes clients and
+// caches them for the lifetime of the process. It implements usecase.Dependencies.
+type Dependencies struct {
+	mu      sync.Mutex
+	store   *config.Store
+	clients map[string]*kubernetes.Client
+}
+
+// NewDependencies creates an empty dependency cache
+func NewDependencies() *Dependencies {
+	return &Dependencies{clients: make(map[string]*kubernetes.Client)}
+}
+
+// Store returns the shared config store, creating it on first use
+func (d *Dependencies) Store() (*config.Store, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.store == nil {
+		store, err := config.NewStore()
+		if err != nil {
+			return nil, err
+		}
+		d.store = store
+	}
+	return d.store, nil
+}
+
+// KubernetesClient returns the shared client for kubeconfigPath, creating it on first use
+func (d *Dependencies) KubernetesClient(kubeconfigPath string) (*kubernetes.Client, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if client, ok := d.clients[kubeconfigPath]; ok {
+		return client, nil
+	}
+	client, err := kubernetes.NewClient(kubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+	d.clients[kubeconfigPath] = client
+	return client, nil
+}
@@ -0,0 +1,31 @@
+package application
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestDependencies_CachesInstances(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KUBECONFIG", "")
+	t.Setenv(kubernetes.EnvServer, "https://k8s.example.com")
+	t.Setenv(kubernetes.EnvToken, "secret")
+
+	deps := NewDependencies()
+
+	store1, err := deps.Store()
+	require.NoError(t, err)
+	store2, err := deps.Store()
+	require.NoError(t, err)
+	assert.Same(t, store1, store2)
+
+	client1, err := deps.KubernetesClient("")
+	require.NoError(t, err)
+	client2, err := deps.KubernetesClient("")
+	require.NoError(t, err)
+	assert.Same(t, client1, client2)
+}
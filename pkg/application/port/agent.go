@@ -6,9 +6,11 @@ import (
 
 // AgentExecutor abstracts coding agent operations for testing
 type AgentExecutor interface {
-	// TaskStart initiates a new coding task with the given prompt
+	// TaskStart runs a new coding task with the given prompt and returns when it finishes
+	// Its stdout and stderr are written to logPath in the pod, unless logPath is empty.
+	// A deadline on ctx stops the task, in the pod as well.
 	// Returns task ID and error
-	TaskStart(ctx context.Context, namespace, podName, prompt string) (taskID string, err error)
+	TaskStart(ctx context.Context, namespace, podName, prompt, logPath string) (taskID string, err error)
 
 	// Additional methods for future expansion:
 	// TaskStatus(ctx context.Context, taskID string) (*TaskStatus, error)
@@ -2,6 +2,7 @@ package port
 
 import (
 	"context"
+	"io"
 	"os/exec"
 	"time"
 
@@ -13,21 +14,56 @@ type KubernetesClient interface {
 	// Pod operations
 	CreatePod(ctx context.Context, spec *kubernetes.PodSpec) error
 	GetPod(ctx context.Context, name, namespace string) (*kubernetes.PodStatus, error)
+	ListSessionPods(ctx context.Context, namespace string) (map[string]*kubernetes.PodStatus, error)
 	WaitForPodReady(ctx context.Context, name, namespace string, timeout time.Duration) error
 	DeletePod(ctx context.Context, name, namespace string) error
 	WaitForPodDeleted(ctx context.Context, name, namespace string, timeout time.Duration) error
 	GetPodIP(ctx context.Context, name, namespace string) (string, error)
+	StreamPodLogs(ctx context.Context, name, namespace, container string, follow bool, tailLines int64) (io.ReadCloser, error)
+	GetContainerCrash(ctx context.Context, name, namespace string, logLines int64) (*kubernetes.ContainerCrash, error)
 
 	// Secret operations
-	CreateSecret(ctx context.Context, name, namespace string, data map[string]string) error
+	CreateSecret(ctx context.Context, name, namespace string, data, labels map[string]string) error
 	DeleteSecret(ctx context.Context, name, namespace string) error
 	SecretExists(ctx context.Context, name, namespace string) (bool, error)
-	CreateFileSecret(ctx context.Context, name, namespace string, files map[string][]byte) error
+	CreateFileSecret(ctx context.Context, name, namespace string, files map[string][]byte, labels map[string]string) error
+	GetSecretData(ctx context.Context, name, namespace string) (map[string]string, error)
+	UpdateSecretData(ctx context.Context, name, namespace string, data map[string]string) error
+	SetSecretsOwner(ctx context.Context, namespace, podName string, secretNames ...string) error
+
+	// ConfigMap operations
+	DeleteConfigMap(ctx context.Context, name, namespace string) error
+
+	// Session resource operations, for the Secrets and ConfigMaps of sessions
+	DeleteSessionResources(ctx context.Context, namespace, session string, tracked []kubernetes.SessionResource) ([]kubernetes.SessionResource, error)
+	ListStaleSessionResources(ctx context.Context, namespace string, minAge time.Duration) ([]kubernetes.SessionResource, error)
+	DeleteSessionResource(ctx context.Context, resource kubernetes.SessionResource) error
+
+	// Exec operations
+	ExecInPod(ctx context.Context, namespace, podName string, command []string) (stdout, stderr string, err error)
+
+	// Reaper operations
+	InstallReaper(ctx context.Context, opts kubernetes.ReaperOptions) error
+
+	// Dependency cache operations
+	EnsureCachePVC(ctx context.Context, opts kubernetes.CacheWarmOptions) (created bool, err error)
+	RunCacheWarmJob(ctx context.Context, opts kubernetes.CacheWarmOptions, timeout time.Duration) (jobName string, err error)
+
+	// Image operations
+	PrepullImage(ctx context.Context, opts kubernetes.PrepullOptions, timeout time.Duration) (*kubernetes.PrepullResult, error)
+
+	// Quota operations
+	GetQuotaReport(ctx context.Context, namespace string) (*kubernetes.QuotaReport, error)
+
+	// Session lock operations
+	AcquireSessionLock(ctx context.Context, namespace, podName, holder string, ttl time.Duration, steal bool) (*kubernetes.SessionLock, error)
+	ReleaseSessionLock(ctx context.Context, namespace, podName, holder string) error
 
 	// Port forwarding
-	StartPortForward(ctx context.Context, podName string, localPort, remotePort int) (*exec.Cmd, error)
+	StartPortForward(ctx context.Context, namespace, podName string, localPort, remotePort int) (*exec.Cmd, error)
 
 	// Utility operations
 	GetCurrentNamespace() (string, error)
+	GetCurrentContext() string
 	Ping(ctx context.Context) error
 }
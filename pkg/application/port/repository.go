@@ -1,6 +1,7 @@
 package port
 
 import (
+	"github.com/illumination-k/kodama/pkg/agent/auth"
 	"github.com/illumination-k/kodama/pkg/config"
 )
 
@@ -18,11 +19,34 @@ type SessionRepository interface {
 	// ListSessions returns all session configurations
 	ListSessions() ([]*config.SessionConfig, error)
 
+	// QuerySessions returns the sessions matching query, in query order
+	QuerySessions(query config.SessionQuery) ([]*config.SessionConfig, error)
+
 	// SessionExists checks if a session exists
 	SessionExists(name string) bool
 
-	// GetSessionPath returns the file path for a session config
-	GetSessionPath(name string) string
+	// GetSessionPath returns the file path for a session config, rejecting invalid names
+	GetSessionPath(name string) (string, error)
+
+	// MigrateToEncrypted rewrites existing plaintext files in encrypted form
+	// Returns the number of files migrated
+	MigrateToEncrypted() (int, error)
+
+	// CurrentSession returns the session selected with 'kodama use', or "" if none is set
+	CurrentSession() (string, error)
+
+	// SetCurrentSession records the current session; an empty name clears it
+	SetCurrentSession(name string) error
+
+	// RecordEvent appends an event to a session's timeline
+	RecordEvent(name string, event config.SessionEvent) error
+
+	// LoadEvents returns a session's timeline, oldest first
+	LoadEvents(name string) ([]config.SessionEvent, error)
+
+	// PruneOrphanedSessionDirs removes the local directories of sessions whose
+	// config is gone and returns them; with dryRun they are only returned
+	PruneOrphanedSessionDirs(dryRun bool) ([]string, error)
 }
 
 // ConfigRepository handles persistence of global configuration
@@ -33,12 +57,24 @@ type ConfigRepository interface {
 	// SaveGlobalConfig saves the global configuration
 	SaveGlobalConfig(config *config.GlobalConfig) error
 
-	// LoadSessionTemplate loads a session template from an arbitrary path
-	LoadSessionTemplate(path string) (*config.SessionConfig, error)
+	// LoadSessionTemplate loads a session template from an arbitrary path,
+	// rendering template expressions with the given values
+	LoadSessionTemplate(path string, values map[string]interface{}) (*config.SessionConfig, error)
+
+	// LoadSessionTemplates loads a session template and the templates it
+	// extends, base first
+	LoadSessionTemplates(path string, values map[string]interface{}) ([]*config.SessionConfig, error)
 
 	// EnsureConfigDir creates the configuration directory structure if it doesn't exist
 	EnsureConfigDir() error
 
 	// GetGlobalConfigPath returns the file path for global config
 	GetGlobalConfigPath() string
+
+	// MigrateSchema rewrites the global config and session files written with
+	// an older schema version; with dryRun, files are only checked
+	MigrateSchema(dryRun bool) ([]config.SchemaMigration, error)
+
+	// SaveAuthProfile stores a coding agent auth profile in the auth cache
+	SaveAuthProfile(name string, profile auth.Profile) error
 }
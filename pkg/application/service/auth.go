@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/agent/auth"
+)
+
+// AuthStatus checks every auth provider the coding agent would try, in order,
+// reporting which one is active, when its credentials expire, and whether a
+// due refresh failed
+func (s *SessionService) AuthStatus(ctx context.Context) ([]auth.ProviderStatus, error) {
+	globalConfig, err := s.configRepo.LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	providers, err := globalConfig.ClaudeAuth.Providers()
+	if err != nil {
+		return nil, err
+	}
+	return auth.CheckProviders(ctx, providers), nil
+}
+
+// LoginOptions configures a device login
+type LoginOptions struct {
+	Profile string                // Auth cache profile to store the token in (default: claudeAuth.profile or "default")
+	Flow    auth.DeviceFlowConfig // Overrides claudeAuth.login field by field
+}
+
+// LoginResult describes a finished device login
+type LoginResult struct {
+	Profile         string
+	ExpiresAt       *time.Time
+	HasRefreshToken bool
+}
+
+// Login runs the OAuth device flow against the configured identity provider.
+// prompt is called with the code the user enters at the verification URL;
+// the resulting access and refresh tokens are stored in the auth cache, where
+// the file provider refreshes them.
+func (s *SessionService) Login(ctx context.Context, opts LoginOptions, prompt func(*auth.DeviceAuthorization)) (*LoginResult, error) {
+	globalConfig, err := s.configRepo.LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	flow := globalConfig.ClaudeAuth.Login.DeviceFlow()
+	if opts.Flow.DeviceAuthURL != "" {
+		flow.DeviceAuthURL = opts.Flow.DeviceAuthURL
+	}
+	if opts.Flow.TokenURL != "" {
+		flow.TokenURL = opts.Flow.TokenURL
+	}
+	if opts.Flow.ClientID != "" {
+		flow.ClientID = opts.Flow.ClientID
+	}
+	if len(opts.Flow.Scopes) > 0 {
+		flow.Scopes = opts.Flow.Scopes
+	}
+	if err := flow.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: set claudeAuth.login in the global config or pass the flags", err)
+	}
+
+	profileName := opts.Profile
+	if profileName == "" {
+		profileName = globalConfig.ClaudeAuth.Profile
+	}
+	if profileName == "" {
+		profileName = "default"
+	}
+
+	device, err := auth.RequestDeviceCode(ctx, flow)
+	if err != nil {
+		return nil, err
+	}
+	if prompt != nil {
+		prompt(device)
+	}
+	token, err := auth.PollDeviceToken(ctx, flow, device)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := auth.Profile{
+		Token:        token.AccessToken,
+		RefreshURL:   flow.TokenURL,
+		RefreshToken: token.RefreshToken,
+		ClientID:     flow.ClientID,
+	}
+	expiresAt := token.ExpiresAt(time.Now())
+	if expiresAt != nil {
+		profile.ExpiresAt = expiresAt.UTC().Format(time.RFC3339)
+	}
+	if err := s.configRepo.SaveAuthProfile(profileName, profile); err != nil {
+		return nil, err
+	}
+
+	return &LoginResult{
+		Profile:         profileName,
+		ExpiresAt:       expiresAt,
+		HasRefreshToken: token.RefreshToken != "",
+	}, nil
+}
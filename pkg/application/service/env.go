@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/env"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// ListEnv returns the environment variables stored in the session's env secret
+func (s *SessionService) ListEnv(ctx context.Context, name string) (map[string]string, error) {
+	session, err := s.sessionRepo.LoadSession(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if !session.Env.SecretCreated || session.Env.SecretName == "" {
+		return map[string]string{}, nil
+	}
+
+	return s.k8sClient.GetSecretData(ctx, session.Env.SecretName, session.Namespace)
+}
+
+// SetEnv adds or updates environment variables in the session's env secret
+// The secret is created if the session was started without one
+func (s *SessionService) SetEnv(ctx context.Context, name string, vars map[string]string) (*config.SessionConfig, error) {
+	for key := range vars {
+		if err := env.ValidateVarName(key); err != nil {
+			return nil, fmt.Errorf("invalid variable name '%s': %w", key, err)
+		}
+		if env.IsSystemVar(key) {
+			return nil, fmt.Errorf("cannot set system variable '%s'", key)
+		}
+	}
+
+	return s.updateEnv(ctx, name, func(session *config.SessionConfig, data map[string]string) {
+		for key, value := range vars {
+			data[key] = value
+			session.Env.UnsetVars = removeString(session.Env.UnsetVars, key)
+		}
+	})
+}
+
+// UnsetEnv removes environment variables from the session's env secret
+func (s *SessionService) UnsetEnv(ctx context.Context, name string, keys []string) (*config.SessionConfig, error) {
+	for _, key := range keys {
+		if err := env.ValidateVarName(key); err != nil {
+			return nil, fmt.Errorf("invalid variable name '%s': %w", key, err)
+		}
+	}
+
+	return s.updateEnv(ctx, name, func(session *config.SessionConfig, data map[string]string) {
+		for _, key := range keys {
+			delete(data, key)
+			if !containsString(session.Env.UnsetVars, key) {
+				session.Env.UnsetVars = append(session.Env.UnsetVars, key)
+			}
+		}
+	})
+}
+
+// WriteEnvFile writes the session's current variables to the runtime env file
+// in the pod, so new shells pick up changes without recreating the pod
+func (s *SessionService) WriteEnvFile(ctx context.Context, session *config.SessionConfig) error {
+	podStatus, err := s.k8sClient.GetPod(ctx, session.PodName, session.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+	if podStatus.Phase != corev1.PodRunning {
+		return fmt.Errorf("pod '%s' is not running (phase: %s)", session.PodName, podStatus.Phase)
+	}
+
+	vars := map[string]string{}
+	if session.Env.SecretCreated && session.Env.SecretName != "" {
+		vars, err = s.k8sClient.GetSecretData(ctx, session.Env.SecretName, session.Namespace)
+		if err != nil {
+			return err
+		}
+	}
+
+	content := env.RenderShellFile(vars, session.Env.UnsetVars)
+	_, stderr, err := s.k8sClient.ExecInPod(ctx, session.Namespace, session.PodName, env.InstallShellFileCommand(content))
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %s: %w", env.ShellFilePath, strings.TrimSpace(stderr), err)
+	}
+
+	return nil
+}
+
+// updateEnv applies mutate to the session's env secret data and persists the result
+func (s *SessionService) updateEnv(
+	ctx context.Context,
+	name string,
+	mutate func(session *config.SessionConfig, data map[string]string),
+) (*config.SessionConfig, error) {
+	session, err := s.sessionRepo.LoadSession(name)
+	if err != nil {
+		return nil, err
+	}
+
+	secretName := session.Env.SecretName
+	if secretName == "" {
+		secretName = fmt.Sprintf("kodama-env-%s", session.Name)
+	}
+
+	exists, err := s.k8sClient.SecretExists(ctx, secretName, session.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]string{}
+	if exists {
+		data, err = s.k8sClient.GetSecretData(ctx, secretName, session.Namespace)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mutate(session, data)
+
+	if err := env.ValidateSecretSize(data); err != nil {
+		return nil, err
+	}
+
+	if exists {
+		err = s.k8sClient.UpdateSecretData(ctx, secretName, session.Namespace, data)
+	} else {
+		err = s.k8sClient.CreateSecret(ctx, secretName, session.Namespace, data, session.Labels)
+		if err == nil {
+			// Deleted with the pod, like the secrets created by start
+			err = s.k8sClient.SetSecretsOwner(ctx, session.Namespace, session.PodName, secretName)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session.Env.SecretName = secretName
+	session.Env.SecretCreated = true
+	session.TrackResource(kubernetes.KindSecret, secretName)
+	if err := s.sessionRepo.SaveSession(session); err != nil {
+		return nil, fmt.Errorf("failed to save session: %w", err)
+	}
+
+	return session, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func removeString(list []string, s string) []string {
+	result := list[:0]
+	for _, v := range list {
+		if v != s {
+			result = append(result, v)
+		}
+	}
+	return result
+}
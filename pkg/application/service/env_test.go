@@ -0,0 +1,19 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnsetEnv_InvalidName(t *testing.T) {
+	svc, repo, _ := newAgentTestService(&fakeLocker{})
+
+	_, err := svc.UnsetEnv(context.Background(), "work", []string{"TOKEN; rm -rf /"})
+	require.Error(t, err)
+
+	assert.Contains(t, err.Error(), "invalid variable name")
+	assert.Zero(t, repo.saved)
+}
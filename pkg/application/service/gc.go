@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// DefaultGCMinAge is how old a session Secret or ConfigMap must be before gc
+// removes it, so sessions that are still starting keep theirs
+const DefaultGCMinAge = 10 * time.Minute
+
+// GCOptions contains options for CollectGarbage
+type GCOptions struct {
+	// Cluster also sweeps session Secrets and ConfigMaps whose pod is gone
+	Cluster   bool
+	Namespace string // Namespace to sweep ("" = all namespaces)
+	MinAge    time.Duration
+	DryRun    bool // Only report what would be removed
+}
+
+// GCResult lists what CollectGarbage removed, or would remove with DryRun
+type GCResult struct {
+	LocalDirs []string
+	Resources []kubernetes.SessionResource
+}
+
+// CollectGarbage removes the local directories left by deleted sessions and,
+// with opts.Cluster, the session Secrets and ConfigMaps whose pod is gone.
+// Resources are judged by their pod rather than the local store, so sessions
+// of other users in a shared namespace are left alone.
+func (s *SessionService) CollectGarbage(ctx context.Context, opts GCOptions) (*GCResult, error) {
+	result := &GCResult{}
+
+	dirs, err := s.sessionRepo.PruneOrphanedSessionDirs(opts.DryRun)
+	if err != nil {
+		return nil, err
+	}
+	result.LocalDirs = dirs
+
+	if !opts.Cluster {
+		return result, nil
+	}
+
+	stale, err := s.k8sClient.ListStaleSessionResources(ctx, opts.Namespace, opts.MinAge)
+	if err != nil {
+		return result, err
+	}
+	for _, resource := range stale {
+		if !opts.DryRun {
+			if err := s.k8sClient.DeleteSessionResource(ctx, resource); err != nil {
+				return result, fmt.Errorf("failed to delete %s: %w", resource, err)
+			}
+		}
+		result.Resources = append(result.Resources, resource)
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/application/port"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+type fakeGCRepo struct {
+	port.SessionRepository
+	dryRun bool
+}
+
+func (f *fakeGCRepo) PruneOrphanedSessionDirs(dryRun bool) ([]string, error) {
+	f.dryRun = dryRun
+	return []string{"/home/me/.kodama/sessions/gone"}, nil
+}
+
+type fakeGCCluster struct {
+	port.KubernetesClient
+	namespace string
+	minAge    time.Duration
+	deleted   []string
+}
+
+func (f *fakeGCCluster) ListStaleSessionResources(ctx context.Context, namespace string, minAge time.Duration) ([]kubernetes.SessionResource, error) {
+	f.namespace, f.minAge = namespace, minAge
+	return []kubernetes.SessionResource{{Kind: kubernetes.KindSecret, Name: "kodama-env-gone", Namespace: "dev", Session: "gone"}}, nil
+}
+
+func (f *fakeGCCluster) DeleteSessionResource(ctx context.Context, resource kubernetes.SessionResource) error {
+	f.deleted = append(f.deleted, resource.Name)
+	return nil
+}
+
+func TestCollectGarbage(t *testing.T) {
+	repo, cluster := &fakeGCRepo{}, &fakeGCCluster{}
+	svc := NewSessionService(repo, nil, cluster, nil, nil)
+
+	// Local only
+	result, err := svc.CollectGarbage(context.Background(), GCOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/home/me/.kodama/sessions/gone"}, result.LocalDirs)
+	assert.Empty(t, result.Resources)
+	assert.Empty(t, cluster.namespace)
+
+	// A dry run reports without deleting
+	result, err = svc.CollectGarbage(context.Background(), GCOptions{Cluster: true, Namespace: "dev", MinAge: time.Hour, DryRun: true})
+	require.NoError(t, err)
+	assert.True(t, repo.dryRun)
+	assert.Len(t, result.Resources, 1)
+	assert.Equal(t, "dev", cluster.namespace)
+	assert.Equal(t, time.Hour, cluster.minAge)
+	assert.Empty(t, cluster.deleted)
+
+	result, err = svc.CollectGarbage(context.Background(), GCOptions{Cluster: true, Namespace: "dev"})
+	require.NoError(t, err)
+	assert.Len(t, result.Resources, 1)
+	assert.Equal(t, []string{"kodama-env-gone"}, cluster.deleted)
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/container"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// ListConcurrency bounds the API and sync calls made concurrently while listing sessions
+const ListConcurrency = 8
+
+// containerRunning reports whether a local session's container is running
+// Tests replace it to avoid running docker.
+var containerRunning = func(ctx context.Context, runtime, name string) (bool, error) {
+	return container.Engine(runtime).Running(ctx, name)
+}
+
+// SessionStatus pairs a session with the status of its pod
+type SessionStatus struct {
+	Session     *config.SessionConfig
+	Pod         *kubernetes.PodStatus // nil when PodErr is set
+	PodErr      error
+	Crash       *kubernetes.ContainerCrash // Last termination of the main container (GetSessionStatus only)
+	Watchdog    *kubernetes.WatchdogStatus // Heartbeat and restarts of the watchdog (GetSessionStatus only)
+	WatchdogErr error                      // Why the watchdog status could not be read
+}
+
+// ListSessionStatuses loads the sessions matching query and fetches their pods
+// with one List call per namespace instead of a Get per session. Sessions on a
+// local container runtime get the status of their container instead.
+func (s *SessionService) ListSessionStatuses(ctx context.Context, query config.SessionQuery) ([]SessionStatus, error) {
+	sessions, err := s.sessionRepo.QuerySessions(query)
+	if err != nil {
+		return nil, err
+	}
+
+	var namespaces []string
+	seen := make(map[string]bool)
+	for _, session := range sessions {
+		if session.IsLocalRuntime() {
+			continue
+		}
+		if !seen[session.Namespace] {
+			seen[session.Namespace] = true
+			namespaces = append(namespaces, session.Namespace)
+		}
+	}
+
+	var (
+		mu     sync.Mutex
+		pods   = make(map[string]map[string]*kubernetes.PodStatus, len(namespaces))
+		nsErrs = make(map[string]error)
+		local  = make(map[string]*kubernetes.PodStatus)
+		lErrs  = make(map[string]error)
+	)
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(ListConcurrency)
+	for _, namespace := range namespaces {
+		g.Go(func() error {
+			nsPods, err := s.k8sClient.ListSessionPods(gctx, namespace)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				// Report per session rather than failing the whole listing
+				nsErrs[namespace] = err
+			} else {
+				pods[namespace] = nsPods
+			}
+			return nil
+		})
+	}
+	for _, session := range sessions {
+		if !session.IsLocalRuntime() {
+			continue
+		}
+		g.Go(func() error {
+			running, err := containerRunning(gctx, session.Runtime, session.PodName)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				lErrs[session.Name] = err
+			case running:
+				local[session.Name] = &kubernetes.PodStatus{Phase: corev1.PodRunning, Ready: true}
+			default:
+				lErrs[session.Name] = fmt.Errorf("%w: container %s is not running", kubernetes.ErrPodNotFound, session.PodName)
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	statuses := make([]SessionStatus, 0, len(sessions))
+	for _, session := range sessions {
+		status := SessionStatus{Session: session}
+		if session.IsLocalRuntime() {
+			status.Pod, status.PodErr = local[session.Name], lErrs[session.Name]
+			statuses = append(statuses, status)
+			continue
+		}
+		switch pod, ok := pods[session.Namespace][session.PodName]; {
+		case nsErrs[session.Namespace] != nil:
+			status.PodErr = nsErrs[session.Namespace]
+		case ok:
+			status.Pod = pod
+		default:
+			status.PodErr = fmt.Errorf("%w: %s in namespace %s", kubernetes.ErrPodNotFound, session.PodName, session.Namespace)
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
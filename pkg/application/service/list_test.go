@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/application/port"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+type fakeSessionRepo struct {
+	port.SessionRepository
+	sessions []*config.SessionConfig
+}
+
+func (f *fakeSessionRepo) ListSessions() ([]*config.SessionConfig, error) {
+	return f.sessions, nil
+}
+
+func (f *fakeSessionRepo) QuerySessions(query config.SessionQuery) ([]*config.SessionConfig, error) {
+	return query.Apply(f.sessions), nil
+}
+
+type fakePodLister struct {
+	port.KubernetesClient
+	calls atomic.Int32
+	pods  map[string]map[string]*kubernetes.PodStatus
+	errs  map[string]error
+}
+
+func (f *fakePodLister) ListSessionPods(ctx context.Context, namespace string) (map[string]*kubernetes.PodStatus, error) {
+	f.calls.Add(1)
+	if err := f.errs[namespace]; err != nil {
+		return nil, err
+	}
+	return f.pods[namespace], nil
+}
+
+func TestListSessionStatuses(t *testing.T) {
+	repo := &fakeSessionRepo{sessions: []*config.SessionConfig{
+		{Name: "a", Namespace: "dev", PodName: "kodama-a"},
+		{Name: "b", Namespace: "dev", PodName: "kodama-b"},
+		{Name: "c", Namespace: "dev", PodName: "kodama-c"},
+		{Name: "d", Namespace: "broken", PodName: "kodama-d"},
+	}}
+	k8s := &fakePodLister{
+		pods: map[string]map[string]*kubernetes.PodStatus{
+			"dev": {
+				"kodama-a": {Ready: true},
+				"kodama-b": {Ready: false},
+			},
+		},
+		errs: map[string]error{"broken": errors.New("forbidden")},
+	}
+	svc := NewSessionService(repo, nil, k8s, nil, nil)
+
+	statuses, err := svc.ListSessionStatuses(context.Background(), config.SessionQuery{})
+	require.NoError(t, err)
+	require.Len(t, statuses, 4)
+
+	// One List call per namespace, not per session
+	assert.Equal(t, int32(2), k8s.calls.Load())
+
+	assert.True(t, statuses[0].Pod.Ready)
+	assert.NoError(t, statuses[0].PodErr)
+	assert.False(t, statuses[1].Pod.Ready)
+	assert.ErrorIs(t, statuses[2].PodErr, kubernetes.ErrPodNotFound)
+	assert.Nil(t, statuses[2].Pod)
+	assert.EqualError(t, statuses[3].PodErr, "forbidden")
+}
+
+func TestListSessionStatuses_LocalRuntime(t *testing.T) {
+	orig := containerRunning
+	t.Cleanup(func() { containerRunning = orig })
+	containerRunning = func(ctx context.Context, runtime, name string) (bool, error) {
+		return name == "kodama-up", nil
+	}
+
+	repo := &fakeSessionRepo{sessions: []*config.SessionConfig{
+		{Name: "up", Runtime: config.RuntimeDocker, PodName: "kodama-up"},
+		{Name: "down", Runtime: config.RuntimePodman, PodName: "kodama-down"},
+	}}
+	k8s := &fakePodLister{}
+	svc := NewSessionService(repo, nil, k8s, nil, nil)
+
+	statuses, err := svc.ListSessionStatuses(context.Background(), config.SessionQuery{})
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+
+	assert.Zero(t, k8s.calls.Load(), "local sessions need no cluster")
+	// Sorted by name
+	assert.ErrorIs(t, statuses[0].PodErr, kubernetes.ErrPodNotFound)
+	assert.True(t, statuses[1].Pod.Ready)
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+// UsageReportOptions selects the sessions of a usage report and how they are grouped
+type UsageReportOptions struct {
+	By        []string  // Label keys to group by (default: requiredLabels of the global config)
+	Namespace string    // Only sessions in this namespace; "" for all
+	Since     time.Time // Runtime before Since is not counted; zero for no bound
+	Until     time.Time // Runtime after Until is not counted; zero for now
+}
+
+// UsageRow is the runtime of the sessions sharing one combination of label values
+type UsageRow struct {
+	Labels         []string // Values of the report's label keys, "" when a session does not set one
+	Sessions       int
+	Hours          float64 // Wall-clock runtime
+	CPUHours       float64 // Runtime weighted by the CPU limit
+	MemoryGiBHours float64 // Runtime weighted by the memory limit
+}
+
+// UsageReport is the runtime of stored sessions aggregated by label
+type UsageReport struct {
+	By   []string
+	Rows []UsageRow // Sorted by label values
+}
+
+// UsageReport aggregates the runtime of the stored sessions by label values
+// A session runs from its creation until now while it is active, or until its
+// last status change once it is stopped or failed. Sessions deleted without
+// --keep-config are no longer in the store and are not counted.
+func (s *SessionService) UsageReport(opts UsageReportOptions) (*UsageReport, error) {
+	by := opts.By
+	if len(by) == 0 {
+		globalConfig, err := s.configRepo.LoadGlobalConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load global config: %w", err)
+		}
+		by = globalConfig.RequiredLabels
+	}
+	if len(by) == 0 {
+		return nil, fmt.Errorf("no label keys to group by: pass --by or set requiredLabels in the global config")
+	}
+
+	sessions, err := s.sessionRepo.QuerySessions(config.SessionQuery{Namespace: opts.Namespace})
+	if err != nil {
+		return nil, err
+	}
+	return BuildUsageReport(sessions, by, opts.Since, cmp.Or(opts.Until, time.Now())), nil
+}
+
+// BuildUsageReport aggregates the runtime of sessions between since and until by the label keys in by
+func BuildUsageReport(sessions []*config.SessionConfig, by []string, since, until time.Time) *UsageReport {
+	rows := make(map[string]*UsageRow)
+	for _, session := range sessions {
+		hours := sessionRuntime(session, since, until).Hours()
+		if hours <= 0 {
+			continue
+		}
+
+		values := make([]string, len(by))
+		for i, key := range by {
+			values[i] = session.Labels[key]
+		}
+		key := strings.Join(values, "\x00")
+		row, ok := rows[key]
+		if !ok {
+			row = &UsageRow{Labels: values}
+			rows[key] = row
+		}
+
+		row.Sessions++
+		row.Hours += hours
+		if cpu, err := resource.ParseQuantity(session.Resources.CPU); err == nil {
+			row.CPUHours += cpu.AsApproximateFloat64() * hours
+		}
+		if memory, err := resource.ParseQuantity(session.Resources.Memory); err == nil {
+			row.MemoryGiBHours += memory.AsApproximateFloat64() / (1 << 30) * hours
+		}
+	}
+
+	report := &UsageReport{By: by}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	slices.SortFunc(report.Rows, func(a, b UsageRow) int {
+		return slices.Compare(a.Labels, b.Labels)
+	})
+	return report
+}
+
+// sessionRuntime returns how long session ran between since and until
+func sessionRuntime(session *config.SessionConfig, since, until time.Time) time.Duration {
+	start, end := session.CreatedAt, until
+	switch session.Status {
+	case config.StatusStopped, config.StatusFailed:
+		end = session.UpdatedAt
+	}
+	if start.Before(since) {
+		start = since
+	}
+	if end.After(until) {
+		end = until
+	}
+	return end.Sub(start)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/application/port"
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+type fakeConfigRepo struct {
+	port.ConfigRepository
+	global *config.GlobalConfig
+}
+
+func (f *fakeConfigRepo) LoadGlobalConfig() (*config.GlobalConfig, error) {
+	return f.global, nil
+}
+
+func TestBuildUsageReport(t *testing.T) {
+	day := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	sessions := []*config.SessionConfig{
+		{
+			Name:      "running",
+			Status:    config.StatusRunning,
+			CreatedAt: day.Add(-2 * time.Hour), // Only counted from since
+			Labels:    map[string]string{"team": "payments", "cost-center": "cc-1"},
+			Resources: config.ResourceConfig{CPU: "2", Memory: "4Gi"},
+		},
+		{
+			Name:      "stopped",
+			Status:    config.StatusStopped,
+			CreatedAt: day.Add(time.Hour),
+			UpdatedAt: day.Add(4 * time.Hour),
+			Labels:    map[string]string{"team": "payments", "cost-center": "cc-1"},
+			Resources: config.ResourceConfig{CPU: "500m", Memory: "1Gi"},
+		},
+		{
+			Name:      "unlabeled",
+			Status:    config.StatusRunning,
+			CreatedAt: day,
+		},
+		{
+			Name:      "before",
+			Status:    config.StatusStopped,
+			CreatedAt: day.Add(-5 * time.Hour),
+			UpdatedAt: day.Add(-time.Hour),
+			Labels:    map[string]string{"team": "search"},
+		},
+	}
+
+	report := BuildUsageReport(sessions, []string{"team", "cost-center"}, day, day.Add(10*time.Hour))
+
+	require.Len(t, report.Rows, 2)
+	assert.Equal(t, UsageRow{Labels: []string{"", ""}, Sessions: 1, Hours: 10}, report.Rows[0])
+	payments := report.Rows[1]
+	assert.Equal(t, []string{"payments", "cc-1"}, payments.Labels)
+	assert.Equal(t, 2, payments.Sessions)
+	assert.InDelta(t, 13, payments.Hours, 1e-9)
+	assert.InDelta(t, 2*10+0.5*3, payments.CPUHours, 1e-9)
+	assert.InDelta(t, 4*10+1*3, payments.MemoryGiBHours, 1e-9)
+}
+
+func TestUsageReport_RequiresLabelKeys(t *testing.T) {
+	svc := &SessionService{
+		sessionRepo: &fakeSessionRepo{},
+		configRepo:  &fakeConfigRepo{global: config.DefaultGlobalConfig()},
+	}
+
+	_, err := svc.UsageReport(UsageReportOptions{})
+	assert.ErrorContains(t, err, "no label keys to group by")
+
+	svc.configRepo = &fakeConfigRepo{global: &config.GlobalConfig{RequiredLabels: []string{"team"}}}
+	report, err := svc.UsageReport(UsageReportOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team"}, report.By)
+}
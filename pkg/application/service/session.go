@@ -2,7 +2,12 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
 
+	"github.com/illumination-k/kodama/pkg/agent"
 	"github.com/illumination-k/kodama/pkg/application/port"
 	"github.com/illumination-k/kodama/pkg/config"
 	"github.com/illumination-k/kodama/pkg/kubernetes"
@@ -109,3 +114,239 @@ func (s *SessionService) GetPod(ctx context.Context, name, namespace string) (*k
 	// Need to import kubernetes package
 	return s.k8sClient.GetPod(ctx, name, namespace)
 }
+
+// EncryptStore migrates existing session files to encrypted form and enables
+// encryption at rest for future writes
+func (s *SessionService) EncryptStore() (int, error) {
+	migrated, err := s.sessionRepo.MigrateToEncrypted()
+	if err != nil {
+		return migrated, fmt.Errorf("failed to migrate session files: %w", err)
+	}
+
+	globalConfig, err := s.configRepo.LoadGlobalConfig()
+	if err != nil {
+		return migrated, fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	globalConfig.Store.Encrypt = true
+	if err := s.configRepo.SaveGlobalConfig(globalConfig); err != nil {
+		return migrated, fmt.Errorf("failed to save global config: %w", err)
+	}
+
+	return migrated, nil
+}
+
+// UseSession makes name the current session, used when commands are run without a name
+// An empty name clears the current session.
+func (s *SessionService) UseSession(name string) error {
+	if name != "" && !s.sessionRepo.SessionExists(name) {
+		return fmt.Errorf("%w: %s", config.ErrSessionNotFound, name)
+	}
+	return s.sessionRepo.SetCurrentSession(name)
+}
+
+// CurrentSession returns the session selected with UseSession, or "" if none is set
+func (s *SessionService) CurrentSession() (string, error) {
+	return s.sessionRepo.CurrentSession()
+}
+
+// MigrateConfig upgrades the global config and session files to the current schema version
+func (s *SessionService) MigrateConfig(dryRun bool) ([]config.SchemaMigration, error) {
+	return s.configRepo.MigrateSchema(dryRun)
+}
+
+// contextGlobalConfig loads the global config with the defaults for the
+// current kubeconfig context applied
+func (s *SessionService) contextGlobalConfig() (*config.GlobalConfig, error) {
+	globalConfig, err := s.configRepo.LoadGlobalConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global config: %w", err)
+	}
+	return globalConfig.ForContext(s.k8sClient.GetCurrentContext()), nil
+}
+
+// ResolveNamespace returns namespace if set, otherwise the global default for
+// the current context, falling back to the current kubeconfig namespace
+func (s *SessionService) ResolveNamespace(namespace string) (string, error) {
+	if namespace != "" {
+		return namespace, nil
+	}
+
+	globalConfig, err := s.contextGlobalConfig()
+	if err != nil {
+		return "", err
+	}
+	if globalConfig.Defaults.Namespace != "" {
+		return globalConfig.Defaults.Namespace, nil
+	}
+
+	namespace, err = s.k8sClient.GetCurrentNamespace()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine namespace: %w", err)
+	}
+	return namespace, nil
+}
+
+// InstallReaper deploys the CronJob that deletes expired sessions in a namespace
+func (s *SessionService) InstallReaper(ctx context.Context, opts kubernetes.ReaperOptions) error {
+	if err := s.k8sClient.InstallReaper(ctx, opts); err != nil {
+		return fmt.Errorf("failed to install reaper: %w", err)
+	}
+	return nil
+}
+
+// ResolveCacheOptions fills the cache PVC and image from the global config when unset
+func (s *SessionService) ResolveCacheOptions(opts *kubernetes.CacheWarmOptions) error {
+	globalConfig, err := s.configRepo.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	opts.PVC = config.CoalesceString(opts.PVC, config.CoalesceString(globalConfig.Defaults.Cache.PVC, kubernetes.DefaultCachePVC))
+	opts.Image, err = s.ResolveImage(opts.Image)
+	return err
+}
+
+// ResolveImage returns the given image or the default session image from
+// global config for the current context
+func (s *SessionService) ResolveImage(image string) (string, error) {
+	if image != "" {
+		return image, nil
+	}
+
+	globalConfig, err := s.contextGlobalConfig()
+	if err != nil {
+		return "", err
+	}
+	if globalConfig.Defaults.Image == "" {
+		return "", fmt.Errorf("no image configured; pass --image")
+	}
+	return globalConfig.Defaults.Image, nil
+}
+
+// PrepullImage pulls an image onto every node ahead of the first session
+func (s *SessionService) PrepullImage(ctx context.Context, opts kubernetes.PrepullOptions, timeout time.Duration) (*kubernetes.PrepullResult, error) {
+	result, err := s.k8sClient.PrepullImage(ctx, opts, timeout)
+	if err != nil {
+		return result, fmt.Errorf("failed to prepull image %s: %w", opts.Image, err)
+	}
+	return result, nil
+}
+
+// QuotaReport compares the kodama pods in namespace with its ResourceQuotas
+func (s *SessionService) QuotaReport(ctx context.Context, namespace string) (*kubernetes.QuotaReport, error) {
+	return s.k8sClient.GetQuotaReport(ctx, namespace)
+}
+
+// EnsureCachePVC creates the shared dependency cache PVC if it does not exist
+func (s *SessionService) EnsureCachePVC(ctx context.Context, opts kubernetes.CacheWarmOptions) (bool, error) {
+	return s.k8sClient.EnsureCachePVC(ctx, opts)
+}
+
+// WarmCache runs a Job that populates the shared dependency cache
+func (s *SessionService) WarmCache(ctx context.Context, opts kubernetes.CacheWarmOptions, timeout time.Duration) (string, error) {
+	return s.k8sClient.RunCacheWarmJob(ctx, opts, timeout)
+}
+
+// DeleteSession removes a session's sync, secrets, pod and config without prompting
+// Kubernetes cleanup is best effort; the config is removed even if the pod is already gone.
+func (s *SessionService) DeleteSession(ctx context.Context, name string) error {
+	session, err := s.sessionRepo.LoadSession(name)
+	if err != nil {
+		return err
+	}
+
+	if session.Sync.Enabled && session.Sync.MutagenSession != "" {
+		_ = s.syncMgr.Stop(ctx, session.Sync.MutagenSession)
+	}
+	if _, err := s.k8sClient.DeleteSessionResources(ctx, session.Namespace, session.Name, session.TrackedResources()); err != nil {
+		return fmt.Errorf("failed to delete session secrets and config maps: %w", err)
+	}
+	if err := s.k8sClient.DeletePod(ctx, session.PodName, session.Namespace); err != nil {
+		return fmt.Errorf("failed to delete pod: %w", err)
+	}
+
+	return s.sessionRepo.DeleteSession(name)
+}
+
+// RunAgent runs a coding agent task in a running session and records the execution
+// The session lock is held and renewed until the task finishes, so other users
+// and concurrent runs cannot attach or start another task meanwhile.
+func (s *SessionService) RunAgent(ctx context.Context, name, prompt string) (*config.SessionConfig, error) {
+	return s.RunAgentWithOptions(ctx, name, prompt, config.AgentRunOptions{})
+}
+
+// RunAgentWithOptions is RunAgent with options, e.g. to exceed the session's agent budget
+// or to take over the session lock
+// A task refused for the budget returns config.ErrAgentBudgetExceeded and is not recorded.
+func (s *SessionService) RunAgentWithOptions(ctx context.Context, name, prompt string, opts config.AgentRunOptions) (*config.SessionConfig, error) {
+	session, err := s.sessionRepo.LoadSession(name)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := kubernetes.HoldSessionLock(ctx, s.k8sClient, session.Namespace, session.PodName, kubernetes.LockHolder(), kubernetes.DefaultLockTTL, opts.StealLock, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// Record HEAD first so changes the agent commits are reviewed as well
+	if opts.BaseCommit == "" {
+		if base, err := agent.HeadCommit(ctx, s.k8sClient, session.Namespace, session.PodName); err == nil {
+			opts.BaseCommit = base
+		}
+	}
+
+	// The timeline is a debugging aid, so failing to record it does not fail the task
+	_ = s.sessionRepo.RecordEvent(name, config.NewSessionEvent(config.EventAgentStarted, "Agent task started", nil))
+	agentErr := session.StartAgentWithOptions(ctx, s.agentExecutor, prompt, opts)
+	if agentErr == nil {
+		if err := session.ReviewAgentRun(ctx, s.k8sClient); err != nil {
+			agentErr = fmt.Errorf("failed to review agent changes: %w", err)
+		}
+	}
+	_ = s.sessionRepo.RecordEvent(name, config.AgentFinishedEvent(session.GetLastAgentExecution(), agentErr))
+
+	// The execution record and usage are saved even when the task failed to start
+	if len(session.AgentExecutions) > 0 {
+		if err := s.sessionRepo.SaveSession(session); err != nil {
+			return nil, fmt.Errorf("failed to save agent execution record: %w", err)
+		}
+	}
+
+	return session, agentErr
+}
+
+// SessionEvents returns the event timeline of a session, oldest first
+func (s *SessionService) SessionEvents(name string) ([]config.SessionEvent, error) {
+	if !s.sessionRepo.SessionExists(name) {
+		return nil, fmt.Errorf("%w: %s", config.ErrSessionNotFound, name)
+	}
+	return s.sessionRepo.LoadEvents(name)
+}
+
+// StreamLogs opens a log stream for the session's main container
+func (s *SessionService) StreamLogs(ctx context.Context, name string, follow bool, tailLines int64) (io.ReadCloser, error) {
+	session, err := s.sessionRepo.LoadSession(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.k8sClient.StreamPodLogs(ctx, session.PodName, session.Namespace, kubernetes.MainContainerName, follow, tailLines)
+}
+
+// DiffSummary returns 'git diff --stat' for the session workspace, including untracked files
+func (s *SessionService) DiffSummary(ctx context.Context, name string) (string, error) {
+	session, err := s.sessionRepo.LoadSession(name)
+	if err != nil {
+		return "", err
+	}
+
+	stdout, stderr, err := s.k8sClient.ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", agent.DiffStatScript})
+	if err != nil {
+		return "", fmt.Errorf("failed to get diff summary: %s: %w", strings.TrimSpace(stderr), err)
+	}
+
+	return strings.TrimSpace(stdout), nil
+}
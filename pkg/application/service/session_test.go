@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/application/port"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+type fakeAgentRepo struct {
+	port.SessionRepository
+	session *config.SessionConfig
+	saved   int
+	events  []config.SessionEvent
+}
+
+func (f *fakeAgentRepo) LoadSession(name string) (*config.SessionConfig, error) {
+	return f.session, nil
+}
+
+func (f *fakeAgentRepo) SaveSession(session *config.SessionConfig) error {
+	f.saved++
+	return nil
+}
+
+func (f *fakeAgentRepo) RecordEvent(name string, event config.SessionEvent) error {
+	f.events = append(f.events, event)
+	return nil
+}
+
+// fakeLocker records lock calls and answers workspace git commands with nothing
+type fakeLocker struct {
+	port.KubernetesClient
+	holder    string
+	stolen    bool
+	released  []string
+	lockedErr error
+}
+
+func (f *fakeLocker) AcquireSessionLock(ctx context.Context, namespace, podName, holder string, ttl time.Duration, steal bool) (*kubernetes.SessionLock, error) {
+	if f.lockedErr != nil && !steal {
+		return nil, f.lockedErr
+	}
+	f.holder, f.stolen = holder, steal
+	return &kubernetes.SessionLock{Holder: holder}, nil
+}
+
+func (f *fakeLocker) ReleaseSessionLock(ctx context.Context, namespace, podName, holder string) error {
+	f.released = append(f.released, holder)
+	return nil
+}
+
+func (f *fakeLocker) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	return "", "", nil
+}
+
+func newAgentTestService(locker *fakeLocker) (*SessionService, *fakeAgentRepo, *agent.MockCodingAgentExecutor) {
+	repo := &fakeAgentRepo{session: &config.SessionConfig{Name: "work", Namespace: "dev", PodName: "kodama-work", Status: config.StatusRunning}}
+	executor := agent.NewMockCodingAgentExecutor()
+	return NewSessionService(repo, nil, locker, nil, executor), repo, executor
+}
+
+func TestRunAgentWithOptions_HoldsLockUntilDone(t *testing.T) {
+	locker := &fakeLocker{}
+	svc, repo, executor := newAgentTestService(locker)
+	executor.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
+		assert.Empty(t, locker.released, "the lock is held while the task runs")
+		return "task-1", nil
+	}
+
+	_, err := svc.RunAgentWithOptions(context.Background(), "work", "fix the tests", config.AgentRunOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{locker.holder}, locker.released)
+	assert.False(t, locker.stolen)
+	assert.Equal(t, 1, repo.saved)
+
+	require.Len(t, repo.events, 2)
+	assert.Equal(t, config.EventAgentStarted, repo.events[0].Type)
+	assert.Equal(t, config.EventAgentFinished, repo.events[1].Type)
+	assert.Equal(t, "completed", repo.events[1].Details["status"])
+}
+
+func TestRunAgentWithOptions_Locked(t *testing.T) {
+	locker := &fakeLocker{lockedErr: &kubernetes.LockHeldError{Lock: kubernetes.SessionLock{Holder: "bob@desktop/1"}}}
+	svc, _, executor := newAgentTestService(locker)
+
+	_, err := svc.RunAgentWithOptions(context.Background(), "work", "fix the tests", config.AgentRunOptions{})
+	assert.ErrorIs(t, err, kubernetes.ErrSessionLocked)
+	assert.Empty(t, executor.GetTaskStartCalls())
+
+	_, err = svc.RunAgentWithOptions(context.Background(), "work", "fix the tests", config.AgentRunOptions{StealLock: true})
+	require.NoError(t, err)
+	assert.True(t, locker.stolen)
+	assert.Len(t, locker.released, 1)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// sharedCleanScript removes the entries given as arguments from the shared volume
+const sharedCleanScript = `cd ` + kubernetes.SharedMountPath + ` && rm -rf -- "$@"`
+
+// SharedOptions selects the shared scratch volume of a namespace
+type SharedOptions struct {
+	Namespace string
+	PVC       string
+}
+
+// SharedListing is the content of the shared scratch volume
+type SharedListing struct {
+	PVC      string
+	Session  string                  // Session whose pod read the volume
+	Usage    *kubernetes.SharedUsage // nil when the usage could not be read
+	Entries  []kubernetes.SharedEntry
+	UsageErr error
+}
+
+// SharedCleanOptions selects the entries CleanShared removes
+type SharedCleanOptions struct {
+	SharedOptions
+	Names     []string      // Top-level entries to remove
+	OlderThan time.Duration // Remove top-level entries not modified for this long (0 = by name only)
+	All       bool          // Remove every entry
+	DryRun    bool          // Only report what would be removed
+}
+
+// ResolveSharedPVC returns pvc or the shared PVC from global config when unset
+func (s *SessionService) ResolveSharedPVC(pvc string) (string, error) {
+	if pvc != "" {
+		return pvc, nil
+	}
+	globalConfig, err := s.configRepo.LoadGlobalConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load global config: %w", err)
+	}
+	return config.CoalesceString(globalConfig.Defaults.Shared.PVC, kubernetes.DefaultSharedPVC), nil
+}
+
+// sharedSession returns a ready session of yours that mounts the shared volume
+// The volume is read through its pod, so no extra pod or image is needed.
+func (s *SessionService) sharedSession(ctx context.Context, opts SharedOptions) (*config.SessionConfig, error) {
+	sessions, err := s.sessionRepo.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	var pods map[string]*kubernetes.PodStatus
+	for _, session := range sessions {
+		if session.IsLocalRuntime() || session.Namespace != opts.Namespace ||
+			!session.Shared.Enabled || session.Shared.PVC != opts.PVC {
+			continue
+		}
+		if pods == nil {
+			if pods, err = s.k8sClient.ListSessionPods(ctx, opts.Namespace); err != nil {
+				return nil, err
+			}
+		}
+		if pod, ok := pods[session.PodName]; ok && pod.Ready {
+			return session, nil
+		}
+	}
+	return nil, fmt.Errorf("no running session mounts the shared volume %s in namespace %s; start one with: kubectl kodama start <name> --shared",
+		opts.PVC, opts.Namespace)
+}
+
+// ListShared lists the top-level entries of the shared volume and its usage
+func (s *SessionService) ListShared(ctx context.Context, opts SharedOptions) (*SharedListing, error) {
+	session, err := s.sharedSession(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return s.listShared(ctx, session)
+}
+
+// listShared reads the shared volume through the pod of session
+func (s *SessionService) listShared(ctx context.Context, session *config.SessionConfig) (*SharedListing, error) {
+	listing := &SharedListing{PVC: session.Shared.PVC, Session: session.Name}
+
+	stdout, stderr, err := s.k8sClient.ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", kubernetes.SharedListScript})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the shared volume: %s: %w", strings.TrimSpace(stderr), err)
+	}
+	if listing.Entries, err = kubernetes.ParseSharedEntries(stdout); err != nil {
+		return nil, err
+	}
+
+	stdout, _, err = s.k8sClient.ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", kubernetes.SharedUsageScript})
+	if err == nil {
+		var usage kubernetes.SharedUsage
+		if usage, err = kubernetes.ParseSharedUsage(stdout); err == nil {
+			listing.Usage = &usage
+		}
+	}
+	listing.UsageErr = err
+	return listing, nil
+}
+
+// CleanShared removes entries of the shared volume selected by opts and
+// returns them. Other sessions may still read them, so removal is explicit:
+// by name, by age or all of them.
+func (s *SessionService) CleanShared(ctx context.Context, opts SharedCleanOptions) ([]kubernetes.SharedEntry, error) {
+	if len(opts.Names) == 0 && opts.OlderThan == 0 && !opts.All {
+		return nil, fmt.Errorf("name the entries to remove, or use --older-than or --all")
+	}
+
+	session, err := s.sharedSession(ctx, opts.SharedOptions)
+	if err != nil {
+		return nil, err
+	}
+	listing, err := s.listShared(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := selectSharedEntries(listing.Entries, opts, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 || opts.DryRun {
+		return selected, nil
+	}
+
+	command := []string{"sh", "-c", sharedCleanScript, "sh"}
+	for _, entry := range selected {
+		command = append(command, entry.Name)
+	}
+	if _, stderr, err := s.k8sClient.ExecInPod(ctx, session.Namespace, session.PodName, command); err != nil {
+		return nil, fmt.Errorf("failed to clean the shared volume: %s: %w", strings.TrimSpace(stderr), err)
+	}
+	return selected, nil
+}
+
+// selectSharedEntries returns the entries CleanShared removes at now
+// Named entries must exist, so a typo is not mistaken for an empty volume.
+func selectSharedEntries(entries []kubernetes.SharedEntry, opts SharedCleanOptions, now time.Time) ([]kubernetes.SharedEntry, error) {
+	for _, name := range opts.Names {
+		if !slices.ContainsFunc(entries, func(entry kubernetes.SharedEntry) bool { return entry.Name == name }) {
+			return nil, fmt.Errorf("%q is not a top-level entry of the shared volume (see: kubectl kodama shared ls)", name)
+		}
+	}
+
+	var selected []kubernetes.SharedEntry
+	for _, entry := range entries {
+		switch {
+		case opts.All,
+			slices.Contains(opts.Names, entry.Name),
+			opts.OlderThan > 0 && now.Sub(entry.Modified) >= opts.OlderThan:
+			selected = append(selected, entry)
+		}
+	}
+	return selected, nil
+}
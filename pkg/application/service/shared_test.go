@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/application/port"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+type fakeSharedRepo struct {
+	port.SessionRepository
+	sessions []*config.SessionConfig
+}
+
+func (f *fakeSharedRepo) ListSessions() ([]*config.SessionConfig, error) {
+	return f.sessions, nil
+}
+
+// fakeSharedCluster answers the shared volume scripts in any pod
+type fakeSharedCluster struct {
+	port.KubernetesClient
+	pods     map[string]*kubernetes.PodStatus
+	listing  string
+	execPods []string
+	removed  []string
+}
+
+func (f *fakeSharedCluster) ListSessionPods(ctx context.Context, namespace string) (map[string]*kubernetes.PodStatus, error) {
+	return f.pods, nil
+}
+
+func (f *fakeSharedCluster) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	f.execPods = append(f.execPods, podName)
+	switch command[2] {
+	case kubernetes.SharedListScript:
+		return f.listing, "", nil
+	case kubernetes.SharedUsageScript:
+		return "nfs  1000  900  100  90% /shared\n", "", nil
+	}
+	f.removed = append(f.removed, command[4:]...)
+	return "", "", nil
+}
+
+func newSharedTestService() (*SessionService, *fakeSharedCluster) {
+	shared := config.SharedConfig{Enabled: true, PVC: "kodama-shared"}
+	repo := &fakeSharedRepo{sessions: []*config.SessionConfig{
+		{Name: "other-ns", Namespace: "prod", PodName: "kodama-other-ns", Shared: shared},
+		{Name: "unshared", Namespace: "dev", PodName: "kodama-unshared"},
+		{Name: "stopped", Namespace: "dev", PodName: "kodama-stopped", Shared: shared},
+		{Name: "codegen", Namespace: "dev", PodName: "kodama-codegen", Shared: shared},
+	}}
+	cluster := &fakeSharedCluster{
+		pods: map[string]*kubernetes.PodStatus{
+			"kodama-other-ns": {Ready: true},
+			"kodama-unshared": {Ready: true},
+			"kodama-stopped":  {Ready: false},
+			"kodama-codegen":  {Ready: true},
+		},
+		listing: fmt.Sprintf("2048\t%d\tstale-build\n64\t%d\tapi-client\n",
+			time.Now().Add(-30*24*time.Hour).Unix(), time.Now().Add(-time.Hour).Unix()),
+	}
+	return NewSessionService(repo, nil, cluster, nil, nil), cluster
+}
+
+func TestListShared(t *testing.T) {
+	svc, cluster := newSharedTestService()
+
+	listing, err := svc.ListShared(context.Background(), SharedOptions{Namespace: "dev", PVC: "kodama-shared"})
+	require.NoError(t, err)
+	assert.Equal(t, "codegen", listing.Session)
+	assert.Equal(t, []string{"kodama-codegen", "kodama-codegen"}, cluster.execPods)
+	require.Len(t, listing.Entries, 2)
+	assert.Equal(t, "stale-build", listing.Entries[0].Name)
+	require.NotNil(t, listing.Usage)
+	assert.True(t, listing.Usage.NearlyFull())
+
+	// No ready session mounts another PVC
+	_, err = svc.ListShared(context.Background(), SharedOptions{Namespace: "dev", PVC: "team-shared"})
+	assert.ErrorContains(t, err, "no running session mounts the shared volume team-shared")
+}
+
+func TestCleanShared(t *testing.T) {
+	svc, cluster := newSharedTestService()
+	opts := SharedOptions{Namespace: "dev", PVC: "kodama-shared"}
+
+	_, err := svc.CleanShared(context.Background(), SharedCleanOptions{SharedOptions: opts})
+	assert.ErrorContains(t, err, "--older-than or --all")
+
+	removed, err := svc.CleanShared(context.Background(), SharedCleanOptions{SharedOptions: opts, OlderThan: 7 * 24 * time.Hour, DryRun: true})
+	require.NoError(t, err)
+	require.Len(t, removed, 1)
+	assert.Equal(t, "stale-build", removed[0].Name)
+	assert.Empty(t, cluster.removed)
+
+	removed, err = svc.CleanShared(context.Background(), SharedCleanOptions{SharedOptions: opts, Names: []string{"api-client"}})
+	require.NoError(t, err)
+	assert.Len(t, removed, 1)
+	assert.Equal(t, []string{"api-client"}, cluster.removed)
+
+	_, err = svc.CleanShared(context.Background(), SharedCleanOptions{SharedOptions: opts, Names: []string{"api-clinet"}})
+	assert.ErrorContains(t, err, `"api-clinet" is not a top-level entry`)
+
+	cluster.removed = nil
+	removed, err = svc.CleanShared(context.Background(), SharedCleanOptions{SharedOptions: opts, All: true})
+	require.NoError(t, err)
+	assert.Len(t, removed, 2)
+	assert.ElementsMatch(t, []string{"stale-build", "api-client"}, cluster.removed)
+}
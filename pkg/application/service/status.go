@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// GetSessionStatus returns a session with the status of its pod and, when the
+// main container has terminated, the crash: its reason, exit code and last log
+// lines. Sessions with a watchdog also get its heartbeat and restarts.
+func (s *SessionService) GetSessionStatus(ctx context.Context, name string) (*SessionStatus, error) {
+	session, err := s.sessionRepo.LoadSession(name)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &SessionStatus{Session: session}
+	if session.IsLocalRuntime() {
+		running, err := containerRunning(ctx, session.Runtime, session.PodName)
+		switch {
+		case err != nil:
+			status.PodErr = err
+		case running:
+			status.Pod = &kubernetes.PodStatus{Phase: corev1.PodRunning, Ready: true}
+		default:
+			status.PodErr = fmt.Errorf("%w: container %s is not running", kubernetes.ErrPodNotFound, session.PodName)
+		}
+		return status, nil
+	}
+
+	status.Pod, status.PodErr = s.k8sClient.GetPod(ctx, session.PodName, session.Namespace)
+	if status.PodErr != nil {
+		return status, nil
+	}
+
+	status.Crash, err = s.k8sClient.GetContainerCrash(ctx, session.PodName, session.Namespace, kubernetes.DefaultCrashLogLines)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.Watchdog && status.Pod.Phase == corev1.PodRunning {
+		status.Watchdog, status.WatchdogErr = s.getWatchdogStatus(ctx, session.Namespace, session.PodName)
+	}
+	return status, nil
+}
+
+// getWatchdogStatus reads the watchdog's heartbeat and restart log in the pod
+func (s *SessionService) getWatchdogStatus(ctx context.Context, namespace, podName string) (*kubernetes.WatchdogStatus, error) {
+	stdout, stderr, err := s.k8sClient.ExecInPod(ctx, namespace, podName, []string{"sh", "-c", kubernetes.WatchdogStatusScript()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read watchdog status: %s: %w", strings.TrimSpace(stderr), err)
+	}
+	return kubernetes.ParseWatchdogStatus(stdout)
+}
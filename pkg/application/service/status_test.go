@@ -0,0 +1,73 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/application/port"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// fakeStatusCluster answers pod, crash and watchdog status requests
+type fakeStatusCluster struct {
+	port.KubernetesClient
+	pod      *kubernetes.PodStatus
+	crash    *kubernetes.ContainerCrash
+	watchdog string
+	execs    int
+}
+
+func (f *fakeStatusCluster) GetPod(ctx context.Context, name, namespace string) (*kubernetes.PodStatus, error) {
+	return f.pod, nil
+}
+
+func (f *fakeStatusCluster) GetContainerCrash(ctx context.Context, name, namespace string, logLines int64) (*kubernetes.ContainerCrash, error) {
+	return f.crash, nil
+}
+
+func (f *fakeStatusCluster) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	f.execs++
+	if !strings.Contains(command[len(command)-1], "heartbeat") {
+		return "", "unexpected command", assert.AnError
+	}
+	return f.watchdog, "", nil
+}
+
+func TestGetSessionStatus_Watchdog(t *testing.T) {
+	repo := &fakeAgentRepo{session: &config.SessionConfig{Name: "w", Namespace: "dev", PodName: "kodama-w", Watchdog: true}}
+	k8s := &fakeStatusCluster{
+		pod:      &kubernetes.PodStatus{Phase: corev1.PodRunning, Ready: true},
+		watchdog: "heartbeat=1714557600\nrestarts=1\nlast=2024-05-01T09:58:00Z main process exited with 1\n",
+	}
+	svc := NewSessionService(repo, nil, k8s, nil, nil)
+
+	status, err := svc.GetSessionStatus(context.Background(), "w")
+	require.NoError(t, err)
+	require.NoError(t, status.WatchdogErr)
+	require.NotNil(t, status.Watchdog)
+	assert.Equal(t, int64(1714557600), status.Watchdog.Heartbeat.Unix())
+	assert.Equal(t, 1, status.Watchdog.Restarts)
+	assert.Nil(t, status.Crash)
+}
+
+func TestGetSessionStatus_WithoutWatchdog(t *testing.T) {
+	repo := &fakeAgentRepo{session: &config.SessionConfig{Name: "p", Namespace: "dev", PodName: "kodama-p"}}
+	k8s := &fakeStatusCluster{
+		pod:   &kubernetes.PodStatus{Phase: corev1.PodRunning},
+		crash: &kubernetes.ContainerCrash{Reason: "Error", ExitCode: 1},
+	}
+	svc := NewSessionService(repo, nil, k8s, nil, nil)
+
+	status, err := svc.GetSessionStatus(context.Background(), "p")
+	require.NoError(t, err)
+	assert.Nil(t, status.Watchdog)
+	assert.Zero(t, k8s.execs, "no exec without a watchdog")
+	require.NotNil(t, status.Crash)
+	assert.Equal(t, int32(1), status.Crash.ExitCode)
+}
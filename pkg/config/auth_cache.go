@@ -0,0 +1,53 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/illumination-k/kodama/pkg/agent/auth"
+)
+
+// GetAuthFilePath returns the path of the auth cache (claude-auth.json)
+func (s *Store) GetAuthFilePath() string {
+	return filepath.Join(s.configDir, AuthFile)
+}
+
+// SaveAuthProfile stores a profile in the auth cache, replacing one with the
+// same name, and encrypts the file when store encryption is enabled. The
+// profile becomes the default when the cache has no default yet.
+func (s *Store) SaveAuthProfile(name string, profile auth.Profile) error {
+	if err := s.EnsureConfigDir(); err != nil {
+		return err
+	}
+
+	path := s.GetAuthFilePath()
+	authFile := auth.AuthFile{Profiles: map[string]auth.Profile{}}
+	data, err := s.readFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &authFile); err != nil {
+			return fmt.Errorf("failed to parse auth file: %w", err)
+		}
+		if authFile.Profiles == nil {
+			authFile.Profiles = map[string]auth.Profile{}
+		}
+	case !os.IsNotExist(err):
+		return fmt.Errorf("failed to read auth file: %w", err)
+	}
+
+	authFile.Profiles[name] = profile
+	if _, ok := authFile.Profiles[authFile.DefaultProfile]; !ok {
+		authFile.DefaultProfile = name
+	}
+
+	data, err = json.MarshalIndent(authFile, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal auth file: %w", err)
+	}
+	if err := s.writeFile(path, data); err != nil {
+		return fmt.Errorf("failed to write auth file: %w", err)
+	}
+	return nil
+}
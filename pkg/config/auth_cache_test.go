@@ -0,0 +1,27 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/agent/auth"
+)
+
+func TestStore_SaveAuthProfile(t *testing.T) {
+	store := NewStoreWithPath(t.TempDir())
+
+	require.NoError(t, store.SaveAuthProfile("work", auth.Profile{Token: "work-token", RefreshToken: "refresh"}))
+	require.NoError(t, store.SaveAuthProfile("home", auth.Profile{Token: "home-token"}))
+
+	// The first profile becomes the default, and the file provider reads both
+	creds, err := auth.NewFileProvider(auth.FileConfig{Path: store.GetAuthFilePath()}).GetCredentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "work-token", creds.Token)
+
+	creds, err = auth.NewFileProvider(auth.FileConfig{Path: store.GetAuthFilePath(), Profile: "home"}).GetCredentials(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "home-token", creds.Token)
+}
@@ -0,0 +1,134 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/illumination-k/kodama/pkg/agent/auth"
+)
+
+// ErrInvalidClaudeAuth is returned when claudeAuth in the global config cannot
+// build an auth provider. It surfaces when the coding agent first runs.
+var ErrInvalidClaudeAuth = errors.New("invalid claudeAuth in global config")
+
+// ClaudeAuthConfig selects how the coding agent authenticates, so alternative
+// agent backends such as Gemini or OpenAI-compatible servers can be used.
+// Without an authType, CLAUDE_CODE_AUTH_TOKEN or the auth file is used.
+type ClaudeAuthConfig struct {
+	// AuthType is token, file, google-adc or api-key
+	AuthType string `yaml:"authType,omitempty"`
+
+	// EnvVar holds the token or API key (token, api-key), or names the
+	// variable the Google access token is exposed as (google-adc)
+	EnvVar string `yaml:"envVar,omitempty"`
+
+	// Path and Profile select the auth file and its profile (file)
+	Path    string `yaml:"path,omitempty"`
+	Profile string `yaml:"profile,omitempty"`
+
+	// CredentialsFile and Scopes configure Application Default Credentials
+	// (google-adc; default: $GOOGLE_APPLICATION_CREDENTIALS or the metadata server)
+	CredentialsFile string   `yaml:"credentialsFile,omitempty"`
+	Scopes          []string `yaml:"scopes,omitempty"`
+
+	// Provider is openai (default), gemini, anthropic, openrouter or another
+	// OpenAI-compatible backend, and BaseURL its endpoint (api-key)
+	Provider string `yaml:"provider,omitempty"`
+	BaseURL  string `yaml:"baseURL,omitempty"`
+
+	// Chain lists providers tried in order instead of a single authType,
+	// falling back to the next when one has no credentials
+	Chain []ClaudeAuthConfig `yaml:"chain,omitempty"`
+
+	// Login is the identity provider of kodama auth login
+	Login DeviceLoginConfig `yaml:"login,omitempty"`
+}
+
+// DeviceLoginConfig configures the OAuth device flow of kodama auth login
+type DeviceLoginConfig struct {
+	DeviceAuthURL string   `yaml:"deviceAuthURL,omitempty"` // Device authorization endpoint
+	TokenURL      string   `yaml:"tokenURL,omitempty"`      // Token endpoint, also used to refresh
+	ClientID      string   `yaml:"clientID,omitempty"`      // Public client registered for the device flow
+	Scopes        []string `yaml:"scopes,omitempty"`        // e.g. openid, offline_access
+}
+
+// DeviceFlow converts the config for the auth device flow
+func (c DeviceLoginConfig) DeviceFlow() auth.DeviceFlowConfig {
+	return auth.DeviceFlowConfig{
+		DeviceAuthURL: c.DeviceAuthURL,
+		TokenURL:      c.TokenURL,
+		ClientID:      c.ClientID,
+		Scopes:        c.Scopes,
+	}
+}
+
+// IsEnabled returns true if an auth type or chain is configured
+func (c ClaudeAuthConfig) IsEnabled() bool {
+	return c.AuthType != "" || len(c.Chain) > 0
+}
+
+// AuthConfig converts the config for auth.NewAuthProvider
+func (c ClaudeAuthConfig) AuthConfig() auth.AuthConfig {
+	return auth.AuthConfig{
+		Type:        auth.AuthType(c.AuthType),
+		TokenSource: auth.TokenConfig{EnvVar: c.EnvVar},
+		FileSource:  auth.FileConfig{Path: c.Path, Profile: c.Profile},
+		GoogleADCSource: auth.GoogleADCConfig{
+			CredentialsFile: c.CredentialsFile,
+			Scopes:          c.Scopes,
+			EnvVar:          c.EnvVar,
+		},
+		APIKeySource: auth.APIKeyConfig{
+			Provider: c.Provider,
+			EnvVar:   c.EnvVar,
+			BaseURL:  c.BaseURL,
+		},
+	}
+}
+
+// NewProvider returns the configured auth provider, or nil without an authType
+// or chain. A chain becomes an auth.ChainProvider.
+func (c ClaudeAuthConfig) NewProvider() (auth.AuthProvider, error) {
+	if !c.IsEnabled() {
+		return nil, nil
+	}
+	if len(c.Chain) == 0 {
+		return auth.NewAuthProvider(c.AuthConfig())
+	}
+	providers, err := c.Providers()
+	if err != nil {
+		return nil, err
+	}
+	return auth.NewChainProvider(providers...), nil
+}
+
+// Providers returns the providers the agent tries in order: the chain, the
+// configured authType, or else CLAUDE_CODE_AUTH_TOKEN and then the auth file
+func (c ClaudeAuthConfig) Providers() ([]auth.AuthProvider, error) {
+	if !c.IsEnabled() {
+		return []auth.AuthProvider{
+			auth.NewTokenProvider(auth.TokenConfig{EnvVar: "CLAUDE_CODE_AUTH_TOKEN"}),
+			auth.NewFileProvider(auth.FileConfig{}),
+		}, nil
+	}
+	if len(c.Chain) == 0 {
+		provider, err := auth.NewAuthProvider(c.AuthConfig())
+		if err != nil {
+			return nil, err
+		}
+		return []auth.AuthProvider{provider}, nil
+	}
+
+	providers := make([]auth.AuthProvider, 0, len(c.Chain))
+	for i, entry := range c.Chain {
+		if len(entry.Chain) > 0 || entry.AuthType == "" {
+			return nil, fmt.Errorf("claudeAuth.chain[%d]: set authType (chains cannot be nested)", i)
+		}
+		provider, err := auth.NewAuthProvider(entry.AuthConfig())
+		if err != nil {
+			return nil, fmt.Errorf("claudeAuth.chain[%d]: %w", i, err)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
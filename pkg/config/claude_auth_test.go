@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/illumination-k/kodama/pkg/agent/auth"
+)
+
+func TestClaudeAuthConfig_NewProvider(t *testing.T) {
+	provider, err := ClaudeAuthConfig{}.NewProvider()
+	require.NoError(t, err)
+	assert.Nil(t, provider, "without authType the environment is used")
+
+	var global GlobalConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+claudeAuth:
+  authType: api-key
+  provider: vllm
+  envVar: VLLM_API_KEY
+  baseURL: http://vllm:8000/v1
+`), &global))
+	provider, err = global.ClaudeAuth.NewProvider()
+	require.NoError(t, err)
+	assert.Equal(t, auth.AuthTypeAPIKey, provider.Type())
+
+	provider, err = ClaudeAuthConfig{AuthType: "google-adc"}.NewProvider()
+	require.NoError(t, err)
+	assert.Equal(t, auth.AuthTypeGoogleADC, provider.Type())
+
+	_, err = ClaudeAuthConfig{AuthType: "kerberos"}.NewProvider()
+	assert.ErrorContains(t, err, `unsupported auth type "kerberos"`)
+}
+
+func TestClaudeAuthConfig_Chain(t *testing.T) {
+	var global GlobalConfig
+	require.NoError(t, yaml.Unmarshal([]byte(`
+claudeAuth:
+  chain:
+    - authType: google-adc
+    - authType: file
+    - authType: token
+      envVar: MY_TOKEN
+`), &global))
+
+	providers, err := global.ClaudeAuth.Providers()
+	require.NoError(t, err)
+	require.Len(t, providers, 3)
+	assert.Equal(t, []auth.AuthType{auth.AuthTypeGoogleADC, auth.AuthTypeFile, auth.AuthTypeToken},
+		[]auth.AuthType{providers[0].Type(), providers[1].Type(), providers[2].Type()})
+
+	provider, err := global.ClaudeAuth.NewProvider()
+	require.NoError(t, err)
+	assert.Equal(t, auth.AuthTypeChain, provider.Type())
+
+	// Without claudeAuth the environment token and the auth file are tried
+	providers, err = ClaudeAuthConfig{}.Providers()
+	require.NoError(t, err)
+	assert.Len(t, providers, 2)
+
+	_, err = ClaudeAuthConfig{Chain: []ClaudeAuthConfig{{AuthType: "token"}, {}}}.Providers()
+	assert.ErrorContains(t, err, "claudeAuth.chain[1]")
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DefaultDataDestination is where datasets are fetched to, relative to /workspace
+const DefaultDataDestination = "data"
+
+// DataConfig fetches a dataset from object storage into the workspace before
+// the coding agent starts, with an init container running the aws, gcloud or
+// rclone CLI
+type DataConfig struct {
+	URI               string `yaml:"uri,omitempty"`               // s3://bucket/prefix/, gs://bucket/object or an rclone remote:path
+	Destination       string `yaml:"destination,omitempty"`       // Directory relative to /workspace (default: data)
+	CredentialsSecret string `yaml:"credentialsSecret,omitempty"` // Secret with the credentials (empty = pod identity)
+	Image             string `yaml:"image,omitempty"`             // Replaces the default image for the URI scheme
+}
+
+// IsEnabled reports whether a dataset is fetched
+func (d DataConfig) IsEnabled() bool {
+	return d.URI != ""
+}
+
+// DestinationPath returns the absolute directory the dataset is fetched to
+func (d DataConfig) DestinationPath() string {
+	destination := strings.TrimPrefix(CoalesceString(d.Destination, DefaultDataDestination), "/workspace/")
+	return path.Join("/workspace", destination)
+}
+
+// Validate checks that the destination is a directory inside the workspace
+func (d DataConfig) Validate() error {
+	if !d.IsEnabled() {
+		return nil
+	}
+	if d.Destination == "" {
+		return nil
+	}
+	destination := path.Clean(strings.TrimPrefix(d.Destination, "/workspace/"))
+	if path.IsAbs(destination) || destination == "." || destination == ".." || strings.HasPrefix(destination, "../") {
+		return fmt.Errorf("data.destination must be a directory inside /workspace, got %q", d.Destination)
+	}
+	return nil
+}
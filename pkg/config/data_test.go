@@ -0,0 +1,25 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataConfig(t *testing.T) {
+	assert.NoError(t, DataConfig{}.Validate())
+
+	data := DataConfig{URI: "s3://bucket/datasets/"}
+	assert.True(t, data.IsEnabled())
+	assert.NoError(t, data.Validate())
+	assert.Equal(t, "/workspace/data", data.DestinationPath())
+
+	data.Destination = "/workspace/inputs/imagenet"
+	assert.NoError(t, data.Validate())
+	assert.Equal(t, "/workspace/inputs/imagenet", data.DestinationPath())
+
+	for _, destination := range []string{"/data", "/workspace", "../data", ".", "inputs/../.."} {
+		data.Destination = destination
+		assert.ErrorContains(t, data.Validate(), "must be a directory inside /workspace", destination)
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DotfilesConfig clones a dotfiles repository into the pod after the session
+// starts and runs its installer, as an alternative to syncing many custom dirs
+type DotfilesConfig struct {
+	Repo   string `yaml:"repo,omitempty"`   // e.g. https://github.com/me/dotfiles
+	Branch string `yaml:"branch,omitempty"` // Default: the repository's default branch
+	// InstallCommand runs in the cloned repository, e.g. "chezmoi init --apply --source ."
+	// or "stow -t ~ zsh git". Empty runs install.sh, bootstrap.sh or setup.sh when
+	// present, applies a chezmoi source directory, or links the top-level dotfiles.
+	InstallCommand string `yaml:"installCommand,omitempty"`
+}
+
+// IsEnabled reports whether a dotfiles repository is configured
+func (d DotfilesConfig) IsEnabled() bool {
+	return d.Repo != ""
+}
+
+// Validate checks that an install command comes with a repository
+func (d DotfilesConfig) Validate() error {
+	if d.Repo == "" && (d.Branch != "" || d.InstallCommand != "") {
+		return fmt.Errorf("dotfiles.repo is required when dotfiles.branch or dotfiles.installCommand is set")
+	}
+	if strings.HasPrefix(d.Repo, "-") || strings.HasPrefix(d.Branch, "-") {
+		return fmt.Errorf("invalid dotfiles repository %q", d.Repo)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Types of session events
+const (
+	EventCreated       = "created"
+	EventPodReady      = "pod-ready"
+	EventAgentReady    = "agent-ready"
+	EventSyncCompleted = "sync-completed"
+	EventAgentStarted  = "agent-started"
+	EventAgentFinished = "agent-finished"
+	EventAttachOpened  = "attach-opened"
+	EventError         = "error"
+)
+
+// SessionEvent is an entry in a session's timeline
+type SessionEvent struct {
+	Time    time.Time         `yaml:"time" json:"time"`
+	Type    string            `yaml:"type" json:"type"`
+	Message string            `yaml:"message" json:"message"`
+	Details map[string]string `yaml:"details,omitempty" json:"details,omitempty"`
+}
+
+// NewSessionEvent returns an event of the given type that happened now
+func NewSessionEvent(eventType, message string, details map[string]string) SessionEvent {
+	return SessionEvent{Time: time.Now(), Type: eventType, Message: message, Details: details}
+}
+
+// AgentFinishedEvent describes the end of an agent task: err is the error the
+// task failed with, and execution its record. Prompts are left out, as the
+// timeline is not encrypted with the session file.
+func AgentFinishedEvent(execution *AgentExecution, err error) SessionEvent {
+	details := map[string]string{}
+	if execution != nil && execution.Step != "" {
+		details["step"] = execution.Step
+	}
+	if err != nil {
+		details["status"] = "failed"
+		return NewSessionEvent(EventAgentFinished, "Agent task failed: "+err.Error(), details)
+	}
+
+	message := "Agent task finished"
+	if execution != nil {
+		details["status"] = execution.Status
+		details["duration"] = time.Since(execution.ExecutedAt).Round(time.Second).String()
+		if len(execution.ChangedFiles) > 0 {
+			details["changedFiles"] = fmt.Sprint(len(execution.ChangedFiles))
+		}
+		if execution.Status == AgentStatusNeedsReview {
+			message = "Agent task finished with changes to protected paths"
+		}
+	}
+	return NewSessionEvent(EventAgentFinished, message, details)
+}
+
+// RecordEvent appends an event to the session's timeline, one JSON object per
+// line in its session directory
+func (s *Store) RecordEvent(name string, event SessionEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode session event: %w", err)
+	}
+
+	dir := s.GetSessionDir(name)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	// #nosec G304 -- path is constructed from config directory
+	file, err := os.OpenFile(filepath.Join(dir, EventsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open session events: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write session event: %w", err)
+	}
+	return file.Close()
+}
+
+// LoadEvents returns the session's timeline, oldest first
+// A line cut short by an interrupted write is skipped.
+func (s *Store) LoadEvents(name string) ([]SessionEvent, error) {
+	// #nosec G304 -- path is constructed from config directory
+	data, err := os.ReadFile(filepath.Join(s.GetSessionDir(name), EventsFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session events: %w", err)
+	}
+
+	var events []SessionEvent
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var event SessionEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse session events: %w", err)
+	}
+
+	// Commands run concurrently, so appends may land slightly out of order
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events, nil
+}
@@ -0,0 +1,65 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Events(t *testing.T) {
+	store := NewStoreWithPath(t.TempDir())
+
+	// Empty until the first event
+	events, err := store.LoadEvents("my-work")
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	created := SessionEvent{Time: time.Unix(1700000000, 0), Type: EventCreated, Message: "Session created"}
+	synced := SessionEvent{
+		Time: time.Unix(1700000060, 0), Type: EventSyncCompleted, Message: "Initial sync completed",
+		Details: map[string]string{"added": "12"},
+	}
+	// Recorded out of order, as by concurrent commands
+	require.NoError(t, store.RecordEvent("my-work", synced))
+	require.NoError(t, store.RecordEvent("my-work", created))
+
+	// A line cut short by an interrupted write is skipped
+	path := filepath.Join(store.GetSessionDir("my-work"), EventsFile)
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"time":"2023-11-`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	events, err = store.LoadEvents("my-work")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, EventCreated, events[0].Type)
+	assert.True(t, created.Time.Equal(events[0].Time))
+	assert.Equal(t, EventSyncCompleted, events[1].Type)
+	assert.Equal(t, synced.Details, events[1].Details)
+}
+
+func TestAgentFinishedEvent(t *testing.T) {
+	event := AgentFinishedEvent(&AgentExecution{
+		ExecutedAt:   time.Now().Add(-time.Minute),
+		Status:       AgentStatusNeedsReview,
+		ChangedFiles: []string{"a.go", "b.go"},
+	}, nil)
+	assert.Equal(t, EventAgentFinished, event.Type)
+	assert.Contains(t, event.Message, "protected paths")
+	assert.Equal(t, AgentStatusNeedsReview, event.Details["status"])
+	assert.Equal(t, "2", event.Details["changedFiles"])
+
+	event = AgentFinishedEvent(nil, errors.New("boom"))
+	assert.Equal(t, "Agent task failed: boom", event.Message)
+	assert.Equal(t, "failed", event.Details["status"])
+
+	event = AgentFinishedEvent(&AgentExecution{Step: "migrate", Status: "failed"}, errors.New("boom"))
+	assert.Equal(t, "migrate", event.Details["step"])
+}
@@ -1,26 +1,154 @@
 package config
 
 import (
+	"fmt"
+	"slices"
+	"strings"
+
 	"github.com/illumination-k/kodama/pkg/env"
+	"github.com/illumination-k/kodama/pkg/kubernetes/initcontainer"
 	"github.com/illumination-k/kodama/pkg/secretfile"
 )
 
 // GlobalConfig represents global configuration for Kodama
 type GlobalConfig struct {
-	Defaults DefaultsConfig   `yaml:"defaults"`
-	Sync     GlobalSyncConfig `yaml:"sync,omitempty"`
+	SchemaVersion int              `yaml:"schemaVersion,omitempty"` // See GlobalSchemaVersion
+	Defaults      DefaultsConfig   `yaml:"defaults"`
+	Sync          GlobalSyncConfig `yaml:"sync,omitempty"`
+	Store         StoreConfig      `yaml:"store,omitempty"`
+	Snapshot      SnapshotConfig   `yaml:"snapshot,omitempty"`
+	Proxy         ProxyConfig      `yaml:"proxy,omitempty"`
+	TLS           TLSConfig        `yaml:"tls,omitempty"`
+	// Installer selects where Claude Code and ttyd binaries come from at pod start
+	Installer InstallerConfig `yaml:"installer,omitempty"`
+	// ClaudeAuth selects how the coding agent authenticates
+	ClaudeAuth ClaudeAuthConfig `yaml:"claudeAuth,omitempty"`
+	// Values are available to session templates as {{ .Values.key }}
+	Values map[string]interface{} `yaml:"values,omitempty"`
+	// Images are suggested when completing --image, e.g. the team's registry images
+	Images []string `yaml:"images,omitempty"`
+	// Contexts override Defaults per kubeconfig context name, see ForContext
+	Contexts map[string]DefaultsConfig `yaml:"contexts,omitempty"`
+	// RequiredLabels are session label keys every new session must set,
+	// e.g. team and cost-center for chargeback, see CheckRequiredLabels
+	RequiredLabels []string `yaml:"requiredLabels,omitempty"`
+}
+
+// SnapshotConfig holds settings for workspace snapshots
+type SnapshotConfig struct {
+	// Store is an s3:// or gs:// prefix where snapshots are written by default
+	Store string `yaml:"store,omitempty"`
+	// Exclude lists tar patterns left out of snapshots (e.g. node_modules)
+	Exclude []string `yaml:"exclude,omitempty"`
+}
+
+// ProxyConfig holds HTTP proxy settings passed to every container in session pods
+type ProxyConfig struct {
+	HTTPProxy  string `yaml:"httpProxy,omitempty"`  // e.g. http://proxy.corp:3128
+	HTTPSProxy string `yaml:"httpsProxy,omitempty"` // e.g. http://proxy.corp:3128
+	NoProxy    string `yaml:"noProxy,omitempty"`    // Comma-separated hosts, e.g. .corp,10.0.0.0/8
+}
+
+// IsEmpty returns true if no proxy is configured
+func (p ProxyConfig) IsEmpty() bool {
+	return p.HTTPProxy == "" && p.HTTPSProxy == "" && p.NoProxy == ""
+}
+
+// TLSConfig holds trust settings for session pods
+type TLSConfig struct {
+	// ExtraCABundleSecret names a Secret in the session namespace whose ca.crt
+	// key is a PEM bundle trusted by curl, git, npm, pip and Node.js
+	ExtraCABundleSecret string `yaml:"extraCABundleSecret,omitempty"`
+}
+
+// Installer sources for Claude Code and ttyd binaries
+const (
+	InstallerSourceDownload  = initcontainer.SourceDownload  // claude.ai/install.sh and GitHub releases (default)
+	InstallerSourceConfigMap = initcontainer.SourceConfigMap // Binaries stored as ConfigMap binaryData
+	InstallerSourcePVC       = initcontainer.SourcePVC       // Binaries on a pre-populated PVC
+	InstallerSourceImage     = initcontainer.SourceImage     // Binaries baked into an OCI image
+)
+
+// InstallerConfig selects where the tools-installer init container gets its binaries
+// Every source other than download copies files named claude and ttyd, so pods
+// can start in air-gapped clusters.
+type InstallerConfig struct {
+	Source    string `yaml:"source,omitempty"`    // download (default), configmap, pvc or image
+	ConfigMap string `yaml:"configMap,omitempty"` // ConfigMap name for the configmap source
+	PVC       string `yaml:"pvc,omitempty"`       // PVC name for the pvc source
+	Image     string `yaml:"image,omitempty"`     // Image for the image source
+	Path      string `yaml:"path,omitempty"`      // Directory with the binaries, inside the PVC or the image
+}
+
+// IsDownload returns true if binaries are downloaded from the internet
+func (i InstallerConfig) IsDownload() bool {
+	return i.Source == "" || i.Source == InstallerSourceDownload
+}
+
+// Validate checks that the source is known and names what it copies from
+func (i InstallerConfig) Validate() error {
+	var missing string
+	switch i.Source {
+	case "", InstallerSourceDownload:
+	case InstallerSourceConfigMap:
+		if i.ConfigMap == "" {
+			missing = "installer.configMap"
+		}
+	case InstallerSourcePVC:
+		if i.PVC == "" {
+			missing = "installer.pvc"
+		}
+	case InstallerSourceImage:
+		if i.Image == "" {
+			missing = "installer.image"
+		}
+	default:
+		return fmt.Errorf("unknown installer.source %q (want %s, %s, %s or %s)", i.Source,
+			InstallerSourceDownload, InstallerSourceConfigMap, InstallerSourcePVC, InstallerSourceImage)
+	}
+	if missing != "" {
+		return fmt.Errorf("installer.source %s requires %s", i.Source, missing)
+	}
+	return nil
+}
+
+// StoreConfig holds settings for the local session store
+type StoreConfig struct {
+	// Encrypt enables AES-256-GCM encryption at rest for session files
+	Encrypt bool `yaml:"encrypt,omitempty"`
+
+	// KeySource is where the encryption key is kept: keychain (default) or age
+	KeySource string `yaml:"keySource,omitempty"`
+
+	// AgeRecipient is the age public key the store key is encrypted to (keySource: age)
+	AgeRecipient string `yaml:"ageRecipient,omitempty"`
+
+	// AgeIdentity is the age identity file that decrypts the store key (keySource: age)
+	AgeIdentity string `yaml:"ageIdentity,omitempty"`
 }
 
 // DefaultsConfig holds default values for session creation
 type DefaultsConfig struct {
-	Namespace    string                      `yaml:"namespace"`
-	Image        string                      `yaml:"image"`
-	Resources    ResourceConfig              `yaml:"resources"`
-	Storage      StorageConfig               `yaml:"storage"`
-	Ttyd         TtydConfig                  `yaml:"ttyd"`
-	BranchPrefix string                      `yaml:"branchPrefix"`
-	Env          env.EnvConfig               `yaml:"env,omitempty"`
-	SecretFile   secretfile.SecretFileConfig `yaml:"secretFile,omitempty"`
+	Namespace     string                      `yaml:"namespace"`
+	Image         string                      `yaml:"image"`
+	Resources     ResourceConfig              `yaml:"resources"`
+	Storage       StorageConfig               `yaml:"storage"`
+	Ttyd          TtydConfig                  `yaml:"ttyd"`
+	BranchPrefix  string                      `yaml:"branchPrefix"`
+	Shell         string                      `yaml:"shell,omitempty"`
+	Locale        string                      `yaml:"locale,omitempty"`
+	GitIdentity   GitIdentityConfig           `yaml:"gitIdentity,omitempty"`
+	GitSigning    GitSigningConfig            `yaml:"gitSigning,omitempty"`
+	Git           GitConfig                   `yaml:"git,omitempty"`
+	Dotfiles      DotfilesConfig              `yaml:"dotfiles,omitempty"`
+	Env           env.EnvConfig               `yaml:"env,omitempty"`
+	SecretFile    secretfile.SecretFileConfig `yaml:"secretFile,omitempty"`
+	SealedSecrets bool                        `yaml:"sealedSecrets,omitempty"`
+	Cache         CacheConfig                 `yaml:"cache,omitempty"`
+	Shared        SharedConfig                `yaml:"shared,omitempty"`
+	Disruption    DisruptionConfig            `yaml:"disruption,omitempty"`
+	Editor        EditorConfig                `yaml:"editor,omitempty"`
+	Agent         AgentConfig                 `yaml:"agent,omitempty"`
 }
 
 // StorageConfig holds default storage sizes
@@ -32,8 +160,10 @@ type StorageConfig struct {
 // GlobalSyncConfig holds global sync-related configuration
 type GlobalSyncConfig struct {
 	UseGitignore *bool           `yaml:"useGitignore,omitempty"`
+	Include      []string        `yaml:"include,omitempty"` // Only sync these root-relative paths (evaluated before excludes)
 	Exclude      []string        `yaml:"exclude,omitempty"`
 	CustomDirs   []CustomDirSync `yaml:"customDirs,omitempty"`
+	Compression  string          `yaml:"compression,omitempty"` // none, gzip or zstd (default: zstd when the pod has it, else gzip)
 }
 
 // DefaultGlobalConfig returns a GlobalConfig with sensible defaults
@@ -90,6 +220,28 @@ func (g *GlobalConfig) Merge(other *GlobalConfig) {
 	if other.Defaults.BranchPrefix != "" {
 		g.Defaults.BranchPrefix = other.Defaults.BranchPrefix
 	}
+	if other.Defaults.Shell != "" {
+		g.Defaults.Shell = other.Defaults.Shell
+	}
+	if other.Defaults.Locale != "" {
+		g.Defaults.Locale = other.Defaults.Locale
+	}
+	if other.Defaults.SealedSecrets {
+		g.Defaults.SealedSecrets = true
+	}
+	g.Defaults.GitIdentity = g.Defaults.GitIdentity.Merge(other.Defaults.GitIdentity)
+	if other.Defaults.GitSigning.IsEnabled() {
+		g.Defaults.GitSigning = other.Defaults.GitSigning
+	}
+	if other.Defaults.Git.InstallHooks {
+		g.Defaults.Git.InstallHooks = true
+	}
+	if len(other.Defaults.Git.Hooks) > 0 {
+		g.Defaults.Git.Hooks = other.Defaults.Git.Hooks
+	}
+	if other.Defaults.Dotfiles.IsEnabled() {
+		g.Defaults.Dotfiles = other.Defaults.Dotfiles
+	}
 	// Merge ttyd config
 	if other.Defaults.Ttyd.Port != 0 {
 		g.Defaults.Ttyd.Port = other.Defaults.Ttyd.Port
@@ -106,6 +258,9 @@ func (g *GlobalConfig) Merge(other *GlobalConfig) {
 		g.Defaults.Ttyd.Writable = other.Defaults.Ttyd.Writable
 	}
 	// Merge sync config
+	if len(other.Sync.Include) > 0 {
+		g.Sync.Include = other.Sync.Include
+	}
 	if len(other.Sync.Exclude) > 0 {
 		g.Sync.Exclude = other.Sync.Exclude
 	}
@@ -115,6 +270,9 @@ func (g *GlobalConfig) Merge(other *GlobalConfig) {
 	if len(other.Sync.CustomDirs) > 0 {
 		g.Sync.CustomDirs = other.Sync.CustomDirs
 	}
+	if other.Sync.Compression != "" {
+		g.Sync.Compression = other.Sync.Compression
+	}
 	// Merge env config
 	if len(other.Defaults.Env.DotenvFiles) > 0 {
 		g.Defaults.Env.DotenvFiles = other.Defaults.Env.DotenvFiles
@@ -127,4 +285,96 @@ func (g *GlobalConfig) Merge(other *GlobalConfig) {
 	if len(other.Defaults.SecretFile.Files) > 0 {
 		g.Defaults.SecretFile.Files = other.Defaults.SecretFile.Files
 	}
+	// Merge cache config
+	if other.Defaults.Cache.PVC != "" {
+		g.Defaults.Cache.PVC = other.Defaults.Cache.PVC
+	}
+	// Merge shared scratch volume config
+	g.Defaults.Shared = g.Defaults.Shared.Merge(other.Defaults.Shared)
+	// Merge eviction protection
+	g.Defaults.Disruption = g.Defaults.Disruption.Merge(other.Defaults.Disruption)
+	// Merge editor config
+	g.Defaults.Editor = g.Defaults.Editor.Merge(other.Defaults.Editor)
+	// Merge agent config
+	g.Defaults.Agent = g.Defaults.Agent.Merge(other.Defaults.Agent)
+	// Merge snapshot config
+	if other.Snapshot.Store != "" {
+		g.Snapshot.Store = other.Snapshot.Store
+	}
+	if len(other.Snapshot.Exclude) > 0 {
+		g.Snapshot.Exclude = other.Snapshot.Exclude
+	}
+	// Merge proxy and TLS config
+	if !other.Proxy.IsEmpty() {
+		g.Proxy = other.Proxy
+	}
+	if other.TLS.ExtraCABundleSecret != "" {
+		g.TLS.ExtraCABundleSecret = other.TLS.ExtraCABundleSecret
+	}
+	// Merge installer config
+	if other.Installer != (InstallerConfig{}) {
+		g.Installer = other.Installer
+	}
+	// Merge agent auth config
+	if other.ClaudeAuth.IsEnabled() {
+		g.ClaudeAuth = other.ClaudeAuth
+	}
+	// Merge store config
+	if other.Store.Encrypt {
+		g.Store.Encrypt = true
+	}
+	if other.Store.KeySource != "" {
+		g.Store.KeySource = other.Store.KeySource
+	}
+	if other.Store.AgeRecipient != "" {
+		g.Store.AgeRecipient = other.Store.AgeRecipient
+	}
+	if other.Store.AgeIdentity != "" {
+		g.Store.AgeIdentity = other.Store.AgeIdentity
+	}
+	// Merge template values
+	if len(other.Values) > 0 {
+		g.Values = MergeValues(g.Values, other.Values)
+	}
+	// Merge completion images
+	if len(other.Images) > 0 {
+		g.Images = other.Images
+	}
+	// Merge per-context defaults
+	if len(other.Contexts) > 0 {
+		g.Contexts = other.Contexts
+	}
+	// Merge required labels
+	if len(other.RequiredLabels) > 0 {
+		g.RequiredLabels = other.RequiredLabels
+	}
+}
+
+// CheckRequiredLabels returns ErrMissingLabels naming the required label keys
+// that labels leaves unset or empty
+func (g *GlobalConfig) CheckRequiredLabels(labels map[string]string) error {
+	var missing []string
+	for _, key := range g.RequiredLabels {
+		if labels[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingLabels, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// ForContext returns the config with the defaults under contexts.<name>
+// merged over Defaults. g is returned unchanged when name has no entry.
+func (g *GlobalConfig) ForContext(name string) *GlobalConfig {
+	overrides, ok := g.Contexts[name]
+	if name == "" || !ok {
+		return g
+	}
+
+	resolved := *g
+	resolved.Defaults.Env.ExcludeVars = slices.Clone(g.Defaults.Env.ExcludeVars)
+	resolved.Merge(&GlobalConfig{Defaults: overrides})
+	return &resolved
 }
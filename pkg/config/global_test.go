@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/illumination-k/kodama/pkg/env"
 )
 
 func TestDefaultGlobalConfig(t *testing.T) {
@@ -86,3 +88,81 @@ func TestGlobalConfig_MergeEmpty(t *testing.T) {
 	assert.Equal(t, original.Defaults.Image, base.Defaults.Image)
 	assert.Equal(t, original.Defaults.Resources.CPU, base.Defaults.Resources.CPU)
 }
+
+func TestGlobalConfig_MergeSnapshot(t *testing.T) {
+	base := DefaultGlobalConfig()
+
+	base.Merge(&GlobalConfig{
+		Snapshot: SnapshotConfig{
+			Store:   "s3://team-bucket/kodama",
+			Exclude: []string{"node_modules"},
+		},
+	})
+
+	assert.Equal(t, "s3://team-bucket/kodama", base.Snapshot.Store)
+	assert.Equal(t, []string{"node_modules"}, base.Snapshot.Exclude)
+}
+
+func TestGlobalConfig_MergeProxyAndTLS(t *testing.T) {
+	base := DefaultGlobalConfig()
+	base.Proxy = ProxyConfig{HTTPProxy: "http://old:3128", NoProxy: "localhost"}
+
+	base.Merge(&GlobalConfig{TLS: TLSConfig{ExtraCABundleSecret: "corp-ca"}})
+	assert.Equal(t, "http://old:3128", base.Proxy.HTTPProxy, "unset proxy keeps the existing one")
+
+	base.Merge(&GlobalConfig{Proxy: ProxyConfig{HTTPSProxy: "http://proxy.corp:3128"}})
+	assert.Equal(t, ProxyConfig{HTTPSProxy: "http://proxy.corp:3128"}, base.Proxy, "proxy settings are replaced together")
+	assert.Equal(t, "corp-ca", base.TLS.ExtraCABundleSecret)
+}
+
+func TestGlobalConfig_ForContext(t *testing.T) {
+	base := DefaultGlobalConfig()
+	base.Merge(&GlobalConfig{
+		Defaults: DefaultsConfig{Env: env.EnvConfig{ExcludeVars: []string{"DEBUG"}}},
+		Contexts: map[string]DefaultsConfig{
+			"gpu-cluster": {
+				Namespace: "ml",
+				Image:     "ghcr.io/org/cuda:12",
+				Env:       env.EnvConfig{ExcludeVars: []string{"CUDA_HOME"}},
+			},
+		},
+	})
+	assert.Len(t, base.Contexts, 1, "Merge dropped contexts")
+
+	resolved := base.ForContext("gpu-cluster")
+	assert.Equal(t, "ml", resolved.Defaults.Namespace)
+	assert.Equal(t, "ghcr.io/org/cuda:12", resolved.Defaults.Image)
+	assert.Equal(t, "2Gi", resolved.Defaults.Resources.Memory)
+	assert.Equal(t, []string{"DEBUG", "CUDA_HOME"}, resolved.Defaults.Env.ExcludeVars)
+
+	assert.Equal(t, "default", base.Defaults.Namespace, "ForContext modified the base config")
+	assert.Equal(t, []string{"DEBUG"}, base.Defaults.Env.ExcludeVars)
+	assert.Same(t, base, base.ForContext("kind-dev"))
+	assert.Same(t, base, base.ForContext(""))
+}
+
+func TestInstallerConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  InstallerConfig
+		wantErr string
+	}{
+		{"default", InstallerConfig{}, ""},
+		{"download", InstallerConfig{Source: InstallerSourceDownload}, ""},
+		{"configmap", InstallerConfig{Source: InstallerSourceConfigMap, ConfigMap: "kodama-tools"}, ""},
+		{"pvc without name", InstallerConfig{Source: InstallerSourcePVC}, "requires installer.pvc"},
+		{"image without image", InstallerConfig{Source: InstallerSourceImage}, "requires installer.image"},
+		{"unknown", InstallerConfig{Source: "ftp"}, `unknown installer.source "ftp"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
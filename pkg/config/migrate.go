@@ -0,0 +1,125 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema versions of the files kodama reads and writes
+// Files without schemaVersion predate versioning and are treated as version 0.
+// Bump a version together with a migration when a field is renamed or moved,
+// so values in existing files are carried over instead of silently dropped.
+const (
+	SessionSchemaVersion = 1
+	GlobalSchemaVersion  = 1
+)
+
+// schemaVersionKey is the YAML key holding a file's schema version
+const schemaVersionKey = "schemaVersion"
+
+// ErrNewerSchema is returned when a file was written by a newer kodama
+// Such files are not loaded, so saving them again cannot drop unknown fields.
+var ErrNewerSchema = errors.New("written by a newer version of kodama")
+
+// Migration upgrades a YAML document from schema version From to From+1
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc *yaml.Node) error
+}
+
+// sessionMigrations upgrade session files and templates, in order
+var sessionMigrations = []Migration{
+	{From: 0, Description: "record schema version", Apply: func(*yaml.Node) error { return nil }},
+}
+
+// globalMigrations upgrade ~/.kodama/config.yaml, in order
+var globalMigrations = []Migration{
+	{From: 0, Description: "record schema version", Apply: func(*yaml.Node) error { return nil }},
+}
+
+// SchemaMigration describes a file rewritten by Store.MigrateSchema
+type SchemaMigration struct {
+	Path string
+	From int
+	To   int
+}
+
+// migrateDocument upgrades a YAML document to the current schema version
+// It returns the document's original version. Up-to-date and empty documents
+// are returned unchanged; migrated ones are re-encoded, keeping comments.
+func migrateDocument(data []byte, migrations []Migration, current int) ([]byte, int, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, 0, err
+	}
+	if root.Kind == 0 || len(root.Content) == 0 {
+		return data, current, nil
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return nil, 0, fmt.Errorf("expected a YAML mapping at the top level")
+	}
+
+	version := 0
+	if node := mappingValue(doc, schemaVersionKey); node != nil {
+		v, err := strconv.Atoi(node.Value)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid %s %q", schemaVersionKey, node.Value)
+		}
+		version = v
+	}
+	if version > current {
+		return nil, version, fmt.Errorf("%w (schema version %d, this kodama supports up to %d)", ErrNewerSchema, version, current)
+	}
+	if version == current {
+		return data, version, nil
+	}
+
+	for _, migration := range migrations {
+		if migration.From < version || migration.From >= current {
+			continue
+		}
+		if err := migration.Apply(doc); err != nil {
+			return nil, version, fmt.Errorf("schema migration from version %d (%s) failed: %w", migration.From, migration.Description, err)
+		}
+	}
+	setMappingValue(doc, schemaVersionKey, strconv.Itoa(current))
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&root); err != nil {
+		return nil, version, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, version, err
+	}
+	return buf.Bytes(), version, nil
+}
+
+// mappingValue returns the value node for key in a mapping node, or nil
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setMappingValue sets a scalar value in a mapping node, adding the key first if it is missing
+func setMappingValue(mapping *yaml.Node, key, value string) {
+	if node := mappingValue(mapping, key); node != nil {
+		node.Kind, node.Tag, node.Value, node.Content = yaml.ScalarNode, "", value, nil
+		return
+	}
+	mapping.Content = append([]*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: key},
+		{Kind: yaml.ScalarNode, Value: value},
+	}, mapping.Content...)
+}
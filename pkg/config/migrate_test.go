@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/illumination-k/kodama/pkg/encryption"
+)
+
+func TestMigrateDocument(t *testing.T) {
+	legacy := []byte("# team defaults\ndefaults:\n  namespace: dev # shared\n")
+
+	migrated, from, err := migrateDocument(legacy, globalMigrations, GlobalSchemaVersion)
+	require.NoError(t, err)
+	assert.Equal(t, 0, from)
+	assert.Contains(t, string(migrated), "schemaVersion: 1\n")
+	assert.Contains(t, string(migrated), "# team defaults")
+	assert.Contains(t, string(migrated), "namespace: dev # shared")
+
+	// Up-to-date documents are returned as they are
+	again, from, err := migrateDocument(migrated, globalMigrations, GlobalSchemaVersion)
+	require.NoError(t, err)
+	assert.Equal(t, GlobalSchemaVersion, from)
+	assert.Equal(t, migrated, again)
+
+	// Empty files have nothing to migrate
+	empty, from, err := migrateDocument(nil, globalMigrations, GlobalSchemaVersion)
+	require.NoError(t, err)
+	assert.Equal(t, GlobalSchemaVersion, from)
+	assert.Empty(t, empty)
+}
+
+func TestMigrateDocument_NewerSchema(t *testing.T) {
+	_, from, err := migrateDocument([]byte("schemaVersion: 99\nname: demo\n"), sessionMigrations, SessionSchemaVersion)
+	require.ErrorIs(t, err, ErrNewerSchema)
+	assert.Equal(t, 99, from)
+	assert.Contains(t, err.Error(), "schema version 99")
+}
+
+func TestMigrateDocument_RenamedField(t *testing.T) {
+	// A future rename: version 1 stored the image under "containerImage"
+	migrations := []Migration{
+		{From: 0, Description: "record schema version", Apply: func(*yaml.Node) error { return nil }},
+		{From: 1, Description: "rename containerImage to image", Apply: func(doc *yaml.Node) error {
+			if node := mappingValue(doc, "containerImage"); node != nil {
+				setMappingValue(doc, "image", node.Value)
+			}
+			return nil
+		}},
+	}
+
+	for _, input := range []string{
+		"name: demo\ncontainerImage: ubuntu:24.04\n",
+		"schemaVersion: 1\nname: demo\ncontainerImage: ubuntu:24.04\n",
+	} {
+		migrated, _, err := migrateDocument([]byte(input), migrations, 2)
+		require.NoError(t, err)
+
+		var session SessionConfig
+		require.NoError(t, yaml.Unmarshal(migrated, &session))
+		assert.Equal(t, "ubuntu:24.04", session.Image, input)
+		assert.Equal(t, 2, session.SchemaVersion, input)
+	}
+}
+
+func TestStore_NewerGlobalConfig(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, GlobalConfigFile), []byte("schemaVersion: 99\n"), 0o600))
+
+	// The store is created, but store.encrypt can't be checked, so writes fail
+	store := NewStoreWithPath(tmpDir)
+	store.configureEncryption()
+	err := store.SaveSession(&SessionConfig{Name: "demo", Namespace: "default"})
+	assert.ErrorIs(t, err, ErrNewerSchema)
+	assert.NoFileExists(t, sessionPath(t, store, "demo"))
+
+	_, err = store.LoadGlobalConfig()
+	assert.ErrorIs(t, err, ErrNewerSchema)
+}
+
+func TestStore_EncryptionKeyUnavailable(t *testing.T) {
+	t.Setenv("KODAMA_STORE_KEY", "")
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, GlobalConfigFile), []byte("store:\n  encrypt: true\n  keySource: vault\n"), 0o600))
+
+	store := NewStoreWithPath(tmpDir)
+	store.configureEncryption()
+
+	// Nothing is written in plaintext when encryption is requested
+	err := store.SaveSession(&SessionConfig{Name: "demo", Namespace: "default"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown store.keySource")
+	assert.NoFileExists(t, sessionPath(t, store, "demo"))
+}
+
+func TestStore_GlobalConfigUnreadable(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, GlobalConfigFile), []byte("store: [unterminated\n"), 0o600))
+
+	store := NewStoreWithPath(tmpDir)
+	store.configureEncryption()
+
+	// store.encrypt may be set in the unreadable config, so nothing is written
+	err := store.SaveSession(&SessionConfig{Name: "demo", Namespace: "default"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "store.encrypt could not be checked")
+	assert.NoFileExists(t, sessionPath(t, store, "demo"))
+}
+
+func TestStore_LoadLegacySession(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStoreWithPath(tmpDir)
+	require.NoError(t, store.EnsureConfigDir())
+	require.NoError(t, os.WriteFile(sessionPath(t, store, "old"), []byte("name: old\nnamespace: default\nstatus: Running\n"), 0o600))
+
+	session, err := store.LoadSession("old")
+	require.NoError(t, err)
+	assert.Equal(t, SessionSchemaVersion, session.SchemaVersion)
+	assert.Equal(t, StatusRunning, session.Status)
+
+	require.NoError(t, os.WriteFile(sessionPath(t, store, "future"), []byte("schemaVersion: 99\nname: future\nnamespace: default\n"), 0o600))
+	_, err = store.LoadSession("future")
+	assert.ErrorIs(t, err, ErrNewerSchema)
+}
+
+func TestStore_MigrateSchema(t *testing.T) {
+	t.Setenv("KODAMA_STORE_KEY", testStoreKey)
+	tmpDir := t.TempDir()
+	store := NewStoreWithPath(tmpDir)
+	require.NoError(t, store.EnsureConfigDir())
+
+	globalPath := store.GetGlobalConfigPath()
+	require.NoError(t, os.WriteFile(globalPath, []byte("# mine\ndefaults:\n  namespace: dev\n"), 0o600))
+	require.NoError(t, os.WriteFile(sessionPath(t, store, "old"), []byte("name: old\nnamespace: default\n"), 0o600))
+	require.NoError(t, store.SaveSession(&SessionConfig{Name: "current", Namespace: "default"}))
+
+	// Dry run reports without writing
+	migrations, err := store.MigrateSchema(true)
+	require.NoError(t, err)
+	assert.Equal(t, []SchemaMigration{
+		{Path: globalPath, From: 0, To: GlobalSchemaVersion},
+		{Path: sessionPath(t, store, "old"), From: 0, To: SessionSchemaVersion},
+	}, migrations)
+	raw, err := os.ReadFile(sessionPath(t, store, "old"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "schemaVersion")
+
+	// Encrypted stores keep session files encrypted
+	require.NoError(t, store.EnableEncryption())
+	migrations, err = store.MigrateSchema(false)
+	require.NoError(t, err)
+	assert.Len(t, migrations, 2)
+
+	raw, err = os.ReadFile(globalPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "# mine")
+	assert.Contains(t, string(raw), "schemaVersion: 1")
+
+	raw, err = os.ReadFile(filepath.Join(tmpDir, SessionsSubdir, "old.yaml"))
+	require.NoError(t, err)
+	assert.True(t, encryption.IsEncrypted(raw))
+
+	// Running again is a no-op
+	migrations, err = store.MigrateSchema(false)
+	require.NoError(t, err)
+	assert.Empty(t, migrations)
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// longestResourcePrefix is the longest prefix kodama adds to a session name
+// when naming Kubernetes resources (the file secret)
+const longestResourcePrefix = "kodama-secret-files-"
+
+// MaxSessionNameLength keeps every derived resource name, and the session
+// label value, within the 63 character limit of an RFC 1123 label
+const MaxSessionNameLength = 63 - len(longestResourcePrefix)
+
+// ErrInvalidSessionName is returned when a session name cannot be used in Kubernetes resource names
+var ErrInvalidSessionName = errors.New("invalid session name")
+
+var (
+	sessionNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	invalidNameChars   = regexp.MustCompile(`[^a-z0-9-]+`)
+	repeatedDashes     = regexp.MustCompile(`-{2,}`)
+)
+
+// ValidateSessionName checks that name is a lowercase RFC 1123 label short
+// enough to be used in every resource name derived from it, e.g. kodama-<name>
+// for the pod and kodama-secret-files-<name> for the file secret
+func ValidateSessionName(name string) error {
+	if name == "" {
+		return ErrSessionNameRequired
+	}
+
+	if len(name) > MaxSessionNameLength {
+		return fmt.Errorf("%w '%s': must be at most %d characters (got %d) so that resource names such as %s<name> stay within 63 characters",
+			ErrInvalidSessionName, name, MaxSessionNameLength, len(name), longestResourcePrefix)
+	}
+
+	if !sessionNamePattern.MatchString(name) {
+		return fmt.Errorf("%w '%s': must consist of lowercase letters, digits and '-', and start and end with a letter or digit (e.g. %s)",
+			ErrInvalidSessionName, name, suggestionFor(name))
+	}
+
+	return nil
+}
+
+// SanitizeSessionName converts name into a valid session name by lowercasing it,
+// replacing invalid characters with '-' and truncating it to MaxSessionNameLength.
+// It returns an empty string if nothing valid remains.
+func SanitizeSessionName(name string) string {
+	s := strings.ToLower(name)
+	s = invalidNameChars.ReplaceAllString(s, "-")
+	s = repeatedDashes.ReplaceAllString(s, "-")
+	s = strings.Trim(s, "-")
+	if len(s) > MaxSessionNameLength {
+		s = strings.TrimRight(s[:MaxSessionNameLength], "-")
+	}
+	return s
+}
+
+// suggestionFor returns a sanitized name to show in errors, or a generic example
+func suggestionFor(name string) string {
+	if s := SanitizeSessionName(name); s != "" {
+		return "'" + s + "'"
+	}
+	return "'my-session'"
+}
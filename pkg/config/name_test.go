@@ -0,0 +1,70 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateSessionName(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantError bool
+	}{
+		{name: "simple", input: "my-work", wantError: false},
+		{name: "digits", input: "task-42", wantError: false},
+		{name: "max length", input: strings.Repeat("a", MaxSessionNameLength), wantError: false},
+		{name: "empty", input: "", wantError: true},
+		{name: "too long", input: strings.Repeat("a", MaxSessionNameLength+1), wantError: true},
+		{name: "uppercase", input: "MyWork", wantError: true},
+		{name: "underscore", input: "my_work", wantError: true},
+		{name: "dot", input: "my.work", wantError: true},
+		{name: "leading dash", input: "-work", wantError: true},
+		{name: "trailing dash", input: "work-", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSessionName(tt.input)
+			if tt.wantError && err == nil {
+				t.Errorf("Expected error for %q, got nil", tt.input)
+			}
+			if !tt.wantError && err != nil {
+				t.Errorf("Unexpected error for %q: %v", tt.input, err)
+			}
+		})
+	}
+
+	if err := ValidateSessionName("My_Work"); !errors.Is(err, ErrInvalidSessionName) || !strings.Contains(err.Error(), "'my-work'") {
+		t.Errorf("Expected ErrInvalidSessionName suggesting 'my-work', got: %v", err)
+	}
+}
+
+func TestSanitizeSessionName(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{input: "my-work", want: "my-work"},
+		{input: "My_Work", want: "my-work"},
+		{input: "feature/JIRA-123 fix", want: "feature-jira-123-fix"},
+		{input: "--a..b--", want: "a-b"},
+		{input: "___", want: ""},
+		{input: strings.Repeat("ab-", 30), want: strings.TrimRight(strings.Repeat("ab-", 30)[:MaxSessionNameLength], "-")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got := SanitizeSessionName(tt.input)
+			if got != tt.want {
+				t.Errorf("SanitizeSessionName(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+			if got != "" {
+				if err := ValidateSessionName(got); err != nil {
+					t.Errorf("Sanitized name %q is invalid: %v", got, err)
+				}
+			}
+		})
+	}
+}
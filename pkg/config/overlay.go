@@ -0,0 +1,46 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ApplyOverlay appends the overlay called name from each template of an
+// extends chain (base first) to the chain, so the overlays are merged after
+// every template. It fails when no template defines the overlay.
+func ApplyOverlay(templates []*SessionConfig, name string) ([]*SessionConfig, error) {
+	if name == "" {
+		return templates, nil
+	}
+
+	var overlays []*SessionConfig
+	available := map[string]bool{}
+	for _, template := range templates {
+		for overlayName := range template.Overlays {
+			available[overlayName] = true
+		}
+		overlay, ok := template.Overlays[name]
+		if !ok || overlay == nil {
+			continue
+		}
+		if overlay.Extends != "" || len(overlay.Overlays) > 0 {
+			return nil, fmt.Errorf("overlay %q cannot set extends or overlays", name)
+		}
+		overlays = append(overlays, overlay)
+	}
+
+	if len(overlays) == 0 {
+		if len(available) == 0 {
+			return nil, fmt.Errorf("overlay %q not found: the session template defines no overlays", name)
+		}
+		names := make([]string, 0, len(available))
+		for overlayName := range available {
+			names = append(names, overlayName)
+		}
+		slices.Sort(names)
+		return nil, fmt.Errorf("overlay %q not found (available: %s)", name, strings.Join(names, ", "))
+	}
+
+	return append(slices.Clone(templates), overlays...), nil
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyOverlay(t *testing.T) {
+	staging := &SessionConfig{Namespace: "staging"}
+	baseStaging := &SessionConfig{Image: "app:rc"}
+	base := &SessionConfig{Image: "app:1", Overlays: map[string]*SessionConfig{"staging": baseStaging}}
+	service := &SessionConfig{
+		Namespace: "dev",
+		Overlays:  map[string]*SessionConfig{"staging": staging, "prod": {Namespace: "prod"}},
+	}
+
+	chain, err := ApplyOverlay([]*SessionConfig{base, service}, "staging")
+	require.NoError(t, err)
+	assert.Equal(t, []*SessionConfig{base, service, baseStaging, staging}, chain)
+
+	resolved := NewConfigResolver(DefaultGlobalConfig(), chain...).Resolve()
+	assert.Equal(t, "staging", resolved.Namespace)
+	assert.Equal(t, "app:rc", resolved.Image)
+
+	chain, err = ApplyOverlay([]*SessionConfig{base, service}, "")
+	require.NoError(t, err)
+	assert.Len(t, chain, 2)
+
+	_, err = ApplyOverlay([]*SessionConfig{base, service}, "qa")
+	assert.ErrorContains(t, err, "available: prod, staging")
+
+	_, err = ApplyOverlay([]*SessionConfig{{}}, "qa")
+	assert.ErrorContains(t, err, "defines no overlays")
+
+	nested := &SessionConfig{Overlays: map[string]*SessionConfig{"qa": {Extends: "base.yaml"}}}
+	_, err = ApplyOverlay([]*SessionConfig{nested}, "qa")
+	assert.ErrorContains(t, err, "cannot set extends")
+}
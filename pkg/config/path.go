@@ -1,9 +1,11 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -37,13 +39,66 @@ func ResolvePath(path string) (string, error) {
 	return absPath, nil
 }
 
+// TemplatePath resolves a template name to <dir>/<name>.yaml
+// Names with path separators or a leading dot are rejected, so a name taken
+// from a request can't reach files outside dir.
+func TemplatePath(dir, name string) (string, error) {
+	if dir == "" {
+		return "", errors.New("no templates directory configured")
+	}
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("invalid template name '%s'", name)
+	}
+
+	path := filepath.Join(dir, name+".yaml")
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("template '%s' not found", name)
+	}
+	return path, nil
+}
+
+// SyncDirection controls which way a custom directory is synced
+type SyncDirection string
+
+const (
+	// SyncDirectionPush copies the local source into the pod when the session starts (default)
+	SyncDirectionPush SyncDirection = "push"
+
+	// SyncDirectionPull copies the pod destination back to the local source when the session is deleted
+	SyncDirectionPull SyncDirection = "pull"
+
+	// SyncDirectionBoth pushes on start and pulls on delete
+	SyncDirectionBoth SyncDirection = "both"
+)
+
+var (
+	// chownPattern matches user[:group] by name or numeric id
+	chownPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]*(:[a-zA-Z0-9_][a-zA-Z0-9_.-]*)?$`)
+
+	// chmodPattern matches an octal file mode such as 600 or 0644
+	chmodPattern = regexp.MustCompile(`^0?[0-7]{3}$`)
+)
+
 // CustomDirSync represents a custom directory sync configuration
 type CustomDirSync struct {
-	Source       string   `yaml:"source"`
-	Destination  string   `yaml:"destination"`
-	Exclude      []string `yaml:"exclude,omitempty"`
-	UseGitignore *bool    `yaml:"useGitignore,omitempty"`
-	Recursive    bool     `yaml:"recursive,omitempty"`
+	Source       string        `yaml:"source"`
+	Destination  string        `yaml:"destination"`
+	Exclude      []string      `yaml:"exclude,omitempty"`
+	UseGitignore *bool         `yaml:"useGitignore,omitempty"`
+	Recursive    bool          `yaml:"recursive,omitempty"`
+	Chown        string        `yaml:"chown,omitempty"`     // Owner of pushed files in the pod, e.g. "1000:1000" or "dev"
+	Chmod        string        `yaml:"chmod,omitempty"`     // Octal mode for pushed files (not directories), e.g. "0600"
+	Direction    SyncDirection `yaml:"direction,omitempty"` // push (default), pull or both
+}
+
+// Pushes reports whether the directory is copied into the pod on start
+func (c *CustomDirSync) Pushes() bool {
+	return c.Direction == "" || c.Direction == SyncDirectionPush || c.Direction == SyncDirectionBoth
+}
+
+// Pulls reports whether the directory is copied back from the pod on delete
+func (c *CustomDirSync) Pulls() bool {
+	return c.Direction == SyncDirectionPull || c.Direction == SyncDirectionBoth
 }
 
 // Validate checks if the custom directory sync configuration is valid
@@ -61,12 +116,34 @@ func (c *CustomDirSync) Validate() error {
 		return fmt.Errorf("destination must be an absolute path, got: %s", c.Destination)
 	}
 
+	switch c.Direction {
+	case "", SyncDirectionPush, SyncDirectionPull, SyncDirectionBoth:
+	default:
+		return fmt.Errorf("invalid direction: %s (must be push, pull or both)", c.Direction)
+	}
+
+	if c.Chown != "" && !chownPattern.MatchString(c.Chown) {
+		return fmt.Errorf("invalid chown: %s (must be user or user:group)", c.Chown)
+	}
+
+	if c.Chmod != "" && !chmodPattern.MatchString(c.Chmod) {
+		return fmt.Errorf("invalid chmod: %s (must be an octal mode such as 0600)", c.Chmod)
+	}
+
 	// Resolve source path
 	resolvedSource, err := ResolvePath(c.Source)
 	if err != nil {
 		return fmt.Errorf("failed to resolve source path: %w", err)
 	}
 
+	// A pull-only source is created locally when it is pulled
+	if !c.Pushes() {
+		if c.Recursive {
+			return fmt.Errorf("recursive is not supported with direction pull")
+		}
+		return nil
+	}
+
 	// Check if source exists
 	info, err := os.Stat(resolvedSource)
 	if err != nil {
@@ -72,6 +72,27 @@ func TestResolvePath(t *testing.T) {
 	}
 }
 
+func TestTemplatePath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ok.yaml"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := TemplatePath(dir, "ok")
+	if err != nil || path != filepath.Join(dir, "ok.yaml") {
+		t.Errorf("TemplatePath(ok) = %q, %v", path, err)
+	}
+
+	for _, name := range []string{"", "missing", "../ok", ".hidden", "a/b", "/etc/passwd"} {
+		if _, err := TemplatePath(dir, name); err == nil {
+			t.Errorf("TemplatePath(%q) expected error", name)
+		}
+	}
+	if _, err := TemplatePath("", "ok"); err == nil {
+		t.Error("TemplatePath without a directory expected error")
+	}
+}
+
 func TestCustomDirSync_Validate(t *testing.T) {
 	// Create a temporary directory for testing
 	tmpDir := t.TempDir()
@@ -143,6 +164,71 @@ func TestCustomDirSync_Validate(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "ownership and mode",
+			customDir: CustomDirSync{
+				Source:      tmpFile,
+				Destination: "/home/dev/.ssh/id_ed25519",
+				Chown:       "1000:1000",
+				Chmod:       "0600",
+			},
+			wantError: false,
+		},
+		{
+			name: "invalid chown",
+			customDir: CustomDirSync{
+				Source:      tmpFile,
+				Destination: "/root/.bashrc",
+				Chown:       "dev; rm -rf /",
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid chmod",
+			customDir: CustomDirSync{
+				Source:      tmpFile,
+				Destination: "/root/.bashrc",
+				Chmod:       "u+x",
+			},
+			wantError: true,
+		},
+		{
+			name: "invalid direction",
+			customDir: CustomDirSync{
+				Source:      tmpFile,
+				Destination: "/root/.bashrc",
+				Direction:   "sideways",
+			},
+			wantError: true,
+		},
+		{
+			name: "pull with non-existent source",
+			customDir: CustomDirSync{
+				Source:      filepath.Join(tmpDir, "pulled"),
+				Destination: "/root/.config/gh",
+				Direction:   SyncDirectionPull,
+			},
+			wantError: false,
+		},
+		{
+			name: "both with non-existent source",
+			customDir: CustomDirSync{
+				Source:      filepath.Join(tmpDir, "pulled"),
+				Destination: "/root/.config/gh",
+				Direction:   SyncDirectionBoth,
+			},
+			wantError: true,
+		},
+		{
+			name: "recursive pull",
+			customDir: CustomDirSync{
+				Source:      tmpDir,
+				Destination: "/root/.config",
+				Direction:   SyncDirectionPull,
+				Recursive:   true,
+			},
+			wantError: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,121 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TaskPlan is a list of agent tasks run one after another in a session
+type TaskPlan struct {
+	Steps []PlanStep `yaml:"steps"`
+}
+
+// PlanStep is one agent task of a TaskPlan. A failed step stops the plan,
+// unless continueOnError moves on to the next step or onError names a later
+// step to continue with, e.g. one that repairs the build. Steps named by
+// onError only run when jumped to.
+type PlanStep struct {
+	Name       string `yaml:"name"`
+	Prompt     string `yaml:"prompt,omitempty"`
+	PromptFile string `yaml:"promptFile,omitempty"` // Relative to the plan file
+
+	ContinueOnError bool   `yaml:"continueOnError,omitempty"`
+	OnError         string `yaml:"onError,omitempty"` // Step to jump to when this one fails
+}
+
+// LoadTaskPlan reads and validates a plan file, reading prompt files relative
+// to it into Prompt
+func LoadTaskPlan(path string) (*TaskPlan, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- plan file given by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read plan: %w", err)
+	}
+	var plan TaskPlan
+	if err := yaml.Unmarshal(data, &plan); err != nil {
+		return nil, fmt.Errorf("failed to parse plan %s: %w", path, err)
+	}
+
+	for i := range plan.Steps {
+		step := &plan.Steps[i]
+		if step.PromptFile == "" {
+			continue
+		}
+		if step.Prompt != "" {
+			return nil, fmt.Errorf("plan step %q: set prompt or promptFile, not both", step.Name)
+		}
+		promptFile := step.PromptFile
+		if !filepath.IsAbs(promptFile) {
+			promptFile = filepath.Join(filepath.Dir(path), promptFile)
+		}
+		if step.Prompt, err = ReadPromptFromFile(promptFile); err != nil {
+			return nil, fmt.Errorf("plan step %q: %w", step.Name, err)
+		}
+	}
+	if err := plan.Validate(); err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+// Validate checks that steps have unique names and prompts, and that onError
+// names a later step, so a plan always ends
+func (p *TaskPlan) Validate() error {
+	if len(p.Steps) == 0 {
+		return fmt.Errorf("plan has no steps")
+	}
+	index := make(map[string]int, len(p.Steps))
+	for i, step := range p.Steps {
+		if step.Name == "" {
+			return fmt.Errorf("plan step %d has no name", i+1)
+		}
+		if _, ok := index[step.Name]; ok {
+			return fmt.Errorf("plan step %q is defined twice", step.Name)
+		}
+		if step.Prompt == "" {
+			return fmt.Errorf("plan step %q has no prompt", step.Name)
+		}
+		index[step.Name] = i
+	}
+	for i, step := range p.Steps {
+		if step.OnError == "" {
+			continue
+		}
+		if step.ContinueOnError {
+			return fmt.Errorf("plan step %q: set continueOnError or onError, not both", step.Name)
+		}
+		if target, ok := index[step.OnError]; !ok || target <= i {
+			return fmt.Errorf("plan step %q: onError must name a later step (got %q)", step.Name, step.OnError)
+		}
+	}
+	return nil
+}
+
+// Next returns the index of the step to run after step i, or len(p.Steps) when
+// the plan is done. stop is true when step i failed without an error policy.
+func (p *TaskPlan) Next(i int, failed bool) (next int, stop bool) {
+	step := p.Steps[i]
+	if failed && !step.ContinueOnError {
+		for j := i + 1; j < len(p.Steps) && step.OnError != ""; j++ {
+			if p.Steps[j].Name == step.OnError {
+				return j, false
+			}
+		}
+		return len(p.Steps), true
+	}
+
+	// Error handlers are skipped in the normal flow
+	handlers := make(map[string]bool)
+	for _, s := range p.Steps {
+		if s.OnError != "" {
+			handlers[s.OnError] = true
+		}
+	}
+	next = i + 1
+	for next < len(p.Steps) && handlers[p.Steps[next].Name] {
+		next++
+	}
+	return next, false
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadTaskPlan(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "prompts"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prompts", "migrate.md"), []byte("Migrate the schema\n"), 0o600))
+	path := filepath.Join(dir, "plan.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`steps:
+  - name: migrate
+    promptFile: prompts/migrate.md
+    onError: fix
+  - name: docs
+    prompt: Update the docs
+    continueOnError: true
+  - name: fix
+    prompt: Fix what failed
+`), 0o600))
+
+	plan, err := LoadTaskPlan(path)
+	require.NoError(t, err)
+	require.Len(t, plan.Steps, 3)
+	assert.Equal(t, "Migrate the schema\n", plan.Steps[0].Prompt)
+	assert.Equal(t, "fix", plan.Steps[0].OnError)
+	assert.True(t, plan.Steps[1].ContinueOnError)
+
+	_, err = LoadTaskPlan(filepath.Join(dir, "missing.yaml"))
+	assert.ErrorContains(t, err, "failed to read plan")
+}
+
+func TestTaskPlan_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		steps   []PlanStep
+		wantErr string
+	}{
+		{"empty", nil, "no steps"},
+		{"unnamed", []PlanStep{{Prompt: "a"}}, "has no name"},
+		{"duplicate", []PlanStep{{Name: "a", Prompt: "a"}, {Name: "a", Prompt: "b"}}, "defined twice"},
+		{"no prompt", []PlanStep{{Name: "a"}}, "has no prompt"},
+		{"backward jump", []PlanStep{{Name: "a", Prompt: "a"}, {Name: "b", Prompt: "b", OnError: "a"}}, "onError must name a later step"},
+		{"unknown jump", []PlanStep{{Name: "a", Prompt: "a", OnError: "x"}}, "onError must name a later step"},
+		{"both policies", []PlanStep{{Name: "a", Prompt: "a", OnError: "b", ContinueOnError: true}, {Name: "b", Prompt: "b"}}, "not both"},
+		{"valid", []PlanStep{{Name: "a", Prompt: "a", OnError: "b"}, {Name: "b", Prompt: "b"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := (&TaskPlan{Steps: tt.steps}).Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTaskPlan_Next(t *testing.T) {
+	plan := &TaskPlan{Steps: []PlanStep{
+		{Name: "build", Prompt: "a", OnError: "fix"},
+		{Name: "lint", Prompt: "b", ContinueOnError: true},
+		{Name: "fix", Prompt: "c"},
+		{Name: "docs", Prompt: "d"},
+	}}
+
+	next, stop := plan.Next(0, false)
+	assert.Equal(t, 1, next)
+	assert.False(t, stop)
+
+	// The error handler is skipped when nothing failed
+	next, stop = plan.Next(1, false)
+	assert.Equal(t, 3, next)
+	assert.False(t, stop)
+
+	next, stop = plan.Next(0, true)
+	assert.Equal(t, 2, next, "a failed step jumps to its handler")
+	assert.False(t, stop)
+
+	next, stop = plan.Next(1, true)
+	assert.Equal(t, 3, next, "continueOnError moves on")
+	assert.False(t, stop)
+
+	next, stop = plan.Next(3, true)
+	assert.Equal(t, 4, next)
+	assert.True(t, stop)
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SessionSort is a field sessions can be sorted by
+type SessionSort string
+
+const (
+	SortByName      SessionSort = "name"
+	SortByCreated   SessionSort = "created" // Newest first
+	SortByUpdated   SessionSort = "updated" // Most recently updated first
+	SortByStatus    SessionSort = "status"
+	SortByNamespace SessionSort = "namespace"
+)
+
+// SessionQuery filters and orders sessions. Zero values match everything.
+type SessionQuery struct {
+	Namespace string
+	Status    SessionStatus     // Compared case-insensitively with the stored status
+	Labels    map[string]string // All labels must match
+	SortBy    SessionSort       // Default: name
+}
+
+// Validate checks the sort field
+func (q SessionQuery) Validate() error {
+	switch q.SortBy {
+	case "", SortByName, SortByCreated, SortByUpdated, SortByStatus, SortByNamespace:
+		return nil
+	default:
+		return fmt.Errorf("invalid sort field: %s (must be name, created, updated, status or namespace)", q.SortBy)
+	}
+}
+
+// Matches reports whether session satisfies the query filters
+func (q SessionQuery) Matches(session *SessionConfig) bool {
+	if q.Namespace != "" && session.Namespace != q.Namespace {
+		return false
+	}
+	if q.Status != "" && !strings.EqualFold(string(session.Status), string(q.Status)) {
+		return false
+	}
+	for key, value := range q.Labels {
+		if actual, ok := session.Labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply returns the sessions that match the query, in query order
+func (q SessionQuery) Apply(sessions []*SessionConfig) []*SessionConfig {
+	result := make([]*SessionConfig, 0, len(sessions))
+	for _, session := range sessions {
+		if q.Matches(session) {
+			result = append(result, session)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b *SessionConfig) int {
+		switch q.SortBy {
+		case SortByCreated:
+			if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+				return c
+			}
+		case SortByUpdated:
+			if c := b.UpdatedAt.Compare(a.UpdatedAt); c != 0 {
+				return c
+			}
+		case SortByStatus:
+			if c := strings.Compare(string(a.Status), string(b.Status)); c != 0 {
+				return c
+			}
+		case SortByNamespace:
+			if c := strings.Compare(a.Namespace, b.Namespace); c != 0 {
+				return c
+			}
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return result
+}
+
+// ParseLabels parses key=value pairs, as given to --label
+func ParseLabels(pairs []string) (map[string]string, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	labels := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label format: %s (expected format: key=value, e.g., team=payments)", pair)
+		}
+		labels[key] = value
+	}
+	return labels, nil
+}
+
+// SessionsForDirectory returns the sessions syncing dir or its closest synced
+// ancestor, so a command run anywhere inside a synced project finds its session.
+// dir and the stored sync paths must be absolute.
+func SessionsForDirectory(sessions []*SessionConfig, dir string) []*SessionConfig {
+	var matches []*SessionConfig
+	best := -1
+	for _, session := range sessions {
+		if !session.Sync.Enabled || session.Sync.LocalPath == "" {
+			continue
+		}
+		root := filepath.Clean(session.Sync.LocalPath)
+		rel, err := filepath.Rel(root, dir)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		switch depth := len(root); {
+		case depth > best:
+			best, matches = depth, []*SessionConfig{session}
+		case depth == best:
+			matches = append(matches, session)
+		}
+	}
+	return matches
+}
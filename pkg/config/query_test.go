@@ -0,0 +1,106 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionQuery_Apply(t *testing.T) {
+	now := time.Now()
+	sessions := []*SessionConfig{
+		{Name: "b", Namespace: "dev", Status: StatusRunning, CreatedAt: now.Add(-2 * time.Hour), Labels: map[string]string{"team": "payments"}},
+		{Name: "a", Namespace: "prod", Status: StatusFailed, CreatedAt: now.Add(-1 * time.Hour)},
+		{Name: "c", Namespace: "dev", Status: StatusStopped, CreatedAt: now, Labels: map[string]string{"team": "payments", "tier": "backend"}},
+	}
+
+	names := func(sessions []*SessionConfig) []string {
+		result := make([]string, 0, len(sessions))
+		for _, s := range sessions {
+			result = append(result, s.Name)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name  string
+		query SessionQuery
+		want  []string
+	}{
+		{name: "all sorted by name", query: SessionQuery{}, want: []string{"a", "b", "c"}},
+		{name: "namespace", query: SessionQuery{Namespace: "dev"}, want: []string{"b", "c"}},
+		{name: "status is case-insensitive", query: SessionQuery{Status: "running"}, want: []string{"b"}},
+		{name: "single label", query: SessionQuery{Labels: map[string]string{"team": "payments"}}, want: []string{"b", "c"}},
+		{name: "all labels must match", query: SessionQuery{Labels: map[string]string{"team": "payments", "tier": "backend"}}, want: []string{"c"}},
+		{name: "newest first", query: SessionQuery{SortBy: SortByCreated}, want: []string{"c", "a", "b"}},
+		{name: "by namespace then name", query: SessionQuery{SortBy: SortByNamespace}, want: []string{"b", "c", "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, names(tt.query.Apply(sessions)))
+		})
+	}
+}
+
+func TestSessionQuery_Validate(t *testing.T) {
+	assert.NoError(t, SessionQuery{SortBy: SortByUpdated}.Validate())
+	assert.Error(t, SessionQuery{SortBy: "size"}.Validate())
+}
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels([]string{"team=payments", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"team": "payments", "empty": ""}, labels)
+
+	_, err = ParseLabels([]string{"team"})
+	assert.Error(t, err)
+
+	_, err = ParseLabels([]string{"=payments"})
+	assert.Error(t, err)
+}
+
+func TestStore_QuerySessions(t *testing.T) {
+	store := NewStoreWithPath(t.TempDir())
+	require.NoError(t, store.SaveSession(&SessionConfig{Name: "a", Namespace: "dev", Labels: map[string]string{"team": "payments"}}))
+	require.NoError(t, store.SaveSession(&SessionConfig{Name: "b", Namespace: "dev"}))
+
+	sessions, err := store.QuerySessions(SessionQuery{Labels: map[string]string{"team": "payments"}})
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, "a", sessions[0].Name)
+
+	_, err = store.QuerySessions(SessionQuery{SortBy: "size"})
+	assert.Error(t, err)
+}
+
+func TestSessionsForDirectory(t *testing.T) {
+	synced := func(name, path string) *SessionConfig {
+		return &SessionConfig{Name: name, Sync: SyncConfig{Enabled: true, LocalPath: path}}
+	}
+	sessions := []*SessionConfig{
+		synced("project", "/src/project"),
+		synced("frontend", "/src/project/web"),
+		synced("other", "/src/project-b"),
+		{Name: "repo", Repo: "github.com/example/repo"},
+	}
+
+	names := func(dir string) []string {
+		var result []string
+		for _, session := range SessionsForDirectory(sessions, dir) {
+			result = append(result, session.Name)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"project"}, names("/src/project"))
+	assert.Equal(t, []string{"project"}, names("/src/project/api/handlers"))
+	assert.Equal(t, []string{"frontend"}, names("/src/project/web/src"), "closest ancestor wins")
+	assert.Equal(t, []string{"other"}, names("/src/project-b"), "sibling with a shared prefix does not match")
+	assert.Empty(t, names("/src"))
+
+	sessions = append(sessions, synced("project-2", "/src/project/"))
+	assert.Equal(t, []string{"project", "project-2"}, names("/src/project/docs"))
+}
@@ -1,6 +1,10 @@
 package config
 
-import "github.com/illumination-k/kodama/pkg/secretfile"
+import (
+	"maps"
+
+	"github.com/illumination-k/kodama/pkg/secretfile"
+)
 
 // ResolvedConfig represents the merged configuration from global and template sources
 // This does NOT include CLI flags, which are applied at the usecase layer
@@ -15,6 +19,8 @@ type ResolvedConfig struct {
 	CloneDepth      int
 	SingleBranch    bool
 	GitCloneArgs    string
+	CloneAttempts   int
+	CloneMirror     string
 	Repo            string
 	Command         string
 
@@ -25,40 +31,124 @@ type ResolvedConfig struct {
 	TtydWritable bool
 
 	// Sync config (from template only, but fallback to global)
+	SyncInclude      []string
+	SyncLocalPath    string // Default for --sync (template only)
+	SyncRemotePath   string // Default for --remote-path (template only)
 	SyncExclude      []string
 	SyncUseGitignore *bool
 	SyncCustomDirs   []CustomDirSync
+	SyncCompression  string
 
 	// Storage (from global only)
 	StorageWorkspace  string
 	StorageClaudeHome string
 	BranchPrefix      string
 
+	// Commit author in the pod (template fields override global)
+	GitIdentity GitIdentityConfig
+
+	// Commit signing (template replaces global)
+	GitSigning GitSigningConfig
+
+	// Git hook installation (template enables it; template hooks replace global ones)
+	Git GitConfig
+
+	// Dotfiles repository installed after start (template replaces global)
+	Dotfiles DotfilesConfig
+
 	// Env config (merged from template and global)
 	EnvDotenvFiles []string
 	EnvExcludeVars []string
 
 	// Secret file config (template completely replaces global)
 	SecretFileMappings []secretfile.FileMapping
+
+	// Shared dependency cache PVC (template overrides global)
+	CachePVC string
+
+	// Shared scratch volume (global and template each enable it, template fields override)
+	Shared SharedConfig
+
+	// Proxy and extra CA bundle for in-pod tooling (global only)
+	Proxy ProxyConfig
+	TLS   TLSConfig
+
+	// Source of Claude Code and ttyd binaries (global only)
+	Installer InstallerConfig
+
+	// Eviction protection (global and template each enable it)
+	Disruption DisruptionConfig
+
+	// Shell opened by attach and ttyd (template overrides global)
+	Shell string
+
+	// Locale of the pod, attach and ttyd (template overrides global)
+	Locale string
+
+	// Terminal recording (template only)
+	Record bool
+
+	// Spot node scheduling with workspace checkpoints (template only)
+	SpotFriendly bool
+
+	// Pod restart policy (template only)
+	RestartPolicy string
+
+	// Process watchdog in the main container (template only)
+	Watchdog bool
+
+	// Env and file secrets owned by the pod (global or template)
+	SealedSecrets bool
+
+	// Test command for 'kodama test' (template only)
+	TestCommand string
+
+	// Session labels for filtering (template only)
+	Labels map[string]string
+
+	// PodSpec fields patched onto the generated pod (template only)
+	PodOverrides map[string]interface{}
+
+	// Existing volumes mounted into the pod (template only)
+	Volumes []VolumeConfig
+
+	// Dataset fetched into the workspace (template only)
+	Data DataConfig
+
+	// Toolchains installed with mise (template only)
+	Tools []string
+
+	// Writable copy of the workspace for the agent (template only)
+	Sandbox bool
+
+	// Editor settings (template fields override global)
+	Editor EditorConfig
+
+	// Agent guardrails (template protected paths add to global)
+	Agent AgentConfig
 }
 
 // ConfigResolver merges global and template configurations
 type ConfigResolver struct {
-	global   *GlobalConfig
-	template *SessionConfig
+	global    *GlobalConfig
+	templates []*SessionConfig
 }
 
 // NewConfigResolver creates a new ConfigResolver
-// global must not be nil, template can be nil if no template config is provided
-func NewConfigResolver(global *GlobalConfig, template *SessionConfig) *ConfigResolver {
-	return &ConfigResolver{
-		global:   global,
-		template: template,
+// global must not be nil. templates is an extends chain, base first, as
+// returned by Store.LoadSessionTemplates; nil templates are skipped.
+func NewConfigResolver(global *GlobalConfig, templates ...*SessionConfig) *ConfigResolver {
+	r := &ConfigResolver{global: global}
+	for _, template := range templates {
+		if template != nil {
+			r.templates = append(r.templates, template)
+		}
 	}
+	return r
 }
 
 // Resolve merges global and template configs with the following priority:
-// Template > Global > Hardcoded defaults
+// Template > Base templates it extends > Global > Hardcoded defaults
 // Returns a ResolvedConfig that can be further overridden by CLI flags at the usecase layer
 func (r *ConfigResolver) Resolve() *ResolvedConfig {
 	resolved := &ResolvedConfig{
@@ -96,11 +186,20 @@ func (r *ConfigResolver) Resolve() *ResolvedConfig {
 	resolved.StorageWorkspace = r.global.Defaults.Storage.Workspace
 	resolved.StorageClaudeHome = r.global.Defaults.Storage.ClaudeHome
 	resolved.BranchPrefix = r.global.Defaults.BranchPrefix
+	resolved.Shell = r.global.Defaults.Shell
+	resolved.Locale = r.global.Defaults.Locale
+	resolved.SealedSecrets = r.global.Defaults.SealedSecrets
+	resolved.GitIdentity = r.global.Defaults.GitIdentity
+	resolved.GitSigning = r.global.Defaults.GitSigning
+	resolved.Git = r.global.Defaults.Git
+	resolved.Dotfiles = r.global.Defaults.Dotfiles
 
 	// Sync config from global
+	resolved.SyncInclude = r.global.Sync.Include
 	resolved.SyncExclude = r.global.Sync.Exclude
 	resolved.SyncUseGitignore = r.global.Sync.UseGitignore
 	resolved.SyncCustomDirs = r.global.Sync.CustomDirs
+	resolved.SyncCompression = r.global.Sync.Compression
 
 	// Env config from global
 	resolved.EnvDotenvFiles = r.global.Defaults.Env.DotenvFiles
@@ -109,77 +208,169 @@ func (r *ConfigResolver) Resolve() *ResolvedConfig {
 	// Secret file config from global
 	resolved.SecretFileMappings = r.global.Defaults.SecretFile.Files
 
-	// Layer 2: Apply template config (overrides global)
-	if r.template != nil {
-		// Apply string fields using coalesce
-		resolved.Namespace = CoalesceString(r.template.Namespace, resolved.Namespace)
-		resolved.Image = CoalesceString(r.template.Image, resolved.Image)
-		resolved.CPU = CoalesceString(r.template.Resources.CPU, resolved.CPU)
-		resolved.Memory = CoalesceString(r.template.Resources.Memory, resolved.Memory)
-		resolved.Branch = CoalesceString(r.template.Branch, resolved.Branch)
-		resolved.GitCloneArgs = CoalesceString(r.template.GitClone.ExtraArgs, resolved.GitCloneArgs)
-		resolved.Repo = CoalesceString(r.template.Repo, resolved.Repo)
-
-		// Apply int fields
-		resolved.CloneDepth = CoalesceInt(r.template.GitClone.Depth, resolved.CloneDepth)
-		resolved.TtydPort = CoalesceInt(r.template.Ttyd.Port, resolved.TtydPort)
-
-		// Apply bool fields (SingleBranch: true means explicitly set)
-		resolved.SingleBranch = CoalesceBool(r.template.GitClone.SingleBranch, resolved.SingleBranch, r.template.GitClone.SingleBranch)
-
-		// Apply *bool fields (nil check required)
-		if r.template.Ttyd.Enabled != nil {
-			resolved.TtydEnabled = *r.template.Ttyd.Enabled
-		}
-		if r.template.Ttyd.Writable != nil {
-			resolved.TtydWritable = *r.template.Ttyd.Writable
-		}
+	// Cache config from global
+	resolved.CachePVC = r.global.Defaults.Cache.PVC
 
-		// Apply ttyd options
-		resolved.TtydOptions = CoalesceString(r.template.Ttyd.Options, resolved.TtydOptions)
+	// Shared scratch volume from global
+	resolved.Shared = r.global.Defaults.Shared
 
-		// Custom resources: template completely replaces global (not merged)
-		if r.template.Resources.CustomResources != nil {
-			resolved.CustomResources = make(map[string]string)
-			for k, v := range r.template.Resources.CustomResources {
-				resolved.CustomResources[k] = v
-			}
-		}
+	// Eviction protection from global
+	resolved.Disruption = r.global.Defaults.Disruption
 
-		// Command: convert []string to string if provided
-		if len(r.template.Command) > 0 {
-			resolved.Command = joinCommand(r.template.Command)
-		}
+	// Proxy and TLS config (global only)
+	resolved.Proxy = r.global.Proxy
+	resolved.TLS = r.global.TLS
+	resolved.Installer = r.global.Installer
 
-		// Sync config: template completely replaces global (not merged)
-		if len(r.template.Sync.Exclude) > 0 {
-			resolved.SyncExclude = r.template.Sync.Exclude
-		}
-		if r.template.Sync.UseGitignore != nil {
-			resolved.SyncUseGitignore = r.template.Sync.UseGitignore
-		}
-		if len(r.template.Sync.CustomDirs) > 0 {
-			resolved.SyncCustomDirs = r.template.Sync.CustomDirs
-		}
+	// Editor config from global
+	resolved.Editor = r.global.Defaults.Editor
 
-		// Env config: template dotenv files override, exclusions append
-		if len(r.template.Env.DotenvFiles) > 0 {
-			resolved.EnvDotenvFiles = r.template.Env.DotenvFiles
-		}
-		if len(r.template.Env.ExcludeVars) > 0 {
-			// Append template exclusions to global exclusions
-			resolved.EnvExcludeVars = append(resolved.EnvExcludeVars, r.template.Env.ExcludeVars...)
-		}
+	// Agent config from global
+	resolved.Agent = r.global.Defaults.Agent
 
-		// Secret file config: template completely replaces global (no merge)
-		if len(r.template.SecretFile.Files) > 0 {
-			resolved.SecretFileMappings = r.template.SecretFile.Files
-		}
+	// Layer 2: Apply templates (overrides global), each over the base it extends
+	for _, template := range r.templates {
+		applyTemplate(resolved, template)
 	}
 
 	return resolved
 }
 
+// applyTemplate applies one template's fields over resolved
+func applyTemplate(resolved *ResolvedConfig, t *SessionConfig) {
+	// Apply string fields using coalesce
+	resolved.Namespace = CoalesceString(t.Namespace, resolved.Namespace)
+	resolved.Image = CoalesceString(t.Image, resolved.Image)
+	resolved.CPU = CoalesceString(t.Resources.CPU, resolved.CPU)
+	resolved.Memory = CoalesceString(t.Resources.Memory, resolved.Memory)
+	resolved.Branch = CoalesceString(t.Branch, resolved.Branch)
+	resolved.GitCloneArgs = CoalesceString(t.GitClone.ExtraArgs, resolved.GitCloneArgs)
+	resolved.CloneMirror = CoalesceString(t.GitClone.Mirror, resolved.CloneMirror)
+	resolved.Repo = CoalesceString(t.Repo, resolved.Repo)
+	resolved.CachePVC = CoalesceString(t.Cache.PVC, resolved.CachePVC)
+	resolved.Shared = resolved.Shared.Merge(t.Shared)
+	resolved.Disruption = resolved.Disruption.Merge(t.Disruption)
+	resolved.Shell = CoalesceString(t.Shell, resolved.Shell)
+	resolved.Locale = CoalesceString(t.Locale, resolved.Locale)
+	resolved.Record = resolved.Record || t.Record
+	resolved.SpotFriendly = resolved.SpotFriendly || t.SpotFriendly
+	resolved.RestartPolicy = CoalesceString(t.RestartPolicy, resolved.RestartPolicy)
+	resolved.Watchdog = resolved.Watchdog || t.Watchdog
+	resolved.SealedSecrets = resolved.SealedSecrets || t.SealedSecrets
+	resolved.Sandbox = resolved.Sandbox || t.Sandbox
+	resolved.TestCommand = CoalesceString(t.Test.Command, resolved.TestCommand)
+
+	// Labels and pod overrides: extending templates are merged over their base
+	if len(t.Labels) > 0 {
+		labels := make(map[string]string, len(resolved.Labels)+len(t.Labels))
+		maps.Copy(labels, resolved.Labels)
+		maps.Copy(labels, t.Labels)
+		resolved.Labels = labels
+	}
+	if len(t.PodOverrides) > 0 {
+		resolved.PodOverrides = MergeValues(resolved.PodOverrides, t.PodOverrides)
+	}
+
+	// Volumes: extending templates add volumes or replace them by name
+	resolved.Volumes = MergeVolumes(resolved.Volumes, t.Volumes)
+
+	// Tools: extending templates add tools or change their versions
+	resolved.Tools = MergeTools(resolved.Tools, t.Tools)
+
+	// Dataset: template completely replaces its base
+	if t.Data.IsEnabled() {
+		resolved.Data = t.Data
+	}
+
+	// Apply int fields
+	resolved.CloneDepth = CoalesceInt(t.GitClone.Depth, resolved.CloneDepth)
+	resolved.CloneAttempts = CoalesceInt(t.GitClone.Attempts, resolved.CloneAttempts)
+	resolved.TtydPort = CoalesceInt(t.Ttyd.Port, resolved.TtydPort)
+
+	// Apply bool fields (SingleBranch: true means explicitly set)
+	resolved.SingleBranch = CoalesceBool(t.GitClone.SingleBranch, resolved.SingleBranch, t.GitClone.SingleBranch)
+
+	// Apply *bool fields (nil check required)
+	if t.Ttyd.Enabled != nil {
+		resolved.TtydEnabled = *t.Ttyd.Enabled
+	}
+	if t.Ttyd.Writable != nil {
+		resolved.TtydWritable = *t.Ttyd.Writable
+	}
+
+	// Apply ttyd options
+	resolved.TtydOptions = CoalesceString(t.Ttyd.Options, resolved.TtydOptions)
+
+	// Custom resources: template completely replaces global (not merged)
+	if t.Resources.CustomResources != nil {
+		resolved.CustomResources = make(map[string]string)
+		for k, v := range t.Resources.CustomResources {
+			resolved.CustomResources[k] = v
+		}
+	}
+
+	// Command: convert []string to string if provided
+	if len(t.Command) > 0 {
+		resolved.Command = joinCommand(t.Command)
+	}
+
+	// Sync config: template completely replaces global (not merged)
+	if len(t.Sync.Include) > 0 {
+		resolved.SyncInclude = t.Sync.Include
+	}
+	resolved.SyncLocalPath = CoalesceString(t.Sync.LocalPath, resolved.SyncLocalPath)
+	resolved.SyncRemotePath = CoalesceString(t.Sync.RemotePath, resolved.SyncRemotePath)
+	if len(t.Sync.Exclude) > 0 {
+		resolved.SyncExclude = t.Sync.Exclude
+	}
+	if t.Sync.UseGitignore != nil {
+		resolved.SyncUseGitignore = t.Sync.UseGitignore
+	}
+	if len(t.Sync.CustomDirs) > 0 {
+		resolved.SyncCustomDirs = t.Sync.CustomDirs
+	}
+	resolved.SyncCompression = CoalesceString(t.Sync.Compression, resolved.SyncCompression)
+
+	// Env config: template dotenv files override, exclusions append
+	if len(t.Env.DotenvFiles) > 0 {
+		resolved.EnvDotenvFiles = t.Env.DotenvFiles
+	}
+	if len(t.Env.ExcludeVars) > 0 {
+		// Append template exclusions to global exclusions
+		resolved.EnvExcludeVars = append(resolved.EnvExcludeVars, t.Env.ExcludeVars...)
+	}
+
+	// Secret file config: template completely replaces global (no merge)
+	if len(t.SecretFile.Files) > 0 {
+		resolved.SecretFileMappings = t.SecretFile.Files
+	}
+
+	// Git identity: template fields override global fields
+	resolved.GitIdentity = resolved.GitIdentity.Merge(t.GitIdentity)
+
+	// Git signing: template completely replaces global
+	if t.GitSigning.IsEnabled() {
+		resolved.GitSigning = t.GitSigning
+	}
+
+	// Git hooks: template enables installation, template hooks replace global ones
+	resolved.Git.InstallHooks = resolved.Git.InstallHooks || t.Git.InstallHooks
+	if len(t.Git.Hooks) > 0 {
+		resolved.Git.Hooks = t.Git.Hooks
+	}
+
+	// Dotfiles: template completely replaces global
+	if t.Dotfiles.IsEnabled() {
+		resolved.Dotfiles = t.Dotfiles
+	}
+
+	// Editor config: template fields override global fields
+	resolved.Editor = resolved.Editor.Merge(t.Editor)
+
+	// Agent config: template protected paths add to global ones
+	resolved.Agent = resolved.Agent.Merge(t.Agent)
+}
+
 // joinCommand joins command slice into a space-separated string
 func joinCommand(cmd []string) string {
 	if len(cmd) == 0 {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -345,6 +346,20 @@ func TestConfigResolver_Resolve_SyncConfig(t *testing.T) {
 	}
 }
 
+func TestConfigResolver_Resolve_SyncCompression(t *testing.T) {
+	global := DefaultGlobalConfig()
+	global.Sync.Compression = SyncCompressionGzip
+
+	if got := NewConfigResolver(global, &SessionConfig{}).Resolve().SyncCompression; got != SyncCompressionGzip {
+		t.Errorf("expected global compression gzip, got %q", got)
+	}
+
+	template := &SessionConfig{Sync: SyncConfig{Compression: SyncCompressionNone}}
+	if got := NewConfigResolver(global, template).Resolve().SyncCompression; got != SyncCompressionNone {
+		t.Errorf("expected template compression none, got %q", got)
+	}
+}
+
 func TestConfigResolver_Resolve_EmptyTemplateFields(t *testing.T) {
 	// Test that empty template fields don't override global config
 	global := DefaultGlobalConfig()
@@ -405,3 +420,273 @@ func TestJoinCommand(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigResolver_Resolve_CachePVC(t *testing.T) {
+	global := &GlobalConfig{
+		Defaults: DefaultsConfig{
+			Cache: CacheConfig{PVC: "team-cache"},
+		},
+	}
+
+	resolved := NewConfigResolver(global, nil).Resolve()
+	if resolved.CachePVC != "team-cache" {
+		t.Errorf("expected CachePVC 'team-cache', got '%s'", resolved.CachePVC)
+	}
+
+	template := &SessionConfig{Cache: CacheConfig{PVC: "ml-cache"}}
+	resolved = NewConfigResolver(global, template).Resolve()
+	if resolved.CachePVC != "ml-cache" {
+		t.Errorf("expected template CachePVC 'ml-cache', got '%s'", resolved.CachePVC)
+	}
+}
+
+func TestConfigResolver_Resolve_Shared(t *testing.T) {
+	global := &GlobalConfig{
+		Defaults: DefaultsConfig{
+			Shared: SharedConfig{PVC: "team-shared", Size: "50Gi"},
+		},
+	}
+
+	resolved := NewConfigResolver(global, nil).Resolve()
+	if resolved.Shared.Enabled {
+		t.Error("expected the shared volume to stay disabled without enabled: true")
+	}
+
+	template := &SessionConfig{Shared: SharedConfig{Enabled: true, StorageClass: "nfs"}}
+	resolved = NewConfigResolver(global, template).Resolve()
+	want := SharedConfig{Enabled: true, PVC: "team-shared", Size: "50Gi", StorageClass: "nfs"}
+	if resolved.Shared != want {
+		t.Errorf("expected template to enable the global shared volume, got %+v", resolved.Shared)
+	}
+}
+
+func TestConfigResolver_Resolve_ProxyAndTLS(t *testing.T) {
+	global := DefaultGlobalConfig()
+	global.Proxy = ProxyConfig{HTTPSProxy: "http://proxy.corp:3128", NoProxy: ".corp"}
+	global.TLS = TLSConfig{ExtraCABundleSecret: "corp-ca"}
+
+	resolved := NewConfigResolver(global, &SessionConfig{}).Resolve()
+	if resolved.Proxy != global.Proxy {
+		t.Errorf("expected proxy %+v, got %+v", global.Proxy, resolved.Proxy)
+	}
+	if resolved.TLS.ExtraCABundleSecret != "corp-ca" {
+		t.Errorf("expected ExtraCABundleSecret 'corp-ca', got '%s'", resolved.TLS.ExtraCABundleSecret)
+	}
+}
+
+func TestConfigResolver_Resolve_GitIdentity(t *testing.T) {
+	global := DefaultGlobalConfig()
+	global.Defaults.GitIdentity = GitIdentityConfig{Name: "Jane Doe", Email: "jane@example.com"}
+
+	template := &SessionConfig{GitIdentity: GitIdentityConfig{Email: "jane@work.example.com"}}
+	resolved := NewConfigResolver(global, template).Resolve()
+	want := GitIdentityConfig{Name: "Jane Doe", Email: "jane@work.example.com"}
+	if resolved.GitIdentity != want {
+		t.Errorf("expected git identity %+v, got %+v", want, resolved.GitIdentity)
+	}
+}
+
+func TestConfigResolver_Resolve_GitHooks(t *testing.T) {
+	global := DefaultGlobalConfig()
+	global.Defaults.Git = GitConfig{Hooks: map[string]string{"pre-commit": "hooks/pre-commit"}}
+
+	resolved := NewConfigResolver(global, &SessionConfig{Git: GitConfig{InstallHooks: true}}).Resolve()
+	if !resolved.Git.InstallHooks || resolved.Git.Hooks["pre-commit"] != "hooks/pre-commit" {
+		t.Errorf("expected template to enable global hooks, got %+v", resolved.Git)
+	}
+}
+
+func TestConfigResolver_Resolve_Dotfiles(t *testing.T) {
+	global := DefaultGlobalConfig()
+	global.Defaults.Dotfiles = DotfilesConfig{Repo: "https://github.com/me/dotfiles", InstallCommand: "./install.sh"}
+
+	if got := NewConfigResolver(global, &SessionConfig{}).Resolve().Dotfiles; got != global.Defaults.Dotfiles {
+		t.Errorf("expected global dotfiles, got %+v", got)
+	}
+
+	template := &SessionConfig{Dotfiles: DotfilesConfig{Repo: "https://github.com/team/dotfiles"}}
+	want := DotfilesConfig{Repo: "https://github.com/team/dotfiles"}
+	if got := NewConfigResolver(global, template).Resolve().Dotfiles; got != want {
+		t.Errorf("expected template dotfiles %+v to replace global, got %+v", want, got)
+	}
+}
+
+func TestConfigResolver_Resolve_Tools(t *testing.T) {
+	base := &SessionConfig{Tools: []string{"go@1.22", "node@22"}}
+	template := &SessionConfig{Tools: []string{"go@1.23", "rust@stable"}}
+
+	got := NewConfigResolver(DefaultGlobalConfig(), base, template).Resolve().Tools
+	want := []string{"node@22", "go@1.23", "rust@stable"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected tools %v, got %v", want, got)
+	}
+}
+
+func TestConfigResolver_Resolve_Record(t *testing.T) {
+	global := DefaultGlobalConfig()
+
+	if NewConfigResolver(global, nil).Resolve().Record {
+		t.Error("expected Record to be false without a template")
+	}
+
+	template := &SessionConfig{Record: true}
+	if !NewConfigResolver(global, template).Resolve().Record {
+		t.Error("expected Record to be true from template")
+	}
+}
+
+func TestConfigResolver_Resolve_SpotFriendly(t *testing.T) {
+	global := DefaultGlobalConfig()
+
+	if NewConfigResolver(global, nil).Resolve().SpotFriendly {
+		t.Error("expected SpotFriendly to be false without a template")
+	}
+
+	template := &SessionConfig{SpotFriendly: true}
+	if !NewConfigResolver(global, template).Resolve().SpotFriendly {
+		t.Error("expected SpotFriendly to be true from template")
+	}
+}
+
+func TestConfigResolver_Resolve_SealedSecrets(t *testing.T) {
+	global := DefaultGlobalConfig()
+
+	if NewConfigResolver(global, nil).Resolve().SealedSecrets {
+		t.Error("expected SealedSecrets to be false by default")
+	}
+
+	template := &SessionConfig{SealedSecrets: true}
+	if !NewConfigResolver(global, template).Resolve().SealedSecrets {
+		t.Error("expected SealedSecrets to be true from template")
+	}
+
+	global.Defaults.SealedSecrets = true
+	if !NewConfigResolver(global, &SessionConfig{}).Resolve().SealedSecrets {
+		t.Error("expected SealedSecrets to be true from global defaults")
+	}
+}
+
+func TestConfigResolver_Resolve_Sandbox(t *testing.T) {
+	global := DefaultGlobalConfig()
+
+	if NewConfigResolver(global, nil).Resolve().Sandbox {
+		t.Error("expected Sandbox to be false without a template")
+	}
+
+	template := &SessionConfig{Sandbox: true}
+	if !NewConfigResolver(global, template).Resolve().Sandbox {
+		t.Error("expected Sandbox to be true from template")
+	}
+}
+
+func TestConfigResolver_Resolve_Disruption(t *testing.T) {
+	global := DefaultGlobalConfig()
+	global.Defaults.Disruption = DisruptionConfig{NoEvict: true}
+
+	template := &SessionConfig{Disruption: DisruptionConfig{PodDisruptionBudget: true}}
+	resolved := NewConfigResolver(global, template).Resolve()
+	want := DisruptionConfig{NoEvict: true, PodDisruptionBudget: true}
+	if resolved.Disruption != want {
+		t.Errorf("expected disruption %+v, got %+v", want, resolved.Disruption)
+	}
+}
+
+func TestConfigResolver_Resolve_EditorConfig(t *testing.T) {
+	enabled := true
+	disabled := false
+
+	global := DefaultGlobalConfig()
+	global.Defaults.Editor = EditorConfig{
+		Editor:     "vim",
+		CodeServer: CodeServerConfig{Enabled: &enabled, Port: 9000},
+	}
+	template := &SessionConfig{
+		Editor: EditorConfig{
+			Editor:     "hx",
+			CodeServer: CodeServerConfig{Enabled: &disabled},
+		},
+	}
+
+	resolved := NewConfigResolver(global, template).Resolve()
+
+	if resolved.Editor.Editor != "hx" {
+		t.Errorf("expected template editor 'hx', got '%s'", resolved.Editor.Editor)
+	}
+	if resolved.Editor.CodeServer.IsEnabled() {
+		t.Error("expected template to disable code-server")
+	}
+	if resolved.Editor.CodeServer.Port != 9000 {
+		t.Errorf("expected global code-server port 9000, got %d", resolved.Editor.CodeServer.Port)
+	}
+
+	resolved = NewConfigResolver(global, nil).Resolve()
+	if resolved.Editor.Editor != "vim" || !resolved.Editor.CodeServer.IsEnabled() {
+		t.Errorf("expected global editor settings, got %+v", resolved.Editor)
+	}
+}
+
+func TestConfigResolver_Resolve_ExtendsChain(t *testing.T) {
+	global := DefaultGlobalConfig()
+	base := &SessionConfig{
+		Image:     "base:1",
+		Namespace: "platform",
+		Resources: ResourceConfig{CPU: "1", Memory: "2Gi"},
+		Record:    true,
+		Test:      TestConfig{Command: "make test"},
+		Labels:    map[string]string{"team": "platform", "tier": "base"},
+		PodOverrides: map[string]interface{}{
+			"nodeSelector": map[string]interface{}{"pool": "dev", "arch": "amd64"},
+			"tolerations":  []interface{}{"base"},
+		},
+	}
+	service := &SessionConfig{
+		Image:     "payments:2",
+		Resources: ResourceConfig{CPU: "4"},
+		Labels:    map[string]string{"tier": "service"},
+		PodOverrides: map[string]interface{}{
+			"nodeSelector": map[string]interface{}{"pool": "gpu"},
+			"tolerations":  []interface{}{"service"},
+		},
+	}
+
+	resolved := NewConfigResolver(global, base, nil, service).Resolve()
+
+	if resolved.Image != "payments:2" || resolved.CPU != "4" {
+		t.Errorf("expected extending template to win, got image %q cpu %q", resolved.Image, resolved.CPU)
+	}
+	if resolved.Namespace != "platform" || resolved.Memory != "2Gi" {
+		t.Errorf("expected base values to be kept, got namespace %q memory %q", resolved.Namespace, resolved.Memory)
+	}
+	if !resolved.Record || resolved.TestCommand != "make test" {
+		t.Errorf("expected base Record and test command, got %v %q", resolved.Record, resolved.TestCommand)
+	}
+	if want := map[string]string{"team": "platform", "tier": "service"}; !reflect.DeepEqual(resolved.Labels, want) {
+		t.Errorf("expected labels %v, got %v", want, resolved.Labels)
+	}
+	want := map[string]interface{}{
+		"nodeSelector": map[string]interface{}{"pool": "gpu", "arch": "amd64"},
+		"tolerations":  []interface{}{"service"},
+	}
+	if !reflect.DeepEqual(resolved.PodOverrides, want) {
+		t.Errorf("expected pod overrides %v, got %v", want, resolved.PodOverrides)
+	}
+	if base.Labels["tier"] != "base" {
+		t.Error("expected base template labels to be left unchanged")
+	}
+}
+
+func TestConfigResolver_Resolve_SyncInclude(t *testing.T) {
+	global := DefaultGlobalConfig()
+	global.Sync.Include = []string{"go.mod", "pkg/**"}
+
+	resolved := NewConfigResolver(global, nil).Resolve()
+	if len(resolved.SyncInclude) != 2 || resolved.SyncInclude[1] != "pkg/**" {
+		t.Errorf("expected global include patterns, got %v", resolved.SyncInclude)
+	}
+
+	template := &SessionConfig{Sync: SyncConfig{Include: []string{"services/payments/**"}}}
+	resolved = NewConfigResolver(global, template).Resolve()
+	if len(resolved.SyncInclude) != 1 || resolved.SyncInclude[0] != "services/payments/**" {
+		t.Errorf("expected template include patterns to replace global, got %v", resolved.SyncInclude)
+	}
+}
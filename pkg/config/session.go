@@ -2,6 +2,12 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/illumination-k/kodama/pkg/env"
@@ -17,6 +23,18 @@ var (
 
 	// ErrRepoRequired is returned when repository URL is empty
 	ErrRepoRequired = errors.New("repository URL is required")
+
+	// ErrNoCurrentSession is returned when a command needs a session name,
+	// none was given, and no current session is set with 'kodama use'
+	ErrNoCurrentSession = errors.New("no session name given and no current session set")
+
+	// ErrAmbiguousSession is returned when several sessions sync the working
+	// directory and none of them is the current session
+	ErrAmbiguousSession = errors.New("several sessions sync this directory")
+
+	// ErrMissingLabels is returned when a new session lacks a label listed in
+	// requiredLabels of the global config
+	ErrMissingLabels = errors.New("missing required session labels")
 )
 
 // SessionStatus represents the current state of a session
@@ -28,21 +46,54 @@ const (
 	StatusRunning  SessionStatus = "Running"
 	StatusStopped  SessionStatus = "Stopped"
 	StatusFailed   SessionStatus = "Failed"
+	StatusDegraded SessionStatus = "Degraded" // Pod evicted or its node lost
 )
 
 // AgentExecution represents a single agent execution record
 type AgentExecution struct {
 	ExecutedAt time.Time `yaml:"executedAt"`
 	Prompt     string    `yaml:"prompt,omitempty"`
+	Step       string    `yaml:"step,omitempty"` // Name of the --plan step the task ran
 	TaskID     string    `yaml:"taskID,omitempty"`
-	Status     string    `yaml:"status"` // "pending", "running", "completed", "failed"
+	Status     string    `yaml:"status"` // "pending", "running", "completed", "failed", "needs-review"
 	Error      string    `yaml:"error,omitempty"`
+	// LogPath is the task output log in the pod; ArtifactPath is its local copy
+	LogPath      string `yaml:"logPath,omitempty"`
+	ArtifactPath string `yaml:"artifactPath,omitempty"`
+	// BaseCommit is the workspace HEAD before the task; changes are reported against it
+	BaseCommit string `yaml:"baseCommit,omitempty"`
+	// DiffStat and ChangedFiles describe the workspace changes after the task
+	DiffStat     string   `yaml:"diffStat,omitempty"`
+	ChangedFiles []string `yaml:"changedFiles,omitempty"`
+	// ProtectedChanges are changed files matching Agent.ProtectedPaths
+	ProtectedChanges  []string `yaml:"protectedChanges,omitempty"`
+	ProtectedReverted bool     `yaml:"protectedReverted,omitempty"`
+}
+
+// TestConfig holds the project's test command for 'kodama test'
+type TestConfig struct {
+	Command string `yaml:"command,omitempty"` // Run with sh in /workspace, e.g. "make test"
 }
 
+// TestResult records a run of 'kodama test'
+type TestResult struct {
+	StartedAt time.Time     `yaml:"startedAt"`
+	Command   string        `yaml:"command"`
+	Passed    bool          `yaml:"passed"`
+	ExitCode  int           `yaml:"exitCode"`
+	Duration  time.Duration `yaml:"duration"`
+}
+
+// AgentStatusNeedsReview marks an agent execution that modified protected paths
+const AgentStatusNeedsReview = "needs-review"
+
 // SessionConfig represents a Kodama session configuration
 //
 //nolint:govet // fieldalignment: accepting minor memory overhead for logical field grouping
 type SessionConfig struct {
+	SchemaVersion   int                         `yaml:"schemaVersion,omitempty"` // See SessionSchemaVersion
+	Extends         string                      `yaml:"extends,omitempty"`       // Templates only: base template path or profile name merged under this one
+	Overlays        map[string]*SessionConfig   `yaml:"overlays,omitempty"`      // Templates only: named variants selected with --overlay
 	CreatedAt       time.Time                   `yaml:"createdAt"`
 	UpdatedAt       time.Time                   `yaml:"updatedAt"`
 	Sync            SyncConfig                  `yaml:"sync,omitempty"`
@@ -60,12 +111,46 @@ type SessionConfig struct {
 	Image           string                      `yaml:"image,omitempty"`
 	Command         []string                    `yaml:"command,omitempty"`
 	GitClone        GitCloneConfig              `yaml:"gitClone,omitempty"`
+	GitIdentity     GitIdentityConfig           `yaml:"gitIdentity,omitempty"`
+	GitSigning      GitSigningConfig            `yaml:"gitSigning,omitempty"`
+	Git             GitConfig                   `yaml:"git,omitempty"`
+	Dotfiles        DotfilesConfig              `yaml:"dotfiles,omitempty"`
+	Test            TestConfig                  `yaml:"test,omitempty"`
+	LastTest        *TestResult                 `yaml:"lastTest,omitempty"`
 	Status          SessionStatus               `yaml:"status"`
 	AutoBranch      bool                        `yaml:"autoBranch,omitempty"`
 	AgentExecutions []AgentExecution            `yaml:"agentExecutions,omitempty"`
 	LastAgentRun    *time.Time                  `yaml:"lastAgentRun,omitempty"`
+	ExpiresAt       *time.Time                  `yaml:"expiresAt,omitempty"`
 	Env             env.EnvConfig               `yaml:"env,omitempty"`
 	SecretFile      secretfile.SecretFileConfig `yaml:"secretFile,omitempty"`
+	Cache           CacheConfig                 `yaml:"cache,omitempty"`
+	Shared          SharedConfig                `yaml:"shared,omitempty"`
+	Disruption      DisruptionConfig            `yaml:"disruption,omitempty"`
+	Proxy           ProxyConfig                 `yaml:"proxy,omitempty"`
+	TLS             TLSConfig                   `yaml:"tls,omitempty"`
+	Installer       InstallerConfig             `yaml:"installer,omitempty"`
+	Record          bool                        `yaml:"record,omitempty"`        // Record interactive terminals to /workspace/.kodama/recordings
+	SpotFriendly    bool                        `yaml:"spotFriendly,omitempty"`  // Run on spot nodes, checkpointing the workspace and recreating the pod when preempted
+	SealedSecrets   bool                        `yaml:"sealedSecrets,omitempty"` // Apply env and file secrets server-side, owned by the pod and deleted with it
+	Runtime         string                      `yaml:"runtime,omitempty"`       // docker or podman for a local container session; empty for a Kubernetes pod
+	RestartPolicy   string                      `yaml:"restartPolicy,omitempty"` // Never, OnFailure or Always; empty = OnFailure with a command, Never otherwise
+	Watchdog        bool                        `yaml:"watchdog,omitempty"`      // Restart ttyd, the session command and sshd in the pod when they die
+	Shell           string                      `yaml:"shell,omitempty"`         // Shell opened by attach and ttyd, e.g. zsh; empty detects bash, zsh or sh
+	Locale          string                      `yaml:"locale,omitempty"`        // LANG and LC_ALL of the pod, attach and ttyd; empty = C.UTF-8
+	Editor          EditorConfig                `yaml:"editor,omitempty"`
+	Agent           AgentConfig                 `yaml:"agent,omitempty"`
+	AgentUsage      AgentUsage                  `yaml:"agentUsage,omitempty"`
+	Labels          map[string]string           `yaml:"labels,omitempty"`       // User-defined labels for filtering, e.g. team: payments
+	PodOverrides    map[string]interface{}      `yaml:"podOverrides,omitempty"` // PodSpec fields strategic-merge-patched onto the generated pod
+	Volumes         []VolumeConfig              `yaml:"volumes,omitempty"`      // Existing PVCs, ConfigMaps, Secrets or host paths mounted into the pod
+	Data            DataConfig                  `yaml:"data,omitempty"`         // Dataset fetched into the workspace by an init container
+	Tools           []string                    `yaml:"tools,omitempty"`        // Toolchains installed with mise, e.g. go@1.23, node@22
+	Sandbox         bool                        `yaml:"sandbox,omitempty"`      // Give the agent a writable copy of the workspace, which is mounted read-only
+
+	// CreatedResources are the Secrets and ConfigMaps created for the session,
+	// recorded before they are created so delete finds them after any failure
+	CreatedResources []CreatedResource `yaml:"createdResources,omitempty"`
 
 	// ManifestsGenerated holds generated manifests when DryRun mode is used
 	// Not serialized to YAML as this is only used during manifest generation
@@ -77,18 +162,269 @@ type GitCloneConfig struct {
 	Depth        int    `yaml:"depth,omitempty"`        // Shallow clone depth (0 = full)
 	SingleBranch bool   `yaml:"singleBranch,omitempty"` // Clone only single branch
 	ExtraArgs    string `yaml:"extraArgs,omitempty"`    // Additional git clone arguments
+	Attempts     int    `yaml:"attempts,omitempty"`     // Clone attempts per remote (0 = default of 3)
+	Mirror       string `yaml:"mirror,omitempty"`       // Alternate remote used when the repository keeps failing
+}
+
+// GitIdentityConfig is the author of commits made inside the pod
+// Empty fields are filled from the local git config when the session starts.
+type GitIdentityConfig struct {
+	Name  string `yaml:"name,omitempty"`
+	Email string `yaml:"email,omitempty"`
+}
+
+// IsEmpty reports whether no identity is set
+func (g GitIdentityConfig) IsEmpty() bool {
+	return g.Name == "" && g.Email == ""
+}
+
+// Merge returns g with the fields set in other overriding it
+func (g GitIdentityConfig) Merge(other GitIdentityConfig) GitIdentityConfig {
+	g.Name = CoalesceString(other.Name, g.Name)
+	g.Email = CoalesceString(other.Email, g.Email)
+	return g
+}
+
+// gitHookNamePattern matches git hook names such as pre-commit or commit-msg
+var gitHookNamePattern = regexp.MustCompile(`^[a-z]+(-[a-z]+)*$`)
+
+// GitConfig holds git settings for the workspace
+type GitConfig struct {
+	// InstallHooks installs git hooks in /workspace after the clone or initial sync
+	InstallHooks bool `yaml:"installHooks,omitempty"`
+	// Hooks maps hook names to scripts in the workspace that are copied into
+	// .git/hooks, e.g. pre-commit: scripts/pre-commit.sh. Without hooks,
+	// 'pre-commit install' is run.
+	Hooks map[string]string `yaml:"hooks,omitempty"`
+}
+
+// Validate checks hook names and that scripts are relative to the workspace
+func (g GitConfig) Validate() error {
+	for name, script := range g.Hooks {
+		if !gitHookNamePattern.MatchString(name) {
+			return fmt.Errorf("invalid git hook name %q", name)
+		}
+		if script == "" || filepath.IsAbs(script) || strings.HasPrefix(filepath.Clean(script), "..") {
+			return fmt.Errorf("git hook %s: script must be a path inside the workspace, got %q", name, script)
+		}
+	}
+	return nil
+}
+
+// Commit signing formats
+const (
+	GitSigningSSH = "ssh"
+	GitSigningGPG = "gpg"
+)
+
+// GitSigningConfig signs commits made in the pod with a key from a Secret
+// The Secret holds the private key under "signing-key".
+type GitSigningConfig struct {
+	Format string `yaml:"format,omitempty"` // ssh (default) or gpg
+	Secret string `yaml:"secret,omitempty"` // Secret with the signing key (empty = signing disabled)
+	Key    string `yaml:"key,omitempty"`    // GPG key ID or fingerprint; required for gpg
+}
+
+// IsEnabled reports whether commits are signed
+func (g GitSigningConfig) IsEnabled() bool {
+	return g.Secret != ""
+}
+
+// Validate checks the signing format and the key required for gpg
+func (g GitSigningConfig) Validate() error {
+	if !g.IsEnabled() {
+		return nil
+	}
+	switch g.Format {
+	case "", GitSigningSSH:
+	case GitSigningGPG:
+		if g.Key == "" {
+			return fmt.Errorf("gitSigning.format gpg requires gitSigning.key")
+		}
+	default:
+		return fmt.Errorf("unknown gitSigning.format %q (want %s or %s)", g.Format, GitSigningSSH, GitSigningGPG)
+	}
+	return nil
+}
+
+// DisruptionConfig protects session pods from voluntary evictions, such as
+// the cluster autoscaler bin-packing nodes or node drains
+type DisruptionConfig struct {
+	NoEvict             bool `yaml:"noEvict,omitempty"`             // Annotate the pod cluster-autoscaler.kubernetes.io/safe-to-evict=false
+	PodDisruptionBudget bool `yaml:"podDisruptionBudget,omitempty"` // Create a PodDisruptionBudget that blocks evictions, including drains
+}
+
+// Merge returns d with the protections enabled in other also enabled
+func (d DisruptionConfig) Merge(other DisruptionConfig) DisruptionConfig {
+	return DisruptionConfig{
+		NoEvict:             d.NoEvict || other.NoEvict,
+		PodDisruptionBudget: d.PodDisruptionBudget || other.PodDisruptionBudget,
+	}
+}
+
+// CacheConfig holds the shared dependency cache configuration
+// The PVC is shared by every session in the namespace and populated by 'kodama cache warm'.
+type CacheConfig struct {
+	PVC string `yaml:"pvc,omitempty"` // ReadWriteMany PVC mounted at /cache (empty = disabled)
+}
+
+// SharedConfig holds the inter-session shared scratch volume configuration
+// Every session that enables it mounts the same namespace-wide PVC at /shared,
+// so one session's agent can leave artifacts (e.g. generated code) for another.
+type SharedConfig struct {
+	Enabled      bool   `yaml:"enabled,omitempty"`
+	PVC          string `yaml:"pvc,omitempty"`          // ReadWriteMany PVC (default: kodama-shared); set by start
+	Size         string `yaml:"size,omitempty"`         // Requested size when start creates the PVC (default: 10Gi)
+	StorageClass string `yaml:"storageClass,omitempty"` // Must support ReadWriteMany; used when start creates the PVC
+}
+
+// Merge returns c with the fields set in other overriding it; either enables the volume
+func (c SharedConfig) Merge(other SharedConfig) SharedConfig {
+	return SharedConfig{
+		Enabled:      c.Enabled || other.Enabled,
+		PVC:          CoalesceString(other.PVC, c.PVC),
+		Size:         CoalesceString(other.Size, c.Size),
+		StorageClass: CoalesceString(other.StorageClass, c.StorageClass),
+	}
+}
+
+// DefaultEditorConfigDir is where editor config files are read from, relative
+// to the synced directory (or the current directory in repo mode)
+const DefaultEditorConfigDir = ".kodama/configs"
+
+// EditorConfig holds editor settings for the session
+// Files under ConfigDir (e.g. helix/config.toml) are mounted at ~/.config/<path>
+// in the pod through a ConfigMap.
+type EditorConfig struct {
+	Editor     string           `yaml:"editor,omitempty"`     // EDITOR and VISUAL in the pod, e.g. "hx"
+	ConfigDir  string           `yaml:"configDir,omitempty"`  // Default: .kodama/configs
+	CodeServer CodeServerConfig `yaml:"codeServer,omitempty"` // Optional browser-based VS Code sidecar
+
+	// Set by start; used for cleanup
+	ConfigMapName    string   `yaml:"configMapName,omitempty"`
+	ConfigMapCreated bool     `yaml:"configMapCreated,omitempty"`
+	Files            []string `yaml:"files,omitempty"` // Config files in the ConfigMap, relative to ConfigDir
+}
+
+// CodeServerConfig holds configuration for the code-server sidecar
+type CodeServerConfig struct {
+	Enabled *bool  `yaml:"enabled,omitempty"` // nil = disabled
+	Image   string `yaml:"image,omitempty"`   // Default: codercom/code-server:latest
+	Port    int    `yaml:"port,omitempty"`    // Default: 8080
+}
+
+// IsEnabled reports whether the code-server sidecar is enabled
+func (c CodeServerConfig) IsEnabled() bool {
+	return c.Enabled != nil && *c.Enabled
+}
+
+// Merge returns e with the non-empty settings of other applied on top
+func (e EditorConfig) Merge(other EditorConfig) EditorConfig {
+	e.Editor = CoalesceString(other.Editor, e.Editor)
+	e.ConfigDir = CoalesceString(other.ConfigDir, e.ConfigDir)
+	if other.CodeServer.Enabled != nil {
+		e.CodeServer.Enabled = other.CodeServer.Enabled
+	}
+	e.CodeServer.Image = CoalesceString(other.CodeServer.Image, e.CodeServer.Image)
+	e.CodeServer.Port = CoalesceInt(other.CodeServer.Port, e.CodeServer.Port)
+	return e
+}
+
+// ProtectedChangeRevert makes kodama undo agent changes to protected paths
+const ProtectedChangeRevert = "revert"
+
+// AgentConfig holds guardrails for coding agent runs
+type AgentConfig struct {
+	// ProtectedPaths are globs the agent must not modify, e.g. "infra/**" or ".github/workflows/**"
+	ProtectedPaths []string `yaml:"protectedPaths,omitempty"`
+	// OnProtectedChange is "flag" (default) to only mark the run needs-review, or "revert"
+	OnProtectedChange string `yaml:"onProtectedChange,omitempty"`
+	// MaxRuns and MaxDuration limit the agent tasks of a session (0 = unlimited)
+	MaxRuns     int           `yaml:"maxRuns,omitempty"`
+	MaxDuration time.Duration `yaml:"maxDuration,omitempty"` // Total wall-clock time, e.g. "2h"
+	// ContextFiles are local files uploaded with every prompt of start and run,
+	// e.g. design docs; --attach-file adds more
+	ContextFiles []string `yaml:"contextFiles,omitempty"`
+}
+
+// AgentUsage is the cumulative agent usage of a session, checked against AgentConfig limits
+type AgentUsage struct {
+	Runs     int           `yaml:"runs,omitempty"`
+	Duration time.Duration `yaml:"duration,omitempty"`
+}
+
+// Merge returns a with other applied on top
+// Protected paths accumulate, so a template cannot lift the global ones.
+// Context files are replaced.
+func (a AgentConfig) Merge(other AgentConfig) AgentConfig {
+	if len(other.ProtectedPaths) > 0 {
+		a.ProtectedPaths = append(slices.Clone(a.ProtectedPaths), other.ProtectedPaths...)
+	}
+	a.OnProtectedChange = CoalesceString(other.OnProtectedChange, a.OnProtectedChange)
+	a.MaxRuns = CoalesceInt(other.MaxRuns, a.MaxRuns)
+	if other.MaxDuration > 0 {
+		a.MaxDuration = other.MaxDuration
+	}
+	a.ContextFiles = CoalesceStringSlice(other.ContextFiles, a.ContextFiles)
+	return a
 }
 
 // SyncConfig holds configuration for file synchronization
 type SyncConfig struct {
 	UseGitignore   *bool           `yaml:"useGitignore,omitempty"`
 	LocalPath      string          `yaml:"localPath,omitempty"`
+	RemotePath     string          `yaml:"remotePath,omitempty"` // Directory inside /workspace the local path is synced to (default: /workspace)
 	MutagenSession string          `yaml:"mutagenSession,omitempty"`
+	Include        []string        `yaml:"include,omitempty"` // Only sync these root-relative paths, e.g. src/** and go.mod (evaluated before excludes)
 	Exclude        []string        `yaml:"exclude,omitempty"`
 	CustomDirs     []CustomDirSync `yaml:"customDirs,omitempty"`
+	Compression    string          `yaml:"compression,omitempty"` // none, gzip or zstd (default: zstd when the pod has it, else gzip)
 	Enabled        bool            `yaml:"enabled"`
 }
 
+// Sync compression settings
+const (
+	SyncCompressionAuto = "auto"
+	SyncCompressionNone = "none"
+	SyncCompressionGzip = "gzip"
+	SyncCompressionZstd = "zstd"
+)
+
+// WorkspaceRoot is where the session's repository is cloned or synced to in the pod
+const WorkspaceRoot = "/workspace"
+
+// RemoteRoot returns the absolute directory in the pod the local path is
+// synced to. A relative remotePath is relative to /workspace.
+func (c SyncConfig) RemoteRoot() string {
+	if path.IsAbs(c.RemotePath) {
+		return path.Clean(c.RemotePath)
+	}
+	return path.Join(WorkspaceRoot, c.RemotePath)
+}
+
+// ValidateSyncRemotePath checks that a sync.remotePath is /workspace or a directory inside it
+func ValidateSyncRemotePath(remotePath string) error {
+	if remotePath == "" {
+		return nil
+	}
+	root := SyncConfig{RemotePath: remotePath}.RemoteRoot()
+	if root != WorkspaceRoot && !strings.HasPrefix(root, WorkspaceRoot+"/") {
+		return fmt.Errorf("sync.remotePath must be a directory inside %s, got %q", WorkspaceRoot, remotePath)
+	}
+	return nil
+}
+
+// ValidateSyncCompression checks a sync.compression setting
+func ValidateSyncCompression(compression string) error {
+	switch compression {
+	case "", SyncCompressionAuto, SyncCompressionNone, SyncCompressionGzip, SyncCompressionZstd:
+		return nil
+	default:
+		return fmt.Errorf("unknown sync.compression %q (want %s, %s, %s or %s)", compression,
+			SyncCompressionAuto, SyncCompressionNone, SyncCompressionGzip, SyncCompressionZstd)
+	}
+}
+
 // ResourceConfig holds resource limit configuration
 type ResourceConfig struct {
 	CPU             string            `yaml:"cpu,omitempty"`
@@ -109,13 +445,125 @@ func (s *SessionConfig) Validate() error {
 	if s.Name == "" {
 		return ErrSessionNameRequired
 	}
-	if s.Namespace == "" {
+	if err := ValidateRuntime(s.Runtime); err != nil {
+		return err
+	}
+	if err := ValidateShell(s.Shell); err != nil {
+		return err
+	}
+	if err := ValidateLocale(s.Locale); err != nil {
+		return err
+	}
+	if err := ValidateRestartPolicy(s.RestartPolicy); err != nil {
+		return err
+	}
+	if s.Namespace == "" && !s.IsLocalRuntime() {
 		return ErrNamespaceRequired
 	}
 	// Repo is now optional (not required when using sync)
+	if err := s.GitSigning.Validate(); err != nil {
+		return err
+	}
+	if err := s.Git.Validate(); err != nil {
+		return err
+	}
+	if err := s.Dotfiles.Validate(); err != nil {
+		return err
+	}
+	if err := ValidateSyncCompression(s.Sync.Compression); err != nil {
+		return err
+	}
+	if err := ValidateSyncRemotePath(s.Sync.RemotePath); err != nil {
+		return err
+	}
+	if err := ValidateVolumes(s.Volumes); err != nil {
+		return err
+	}
+	if err := s.Data.Validate(); err != nil {
+		return err
+	}
+	if err := ValidateTools(s.Tools); err != nil {
+		return err
+	}
+	return s.Installer.Validate()
+}
+
+// Session runtimes
+const (
+	RuntimeKubernetes = "kubernetes"
+	RuntimeDocker     = "docker"
+	RuntimePodman     = "podman"
+)
+
+// ValidateRuntime checks a session runtime ("" is Kubernetes)
+func ValidateRuntime(runtime string) error {
+	switch runtime {
+	case "", RuntimeKubernetes, RuntimeDocker, RuntimePodman:
+		return nil
+	default:
+		return fmt.Errorf("unknown runtime %q (want kubernetes, docker or podman)", runtime)
+	}
+}
+
+// shellPattern matches a shell name or absolute path, e.g. zsh or /usr/bin/fish
+var shellPattern = regexp.MustCompile(`^/?[A-Za-z0-9_.+-]+(/[A-Za-z0-9_.+-]+)*$`)
+
+// ValidateShell checks a session shell ("" detects one in the image)
+func ValidateShell(shell string) error {
+	if shell != "" && !shellPattern.MatchString(shell) {
+		return fmt.Errorf("invalid shell %q: use a name such as zsh or an absolute path such as /bin/zsh", shell)
+	}
+	return nil
+}
+
+// localePattern matches locale names such as en_US.UTF-8 or C.UTF-8
+var localePattern = regexp.MustCompile(`^[A-Za-z0-9_.@-]+$`)
+
+// ValidateLocale checks a session locale ("" uses C.UTF-8)
+func ValidateLocale(locale string) error {
+	if locale != "" && !localePattern.MatchString(locale) {
+		return fmt.Errorf("invalid locale %q: use a name such as C.UTF-8 or en_US.UTF-8", locale)
+	}
 	return nil
 }
 
+// Pod restart policies of a session, see SessionConfig.PodRestartPolicy
+const (
+	RestartPolicyNever     = "Never"
+	RestartPolicyOnFailure = "OnFailure"
+	RestartPolicyAlways    = "Always"
+)
+
+// ValidateRestartPolicy checks a session restart policy ("" picks one, see PodRestartPolicy)
+func ValidateRestartPolicy(policy string) error {
+	switch policy {
+	case "", RestartPolicyNever, RestartPolicyOnFailure, RestartPolicyAlways:
+		return nil
+	default:
+		return fmt.Errorf("invalid restart policy %q: use Never, OnFailure or Always", policy)
+	}
+}
+
+// PodRestartPolicy returns the restart policy of the session pod: the
+// configured one, else OnFailure for sessions running a command, so a crashed
+// command is restarted instead of leaving the session without a container,
+// and Never for interactive sessions
+func (s *SessionConfig) PodRestartPolicy() string {
+	if s.RestartPolicy != "" {
+		return s.RestartPolicy
+	}
+	if len(s.Command) > 0 {
+		return RestartPolicyOnFailure
+	}
+	return RestartPolicyNever
+}
+
+// IsLocalRuntime reports whether the session runs as a local docker or podman
+// container instead of a pod
+func (s *SessionConfig) IsLocalRuntime() bool {
+	return s.Runtime == RuntimeDocker || s.Runtime == RuntimePodman
+}
+
 // IsRunning returns true if the session is in Running state
 func (s *SessionConfig) IsRunning() bool {
 	return s.Status == StatusRunning
@@ -126,6 +574,17 @@ func (s *SessionConfig) IsStopped() bool {
 	return s.Status == StatusStopped
 }
 
+// IsResumable returns true if a previous start did not complete
+// Pending, Starting and Failed sessions can be resumed by running start again.
+func (s *SessionConfig) IsResumable() bool {
+	return s.Status == StatusPending || s.Status == StatusStarting || s.Status == StatusFailed
+}
+
+// IsExpired returns true if the session has an expiration time at or before now
+func (s *SessionConfig) IsExpired(now time.Time) bool {
+	return s.ExpiresAt != nil && !s.ExpiresAt.After(now)
+}
+
 // UpdateStatus updates the session status and timestamp
 func (s *SessionConfig) UpdateStatus(status SessionStatus) {
 	s.Status = status
@@ -2,15 +2,35 @@ package config
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
 )
 
+// ErrAgentBudgetExceeded is returned by StartAgent when the session has used up
+// its agent.maxRuns or agent.maxDuration budget
+var ErrAgentBudgetExceeded = errors.New("agent budget exceeded")
+
+// AgentRunOptions contains options for StartAgentWithOptions
+type AgentRunOptions struct {
+	IgnoreBudget bool   // Run even when the session's agent budget is used up
+	BaseCommit   string // Workspace HEAD before the task, see agent.HeadCommit (empty = HEAD after it)
+	StealLock    bool   // Take over the session lock from another user or run
+	Step         string // Plan step the task runs, recorded on the execution
+}
+
 // StartAgent initiates a coding agent task for this session
 func (s *SessionConfig) StartAgent(ctx context.Context, executor agent.CodingAgentExecutor, prompt string) error {
+	return s.StartAgentWithOptions(ctx, executor, prompt, AgentRunOptions{})
+}
+
+// StartAgentWithOptions initiates a coding agent task for this session
+// The task counts towards the session's agent usage, whether it succeeds or not.
+func (s *SessionConfig) StartAgentWithOptions(ctx context.Context, executor agent.CodingAgentExecutor, prompt string, opts AgentRunOptions) error {
 	// Validation
 	if prompt == "" {
 		return fmt.Errorf("prompt cannot be empty")
@@ -18,30 +38,115 @@ func (s *SessionConfig) StartAgent(ctx context.Context, executor agent.CodingAge
 	if !s.IsRunning() {
 		return fmt.Errorf("session must be running to start agent")
 	}
+	if !opts.IgnoreBudget {
+		if err := s.CheckAgentBudget(); err != nil {
+			return err
+		}
+	}
 
 	// Create execution record
 	execution := AgentExecution{
 		ExecutedAt: time.Now(),
 		Prompt:     prompt,
 		Status:     "running",
+		BaseCommit: opts.BaseCommit,
+		Step:       opts.Step,
+	}
+	execution.LogPath = agent.RunLogPath(execution.ExecutedAt)
+
+	// The agent time left in the budget bounds the task while it runs
+	taskCtx := ctx
+	if s.Agent.MaxDuration > 0 && !opts.IgnoreBudget {
+		var cancel context.CancelFunc
+		taskCtx, cancel = context.WithTimeout(ctx, s.Agent.MaxDuration-s.AgentUsage.Duration)
+		defer cancel()
 	}
 
-	// Start task
-	taskID, err := executor.TaskStart(ctx, s.Namespace, s.PodName, prompt)
+	// Run task
+	taskID, err := executor.TaskStart(taskCtx, s.Namespace, s.PodName, prompt, execution.LogPath)
+	s.AgentUsage.Runs++
+	s.AgentUsage.Duration += time.Since(execution.ExecutedAt).Round(time.Second)
 	if err != nil {
+		if ctx.Err() == nil && errors.Is(taskCtx.Err(), context.DeadlineExceeded) {
+			err = fmt.Errorf("stopped after using the remaining agent time of %s: %w", s.Agent.MaxDuration, err)
+		}
 		execution.Status = "failed"
 		execution.Error = err.Error()
 		s.RecordAgentExecution(execution)
 		return fmt.Errorf("failed to start agent task: %w", err)
 	}
 
+	// TaskStart returns once the task has exited in the pod, so it ran to completion
 	execution.TaskID = taskID
-	execution.Status = "completed" // For now, mark as completed immediately
+	execution.Status = "completed"
 	s.RecordAgentExecution(execution)
 
 	return nil
 }
 
+// CheckAgentBudget returns ErrAgentBudgetExceeded when another agent task would
+// exceed agent.maxRuns or agent.maxDuration
+func (s *SessionConfig) CheckAgentBudget() error {
+	if s.Agent.MaxRuns > 0 && s.AgentUsage.Runs >= s.Agent.MaxRuns {
+		return fmt.Errorf("%w: %d of %d runs used", ErrAgentBudgetExceeded, s.AgentUsage.Runs, s.Agent.MaxRuns)
+	}
+	if s.Agent.MaxDuration > 0 && s.AgentUsage.Duration >= s.Agent.MaxDuration {
+		return fmt.Errorf("%w: %s of %s agent time used", ErrAgentBudgetExceeded, s.AgentUsage.Duration, s.Agent.MaxDuration)
+	}
+	return nil
+}
+
+// RecordAgentChanges stores the workspace changes on the last agent execution
+func (s *SessionConfig) RecordAgentChanges(changes *agent.Changes) {
+	execution := s.GetLastAgentExecution()
+	if execution == nil || changes == nil {
+		return
+	}
+	execution.DiffStat = changes.Stat
+	execution.ChangedFiles = changes.Files
+	s.UpdatedAt = time.Now()
+}
+
+// ReviewAgentRun records the workspace changes after the last agent execution and
+// checks them against Agent.ProtectedPaths. Changes are taken since the
+// execution's BaseCommit, so files the agent committed itself are checked too.
+// Changes to protected paths mark the execution needs-review, and are reverted
+// first when Agent.OnProtectedChange is "revert".
+func (s *SessionConfig) ReviewAgentRun(ctx context.Context, executor kubernetes.CommandExecutor) error {
+	execution := s.GetLastAgentExecution()
+	if execution == nil {
+		return nil
+	}
+
+	changes, err := agent.CollectChanges(ctx, executor, s.Namespace, s.PodName, execution.BaseCommit)
+	if err != nil {
+		return err
+	}
+	s.RecordAgentChanges(changes)
+
+	protected := agent.MatchProtectedPaths(s.Agent.ProtectedPaths, changes.Files)
+	if len(protected) == 0 {
+		return nil
+	}
+	execution.ProtectedChanges = protected
+	execution.Status = AgentStatusNeedsReview
+
+	if s.Agent.OnProtectedChange != ProtectedChangeRevert {
+		return nil
+	}
+	if err := agent.RevertFiles(ctx, executor, s.Namespace, s.PodName, execution.BaseCommit, protected); err != nil {
+		return err
+	}
+	execution.ProtectedReverted = true
+
+	changes, err = agent.CollectChanges(ctx, executor, s.Namespace, s.PodName, execution.BaseCommit)
+	if err != nil {
+		return err
+	}
+	s.RecordAgentChanges(changes)
+	return nil
+}
+
 // ReadPromptFromFile reads prompt content from a file
 func ReadPromptFromFile(filePath string) (string, error) {
 	if filePath == "" {
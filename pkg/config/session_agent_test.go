@@ -6,11 +6,13 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
 )
 
 func TestSessionConfig_StartAgent_Success(t *testing.T) {
@@ -37,6 +39,8 @@ func TestSessionConfig_StartAgent_Success(t *testing.T) {
 	assert.Equal(t, "test-ns", calls[0].Namespace)
 	assert.Equal(t, "test-pod", calls[0].PodName)
 	assert.Equal(t, "test prompt", calls[0].Prompt)
+	assert.Equal(t, session.AgentExecutions[0].LogPath, calls[0].LogPath)
+	assert.Equal(t, agent.RunLogPath(session.AgentExecutions[0].ExecutedAt), calls[0].LogPath)
 }
 
 func TestSessionConfig_StartAgent_EmptyPrompt(t *testing.T) {
@@ -77,7 +81,7 @@ func TestSessionConfig_StartAgent_SessionNotRunning(t *testing.T) {
 
 func TestSessionConfig_StartAgent_ExecutorError(t *testing.T) {
 	mock := agent.NewMockCodingAgentExecutor()
-	mock.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt string) (string, error) {
+	mock.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
 		return "", fmt.Errorf("executor failed")
 	}
 
@@ -196,3 +200,152 @@ func TestTruncatePrompt(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionConfig_ReviewAgentRun(t *testing.T) {
+	newSession := func(onChange string) *SessionConfig {
+		return &SessionConfig{
+			Name: "test-session", Namespace: "test-ns", PodName: "test-pod", Status: StatusRunning,
+			Agent:           AgentConfig{ProtectedPaths: []string{"infra/**"}, OnProtectedChange: onChange},
+			AgentExecutions: []AgentExecution{{Status: "completed"}},
+		}
+	}
+	newExecutor := func() *kubernetes.MockExecutor {
+		executor := kubernetes.NewMockExecutor()
+		executor.SetResponse("sh -c "+agent.DiffStatScript, " infra/main.tf | 2 +-\n src/app.go | 1 +\n", "", nil)
+		executor.SetResponse("sh -c cd /workspace && git diff --name-only", "infra/main.tf\nsrc/app.go\n", "", nil)
+		return executor
+	}
+
+	t.Run("flag", func(t *testing.T) {
+		session := newSession("")
+		executor := newExecutor()
+		require.NoError(t, session.ReviewAgentRun(context.Background(), executor))
+
+		execution := session.GetLastAgentExecution()
+		assert.Equal(t, AgentStatusNeedsReview, execution.Status)
+		assert.Equal(t, []string{"infra/main.tf"}, execution.ProtectedChanges)
+		assert.False(t, execution.ProtectedReverted)
+		assert.Equal(t, []string{"infra/main.tf", "src/app.go"}, execution.ChangedFiles)
+		assert.Len(t, executor.GetCommands(), 2, "nothing is reverted")
+	})
+
+	t.Run("revert", func(t *testing.T) {
+		session := newSession(ProtectedChangeRevert)
+		executor := newExecutor()
+		require.NoError(t, session.ReviewAgentRun(context.Background(), executor))
+
+		execution := session.GetLastAgentExecution()
+		assert.Equal(t, AgentStatusNeedsReview, execution.Status)
+		assert.True(t, execution.ProtectedReverted)
+		assert.Contains(t, executor.GetCommands()[2].Command, "infra/main.tf")
+	})
+
+	t.Run("changes since base commit", func(t *testing.T) {
+		session := newSession(ProtectedChangeRevert)
+		session.AgentExecutions[0].BaseCommit = "abc123"
+		executor := newExecutor()
+		require.NoError(t, session.ReviewAgentRun(context.Background(), executor))
+
+		commands := executor.GetCommands()
+		require.Len(t, commands, 5)
+		for _, command := range commands {
+			assert.Equal(t, "abc123", command.Command[4], "every step works against the base commit")
+		}
+	})
+
+	t.Run("no protected changes", func(t *testing.T) {
+		session := newSession(ProtectedChangeRevert)
+		session.Agent.ProtectedPaths = []string{".github/workflows/**"}
+		require.NoError(t, session.ReviewAgentRun(context.Background(), newExecutor()))
+		assert.Equal(t, "completed", session.GetLastAgentExecution().Status)
+	})
+}
+
+func TestAgentConfig_Merge(t *testing.T) {
+	global := AgentConfig{ProtectedPaths: []string{"infra/**"}, OnProtectedChange: "flag"}
+	merged := global.Merge(AgentConfig{ProtectedPaths: []string{".github/workflows/**"}, OnProtectedChange: ProtectedChangeRevert})
+
+	assert.Equal(t, []string{"infra/**", ".github/workflows/**"}, merged.ProtectedPaths)
+	assert.Equal(t, ProtectedChangeRevert, merged.OnProtectedChange)
+	assert.Equal(t, []string{"infra/**"}, global.ProtectedPaths, "global is not modified")
+
+	merged = AgentConfig{MaxRuns: 5, MaxDuration: 2 * time.Hour, ContextFiles: []string{"docs/style.md"}}.Merge(AgentConfig{MaxRuns: 2})
+	assert.Equal(t, 2, merged.MaxRuns)
+	assert.Equal(t, 2*time.Hour, merged.MaxDuration)
+	assert.Equal(t, []string{"docs/style.md"}, merged.ContextFiles)
+
+	merged = merged.Merge(AgentConfig{ContextFiles: []string{"docs/design.md"}})
+	assert.Equal(t, []string{"docs/design.md"}, merged.ContextFiles, "template context files replace global ones")
+}
+
+func TestSessionConfig_StartAgent_Budget(t *testing.T) {
+	newSession := func() *SessionConfig {
+		return &SessionConfig{
+			Name:      "test-session",
+			Namespace: "test-ns",
+			PodName:   "test-pod",
+			Status:    StatusRunning,
+			Agent:     AgentConfig{MaxRuns: 2, MaxDuration: time.Hour},
+		}
+	}
+
+	t.Run("usage is recorded", func(t *testing.T) {
+		session := newSession()
+		mock := agent.NewMockCodingAgentExecutor()
+		mock.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
+			return "", fmt.Errorf("boom")
+		}
+
+		require.NoError(t, session.StartAgent(context.Background(), agent.NewMockCodingAgentExecutor(), "one"))
+		require.Error(t, session.StartAgent(context.Background(), mock, "two"))
+		assert.Equal(t, 2, session.AgentUsage.Runs, "failed runs count toward the budget")
+	})
+
+	t.Run("runs exceeded", func(t *testing.T) {
+		session := newSession()
+		session.AgentUsage.Runs = 2
+		mock := agent.NewMockCodingAgentExecutor()
+
+		err := session.StartAgent(context.Background(), mock, "prompt")
+		require.ErrorIs(t, err, ErrAgentBudgetExceeded)
+		assert.Contains(t, err.Error(), "2 of 2 runs used")
+		assert.Empty(t, mock.GetTaskStartCalls())
+		assert.Empty(t, session.AgentExecutions)
+	})
+
+	t.Run("duration exceeded", func(t *testing.T) {
+		session := newSession()
+		session.AgentUsage.Duration = 90 * time.Minute
+
+		err := session.StartAgent(context.Background(), agent.NewMockCodingAgentExecutor(), "prompt")
+		require.ErrorIs(t, err, ErrAgentBudgetExceeded)
+		assert.Contains(t, err.Error(), "1h30m0s of 1h0m0s agent time used")
+	})
+
+	t.Run("remaining time bounds the running task", func(t *testing.T) {
+		session := newSession()
+		session.Agent.MaxDuration = time.Hour + 1500*time.Millisecond
+		session.AgentUsage.Duration = time.Hour
+		mock := agent.NewMockCodingAgentExecutor()
+		mock.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+
+		err := session.StartAgent(context.Background(), mock, "long task")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "remaining agent time")
+		assert.Equal(t, "failed", session.GetLastAgentExecution().Status)
+		assert.GreaterOrEqual(t, session.AgentUsage.Duration, time.Hour+time.Second, "the task's wall-clock time is counted")
+		require.ErrorIs(t, session.CheckAgentBudget(), ErrAgentBudgetExceeded)
+	})
+
+	t.Run("ignore budget", func(t *testing.T) {
+		session := newSession()
+		session.AgentUsage.Runs = 2
+
+		err := session.StartAgentWithOptions(context.Background(), agent.NewMockCodingAgentExecutor(), "prompt", AgentRunOptions{IgnoreBudget: true})
+		require.NoError(t, err)
+		assert.Equal(t, 3, session.AgentUsage.Runs)
+	})
+}
@@ -0,0 +1,52 @@
+package config
+
+import (
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// CreatedResource is a Secret or ConfigMap created for a session in its namespace
+type CreatedResource struct {
+	Kind string `yaml:"kind"` // kubernetes.KindSecret or kubernetes.KindConfigMap
+	Name string `yaml:"name"`
+}
+
+// TrackResource records a Secret or ConfigMap created for the session
+func (s *SessionConfig) TrackResource(kind, name string) {
+	for _, resource := range s.CreatedResources {
+		if resource.Kind == kind && resource.Name == name {
+			return
+		}
+	}
+	s.CreatedResources = append(s.CreatedResources, CreatedResource{Kind: kind, Name: name})
+}
+
+// TrackedResources returns the session's CreatedResources along with the env
+// secret, file secret and editor ConfigMap recorded by older versions
+func (s *SessionConfig) TrackedResources() []kubernetes.SessionResource {
+	created := append([]CreatedResource(nil), s.CreatedResources...)
+	if s.Env.SecretCreated && s.Env.SecretName != "" {
+		created = append(created, CreatedResource{Kind: kubernetes.KindSecret, Name: s.Env.SecretName})
+	}
+	if s.SecretFile.SecretCreated && s.SecretFile.SecretName != "" {
+		created = append(created, CreatedResource{Kind: kubernetes.KindSecret, Name: s.SecretFile.SecretName})
+	}
+	if s.Editor.ConfigMapCreated && s.Editor.ConfigMapName != "" {
+		created = append(created, CreatedResource{Kind: kubernetes.KindConfigMap, Name: s.Editor.ConfigMapName})
+	}
+
+	var resources []kubernetes.SessionResource
+	seen := make(map[CreatedResource]bool, len(created))
+	for _, resource := range created {
+		if seen[resource] {
+			continue
+		}
+		seen[resource] = true
+		resources = append(resources, kubernetes.SessionResource{
+			Kind:      resource.Kind,
+			Name:      resource.Name,
+			Namespace: s.Namespace,
+			Session:   s.Name,
+		})
+	}
+	return resources
+}
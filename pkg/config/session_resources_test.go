@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/illumination-k/kodama/pkg/env"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestSessionConfig_TrackedResources(t *testing.T) {
+	session := &SessionConfig{Name: "work", Namespace: "dev"}
+	session.TrackResource(kubernetes.KindSecret, "kodama-env-work")
+	session.TrackResource(kubernetes.KindSecret, "kodama-env-work")
+	session.TrackResource(kubernetes.KindConfigMap, "kodama-editor-work")
+	assert.Len(t, session.CreatedResources, 2)
+
+	// Sessions saved by older versions only carry the created flags
+	session.Env = env.EnvConfig{SecretName: "kodama-env-work", SecretCreated: true}
+	session.SecretFile.SecretName = "kodama-secret-files-work"
+	session.SecretFile.SecretCreated = true
+
+	assert.Equal(t, []kubernetes.SessionResource{
+		{Kind: kubernetes.KindSecret, Name: "kodama-env-work", Namespace: "dev", Session: "work"},
+		{Kind: kubernetes.KindConfigMap, Name: "kodama-editor-work", Namespace: "dev", Session: "work"},
+		{Kind: kubernetes.KindSecret, Name: "kodama-secret-files-work", Namespace: "dev", Session: "work"},
+	}, session.TrackedResources())
+}
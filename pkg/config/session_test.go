@@ -38,6 +38,14 @@ func TestSessionConfig_Validate(t *testing.T) {
 			},
 			wantErr: ErrNamespaceRequired,
 		},
+		{
+			name: "local runtime without namespace",
+			config: &SessionConfig{
+				Name:    "test-session",
+				Runtime: RuntimeDocker,
+			},
+			wantErr: nil,
+		},
 		{
 			name: "missing repo (now optional)",
 			config: &SessionConfig{
@@ -48,6 +56,11 @@ func TestSessionConfig_Validate(t *testing.T) {
 		},
 	}
 
+	invalid := &SessionConfig{Name: "test-session", Namespace: "default", Sync: SyncConfig{Compression: "brotli"}}
+	assert.ErrorContains(t, invalid.Validate(), `unknown sync.compression "brotli"`)
+	invalid = &SessionConfig{Name: "test-session", Runtime: "lxc"}
+	assert.ErrorContains(t, invalid.Validate(), `unknown runtime "lxc"`)
+
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			err := tt.config.Validate()
@@ -82,6 +95,41 @@ func TestSessionConfig_IsStopped(t *testing.T) {
 	assert.False(t, config.IsStopped())
 }
 
+func TestSessionConfig_IsResumable(t *testing.T) {
+	tests := []struct {
+		status SessionStatus
+		want   bool
+	}{
+		{StatusPending, true},
+		{StatusStarting, true},
+		{StatusFailed, true},
+		{StatusRunning, false},
+		{StatusStopped, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			config := &SessionConfig{Status: tt.status}
+			assert.Equal(t, tt.want, config.IsResumable())
+		})
+	}
+}
+
+func TestSessionConfig_IsExpired(t *testing.T) {
+	now := time.Now()
+
+	config := &SessionConfig{}
+	assert.False(t, config.IsExpired(now), "no expiration set")
+
+	future := now.Add(time.Hour)
+	config.ExpiresAt = &future
+	assert.False(t, config.IsExpired(now))
+
+	past := now.Add(-time.Minute)
+	config.ExpiresAt = &past
+	assert.True(t, config.IsExpired(now))
+}
+
 func TestSessionConfig_UpdateStatus(t *testing.T) {
 	config := &SessionConfig{
 		Status:    StatusPending,
@@ -195,3 +243,91 @@ func TestSessionConfig_HasPendingAgentTask(t *testing.T) {
 	})
 	assert.False(t, config3.HasPendingAgentTask())
 }
+
+func TestGitSigningConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		signing GitSigningConfig
+		wantErr string
+	}{
+		{"disabled", GitSigningConfig{Format: "gpg"}, ""},
+		{"ssh by default", GitSigningConfig{Secret: "git-signing"}, ""},
+		{"gpg with key", GitSigningConfig{Format: GitSigningGPG, Secret: "git-signing", Key: "ABCD1234"}, ""},
+		{"gpg without key", GitSigningConfig{Format: GitSigningGPG, Secret: "git-signing"}, "requires gitSigning.key"},
+		{"unknown format", GitSigningConfig{Format: "x509", Secret: "git-signing"}, "unknown gitSigning.format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &SessionConfig{Name: "demo", Namespace: "default", GitSigning: tt.signing}
+			err := session.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateShell(t *testing.T) {
+	for _, shell := range []string{"", "zsh", "/bin/bash", "/usr/local/bin/fish"} {
+		assert.NoError(t, ValidateShell(shell), shell)
+	}
+	for _, shell := range []string{"zsh -l", "bash;rm", "$(id)", "/bin/", "ba'sh"} {
+		assert.Error(t, ValidateShell(shell), shell)
+	}
+}
+
+func TestValidateLocale(t *testing.T) {
+	for _, locale := range []string{"", "C.UTF-8", "en_US.UTF-8", "de_DE@euro"} {
+		assert.NoError(t, ValidateLocale(locale), locale)
+	}
+	for _, locale := range []string{"en US", "C.UTF-8;id", "$(id)"} {
+		assert.Error(t, ValidateLocale(locale), locale)
+	}
+}
+
+func TestPodRestartPolicy(t *testing.T) {
+	assert.NoError(t, ValidateRestartPolicy(""))
+	assert.NoError(t, ValidateRestartPolicy(RestartPolicyOnFailure))
+	assert.Error(t, ValidateRestartPolicy("onfailure"))
+
+	assert.Equal(t, RestartPolicyNever, (&SessionConfig{}).PodRestartPolicy())
+	assert.Equal(t, RestartPolicyOnFailure, (&SessionConfig{Command: []string{"make", "serve"}}).PodRestartPolicy())
+	assert.Equal(t, RestartPolicyAlways, (&SessionConfig{RestartPolicy: RestartPolicyAlways}).PodRestartPolicy())
+	assert.Equal(t, RestartPolicyNever, (&SessionConfig{Command: []string{"make"}, RestartPolicy: RestartPolicyNever}).PodRestartPolicy())
+}
+
+func TestSyncConfig_RemoteRoot(t *testing.T) {
+	assert.Equal(t, "/workspace", SyncConfig{}.RemoteRoot())
+	assert.Equal(t, "/workspace/services/payments", SyncConfig{RemotePath: "/workspace/services/payments/"}.RemoteRoot())
+	assert.Equal(t, "/workspace/services/payments", SyncConfig{RemotePath: "services/payments"}.RemoteRoot())
+
+	assert.NoError(t, ValidateSyncRemotePath(""))
+	assert.NoError(t, ValidateSyncRemotePath("/workspace"))
+	assert.NoError(t, ValidateSyncRemotePath("services/payments"))
+	for _, remote := range []string{"/etc", "/workspace2/app", "/workspace/../etc", "../outside"} {
+		assert.Error(t, ValidateSyncRemotePath(remote), remote)
+	}
+}
+
+func TestDotfilesConfig_Validate(t *testing.T) {
+	assert.NoError(t, DotfilesConfig{}.Validate())
+	assert.NoError(t, DotfilesConfig{Repo: "https://github.com/me/dotfiles", InstallCommand: "stow -t ~ zsh"}.Validate())
+	assert.Error(t, DotfilesConfig{InstallCommand: "./install.sh"}.Validate())
+	assert.Error(t, DotfilesConfig{Repo: "--upload-pack=touch /tmp/x"}.Validate())
+}
+
+func TestGitConfig_Validate(t *testing.T) {
+	valid := GitConfig{InstallHooks: true, Hooks: map[string]string{"pre-commit": "scripts/pre-commit.sh", "commit-msg": ".githooks/commit-msg"}}
+	assert.NoError(t, valid.Validate())
+
+	for _, hooks := range []map[string]string{
+		{"Pre Commit": "scripts/pre-commit.sh"},
+		{"pre-commit": "/etc/hook"},
+		{"pre-commit": "../outside.sh"},
+		{"pre-commit": ""},
+	} {
+		assert.Error(t, GitConfig{Hooks: hooks}.Validate(), hooks)
+	}
+}
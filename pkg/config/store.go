@@ -1,13 +1,19 @@
 package config
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/illumination-k/kodama/pkg/encryption"
+	"github.com/illumination-k/kodama/pkg/sops"
 )
 
 // ErrSessionNotFound is returned when a session config file doesn't exist
@@ -22,11 +28,58 @@ const (
 
 	// GlobalConfigFile is the filename for global configuration
 	GlobalConfigFile = "config.yaml"
+
+	// KeyFile is the plaintext encryption key written by older versions
+	// It is read for compatibility and moved into the key source by 'store encrypt'.
+	KeyFile = "store.key"
+
+	// AgeKeyFile is the filename for the age-encrypted store key (store.keySource: age)
+	AgeKeyFile = "store.key.age"
+
+	// AuthFile is the filename for cached agent credentials
+	AuthFile = "claude-auth.json"
+
+	// SSHSubdir holds per-session SSH key pairs and client configs
+	SSHSubdir = "ssh"
+
+	// ArtifactsSubdir holds files copied out of a session, below its session directory
+	ArtifactsSubdir = "artifacts"
+
+	// CurrentSessionFile holds the name of the session selected with 'kodama use'
+	CurrentSessionFile = "current-session"
+
+	// ProfilesSubdir holds named session templates that templates can extend
+	ProfilesSubdir = "profiles"
+
+	// maxExtendsDepth bounds how many templates an extends chain can hold
+	maxExtendsDepth = 10
+
+	// SyncManifestFile records the files of the last sync, below its session directory
+	SyncManifestFile = "sync-manifest"
+
+	// EventsFile holds the session's event timeline, below its session directory
+	EventsFile = "events.jsonl"
+
+	// KubectlCheckFile caches the kubectl checks run at startup, per kubeconfig context
+	KubectlCheckFile = "kubectl-check.json"
 )
 
 // Store handles reading and writing configuration files
+// Session files are transparently encrypted when store.encrypt is enabled in global config
 type Store struct {
+	keys      encryption.KeyStore
 	configDir string
+
+	// mu guards the cipher, which is loaded on first use and shared by
+	// concurrent requests in 'serve'
+	mu      sync.Mutex
+	cipher  *encryption.Cipher
+	encrypt bool
+
+	// configErr and keyErr are reported when a file is written, not when the
+	// store is created, so commands that don't touch the store still run
+	configErr error
+	keyErr    error
 }
 
 // NewStore creates a new configuration store
@@ -36,13 +89,17 @@ func NewStore() (*Store, error) {
 		return nil, fmt.Errorf("failed to get user home directory: %w", err)
 	}
 
-	configDir := filepath.Join(home, DefaultConfigDir)
-	return &Store{configDir: configDir}, nil
+	store := NewStoreWithPath(filepath.Join(home, DefaultConfigDir))
+	store.configureEncryption()
+
+	return store, nil
 }
 
 // NewStoreWithPath creates a store with a custom config directory
 func NewStoreWithPath(configDir string) *Store {
-	return &Store{configDir: configDir}
+	s := &Store{configDir: configDir}
+	s.keys = s.keyStore(StoreConfig{})
+	return s
 }
 
 // EnsureConfigDir creates the configuration directory structure if it doesn't exist
@@ -62,8 +119,27 @@ func (s *Store) EnsureConfigDir() error {
 }
 
 // GetSessionPath returns the file path for a session config
-func (s *Store) GetSessionPath(name string) string {
-	return filepath.Join(s.configDir, SessionsSubdir, name+".yaml")
+// Invalid names are rejected, so no name can point outside the sessions directory.
+func (s *Store) GetSessionPath(name string) (string, error) {
+	if err := ValidateSessionName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.configDir, SessionsSubdir, name+".yaml"), nil
+}
+
+// GetSessionDir returns the directory holding a session's local files, next to its config
+func (s *Store) GetSessionDir(name string) string {
+	return filepath.Join(s.configDir, SessionsSubdir, name)
+}
+
+// GetArtifactsDir returns the directory holding files copied out of a session, e.g. agent logs
+func (s *Store) GetArtifactsDir(name string) string {
+	return filepath.Join(s.GetSessionDir(name), ArtifactsSubdir)
+}
+
+// GetProfilePath returns the file path of a named template profile
+func (s *Store) GetProfilePath(name string) string {
+	return filepath.Join(s.configDir, ProfilesSubdir, name+".yaml")
 }
 
 // GetGlobalConfigPath returns the file path for global config
@@ -71,12 +147,178 @@ func (s *Store) GetGlobalConfigPath() string {
 	return filepath.Join(s.configDir, GlobalConfigFile)
 }
 
+// GetKubectlCheckPath returns the file path of the cached kubectl checks
+func (s *Store) GetKubectlCheckPath() string {
+	return filepath.Join(s.configDir, KubectlCheckFile)
+}
+
+// GetKeyPath returns the file path of the plaintext key written by older versions
+func (s *Store) GetKeyPath() string {
+	return filepath.Join(s.configDir, KeyFile)
+}
+
+// Keys returns where the store's encryption key is kept
+func (s *Store) Keys() encryption.KeyStore {
+	return s.keys
+}
+
+// GetSSHDir returns the directory holding a session's SSH key pair and client config
+func (s *Store) GetSSHDir(name string) string {
+	return filepath.Join(s.configDir, SSHSubdir, name)
+}
+
+// EncryptionEnabled reports whether session files are written encrypted
+func (s *Store) EncryptionEnabled() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.encrypt
+}
+
+// keyStore returns the key store for the store settings in the global config
+func (s *Store) keyStore(cfg StoreConfig) encryption.KeyStore {
+	// The age CLI does not expand ~ in the identity path
+	if cfg.AgeIdentity != "" {
+		if path, err := ResolvePath(cfg.AgeIdentity); err == nil {
+			cfg.AgeIdentity = path
+		}
+	}
+
+	return encryption.KeyStore{
+		Source:       encryption.KeySource(cfg.KeySource),
+		Account:      s.configDir,
+		AgeFile:      filepath.Join(s.configDir, AgeKeyFile),
+		AgeRecipient: cfg.AgeRecipient,
+		AgeIdentity:  cfg.AgeIdentity,
+		LegacyPath:   s.GetKeyPath(),
+	}
+}
+
+// configureEncryption enables encryption if requested by the global config
+// An unreadable global config, or a key that can't be loaded, fails every write
+// instead of the store being configured, so nothing is written in plaintext
+// when encryption may have been requested.
+func (s *Store) configureEncryption() {
+	globalConfig, err := s.LoadGlobalConfig()
+	if err != nil {
+		s.configErr = err
+		return
+	}
+
+	s.keys = s.keyStore(globalConfig.Store)
+	if !globalConfig.Store.Encrypt {
+		return
+	}
+
+	if err := s.EnableEncryption(); err != nil {
+		s.keyErr = err
+	}
+}
+
+// EnableEncryption turns on encryption for subsequent writes, creating a key if needed
+func (s *Store) EnableEncryption() error {
+	key, err := s.keys.LoadOrCreate()
+	if err != nil {
+		return fmt.Errorf("failed to load encryption key: %w", err)
+	}
+
+	c, err := encryption.NewCipher(key)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cipher = c
+	s.encrypt = true
+	s.keyErr = nil
+	return nil
+}
+
+// loadCipher returns the cipher, loading the key on first use
+// Encrypted files can always be read as long as the key is available, even if
+// encryption is currently disabled for writes.
+func (s *Store) loadCipher() (*encryption.Cipher, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cipher != nil {
+		return s.cipher, nil
+	}
+
+	key, err := s.keys.Load()
+	if err != nil {
+		return nil, err
+	}
+	c, err := encryption.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	s.cipher = c
+	return c, nil
+}
+
+// Decrypt decrypts data written encrypted by the store, such as the auth cache
+func (s *Store) Decrypt(data []byte) ([]byte, error) {
+	c, err := s.loadCipher()
+	if err != nil {
+		return nil, fmt.Errorf("data is encrypted but no key is available: %w", err)
+	}
+	return c.Decrypt(data)
+}
+
+// readFile reads a file, decrypting it if it was written encrypted
+func (s *Store) readFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- path is constructed by the store
+	if err != nil {
+		return nil, err
+	}
+
+	if !encryption.IsEncrypted(data) {
+		return data, nil
+	}
+
+	c, err := s.loadCipher()
+	if err != nil {
+		return nil, fmt.Errorf("file %s is encrypted but no key is available: %w", path, err)
+	}
+	return c.Decrypt(data)
+}
+
+// writeFile writes a file, encrypting it if encryption is enabled
+func (s *Store) writeFile(path string, data []byte) error {
+	s.mu.Lock()
+	c, keyErr := s.cipher, s.keyErr
+	if !s.encrypt {
+		c = nil
+	}
+	s.mu.Unlock()
+
+	if keyErr != nil {
+		return fmt.Errorf("store.encrypt is enabled but %w", keyErr)
+	}
+	if s.configErr != nil {
+		return fmt.Errorf("refusing to write %s: store.encrypt could not be checked: %w", path, s.configErr)
+	}
+
+	if c != nil {
+		encrypted, err := c.Encrypt(data)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt %s: %w", path, err)
+		}
+		data = encrypted
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
+
 // LoadSession loads a session configuration from disk
 func (s *Store) LoadSession(name string) (*SessionConfig, error) {
-	path := s.GetSessionPath(name)
+	path, err := s.GetSessionPath(name)
+	if err != nil {
+		return nil, err
+	}
 
-	// #nosec G304 -- path is constructed from validated session name
-	data, err := os.ReadFile(path)
+	data, err := s.readFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return nil, ErrSessionNotFound
@@ -84,6 +326,12 @@ func (s *Store) LoadSession(name string) (*SessionConfig, error) {
 		return nil, fmt.Errorf("failed to read session config: %w", err)
 	}
 
+	// Older files are upgraded in memory; 'kodama config migrate' rewrites them
+	data, _, err = migrateDocument(data, sessionMigrations, SessionSchemaVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session config %s: %w", path, err)
+	}
+
 	var config SessionConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse session config: %w", err)
@@ -102,14 +350,18 @@ func (s *Store) SaveSession(config *SessionConfig) error {
 		return err
 	}
 
-	path := s.GetSessionPath(config.Name)
+	path, err := s.GetSessionPath(config.Name)
+	if err != nil {
+		return err
+	}
 
+	config.SchemaVersion = SessionSchemaVersion
 	data, err := yaml.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal session config: %w", err)
 	}
 
-	if err := os.WriteFile(path, data, 0o600); err != nil {
+	if err := s.writeFile(path, data); err != nil {
 		return fmt.Errorf("failed to write session config: %w", err)
 	}
 
@@ -119,7 +371,9 @@ func (s *Store) SaveSession(config *SessionConfig) error {
 // LoadSessionTemplate loads a session template configuration from an arbitrary path
 // This is used for --config flag to load session templates.
 // Unlike LoadSession, this does not validate the config as templates can be partial.
-func (s *Store) LoadSessionTemplate(path string) (*SessionConfig, error) {
+// Go-template expressions are rendered before parsing, with values from the global
+// config overlaid by the given values (e.g. from --set).
+func (s *Store) LoadSessionTemplate(path string, values map[string]interface{}) (*SessionConfig, error) {
 	// Validate path exists
 	if _, err := os.Stat(path); err != nil {
 		if os.IsNotExist(err) {
@@ -134,6 +388,33 @@ func (s *Store) LoadSessionTemplate(path string) (*SessionConfig, error) {
 		return nil, fmt.Errorf("failed to read session template: %w", err)
 	}
 
+	// Decrypt SOPS-encrypted templates in memory
+	if sops.IsEncrypted(data) {
+		data, err = sops.Decrypt(context.Background(), path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt session template: %w", err)
+		}
+	}
+
+	globalConfig, err := s.LoadGlobalConfig()
+	if err != nil {
+		return nil, err
+	}
+	data, err = RenderSessionTemplate(filepath.Base(path), data, TemplateData{
+		Values:   MergeValues(globalConfig.Values, values),
+		Env:      EnvironMap(),
+		Defaults: globalConfig.Defaults,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render session template: %w", err)
+	}
+
+	// Templates live in repositories and may predate field renames too
+	data, _, err = migrateDocument(data, sessionMigrations, SessionSchemaVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session template %s: %w", path, err)
+	}
+
 	var config SessionConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse session template: %w", err)
@@ -143,9 +424,58 @@ func (s *Store) LoadSessionTemplate(path string) (*SessionConfig, error) {
 	return &config, nil
 }
 
+// LoadSessionTemplates loads the session template at path and every template
+// it extends, returned base first for NewConfigResolver
+func (s *Store) LoadSessionTemplates(path string, values map[string]interface{}) ([]*SessionConfig, error) {
+	var chain []*SessionConfig
+	seen := map[string]bool{}
+	for path != "" {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve template path %s: %w", path, err)
+		}
+		if seen[absPath] {
+			return nil, fmt.Errorf("session template %s extends itself", absPath)
+		}
+		if len(chain) == maxExtendsDepth {
+			return nil, fmt.Errorf("session template %s extends more than %d templates", absPath, maxExtendsDepth)
+		}
+		seen[absPath] = true
+
+		template, err := s.LoadSessionTemplate(absPath, values)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, template)
+		path = s.resolveExtends(absPath, template.Extends)
+	}
+
+	slices.Reverse(chain)
+	return chain, nil
+}
+
+// resolveExtends returns the path of the template extended from the template
+// at from: a value without a directory or .yaml extension names a profile,
+// and relative paths are relative to the extending template
+func (s *Store) resolveExtends(from, extends string) string {
+	switch {
+	case extends == "":
+		return ""
+	case !strings.ContainsAny(extends, `/\`) && filepath.Ext(extends) == "":
+		return s.GetProfilePath(extends)
+	case filepath.IsAbs(extends):
+		return extends
+	default:
+		return filepath.Join(filepath.Dir(from), extends)
+	}
+}
+
 // DeleteSession removes a session configuration from disk
 func (s *Store) DeleteSession(name string) error {
-	path := s.GetSessionPath(name)
+	path, err := s.GetSessionPath(name)
+	if err != nil {
+		return err
+	}
 
 	if err := os.Remove(path); err != nil {
 		if os.IsNotExist(err) {
@@ -154,6 +484,70 @@ func (s *Store) DeleteSession(name string) error {
 		return fmt.Errorf("failed to delete session config: %w", err)
 	}
 
+	// A deleted session cannot stay the current one
+	if current, err := s.CurrentSession(); err == nil && current == name {
+		return s.SetCurrentSession("")
+	}
+
+	return nil
+}
+
+// CurrentSession returns the session selected with 'kodama use', or "" if none is set
+func (s *Store) CurrentSession() (string, error) {
+	// #nosec G304 -- path is constructed from config directory
+	data, err := os.ReadFile(filepath.Join(s.configDir, CurrentSessionFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to read current session: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SetCurrentSession records the session used when commands are run without a name
+// An empty name clears it.
+func (s *Store) SetCurrentSession(name string) error {
+	path := filepath.Join(s.configDir, CurrentSessionFile)
+	if name == "" {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to clear current session: %w", err)
+		}
+		return nil
+	}
+
+	if err := s.EnsureConfigDir(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0o600); err != nil {
+		return fmt.Errorf("failed to write current session: %w", err)
+	}
+	return nil
+}
+
+// LoadSyncManifest returns the files recorded after the session's last sync,
+// or an empty manifest if none were recorded
+func (s *Store) LoadSyncManifest(name string) (SyncManifest, error) {
+	// #nosec G304 -- path is constructed from config directory
+	data, err := os.ReadFile(filepath.Join(s.GetSessionDir(name), SyncManifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return SyncManifest{}, nil
+		}
+		return nil, fmt.Errorf("failed to read sync manifest: %w", err)
+	}
+	return ParseSyncManifest(data)
+}
+
+// SaveSyncManifest records the files of the session's last sync
+func (s *Store) SaveSyncManifest(name string, manifest SyncManifest) error {
+	dir := s.GetSessionDir(name)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create session directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, SyncManifestFile), EncodeSyncManifest(manifest), 0o600); err != nil {
+		return fmt.Errorf("failed to write sync manifest: %w", err)
+	}
 	return nil
 }
 
@@ -192,6 +586,50 @@ func (s *Store) ListSessions() ([]*SessionConfig, error) {
 	return sessions, nil
 }
 
+// PruneOrphanedSessionDirs removes the session and SSH directories whose
+// session config is gone, e.g. after a session file was deleted by hand, and
+// returns them. With dryRun, they are only returned.
+func (s *Store) PruneOrphanedSessionDirs(dryRun bool) ([]string, error) {
+	var orphaned []string
+	for _, subdir := range []string{SessionsSubdir, SSHSubdir} {
+		entries, err := os.ReadDir(filepath.Join(s.configDir, subdir))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to read %s directory: %w", subdir, err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() || s.SessionExists(entry.Name()) {
+				continue
+			}
+			orphaned = append(orphaned, filepath.Join(s.configDir, subdir, entry.Name()))
+		}
+	}
+
+	if !dryRun {
+		for _, dir := range orphaned {
+			if err := os.RemoveAll(dir); err != nil {
+				return nil, fmt.Errorf("failed to remove %s: %w", dir, err)
+			}
+		}
+	}
+	return orphaned, nil
+}
+
+// QuerySessions returns the sessions matching query, in query order
+func (s *Store) QuerySessions(query SessionQuery) ([]*SessionConfig, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.ListSessions()
+	if err != nil {
+		return nil, err
+	}
+	return query.Apply(sessions), nil
+}
+
 // LoadGlobalConfig loads the global configuration
 func (s *Store) LoadGlobalConfig() (*GlobalConfig, error) {
 	path := s.GetGlobalConfigPath()
@@ -206,6 +644,11 @@ func (s *Store) LoadGlobalConfig() (*GlobalConfig, error) {
 		return nil, fmt.Errorf("failed to read global config: %w", err)
 	}
 
+	data, _, err = migrateDocument(data, globalMigrations, GlobalSchemaVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global config %s: %w", path, err)
+	}
+
 	var config GlobalConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse global config: %w", err)
@@ -226,6 +669,7 @@ func (s *Store) SaveGlobalConfig(config *GlobalConfig) error {
 
 	path := s.GetGlobalConfigPath()
 
+	config.SchemaVersion = GlobalSchemaVersion
 	data, err := yaml.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal global config: %w", err)
@@ -240,7 +684,115 @@ func (s *Store) SaveGlobalConfig(config *GlobalConfig) error {
 
 // SessionExists checks if a session configuration exists
 func (s *Store) SessionExists(name string) bool {
-	path := s.GetSessionPath(name)
-	_, err := os.Stat(path)
+	path, err := s.GetSessionPath(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
 	return err == nil
 }
+
+// MigrateToEncrypted enables encryption and rewrites existing plaintext session
+// files and the auth cache in encrypted form. Returns the number of files migrated.
+func (s *Store) MigrateToEncrypted() (int, error) {
+	if err := s.EnableEncryption(); err != nil {
+		return 0, err
+	}
+
+	paths := []string{filepath.Join(s.configDir, AuthFile)}
+
+	sessionsDir := filepath.Join(s.configDir, SessionsSubdir)
+	entries, err := os.ReadDir(sessionsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return 0, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		paths = append(paths, filepath.Join(sessionsDir, entry.Name()))
+	}
+
+	migrated := 0
+	for _, path := range paths {
+		// #nosec G304 -- path is constructed by the store
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return migrated, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+
+		if encryption.IsEncrypted(data) {
+			continue
+		}
+
+		if err := s.writeFile(path, data); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	return migrated, nil
+}
+
+// MigrateSchema rewrites the global config and session files that were written
+// with an older schema version. With dryRun, files are only checked.
+// Returns the files that were (or would be) rewritten.
+func (s *Store) MigrateSchema(dryRun bool) ([]SchemaMigration, error) {
+	var migrations []SchemaMigration
+
+	globalPath := s.GetGlobalConfigPath()
+	// #nosec G304 -- path is constructed from config directory
+	data, err := os.ReadFile(globalPath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read global config: %w", err)
+	}
+	if err == nil {
+		migrated, from, err := migrateDocument(data, globalMigrations, GlobalSchemaVersion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to migrate %s: %w", globalPath, err)
+		}
+		if from != GlobalSchemaVersion {
+			if !dryRun {
+				if err := os.WriteFile(globalPath, migrated, 0o600); err != nil {
+					return migrations, fmt.Errorf("failed to write global config: %w", err)
+				}
+			}
+			migrations = append(migrations, SchemaMigration{Path: globalPath, From: from, To: GlobalSchemaVersion})
+		}
+	}
+
+	sessionsDir := filepath.Join(s.configDir, SessionsSubdir)
+	entries, err := os.ReadDir(sessionsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return migrations, fmt.Errorf("failed to read sessions directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".yaml" {
+			continue
+		}
+		path := filepath.Join(sessionsDir, entry.Name())
+
+		data, err := s.readFile(path)
+		if err != nil {
+			return migrations, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		migrated, from, err := migrateDocument(data, sessionMigrations, SessionSchemaVersion)
+		if err != nil {
+			return migrations, fmt.Errorf("failed to migrate %s: %w", path, err)
+		}
+		if from == SessionSchemaVersion {
+			continue
+		}
+		if !dryRun {
+			if err := s.writeFile(path, migrated); err != nil {
+				return migrations, fmt.Errorf("failed to write %s: %w", path, err)
+			}
+		}
+		migrations = append(migrations, SchemaMigration{Path: path, From: from, To: SessionSchemaVersion})
+	}
+
+	return migrations, nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -127,6 +128,64 @@ func TestStore_DeleteSession(t *testing.T) {
 	assert.ErrorIs(t, err, ErrSessionNotFound)
 }
 
+func TestStore_CurrentSession(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStoreWithPath(tmpDir)
+
+	// Unset until chosen
+	current, err := store.CurrentSession()
+	require.NoError(t, err)
+	assert.Empty(t, current)
+
+	require.NoError(t, store.SaveSession(&SessionConfig{Name: "my-work", Namespace: "default"}))
+	require.NoError(t, store.SetCurrentSession("my-work"))
+	current, err = store.CurrentSession()
+	require.NoError(t, err)
+	assert.Equal(t, "my-work", current)
+
+	// Deleting the current session clears it
+	require.NoError(t, store.DeleteSession("my-work"))
+	current, err = store.CurrentSession()
+	require.NoError(t, err)
+	assert.Empty(t, current)
+
+	// Clearing twice is fine
+	require.NoError(t, store.SetCurrentSession(""))
+	require.NoError(t, store.SetCurrentSession(""))
+}
+
+func TestStore_SyncManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStoreWithPath(tmpDir)
+
+	// Empty until the first sync
+	manifest, err := store.LoadSyncManifest("my-work")
+	require.NoError(t, err)
+	assert.Empty(t, manifest)
+
+	want := SyncManifest{
+		"main.go":            {Hash: "aaa", Size: 12, ModTime: time.Unix(0, 1700000000123456789)},
+		"dir/with space.txt": {Hash: "bbb", Size: 0, ModTime: time.Unix(1700000000, 0)},
+	}
+	require.NoError(t, store.SaveSyncManifest("my-work", want))
+
+	manifest, err = store.LoadSyncManifest("my-work")
+	require.NoError(t, err)
+	require.Len(t, manifest, 2)
+	for path, file := range want {
+		assert.Equal(t, file.Hash, manifest[path].Hash)
+		assert.Equal(t, file.Size, manifest[path].Size)
+		assert.True(t, file.ModTime.Equal(manifest[path].ModTime), path)
+	}
+}
+
+func TestParseSyncManifest_Legacy(t *testing.T) {
+	manifest, err := ParseSyncManifest([]byte("aaa  main.go\nbbb  dir/with space.txt\n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"main.go": "aaa", "dir/with space.txt": "bbb"}, manifest.Hashes())
+	assert.True(t, manifest["main.go"].ModTime.IsZero())
+}
+
 func TestStore_DeleteSession_NotFound(t *testing.T) {
 	tmpDir := t.TempDir()
 	store := NewStoreWithPath(tmpDir)
@@ -208,12 +267,39 @@ func TestStore_GetSessionPath(t *testing.T) {
 	tmpDir := t.TempDir()
 	store := NewStoreWithPath(tmpDir)
 
-	path := store.GetSessionPath("my-session")
+	path := sessionPath(t, store, "my-session")
 	expected := filepath.Join(tmpDir, SessionsSubdir, "my-session.yaml")
 
 	assert.Equal(t, expected, path)
 }
 
+func TestStore_RejectsInvalidSessionNames(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStoreWithPath(tmpDir)
+	require.NoError(t, store.EnsureConfigDir())
+	require.NoError(t, os.WriteFile(store.GetGlobalConfigPath(), []byte("defaults:\n  namespace: dev\n"), 0o600))
+
+	// Names must not reach files outside the sessions directory
+	for _, name := range []string{"../config", "..", "a/b", ""} {
+		_, err := store.GetSessionPath(name)
+		assert.Error(t, err, name)
+		assert.False(t, store.SessionExists(name), name)
+		_, err = store.LoadSession(name)
+		assert.Error(t, err, name)
+		assert.NotErrorIs(t, err, ErrSessionNotFound, name)
+		assert.Error(t, store.DeleteSession(name), name)
+	}
+	assert.FileExists(t, store.GetGlobalConfigPath())
+}
+
+// sessionPath returns the config file path of a valid session name
+func sessionPath(t *testing.T, store *Store, name string) string {
+	t.Helper()
+	path, err := store.GetSessionPath(name)
+	require.NoError(t, err)
+	return path
+}
+
 func TestStore_GetGlobalConfigPath(t *testing.T) {
 	tmpDir := t.TempDir()
 	store := NewStoreWithPath(tmpDir)
@@ -351,7 +437,7 @@ sync:
 			store := NewStoreWithPath(tmpDir)
 
 			path := tt.setupFile(tmpDir)
-			config, err := store.LoadSessionTemplate(path)
+			config, err := store.LoadSessionTemplate(path, nil)
 
 			if tt.expectError {
 				assert.Error(t, err)
@@ -366,3 +452,129 @@ sync:
 		})
 	}
 }
+
+func TestStore_LoadSessionTemplates(t *testing.T) {
+	tmpDir := t.TempDir()
+	store := NewStoreWithPath(filepath.Join(tmpDir, ".kodama"))
+
+	writeTemplate := func(path, content string) string {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	writeTemplate(store.GetProfilePath("gpu"), "resources:\n  memory: 16Gi\n")
+	writeTemplate(filepath.Join(tmpDir, "repo", "base.kodama.yaml"), "extends: gpu\nimage: base:1\n")
+	service := writeTemplate(filepath.Join(tmpDir, "repo", "services", "api", ".kodama.yaml"),
+		"extends: ../../base.kodama.yaml\nimage: api:2\n")
+
+	chain, err := store.LoadSessionTemplates(service, nil)
+	require.NoError(t, err)
+	require.Len(t, chain, 3)
+	assert.Equal(t, "16Gi", chain[0].Resources.Memory)
+	assert.Equal(t, "base:1", chain[1].Image)
+	assert.Equal(t, "api:2", chain[2].Image)
+
+	t.Run("cycle", func(t *testing.T) {
+		a := writeTemplate(filepath.Join(tmpDir, "cycle", "a.yaml"), "extends: b.yaml\n")
+		writeTemplate(filepath.Join(tmpDir, "cycle", "b.yaml"), "extends: ./a.yaml\n")
+
+		_, err := store.LoadSessionTemplates(a, nil)
+		assert.ErrorContains(t, err, "extends itself")
+	})
+
+	t.Run("missing profile", func(t *testing.T) {
+		path := writeTemplate(filepath.Join(tmpDir, "missing.yaml"), "extends: nope\n")
+
+		_, err := store.LoadSessionTemplates(path, nil)
+		assert.ErrorContains(t, err, filepath.Join("profiles", "nope.yaml"))
+	})
+}
+
+// testStoreKey keeps encryption tests away from the OS keychain
+const testStoreKey = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func TestStore_EncryptedSessionRoundTrip(t *testing.T) {
+	t.Setenv("KODAMA_STORE_KEY", testStoreKey)
+	tmpDir := t.TempDir()
+	store := NewStoreWithPath(tmpDir)
+	require.NoError(t, store.EnableEncryption())
+
+	session := &SessionConfig{Name: "secret-session", Namespace: "default", Repo: "repo"}
+	require.NoError(t, store.SaveSession(session))
+
+	raw, err := os.ReadFile(sessionPath(t, store, "secret-session"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(raw), "secret-session")
+
+	// A fresh store without encryption enabled can still read encrypted files
+	reader := NewStoreWithPath(tmpDir)
+	loaded, err := reader.LoadSession("secret-session")
+	require.NoError(t, err)
+	assert.Equal(t, "repo", loaded.Repo)
+}
+
+func TestStore_ConcurrentEncryptedReads(t *testing.T) {
+	t.Setenv("KODAMA_STORE_KEY", testStoreKey)
+	tmpDir := t.TempDir()
+	writer := NewStoreWithPath(tmpDir)
+	require.NoError(t, writer.EnableEncryption())
+	require.NoError(t, writer.SaveSession(&SessionConfig{Name: "shared", Namespace: "default"}))
+
+	// 'serve' reads sessions from many requests at once; the key is loaded once
+	reader := NewStoreWithPath(tmpDir)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := reader.LoadSession("shared")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStore_MigrateToEncrypted(t *testing.T) {
+	t.Setenv("KODAMA_STORE_KEY", testStoreKey)
+	tmpDir := t.TempDir()
+	store := NewStoreWithPath(tmpDir)
+
+	require.NoError(t, store.SaveSession(&SessionConfig{Name: "s1", Namespace: "default"}))
+	require.NoError(t, store.SaveSession(&SessionConfig{Name: "s2", Namespace: "default"}))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, AuthFile), []byte(`{"profiles":{}}`), 0o600))
+
+	migrated, err := store.MigrateToEncrypted()
+	require.NoError(t, err)
+	assert.Equal(t, 3, migrated)
+	assert.True(t, store.EncryptionEnabled())
+
+	// Running again is a no-op
+	migrated, err = store.MigrateToEncrypted()
+	require.NoError(t, err)
+	assert.Equal(t, 0, migrated)
+
+	sessions, err := store.ListSessions()
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+}
+
+func TestStore_PruneOrphanedSessionDirs(t *testing.T) {
+	store := NewStoreWithPath(t.TempDir())
+	require.NoError(t, store.SaveSession(&SessionConfig{Name: "live", Namespace: "dev", Status: StatusRunning}))
+	require.NoError(t, store.SaveSyncManifest("live", SyncManifest{}))
+	require.NoError(t, store.SaveSyncManifest("gone", SyncManifest{}))
+	require.NoError(t, os.MkdirAll(store.GetSSHDir("gone"), 0o700))
+
+	want := []string{store.GetSessionDir("gone"), store.GetSSHDir("gone")}
+	orphaned, err := store.PruneOrphanedSessionDirs(true)
+	require.NoError(t, err)
+	assert.Equal(t, want, orphaned)
+	assert.DirExists(t, store.GetSessionDir("gone"))
+
+	orphaned, err = store.PruneOrphanedSessionDirs(false)
+	require.NoError(t, err)
+	assert.Equal(t, want, orphaned)
+	assert.NoDirExists(t, store.GetSessionDir("gone"))
+	assert.NoDirExists(t, store.GetSSHDir("gone"))
+	assert.DirExists(t, store.GetSessionDir("live"))
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SyncedFile is the size, modification time and content hash of a synced file
+type SyncedFile struct {
+	Hash    string
+	Size    int64
+	ModTime time.Time
+}
+
+// SyncManifest maps slash-separated paths relative to the sync root to their state
+type SyncManifest map[string]SyncedFile
+
+// Hashes returns the path → hash pairs of the manifest
+func (m SyncManifest) Hashes() map[string]string {
+	hashes := make(map[string]string, len(m))
+	for path, file := range m {
+		hashes[path] = file.Hash
+	}
+	return hashes
+}
+
+// EncodeSyncManifest formats m as one "<hash> <size> <mtime> <path>" line per
+// file, sorted by path, with the modification time in Unix nanoseconds
+func EncodeSyncManifest(m SyncManifest) []byte {
+	paths := make([]string, 0, len(m))
+	for path := range m {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b bytes.Buffer
+	for _, path := range paths {
+		file := m[path]
+		fmt.Fprintf(&b, "%s %d %d %s\n", file.Hash, file.Size, file.ModTime.UnixNano(), path)
+	}
+	return b.Bytes()
+}
+
+// ParseSyncManifest parses the output of EncodeSyncManifest
+// Manifests written by older versions hold "<hash>  <path>" lines without size
+// and time; their files are hashed again on the next scan.
+func ParseSyncManifest(data []byte) (SyncManifest, error) {
+	manifest := SyncManifest{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		hash, rest, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("unexpected sync manifest line: %q", line)
+		}
+		if path, legacy := strings.CutPrefix(rest, " "); legacy {
+			manifest[path] = SyncedFile{Hash: hash}
+			continue
+		}
+
+		fields := strings.SplitN(rest, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected sync manifest line: %q", line)
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected sync manifest line: %q", line)
+		}
+		nanos, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected sync manifest line: %q", line)
+		}
+		manifest[fields[2]] = SyncedFile{Hash: hash, Size: size, ModTime: time.Unix(0, nanos)}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to parse sync manifest: %w", err)
+	}
+	return manifest, nil
+}
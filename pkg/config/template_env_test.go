@@ -37,7 +37,7 @@ env:
 
 	// Load template
 	store := NewStoreWithPath(tmpDir)
-	template, err := store.LoadSessionTemplate(templatePath)
+	template, err := store.LoadSessionTemplate(templatePath, nil)
 	if err != nil {
 		t.Fatalf("failed to load session template: %v", err)
 	}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// toolPattern matches a mise tool spec, e.g. go@1.23, node@22, rust@stable or npm:prettier@3
+var toolPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._:/-]*@[A-Za-z0-9][A-Za-z0-9._+-]*$`)
+
+// ToolName returns the tool of a spec such as node@22, without its version
+func ToolName(tool string) string {
+	name, _, _ := strings.Cut(tool, "@")
+	return name
+}
+
+// ValidateTools checks that each tool is a name@version spec and is listed once
+func ValidateTools(tools []string) error {
+	seen := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		if !toolPattern.MatchString(tool) {
+			return fmt.Errorf("invalid tool %q: use name@version, e.g. go@1.23 or node@22", tool)
+		}
+		name := ToolName(tool)
+		if seen[name] {
+			return fmt.Errorf("tool %s is listed more than once", name)
+		}
+		seen[name] = true
+	}
+	return nil
+}
+
+// MergeTools returns base with the tools of other added; a tool in other
+// replaces the version of the same tool in base
+func MergeTools(base, other []string) []string {
+	if len(other) == 0 {
+		return base
+	}
+	merged := make([]string, 0, len(base)+len(other))
+	for _, tool := range base {
+		replaced := slices.ContainsFunc(other, func(o string) bool { return ToolName(o) == ToolName(tool) })
+		if !replaced {
+			merged = append(merged, tool)
+		}
+	}
+	return append(merged, other...)
+}
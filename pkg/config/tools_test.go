@@ -0,0 +1,24 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTools(t *testing.T) {
+	assert.NoError(t, ValidateTools(nil))
+	assert.NoError(t, ValidateTools([]string{"go@1.23", "node@22", "rust@stable", "python@3.12.4", "npm:prettier@3"}))
+
+	for _, tool := range []string{"go", "go@", "@1.23", "Go@1.23", "go@1.23; rm -rf /", "node@$(id)"} {
+		assert.ErrorContains(t, ValidateTools([]string{tool}), "invalid tool", tool)
+	}
+	assert.ErrorContains(t, ValidateTools([]string{"go@1.22", "go@1.23"}), "listed more than once")
+}
+
+func TestMergeTools(t *testing.T) {
+	base := []string{"go@1.22", "node@22"}
+	assert.Equal(t, base, MergeTools(base, nil))
+	assert.Equal(t, []string{"node@22", "go@1.23", "rust@stable"}, MergeTools(base, []string{"go@1.23", "rust@stable"}))
+	assert.Equal(t, []string{"go@1.22", "node@22"}, base, "base is not modified")
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the data available to Go-template expressions in session templates
+type TemplateData struct {
+	Values   map[string]interface{} // Global config values overlaid with --set
+	Env      map[string]string      // Environment variables
+	Defaults DefaultsConfig         // Global config defaults (e.g. .Defaults.Namespace)
+}
+
+// templateFuncs are the helper functions available in session templates
+var templateFuncs = template.FuncMap{
+	// default returns def when value is empty: {{ .Values.tag | default "latest" }}
+	"default": func(def, value interface{}) interface{} {
+		if isEmptyValue(value) {
+			return def
+		}
+		return value
+	},
+	// required fails rendering when value is empty: {{ required "tag is required" .Values.tag }}
+	"required": func(msg string, value interface{}) (interface{}, error) {
+		if isEmptyValue(value) {
+			return nil, fmt.Errorf("%s", msg)
+		}
+		return value, nil
+	},
+	"quote": func(value interface{}) string {
+		return strconv.Quote(fmt.Sprint(value))
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+}
+
+// isEmptyValue reports whether a template value is missing or empty
+func isEmptyValue(value interface{}) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	case bool:
+		return !v
+	case map[string]interface{}:
+		return len(v) == 0
+	case []interface{}:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// RenderSessionTemplate renders Go-template expressions in a session template.
+// Data without template actions is returned unchanged. Missing values render empty.
+func RenderSessionTemplate(name string, data []byte, values TemplateData) ([]byte, error) {
+	if !bytes.Contains(data, []byte("{{")) {
+		return data, nil
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=zero").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template expressions: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, values); err != nil {
+		return nil, fmt.Errorf("failed to render template expressions: %w", err)
+	}
+
+	// missingkey=zero renders missing map entries as "<no value>"
+	return bytes.ReplaceAll(buf.Bytes(), []byte("<no value>"), nil), nil
+}
+
+// EnvironMap returns the process environment as a map
+func EnvironMap() map[string]string {
+	result := make(map[string]string)
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// ParseSetValues parses key=value pairs, as given to --set.
+// Dotted keys create nested values: image.tag=v2 becomes {image: {tag: v2}}.
+func ParseSetValues(pairs []string) (map[string]interface{}, error) {
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	values := make(map[string]interface{})
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set format: %s (expected format: key=value, e.g., image.tag=v2)", pair)
+		}
+
+		parts := strings.Split(key, ".")
+		current := values
+		for i, part := range parts {
+			if part == "" {
+				return nil, fmt.Errorf("invalid --set key: %s", key)
+			}
+			if i == len(parts)-1 {
+				current[part] = value
+				break
+			}
+			next, ok := current[part].(map[string]interface{})
+			if !ok {
+				next = make(map[string]interface{})
+				current[part] = next
+			}
+			current = next
+		}
+	}
+	return values, nil
+}
+
+// MergeValues deep-merges override into base, with override taking precedence.
+// Neither input is modified.
+func MergeValues(base, override map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base)+len(override))
+	for key, value := range base {
+		result[key] = value
+	}
+	for key, value := range override {
+		baseMap, baseIsMap := result[key].(map[string]interface{})
+		overrideMap, overrideIsMap := value.(map[string]interface{})
+		if baseIsMap && overrideIsMap {
+			result[key] = MergeValues(baseMap, overrideMap)
+			continue
+		}
+		result[key] = value
+	}
+	return result
+}
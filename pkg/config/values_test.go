@@ -0,0 +1,101 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSessionTemplate(t *testing.T) {
+	data := TemplateData{
+		Values:   map[string]interface{}{"image": map[string]interface{}{"tag": "v2"}, "stage": "dev"},
+		Env:      map[string]string{"TEAM": "payments"},
+		Defaults: DefaultsConfig{Namespace: "kodama"},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{name: "no expressions", template: "image: app:latest\n", want: "image: app:latest\n"},
+		{name: "nested value", template: "image: app:{{ .Values.image.tag }}\n", want: "image: app:v2\n"},
+		{name: "env", template: "namespace: {{ .Env.TEAM }}\n", want: "namespace: payments\n"},
+		{name: "defaults", template: "namespace: {{ .Defaults.Namespace }}-{{ .Values.stage }}\n", want: "namespace: kodama-dev\n"},
+		{name: "missing value renders empty", template: "branch: {{ .Values.branch }}\n", want: "branch: \n"},
+		{name: "default func", template: "branch: {{ .Values.branch | default \"main\" }}\n", want: "branch: main\n"},
+		{name: "upper and quote", template: "x: {{ .Values.stage | upper | quote }}\n", want: "x: \"DEV\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderSessionTemplate("test", []byte(tt.template), data)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestRenderSessionTemplate_Errors(t *testing.T) {
+	_, err := RenderSessionTemplate("test", []byte("image: {{ required \"image.tag is required\" .Values.tag }}"), TemplateData{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "image.tag is required")
+
+	_, err = RenderSessionTemplate("test", []byte("image: {{ .Values.tag"), TemplateData{})
+	assert.Error(t, err)
+}
+
+func TestParseSetValues(t *testing.T) {
+	values, err := ParseSetValues([]string{"stage=dev", "image.tag=v2", "image.repo=app", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"stage": "dev",
+		"image": map[string]interface{}{"tag": "v2", "repo": "app"},
+		"empty": "",
+	}, values)
+
+	for _, invalid := range []string{"stage", "=dev", "image..tag=v2"} {
+		_, err := ParseSetValues([]string{invalid})
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	base := map[string]interface{}{"image": map[string]interface{}{"tag": "v1", "repo": "app"}, "stage": "dev"}
+	override := map[string]interface{}{"image": map[string]interface{}{"tag": "v2"}}
+
+	merged := MergeValues(base, override)
+
+	assert.Equal(t, map[string]interface{}{
+		"image": map[string]interface{}{"tag": "v2", "repo": "app"},
+		"stage": "dev",
+	}, merged)
+	assert.Equal(t, "v1", base["image"].(map[string]interface{})["tag"], "base is not modified")
+}
+
+func TestLoadSessionTemplate_WithValues(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStoreWithPath(dir)
+	require.NoError(t, store.SaveGlobalConfig(&GlobalConfig{
+		Defaults: DefaultsConfig{Namespace: "team"},
+		Values:   map[string]interface{}{"stage": "dev", "tag": "latest"},
+	}))
+
+	templatePath := filepath.Join(dir, "template.yaml")
+	require.NoError(t, os.WriteFile(templatePath, []byte(""+
+		"namespace: {{ .Defaults.Namespace }}-{{ .Values.stage }}\n"+
+		"image: ghcr.io/org/app:{{ .Values.tag }}\n"), 0o600))
+
+	template, err := store.LoadSessionTemplate(templatePath, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "team-dev", template.Namespace)
+	assert.Equal(t, "ghcr.io/org/app:latest", template.Image)
+
+	template, err = store.LoadSessionTemplate(templatePath, map[string]interface{}{"stage": "stage", "tag": "v2"})
+	require.NoError(t, err)
+	assert.Equal(t, "team-stage", template.Namespace)
+	assert.Equal(t, "ghcr.io/org/app:v2", template.Image)
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// volumeNamePattern matches a DNS-1123 label, as Kubernetes requires for volume names
+var volumeNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// maxVolumeNameLength leaves room for the prefix the pod builder adds
+const maxVolumeNameLength = 55
+
+// reservedMountPaths are mounted by kodama itself and cannot be replaced
+var reservedMountPaths = []string{"/", "/workspace", "/kodama/bin", "/kodama/tools", "/kodama/canonical", "/home/claude", "/cache", "/shared"}
+
+// VolumeConfig mounts an existing PVC, ConfigMap, Secret or host path into
+// the main container of the session pod. Exactly one source is set.
+type VolumeConfig struct {
+	Name      string `yaml:"name"`               // Unique within the session, e.g. datasets
+	MountPath string `yaml:"mountPath"`          // Absolute path in the container, e.g. /workspace/data
+	SubPath   string `yaml:"subPath,omitempty"`  // Path inside the volume to mount instead of its root
+	ReadOnly  bool   `yaml:"readOnly,omitempty"` // Mount read-only
+
+	PVC       string `yaml:"pvc,omitempty"`       // PersistentVolumeClaim in the session namespace
+	ConfigMap string `yaml:"configMap,omitempty"` // ConfigMap in the session namespace
+	Secret    string `yaml:"secret,omitempty"`    // Secret in the session namespace
+	HostPath  string `yaml:"hostPath,omitempty"`  // Directory on the node
+}
+
+// Validate checks the name, the mount path and that exactly one source is set
+func (v VolumeConfig) Validate() error {
+	if len(v.Name) > maxVolumeNameLength || !volumeNamePattern.MatchString(v.Name) {
+		return fmt.Errorf("invalid volume name %q: use lowercase letters, digits and '-' (at most %d characters)", v.Name, maxVolumeNameLength)
+	}
+	if !path.IsAbs(v.MountPath) {
+		return fmt.Errorf("volume %s: mountPath must be absolute, got %q", v.Name, v.MountPath)
+	}
+	for _, reserved := range reservedMountPaths {
+		if path.Clean(v.MountPath) == reserved {
+			return fmt.Errorf("volume %s: %s is mounted by kodama, mount below it instead", v.Name, reserved)
+		}
+	}
+	if v.SubPath != "" && (path.IsAbs(v.SubPath) || strings.HasPrefix(path.Clean(v.SubPath), "..")) {
+		return fmt.Errorf("volume %s: subPath must be relative to the volume, got %q", v.Name, v.SubPath)
+	}
+
+	sources := 0
+	for _, source := range []string{v.PVC, v.ConfigMap, v.Secret, v.HostPath} {
+		if source != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("volume %s: set exactly one of pvc, configMap, secret and hostPath", v.Name)
+	}
+	if v.HostPath != "" && !path.IsAbs(v.HostPath) {
+		return fmt.Errorf("volume %s: hostPath must be absolute, got %q", v.Name, v.HostPath)
+	}
+	return nil
+}
+
+// ValidateVolumes validates each volume and that names and mount paths are unique
+func ValidateVolumes(volumes []VolumeConfig) error {
+	names := make(map[string]bool, len(volumes))
+	mountPaths := make(map[string]string, len(volumes))
+	for _, v := range volumes {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+		if names[v.Name] {
+			return fmt.Errorf("duplicate volume name %q", v.Name)
+		}
+		names[v.Name] = true
+		mountPath := path.Clean(v.MountPath)
+		if other, ok := mountPaths[mountPath]; ok {
+			return fmt.Errorf("volumes %s and %s are both mounted at %s", other, v.Name, mountPath)
+		}
+		mountPaths[mountPath] = v.Name
+	}
+	return nil
+}
+
+// MergeVolumes returns base with the volumes of other added; a volume in
+// other replaces the base volume with the same name
+func MergeVolumes(base, other []VolumeConfig) []VolumeConfig {
+	if len(other) == 0 {
+		return base
+	}
+	merged := make([]VolumeConfig, 0, len(base)+len(other))
+	for _, v := range base {
+		replaced := slices.ContainsFunc(other, func(o VolumeConfig) bool { return o.Name == v.Name })
+		if !replaced {
+			merged = append(merged, v)
+		}
+	}
+	return append(merged, other...)
+}
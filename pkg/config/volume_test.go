@@ -0,0 +1,55 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateVolumes(t *testing.T) {
+	valid := []VolumeConfig{
+		{Name: "datasets", MountPath: "/workspace/data", PVC: "imagenet", ReadOnly: true},
+		{Name: "settings", MountPath: "/etc/app", ConfigMap: "app-settings"},
+	}
+	assert.NoError(t, ValidateVolumes(valid))
+
+	tests := []struct {
+		name   string
+		volume VolumeConfig
+		errMsg string
+	}{
+		{"invalid name", VolumeConfig{Name: "Data_Sets", MountPath: "/data", PVC: "x"}, "invalid volume name"},
+		{"relative mount path", VolumeConfig{Name: "data", MountPath: "data", PVC: "x"}, "mountPath must be absolute"},
+		{"reserved mount path", VolumeConfig{Name: "data", MountPath: "/workspace/", PVC: "x"}, "/workspace is mounted by kodama"},
+		{"no source", VolumeConfig{Name: "data", MountPath: "/data"}, "set exactly one of"},
+		{"two sources", VolumeConfig{Name: "data", MountPath: "/data", PVC: "x", Secret: "y"}, "set exactly one of"},
+		{"escaping subPath", VolumeConfig{Name: "data", MountPath: "/data", PVC: "x", SubPath: "../etc"}, "subPath must be relative"},
+		{"relative hostPath", VolumeConfig{Name: "data", MountPath: "/data", HostPath: "mnt"}, "hostPath must be absolute"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.volume.Validate(), tt.errMsg)
+		})
+	}
+
+	assert.ErrorContains(t, ValidateVolumes(append(valid, VolumeConfig{Name: "datasets", MountPath: "/other", PVC: "x"})), "duplicate volume name")
+	assert.ErrorContains(t, ValidateVolumes(append(valid, VolumeConfig{Name: "other", MountPath: "/etc/app/", PVC: "x"})), "both mounted at /etc/app")
+}
+
+func TestMergeVolumes(t *testing.T) {
+	base := []VolumeConfig{
+		{Name: "datasets", MountPath: "/data", PVC: "small"},
+		{Name: "models", MountPath: "/models", PVC: "models"},
+	}
+	merged := MergeVolumes(base, []VolumeConfig{
+		{Name: "datasets", MountPath: "/data", PVC: "large"},
+		{Name: "scratch", MountPath: "/scratch", HostPath: "/mnt/scratch"},
+	})
+
+	assert.Equal(t, []VolumeConfig{
+		{Name: "models", MountPath: "/models", PVC: "models"},
+		{Name: "datasets", MountPath: "/data", PVC: "large"},
+		{Name: "scratch", MountPath: "/scratch", HostPath: "/mnt/scratch"},
+	}, merged)
+	assert.Equal(t, base, MergeVolumes(base, nil))
+}
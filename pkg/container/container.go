@@ -0,0 +1,141 @@
+// Package container runs sessions as containers on the local docker or podman
+// engine, for offline work without a cluster
+package container
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// WorkspacePath is where the workspace is mounted in the container, as in pods
+const WorkspacePath = "/workspace"
+
+// Engine is the CLI of a local container engine, "docker" or "podman"
+type Engine string
+
+// Spec describes a session container
+type Spec struct {
+	Name      string            // Container name, the session's pod name
+	Image     string            // Container image
+	Workspace string            // Host directory bind-mounted at /workspace; empty uses a volume removed with the container
+	EnvFile   string            // File of KEY=value lines with the session's environment
+	Labels    map[string]string // Container labels
+	CPU       string            // CPU limit as a Kubernetes quantity, e.g. "2" or "500m"
+	Memory    string            // Memory limit as a Kubernetes quantity, e.g. "4Gi"
+	Command   []string          // Main process (default: sleep forever, so execs keep working)
+}
+
+// RunArgs returns the engine arguments creating and starting the container for spec
+func RunArgs(spec Spec) ([]string, error) {
+	args := []string{"run", "-d", "--name", spec.Name, "--hostname", spec.Name, "-w", WorkspacePath}
+
+	if spec.Workspace != "" {
+		args = append(args, "-v", spec.Workspace+":"+WorkspacePath)
+	} else {
+		args = append(args, "--mount", "type=volume,dst="+WorkspacePath)
+	}
+	if spec.EnvFile != "" {
+		args = append(args, "--env-file", spec.EnvFile)
+	}
+	for _, key := range slices.Sorted(maps.Keys(spec.Labels)) {
+		args = append(args, "--label", key+"="+spec.Labels[key])
+	}
+
+	if spec.CPU != "" {
+		cpu, err := resource.ParseQuantity(spec.CPU)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu %q: %w", spec.CPU, err)
+		}
+		args = append(args, "--cpus", strconv.FormatFloat(cpu.AsApproximateFloat64(), 'f', -1, 64))
+	}
+	if spec.Memory != "" {
+		memory, err := resource.ParseQuantity(spec.Memory)
+		if err != nil {
+			return nil, fmt.Errorf("invalid memory %q: %w", spec.Memory, err)
+		}
+		args = append(args, "--memory", strconv.FormatInt(memory.Value(), 10))
+	}
+
+	args = append(args, spec.Image)
+	if len(spec.Command) > 0 {
+		return append(args, spec.Command...), nil
+	}
+	return append(args, "sleep", "infinity"), nil
+}
+
+// Run creates and starts the container for spec
+func (e Engine) Run(ctx context.Context, spec Spec) error {
+	args, err := RunArgs(spec)
+	if err != nil {
+		return err
+	}
+	_, err = e.run(ctx, args...)
+	return err
+}
+
+// Remove deletes the container and its workspace volume; a missing container is not an error
+func (e Engine) Remove(ctx context.Context, name string) error {
+	if _, err := e.run(ctx, "rm", "-f", "-v", name); err != nil && !strings.Contains(err.Error(), "no such container") {
+		return err
+	}
+	return nil
+}
+
+// Running reports whether the container exists and is running
+func (e Engine) Running(ctx context.Context, name string) (bool, error) {
+	out, err := e.run(ctx, "inspect", "-f", "{{.State.Running}}", name)
+	if err != nil {
+		if strings.Contains(err.Error(), "no such") {
+			return false, nil
+		}
+		return false, err
+	}
+	return strings.TrimSpace(out) == "true", nil
+}
+
+// ExecArgs returns the engine arguments running command in the container's
+// workspace, with a terminal when interactive
+func ExecArgs(name string, interactive bool, command []string) []string {
+	args := []string{"exec", "-w", WorkspacePath}
+	if interactive {
+		args = append(args, "-it")
+	}
+	return append(append(args, name), command...)
+}
+
+// LogsArgs returns the engine arguments printing the container's logs, the
+// last tail lines only when tail > 0
+func LogsArgs(name string, follow bool, tail int64) []string {
+	args := []string{"logs"}
+	if follow {
+		args = append(args, "--follow")
+	}
+	if tail > 0 {
+		args = append(args, "--tail", strconv.FormatInt(tail, 10))
+	}
+	return append(args, name)
+}
+
+// run runs the engine CLI and returns its stdout; errors carry its stderr
+func (e Engine) run(ctx context.Context, args ...string) (string, error) {
+	//#nosec G204 -- docker or podman with kodama-controlled arguments
+	cmd := procutil.CommandContext(ctx, string(e), args...)
+	var stdout, stderr strings.Builder
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("%s %s failed: %w: %s", e, args[0], err, strings.ToLower(strings.TrimSpace(stderr.String())))
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,88 @@
+package container
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubEngine puts a docker that runs script first on PATH
+func stubEngine(t *testing.T, script string) Engine {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker"), []byte("#!/bin/sh\n"+script+"\n"), 0o700)) //#nosec G306 -- test executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return Engine("docker")
+}
+
+func TestRunArgs(t *testing.T) {
+	args, err := RunArgs(Spec{
+		Name:      "kodama-work",
+		Image:     "ubuntu:24.04",
+		Workspace: "/home/me/src",
+		EnvFile:   "/tmp/env",
+		Labels:    map[string]string{"session": "work", "app": "kodama"},
+		CPU:       "500m",
+		Memory:    "1Gi",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"run", "-d", "--name", "kodama-work", "--hostname", "kodama-work", "-w", "/workspace",
+		"-v", "/home/me/src:/workspace", "--env-file", "/tmp/env",
+		"--label", "app=kodama", "--label", "session=work",
+		"--cpus", "0.5", "--memory", "1073741824",
+		"ubuntu:24.04", "sleep", "infinity",
+	}, args)
+
+	args, err = RunArgs(Spec{Name: "kodama-work", Image: "ubuntu:24.04", Command: []string{"bash"}})
+	require.NoError(t, err)
+	assert.Contains(t, strings.Join(args, " "), "--mount type=volume,dst=/workspace ubuntu:24.04 bash")
+
+	_, err = RunArgs(Spec{Name: "kodama-work", Image: "ubuntu:24.04", Memory: "lots"})
+	assert.Error(t, err)
+}
+
+func TestEngine_Running(t *testing.T) {
+	engine := stubEngine(t, `case "$4" in
+  kodama-up) echo true ;;
+  kodama-down) echo false ;;
+  *) echo "Error: No such object: $4" >&2; exit 1 ;;
+esac`)
+
+	for name, want := range map[string]bool{"kodama-up": true, "kodama-down": false, "kodama-gone": false} {
+		running, err := engine.Running(context.Background(), name)
+		require.NoError(t, err)
+		assert.Equal(t, want, running, name)
+	}
+}
+
+func TestEngine_Remove(t *testing.T) {
+	engine := stubEngine(t, `echo "Error response from daemon: No such container: $4" >&2; exit 1`)
+	assert.NoError(t, engine.Remove(context.Background(), "kodama-gone"))
+
+	engine = stubEngine(t, `echo "permission denied" >&2; exit 1`)
+	err := engine.Remove(context.Background(), "kodama-work")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestExecArgs(t *testing.T) {
+	assert.Equal(t, []string{"exec", "-w", "/workspace", "-it", "kodama-work", "bash"},
+		ExecArgs("kodama-work", true, []string{"bash"}))
+	assert.Equal(t, []string{"exec", "-w", "/workspace", "kodama-work", "ls"},
+		ExecArgs("kodama-work", false, []string{"ls"}))
+}
+
+func TestLogsArgs(t *testing.T) {
+	assert.Equal(t, []string{"logs", "kodama-work"}, LogsArgs("kodama-work", false, 0))
+	assert.Equal(t, []string{"logs", "--follow", "--tail", "50", "kodama-work"}, LogsArgs("kodama-work", true, 50))
+}
@@ -0,0 +1,136 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	// KeySize is the size of the AES-256 key in bytes
+	KeySize = 32
+
+	// KeyEnvVar allows supplying the hex-encoded key via environment variable
+	// (useful on CI machines without a persistent ~/.kodama directory)
+	KeyEnvVar = "KODAMA_STORE_KEY"
+)
+
+// header marks a file as encrypted by kodama
+// Files without this header are treated as plaintext
+var header = []byte("KODAMA-ENCRYPTED-V1\n")
+
+// ErrKeyNotFound is returned when no encryption key is available
+var ErrKeyNotFound = errors.New("encryption key not found")
+
+// Cipher encrypts and decrypts data at rest using AES-256-GCM
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// NewCipher creates a new Cipher from a raw 32-byte key
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid key size: got %d bytes, want %d", len(key), KeySize)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+
+	return &Cipher{aead: aead}, nil
+}
+
+// Encrypt encrypts plaintext and returns the armored ciphertext (header + base64)
+func (c *Cipher) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, header)
+
+	encoded := make([]byte, base64.StdEncoding.EncodedLen(len(sealed)))
+	base64.StdEncoding.Encode(encoded, sealed)
+
+	result := make([]byte, 0, len(header)+len(encoded)+1)
+	result = append(result, header...)
+	result = append(result, encoded...)
+	result = append(result, '\n')
+	return result, nil
+}
+
+// Decrypt decrypts armored ciphertext produced by Encrypt
+func (c *Cipher) Decrypt(data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return nil, fmt.Errorf("data is not encrypted")
+	}
+
+	encoded := bytes.TrimSpace(data[len(header):])
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(encoded)))
+	n, err := base64.StdEncoding.Decode(sealed, encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode encrypted data: %w", err)
+	}
+	sealed = sealed[:n]
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, fmt.Errorf("encrypted data is too short")
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt data (wrong key?): %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// IsEncrypted reports whether data was produced by Cipher.Encrypt
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, header)
+}
+
+// LoadKey loads the encryption key from KODAMA_STORE_KEY or a plaintext key file
+// Returns ErrKeyNotFound if neither is available. New keys are kept in a KeyStore.
+func LoadKey(path string) ([]byte, error) {
+	if envKey := os.Getenv(KeyEnvVar); envKey != "" {
+		return decodeKey(envKey)
+	}
+
+	data, err := os.ReadFile(path) // #nosec G304 -- key file path from kodama config
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to read key file: %w", err)
+	}
+
+	return decodeKey(string(data))
+}
+
+// decodeKey parses a hex-encoded key
+func decodeKey(s string) ([]byte, error) {
+	key, err := hex.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: must be hex-encoded: %w", err)
+	}
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("invalid encryption key: got %d bytes, want %d", len(key), KeySize)
+	}
+	return key, nil
+}
@@ -0,0 +1,71 @@
+package encryption
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCipher_EncryptDecrypt(t *testing.T) {
+	key := make([]byte, KeySize)
+	c, err := NewCipher(key)
+	require.NoError(t, err)
+
+	plaintext := []byte("name: my-session\nnamespace: default\n")
+	encrypted, err := c.Encrypt(plaintext)
+	require.NoError(t, err)
+
+	assert.True(t, IsEncrypted(encrypted))
+	assert.NotContains(t, string(encrypted), "my-session")
+
+	decrypted, err := c.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, plaintext, decrypted)
+}
+
+func TestCipher_DecryptWrongKey(t *testing.T) {
+	key1 := make([]byte, KeySize)
+	key2 := make([]byte, KeySize)
+	key2[0] = 1
+
+	c1, err := NewCipher(key1)
+	require.NoError(t, err)
+	c2, err := NewCipher(key2)
+	require.NoError(t, err)
+
+	encrypted, err := c1.Encrypt([]byte("secret"))
+	require.NoError(t, err)
+
+	_, err = c2.Decrypt(encrypted)
+	assert.Error(t, err)
+}
+
+func TestCipher_DecryptPlaintext(t *testing.T) {
+	c, err := NewCipher(make([]byte, KeySize))
+	require.NoError(t, err)
+
+	_, err = c.Decrypt([]byte("name: plain\n"))
+	assert.Error(t, err)
+}
+
+func TestNewCipher_InvalidKeySize(t *testing.T) {
+	_, err := NewCipher([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestLoadKey_FromEnv(t *testing.T) {
+	t.Setenv(KeyEnvVar, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+
+	key, err := LoadKey(filepath.Join(t.TempDir(), "missing.key"))
+	require.NoError(t, err)
+	assert.Equal(t, byte(0x1f), key[31])
+}
+
+func TestLoadKey_InvalidEnv(t *testing.T) {
+	t.Setenv(KeyEnvVar, "not-hex")
+
+	_, err := LoadKey(filepath.Join(t.TempDir(), "missing.key"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,247 @@
+package encryption
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// KeySource selects where the store key is kept
+type KeySource string
+
+const (
+	// KeySourceKeychain keeps the key in the OS keychain (macOS Keychain via
+	// 'security', the Secret Service via 'secret-tool' elsewhere)
+	KeySourceKeychain KeySource = "keychain"
+
+	// KeySourceAge keeps the key in a file encrypted to an age recipient
+	KeySourceAge KeySource = "age"
+
+	// keychainService is the keychain service name kodama stores its key under
+	keychainService = "kodama"
+
+	// commandTimeout bounds keychain and age commands, which may prompt for a password
+	commandTimeout = 2 * time.Minute
+)
+
+// ErrKeychainUnavailable is returned when no supported keychain tool is installed
+var ErrKeychainUnavailable = errors.New("no OS keychain available (install 'secret-tool' or set store.keySource: age)")
+
+// commandError describes a keychain or age command that exited with an error
+type commandError struct {
+	Name     string
+	ExitCode int
+	Stderr   string
+}
+
+func (e *commandError) Error() string {
+	return fmt.Sprintf("%s exited with status %d: %s", e.Name, e.ExitCode, e.Stderr)
+}
+
+// runCommand runs an external command with stdin and returns its stdout
+// Tests replace it to avoid touching the real keychain.
+var runCommand = func(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	if _, err := exec.LookPath(name); err != nil {
+		return nil, fmt.Errorf("%s not found in PATH: %w", name, err)
+	}
+
+	//#nosec G204 -- fixed keychain and age commands with kodama-controlled arguments
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return stdout.Bytes(), ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return stdout.Bytes(), &commandError{Name: name, ExitCode: exitErr.ExitCode(), Stderr: strings.TrimSpace(stderr.String())}
+		}
+		return stdout.Bytes(), fmt.Errorf("failed to run %s: %w", name, err)
+	}
+	return stdout.Bytes(), nil
+}
+
+// KeyStore loads and creates the store key outside the encrypted files
+// KODAMA_STORE_KEY always takes precedence. A plaintext key file written by older
+// versions is still read, and is moved into the key source when a key is created.
+type KeyStore struct {
+	Source       KeySource
+	Account      string // Keychain account, the store's config directory
+	AgeFile      string // age-encrypted key file
+	AgeRecipient string // age recipient the key file is encrypted to
+	AgeIdentity  string // age identity file used to decrypt the key file
+	LegacyPath   string // Plaintext key file written by older versions
+}
+
+// Load returns the store key
+// Returns ErrKeyNotFound if no key exists yet.
+func (k KeyStore) Load() ([]byte, error) {
+	if envKey := os.Getenv(KeyEnvVar); envKey != "" {
+		return decodeKey(envKey)
+	}
+
+	encoded, err := k.read()
+	if err == nil {
+		return decodeKey(encoded)
+	}
+	if !errors.Is(err, ErrKeyNotFound) {
+		return nil, err
+	}
+
+	if k.LegacyPath == "" {
+		return nil, ErrKeyNotFound
+	}
+	return LoadKey(k.LegacyPath)
+}
+
+// LoadOrCreate returns the store key, generating one in the key source if missing
+// A key found in the legacy plaintext file is moved into the key source.
+func (k KeyStore) LoadOrCreate() ([]byte, error) {
+	if envKey := os.Getenv(KeyEnvVar); envKey != "" {
+		return decodeKey(envKey)
+	}
+
+	encoded, err := k.read()
+	if err == nil {
+		return decodeKey(encoded)
+	}
+	if !errors.Is(err, ErrKeyNotFound) {
+		return nil, err
+	}
+
+	var key []byte
+	if k.LegacyPath != "" {
+		key, err = LoadKey(k.LegacyPath)
+		if err != nil && !errors.Is(err, ErrKeyNotFound) {
+			return nil, err
+		}
+	}
+	if key == nil {
+		key = make([]byte, KeySize)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+	}
+
+	if err := k.write(hex.EncodeToString(key)); err != nil {
+		return nil, err
+	}
+
+	if k.LegacyPath != "" {
+		if err := os.Remove(k.LegacyPath); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to remove plaintext key file: %w", err)
+		}
+	}
+
+	return key, nil
+}
+
+// read returns the hex-encoded key from the key source
+func (k KeyStore) read() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	switch k.Source {
+	case KeySourceAge:
+		if _, err := os.Stat(k.AgeFile); os.IsNotExist(err) {
+			return "", ErrKeyNotFound
+		}
+		if k.AgeIdentity == "" {
+			return "", errors.New("store.ageIdentity is required to decrypt the age key file")
+		}
+		out, err := runCommand(ctx, nil, "age", "--decrypt", "--identity", k.AgeIdentity, k.AgeFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt %s: %w", k.AgeFile, err)
+		}
+		return string(out), nil
+
+	case KeySourceKeychain, "":
+		var (
+			out []byte
+			err error
+		)
+		if runtime.GOOS == "darwin" {
+			out, err = runCommand(ctx, nil, "security", "find-generic-password", "-s", keychainService, "-a", k.Account, "-w")
+		} else {
+			out, err = runCommand(ctx, nil, "secret-tool", "lookup", "service", keychainService, "account", k.Account)
+		}
+		if err != nil {
+			return "", keychainError(err)
+		}
+		return string(out), nil
+
+	default:
+		return "", fmt.Errorf("unknown store.keySource %q (want %s or %s)", k.Source, KeySourceKeychain, KeySourceAge)
+	}
+}
+
+// keychainError maps a failed keychain lookup to ErrKeyNotFound when the entry
+// does not exist: 'security' exits with 44, 'secret-tool' with 1 and no message.
+// Any other failure, such as a locked keychain, is returned as is, so that a
+// new key never replaces one that could not be read.
+func keychainError(err error) error {
+	if errors.Is(err, exec.ErrNotFound) {
+		return ErrKeychainUnavailable
+	}
+
+	var cmdErr *commandError
+	if errors.As(err, &cmdErr) {
+		if (cmdErr.Name == "security" && cmdErr.ExitCode == 44) || (cmdErr.Name == "secret-tool" && cmdErr.ExitCode == 1 && cmdErr.Stderr == "") {
+			return ErrKeyNotFound
+		}
+	}
+	return fmt.Errorf("failed to read key from keychain: %w", err)
+}
+
+// write stores the hex-encoded key in the key source
+// The key is passed on stdin, never as a command-line argument.
+func (k KeyStore) write(encoded string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	switch k.Source {
+	case KeySourceAge:
+		if k.AgeRecipient == "" {
+			return errors.New("store.ageRecipient is required to create the age key file")
+		}
+		if _, err := runCommand(ctx, []byte(encoded), "age", "--encrypt", "--recipient", k.AgeRecipient, "--output", k.AgeFile); err != nil {
+			return fmt.Errorf("failed to write %s: %w", k.AgeFile, err)
+		}
+		return os.Chmod(k.AgeFile, 0o600)
+
+	case KeySourceKeychain, "":
+		var err error
+		if runtime.GOOS == "darwin" {
+			// 'security -i' reads the command from stdin, keeping the key out of ps
+			command := fmt.Sprintf("add-generic-password -s %s -a %q -w %s\n", keychainService, k.Account, encoded)
+			_, err = runCommand(ctx, []byte(command), "security", "-i")
+		} else {
+			_, err = runCommand(ctx, []byte(encoded), "secret-tool", "store", "--label", "kodama store key",
+				"service", keychainService, "account", k.Account)
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			return ErrKeychainUnavailable
+		}
+		if err != nil {
+			return fmt.Errorf("failed to store key in keychain: %w", err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown store.keySource %q (want %s or %s)", k.Source, KeySourceKeychain, KeySourceAge)
+	}
+}
@@ -0,0 +1,158 @@
+package encryption
+
+import (
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKeychain answers keychain commands from memory
+type fakeKeychain struct {
+	entries map[string]string
+	failErr error
+}
+
+func (f *fakeKeychain) run(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+	if f.failErr != nil {
+		return nil, f.failErr
+	}
+	account := args[len(args)-1]
+	switch {
+	case name == "secret-tool" && args[0] == "lookup":
+		value, ok := f.entries[account]
+		if !ok {
+			return nil, &commandError{Name: name, ExitCode: 1}
+		}
+		return []byte(value), nil
+	case name == "secret-tool" && args[0] == "store":
+		f.entries[account] = string(stdin)
+		return nil, nil
+	case name == "security" && args[0] == "find-generic-password":
+		value, ok := f.entries[args[4]]
+		if !ok {
+			return nil, &commandError{Name: name, ExitCode: 44}
+		}
+		return []byte(value + "\n"), nil
+	case name == "security" && args[0] == "-i":
+		fields := strings.Fields(string(stdin))
+		f.entries[strings.Trim(fields[4], `"`)] = fields[6]
+		return nil, nil
+	}
+	return nil, &commandError{Name: name, ExitCode: 2, Stderr: "unexpected command"}
+}
+
+func useFakeKeychain(t *testing.T) *fakeKeychain {
+	t.Helper()
+	t.Setenv(KeyEnvVar, "")
+	fake := &fakeKeychain{entries: map[string]string{}}
+	orig := runCommand
+	runCommand = fake.run
+	t.Cleanup(func() { runCommand = orig })
+	return fake
+}
+
+func TestKeyStore_Keychain(t *testing.T) {
+	fake := useFakeKeychain(t)
+	keys := KeyStore{Source: KeySourceKeychain, Account: "/home/me/.kodama"}
+
+	_, err := keys.Load()
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	key, err := keys.LoadOrCreate()
+	require.NoError(t, err)
+	assert.Len(t, key, KeySize)
+	assert.Contains(t, fake.entries, "/home/me/.kodama")
+
+	again, err := keys.Load()
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+}
+
+func TestKeyStore_KeychainErrorKeepsKey(t *testing.T) {
+	fake := useFakeKeychain(t)
+	fake.failErr = &commandError{Name: "secret-tool", ExitCode: 1, Stderr: "Cannot autolaunch D-Bus"}
+	if runtime.GOOS == "darwin" {
+		fake.failErr = &commandError{Name: "security", ExitCode: 36, Stderr: "keychain is locked"}
+	}
+
+	// A keychain that can't be read must not be treated as empty
+	_, err := KeyStore{Account: "dir"}.LoadOrCreate()
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrKeyNotFound)
+	assert.Empty(t, fake.entries)
+}
+
+func TestKeyStore_MovesLegacyKey(t *testing.T) {
+	fake := useFakeKeychain(t)
+	legacy := filepath.Join(t.TempDir(), "store.key")
+	want := make([]byte, KeySize)
+	want[0] = 7
+	require.NoError(t, os.WriteFile(legacy, []byte(hex.EncodeToString(want)+"\n"), 0o600))
+	keys := KeyStore{Account: "dir", LegacyPath: legacy}
+
+	// Files encrypted by older versions stay readable before migration
+	key, err := keys.Load()
+	require.NoError(t, err)
+	assert.Equal(t, want, key)
+
+	key, err = keys.LoadOrCreate()
+	require.NoError(t, err)
+	assert.Equal(t, want, key)
+	assert.Equal(t, hex.EncodeToString(want), strings.TrimSpace(fake.entries["dir"]))
+	assert.NoFileExists(t, legacy)
+}
+
+func TestKeyStore_Age(t *testing.T) {
+	t.Setenv(KeyEnvVar, "")
+	dir := t.TempDir()
+	var calls [][]string
+	orig := runCommand
+	runCommand = func(ctx context.Context, stdin []byte, name string, args ...string) ([]byte, error) {
+		calls = append(calls, append([]string{name}, args...))
+		if args[0] == "--encrypt" {
+			return nil, os.WriteFile(args[len(args)-1], []byte("age:"+string(stdin)), 0o644)
+		}
+		data, err := os.ReadFile(args[len(args)-1])
+		return []byte(strings.TrimPrefix(string(data), "age:")), err
+	}
+	t.Cleanup(func() { runCommand = orig })
+
+	keys := KeyStore{
+		Source:       KeySourceAge,
+		AgeFile:      filepath.Join(dir, "store.key.age"),
+		AgeRecipient: "age1example",
+		AgeIdentity:  filepath.Join(dir, "keys.txt"),
+	}
+
+	_, err := keys.Load()
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	key, err := keys.LoadOrCreate()
+	require.NoError(t, err)
+
+	again, err := keys.Load()
+	require.NoError(t, err)
+	assert.Equal(t, key, again)
+
+	info, err := os.Stat(keys.AgeFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+	assert.Equal(t, []string{"age", "--encrypt", "--recipient", "age1example", "--output", keys.AgeFile}, calls[0])
+	for _, call := range calls {
+		assert.NotContains(t, strings.Join(call, " "), hex.EncodeToString(key), "the key is passed on stdin")
+	}
+}
+
+func TestKeyStore_UnknownSource(t *testing.T) {
+	t.Setenv(KeyEnvVar, "")
+
+	_, err := KeyStore{Source: "vault"}.LoadOrCreate()
+	assert.ErrorContains(t, err, "unknown store.keySource")
+}
@@ -0,0 +1,361 @@
+package env
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/sops"
+)
+
+// includeDirective pulls another dotenv file into the current one,
+// resolved relative to the including file's directory
+const includeDirective = "#include"
+
+// dotenvParser parses dotenv files with docker-compose style semantics:
+//   - ${VAR}, $VAR and the ${VAR:-default}, ${VAR:?message} and ${VAR:+alternate}
+//     forms (with or without the colon) are expanded against previously
+//     defined variables, then the local environment
+//   - single-quoted values are literal, double-quoted values support escapes
+//   - $$ produces a literal $
+//   - "#include other.env" inlines another file at that position
+type dotenvParser struct {
+	vars     map[string]string
+	visiting map[string]bool
+}
+
+func newDotenvParser(vars map[string]string) *dotenvParser {
+	return &dotenvParser{
+		vars:     vars,
+		visiting: make(map[string]bool),
+	}
+}
+
+// parseFile reads a dotenv file and merges its variables into p.vars
+// SOPS-encrypted files are decrypted in memory and never written to disk
+func (p *dotenvParser) parseFile(file string) error {
+	absPath, err := filepath.Abs(file)
+	if err != nil {
+		return fmt.Errorf("failed to resolve dotenv file %s: %w", file, err)
+	}
+	if p.visiting[absPath] {
+		return fmt.Errorf("include cycle detected at %s", file)
+	}
+	p.visiting[absPath] = true
+	defer delete(p.visiting, absPath)
+
+	data, err := os.ReadFile(absPath) // #nosec G304 -- user-specified dotenv file
+	if err != nil {
+		return fmt.Errorf("failed to read dotenv file %s: %w", file, err)
+	}
+
+	if sops.IsEncrypted(data) {
+		data, err = sops.Decrypt(context.Background(), absPath)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt dotenv file %s: %w", file, err)
+		}
+	}
+
+	if err := p.parse(string(data), filepath.Dir(absPath)); err != nil {
+		return fmt.Errorf("failed to parse dotenv file %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// parse parses dotenv content, resolving includes relative to baseDir
+func (p *dotenvParser) parse(content string, baseDir string) error {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	for i := 0; i < len(lines); i++ {
+		lineNum := i + 1
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" {
+			continue
+		}
+
+		if rest, ok := strings.CutPrefix(line, includeDirective); ok && (rest == "" || rest[0] == ' ' || rest[0] == '\t') {
+			if err := p.include(rest, baseDir); err != nil {
+				return fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+
+		key, rawValue, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return fmt.Errorf("line %d: expected KEY=VALUE", lineNum)
+		}
+
+		rawValue = strings.TrimLeft(rawValue, " \t")
+
+		var (
+			value string
+			err   error
+		)
+		switch {
+		case strings.HasPrefix(rawValue, "'"), strings.HasPrefix(rawValue, `"`):
+			quote := rawValue[0]
+			var (
+				body     string
+				consumed int
+			)
+			if body, consumed, err = readQuoted(rawValue[1:], lines[i+1:], quote); err != nil {
+				return fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			i += consumed
+
+			if quote == '\'' {
+				value = body
+			} else {
+				value, err = p.expand(body, true)
+			}
+		default:
+			value, err = p.expand(stripInlineComment(rawValue), false)
+		}
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNum, err)
+		}
+
+		p.vars[key] = value
+	}
+
+	return nil
+}
+
+// include parses the file named by an #include directive
+func (p *dotenvParser) include(arg string, baseDir string) error {
+	path := strings.Trim(strings.TrimSpace(arg), `"'`)
+	if path == "" {
+		return fmt.Errorf("%s requires a file path", includeDirective)
+	}
+
+	path, err := p.expand(path, false)
+	if err != nil {
+		return err
+	}
+	if strings.HasPrefix(path, "~") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return fmt.Errorf("failed to expand home directory: %w", err)
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+
+	return p.parseFile(path)
+}
+
+// readQuoted reads a quoted value that may span multiple lines
+// Returns the value body and the number of extra lines consumed
+func readQuoted(first string, rest []string, quote byte) (string, int, error) {
+	text := first
+	consumed := 0
+
+	for {
+		if end := findClosingQuote(text, quote); end >= 0 {
+			return text[:end], consumed, nil
+		}
+		if consumed >= len(rest) {
+			return "", 0, fmt.Errorf("unterminated quoted value")
+		}
+		text += "\n" + rest[consumed]
+		consumed++
+	}
+}
+
+// findClosingQuote returns the index of the closing quote, honouring
+// backslash escapes inside double quotes
+func findClosingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		if quote == '"' && s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == quote {
+			return i
+		}
+	}
+	return -1
+}
+
+// stripInlineComment removes a trailing " # comment" from an unquoted value
+func stripInlineComment(s string) string {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			s = s[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(s)
+}
+
+// lookup resolves a variable from previously parsed values, then the local environment
+func (p *dotenvParser) lookup(name string) (string, bool) {
+	if v, ok := p.vars[name]; ok {
+		return v, true
+	}
+	return os.LookupEnv(name)
+}
+
+// expand performs variable interpolation on s
+// When escapes is true, backslash escapes (\n, \t, \", \$, ...) are also processed
+func (p *dotenvParser) expand(s string, escapes bool) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		if escapes && c == '\\' && i+1 < len(s) {
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(s[i])
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+
+		if c != '$' || i+1 >= len(s) {
+			b.WriteByte(c)
+			continue
+		}
+
+		next := s[i+1]
+		switch {
+		case next == '$':
+			b.WriteByte('$')
+			i++
+		case next == '{':
+			end := findClosingBrace(s[i+2:])
+			if end < 0 {
+				b.WriteByte(c)
+				continue
+			}
+			value, err := p.expandBraced(s[i+2 : i+2+end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			i += end + 2
+		case isVarNameStart(next):
+			j := i + 1
+			for j < len(s) && isVarNameChar(s[j]) {
+				j++
+			}
+			value, _ := p.lookup(s[i+1 : j])
+			b.WriteString(value)
+			i = j - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return b.String(), nil
+}
+
+// findClosingBrace returns the index of the } closing a ${, skipping the
+// ones closing nested expansions such as ${A:-${B}}
+func findClosingBrace(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// expandBraced resolves the inside of ${...}:
+//   - ${VAR:-word} and ${VAR-word} use word when VAR is unset or empty (:-), or unset (-)
+//   - ${VAR:?msg} and ${VAR?msg} fail with msg when VAR is unset or empty (:?), or unset (?)
+//   - ${VAR:+word} and ${VAR+word} use word when VAR is set and not empty (:+), or set (+)
+//
+// word and msg are only expanded when used.
+func (p *dotenvParser) expandBraced(expr string) (string, error) {
+	n := 0
+	for n < len(expr) && isVarNameChar(expr[n]) {
+		n++
+	}
+	name, op := expr[:n], expr[n:]
+	if name == "" || !isVarNameStart(name[0]) {
+		return "", fmt.Errorf("invalid variable expansion ${%s}", expr)
+	}
+
+	value, found := p.lookup(name)
+	if op == "" {
+		return value, nil
+	}
+
+	// A leading colon also treats an empty value as unset
+	set := found
+	if rest, ok := strings.CutPrefix(op, ":"); ok {
+		op = rest
+		set = found && value != ""
+	}
+	if op == "" {
+		return "", fmt.Errorf("invalid variable expansion ${%s}", expr)
+	}
+
+	word := op[1:]
+	switch op[0] {
+	case '-':
+		if set {
+			return value, nil
+		}
+		return p.expand(word, false)
+	case '?':
+		if set {
+			return value, nil
+		}
+		msg, err := p.expand(word, false)
+		if err != nil {
+			return "", err
+		}
+		if msg == "" {
+			msg = "required variable is not set"
+		}
+		return "", fmt.Errorf("%s: %s", name, msg)
+	case '+':
+		if !set {
+			return "", nil
+		}
+		return p.expand(word, false)
+	default:
+		return "", fmt.Errorf("invalid variable expansion ${%s}", expr)
+	}
+}
+
+func isVarNameStart(c byte) bool {
+	return c == '_' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')
+}
+
+func isVarNameChar(c byte) bool {
+	return isVarNameStart(c) || (c >= '0' && c <= '9')
+}
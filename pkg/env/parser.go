@@ -4,8 +4,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-
-	"github.com/joho/godotenv"
 )
 
 // LoadDotenvFiles loads and merges multiple dotenv files with last-wins precedence
@@ -16,6 +14,9 @@ func LoadDotenvFiles(files []string) (map[string]string, error) {
 
 	result := make(map[string]string)
 
+	// Variables from earlier files are visible to ${VAR} references in later ones
+	parser := newDotenvParser(result)
+
 	for _, file := range files {
 		// Expand ~ to home directory
 		if file[0] == '~' {
@@ -33,15 +34,9 @@ func LoadDotenvFiles(files []string) (map[string]string, error) {
 			continue
 		}
 
-		// Load the dotenv file
-		env, err := godotenv.Read(file)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse dotenv file %s: %w", file, err)
-		}
-
-		// Merge with last-wins precedence
-		for key, value := range env {
-			result[key] = value
+		// Load the dotenv file, merging into result with last-wins precedence
+		if err := parser.parseFile(file); err != nil {
+			return nil, err
 		}
 	}
 
@@ -3,6 +3,7 @@ package env
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -66,6 +67,67 @@ func TestLoadDotenvFiles(t *testing.T) {
 				"VAR2": "value2",
 			},
 		},
+		{
+			name: "variable expansion",
+			files: map[string]string{
+				"expand.env": "HOST=localhost\nPORT=5432\nURL=postgres://${HOST}:$PORT/db\nLITERAL='${HOST}'\nESCAPED=\"cost \\$5\"\nDOLLAR=a$$b\n",
+			},
+			fileOrder: []string{"expand.env"},
+			want: map[string]string{
+				"HOST":    "localhost",
+				"PORT":    "5432",
+				"URL":     "postgres://localhost:5432/db",
+				"LITERAL": "${HOST}",
+				"ESCAPED": "cost $5",
+				"DOLLAR":  "a$b",
+			},
+		},
+		{
+			name: "expansion across files and defaults",
+			files: map[string]string{
+				"base.env":     "REGION=us-east-1\n",
+				"override.env": "BUCKET=data-${REGION}\nSTAGE=${KODAMA_TEST_UNSET_VAR:-dev}\n",
+			},
+			fileOrder: []string{"base.env", "override.env"},
+			want: map[string]string{
+				"REGION": "us-east-1",
+				"BUCKET": "data-us-east-1",
+				"STAGE":  "dev",
+			},
+		},
+		{
+			name: "include directive",
+			files: map[string]string{
+				"main.env":   "BEFORE=1\n#include common.env\nAFTER=${SHARED}-after\n",
+				"common.env": "SHARED=shared # inline comment\nBEFORE=overridden\n",
+			},
+			fileOrder: []string{"main.env"},
+			want: map[string]string{
+				"BEFORE": "overridden",
+				"SHARED": "shared",
+				"AFTER":  "shared-after",
+			},
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"a.env": "#include b.env\n",
+				"b.env": "#include a.env\n",
+			},
+			fileOrder:   []string{"a.env"},
+			expectError: true,
+		},
+		{
+			name: "multiline double quoted value",
+			files: map[string]string{
+				"multi.env": "CERT=\"line1\nline2\"\nNEXT=ok\n",
+			},
+			fileOrder: []string{"multi.env"},
+			want: map[string]string{
+				"CERT": "line1\nline2",
+				"NEXT": "ok",
+			},
+		},
 		{
 			name: "malformed dotenv",
 			files: map[string]string{
@@ -135,6 +197,73 @@ func TestLoadDotenvFiles(t *testing.T) {
 	}
 }
 
+func TestDotenvParser_ExpandBraced(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr string
+	}{
+		{"plain", "${SET}", "value", ""},
+		{"unset", "${KODAMA_TEST_UNSET_VAR}", "", ""},
+		{"default unset", "${KODAMA_TEST_UNSET_VAR:-dflt}", "dflt", ""},
+		{"default empty", "${EMPTY:-dflt}", "dflt", ""},
+		{"default set", "${SET:-dflt}", "value", ""},
+		{"dash default unset", "${KODAMA_TEST_UNSET_VAR-dflt}", "dflt", ""},
+		{"dash default empty", "${EMPTY-dflt}", "", ""},
+		{"nested default", "${KODAMA_TEST_UNSET_VAR:-${SET}}", "value", ""},
+		{"nested default twice", "a-${KODAMA_TEST_UNSET_VAR:-${EMPTY:-${SET}-x}}-b", "a-value-x-b", ""},
+		{"required set", "${SET:?must be set}", "value", ""},
+		{"required unset", "${KODAMA_TEST_UNSET_VAR:?must be set}", "", "KODAMA_TEST_UNSET_VAR: must be set"},
+		{"required empty", "${EMPTY:?must be set}", "", "EMPTY: must be set"},
+		{"question unset", "${KODAMA_TEST_UNSET_VAR?missing}", "", "KODAMA_TEST_UNSET_VAR: missing"},
+		{"question empty", "${EMPTY?missing}", "", ""},
+		{"required without message", "${KODAMA_TEST_UNSET_VAR:?}", "", "required variable is not set"},
+		{"required in unused default", "${SET:-${KODAMA_TEST_UNSET_VAR:?unused}}", "value", ""},
+		{"alternate set", "${SET:+alt}", "alt", ""},
+		{"alternate empty", "${EMPTY:+alt}", "", ""},
+		{"alternate unset", "${KODAMA_TEST_UNSET_VAR:+alt}", "", ""},
+		{"plus empty", "${EMPTY+alt}", "alt", ""},
+		{"plus unset", "${KODAMA_TEST_UNSET_VAR+alt}", "", ""},
+		{"nested alternate", "${SET:+--flag=${SET}}", "--flag=value", ""},
+		{"unterminated", "${SET", "${SET", ""},
+		{"invalid name", "${1A}", "", "invalid variable expansion"},
+		{"invalid operator", "${SET:=x}", "", "invalid variable expansion"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newDotenvParser(map[string]string{"SET": "value", "EMPTY": ""})
+			got, err := p.expand(tt.input, false)
+
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expand(%q) error = %v, want %q", tt.input, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expand(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("expand(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDotenvFiles_RequiredVariable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "required.env")
+	if err := os.WriteFile(path, []byte("A=1\nTOKEN=${KODAMA_TEST_UNSET_VAR:?set it in your shell}\n"), 0o600); err != nil {
+		t.Fatalf("failed to write dotenv file: %v", err)
+	}
+
+	_, err := LoadDotenvFiles([]string{path})
+	if err == nil || !strings.Contains(err.Error(), "line 2: KODAMA_TEST_UNSET_VAR: set it in your shell") {
+		t.Errorf("LoadDotenvFiles() error = %v, want the line and message of the required variable", err)
+	}
+}
+
 func TestLoadDotenvFiles_MissingFile(t *testing.T) {
 	// Missing files should warn but continue
 	result, err := LoadDotenvFiles([]string{"/nonexistent/file.env"})
@@ -146,6 +275,23 @@ func TestLoadDotenvFiles_MissingFile(t *testing.T) {
 	}
 }
 
+func TestLoadDotenvFiles_LocalEnvExpansion(t *testing.T) {
+	t.Setenv("KODAMA_TEST_LOCAL_USER", "alice")
+
+	path := filepath.Join(t.TempDir(), "local.env")
+	if err := os.WriteFile(path, []byte("GREETING=hello ${KODAMA_TEST_LOCAL_USER}\n"), 0o600); err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+
+	got, err := LoadDotenvFiles([]string{path})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got["GREETING"] != "hello alice" {
+		t.Errorf("GREETING: got %q, want %q", got["GREETING"], "hello alice")
+	}
+}
+
 func TestApplyExclusions(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,74 @@
+package env
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/shellutil"
+)
+
+const (
+	// ShellFilePath is the env file inside the pod sourced by new shells
+	// It lives in the pod user's home, outside the /workspace git tree, so the
+	// secret values it holds are never committed.
+	ShellFilePath = "~/.kodama-env"
+
+	// shellFile is ShellFilePath as a shell expression
+	shellFile = `"$HOME/.kodama-env"`
+
+	// legacyShellFilePath is where older versions wrote the env file
+	legacyShellFilePath = "/workspace/.kodama-env"
+
+	// shellSourceLine is appended to shell rc files to load ShellFilePath
+	shellSourceLine = "[ -f " + shellFile + " ] && . " + shellFile
+)
+
+// RenderShellFile renders variables as a POSIX shell script of export statements
+// Variables in unset are cleared first, so values baked into the pod environment
+// at creation time can be removed without a restart. Invalid variable names are
+// skipped, so the file never contains anything but plain assignments.
+func RenderShellFile(vars map[string]string, unset []string) string {
+	var b strings.Builder
+	b.WriteString("# Managed by kodama - do not edit (use 'kubectl kodama env')\n")
+
+	sortedUnset := append([]string(nil), unset...)
+	sort.Strings(sortedUnset)
+	for _, key := range sortedUnset {
+		if _, ok := vars[key]; ok || ValidateVarName(key) != nil {
+			continue
+		}
+		b.WriteString("unset " + key + "\n")
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if ValidateVarName(key) != nil {
+			continue
+		}
+		b.WriteString("export " + key + "=" + shellutil.Quote(vars[key]) + "\n")
+	}
+
+	return b.String()
+}
+
+// InstallShellFileCommand returns a command that writes content to ShellFilePath
+// and makes interactive shells source it
+// A file left in /workspace by older versions is removed.
+func InstallShellFileCommand(content string) []string {
+	script := `set -e
+file=` + shellFile + `
+umask 077
+printf '%s' "$1" > "$file.tmp"
+mv "$file.tmp" "$file"
+rm -f "$2"
+for rc in "$HOME/.bashrc" "$HOME/.profile"; do
+  grep -qsF "$3" "$rc" || printf '\n%s\n' "$3" >> "$rc"
+done`
+
+	return []string{"sh", "-c", script, "sh", content, legacyShellFilePath, shellSourceLine}
+}
@@ -0,0 +1,45 @@
+package env
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderShellFile(t *testing.T) {
+	got := RenderShellFile(
+		map[string]string{
+			"B_VAR": "it's here",
+			"A_VAR": "$HOME value",
+		},
+		[]string{"OLD_TOKEN", "B_VAR"},
+	)
+
+	want := "# Managed by kodama - do not edit (use 'kubectl kodama env')\n" +
+		"unset OLD_TOKEN\n" +
+		"export A_VAR='$HOME value'\n" +
+		"export B_VAR='it'\\''s here'\n"
+
+	if got != want {
+		t.Errorf("RenderShellFile() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRenderShellFile_SkipsInvalidNames(t *testing.T) {
+	got := RenderShellFile(map[string]string{"bad name": "x"}, []string{"X; rm -rf /"})
+
+	want := "# Managed by kodama - do not edit (use 'kubectl kodama env')\n"
+	if got != want {
+		t.Errorf("RenderShellFile() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestInstallShellFileCommand_OutsideWorkspace(t *testing.T) {
+	cmd := InstallShellFileCommand("export A=1\n")
+
+	if !strings.Contains(cmd[2], `file="$HOME/.kodama-env"`) {
+		t.Errorf("script does not write to the home directory:\n%s", cmd[2])
+	}
+	if cmd[5] != legacyShellFilePath {
+		t.Errorf("legacy path = %q, want %q", cmd[5], legacyShellFilePath)
+	}
+}
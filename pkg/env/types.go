@@ -6,6 +6,10 @@ type EnvConfig struct {
 	ExcludeVars   []string `yaml:"excludeVars,omitempty"`
 	SecretName    string   `yaml:"secretName,omitempty"`
 	SecretCreated bool     `yaml:"secretCreated,omitempty"`
+
+	// UnsetVars tracks variables removed via 'kodama env unset' so the runtime
+	// env file can clear values injected when the pod was created
+	UnsetVars []string `yaml:"unsetVars,omitempty"`
 }
 
 // DefaultExcludedVars contains system-critical variables that should never be overridden
@@ -0,0 +1,44 @@
+package gitcmd
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"time"
+)
+
+// DefaultBranchPrefix starts generated branch names unless branchPrefix is configured
+const DefaultBranchPrefix = "kodama/"
+
+// BranchNamer generates the branch a session works on when none is given
+type BranchNamer interface {
+	BranchName(prefix, session string) string
+}
+
+// TimestampBranchNamer names branches <prefix><session>-<UTC time>-<suffix>.
+// The random suffix keeps sessions started in the same second, e.g. by a
+// batch, or recreated under the same name, from sharing a branch.
+type TimestampBranchNamer struct {
+	Now     func() time.Time // Clock (time.Now)
+	Entropy io.Reader        // Source of the suffix (crypto/rand.Reader)
+}
+
+// NewBranchNamer creates a TimestampBranchNamer using the wall clock and crypto/rand
+func NewBranchNamer() BranchNamer {
+	return &TimestampBranchNamer{Now: time.Now, Entropy: rand.Reader}
+}
+
+// branchSuffixBytes is the size of the random suffix (6 hex digits)
+const branchSuffixBytes = 3
+
+// BranchName returns the branch name for session
+func (n *TimestampBranchNamer) BranchName(prefix, session string) string {
+	name := prefix + session + "-" + n.Now().UTC().Format("20060102150405")
+
+	suffix := make([]byte, branchSuffixBytes)
+	if _, err := io.ReadFull(n.Entropy, suffix); err != nil {
+		// The timestamp alone is still a usable name
+		return name
+	}
+	return name + "-" + hex.EncodeToString(suffix)
+}
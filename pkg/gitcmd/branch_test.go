@@ -0,0 +1,33 @@
+package gitcmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimestampBranchNamer(t *testing.T) {
+	now := time.Date(2025, 1, 15, 23, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+	namer := &TimestampBranchNamer{
+		Now:     func() time.Time { return now },
+		Entropy: bytes.NewReader([]byte{0x0a, 0xbc, 0xde, 0x01, 0x02, 0x03}),
+	}
+
+	assert.Equal(t, "kodama/my-work-20250115143000-0abcde", namer.BranchName(DefaultBranchPrefix, "my-work"))
+	// Same clock, next entropy: no collision
+	assert.Equal(t, "feature/my-work-20250115143000-010203", namer.BranchName("feature/", "my-work"))
+
+	namer.Entropy = iotest.ErrReader(errors.New("no entropy"))
+	assert.Equal(t, "kodama/my-work-20250115143000", namer.BranchName(DefaultBranchPrefix, "my-work"))
+}
+
+func TestNewBranchNamer(t *testing.T) {
+	namer := NewBranchNamer()
+	a, b := namer.BranchName(DefaultBranchPrefix, "x"), namer.BranchName(DefaultBranchPrefix, "x")
+	assert.NotEqual(t, a, b)
+	assert.Regexp(t, `^kodama/x-\d{14}-[0-9a-f]{6}$`, a)
+}
@@ -5,38 +5,88 @@ import (
 	"strings"
 )
 
+// DefaultCloneAttempts is how often installing git and cloning are attempted per remote
+const DefaultCloneAttempts = 3
+
+// ProgressMarker prefixes structured progress lines in the init script output
+// Each marker is followed by space-separated key=value fields, e.g.
+// "KODAMA_PROGRESS stage=clone remote=origin attempt=1/3 status=retry".
+const ProgressMarker = "KODAMA_PROGRESS"
+
 // CloneOptions contains options for git clone command
 type CloneOptions struct {
 	Branch       string // Branch to clone
 	Depth        int    // Clone depth (0 for full clone)
 	SingleBranch bool   // Clone only specified branch
 	ExtraArgs    string // Additional git clone arguments
+	Attempts     int    // Attempts per remote with exponential backoff (0 = DefaultCloneAttempts)
+	MirrorURL    string // Remote cloned from when the repository URL keeps failing
+}
+
+// retryFunctions defines shell helpers for retrying with backoff and reporting progress
+const retryFunctions = `kodama_progress() {
+    echo "` + ProgressMarker + ` stage=$KODAMA_STAGE $*"
 }
+# Usage: kodama_retry <attempts> <command...>
+kodama_retry() {
+    local attempts=$1 attempt=1 delay=2
+    shift
+    until "$@"; do
+        if [ "$attempt" -ge "$attempts" ]; then
+            kodama_progress "$KODAMA_DETAIL attempt=$attempt/$attempts status=failed"
+            KODAMA_DETAIL="$KODAMA_DETAIL attempts=$attempt"
+            return 1
+        fi
+        kodama_progress "$KODAMA_DETAIL attempt=$attempt/$attempts status=retry delay=${delay}s"
+        sleep "$delay"
+        attempt=$((attempt + 1))
+        delay=$((delay * 2))
+    done
+}
+`
+
+// injectTokenFunction rewrites HTTPS URLs to authenticate with GH_TOKEN
+const injectTokenFunction = `kodama_clone_url() {
+    if [[ "$1" == https://* ]] && [ -n "$GH_TOKEN" ]; then
+        # Inject token into HTTPS URL
+        echo "${1/https:\/\//https://${GH_TOKEN}@}"
+    else
+        echo "$1"
+    fi
+}
+`
 
 // BuildCloneCommandScript builds a bash script for git clone with token injection
-// This is used by init containers and can be reused for other purposes
+// Installing git and cloning are retried with exponential backoff; when the
+// repository keeps failing, the mirror (if any) is cloned and origin is reset
+// to the repository URL. This is used by init containers and can be reused for other purposes
 func BuildCloneCommandScript(repoURL string, opts *CloneOptions) string {
+	attempts := DefaultCloneAttempts
+	if opts != nil && opts.Attempts > 0 {
+		attempts = opts.Attempts
+	}
+
 	var script strings.Builder
 
 	script.WriteString("set -e\n")
+	script.WriteString(retryFunctions)
+	script.WriteString(injectTokenFunction)
+	script.WriteString("\n")
+
+	script.WriteString("KODAMA_STAGE=install-git\n")
+	script.WriteString("KODAMA_DETAIL=\n")
 	script.WriteString("echo 'Installing git...'\n")
-	script.WriteString("apt-get update -qq && apt-get install -y -qq git\n\n")
+	script.WriteString(fmt.Sprintf("kodama_retry %d bash -c 'apt-get update -qq && apt-get install -y -qq git'\n\n", attempts))
 
+	script.WriteString("KODAMA_STAGE=clone\n")
 	script.WriteString("echo 'Cloning repository...'\n")
 	script.WriteString(fmt.Sprintf("REPO_URL='%s'\n", repoURL))
+	script.WriteString("CLONE_URL=$(kodama_clone_url \"$REPO_URL\")\n\n")
 
-	// Inject token for HTTPS URLs
-	script.WriteString(`
-if [[ "$REPO_URL" == https://* ]] && [ -n "$GH_TOKEN" ]; then
-    # Inject token into HTTPS URL
-    CLONE_URL="${REPO_URL/https:\/\//https://${GH_TOKEN}@}"
-else
-    CLONE_URL="$REPO_URL"
-fi
-`)
-
-	// Build clone command
-	script.WriteString("git clone")
+	// Build clone command; leftovers of a failed attempt are removed first
+	script.WriteString("kodama_clone() {\n")
+	script.WriteString("    find /workspace -mindepth 1 -delete 2>/dev/null || true\n")
+	script.WriteString("    git clone")
 	if opts != nil && opts.Depth > 0 {
 		script.WriteString(fmt.Sprintf(" --depth %d", opts.Depth))
 	}
@@ -49,7 +99,23 @@ fi
 	if opts != nil && opts.ExtraArgs != "" {
 		script.WriteString(fmt.Sprintf(" %s", opts.ExtraArgs))
 	}
-	script.WriteString(" \"$CLONE_URL\" /workspace\n\n")
+	script.WriteString(" \"$1\" /workspace\n")
+	script.WriteString("}\n\n")
+
+	script.WriteString("KODAMA_DETAIL=remote=origin\n")
+	if opts != nil && opts.MirrorURL != "" {
+		script.WriteString(fmt.Sprintf("if ! kodama_retry %d kodama_clone \"$CLONE_URL\"; then\n", attempts))
+		script.WriteString("    echo 'Repository unreachable, cloning from mirror...'\n")
+		script.WriteString(fmt.Sprintf("    MIRROR_URL='%s'\n", opts.MirrorURL))
+		script.WriteString("    KODAMA_DETAIL=remote=mirror\n")
+		script.WriteString(fmt.Sprintf("    kodama_retry %d kodama_clone \"$(kodama_clone_url \"$MIRROR_URL\")\"\n", attempts))
+		script.WriteString("    # Push and fetch against the repository, not the mirror\n")
+		script.WriteString("    git -C /workspace remote set-url origin \"$CLONE_URL\"\n")
+		script.WriteString("fi\n")
+	} else {
+		script.WriteString(fmt.Sprintf("kodama_retry %d kodama_clone \"$CLONE_URL\"\n", attempts))
+	}
+	script.WriteString("kodama_progress \"$KODAMA_DETAIL status=done\"\n\n")
 
 	script.WriteString("echo 'Repository clone complete'\n")
 	return script.String()
@@ -85,12 +151,17 @@ fi
 func BuildGitInitScript(repoURL, targetBranch string, opts *CloneOptions) string {
 	var script strings.Builder
 
+	// Record the failing stage (and remote) so the pod status can explain clone failures
+	script.WriteString("trap 'echo \"stage=$KODAMA_STAGE $KODAMA_DETAIL\" > /dev/termination-log' ERR\n")
+
 	// Add clone script
 	script.WriteString(BuildCloneCommandScript(repoURL, opts))
 	script.WriteString("\n")
 
 	// Add branch setup script if target branch specified
 	if targetBranch != "" {
+		script.WriteString("KODAMA_STAGE=branch\n")
+		script.WriteString("KODAMA_DETAIL=\n")
 		script.WriteString(BuildBranchSetupScript(targetBranch))
 		script.WriteString("\n")
 	}
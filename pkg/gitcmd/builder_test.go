@@ -0,0 +1,31 @@
+package gitcmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCloneCommandScript_Retry(t *testing.T) {
+	script := BuildCloneCommandScript("https://github.com/org/repo.git", &CloneOptions{Depth: 1})
+
+	assert.Contains(t, script, "kodama_retry 3 kodama_clone \"$CLONE_URL\"")
+	assert.Contains(t, script, "git clone --depth 1 \"$1\" /workspace")
+	assert.Contains(t, script, ProgressMarker+" stage=$KODAMA_STAGE")
+	assert.NotContains(t, script, "MIRROR_URL")
+
+	script = BuildCloneCommandScript("https://github.com/org/repo.git", &CloneOptions{Attempts: 5})
+	assert.Contains(t, script, "kodama_retry 5 kodama_clone \"$CLONE_URL\"")
+}
+
+func TestBuildCloneCommandScript_Mirror(t *testing.T) {
+	script := BuildCloneCommandScript("https://github.com/org/repo.git", &CloneOptions{
+		MirrorURL: "https://mirror.example.com/org/repo.git",
+	})
+
+	assert.Contains(t, script, "MIRROR_URL='https://mirror.example.com/org/repo.git'")
+	assert.Contains(t, script, "KODAMA_DETAIL=remote=mirror")
+	assert.Contains(t, script, "git -C /workspace remote set-url origin \"$CLONE_URL\"")
+	assert.Less(t, strings.Index(script, "remote=origin"), strings.Index(script, "remote=mirror"), "origin is tried first")
+}
@@ -0,0 +1,152 @@
+package gitcmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/shellutil"
+)
+
+// localChangesPatch is where BuildApplyChangesScript stores the patch in the pod
+const localChangesPatch = "/tmp/kodama-local-changes.patch"
+
+// LocalChanges are the uncommitted changes of a local working tree
+type LocalChanges struct {
+	Base  string   // Commit the changes are relative to, the local HEAD
+	Files []string // Changed paths relative to the repository root
+	Patch []byte   // Binary diff from Base to the working tree
+}
+
+// ReadLocalChanges diffs the working tree of the repository containing dir
+// against HEAD, including untracked files that are not ignored. The real index
+// is left alone: files are staged in a temporary index.
+func ReadLocalChanges(ctx context.Context, dir string) (*LocalChanges, error) {
+	base, err := runGit(ctx, dir, nil, "rev-parse", "--verify", "HEAD")
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a git repository with commits: %w", dir, err)
+	}
+
+	indexDir, err := os.MkdirTemp("", "kodama-index-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary index: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(indexDir) }()
+	env := []string{"GIT_INDEX_FILE=" + filepath.Join(indexDir, "index")}
+
+	if _, err := runGit(ctx, dir, env, "read-tree", "HEAD"); err != nil {
+		return nil, err
+	}
+	if _, err := runGit(ctx, dir, env, "add", "--all"); err != nil {
+		return nil, err
+	}
+	names, err := runGit(ctx, dir, env, "diff", "--cached", "--name-only", "-z", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	patch, err := runGit(ctx, dir, env, "diff", "--cached", "--binary", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &LocalChanges{Base: strings.TrimSpace(string(base)), Patch: patch}
+	for _, name := range strings.Split(string(names), "\x00") {
+		if name != "" {
+			changes.Files = append(changes.Files, name)
+		}
+	}
+	return changes, nil
+}
+
+// runGit runs git in dir with extra environment variables and returns its output
+func runGit(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...) // #nosec G204 -- fixed git subcommands
+	cmd.Env = append(os.Environ(), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// BuildApplyChangesScript builds a shell script applying the patch of a
+// LocalChanges, read from stdin, to the working tree of /workspace. When the
+// clone is not at base, the patch is merged three-way from base, which is
+// fetched if the clone does not have it; the changes stay unstaged as they
+// were locally. A patch that does not merge is rolled back: tracked files are
+// checked out again and the files it created, among files, are removed.
+func BuildApplyChangesScript(base string, files []string) string {
+	quoted := make([]string, len(files))
+	for i, file := range files {
+		quoted[i] = shellutil.Quote(file)
+	}
+	return fmt.Sprintf(`set -e
+cat > %[1]s
+cd /workspace
+if git apply --whitespace=nowarn %[1]s 2>/dev/null; then
+    :
+elif git cat-file -e '%[2]s^{commit}' 2>/dev/null || git fetch -q --depth=1 origin '%[2]s'; then
+    echo 'Merging local changes from %[2]s into the clone' >&2
+    if ! git apply --3way --whitespace=nowarn %[1]s; then
+        git reset -q
+        git checkout -q -- .
+        git clean -fq -- %[3]s
+        rm -f %[1]s
+        echo 'Local changes conflict with the clone; its working tree was restored' >&2
+        exit 1
+    fi
+    git reset -q
+else
+    echo 'Local changes do not apply to the clone and commit %[2]s is not on the remote; push it first' >&2
+    exit 1
+fi
+rm -f %[1]s
+echo 'Local changes applied'
+`, localChangesPatch, base, strings.Join(quoted, " "))
+}
+
+// OriginURL returns the URL of the origin remote of the repository containing dir
+func OriginURL(ctx context.Context, dir string) (string, error) {
+	out, err := runGit(ctx, dir, nil, "remote", "get-url", "origin")
+	if err != nil {
+		return "", fmt.Errorf("%s has no origin remote: %w", dir, err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// SameRepository reports whether two clone URLs name the same repository,
+// ignoring the scheme, user, a trailing .git and case, so that
+// https://github.com/org/repo and git@github.com:org/repo.git match
+func SameRepository(a, b string) bool {
+	return repositoryKey(a) == repositoryKey(b)
+}
+
+// repositoryKey reduces a clone URL to host/path
+func repositoryKey(url string) string {
+	key := strings.ToLower(strings.TrimSpace(url))
+	_, rest, hasScheme := strings.Cut(key, "://")
+	if hasScheme {
+		key = rest
+	}
+
+	host, path, _ := strings.Cut(key, "/")
+	if _, after, ok := strings.Cut(host, "@"); ok {
+		host = after
+	}
+	if name, after, ok := strings.Cut(host, ":"); ok {
+		host = name
+		if !hasScheme {
+			// scp-like syntax, [user@]host:path, rather than a port
+			path = after + "/" + path
+		}
+	}
+
+	path = strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	return host + "/" + path
+}
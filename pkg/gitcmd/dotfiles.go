@@ -0,0 +1,62 @@
+package gitcmd
+
+import (
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/shellutil"
+)
+
+// DotfilesDir is where the dotfiles repository is cloned in the pod
+const DotfilesDir = "$HOME/.dotfiles"
+
+// Dotfiles is a dotfiles repository and how to install it
+type Dotfiles struct {
+	Repo           string
+	Branch         string // Empty clones the default branch
+	InstallCommand string // Empty detects the installer, see defaultDotfilesInstall
+}
+
+// defaultDotfilesInstall runs the first install script found, like GitHub
+// Codespaces, then applies a chezmoi source directory, then falls back to
+// linking the repository's top-level dotfiles into $HOME
+const defaultDotfilesInstall = `for f in install.sh install bootstrap.sh bootstrap script/bootstrap setup.sh setup script/setup; do
+  if [ -f "$f" ]; then chmod +x "$f"; exec "./$f"; fi
+done
+if command -v chezmoi >/dev/null 2>&1 && { [ -e .chezmoiroot ] || ls -d dot_* >/dev/null 2>&1; }; then
+  exec chezmoi init --apply --source "$PWD"
+fi
+for f in .[!.]*; do
+  [ -e "$f" ] || continue
+  case "$f" in .git|.github|.gitignore|.gitmodules) continue ;; esac
+  ln -sfn "$PWD/$f" "$HOME/$f"
+done
+`
+
+// BuildDotfilesScript builds a POSIX shell script that clones the dotfiles
+// repository into DotfilesDir, or pulls it when a previous start cloned it,
+// and runs the installer there. HTTPS URLs are authenticated with GH_TOKEN
+// when it is set.
+func BuildDotfilesScript(dotfiles Dotfiles) string {
+	var script strings.Builder
+	script.WriteString("set -e\n")
+	script.WriteString("if ! command -v git >/dev/null 2>&1; then printf 'git is not installed in the image\\n' >&2; exit 1; fi\n")
+	script.WriteString("url=" + shellutil.Quote(dotfiles.Repo) + "\n")
+	script.WriteString(`case "$url" in https://*) if [ -n "$GH_TOKEN" ]; then url="https://${GH_TOKEN}@${url#https://}"; fi ;; esac` + "\n")
+	script.WriteString(`dir="` + DotfilesDir + `"` + "\n")
+
+	clone, pull := `git clone -q --depth 1 "$url" "$dir"`, `git -C "$dir" pull -q --ff-only "$url"`
+	if dotfiles.Branch != "" {
+		branch := shellutil.Quote(dotfiles.Branch)
+		clone = `git clone -q --depth 1 --branch ` + branch + ` "$url" "$dir"`
+		pull += " " + branch
+	}
+	script.WriteString(`if [ -d "$dir/.git" ]; then ` + pull + `; else ` + clone + `; fi` + "\n")
+	script.WriteString(`cd "$dir"` + "\n")
+
+	if dotfiles.InstallCommand != "" {
+		script.WriteString(dotfiles.InstallCommand + "\n")
+	} else {
+		script.WriteString(defaultDotfilesInstall)
+	}
+	return script.String()
+}
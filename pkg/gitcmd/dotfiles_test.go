@@ -0,0 +1,83 @@
+package gitcmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dotfilesRepo creates a local git repository holding files
+func dotfilesRepo(t *testing.T, files map[string]string) string {
+	t.Helper()
+	for _, tool := range []string{"git", "sh"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skip(tool + " not available")
+		}
+	}
+	repo := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(repo, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "-A"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "dotfiles"},
+	} {
+		out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	return repo
+}
+
+// runDotfilesScript runs the script with home as $HOME
+func runDotfilesScript(t *testing.T, home string, dotfiles Dotfiles) {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", BuildDotfilesScript(dotfiles)) //#nosec G204 -- test script
+	cmd.Env = append(os.Environ(), "HOME="+home, "GH_TOKEN=")
+	out, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(out))
+}
+
+func TestBuildDotfilesScript_InstallScript(t *testing.T) {
+	repo := dotfilesRepo(t, map[string]string{"install.sh": "#!/bin/sh\necho installed >> \"$HOME/marker\"\n"})
+	home := t.TempDir()
+
+	runDotfilesScript(t, home, Dotfiles{Repo: repo})
+	// A second start pulls the existing clone instead of cloning again
+	runDotfilesScript(t, home, Dotfiles{Repo: repo})
+
+	marker, err := os.ReadFile(filepath.Join(home, "marker"))
+	require.NoError(t, err)
+	assert.Equal(t, "installed\ninstalled\n", string(marker))
+	assert.DirExists(t, filepath.Join(home, ".dotfiles", ".git"))
+}
+
+func TestBuildDotfilesScript_LinksDotfiles(t *testing.T) {
+	repo := dotfilesRepo(t, map[string]string{".zshrc": "alias k=kubectl\n", ".config/nvim/init.lua": "", "README.md": ""})
+	home := t.TempDir()
+
+	runDotfilesScript(t, home, Dotfiles{Repo: repo})
+
+	target, err := os.Readlink(filepath.Join(home, ".zshrc"))
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(home, ".dotfiles", ".zshrc"), target)
+	assert.FileExists(t, filepath.Join(home, ".config", "nvim", "init.lua"))
+	assert.NoFileExists(t, filepath.Join(home, "README.md"))
+	assert.NoFileExists(t, filepath.Join(home, ".git"))
+}
+
+func TestBuildDotfilesScript_InstallCommand(t *testing.T) {
+	script := BuildDotfilesScript(Dotfiles{Repo: "https://github.com/me/dotfiles", Branch: "main", InstallCommand: "stow -t ~ zsh git"})
+
+	assert.Contains(t, script, "url='https://github.com/me/dotfiles'\n")
+	assert.Contains(t, script, `git clone -q --depth 1 --branch 'main' "$url" "$dir"`)
+	assert.Contains(t, script, `pull -q --ff-only "$url" 'main'`)
+	assert.Contains(t, script, "\nstow -t ~ zsh git\n")
+	assert.NotContains(t, script, "install.sh", "the configured command replaces detection")
+}
@@ -0,0 +1,39 @@
+package gitcmd
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/shellutil"
+)
+
+// BuildHooksScript builds a shell script installing git hooks in /workspace
+// Each hook script, given relative to the workspace, is copied into the
+// repository's hooks directory. Without hooks, 'pre-commit install' is run,
+// which needs pre-commit in the image and a .pre-commit-config.yaml.
+func BuildHooksScript(hooks map[string]string) string {
+	var script strings.Builder
+	script.WriteString("set -e\n")
+	script.WriteString("cd /workspace\n")
+	script.WriteString("if ! git rev-parse --git-dir >/dev/null 2>&1; then printf '/workspace is not a git repository\\n' >&2; exit 1; fi\n")
+
+	if len(hooks) == 0 {
+		script.WriteString("if [ ! -f .pre-commit-config.yaml ]; then printf 'no .pre-commit-config.yaml in /workspace\\n' >&2; exit 1; fi\n")
+		script.WriteString("if ! command -v pre-commit >/dev/null 2>&1; then printf 'pre-commit is not installed in the image\\n' >&2; exit 1; fi\n")
+		script.WriteString("pre-commit install\n")
+		return script.String()
+	}
+
+	script.WriteString("HOOKS_DIR=$(git rev-parse --git-path hooks)\n")
+	script.WriteString("mkdir -p \"$HOOKS_DIR\"\n")
+	names := make([]string, 0, len(hooks))
+	for name := range hooks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		script.WriteString("cp " + shellutil.Quote(hooks[name]) + " \"$HOOKS_DIR\"/" + shellutil.Quote(name) + "\n")
+		script.WriteString("chmod +x \"$HOOKS_DIR\"/" + shellutil.Quote(name) + "\n")
+	}
+	return script.String()
+}
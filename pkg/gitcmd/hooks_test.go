@@ -0,0 +1,24 @@
+package gitcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildHooksScript(t *testing.T) {
+	script := BuildHooksScript(nil)
+	assert.Contains(t, script, "cd /workspace\n")
+	assert.Contains(t, script, "[ ! -f .pre-commit-config.yaml ]")
+	assert.Contains(t, script, "pre-commit install\n")
+	assert.NotContains(t, script, "HOOKS_DIR")
+
+	script = BuildHooksScript(map[string]string{
+		"pre-commit": "scripts/pre-commit.sh",
+		"commit-msg": "scripts/it's-msg.sh",
+	})
+	assert.NotContains(t, script, "pre-commit install")
+	assert.Contains(t, script, "HOOKS_DIR=$(git rev-parse --git-path hooks)\n")
+	assert.Contains(t, script, "cp 'scripts/it'\\''s-msg.sh' \"$HOOKS_DIR\"/'commit-msg'\nchmod +x \"$HOOKS_DIR\"/'commit-msg'\n"+
+		"cp 'scripts/pre-commit.sh' \"$HOOKS_DIR\"/'pre-commit'\n", "hooks are installed in name order")
+}
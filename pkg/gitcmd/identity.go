@@ -0,0 +1,76 @@
+package gitcmd
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/shellutil"
+)
+
+// Identity is a commit author
+type Identity struct {
+	Name  string
+	Email string
+}
+
+// IsEmpty reports whether neither name nor email is set
+func (i Identity) IsEmpty() bool {
+	return i.Name == "" && i.Email == ""
+}
+
+// LocalIdentity reads user.name and user.email from the local git config as
+// seen from dir. Unset values, or a missing git binary, yield empty fields.
+func LocalIdentity(ctx context.Context, dir string) Identity {
+	return Identity{
+		Name:  localConfigValue(ctx, dir, "user.name"),
+		Email: localConfigValue(ctx, dir, "user.email"),
+	}
+}
+
+// localConfigValue returns a git config value, or "" when it is unset
+func localConfigValue(ctx context.Context, dir, key string) string {
+	args := []string{"config", "--get", key}
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	out, err := exec.CommandContext(ctx, "git", args...).Output() // #nosec G204 -- fixed git subcommand
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// BuildIdentityScript builds a shell script setting user.name and user.email
+// With global, the values go to ~/.gitconfig and, when /workspace is a
+// repository, to its config too, which takes precedence over ~/.gitconfig.
+// Without global, only /workspace's config is written.
+func BuildIdentityScript(identity Identity, global bool) string {
+	return configScript([]configEntry{
+		{"user.name", identity.Name},
+		{"user.email", identity.Email},
+	}, global)
+}
+
+// configEntry is a git config key and value; empty values are skipped
+type configEntry struct {
+	key, value string
+}
+
+// configScript writes entries with git config, scoped as in BuildIdentityScript
+func configScript(entries []configEntry, global bool) string {
+	var script strings.Builder
+	for _, entry := range entries {
+		if entry.value == "" {
+			continue
+		}
+		if global {
+			script.WriteString("git config --global " + entry.key + " " + shellutil.Quote(entry.value) + "\n")
+			script.WriteString("if git -C /workspace rev-parse --git-dir >/dev/null 2>&1; then git -C /workspace config " +
+				entry.key + " " + shellutil.Quote(entry.value) + "; fi\n")
+		} else {
+			script.WriteString("git -C /workspace config " + entry.key + " " + shellutil.Quote(entry.value) + "\n")
+		}
+	}
+	return script.String()
+}
@@ -0,0 +1,55 @@
+package gitcmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIdentityScript(t *testing.T) {
+	identity := Identity{Name: "Jane O'Neil", Email: "jane@example.com"}
+
+	script := BuildIdentityScript(identity, false)
+	assert.Equal(t, "git -C /workspace config user.name 'Jane O'\\''Neil'\n"+
+		"git -C /workspace config user.email 'jane@example.com'\n", script)
+
+	script = BuildIdentityScript(identity, true)
+	assert.Contains(t, script, "git config --global user.name 'Jane O'\\''Neil'\n")
+	assert.Contains(t, script, "then git -C /workspace config user.email 'jane@example.com'; fi\n")
+
+	// Unset fields are left alone
+	script = BuildIdentityScript(Identity{Email: "jane@example.com"}, false)
+	assert.NotContains(t, script, "user.name")
+	assert.Empty(t, BuildIdentityScript(Identity{}, true))
+}
+
+func TestLocalIdentity(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".gitconfig"), []byte("[user]\n\tname = Jane Doe\n\temail = jane@example.com\n"), 0o600))
+
+	repo := t.TempDir()
+	require.NoError(t, exec.Command("git", "init", "-q", repo).Run())
+	require.NoError(t, exec.Command("git", "-C", repo, "config", "user.email", "jane@work.example.com").Run())
+
+	// Repository config overrides the global config, as it does for git itself
+	identity := LocalIdentity(context.Background(), repo)
+	assert.Equal(t, Identity{Name: "Jane Doe", Email: "jane@work.example.com"}, identity)
+
+	identity = LocalIdentity(context.Background(), home)
+	assert.Equal(t, "jane@example.com", identity.Email)
+
+	require.NoError(t, os.Remove(filepath.Join(home, ".gitconfig")))
+	identity = LocalIdentity(context.Background(), home)
+	assert.True(t, identity.IsEmpty())
+}
@@ -0,0 +1,39 @@
+package gitcmd
+
+// Signing key location in session pods
+const (
+	// SigningKeyDir is where the signing key Secret is mounted in the main container
+	SigningKeyDir = "/etc/kodama/signing"
+
+	// SigningKeyName is the Secret key holding the private signing key
+	SigningKeyName = "signing-key"
+
+	// SigningKeyPath is the mounted private signing key
+	SigningKeyPath = SigningKeyDir + "/" + SigningKeyName
+)
+
+// Signing configures commit signing with the mounted key
+type Signing struct {
+	Format string // ssh (default) or gpg
+	Key    string // GPG key ID; ssh signs with SigningKeyPath
+}
+
+// BuildSigningScript builds a shell script that makes git sign commits and tags
+// Scoping follows BuildIdentityScript. With global, a GPG key is imported into
+// the keyring first, which needs the key mounted and gpg in the image.
+func BuildSigningScript(signing Signing, global bool) string {
+	format, key := "ssh", SigningKeyPath
+	var script string
+	if signing.Format == "gpg" {
+		format, key = "openpgp", signing.Key
+		if global {
+			script = "gpg --batch --quiet --import " + SigningKeyPath + "\n"
+		}
+	}
+	return script + configScript([]configEntry{
+		{"gpg.format", format},
+		{"user.signingkey", key},
+		{"commit.gpgsign", "true"},
+		{"tag.gpgsign", "true"},
+	}, global)
+}
@@ -0,0 +1,25 @@
+package gitcmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildSigningScript(t *testing.T) {
+	script := BuildSigningScript(Signing{}, false)
+	assert.Equal(t, "git -C /workspace config gpg.format 'ssh'\n"+
+		"git -C /workspace config user.signingkey '/etc/kodama/signing/signing-key'\n"+
+		"git -C /workspace config commit.gpgsign 'true'\n"+
+		"git -C /workspace config tag.gpgsign 'true'\n", script)
+
+	// GPG keys are imported only where the key is mounted
+	script = BuildSigningScript(Signing{Format: "gpg", Key: "ABCD1234"}, false)
+	assert.NotContains(t, script, "gpg --batch")
+	assert.Contains(t, script, "config gpg.format 'openpgp'")
+	assert.Contains(t, script, "config user.signingkey 'ABCD1234'")
+
+	script = BuildSigningScript(Signing{Format: "gpg", Key: "ABCD1234"}, true)
+	assert.Contains(t, script, "gpg --batch --quiet --import /etc/kodama/signing/signing-key\n")
+	assert.Contains(t, script, "git config --global commit.gpgsign 'true'")
+}
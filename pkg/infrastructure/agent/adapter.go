@@ -26,7 +26,7 @@ func NewAdapterWithExecutor(executor agent.CodingAgentExecutor) port.AgentExecut
 	}
 }
 
-// TaskStart initiates a new coding task with the given prompt
-func (a *Adapter) TaskStart(ctx context.Context, namespace, podName, prompt string) (taskID string, err error) {
-	return a.executor.TaskStart(ctx, namespace, podName, prompt)
+// TaskStart runs a new coding task with the given prompt until it finishes
+func (a *Adapter) TaskStart(ctx context.Context, namespace, podName, prompt, logPath string) (taskID string, err error) {
+	return a.executor.TaskStart(ctx, namespace, podName, prompt, logPath)
 }
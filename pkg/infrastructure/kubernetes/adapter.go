@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"context"
+	"io"
 	"os/exec"
 	"time"
 
@@ -11,7 +12,8 @@ import (
 
 // Adapter implements port.KubernetesClient using the existing kubernetes.Client
 type Adapter struct {
-	client *k8s.Client
+	client   *k8s.Client
+	executor k8s.CommandExecutor
 }
 
 // NewAdapter creates a new Kubernetes adapter
@@ -20,7 +22,15 @@ func NewAdapter(kubeconfigPath string) (port.KubernetesClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Adapter{client: client}, nil
+	return NewAdapterWithClient(client), nil
+}
+
+// NewAdapterWithClient creates a Kubernetes adapter around an existing client
+func NewAdapterWithClient(client *k8s.Client) port.KubernetesClient {
+	return &Adapter{
+		client:   client,
+		executor: k8s.NewKubectlExecutor(),
+	}
 }
 
 // Pod operations
@@ -51,19 +61,39 @@ func (a *Adapter) WaitForPodDeleted(ctx context.Context, name, namespace string,
 	return a.client.WaitForPodDeleted(ctx, name, namespace, timeout)
 }
 
+// ListSessionPods retrieves the status of all kodama pods in a namespace
+func (a *Adapter) ListSessionPods(ctx context.Context, namespace string) (map[string]*k8s.PodStatus, error) {
+	return a.client.ListSessionPods(ctx, namespace)
+}
+
 // GetPodIP retrieves the IP address of a pod
 func (a *Adapter) GetPodIP(ctx context.Context, name, namespace string) (string, error) {
 	return a.client.GetPodIP(ctx, name, namespace)
 }
 
+// StreamPodLogs opens a log stream for a container in a pod
+func (a *Adapter) StreamPodLogs(ctx context.Context, name, namespace, container string, follow bool, tailLines int64) (io.ReadCloser, error) {
+	return a.client.StreamPodLogs(ctx, name, namespace, container, follow, tailLines)
+}
+
+// GetContainerCrash returns the last termination of the main container with its last log lines
+func (a *Adapter) GetContainerCrash(ctx context.Context, name, namespace string, logLines int64) (*k8s.ContainerCrash, error) {
+	return a.client.GetContainerCrash(ctx, name, namespace, logLines)
+}
+
 // Secret operations
 
 // CreateSecret creates a secret with the given data
-func (a *Adapter) CreateSecret(ctx context.Context, name, namespace string, data map[string]string) error {
-	_, err := a.client.CreateSecret(ctx, name, namespace, data, false)
+func (a *Adapter) CreateSecret(ctx context.Context, name, namespace string, data, labels map[string]string) error {
+	_, err := a.client.CreateSecret(ctx, name, namespace, data, labels, false)
 	return err
 }
 
+// SetSecretsOwner makes the pod the owner of the secrets
+func (a *Adapter) SetSecretsOwner(ctx context.Context, namespace, podName string, secretNames ...string) error {
+	return a.client.SetSecretsOwner(ctx, namespace, podName, secretNames...)
+}
+
 // DeleteSecret deletes a secret
 func (a *Adapter) DeleteSecret(ctx context.Context, name, namespace string) error {
 	return a.client.DeleteSecret(ctx, name, namespace)
@@ -75,16 +105,102 @@ func (a *Adapter) SecretExists(ctx context.Context, name, namespace string) (boo
 }
 
 // CreateFileSecret creates a secret from files
-func (a *Adapter) CreateFileSecret(ctx context.Context, name, namespace string, files map[string][]byte) error {
-	_, err := a.client.CreateFileSecret(ctx, name, namespace, files, false)
+func (a *Adapter) CreateFileSecret(ctx context.Context, name, namespace string, files map[string][]byte, labels map[string]string) error {
+	_, err := a.client.CreateFileSecret(ctx, name, namespace, files, labels, false)
 	return err
 }
 
+// GetSecretData retrieves the data of a secret
+func (a *Adapter) GetSecretData(ctx context.Context, name, namespace string) (map[string]string, error) {
+	return a.client.GetSecretData(ctx, name, namespace)
+}
+
+// UpdateSecretData replaces the data of an existing secret
+func (a *Adapter) UpdateSecretData(ctx context.Context, name, namespace string, data map[string]string) error {
+	return a.client.UpdateSecretData(ctx, name, namespace, data)
+}
+
+// ConfigMap operations
+
+// DeleteConfigMap deletes a ConfigMap
+func (a *Adapter) DeleteConfigMap(ctx context.Context, name, namespace string) error {
+	return a.client.DeleteConfigMap(ctx, name, namespace)
+}
+
+// Session resource operations
+
+// DeleteSessionResources deletes the tracked and labeled Secrets and ConfigMaps of a session
+func (a *Adapter) DeleteSessionResources(ctx context.Context, namespace, session string, tracked []k8s.SessionResource) ([]k8s.SessionResource, error) {
+	return a.client.DeleteSessionResources(ctx, namespace, session, tracked)
+}
+
+// ListStaleSessionResources lists session Secrets and ConfigMaps whose session has no pod
+func (a *Adapter) ListStaleSessionResources(ctx context.Context, namespace string, minAge time.Duration) ([]k8s.SessionResource, error) {
+	return a.client.ListStaleSessionResources(ctx, namespace, minAge)
+}
+
+// DeleteSessionResource deletes a session Secret or ConfigMap
+func (a *Adapter) DeleteSessionResource(ctx context.Context, resource k8s.SessionResource) error {
+	return a.client.DeleteSessionResource(ctx, resource)
+}
+
+// Exec operations
+
+// ExecInPod executes a command inside a pod
+func (a *Adapter) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	return a.executor.ExecInPod(ctx, namespace, podName, command)
+}
+
+// Reaper operations
+
+// InstallReaper creates or updates the expired-session reaper CronJob
+func (a *Adapter) InstallReaper(ctx context.Context, opts k8s.ReaperOptions) error {
+	return a.client.InstallReaper(ctx, opts)
+}
+
+// Dependency cache operations
+
+// EnsureCachePVC creates the shared dependency cache PVC if it does not exist
+func (a *Adapter) EnsureCachePVC(ctx context.Context, opts k8s.CacheWarmOptions) (bool, error) {
+	return a.client.EnsureCachePVC(ctx, opts)
+}
+
+// RunCacheWarmJob runs the cache warm Job and waits for it to finish
+func (a *Adapter) RunCacheWarmJob(ctx context.Context, opts k8s.CacheWarmOptions, timeout time.Duration) (string, error) {
+	return a.client.RunCacheWarmJob(ctx, opts, timeout)
+}
+
+// Image operations
+
+// PrepullImage pulls an image onto every node with a temporary DaemonSet
+func (a *Adapter) PrepullImage(ctx context.Context, opts k8s.PrepullOptions, timeout time.Duration) (*k8s.PrepullResult, error) {
+	return a.client.PrepullImage(ctx, opts, timeout)
+}
+
+// Quota operations
+
+// GetQuotaReport sums the kodama pods' resources and lists the namespace's ResourceQuotas
+func (a *Adapter) GetQuotaReport(ctx context.Context, namespace string) (*k8s.QuotaReport, error) {
+	return a.client.GetQuotaReport(ctx, namespace)
+}
+
+// Session lock operations
+
+// AcquireSessionLock takes or renews the lock lease on a session pod
+func (a *Adapter) AcquireSessionLock(ctx context.Context, namespace, podName, holder string, ttl time.Duration, steal bool) (*k8s.SessionLock, error) {
+	return a.client.AcquireSessionLock(ctx, namespace, podName, holder, ttl, steal)
+}
+
+// ReleaseSessionLock removes the lock lease if holder still holds it
+func (a *Adapter) ReleaseSessionLock(ctx context.Context, namespace, podName, holder string) error {
+	return a.client.ReleaseSessionLock(ctx, namespace, podName, holder)
+}
+
 // Port forwarding
 
 // StartPortForward starts port forwarding to a pod
-func (a *Adapter) StartPortForward(ctx context.Context, podName string, localPort, remotePort int) (*exec.Cmd, error) {
-	return a.client.StartPortForward(ctx, podName, localPort, remotePort)
+func (a *Adapter) StartPortForward(ctx context.Context, namespace, podName string, localPort, remotePort int) (*exec.Cmd, error) {
+	return a.client.StartPortForward(ctx, namespace, podName, localPort, remotePort)
 }
 
 // Utility operations
@@ -94,6 +210,11 @@ func (a *Adapter) GetCurrentNamespace() (string, error) {
 	return a.client.GetCurrentNamespace()
 }
 
+// GetCurrentContext returns the current kubeconfig context, or "" without a kubeconfig
+func (a *Adapter) GetCurrentContext() string {
+	return a.client.GetCurrentContext()
+}
+
 // Ping verifies connectivity to the Kubernetes cluster
 func (a *Adapter) Ping(ctx context.Context) error {
 	return a.client.Ping(ctx)
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"github.com/illumination-k/kodama/pkg/agent/auth"
 	"github.com/illumination-k/kodama/pkg/application/port"
 	"github.com/illumination-k/kodama/pkg/config"
 )
@@ -19,6 +20,11 @@ func NewConfigFileRepository() (port.ConfigRepository, error) {
 	return &ConfigFileRepository{store: store}, nil
 }
 
+// NewConfigFileRepositoryWithStore creates a repository backed by an existing store
+func NewConfigFileRepositoryWithStore(store *config.Store) port.ConfigRepository {
+	return &ConfigFileRepository{store: store}
+}
+
 // NewConfigFileRepositoryWithPath creates a repository with a custom config directory
 func NewConfigFileRepositoryWithPath(configDir string) port.ConfigRepository {
 	return &ConfigFileRepository{
@@ -37,8 +43,13 @@ func (r *ConfigFileRepository) SaveGlobalConfig(cfg *config.GlobalConfig) error
 }
 
 // LoadSessionTemplate loads a session template from an arbitrary path
-func (r *ConfigFileRepository) LoadSessionTemplate(path string) (*config.SessionConfig, error) {
-	return r.store.LoadSessionTemplate(path)
+func (r *ConfigFileRepository) LoadSessionTemplate(path string, values map[string]interface{}) (*config.SessionConfig, error) {
+	return r.store.LoadSessionTemplate(path, values)
+}
+
+// LoadSessionTemplates loads a session template and the templates it extends, base first
+func (r *ConfigFileRepository) LoadSessionTemplates(path string, values map[string]interface{}) ([]*config.SessionConfig, error) {
+	return r.store.LoadSessionTemplates(path, values)
 }
 
 // EnsureConfigDir creates the configuration directory structure if it doesn't exist
@@ -50,3 +61,13 @@ func (r *ConfigFileRepository) EnsureConfigDir() error {
 func (r *ConfigFileRepository) GetGlobalConfigPath() string {
 	return r.store.GetGlobalConfigPath()
 }
+
+// MigrateSchema rewrites files written with an older schema version
+func (r *ConfigFileRepository) MigrateSchema(dryRun bool) ([]config.SchemaMigration, error) {
+	return r.store.MigrateSchema(dryRun)
+}
+
+// SaveAuthProfile stores a coding agent auth profile in the auth cache
+func (r *ConfigFileRepository) SaveAuthProfile(name string, profile auth.Profile) error {
+	return r.store.SaveAuthProfile(name, profile)
+}
@@ -19,6 +19,11 @@ func NewSessionFileRepository() (port.SessionRepository, error) {
 	return &SessionFileRepository{store: store}, nil
 }
 
+// NewSessionFileRepositoryWithStore creates a repository backed by an existing store
+func NewSessionFileRepositoryWithStore(store *config.Store) port.SessionRepository {
+	return &SessionFileRepository{store: store}
+}
+
 // NewSessionFileRepositoryWithPath creates a repository with a custom config directory
 func NewSessionFileRepositoryWithPath(configDir string) port.SessionRepository {
 	return &SessionFileRepository{
@@ -46,12 +51,47 @@ func (r *SessionFileRepository) ListSessions() ([]*config.SessionConfig, error)
 	return r.store.ListSessions()
 }
 
+// QuerySessions returns the sessions matching query, in query order
+func (r *SessionFileRepository) QuerySessions(query config.SessionQuery) ([]*config.SessionConfig, error) {
+	return r.store.QuerySessions(query)
+}
+
 // SessionExists checks if a session exists
 func (r *SessionFileRepository) SessionExists(name string) bool {
 	return r.store.SessionExists(name)
 }
 
 // GetSessionPath returns the file path for a session config
-func (r *SessionFileRepository) GetSessionPath(name string) string {
+func (r *SessionFileRepository) GetSessionPath(name string) (string, error) {
 	return r.store.GetSessionPath(name)
 }
+
+// MigrateToEncrypted rewrites existing plaintext files in encrypted form
+func (r *SessionFileRepository) MigrateToEncrypted() (int, error) {
+	return r.store.MigrateToEncrypted()
+}
+
+// CurrentSession returns the session selected with 'kodama use'
+func (r *SessionFileRepository) CurrentSession() (string, error) {
+	return r.store.CurrentSession()
+}
+
+// SetCurrentSession records the current session; an empty name clears it
+func (r *SessionFileRepository) SetCurrentSession(name string) error {
+	return r.store.SetCurrentSession(name)
+}
+
+// RecordEvent appends an event to a session's timeline
+func (r *SessionFileRepository) RecordEvent(name string, event config.SessionEvent) error {
+	return r.store.RecordEvent(name, event)
+}
+
+// LoadEvents returns a session's timeline, oldest first
+func (r *SessionFileRepository) LoadEvents(name string) ([]config.SessionEvent, error) {
+	return r.store.LoadEvents(name)
+}
+
+// PruneOrphanedSessionDirs removes the local directories of sessions whose config is gone
+func (r *SessionFileRepository) PruneOrphanedSessionDirs(dryRun bool) ([]string, error) {
+	return r.store.PruneOrphanedSessionDirs(dryRun)
+}
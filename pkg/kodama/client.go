@@ -0,0 +1,195 @@
+// Package kodama is the Go API for embedding kodama in other tools, such as IDE
+// plugins or internal portals, without shelling out to the kubectl-kodama binary.
+//
+// Progress messages that the CLI prints are written to Options.Output and are
+// discarded by default. The destination is process-wide: the most recently
+// created Client decides where every Client's messages go. Attach is
+// interactive and uses the process terminal.
+package kodama
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/application"
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// Session is the persisted state of a session
+type Session = config.SessionConfig
+
+// SessionStatus pairs a session with the status of its pod
+type SessionStatus = service.SessionStatus
+
+// SessionQuery filters and orders the sessions returned by List
+type SessionQuery = config.SessionQuery
+
+// Errors returned by Client methods, for use with errors.Is
+var (
+	ErrSessionNotFound     = config.ErrSessionNotFound
+	ErrAgentFailed         = usecase.ErrAgentFailed
+	ErrAgentBudgetExceeded = config.ErrAgentBudgetExceeded
+)
+
+// Options configures a Client
+type Options struct {
+	KubeconfigPath string    // Default: KUBECONFIG, KODAMA_K8S_* variables or ~/.kube/config
+	Output         io.Writer // Receives progress messages of all Clients in the process (default: discarded)
+}
+
+// Client manages kodama sessions. The config store, Kubernetes client and
+// progress output are shared with the process, so create one Client and reuse it.
+type Client struct {
+	app            *application.App
+	kubeconfigPath string
+}
+
+// New creates a Client
+// It redirects the process-wide progress output to opts.Output; writes to it
+// are serialized, so concurrent Clients and sync watchers may share it.
+func New(opts Options) (*Client, error) {
+	output := opts.Output
+	if output == nil {
+		output = io.Discard
+	}
+	usecase.SetOutput(output)
+
+	app, err := application.NewApp(opts.KubeconfigPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{app: app, kubeconfigPath: opts.KubeconfigPath}, nil
+}
+
+// StartOptions contains options for starting a session
+// Unset fields fall back to the template in ConfigFile and the global config.
+type StartOptions struct {
+	Name             string
+	Repo             string // Git repository to clone (mutually exclusive with SyncPath)
+	Branch           string
+	SyncPath         string // Local directory to sync into the workspace
+	Namespace        string
+	Image            string
+	CPU              string
+	Memory           string
+	Resources        map[string]string      // Extended resources, e.g. "nvidia.com/gpu": "1"
+	ConfigFile       string                 // Session template
+	TemplateValues   map[string]interface{} // Values for template expressions, as with --set
+	Overlay          string                 // Template overlay, as with --overlay
+	EnvFiles         []string               // Dotenv files injected as a secret
+	Prompt           string                 // Coding agent task to start after the workspace is ready
+	FailOnAgentError bool                   // Return ErrAgentFailed instead of ignoring agent failures
+	SaveAgentLog     bool                   // Copy the agent output log into the session's local artifacts
+	DisableTtyd      bool                   // Do not run the web terminal
+	Expires          time.Duration          // Session lifetime (0 = never expires)
+	Labels           map[string]string      // For filtering with SessionQuery.Labels
+}
+
+// StartSession creates a session and waits until its workspace is ready
+func (c *Client) StartSession(ctx context.Context, opts StartOptions) (*Session, error) {
+	if opts.Name == "" {
+		return nil, fmt.Errorf("session name is required")
+	}
+	return usecase.StartSession(ctx, c.startSessionOptions(opts))
+}
+
+func (c *Client) startSessionOptions(opts StartOptions) usecase.StartSessionOptions {
+	return usecase.StartSessionOptions{
+		Name:             opts.Name,
+		Repo:             opts.Repo,
+		Branch:           opts.Branch,
+		SyncPath:         opts.SyncPath,
+		NoSync:           opts.Repo == "" && opts.SyncPath == "", // Never sync the embedding process's working directory implicitly
+		Namespace:        opts.Namespace,
+		Image:            opts.Image,
+		CPU:              opts.CPU,
+		Memory:           opts.Memory,
+		CustomResources:  opts.Resources,
+		ConfigFile:       opts.ConfigFile,
+		TemplateValues:   opts.TemplateValues,
+		Overlay:          opts.Overlay,
+		EnvFiles:         opts.EnvFiles,
+		Prompt:           opts.Prompt,
+		FailOnAgentError: opts.FailOnAgentError,
+		SaveAgentLog:     opts.SaveAgentLog,
+		TtydEnabled:      opts.DisableTtyd,
+		TtydEnabledVal:   !opts.DisableTtyd,
+		Expires:          opts.Expires,
+		Labels:           opts.Labels,
+		KubeconfigPath:   c.kubeconfigPath,
+	}
+}
+
+// AttachOptions contains options for attaching to a session
+type AttachOptions struct {
+	Command   string // Default: interactive shell
+	Container string // Default: main container
+	TTY       bool   // Use kubectl exec instead of the web terminal
+	LocalPort int    // Port-forward port for the web terminal
+	NoBrowser bool   // Do not open the web terminal in a browser
+	Steal     bool   // Take over the session lock from another user
+}
+
+// Attach connects the process terminal to a session and blocks until it is closed
+func (c *Client) Attach(ctx context.Context, name string, opts AttachOptions) error {
+	return usecase.AttachSession(ctx, usecase.AttachSessionOptions{
+		Name:           name,
+		Command:        opts.Command,
+		Container:      opts.Container,
+		KubeconfigPath: c.kubeconfigPath,
+		TtyMode:        opts.TTY,
+		LocalPort:      opts.LocalPort,
+		NoBrowser:      opts.NoBrowser,
+		Steal:          opts.Steal,
+	})
+}
+
+// RunAgentOptions contains options for RunAgentWithOptions
+type RunAgentOptions struct {
+	IgnoreBudget bool // Run even if the session's agent.maxRuns or agent.maxDuration is used up
+	Steal        bool // Take over the session lock from another user or run
+}
+
+// RunAgent runs a coding agent task in a running session and records the execution
+// Returns ErrAgentBudgetExceeded when the session's agent budget is used up.
+func (c *Client) RunAgent(ctx context.Context, name, prompt string) (*Session, error) {
+	return c.RunAgentWithOptions(ctx, name, prompt, RunAgentOptions{})
+}
+
+// RunAgentWithOptions is RunAgent with options
+func (c *Client) RunAgentWithOptions(ctx context.Context, name, prompt string, opts RunAgentOptions) (*Session, error) {
+	session, err := c.app.SessionService.RunAgentWithOptions(ctx, name, prompt, config.AgentRunOptions{IgnoreBudget: opts.IgnoreBudget, StealLock: opts.Steal})
+	if errors.Is(err, config.ErrAgentBudgetExceeded) {
+		return session, err
+	}
+	if err != nil && session != nil {
+		return session, fmt.Errorf("%w: %w", ErrAgentFailed, err)
+	}
+	return session, err
+}
+
+// DeleteOptions contains options for deleting a session
+type DeleteOptions struct {
+	KeepConfig bool // Keep the session config with status Stopped
+}
+
+// Delete stops file sync, deletes the session's Kubernetes resources and removes its config
+func (c *Client) Delete(ctx context.Context, name string, opts DeleteOptions) error {
+	return usecase.DeleteSession(ctx, usecase.DeleteSessionOptions{
+		Name:           name,
+		KubeconfigPath: c.kubeconfigPath,
+		KeepConfig:     opts.KeepConfig,
+	})
+}
+
+// List returns the sessions matching query with the status of their pods
+// An empty query returns all sessions sorted by name.
+func (c *Client) List(ctx context.Context, query SessionQuery) ([]SessionStatus, error) {
+	return c.app.SessionService.ListSessionStatuses(ctx, query)
+}
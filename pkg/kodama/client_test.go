@@ -0,0 +1,42 @@
+package kodama
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStartSessionOptions(t *testing.T) {
+	c := &Client{kubeconfigPath: "/tmp/kubeconfig"}
+
+	t.Run("repo mode", func(t *testing.T) {
+		opts := c.startSessionOptions(StartOptions{
+			Name:        "task",
+			Repo:        "https://github.com/org/repo",
+			Resources:   map[string]string{"nvidia.com/gpu": "1"},
+			DisableTtyd: true,
+		})
+
+		assert.Equal(t, "task", opts.Name)
+		assert.Equal(t, "https://github.com/org/repo", opts.Repo)
+		assert.Equal(t, "/tmp/kubeconfig", opts.KubeconfigPath)
+		assert.Equal(t, map[string]string{"nvidia.com/gpu": "1"}, opts.CustomResources)
+		assert.False(t, opts.NoSync)
+		assert.True(t, opts.TtydEnabled, "disabling ttyd must override the template")
+		assert.False(t, opts.TtydEnabledVal)
+	})
+
+	t.Run("no workspace source does not sync the working directory", func(t *testing.T) {
+		opts := c.startSessionOptions(StartOptions{Name: "empty"})
+
+		assert.True(t, opts.NoSync)
+		assert.False(t, opts.TtydEnabled, "ttyd is left to the template and global config")
+	})
+
+	t.Run("sync mode", func(t *testing.T) {
+		opts := c.startSessionOptions(StartOptions{Name: "local", SyncPath: "/src"})
+
+		assert.False(t, opts.NoSync)
+		assert.Equal(t, "/src", opts.SyncPath)
+	})
+}
@@ -0,0 +1,233 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/illumination-k/kodama/pkg/shellutil"
+)
+
+const (
+	// CacheMountPath is where the shared dependency cache PVC is mounted
+	CacheMountPath = "/cache"
+
+	// DefaultCachePVC is the cache PVC name used when none is configured
+	DefaultCachePVC = "kodama-cache"
+
+	// DefaultCacheSize is the requested size when kodama creates the cache PVC
+	DefaultCacheSize = "20Gi"
+
+	// cacheVolumeName is the pod volume name for the cache PVC
+	cacheVolumeName = "dependency-cache"
+)
+
+// CacheEnvVars points common package managers at the shared cache
+// Environment variables are used instead of mounting at home-directory paths so
+// the cache works regardless of the image's user.
+func CacheEnvVars() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "GOMODCACHE", Value: CacheMountPath + "/go/mod"},
+		{Name: "GOCACHE", Value: CacheMountPath + "/go/build"},
+		{Name: "npm_config_cache", Value: CacheMountPath + "/npm"},
+		{Name: "npm_config_store_dir", Value: CacheMountPath + "/pnpm"},
+		{Name: "YARN_CACHE_FOLDER", Value: CacheMountPath + "/yarn"},
+		{Name: "PIP_CACHE_DIR", Value: CacheMountPath + "/pip"},
+		{Name: "UV_CACHE_DIR", Value: CacheMountPath + "/uv"},
+	}
+}
+
+// cacheVolume returns the pod volume and mount for the cache PVC
+func cacheVolume(pvcName string) (corev1.Volume, corev1.VolumeMount) {
+	return corev1.Volume{
+		Name: cacheVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: pvcName,
+			},
+		},
+	}, corev1.VolumeMount{
+		Name:      cacheVolumeName,
+		MountPath: CacheMountPath,
+	}
+}
+
+// CacheWarmOptions configures a cache warm job
+type CacheWarmOptions struct {
+	Namespace    string
+	PVC          string
+	Image        string
+	Repos        []string // Repositories whose dependencies are downloaded
+	Commands     []string // Extra shell commands run after the repositories
+	Size         string   // PVC size if it has to be created (default: DefaultCacheSize)
+	StorageClass string   // Storage class if the PVC has to be created
+}
+
+// BuildCachePVC builds the shared cache PVC
+// ReadWriteMany lets sessions on different nodes mount it at the same time.
+func BuildCachePVC(opts CacheWarmOptions) (*corev1.PersistentVolumeClaim, error) {
+	return buildReadWriteManyPVC(opts.Namespace, opts.PVC, "cache", opts.Size, DefaultCacheSize, opts.StorageClass)
+}
+
+// buildReadWriteManyPVC builds a PVC shared by the sessions of a namespace
+func buildReadWriteManyPVC(namespace, name, component, size, defaultSize, storageClass string) (*corev1.PersistentVolumeClaim, error) {
+	if size == "" {
+		size = defaultSize
+	}
+	quantity, err := resource.ParseQuantity(size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s size %q: %w", component, size, err)
+	}
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"app": "kodama", "component": component},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteMany},
+			Resources: corev1.VolumeResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: quantity},
+			},
+		},
+	}
+	if storageClass != "" {
+		pvc.Spec.StorageClassName = &storageClass
+	}
+
+	return pvc, nil
+}
+
+// BuildCacheWarmJob builds a Job that downloads dependencies into the cache
+func BuildCacheWarmJob(opts CacheWarmOptions) *batchv1.Job {
+	backoffLimit := int32(0)
+	ttl := int32(600)
+	volume, mount := cacheVolume(opts.PVC)
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kodama-cache-warm-",
+			Namespace:    opts.Namespace,
+			Labels:       map[string]string{"app": "kodama", "component": "cache-warm"},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": "kodama", "component": "cache-warm"},
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy: corev1.RestartPolicyNever,
+					Containers: []corev1.Container{{
+						Name:         "warm",
+						Image:        opts.Image,
+						Command:      []string{"/bin/sh", "-c", cacheWarmScript(opts.Repos, opts.Commands)},
+						Env:          CacheEnvVars(),
+						VolumeMounts: []corev1.VolumeMount{mount},
+					}},
+					Volumes: []corev1.Volume{volume},
+				},
+			},
+		},
+	}
+}
+
+// cacheWarmScript clones each repository and runs the download step for every
+// package manager it recognizes, then the extra commands
+func cacheWarmScript(repos, commands []string) string {
+	var b strings.Builder
+	b.WriteString("set -e\n")
+	b.WriteString("have() { command -v \"$1\" >/dev/null 2>&1; }\n")
+
+	for i, repo := range repos {
+		dir := fmt.Sprintf("/tmp/warm/%d", i)
+		fmt.Fprintf(&b, "echo %s\n", shellutil.Quote("==> "+repo))
+		fmt.Fprintf(&b, "git clone --depth 1 %s %s\n", shellutil.Quote(repo), dir)
+		fmt.Fprintf(&b, "cd %s\n", dir)
+		b.WriteString("if [ -f go.mod ] && have go; then go mod download -x; fi\n")
+		b.WriteString("if [ -f pnpm-lock.yaml ] && have pnpm; then pnpm fetch;\n")
+		b.WriteString("elif [ -f yarn.lock ] && have yarn; then yarn install --frozen-lockfile --ignore-scripts;\n")
+		b.WriteString("elif [ -f package-lock.json ] && have npm; then npm ci --ignore-scripts --no-audit;\n")
+		b.WriteString("fi\n")
+		b.WriteString("if [ -f uv.lock ] && have uv; then uv sync --frozen --no-install-project;\n")
+		b.WriteString("elif [ -f requirements.txt ] && have pip; then pip download -q -r requirements.txt -d /tmp/pip-download;\n")
+		b.WriteString("fi\n")
+	}
+
+	for _, command := range commands {
+		fmt.Fprintf(&b, "echo %s\n", shellutil.Quote("==> "+command))
+		b.WriteString(command + "\n")
+	}
+
+	b.WriteString("echo 'Cache warm complete'\n")
+	return b.String()
+}
+
+// EnsureCachePVC creates the shared cache PVC if it does not exist
+// Returns true if the PVC was created.
+func (c *Client) EnsureCachePVC(ctx context.Context, opts CacheWarmOptions) (bool, error) {
+	pvc, err := BuildCachePVC(opts)
+	if err != nil {
+		return false, err
+	}
+	return c.ensurePVC(ctx, pvc)
+}
+
+// ensurePVC creates pvc if no PVC of its name exists in its namespace
+func (c *Client) ensurePVC(ctx context.Context, pvc *corev1.PersistentVolumeClaim) (bool, error) {
+	_, err := c.clientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Get(ctx, pvc.Name, metav1.GetOptions{})
+	if err == nil {
+		return false, nil
+	}
+	if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get %s PVC %s: %w", pvc.Labels["component"], pvc.Name, err)
+	}
+
+	if _, err := c.clientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(ctx, pvc, metav1.CreateOptions{}); err != nil {
+		return false, fmt.Errorf("failed to create %s PVC %s: %w", pvc.Labels["component"], pvc.Name, wrapQuotaError(err, pvc.Namespace))
+	}
+	return true, nil
+}
+
+// RunCacheWarmJob creates the cache warm Job and waits for it to finish
+// Returns the job name.
+func (c *Client) RunCacheWarmJob(ctx context.Context, opts CacheWarmOptions, timeout time.Duration) (string, error) {
+	created, err := c.clientset.BatchV1().Jobs(opts.Namespace).Create(ctx, BuildCacheWarmJob(opts), metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create cache warm job: %w", wrapQuotaError(err, opts.Namespace))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(3 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		job, err := c.clientset.BatchV1().Jobs(opts.Namespace).Get(ctx, created.Name, metav1.GetOptions{})
+		if err != nil {
+			return created.Name, fmt.Errorf("failed to get cache warm job: %w", err)
+		}
+		if job.Status.Succeeded > 0 {
+			return created.Name, nil
+		}
+		if job.Status.Failed > 0 {
+			return created.Name, fmt.Errorf("cache warm job %s failed (kubectl logs job/%s -n %s)", created.Name, created.Name, opts.Namespace)
+		}
+
+		select {
+		case <-ctx.Done():
+			return created.Name, fmt.Errorf("cache warm job %s did not finish within %v: %w", created.Name, timeout, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildCacheWarmJob(t *testing.T) {
+	job := BuildCacheWarmJob(CacheWarmOptions{
+		Namespace: "dev",
+		PVC:       "team-cache",
+		Image:     "kodama:test",
+		Repos:     []string{"https://github.com/user/app.git"},
+		Commands:  []string{"go install golang.org/x/tools/gopls@latest"},
+	})
+
+	if job.Namespace != "dev" {
+		t.Errorf("job namespace = %s, want dev", job.Namespace)
+	}
+
+	podSpec := job.Spec.Template.Spec
+	if podSpec.Volumes[0].PersistentVolumeClaim.ClaimName != "team-cache" {
+		t.Errorf("volume claim = %s, want team-cache", podSpec.Volumes[0].PersistentVolumeClaim.ClaimName)
+	}
+
+	container := podSpec.Containers[0]
+	if container.VolumeMounts[0].MountPath != CacheMountPath {
+		t.Errorf("mount path = %s, want %s", container.VolumeMounts[0].MountPath, CacheMountPath)
+	}
+	if !hasEnv(container.Env, "GOMODCACHE", CacheMountPath+"/go/mod") {
+		t.Errorf("GOMODCACHE not pointed at the cache: %v", container.Env)
+	}
+
+	script := container.Command[2]
+	for _, want := range []string{
+		"git clone --depth 1 'https://github.com/user/app.git'",
+		"go mod download",
+		"npm ci",
+		"go install golang.org/x/tools/gopls@latest",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("warm script missing %q", want)
+		}
+	}
+}
+
+func TestCacheWarmScript_EscapesRepo(t *testing.T) {
+	script := cacheWarmScript([]string{"https://example.com/a'b.git"}, nil)
+	if !strings.Contains(script, `'https://example.com/a'\''b.git'`) {
+		t.Errorf("repo URL not escaped:\n%s", script)
+	}
+}
+
+func TestEnsureCachePVC(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	client := &Client{clientset: fakeClientset}
+	ctx := context.Background()
+
+	opts := CacheWarmOptions{Namespace: "dev", PVC: "team-cache", StorageClass: "nfs"}
+	created, err := client.EnsureCachePVC(ctx, opts)
+	if err != nil {
+		t.Fatalf("EnsureCachePVC() error = %v", err)
+	}
+	if !created {
+		t.Error("EnsureCachePVC() created = false, want true")
+	}
+
+	pvc, err := fakeClientset.CoreV1().PersistentVolumeClaims("dev").Get(ctx, "team-cache", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get PVC: %v", err)
+	}
+	if pvc.Spec.AccessModes[0] != corev1.ReadWriteMany {
+		t.Errorf("access mode = %s, want ReadWriteMany", pvc.Spec.AccessModes[0])
+	}
+	if *pvc.Spec.StorageClassName != "nfs" {
+		t.Errorf("storage class = %s, want nfs", *pvc.Spec.StorageClassName)
+	}
+	if got := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; got.String() != DefaultCacheSize {
+		t.Errorf("size = %s, want %s", got.String(), DefaultCacheSize)
+	}
+
+	// An existing PVC is left untouched
+	created, err = client.EnsureCachePVC(ctx, opts)
+	if err != nil {
+		t.Fatalf("EnsureCachePVC() second run error = %v", err)
+	}
+	if created {
+		t.Error("EnsureCachePVC() created = true for existing PVC")
+	}
+}
+
+func TestEnsureCachePVC_InvalidSize(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	_, err := client.EnsureCachePVC(context.Background(), CacheWarmOptions{Namespace: "dev", PVC: "c", Size: "lots"})
+	if err == nil {
+		t.Error("EnsureCachePVC() expected error for invalid size")
+	}
+}
+
+func TestCreatePod_CachePVC(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{
+		Name:      "kodama-test",
+		Namespace: "dev",
+		Image:     "kodama:test",
+		CachePVC:  "team-cache",
+	}, true)
+	if err != nil {
+		t.Fatalf("CreatePod() error = %v", err)
+	}
+
+	var found bool
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == "team-cache" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("cache PVC volume not found in pod")
+	}
+	if !hasEnv(pod.Spec.Containers[0].Env, "PIP_CACHE_DIR", CacheMountPath+"/pip") {
+		t.Errorf("PIP_CACHE_DIR not set on main container: %v", pod.Spec.Containers[0].Env)
+	}
+}
+
+func hasEnv(env []corev1.EnvVar, name, value string) bool {
+	for _, e := range env {
+		if e.Name == name && e.Value == value {
+			return true
+		}
+	}
+	return false
+}
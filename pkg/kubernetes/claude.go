@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes/initcontainer"
+)
+
+// ClaudeVersion is the Claude Code CLI version installed into session pods
+const ClaudeVersion = "latest"
+
+// ErrClaudeUnavailable is returned when the Claude Code CLI is missing from a pod
+// and could not be reinstalled
+var ErrClaudeUnavailable = errors.New("claude CLI is not available in the pod")
+
+// claudeCheckScript prints "ok" or "missing" and always exits 0, so a failing
+// exec (pod gone, not ready) is not mistaken for a missing binary
+const claudeCheckScript = "if claude --version >/dev/null 2>&1; then echo ok; else echo missing; fi"
+
+// EnsureClaude verifies that 'claude --version' works in the session pod and
+// reinstalls the CLI in place when it does not. The binary lives in an emptyDir,
+// which is emptied when the pod's containers are restarted.
+// It reports whether the CLI was reinstalled.
+func EnsureClaude(ctx context.Context, executor CommandExecutor, namespace, podName string) (bool, error) {
+	healthy, err := claudeHealthy(ctx, executor, namespace, podName)
+	if err != nil || healthy {
+		return false, err
+	}
+
+	installer := initcontainer.NewClaudeInstallerConfig(ClaudeVersion, "")
+	_, stderr, err := executor.ExecInPod(ctx, namespace, podName, []string{"sh", "-c", installer.RepairScript()})
+	if err != nil {
+		return true, fmt.Errorf("%w: reinstall failed: %s", ErrClaudeUnavailable, lastLine(stderr))
+	}
+
+	healthy, err = claudeHealthy(ctx, executor, namespace, podName)
+	if err != nil {
+		return true, err
+	}
+	if !healthy {
+		return true, fmt.Errorf("%w: 'claude --version' still fails after reinstall", ErrClaudeUnavailable)
+	}
+	return true, nil
+}
+
+// claudeHealthy runs the health check script in the pod
+func claudeHealthy(ctx context.Context, executor CommandExecutor, namespace, podName string) (bool, error) {
+	stdout, stderr, err := executor.ExecInPod(ctx, namespace, podName, []string{"sh", "-c", claudeCheckScript})
+	if err != nil {
+		return false, fmt.Errorf("failed to check claude CLI: %s: %w", strings.TrimSpace(stderr), err)
+	}
+	return strings.TrimSpace(stdout) != "missing", nil
+}
+
+// lastLine returns the last non-empty line of command output
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,74 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// claudeTestExecutor answers health checks from a queue and records reinstalls
+type claudeTestExecutor struct {
+	checks     []string
+	checkErr   error
+	repairErr  error
+	reinstalls int
+}
+
+func (e *claudeTestExecutor) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	if command[len(command)-1] == claudeCheckScript {
+		if e.checkErr != nil {
+			return "", "error: unable to upgrade connection", e.checkErr
+		}
+		result := e.checks[0]
+		e.checks = e.checks[1:]
+		return result + "\n", "", nil
+	}
+	e.reinstalls++
+	if e.repairErr != nil {
+		return "", "Installing...\ncurl: (6) Could not resolve host: claude.ai\n", e.repairErr
+	}
+	return "", "", nil
+}
+
+func TestEnsureClaude(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		executor := &claudeTestExecutor{checks: []string{"ok"}}
+		repaired, err := EnsureClaude(context.Background(), executor, "dev", "kodama-work")
+		require.NoError(t, err)
+		assert.False(t, repaired)
+		assert.Zero(t, executor.reinstalls)
+	})
+
+	t.Run("missing is reinstalled", func(t *testing.T) {
+		executor := &claudeTestExecutor{checks: []string{"missing", "ok"}}
+		repaired, err := EnsureClaude(context.Background(), executor, "dev", "kodama-work")
+		require.NoError(t, err)
+		assert.True(t, repaired)
+		assert.Equal(t, 1, executor.reinstalls)
+	})
+
+	t.Run("reinstall fails", func(t *testing.T) {
+		executor := &claudeTestExecutor{checks: []string{"missing"}, repairErr: errors.New("exit status 6")}
+		_, err := EnsureClaude(context.Background(), executor, "dev", "kodama-work")
+		require.ErrorIs(t, err, ErrClaudeUnavailable)
+		assert.True(t, strings.HasSuffix(err.Error(), "Could not resolve host: claude.ai"), err.Error())
+	})
+
+	t.Run("still missing after reinstall", func(t *testing.T) {
+		executor := &claudeTestExecutor{checks: []string{"missing", "missing"}}
+		_, err := EnsureClaude(context.Background(), executor, "dev", "kodama-work")
+		assert.ErrorIs(t, err, ErrClaudeUnavailable)
+	})
+
+	t.Run("pod unreachable is not a missing CLI", func(t *testing.T) {
+		executor := &claudeTestExecutor{checkErr: errors.New("exit status 1")}
+		_, err := EnsureClaude(context.Background(), executor, "dev", "kodama-work")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrClaudeUnavailable)
+		assert.Zero(t, executor.reinstalls)
+	})
+}
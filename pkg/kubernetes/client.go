@@ -5,10 +5,14 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/illumination-k/kodama/internal/plugin"
 )
 
 // NewClient creates a new Kubernetes client
@@ -31,12 +35,33 @@ func NewClient(kubeconfigPath string) (*Client, error) {
 	}, nil
 }
 
+// NewClientForClientset creates a client using clientset, such as a fake
+// clientset in tests
+func NewClientForClientset(clientset kubernetes.Interface) *Client {
+	return &Client{clientset: clientset, config: &Config{}}
+}
+
 // buildConfig creates a Kubernetes REST config from kubeconfig
 func buildConfig(kubeconfigPath string) (*rest.Config, error) {
-	// Try in-cluster config first
-	config, err := rest.InClusterConfig()
-	if err == nil {
-		return config, nil
+	// Token-based access from KODAMA_K8S_* variables (CI jobs) takes precedence
+	if kubeconfigPath == "" {
+		auth, err := TokenAuthFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		if auth != nil {
+			return auth.RESTConfig()
+		}
+	}
+
+	// As a kubectl plugin, follow kubectl: an explicit KUBECONFIG wins over in-cluster config
+	preferKubeconfig := kubeconfigPath == "" && plugin.IsKubectlPlugin() && os.Getenv("KUBECONFIG") != ""
+
+	// Try in-cluster config
+	if !preferKubeconfig {
+		if config, err := rest.InClusterConfig(); err == nil {
+			return config, nil
+		}
 	}
 
 	// Fall back to kubeconfig file
@@ -44,7 +69,7 @@ func buildConfig(kubeconfigPath string) (*rest.Config, error) {
 		kubeconfigPath = getDefaultKubeconfigPath()
 	}
 
-	config, err = clientcmd.BuildConfigFromFlags("", kubeconfigPath)
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfigPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build config from kubeconfig: %w", err)
 	}
@@ -55,7 +80,14 @@ func buildConfig(kubeconfigPath string) (*rest.Config, error) {
 // getDefaultKubeconfigPath returns the default kubeconfig file path
 func getDefaultKubeconfigPath() string {
 	if kubeconfigEnv := os.Getenv("KUBECONFIG"); kubeconfigEnv != "" {
-		return kubeconfigEnv
+		// KUBECONFIG may list several files; use the first that exists
+		paths := filepath.SplitList(kubeconfigEnv)
+		for _, path := range paths {
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+		return paths[0]
 	}
 
 	home, err := os.UserHomeDir()
@@ -95,6 +127,41 @@ func (c *Client) GetCurrentNamespace() (string, error) {
 	return context.Namespace, nil
 }
 
+// CurrentContext returns the current context of the kubeconfig at
+// kubeconfigPath (default: $KUBECONFIG or ~/.kube/config), or "" when there
+// is no readable kubeconfig, e.g. in-cluster
+func CurrentContext(kubeconfigPath string) string {
+	if kubeconfigPath == "" {
+		kubeconfigPath = getDefaultKubeconfigPath()
+	}
+
+	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return ""
+	}
+	return config.CurrentContext
+}
+
+// GetCurrentContext returns the current context of the client's kubeconfig
+func (c *Client) GetCurrentContext() string {
+	return CurrentContext(c.config.KubeconfigPath)
+}
+
+// ListNamespaces returns the names of the namespaces in the cluster, sorted
+func (c *Client) ListNamespaces(ctx context.Context) ([]string, error) {
+	list, err := c.clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	names := make([]string, 0, len(list.Items))
+	for _, ns := range list.Items {
+		names = append(names, ns.Name)
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
 // Ping verifies connectivity to the Kubernetes cluster
 func (c *Client) Ping(ctx context.Context) error {
 	_, err := c.clientset.Discovery().ServerVersion()
@@ -2,6 +2,8 @@ package kubernetes
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,3 +34,30 @@ func TestClient_Ping(t *testing.T) {
 	err = client.Ping(ctx)
 	assert.NoError(t, err)
 }
+
+func TestGetDefaultKubeconfigPath_List(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "config")
+	assert.NoError(t, os.WriteFile(existing, []byte{}, 0o600))
+	missing := filepath.Join(dir, "missing")
+
+	t.Setenv("KUBECONFIG", missing+string(filepath.ListSeparator)+existing)
+	assert.Equal(t, existing, getDefaultKubeconfigPath())
+
+	t.Setenv("KUBECONFIG", missing)
+	assert.Equal(t, missing, getDefaultKubeconfigPath())
+}
+
+func TestCurrentContext(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	assert.NoError(t, os.WriteFile(path, []byte(`apiVersion: v1
+kind: Config
+current-context: prod-eu
+contexts:
+- name: prod-eu
+  context: {cluster: prod, user: admin}
+`), 0o600))
+
+	assert.Equal(t, "prod-eu", CurrentContext(path))
+	assert.Empty(t, CurrentContext(filepath.Join(t.TempDir(), "missing")))
+}
@@ -0,0 +1,54 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MainContainerName is the session container that runs the shell, agent and ttyd
+const MainContainerName = "claude-code"
+
+// ErrContainerNotFound is returned by ValidateContainer when the pod has no such container
+var ErrContainerNotFound = errors.New("container not found")
+
+// ValidateContainer checks that a container exists in the live pod spec
+// Sidecar init containers (restartPolicy: Always) keep running and are always
+// accepted; other init containers only when includeInit is set, e.g. for logs.
+func (c *Client) ValidateContainer(ctx context.Context, podName, namespace, container string, includeInit bool) error {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s in namespace %s", ErrPodNotFound, podName, namespace)
+		}
+		return fmt.Errorf("failed to get pod %s: %w", podName, err)
+	}
+
+	names := podContainerNames(pod, includeInit)
+	for _, name := range names {
+		if name == container {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q in pod %s (available: %s)", ErrContainerNotFound, container, podName, strings.Join(names, ", "))
+}
+
+// podContainerNames lists the containers of a pod, main containers first
+func podContainerNames(pod *corev1.Pod, includeInit bool) []string {
+	names := make([]string, 0, len(pod.Spec.Containers)+len(pod.Spec.InitContainers))
+	for _, container := range pod.Spec.Containers {
+		names = append(names, container.Name)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		sidecar := container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+		if includeInit || sidecar {
+			names = append(names, container.Name)
+		}
+	}
+	return names
+}
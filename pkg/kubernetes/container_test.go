@@ -0,0 +1,56 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestValidateContainer(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-test", Namespace: "dev"},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{
+				{Name: "workspace-initializer"},
+				{Name: "diff-viewer", RestartPolicy: &always},
+			},
+			Containers: []corev1.Container{{Name: MainContainerName}},
+		},
+	}
+	client := &Client{clientset: fake.NewSimpleClientset(pod)}
+
+	tests := []struct {
+		name        string
+		podName     string
+		container   string
+		includeInit bool
+		wantErr     error
+	}{
+		{name: "main container", podName: "kodama-test", container: MainContainerName},
+		{name: "sidecar", podName: "kodama-test", container: "diff-viewer"},
+		{name: "init container excluded", podName: "kodama-test", container: "workspace-initializer", wantErr: ErrContainerNotFound},
+		{name: "init container included", podName: "kodama-test", container: "workspace-initializer", includeInit: true},
+		{name: "unknown container", podName: "kodama-test", container: "nope", wantErr: ErrContainerNotFound},
+		{name: "missing pod", podName: "missing", container: MainContainerName, wantErr: ErrPodNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := client.ValidateContainer(context.Background(), tt.podName, "dev", tt.container, tt.includeInit)
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("ValidateContainer() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateContainer() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package kubernetes
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DefaultCrashLogLines is how many log lines of a crashed main container are shown
+const DefaultCrashLogLines = 20
+
+// ContainerCrash describes the last termination of a pod's main container
+type ContainerCrash struct {
+	Reason       string // e.g. Error, OOMKilled, Completed
+	ExitCode     int32
+	Signal       int32
+	Message      string
+	FinishedAt   time.Time
+	RestartCount int32
+	Waiting      string   // Why the container waits to restart, e.g. CrashLoopBackOff
+	Restarted    bool     // The container was restarted after the termination
+	LogTail      []string // Last log lines of the terminated container
+}
+
+// mainContainerCrash returns the last termination of the main container, or
+// nil if it has not terminated: its current state when it stays terminated
+// (restartPolicy Never), or the last state once it was restarted
+func mainContainerCrash(pod *corev1.Pod) *ContainerCrash {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != MainContainerName {
+			continue
+		}
+
+		terminated, restarted := status.State.Terminated, false
+		if terminated == nil {
+			terminated, restarted = status.LastTerminationState.Terminated, true
+		}
+		if terminated == nil {
+			return nil
+		}
+
+		crash := &ContainerCrash{
+			Reason:       terminated.Reason,
+			ExitCode:     terminated.ExitCode,
+			Signal:       terminated.Signal,
+			Message:      terminated.Message,
+			FinishedAt:   terminated.FinishedAt.Time,
+			RestartCount: status.RestartCount,
+			Restarted:    restarted,
+		}
+		if waiting := status.State.Waiting; waiting != nil {
+			crash.Waiting = waiting.Reason
+		}
+		return crash
+	}
+	return nil
+}
+
+// GetContainerCrash returns the last termination of the session pod's main
+// container with its last log lines, or nil if the container never terminated
+// or the pod is gone. Logs of a restarted container come from its previous instance.
+func (c *Client) GetContainerCrash(ctx context.Context, name, namespace string, logLines int64) (*ContainerCrash, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get pod %s in namespace %s: %w", name, namespace, err)
+	}
+
+	crash := mainContainerCrash(pod)
+	if crash == nil || logLines <= 0 {
+		return crash, nil
+	}
+
+	// Logs are best effort: they may already be rotated away
+	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{
+		Container: MainContainerName,
+		Previous:  crash.Restarted,
+		TailLines: &logLines,
+	}).Stream(ctx)
+	if err != nil {
+		return crash, nil
+	}
+	defer func() { _ = stream.Close() }()
+
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		crash.LogTail = append(crash.LogTail, scanner.Text())
+	}
+	return crash, nil
+}
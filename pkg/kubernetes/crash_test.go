@@ -0,0 +1,68 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func crashedPod(status corev1.ContainerStatus) *corev1.Pod {
+	status.Name = MainContainerName
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-test", Namespace: "default"},
+		Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: "code-server", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 2}}},
+			status,
+		}},
+	}
+}
+
+func TestMainContainerCrash(t *testing.T) {
+	// Running and never terminated
+	assert.Nil(t, mainContainerCrash(crashedPod(corev1.ContainerStatus{
+		State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+	})))
+
+	// restartPolicy Never: the container stays terminated
+	crash := mainContainerCrash(crashedPod(corev1.ContainerStatus{
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+	}))
+	require.NotNil(t, crash)
+	assert.Equal(t, "Error", crash.Reason)
+	assert.Equal(t, int32(1), crash.ExitCode)
+	assert.False(t, crash.Restarted)
+
+	// OnFailure: the last termination while waiting to restart
+	crash = mainContainerCrash(crashedPod(corev1.ContainerStatus{
+		RestartCount:         3,
+		State:                corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}},
+	}))
+	require.NotNil(t, crash)
+	assert.Equal(t, "OOMKilled", crash.Reason)
+	assert.Equal(t, int32(137), crash.ExitCode)
+	assert.Equal(t, int32(3), crash.RestartCount)
+	assert.Equal(t, "CrashLoopBackOff", crash.Waiting)
+	assert.True(t, crash.Restarted)
+}
+
+func TestGetContainerCrash(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset(crashedPod(corev1.ContainerStatus{
+		State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+	}))}
+
+	crash, err := client.GetContainerCrash(context.Background(), "kodama-test", "default", DefaultCrashLogLines)
+	require.NoError(t, err)
+	require.NotNil(t, crash)
+	assert.Equal(t, []string{"fake logs"}, crash.LogTail)
+
+	// A missing pod has no crash to report
+	crash, err = client.GetContainerCrash(context.Background(), "missing", "default", DefaultCrashLogLines)
+	require.NoError(t, err)
+	assert.Nil(t, crash)
+}
@@ -0,0 +1,87 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes/initcontainer"
+)
+
+// DataFetcherName is the init container that copies the dataset into the workspace
+const DataFetcherName = "data-fetcher"
+
+// dataCredentialsVolumeName is the pod volume with the data fetcher's credentials files
+const dataCredentialsVolumeName = "kodama-data-credentials"
+
+// dataFetcherConfig returns the data fetcher configuration for spec
+func dataFetcherConfig(spec *PodSpec) *initcontainer.DataFetcherConfig {
+	config := initcontainer.NewDataFetcherConfig(spec.DataURI, spec.DataDestination).
+		WithCredentials(spec.DataCredentialsSecret).
+		WithImage(spec.DataImage)
+	config.CredentialsVolumeName = dataCredentialsVolumeName
+	return config
+}
+
+// dataCredentialsVolume returns the credentials Secret volume for gs:// and rclone
+// URIs; s3:// credentials are passed as environment variables instead
+func dataCredentialsVolume(spec *PodSpec) (corev1.Volume, bool) {
+	if spec.DataURI == "" || !dataFetcherConfig(spec).MountsCredentials() {
+		return corev1.Volume{}, false
+	}
+	mode := int32(0o400)
+	return corev1.Volume{
+		Name: dataCredentialsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  spec.DataCredentialsSecret,
+				DefaultMode: &mode,
+			},
+		},
+	}, true
+}
+
+// InitProgressFunc receives the latest output line of an init container while it runs
+type InitProgressFunc func(container, line string)
+
+type initProgressKey struct{}
+
+// initProgressInterval is how often WaitForPodReady reads the data fetcher's output
+const initProgressInterval = 5 * time.Second
+
+// WithInitProgress makes WaitForPodReady pass the output of the data fetcher
+// to fn while it runs, so long dataset downloads show progress
+func WithInitProgress(ctx context.Context, fn InitProgressFunc) context.Context {
+	return context.WithValue(ctx, initProgressKey{}, fn)
+}
+
+// initProgressFor returns the progress callback of ctx, or nil
+func initProgressFor(ctx context.Context) InitProgressFunc {
+	fn, _ := ctx.Value(initProgressKey{}).(InitProgressFunc)
+	return fn
+}
+
+// dataFetcherRunning reports whether the data fetcher of pod is running
+func dataFetcherRunning(pod *corev1.Pod) bool {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name == DataFetcherName {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
+
+// lastLogLine returns the last line a container has written, or "" if it cannot be read
+func (c *Client) lastLogLine(ctx context.Context, name, namespace, container string) string {
+	tailLines := int64(1)
+	raw, err := c.clientset.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{
+		Container: container,
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreatePod_DataFetcher(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	spec := &PodSpec{
+		Name: "kodama-test", Namespace: "dev", Image: "kodama:test",
+		GitRepo:               "https://github.com/org/repo",
+		DataURI:               "gs://bucket/datasets/",
+		DataDestination:       "/workspace/data",
+		DataCredentialsSecret: "gcp-key",
+	}
+
+	pod, err := client.CreatePod(context.Background(), spec, true)
+	require.NoError(t, err)
+
+	var names []string
+	for _, container := range pod.Spec.InitContainers {
+		names = append(names, container.Name)
+	}
+	assert.Equal(t, []string{ToolsInstallerName, workspaceInitializerName, DataFetcherName}, names, "the fetch runs after the clone")
+
+	var volume *corev1.Volume
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].Name == dataCredentialsVolumeName {
+			volume = &pod.Spec.Volumes[i]
+		}
+	}
+	require.NotNil(t, volume)
+	assert.Equal(t, "gcp-key", volume.Secret.SecretName)
+
+	// s3 credentials come from the environment, so there is no volume
+	spec.DataURI = "s3://bucket/datasets/"
+	pod, err = client.CreatePod(context.Background(), spec, true)
+	require.NoError(t, err)
+	for _, v := range pod.Spec.Volumes {
+		assert.NotEqual(t, dataCredentialsVolumeName, v.Name)
+	}
+}
+
+func TestDataFetcherProgress(t *testing.T) {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-test", Namespace: "dev"},
+		Status: corev1.PodStatus{InitContainerStatuses: []corev1.ContainerStatus{
+			{Name: ToolsInstallerName, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+			{Name: DataFetcherName, State: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}},
+		}},
+	}
+	assert.True(t, dataFetcherRunning(pod))
+	assert.False(t, dataFetcherRunning(&corev1.Pod{}))
+
+	client := &Client{clientset: fake.NewSimpleClientset(pod)}
+	assert.Equal(t, "fake logs", client.lastLogLine(context.Background(), "kodama-test", "dev", DataFetcherName))
+
+	var got string
+	ctx := WithInitProgress(context.Background(), func(container, line string) { got = container + ": " + line })
+	initProgressFor(ctx)(DataFetcherName, "Fetched 1.2G")
+	assert.Equal(t, "data-fetcher: Fetched 1.2G", got)
+	assert.Nil(t, initProgressFor(context.Background()))
+}
@@ -0,0 +1,80 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// SafeToEvictAnnotation tells the cluster autoscaler whether it may evict a
+// pod to scale down its node
+const SafeToEvictAnnotation = "cluster-autoscaler.kubernetes.io/safe-to-evict"
+
+// applyNoEvict marks the pod as not safe to evict for the cluster autoscaler
+func applyNoEvict(pod *corev1.Pod, spec *PodSpec) {
+	if !spec.NoEvict {
+		return
+	}
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[SafeToEvictAnnotation] = "false"
+}
+
+// CreatePodDisruptionBudget creates a PodDisruptionBudget, named like the pod,
+// that blocks voluntary evictions of a session pod such as node drains and
+// autoscaler scale-downs, with the session labels added. An existing budget
+// from an earlier attempt is kept.
+// If dryRun is true, returns the manifest without creating it.
+func (c *Client) CreatePodDisruptionBudget(ctx context.Context, namespace, podName string, labels map[string]string, dryRun bool) (*policyv1.PodDisruptionBudget, error) {
+	maxUnavailable := intstr.FromInt32(0)
+	pdb := &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "policy/v1",
+			Kind:       "PodDisruptionBudget",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podName,
+			Namespace: namespace,
+			Labels: withSessionLabels(map[string]string{
+				"app":        "kodama",
+				"session":    podName,
+				"managed-by": "kodama",
+			}, labels),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MaxUnavailable: &maxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "kodama", "session": podName},
+			},
+		},
+	}
+
+	if dryRun {
+		return pdb, nil
+	}
+
+	created, err := c.clientset.PolicyV1().PodDisruptionBudgets(namespace).Create(ctx, pdb, metav1.CreateOptions{})
+	if err != nil {
+		if errors.IsAlreadyExists(err) {
+			return pdb, nil
+		}
+		return nil, fmt.Errorf("failed to create pod disruption budget: %w", err)
+	}
+	return created, nil
+}
+
+// DeletePodDisruptionBudget deletes the PodDisruptionBudget of a session pod
+// Ignores "not found" errors.
+func (c *Client) DeletePodDisruptionBudget(ctx context.Context, namespace, podName string) error {
+	err := c.clientset.PolicyV1().PodDisruptionBudgets(namespace).Delete(ctx, podName, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete pod disruption budget: %w", err)
+	}
+	return nil
+}
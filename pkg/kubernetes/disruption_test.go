@@ -0,0 +1,48 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreatePod_NoEvict(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{
+		Name: "kodama-work", Namespace: "dev", Image: "kodama:test", NoEvict: true,
+	}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "false", pod.Annotations[SafeToEvictAnnotation])
+
+	pod, err = client.CreatePod(context.Background(), &PodSpec{
+		Name: "kodama-work", Namespace: "dev", Image: "kodama:test",
+	}, true)
+	require.NoError(t, err)
+	assert.NotContains(t, pod.Annotations, SafeToEvictAnnotation)
+}
+
+func TestPodDisruptionBudget(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pdb, err := client.CreatePodDisruptionBudget(ctx, "dev", "kodama-work", nil, false)
+	require.NoError(t, err)
+	assert.Equal(t, "kodama-work", pdb.Name)
+	assert.Equal(t, int32(0), pdb.Spec.MaxUnavailable.IntVal)
+	assert.Equal(t, map[string]string{"app": "kodama", "session": "kodama-work"}, pdb.Spec.Selector.MatchLabels)
+
+	// A resumed start finds the budget of the earlier attempt
+	_, err = client.CreatePodDisruptionBudget(ctx, "dev", "kodama-work", nil, false)
+	require.NoError(t, err)
+
+	require.NoError(t, client.DeletePodDisruptionBudget(ctx, "dev", "kodama-work"))
+	list, err := client.clientset.PolicyV1().PodDisruptionBudgets("dev").List(ctx, metav1.ListOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, list.Items)
+	assert.NoError(t, client.DeletePodDisruptionBudget(ctx, "dev", "kodama-work"), "a missing budget is not an error")
+}
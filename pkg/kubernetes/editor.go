@@ -0,0 +1,148 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"path"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// EditorConfigHome is where editor config files are mounted in the main container
+	EditorConfigHome = "/root/.config"
+
+	// CodeServerContainerName is the optional browser-based VS Code sidecar
+	CodeServerContainerName = "code-server"
+
+	DefaultCodeServerImage = "codercom/code-server:latest"
+	DefaultCodeServerPort  = 8080
+
+	editorConfigVolumeName = "editor-config"
+
+	defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+)
+
+// EditorConfigKey encodes a config file path (e.g. helix/config.toml) as a
+// ConfigMap key, which may not contain '/' or '='
+func EditorConfigKey(relPath string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(relPath))
+}
+
+// CreateEditorConfigMap creates a ConfigMap holding editor config files keyed by
+// their path relative to the config directory. Original paths are stored in
+// annotations, as for file secrets. Session labels are added.
+// If dryRun is true, returns the manifest without creating it
+func (c *Client) CreateEditorConfigMap(ctx context.Context, name, namespace string, files, labels map[string]string, dryRun bool) (*corev1.ConfigMap, error) {
+	data := make(map[string]string, len(files))
+	annotations := make(map[string]string, len(files))
+	for relPath, content := range files {
+		key := EditorConfigKey(relPath)
+		data[key] = content
+		annotations["path-"+key] = relPath
+	}
+
+	// Extract session name from ConfigMap name (format: kodama-editor-<session-name>)
+	sessionName := ""
+	if len(name) > len("kodama-editor-") {
+		sessionName = name[len("kodama-editor-"):]
+	}
+
+	configMap := &corev1.ConfigMap{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "v1",
+			Kind:       "ConfigMap",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: withSessionLabels(map[string]string{
+				"app":        "kodama",
+				"session":    sessionName,
+				"managed-by": "kodama",
+			}, labels),
+			Annotations: annotations,
+		},
+		Data: data,
+	}
+
+	if dryRun {
+		return configMap, nil
+	}
+
+	if _, err := c.clientset.CoreV1().ConfigMaps(namespace).Create(ctx, configMap, metav1.CreateOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to create editor config map: %w", wrapQuotaError(err, namespace))
+	}
+
+	return configMap, nil
+}
+
+// DeleteConfigMap deletes a ConfigMap
+// Ignores "not found" errors (ConfigMap already deleted)
+func (c *Client) DeleteConfigMap(ctx context.Context, name, namespace string) error {
+	err := c.clientset.CoreV1().ConfigMaps(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete config map: %w", err)
+	}
+	return nil
+}
+
+// editorConfigVolume mounts each editor config file at EditorConfigHome/<path>
+// using subPath, so that other files in ~/.config are left untouched
+func editorConfigVolume(configMapName string, files []string) (corev1.Volume, []corev1.VolumeMount) {
+	volume := corev1.Volume{
+		Name: editorConfigVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: configMapName},
+			},
+		},
+	}
+
+	mounts := make([]corev1.VolumeMount, 0, len(files))
+	for _, relPath := range files {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      editorConfigVolumeName,
+			MountPath: path.Join(EditorConfigHome, relPath),
+			SubPath:   EditorConfigKey(relPath),
+			ReadOnly:  true,
+		})
+	}
+	return volume, mounts
+}
+
+// codeServerContainer builds the code-server sidecar serving /workspace without
+// authentication; it is only reachable through port-forward
+func codeServerContainer(image string, port int) (corev1.Container, error) {
+	if image == "" {
+		image = DefaultCodeServerImage
+	}
+	if port == 0 {
+		port = DefaultCodeServerPort
+	}
+	if port < 1 || port > 65535 {
+		return corev1.Container{}, fmt.Errorf("invalid code-server port: %d (must be between 1 and 65535)", port)
+	}
+
+	return corev1.Container{
+		Name:  CodeServerContainerName,
+		Image: image,
+		Args:  []string{"--bind-addr", fmt.Sprintf("0.0.0.0:%d", port), "--auth", "none", "/workspace"},
+		Ports: []corev1.ContainerPort{
+			{
+				Name:          "code-server",
+				ContainerPort: int32(port), //#nosec G115 -- port validated to be in valid range
+				Protocol:      corev1.ProtocolTCP,
+			},
+		},
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "workspace",
+				MountPath: "/workspace",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEditorConfigKey(t *testing.T) {
+	key := EditorConfigKey("helix/config.toml")
+
+	assert.Regexp(t, `^[-._a-zA-Z0-9]+$`, key)
+	assert.NotEqual(t, EditorConfigKey("helix.config/toml"), key)
+}
+
+func TestCreateEditorConfigMap(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	client := &Client{clientset: clientset}
+
+	_, err := client.CreateEditorConfigMap(context.Background(), "kodama-editor-work", "dev", map[string]string{
+		"helix/config.toml": "theme = \"onedark\"\n",
+	}, map[string]string{"team": "payments", "app": "other"}, false)
+	require.NoError(t, err)
+
+	cm, err := clientset.CoreV1().ConfigMaps("dev").Get(context.Background(), "kodama-editor-work", metav1.GetOptions{})
+	require.NoError(t, err)
+
+	key := EditorConfigKey("helix/config.toml")
+	assert.Equal(t, "theme = \"onedark\"\n", cm.Data[key])
+	assert.Equal(t, "helix/config.toml", cm.Annotations["path-"+key])
+	assert.Equal(t, "work", cm.Labels["session"])
+	assert.Equal(t, "payments", cm.Labels["team"], "session labels are added")
+	assert.Equal(t, "kodama", cm.Labels["app"], "reserved labels are kept")
+
+	require.NoError(t, client.DeleteConfigMap(context.Background(), "kodama-editor-work", "dev"))
+	assert.NoError(t, client.DeleteConfigMap(context.Background(), "kodama-editor-work", "dev"), "not found is ignored")
+}
+
+func TestCreatePod_Editor(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{
+		Name:              "kodama-work",
+		Namespace:         "dev",
+		Image:             "kodama:test",
+		Editor:            "hx",
+		EditorConfigMap:   "kodama-editor-work",
+		EditorConfigFiles: []string{"helix/config.toml", "zellij/config.kdl"},
+		CodeServerEnabled: true,
+	}, true)
+	require.NoError(t, err)
+
+	main := pod.Spec.Containers[0]
+	assert.Contains(t, main.Env, corev1.EnvVar{Name: "EDITOR", Value: "hx"})
+	assert.Contains(t, main.Env, corev1.EnvVar{Name: "VISUAL", Value: "hx"})
+	assert.Contains(t, main.VolumeMounts, corev1.VolumeMount{
+		Name:      editorConfigVolumeName,
+		MountPath: "/root/.config/helix/config.toml",
+		SubPath:   EditorConfigKey("helix/config.toml"),
+		ReadOnly:  true,
+	})
+	assert.Contains(t, main.VolumeMounts, corev1.VolumeMount{
+		Name:      editorConfigVolumeName,
+		MountPath: "/root/.config/zellij/config.kdl",
+		SubPath:   EditorConfigKey("zellij/config.kdl"),
+		ReadOnly:  true,
+	})
+
+	require.Len(t, pod.Spec.Containers, 2)
+	assert.Equal(t, MainContainerName, pod.Annotations[defaultContainerAnnotation])
+	sidecar := pod.Spec.Containers[1]
+	assert.Equal(t, CodeServerContainerName, sidecar.Name)
+	assert.Equal(t, DefaultCodeServerImage, sidecar.Image)
+	assert.Equal(t, int32(DefaultCodeServerPort), sidecar.Ports[0].ContainerPort)
+	assert.Equal(t, "/workspace", sidecar.VolumeMounts[0].MountPath)
+
+	_, err = client.CreatePod(context.Background(), &PodSpec{
+		Name:              "kodama-work",
+		Namespace:         "dev",
+		Image:             "kodama:test",
+		CodeServerEnabled: true,
+		CodeServerPort:    70000,
+	}, true)
+	assert.Error(t, err)
+}
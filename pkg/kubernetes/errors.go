@@ -0,0 +1,127 @@
+package kubernetes
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+var (
+	// ErrPodNotFound is returned by GetPod when the pod does not exist
+	ErrPodNotFound = errors.New("pod not found")
+
+	// ErrPodNotReady is returned when a pod failed or did not become ready in time
+	ErrPodNotReady = errors.New("pod not ready")
+
+	// ErrQuotaExceeded is returned when a ResourceQuota rejects a create request
+	ErrQuotaExceeded = errors.New("resource quota exceeded")
+)
+
+// HintError adds a troubleshooting hint to an error
+type HintError struct {
+	Err  error
+	Hint string
+}
+
+func (e *HintError) Error() string { return e.Err.Error() }
+
+func (e *HintError) Unwrap() error { return e.Err }
+
+// ErrCloneFailed is returned when the workspace-initializer init container fails
+// Stage is the step of the init script that failed (install-git, clone, branch).
+type ErrCloneFailed struct {
+	Stage    string
+	Remote   string // Remote of the last clone attempt: origin or mirror
+	Attempts int    // Attempts made before giving up (0 = unknown)
+	Hint     string
+}
+
+func (e *ErrCloneFailed) Error() string {
+	msg := fmt.Sprintf("repository setup failed during %s", e.Stage)
+	if e.Attempts > 0 {
+		msg += fmt.Sprintf(" after %d attempts", e.Attempts)
+	}
+	if e.Remote == "mirror" {
+		msg += " (mirror fallback also failed)"
+	}
+	return msg
+}
+
+// workspaceInitializerName is the init container that clones the repository
+const workspaceInitializerName = "workspace-initializer"
+
+// Clone stages written to the termination log by the workspace-initializer
+const (
+	CloneStageInstallGit = "install-git"
+	CloneStageClone      = "clone"
+	CloneStageBranch     = "branch"
+)
+
+// newCloneFailedError builds an ErrCloneFailed from the init container's termination state
+// The message is "stage=<stage> [remote=<remote>] [attempts=<n>]", or just the stage
+// for pods created by older versions.
+func newCloneFailedError(podName, namespace string, state *corev1.ContainerStateTerminated) *ErrCloneFailed {
+	cloneErr := &ErrCloneFailed{}
+	for _, field := range strings.Fields(state.Message) {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			cloneErr.Stage = field
+			continue
+		}
+		switch key {
+		case "stage":
+			cloneErr.Stage = value
+		case "remote":
+			cloneErr.Remote = value
+		case "attempts":
+			cloneErr.Attempts, _ = strconv.Atoi(value)
+		}
+	}
+	if cloneErr.Stage == "" {
+		cloneErr.Stage = CloneStageClone
+	}
+	stage := cloneErr.Stage
+
+	var hint string
+	switch stage {
+	case CloneStageInstallGit:
+		hint = "The init container could not install git with apt-get. Check that the node can reach the Ubuntu package mirrors."
+	case CloneStageBranch:
+		hint = "The repository was cloned but the feature branch could not be created. Check the --branch name."
+	default:
+		hint = "Check the repository URL and that the cluster can reach it. Private HTTPS repositories need GH_TOKEN (e.g. via --env-file); also check --git-clone-args."
+	}
+	if cloneErr.Remote == "mirror" {
+		hint += " Also check that gitClone.mirror is reachable."
+	}
+	hint += fmt.Sprintf("\nLogs: kubectl logs %s -c %s -n %s", podName, workspaceInitializerName, namespace)
+
+	cloneErr.Hint = hint
+	return cloneErr
+}
+
+// podNotReadyError wraps ErrPodNotReady with commands to inspect the pod
+func NewPodNotReadyError(podName, namespace, detail string) error {
+	return &HintError{
+		Err: fmt.Errorf("%w: %s %s", ErrPodNotReady, podName, detail),
+		Hint: fmt.Sprintf("Inspect the pod:\n  kubectl describe pod %s -n %s\n  kubectl logs %s -n %s --all-containers",
+			podName, namespace, podName, namespace),
+	}
+}
+
+// wrapQuotaError classifies ResourceQuota rejections from the API server
+// Other errors are returned unchanged.
+func wrapQuotaError(err error, namespace string) error {
+	if !apierrors.IsForbidden(err) || !strings.Contains(err.Error(), "exceeded quota") {
+		return err
+	}
+	return &HintError{
+		Err: fmt.Errorf("%w: %w", ErrQuotaExceeded, err),
+		Hint: fmt.Sprintf("Lower --cpu/--memory, delete unused sessions (kubectl kodama list), or ask for a larger quota:\n  kubectl kodama quota -n %s\n  kubectl describe resourcequota -n %s",
+			namespace, namespace),
+	}
+}
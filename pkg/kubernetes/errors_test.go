@@ -0,0 +1,124 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func failedPod(initStatuses ...corev1.ContainerStatus) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-test", Namespace: "default"},
+		Status: corev1.PodStatus{
+			Phase:                 corev1.PodFailed,
+			Message:               "init container failed",
+			InitContainerStatuses: initStatuses,
+		},
+	}
+}
+
+func terminatedStatus(name string, exitCode int32, message string) corev1.ContainerStatus {
+	return corev1.ContainerStatus{
+		Name: name,
+		State: corev1.ContainerState{
+			Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode, Message: message},
+		},
+	}
+}
+
+func TestPodFailedError_CloneStage(t *testing.T) {
+	tests := []struct {
+		message string
+		stage   string
+	}{
+		{"clone\n", CloneStageClone},
+		{"branch\n", CloneStageBranch},
+		{"install-git\n", CloneStageInstallGit},
+		{"", CloneStageClone},
+	}
+
+	for _, tt := range tests {
+		err := podFailedError(failedPod(terminatedStatus(workspaceInitializerName, 128, tt.message)))
+
+		var cloneErr *ErrCloneFailed
+		if !errors.As(err, &cloneErr) {
+			t.Fatalf("expected ErrCloneFailed for message %q, got %v", tt.message, err)
+		}
+		if cloneErr.Stage != tt.stage {
+			t.Errorf("Stage = %q, want %q", cloneErr.Stage, tt.stage)
+		}
+		if !strings.Contains(cloneErr.Hint, "kubectl logs kodama-test -c workspace-initializer -n default") {
+			t.Errorf("hint should point at init container logs, got %q", cloneErr.Hint)
+		}
+	}
+}
+
+func TestPodFailedError_CloneDetails(t *testing.T) {
+	err := podFailedError(failedPod(terminatedStatus(workspaceInitializerName, 128, "stage=clone remote=mirror attempts=3\n")))
+
+	var cloneErr *ErrCloneFailed
+	if !errors.As(err, &cloneErr) {
+		t.Fatalf("expected ErrCloneFailed, got %v", err)
+	}
+	if cloneErr.Stage != CloneStageClone || cloneErr.Remote != "mirror" || cloneErr.Attempts != 3 {
+		t.Errorf("unexpected details: %+v", cloneErr)
+	}
+	if want := "repository setup failed during clone after 3 attempts (mirror fallback also failed)"; cloneErr.Error() != want {
+		t.Errorf("Error() = %q, want %q", cloneErr.Error(), want)
+	}
+	if !strings.Contains(cloneErr.Hint, "gitClone.mirror") {
+		t.Errorf("hint should mention the mirror, got %q", cloneErr.Hint)
+	}
+}
+
+func TestPodFailedError_NotReady(t *testing.T) {
+	// A failing tools installer is not a clone failure
+	err := podFailedError(failedPod(
+		terminatedStatus(workspaceInitializerName, 0, ""),
+		terminatedStatus("tools-installer", 1, ""),
+	))
+
+	if !errors.Is(err, ErrPodNotReady) {
+		t.Fatalf("expected ErrPodNotReady, got %v", err)
+	}
+	var hintErr *HintError
+	if !errors.As(err, &hintErr) || !strings.Contains(hintErr.Hint, "kubectl describe pod kodama-test -n default") {
+		t.Errorf("expected describe hint, got %v", err)
+	}
+}
+
+func TestCreatePod_QuotaExceeded(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "kodama-test",
+			errors.New("exceeded quota: compute, requested: limits.cpu=2, used: limits.cpu=8, limited: limits.cpu=8"))
+	})
+	client := &Client{clientset: clientset}
+
+	_, err := client.CreatePod(context.Background(), &PodSpec{Name: "kodama-test", Namespace: "default", Image: "ubuntu"}, false)
+
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected ErrQuotaExceeded, got %v", err)
+	}
+	var hintErr *HintError
+	if !errors.As(err, &hintErr) || !strings.Contains(hintErr.Hint, "kubectl describe resourcequota -n default") {
+		t.Errorf("expected resourcequota hint, got %v", err)
+	}
+}
+
+func TestWrapQuotaError_OtherForbidden(t *testing.T) {
+	err := apierrors.NewForbidden(schema.GroupResource{Resource: "pods"}, "kodama-test", errors.New("RBAC denied"))
+
+	if got := wrapQuotaError(err, "default"); got != err {
+		t.Errorf("expected error unchanged, got %v", got)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os/exec"
+	"io"
+
+	"github.com/illumination-k/kodama/pkg/procutil"
 )
 
 // CommandExecutor abstracts command execution for testing
@@ -13,30 +15,93 @@ type CommandExecutor interface {
 	ExecInPod(ctx context.Context, namespace, podName string, command []string) (stdout, stderr string, err error)
 }
 
+// InputExecutor is a CommandExecutor that can also feed input to the command's stdin
+type InputExecutor interface {
+	CommandExecutor
+	// ExecInPodWithInput executes a command inside a pod with input as its stdin
+	ExecInPodWithInput(ctx context.Context, namespace, podName string, command []string, input io.Reader) (stdout, stderr string, err error)
+}
+
 // KubectlExecutor implements CommandExecutor using kubectl exec
-type KubectlExecutor struct{}
+type KubectlExecutor struct {
+	// Container runs commands in a container other than the pod's default one
+	Container string
+}
 
 // NewKubectlExecutor creates a new KubectlExecutor
 func NewKubectlExecutor() CommandExecutor {
 	return &KubectlExecutor{}
 }
 
+// NewKubectlContainerExecutor creates a KubectlExecutor that runs commands in container
+func NewKubectlContainerExecutor(container string) CommandExecutor {
+	return &KubectlExecutor{Container: container}
+}
+
 // ExecInPod executes a command inside a Kubernetes pod using kubectl exec
 func (k *KubectlExecutor) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
-	args := []string{"exec", "-n", namespace, podName, "--"}
+	return k.ExecInPodWithInput(ctx, namespace, podName, command, nil)
+}
+
+// ExecInPodWithInput executes a command inside a Kubernetes pod using kubectl
+// exec, attaching stdin when input is not nil
+func (k *KubectlExecutor) ExecInPodWithInput(ctx context.Context, namespace, podName string, command []string, input io.Reader) (string, string, error) {
+	args := []string{"exec", "-n", namespace, podName}
+	if input != nil {
+		args = append(args, "-i")
+	}
+	if k.Container != "" {
+		args = append(args, "-c", k.Container)
+	}
+	args = append(args, "--")
 	args = append(args, command...)
 
 	//#nosec G204 -- kubectl is a known command, args are controlled
-	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd := procutil.CommandContext(ctx, "kubectl", args...)
 
 	var stdout, stderr bytes.Buffer
+	cmd.Stdin = input
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err := cmd.Run()
-	if err != nil {
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return stdout.String(), stderr.String(), ctx.Err()
+		}
 		return stdout.String(), stderr.String(), fmt.Errorf("command failed: %w", err)
 	}
 
 	return stdout.String(), stderr.String(), nil
 }
+
+// Command executor backends
+const (
+	ExecutorKubectl = "kubectl"
+	ExecutorSSH     = "ssh"
+	ExecutorLocal   = "local"
+	ExecutorDocker  = "docker"
+	ExecutorPodman  = "podman"
+)
+
+// ExecutorConfig selects the backend that runs session commands
+type ExecutorConfig struct {
+	Backend  string    // kubectl (default), ssh, local, docker or podman
+	SSH      SSHTarget // Used by the ssh backend
+	LocalDir string    // Workspace directory of the local backend
+}
+
+// NewExecutor creates the CommandExecutor for cfg
+func NewExecutor(cfg ExecutorConfig) (CommandExecutor, error) {
+	switch cfg.Backend {
+	case "", ExecutorKubectl:
+		return NewKubectlExecutor(), nil
+	case ExecutorSSH:
+		return NewSSHExecutor(cfg.SSH), nil
+	case ExecutorLocal:
+		return NewLocalExecutor(cfg.LocalDir), nil
+	case ExecutorDocker, ExecutorPodman:
+		return NewContainerExecutor(cfg.Backend), nil
+	default:
+		return nil, fmt.Errorf("unknown executor backend %q (want kubectl, ssh, local, docker or podman)", cfg.Backend)
+	}
+}
@@ -0,0 +1,54 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// ContainerExecutor implements CommandExecutor with docker or podman exec, for
+// sessions running as local containers
+type ContainerExecutor struct {
+	engine string
+}
+
+// NewContainerExecutor creates a new ContainerExecutor using engine, the
+// docker or podman CLI
+func NewContainerExecutor(engine string) CommandExecutor {
+	return &ContainerExecutor{engine: engine}
+}
+
+// ExecInPod executes a command in the container named podName; namespace is ignored
+func (c *ContainerExecutor) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	return c.ExecInPodWithInput(ctx, namespace, podName, command, nil)
+}
+
+// ExecInPodWithInput executes a command in the container named podName,
+// attaching stdin when input is not nil
+func (c *ContainerExecutor) ExecInPodWithInput(ctx context.Context, namespace, podName string, command []string, input io.Reader) (string, string, error) {
+	args := []string{"exec"}
+	if input != nil {
+		args = append(args, "-i")
+	}
+	args = append(append(args, podName), command...)
+
+	//#nosec G204 -- docker or podman exec with the session's container name
+	cmd := procutil.CommandContext(ctx, c.engine, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdin = input
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return stdout.String(), stderr.String(), ctx.Err()
+		}
+		return stdout.String(), stderr.String(), fmt.Errorf("command failed: %w", err)
+	}
+
+	return stdout.String(), stderr.String(), nil
+}
@@ -0,0 +1,46 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// LocalExecutor implements CommandExecutor by running commands on this
+// machine, for a local dev mode that skips Kubernetes entirely
+type LocalExecutor struct {
+	dir string
+}
+
+// NewLocalExecutor creates a new LocalExecutor running commands in dir, the
+// local workspace (empty: the current directory)
+func NewLocalExecutor(dir string) CommandExecutor {
+	return &LocalExecutor{dir: dir}
+}
+
+// ExecInPod executes a command locally; namespace and podName are ignored
+func (l *LocalExecutor) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	if len(command) == 0 {
+		return "", "", errors.New("command is empty")
+	}
+
+	//#nosec G204 -- runs the same commands the other executors run in the pod
+	cmd := procutil.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Dir = l.dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return stdout.String(), stderr.String(), ctx.Err()
+		}
+		return stdout.String(), stderr.String(), fmt.Errorf("command failed: %w", err)
+	}
+
+	return stdout.String(), stderr.String(), nil
+}
@@ -2,6 +2,7 @@ package kubernetes
 
 import (
 	"context"
+	"io"
 	"strings"
 )
 
@@ -16,6 +17,7 @@ type MockCommand struct {
 	Namespace string
 	PodName   string
 	Command   []string
+	Input     string // Stdin given to ExecInPodWithInput
 }
 
 // MockResponse represents a mock response for a command
@@ -35,12 +37,25 @@ func NewMockExecutor() *MockExecutor {
 
 // ExecInPod records the command and returns a pre-configured response
 func (m *MockExecutor) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	return m.ExecInPodWithInput(ctx, namespace, podName, command, nil)
+}
+
+// ExecInPodWithInput records the command and its input and returns a pre-configured response
+func (m *MockExecutor) ExecInPodWithInput(ctx context.Context, namespace, podName string, command []string, input io.Reader) (string, string, error) {
 	// Record the command
-	m.Commands = append(m.Commands, MockCommand{
+	recorded := MockCommand{
 		Namespace: namespace,
 		PodName:   podName,
 		Command:   command,
-	})
+	}
+	if input != nil {
+		data, err := io.ReadAll(input)
+		if err != nil {
+			return "", "", err
+		}
+		recorded.Input = string(data)
+	}
+	m.Commands = append(m.Commands, recorded)
 
 	// Find matching response
 	cmdStr := strings.Join(command, " ")
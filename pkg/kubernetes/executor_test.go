@@ -0,0 +1,156 @@
+package kubernetes
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubKubectl puts a kubectl that runs script first on PATH
+func stubKubectl(t *testing.T, script string) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubectl"), []byte("#!/bin/sh\n"+script+"\n"), 0o700)) //#nosec G306 -- test executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestKubectlExecutor_Canceled(t *testing.T) {
+	// The stub's background child holds stdout open, as a hung exec would
+	stubKubectl(t, "sleep 30 & wait")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	began := time.Now()
+	_, _, err := NewKubectlExecutor().ExecInPod(ctx, "dev", "kodama-work", []string{"true"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(began), 5*time.Second)
+
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	_, _, err = NewKubectlExecutor().ExecInPod(cancelled, "dev", "kodama-work", []string{"true"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestKubectlExecutor_Failure(t *testing.T) {
+	stubKubectl(t, `echo "pod not found" >&2; exit 1`)
+
+	_, stderr, err := NewKubectlExecutor().ExecInPod(context.Background(), "dev", "kodama-work", []string{"true"})
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, context.Canceled)
+	assert.Contains(t, err.Error(), "command failed")
+	assert.Equal(t, "pod not found\n", stderr)
+}
+
+func TestLocalExecutor(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	executor := NewLocalExecutor(dir)
+
+	stdout, stderr, err := executor.ExecInPod(context.Background(), "ignored", "ignored", []string{"sh", "-c", "pwd; echo warn >&2"})
+	require.NoError(t, err)
+	resolved, err := filepath.EvalSymlinks(dir)
+	require.NoError(t, err)
+	assert.Equal(t, resolved+"\n", stdout)
+	assert.Equal(t, "warn\n", stderr)
+
+	_, _, err = executor.ExecInPod(context.Background(), "", "", []string{"sh", "-c", "exit 3"})
+	assert.ErrorContains(t, err, "command failed")
+
+	_, _, err = executor.ExecInPod(context.Background(), "", "", nil)
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = executor.ExecInPod(ctx, "", "", []string{"sh", "-c", "sleep 30"})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestSSHExecutor_Args(t *testing.T) {
+	executor := &SSHExecutor{target: SSHTarget{
+		User: "dev", Port: 2222, IdentityFile: "/keys/id", ConfigFile: "/ssh/config",
+		Options: []string{"StrictHostKeyChecking=accept-new"},
+	}}
+
+	assert.Equal(t, []string{
+		"-o", "BatchMode=yes", "-F", "/ssh/config", "-p", "2222", "-i", "/keys/id",
+		"-o", "StrictHostKeyChecking=accept-new", "--", "dev@vm-1", `'sh' '-c' 'echo "$1"' 'sh' 'it'\''s'`,
+	}, executor.args("vm-1", []string{"sh", "-c", `echo "$1"`, "sh", "it's"}))
+
+	executor = &SSHExecutor{target: SSHTarget{Host: "builder"}}
+	assert.Equal(t, []string{"-o", "BatchMode=yes", "--", "builder", "'true'"}, executor.args("vm-1", []string{"true"}))
+}
+
+// TestSSHExecutor_RemoteShell runs the command through a stub ssh that hands
+// its last argument to sh, as sshd does with the user's shell
+func TestSSHExecutor_RemoteShell(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	stub := "#!/bin/sh\nfor arg; do last=$arg; done\nexec sh -c \"$last\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ssh"), []byte(stub), 0o700)) //#nosec G306 -- test executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	stdout, _, err := NewSSHExecutor(SSHTarget{Host: "vm"}).ExecInPod(context.Background(), "", "",
+		[]string{"sh", "-c", `printf '%s|' "$@"`, "sh", "two words", "it's", "$HOME"})
+	require.NoError(t, err)
+	assert.Equal(t, "two words|it's|$HOME|", stdout)
+}
+
+func TestContainerExecutor(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	stub := "#!/bin/sh\necho \"$@\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "podman"), []byte(stub), 0o700)) //#nosec G306 -- test executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	stdout, _, err := NewContainerExecutor("podman").ExecInPod(context.Background(), "ignored", "kodama-work",
+		[]string{"git", "status"})
+	require.NoError(t, err)
+	assert.Equal(t, "exec kodama-work git status\n", stdout)
+}
+
+func TestExecInPodWithInput(t *testing.T) {
+	stubKubectl(t, `echo "$@"; cat`)
+
+	stdout, _, err := NewKubectlExecutor().(InputExecutor).ExecInPodWithInput(context.Background(), "dev", "kodama-work",
+		[]string{"sh", "-c", "cat > /tmp/patch"}, strings.NewReader("diff --git a/x b/x\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "exec -n dev kodama-work -i -- sh -c cat > /tmp/patch\ndiff --git a/x b/x\n", stdout)
+
+	var _ InputExecutor = &ContainerExecutor{}
+	var _ InputExecutor = NewMockExecutor()
+}
+
+func TestNewExecutor(t *testing.T) {
+	for backend, want := range map[string]CommandExecutor{
+		"":              &KubectlExecutor{},
+		ExecutorKubectl: &KubectlExecutor{},
+		ExecutorSSH:     &SSHExecutor{target: SSHTarget{Host: "vm"}},
+		ExecutorLocal:   &LocalExecutor{dir: "/src"},
+		ExecutorDocker:  &ContainerExecutor{engine: "docker"},
+		ExecutorPodman:  &ContainerExecutor{engine: "podman"},
+	} {
+		got, err := NewExecutor(ExecutorConfig{Backend: backend, SSH: SSHTarget{Host: "vm"}, LocalDir: "/src"})
+		require.NoError(t, err)
+		assert.Equal(t, want, got, backend)
+	}
+
+	_, err := NewExecutor(ExecutorConfig{Backend: "lxc"})
+	assert.Error(t, err)
+}
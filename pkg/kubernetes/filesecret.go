@@ -14,8 +14,26 @@ import (
 // The secret is labeled with app=kodama, session=<name>, and managed-by=kodama for easy management
 // File paths are base64-encoded to meet K8s secret key naming restrictions
 // Original paths are stored in annotations for reconstruction if needed
+// Session labels are added, see withSessionLabels
 // If dryRun is true, returns the manifest without creating it
-func (c *Client) CreateFileSecret(ctx context.Context, name, namespace string, files map[string][]byte, dryRun bool) (*corev1.Secret, error) {
+func (c *Client) CreateFileSecret(ctx context.Context, name, namespace string, files map[string][]byte, labels map[string]string, dryRun bool) (*corev1.Secret, error) {
+	secret := NewFileSecret(name, namespace, files, labels)
+
+	// If dry-run, return the manifest without creating
+	if dryRun {
+		return secret, nil
+	}
+
+	_, err := c.clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// NewFileSecret builds the file secret of a session (see CreateFileSecret)
+func NewFileSecret(name, namespace string, files map[string][]byte, labels map[string]string) *corev1.Secret {
 	// Convert file paths to base64-encoded secret keys
 	secretData := make(map[string][]byte)
 	annotations := make(map[string]string)
@@ -33,30 +51,18 @@ func (c *Client) CreateFileSecret(ctx context.Context, name, namespace string, f
 		sessionName = name[len("kodama-secret-files-"):]
 	}
 
-	secret := &corev1.Secret{
+	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
-			Labels: map[string]string{
+			Labels: withSessionLabels(map[string]string{
 				"app":        "kodama",
 				"session":    sessionName,
 				"managed-by": "kodama",
-			},
+			}, labels),
 			Annotations: annotations,
 		},
 		Data: secretData,
 		Type: corev1.SecretTypeOpaque,
 	}
-
-	// If dry-run, return the manifest without creating
-	if dryRun {
-		return secret, nil
-	}
-
-	_, err := c.clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to create file secret: %w", err)
-	}
-
-	return secret, nil
 }
@@ -0,0 +1,171 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kinds of session resources
+const (
+	KindSecret    = "Secret"
+	KindConfigMap = "ConfigMap"
+)
+
+// sessionResourceSelector selects the Secrets and ConfigMaps kodama creates for sessions
+const sessionResourceSelector = "managed-by=kodama,session"
+
+// SessionResource is a Secret or ConfigMap created for a session
+type SessionResource struct {
+	Kind      string // KindSecret or KindConfigMap
+	Name      string
+	Namespace string
+	Session   string    // Session label, the session name
+	CreatedAt time.Time // Zero for resources that were not listed
+}
+
+// String formats the resource as kind/namespace/name
+func (r SessionResource) String() string {
+	return fmt.Sprintf("%s/%s/%s", r.Kind, r.Namespace, r.Name)
+}
+
+// ListSessionResources lists the Secrets and ConfigMaps labeled with session
+// in namespace. Both are required: an empty one would widen the selection to
+// every session or every namespace.
+func (c *Client) ListSessionResources(ctx context.Context, namespace, session string) ([]SessionResource, error) {
+	if err := requireSessionScope(namespace, session); err != nil {
+		return nil, err
+	}
+	return c.listLabeledResources(ctx, namespace, "managed-by=kodama,session="+session)
+}
+
+// requireSessionScope rejects an empty namespace or session for the
+// operations on a single session's resources
+func requireSessionScope(namespace, session string) error {
+	if namespace == "" || session == "" {
+		return fmt.Errorf("session resources need a namespace and a session name (got namespace %q, session %q)", namespace, session)
+	}
+	return nil
+}
+
+// listLabeledResources lists the Secrets and ConfigMaps matching selector in
+// namespace ("" = all namespaces)
+func (c *Client) listLabeledResources(ctx context.Context, namespace, selector string) ([]SessionResource, error) {
+	opts := metav1.ListOptions{LabelSelector: selector}
+
+	var resources []SessionResource
+	secrets, err := c.clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list secrets: %w", err)
+	}
+	for _, secret := range secrets.Items {
+		resources = append(resources, sessionResource(KindSecret, secret.ObjectMeta))
+	}
+	configMaps, err := c.clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list config maps: %w", err)
+	}
+	for _, configMap := range configMaps.Items {
+		resources = append(resources, sessionResource(KindConfigMap, configMap.ObjectMeta))
+	}
+
+	sort.Slice(resources, func(i, j int) bool { return resources[i].String() < resources[j].String() })
+	return resources, nil
+}
+
+// sessionResource describes a listed Secret or ConfigMap
+func sessionResource(kind string, meta metav1.ObjectMeta) SessionResource {
+	return SessionResource{
+		Kind:      kind,
+		Name:      meta.Name,
+		Namespace: meta.Namespace,
+		Session:   meta.Labels["session"],
+		CreatedAt: meta.CreationTimestamp.Time,
+	}
+}
+
+// DeleteSessionResource deletes a Secret or ConfigMap; one already gone is ignored
+func (c *Client) DeleteSessionResource(ctx context.Context, resource SessionResource) error {
+	switch resource.Kind {
+	case KindSecret:
+		return c.DeleteSecret(ctx, resource.Name, resource.Namespace)
+	case KindConfigMap:
+		return c.DeleteConfigMap(ctx, resource.Name, resource.Namespace)
+	default:
+		return fmt.Errorf("unsupported session resource kind: %s", resource.Kind)
+	}
+}
+
+// DeleteSessionResources deletes the tracked Secrets and ConfigMaps of a
+// session, then sweeps any other labeled with it, such as those left by an
+// interrupted start. It returns what was deleted, and keeps going past
+// failures, returning the first. An empty namespace or session is an error.
+func (c *Client) DeleteSessionResources(ctx context.Context, namespace, session string, tracked []SessionResource) ([]SessionResource, error) {
+	if err := requireSessionScope(namespace, session); err != nil {
+		return nil, err
+	}
+
+	var (
+		deleted  []SessionResource
+		firstErr error
+	)
+	seen := make(map[string]bool)
+	remove := func(resource SessionResource) {
+		if seen[resource.String()] {
+			return
+		}
+		seen[resource.String()] = true
+		if err := c.DeleteSessionResource(ctx, resource); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			return
+		}
+		deleted = append(deleted, resource)
+	}
+
+	for _, resource := range tracked {
+		remove(resource)
+	}
+
+	labeled, err := c.ListSessionResources(ctx, namespace, session)
+	if err != nil && firstErr == nil {
+		firstErr = err
+	}
+	for _, resource := range labeled {
+		remove(resource)
+	}
+	return deleted, firstErr
+}
+
+// ListStaleSessionResources lists the session Secrets and ConfigMaps in
+// namespace ("" = all namespaces) whose session has no pod, created more than
+// minAge ago so that sessions still starting are left alone
+func (c *Client) ListStaleSessionResources(ctx context.Context, namespace string, minAge time.Duration) ([]SessionResource, error) {
+	resources, err := c.listLabeledResources(ctx, namespace, sessionResourceSelector)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: "app=kodama"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	running := make(map[string]bool, len(pods.Items))
+	for _, pod := range pods.Items {
+		running[pod.Namespace+"/"+pod.Name] = true
+	}
+
+	cutoff := time.Now().Add(-minAge)
+	var stale []SessionResource
+	for _, resource := range resources {
+		// Session pods are named kodama-<session>
+		if running[resource.Namespace+"/kodama-"+resource.Session] || resource.CreatedAt.After(cutoff) {
+			continue
+		}
+		stale = append(stale, resource)
+	}
+	return stale, nil
+}
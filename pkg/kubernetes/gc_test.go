@@ -0,0 +1,80 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func sessionObjectMeta(name, session string, age time.Duration) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:              name,
+		Namespace:         "dev",
+		Labels:            map[string]string{"app": "kodama", "session": session, "managed-by": "kodama"},
+		CreationTimestamp: metav1.NewTime(time.Now().Add(-age)),
+	}
+}
+
+func TestDeleteSessionResources(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: sessionObjectMeta("kodama-env-work", "work", time.Hour)},
+		&corev1.ConfigMap{ObjectMeta: sessionObjectMeta("kodama-editor-work", "work", time.Hour)},
+		&corev1.Secret{ObjectMeta: sessionObjectMeta("kodama-env-other", "other", time.Hour)},
+		// Tracked but unlabeled, e.g. created by an older version
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kodama-secret-files-work", Namespace: "dev"}},
+	)}
+
+	tracked := []SessionResource{
+		{Kind: KindSecret, Name: "kodama-secret-files-work", Namespace: "dev"},
+		{Kind: KindSecret, Name: "kodama-env-work", Namespace: "dev"},
+	}
+	deleted, err := client.DeleteSessionResources(context.Background(), "dev", "work", tracked)
+	require.NoError(t, err)
+
+	var names []string
+	for _, resource := range deleted {
+		names = append(names, resource.Name)
+	}
+	assert.ElementsMatch(t, []string{"kodama-secret-files-work", "kodama-env-work", "kodama-editor-work"}, names)
+
+	remaining, err := client.listLabeledResources(context.Background(), "dev", sessionResourceSelector)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "kodama-env-other", remaining[0].Name)
+
+	// A session without a name or namespace never widens to every session
+	for _, scope := range [][2]string{{"dev", ""}, {"", "other"}, {"", ""}} {
+		_, err := client.DeleteSessionResources(context.Background(), scope[0], scope[1], nil)
+		assert.Error(t, err, scope)
+		_, err = client.ListSessionResources(context.Background(), scope[0], scope[1])
+		assert.Error(t, err, scope)
+	}
+	remaining, err = client.listLabeledResources(context.Background(), "dev", sessionResourceSelector)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 1)
+}
+
+func TestListStaleSessionResources(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "kodama-live", Namespace: "dev", Labels: map[string]string{"app": "kodama"}}},
+		&corev1.Secret{ObjectMeta: sessionObjectMeta("kodama-env-live", "live", time.Hour)},
+		&corev1.Secret{ObjectMeta: sessionObjectMeta("kodama-env-gone", "gone", time.Hour)},
+		&corev1.ConfigMap{ObjectMeta: sessionObjectMeta("kodama-editor-gone", "gone", time.Hour)},
+		// A session still starting has no pod yet
+		&corev1.Secret{ObjectMeta: sessionObjectMeta("kodama-env-new", "new", time.Minute)},
+	)}
+
+	stale, err := client.ListStaleSessionResources(context.Background(), "dev", 10*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, stale, 2)
+	assert.Equal(t, []SessionResource{
+		{Kind: KindConfigMap, Name: "kodama-editor-gone", Namespace: "dev", Session: "gone", CreatedAt: stale[0].CreatedAt},
+		{Kind: KindSecret, Name: "kodama-env-gone", Namespace: "dev", Session: "gone", CreatedAt: stale[1].CreatedAt},
+	}, stale)
+}
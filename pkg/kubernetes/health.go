@@ -0,0 +1,119 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PodHealth describes a pod and the node it is scheduled on
+type PodHealth struct {
+	Phase      corev1.PodPhase
+	Reason     string // e.g. "Evicted"
+	Message    string
+	NodeName   string
+	Exists     bool // false if the pod is gone
+	NodeExists bool
+	NodeReady  bool
+	Terminated bool // true if the pod is being deleted
+}
+
+// Problem returns a description of why the pod cannot serve the session,
+// or "" if it is healthy (or still starting on a ready node)
+func (h *PodHealth) Problem() string {
+	switch {
+	case !h.Exists:
+		return "pod no longer exists"
+	case h.Reason == "Evicted":
+		return fmt.Sprintf("pod was evicted: %s", h.Message)
+	case h.Phase == corev1.PodFailed:
+		return fmt.Sprintf("pod failed: %s", firstNonEmpty(h.Message, h.Reason, "unknown reason"))
+	case h.Phase == corev1.PodSucceeded:
+		return "pod exited"
+	case h.Terminated:
+		return "pod is being deleted"
+	case h.NodeName != "" && !h.NodeExists:
+		return fmt.Sprintf("node %s no longer exists", h.NodeName)
+	case h.NodeName != "" && !h.NodeReady:
+		return fmt.Sprintf("node %s is not ready", h.NodeName)
+	default:
+		return ""
+	}
+}
+
+// GetPodHealth inspects a pod and its node
+func (c *Client) GetPodHealth(ctx context.Context, name, namespace string) (*PodHealth, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return &PodHealth{}, nil
+		}
+		return nil, fmt.Errorf("failed to get pod %s in namespace %s: %w", name, namespace, err)
+	}
+
+	health := &PodHealth{
+		Exists:     true,
+		Phase:      pod.Status.Phase,
+		Reason:     pod.Status.Reason,
+		Message:    pod.Status.Message,
+		NodeName:   pod.Spec.NodeName,
+		Terminated: pod.DeletionTimestamp != nil,
+	}
+
+	if health.NodeName == "" {
+		// Not scheduled yet
+		return health, nil
+	}
+
+	node, err := c.clientset.CoreV1().Nodes().Get(ctx, health.NodeName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return health, nil
+		}
+		if errors.IsForbidden(err) {
+			// Namespaced users often cannot read nodes; assume the node is fine
+			health.NodeExists = true
+			health.NodeReady = true
+			return health, nil
+		}
+		return nil, fmt.Errorf("failed to get node %s: %w", health.NodeName, err)
+	}
+
+	health.NodeExists = true
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			health.NodeReady = condition.Status == corev1.ConditionTrue
+			break
+		}
+	}
+
+	return health, nil
+}
+
+// ForceDeletePod deletes a pod immediately, without waiting for graceful termination
+// Needed for pods on lost nodes, which otherwise stay Terminating indefinitely.
+// The secrets the pod owns are orphaned and kept for the replacement pod.
+func (c *Client) ForceDeletePod(ctx context.Context, name, namespace string) error {
+	gracePeriod := int64(0)
+	orphan := metav1.DeletePropagationOrphan
+	err := c.clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriod,
+		PropagationPolicy:  &orphan,
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to force delete pod %s in namespace %s: %w", name, namespace, err)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
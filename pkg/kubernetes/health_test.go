@@ -0,0 +1,109 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testPod(phase corev1.PodPhase, reason, nodeName string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-test", Namespace: "default"},
+		Spec:       corev1.PodSpec{NodeName: nodeName},
+		Status:     corev1.PodStatus{Phase: phase, Reason: reason, Message: "node was low on memory"},
+	}
+}
+
+func testNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+func TestGetPodHealth(t *testing.T) {
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		wantProblem string
+	}{
+		{
+			name:        "healthy",
+			objects:     []runtime.Object{testPod(corev1.PodRunning, "", "node-1"), testNode("node-1", corev1.ConditionTrue)},
+			wantProblem: "",
+		},
+		{
+			name:        "pending and unscheduled",
+			objects:     []runtime.Object{testPod(corev1.PodPending, "", "")},
+			wantProblem: "",
+		},
+		{
+			name:        "pod deleted",
+			objects:     nil,
+			wantProblem: "pod no longer exists",
+		},
+		{
+			name:        "evicted",
+			objects:     []runtime.Object{testPod(corev1.PodFailed, "Evicted", "node-1"), testNode("node-1", corev1.ConditionTrue)},
+			wantProblem: "pod was evicted: node was low on memory",
+		},
+		{
+			name:        "node gone",
+			objects:     []runtime.Object{testPod(corev1.PodRunning, "", "node-1")},
+			wantProblem: "node node-1 no longer exists",
+		},
+		{
+			name:        "node not ready",
+			objects:     []runtime.Object{testPod(corev1.PodRunning, "", "node-1"), testNode("node-1", corev1.ConditionUnknown)},
+			wantProblem: "node node-1 is not ready",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &Client{clientset: fake.NewSimpleClientset(tt.objects...)}
+
+			health, err := client.GetPodHealth(context.Background(), "kodama-test", "default")
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantProblem, health.Problem())
+		})
+	}
+}
+
+func TestForceDeletePod(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset(testPod(corev1.PodRunning, "", "node-1"))}
+
+	require.NoError(t, client.ForceDeletePod(context.Background(), "kodama-test", "default"))
+	// Deleting a missing pod is not an error
+	require.NoError(t, client.ForceDeletePod(context.Background(), "kodama-test", "default"))
+
+	health, err := client.GetPodHealth(context.Background(), "kodama-test", "default")
+	require.NoError(t, err)
+	assert.False(t, health.Exists)
+}
+
+func TestReplacedPodDeletionOrphansSecrets(t *testing.T) {
+	clientset := fake.NewSimpleClientset(testPod(corev1.PodRunning, "", "node-1"))
+	client := &Client{clientset: clientset}
+
+	require.NoError(t, client.ForceDeletePod(context.Background(), "kodama-test", "default"))
+	require.NoError(t, client.DeletePodKeepingDependents(context.Background(), "kodama-test", "default"))
+
+	// Both keep the secrets the pod owns for its replacement
+	for _, action := range clientset.Actions() {
+		if deleteAction, ok := action.(k8stesting.DeleteAction); ok {
+			policy := deleteAction.GetDeleteOptions().PropagationPolicy
+			require.NotNil(t, policy)
+			assert.Equal(t, metav1.DeletePropagationOrphan, *policy)
+		}
+	}
+}
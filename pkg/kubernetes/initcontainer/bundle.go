@@ -0,0 +1,145 @@
+package initcontainer
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Sources of Claude Code and ttyd binaries
+const (
+	SourceDownload  = "download"
+	SourceConfigMap = "configmap"
+	SourcePVC       = "pvc"
+	SourceImage     = "image"
+)
+
+const (
+	// BundleMountPath is where the configmap and pvc sources are mounted
+	BundleMountPath = "/kodama/bundle"
+
+	// DefaultBundleImagePath is where the image source keeps its binaries
+	DefaultBundleImagePath = "/kodama-tools"
+
+	// bundleTargetPath is where the bin volume is mounted while copying, so an
+	// image that keeps its binaries under /kodama/bin is not shadowed
+	bundleTargetPath = "/kodama/target"
+)
+
+// BundleInstallerConfig copies prebuilt binaries into /kodama/bin instead of
+// downloading them, for clusters without internet access
+type BundleInstallerConfig struct {
+	// Source is SourceConfigMap, SourcePVC or SourceImage
+	Source string
+
+	// SourceImage is the image holding the binaries (image source only)
+	SourceImage string
+
+	// Dir is the directory containing the binaries
+	Dir string
+
+	// Binaries are the file names copied, e.g. "claude" and "ttyd"
+	Binaries []string
+
+	// BinVolumeName is the name of the volume shared as /kodama/bin
+	BinVolumeName string
+
+	// BundleVolumeName is the volume holding the binaries (configmap and pvc sources)
+	BundleVolumeName string
+}
+
+// NewBundleInstallerConfig creates a bundle installer configuration
+// path is a directory inside the PVC or the image; it is ignored for ConfigMaps.
+func NewBundleInstallerConfig(source, image, path, binVolumeName, bundleVolumeName string, binaries ...string) *BundleInstallerConfig {
+	if binVolumeName == "" {
+		binVolumeName = "kodama-bin"
+	}
+
+	dir := BundleMountPath
+	switch source {
+	case SourcePVC:
+		if path != "" {
+			dir += "/" + strings.Trim(path, "/")
+		}
+	case SourceImage:
+		dir = DefaultBundleImagePath
+		if path != "" {
+			dir = path
+		}
+	}
+
+	return &BundleInstallerConfig{
+		Source:           source,
+		SourceImage:      image,
+		Dir:              dir,
+		Binaries:         binaries,
+		BinVolumeName:    binVolumeName,
+		BundleVolumeName: bundleVolumeName,
+	}
+}
+
+// Name returns the init container name
+func (b *BundleInstallerConfig) Name() string {
+	return "bundle-installer"
+}
+
+// Image returns the container image
+func (b *BundleInstallerConfig) Image() string {
+	if b.Source == SourceImage {
+		return b.SourceImage
+	}
+	return "ubuntu:24.04"
+}
+
+// Command returns the shell command
+// sh rather than bash, since tool images are often minimal.
+func (b *BundleInstallerConfig) Command() []string {
+	return []string{"/bin/sh", "-c"}
+}
+
+// Args returns the copy script
+// Missing binaries fail the init container with the path that was searched.
+func (b *BundleInstallerConfig) Args() []string {
+	commands := make([]string, 0, len(b.Binaries)*2)
+	for _, binary := range b.Binaries {
+		src := b.Dir + "/" + binary
+		commands = append(commands,
+			"[ -f '"+src+"' ] || { echo '"+src+" not found in "+b.Source+" source' >&2; exit 1; }",
+			"cp -L '"+src+"' "+bundleTargetPath+"/"+binary+" && chmod 755 "+bundleTargetPath+"/"+binary,
+		)
+	}
+	return []string{BuildScript(b.StartMessage(), b.CompletionMessage(), commands...)}
+}
+
+// VolumeMounts returns required volume mounts
+func (b *BundleInstallerConfig) VolumeMounts() []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{
+		{
+			Name:      b.BinVolumeName,
+			MountPath: bundleTargetPath,
+		},
+	}
+	if b.Source != SourceImage && b.BundleVolumeName != "" {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      b.BundleVolumeName,
+			MountPath: BundleMountPath,
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// EnvVars returns environment variables (none needed for copying)
+func (b *BundleInstallerConfig) EnvVars() []corev1.EnvVar {
+	return []corev1.EnvVar{}
+}
+
+// StartMessage returns the installation start message
+func (b *BundleInstallerConfig) StartMessage() string {
+	return "Copying " + strings.Join(b.Binaries, ", ") + " from " + b.Source + " source..."
+}
+
+// CompletionMessage returns the installation completion message
+func (b *BundleInstallerConfig) CompletionMessage() string {
+	return "Bundled tools installed"
+}
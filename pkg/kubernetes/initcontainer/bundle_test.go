@@ -0,0 +1,69 @@
+package initcontainer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBundleInstallerConfig_Dir(t *testing.T) {
+	tests := []struct {
+		source string
+		path   string
+		want   string
+	}{
+		{SourceConfigMap, "ignored", BundleMountPath},
+		{SourcePVC, "", BundleMountPath},
+		{SourcePVC, "/tools/v1/", BundleMountPath + "/tools/v1"},
+		{SourceImage, "", DefaultBundleImagePath},
+		{SourceImage, "/opt/kodama", "/opt/kodama"},
+	}
+
+	for _, tt := range tests {
+		config := NewBundleInstallerConfig(tt.source, "", tt.path, "", "kodama-bundle", "claude")
+		if config.Dir != tt.want {
+			t.Errorf("source %s path %q: expected dir %q, got %q", tt.source, tt.path, tt.want, config.Dir)
+		}
+	}
+}
+
+func TestBundleInstallerConfig_Script(t *testing.T) {
+	config := NewBundleInstallerConfig(SourcePVC, "", "tools", "", "kodama-bundle", "claude", "ttyd")
+
+	script := config.Args()[0]
+	for _, part := range []string{
+		"[ -f '/kodama/bundle/tools/claude' ] || {",
+		"cp -L '/kodama/bundle/tools/claude' /kodama/target/claude",
+		"cp -L '/kodama/bundle/tools/ttyd' /kodama/target/ttyd",
+	} {
+		if !strings.Contains(script, part) {
+			t.Errorf("script missing %q:\n%s", part, script)
+		}
+	}
+	if strings.Contains(script, "curl") {
+		t.Errorf("bundle script must not download anything:\n%s", script)
+	}
+
+	if config.Image() != "ubuntu:24.04" {
+		t.Errorf("Expected image 'ubuntu:24.04', got '%s'", config.Image())
+	}
+	mounts := config.VolumeMounts()
+	if len(mounts) != 2 || mounts[1].Name != "kodama-bundle" || !mounts[1].ReadOnly {
+		t.Errorf("Expected kodama-bin and read-only kodama-bundle mounts, got %v", mounts)
+	}
+}
+
+func TestBundleInstallerConfig_Image(t *testing.T) {
+	config := NewBundleInstallerConfig(SourceImage, "registry.corp/kodama-tools:1.0", "", "", "kodama-bundle", "claude")
+
+	if config.Image() != "registry.corp/kodama-tools:1.0" {
+		t.Errorf("Expected source image, got '%s'", config.Image())
+	}
+	if cmd := config.Command(); cmd[0] != "/bin/sh" {
+		t.Errorf("Expected /bin/sh for minimal images, got %v", cmd)
+	}
+	// The image provides the binaries itself, only the target is mounted
+	mounts := config.VolumeMounts()
+	if len(mounts) != 1 || mounts[0].MountPath == "/kodama/bin" {
+		t.Errorf("Expected only the target mount outside /kodama/bin, got %v", mounts)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 )
 
+// ClaudeInstallScriptURL is the official Claude Code CLI install script
+const ClaudeInstallScriptURL = "https://claude.ai/install.sh"
+
 // ClaudeInstallerConfig configures Claude Code CLI installation
 type ClaudeInstallerConfig struct {
 	// Version specifies the Claude Code version to install (e.g., "latest")
@@ -49,13 +52,30 @@ func (c *ClaudeInstallerConfig) Args() []string {
 		c.StartMessage(),
 		c.CompletionMessage(),
 		"apt-get update -qq && apt-get install -y -qq curl ca-certificates",
-		"curl -fsSL https://claude.ai/install.sh | bash -s "+c.Version,
+		"curl -fsSL "+ClaudeInstallScriptURL+" | bash -s "+c.Version,
 		"mkdir -p /kodama/bin",
 		"cp -rL /root/.local/bin/* /kodama/bin/",
 	)
 	return []string{script}
 }
 
+// RepairScript returns a script that reinstalls the CLI from inside the main container
+// Used when /kodama/bin lost its contents, e.g. after a container restart. The main
+// image may lack curl, and the installer runs with a temporary HOME so the user's
+// home directory is left untouched.
+func (c *ClaudeInstallerConfig) RepairScript() string {
+	return BuildScript(
+		"Reinstalling Claude Code CLI...",
+		c.CompletionMessage(),
+		"command -v curl >/dev/null 2>&1 || (apt-get update -qq && apt-get install -y -qq curl ca-certificates)",
+		"KODAMA_HOME=$(mktemp -d)",
+		"curl -fsSL "+ClaudeInstallScriptURL+" | HOME=\"$KODAMA_HOME\" bash -s "+c.Version,
+		"mkdir -p /kodama/bin",
+		"cp -rL \"$KODAMA_HOME\"/.local/bin/* /kodama/bin/",
+		"rm -rf \"$KODAMA_HOME\"",
+	)
+}
+
 // VolumeMounts returns required volume mounts
 func (c *ClaudeInstallerConfig) VolumeMounts() []corev1.VolumeMount {
 	return []corev1.VolumeMount{
@@ -102,3 +102,17 @@ func TestClaudeInstallerBuilder(t *testing.T) {
 		t.Errorf("Expected 1 volume mount, got %d", len(container.VolumeMounts))
 	}
 }
+
+func TestClaudeInstallerConfig_RepairScript(t *testing.T) {
+	script := NewClaudeInstallerConfig("latest", "").RepairScript()
+
+	for _, part := range []string{
+		"command -v curl",
+		"curl -fsSL https://claude.ai/install.sh | HOME=\"$KODAMA_HOME\" bash -s latest",
+		"cp -rL \"$KODAMA_HOME\"/.local/bin/* /kodama/bin/",
+	} {
+		if !strings.Contains(script, part) {
+			t.Errorf("Repair script missing expected part: %s", part)
+		}
+	}
+}
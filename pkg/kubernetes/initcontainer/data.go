@@ -0,0 +1,198 @@
+package initcontainer
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Default images of the dataset fetcher, by URI scheme
+const (
+	DefaultAWSCLIImage = "amazon/aws-cli:2.15.0"
+	DefaultGCloudImage = "google/cloud-sdk:slim"
+	DefaultRcloneImage = "rclone/rclone:1.68"
+)
+
+const (
+	// DataCredentialsMountPath is where the credentials Secret is mounted for gs:// and rclone URIs
+	DataCredentialsMountPath = "/kodama/data-credentials"
+
+	// dataProgressInterval is how often the fetcher prints how much it has fetched, in seconds
+	dataProgressInterval = "5"
+)
+
+// DataFetcherConfig configures an init container that copies a dataset from
+// object storage into the workspace before the coding agent starts
+// s3:// URIs are fetched with the aws CLI, gs:// URIs with gcloud storage, and
+// anything else (e.g. remote:bucket/path) with rclone. A URI ending in '/' is
+// copied recursively.
+type DataFetcherConfig struct {
+	// URI is the object or prefix to fetch, e.g. s3://bucket/datasets/
+	URI string
+
+	// Destination is the absolute directory the data is copied into, inside /workspace
+	Destination string
+
+	// CredentialsSecret holds the credentials: AWS_* keys for s3://, key.json
+	// for gs:// and rclone.conf for rclone. Empty uses the pod's identity.
+	CredentialsSecret string
+
+	// CredentialsVolumeName is the volume of the credentials Secret (gs:// and rclone)
+	CredentialsVolumeName string
+
+	// ImageOverride replaces the default image for the URI scheme
+	ImageOverride string
+
+	// WorkspaceVolumeName is the name of the volume to mount at /workspace
+	WorkspaceVolumeName string
+}
+
+// NewDataFetcherConfig creates a dataset fetcher configuration
+func NewDataFetcherConfig(uri, destination string) *DataFetcherConfig {
+	return &DataFetcherConfig{
+		URI:                   uri,
+		Destination:           destination,
+		CredentialsVolumeName: "kodama-data-credentials",
+		WorkspaceVolumeName:   "workspace",
+	}
+}
+
+// WithCredentials sets the Secret holding the object store credentials
+func (d *DataFetcherConfig) WithCredentials(secret string) *DataFetcherConfig {
+	d.CredentialsSecret = secret
+	return d
+}
+
+// WithImage replaces the default image, e.g. with a mirror in a private registry
+func (d *DataFetcherConfig) WithImage(image string) *DataFetcherConfig {
+	d.ImageOverride = image
+	return d
+}
+
+// MountsCredentials reports whether the credentials Secret is mounted as files
+// rather than passed as environment variables
+func (d *DataFetcherConfig) MountsCredentials() bool {
+	return d.CredentialsSecret != "" && !strings.HasPrefix(d.URI, "s3://")
+}
+
+// Name returns the init container name
+func (d *DataFetcherConfig) Name() string {
+	return "data-fetcher"
+}
+
+// Image returns the CLI image for the URI scheme
+func (d *DataFetcherConfig) Image() string {
+	if d.ImageOverride != "" {
+		return d.ImageOverride
+	}
+	switch {
+	case strings.HasPrefix(d.URI, "s3://"):
+		return DefaultAWSCLIImage
+	case strings.HasPrefix(d.URI, "gs://"):
+		return DefaultGCloudImage
+	default:
+		return DefaultRcloneImage
+	}
+}
+
+// Command returns the shell command; the rclone image has no bash
+func (d *DataFetcherConfig) Command() []string {
+	return []string{"/bin/sh", "-c"}
+}
+
+// Args returns the fetch script
+// The URI and destination are passed as environment variables rather than
+// interpolated. The destination is git-ignored, so the dataset is never
+// committed or reported as a change. The size of the destination is printed
+// while the copy runs, which WaitForPodReady reports as progress.
+func (d *DataFetcherConfig) Args() []string {
+	script := BuildScript("", "",
+		`mkdir -p "$DATA_DEST"`,
+		`[ -f "$DATA_DEST/.gitignore" ] || printf '*\n' > "$DATA_DEST/.gitignore"`,
+		`echo "Fetching $DATA_URI into $DATA_DEST"`,
+		`( while sleep `+dataProgressInterval+`; do echo "Fetched $(du -sh "$DATA_DEST" 2>/dev/null | cut -f1)"; done ) &`,
+		d.copyCommand(),
+		`kill $! 2>/dev/null || true`,
+		`echo "Dataset fetched ($(du -sh "$DATA_DEST" | cut -f1))"`,
+	)
+	return []string{script}
+}
+
+// copyCommand returns the CLI invocation for the URI scheme
+func (d *DataFetcherConfig) copyCommand() string {
+	recursive := strings.HasSuffix(d.URI, "/")
+	switch {
+	case strings.HasPrefix(d.URI, "s3://"):
+		if recursive {
+			return `aws s3 sync "$DATA_URI" "$DATA_DEST" --only-show-errors`
+		}
+		return `aws s3 cp "$DATA_URI" "$DATA_DEST/" --only-show-errors`
+	case strings.HasPrefix(d.URI, "gs://"):
+		if recursive {
+			return `gcloud storage rsync --recursive --no-user-output-enabled "$DATA_URI" "$DATA_DEST"`
+		}
+		return `gcloud storage cp --no-user-output-enabled "$DATA_URI" "$DATA_DEST/"`
+	default:
+		return `rclone copy "$DATA_URI" "$DATA_DEST"`
+	}
+}
+
+// VolumeMounts returns the workspace mount and, for gs:// and rclone, the credentials
+func (d *DataFetcherConfig) VolumeMounts() []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{{Name: d.WorkspaceVolumeName, MountPath: "/workspace"}}
+	if d.MountsCredentials() {
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      d.CredentialsVolumeName,
+			MountPath: DataCredentialsMountPath,
+			ReadOnly:  true,
+		})
+	}
+	return mounts
+}
+
+// EnvVars returns the URI, the destination and how the CLI finds its credentials
+func (d *DataFetcherConfig) EnvVars() []corev1.EnvVar {
+	envVars := []corev1.EnvVar{
+		{Name: "DATA_URI", Value: d.URI},
+		{Name: "DATA_DEST", Value: d.Destination},
+	}
+	if d.CredentialsSecret == "" {
+		return envVars
+	}
+
+	switch {
+	case strings.HasPrefix(d.URI, "s3://"):
+		optional := true
+		for _, key := range []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_REGION", "AWS_ENDPOINT_URL"} {
+			envVars = append(envVars, corev1.EnvVar{
+				Name: key,
+				ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: d.CredentialsSecret},
+					Key:                  key,
+					Optional:             &optional,
+				}},
+			})
+		}
+	case strings.HasPrefix(d.URI, "gs://"):
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE",
+			Value: DataCredentialsMountPath + "/key.json",
+		})
+	default:
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  "RCLONE_CONFIG",
+			Value: DataCredentialsMountPath + "/rclone.conf",
+		})
+	}
+	return envVars
+}
+
+// StartMessage returns the fetch start message
+func (d *DataFetcherConfig) StartMessage() string {
+	return "Fetching dataset..."
+}
+
+// CompletionMessage returns the fetch completion message
+func (d *DataFetcherConfig) CompletionMessage() string {
+	return "Dataset fetched"
+}
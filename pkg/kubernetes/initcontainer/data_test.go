@@ -0,0 +1,75 @@
+package initcontainer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDataFetcherConfig_Schemes(t *testing.T) {
+	tests := []struct {
+		uri     string
+		image   string
+		command string
+	}{
+		{"s3://bucket/datasets/", DefaultAWSCLIImage, `aws s3 sync "$DATA_URI" "$DATA_DEST"`},
+		{"s3://bucket/train.tar", DefaultAWSCLIImage, `aws s3 cp "$DATA_URI" "$DATA_DEST/"`},
+		{"gs://bucket/datasets/", DefaultGCloudImage, `gcloud storage rsync --recursive`},
+		{"gs://bucket/train.tar", DefaultGCloudImage, `gcloud storage cp`},
+		{"minio:bucket/datasets", DefaultRcloneImage, `rclone copy "$DATA_URI" "$DATA_DEST"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			config := NewDataFetcherConfig(tt.uri, "/workspace/data")
+			if config.Image() != tt.image {
+				t.Errorf("Image() = %q, want %q", config.Image(), tt.image)
+			}
+			script := config.Args()[0]
+			if !strings.Contains(script, tt.command) {
+				t.Errorf("script does not contain %q:\n%s", tt.command, script)
+			}
+			if !strings.Contains(script, `printf '*\n' > "$DATA_DEST/.gitignore"`) {
+				t.Errorf("the destination is not git-ignored:\n%s", script)
+			}
+			if strings.Contains(script, tt.uri) {
+				t.Errorf("the URI must be passed in the environment, not the script:\n%s", script)
+			}
+		})
+	}
+
+	if image := NewDataFetcherConfig("s3://bucket/", "/workspace/data").WithImage("registry.corp/aws-cli:2").Image(); image != "registry.corp/aws-cli:2" {
+		t.Errorf("WithImage() image = %q", image)
+	}
+}
+
+func TestDataFetcherConfig_Credentials(t *testing.T) {
+	s3 := NewDataFetcherConfig("s3://bucket/", "/workspace/data").WithCredentials("aws-creds")
+	if s3.MountsCredentials() {
+		t.Error("s3 credentials should be passed as environment variables")
+	}
+	env := map[string]string{}
+	for _, envVar := range s3.EnvVars() {
+		if envVar.ValueFrom != nil && envVar.ValueFrom.SecretKeyRef != nil {
+			env[envVar.Name] = envVar.ValueFrom.SecretKeyRef.Name
+		}
+	}
+	if env["AWS_ACCESS_KEY_ID"] != "aws-creds" || env["AWS_SECRET_ACCESS_KEY"] != "aws-creds" {
+		t.Errorf("missing AWS credentials from the secret: %v", env)
+	}
+
+	gs := NewDataFetcherConfig("gs://bucket/", "/workspace/data").WithCredentials("gcp-key")
+	if !gs.MountsCredentials() || len(gs.VolumeMounts()) != 2 {
+		t.Errorf("gs credentials should be mounted, got mounts %v", gs.VolumeMounts())
+	}
+	found := false
+	for _, envVar := range gs.EnvVars() {
+		found = found || envVar.Name == "CLOUDSDK_AUTH_CREDENTIAL_FILE_OVERRIDE" && envVar.Value == DataCredentialsMountPath+"/key.json"
+	}
+	if !found {
+		t.Errorf("gcloud is not pointed at the mounted key: %v", gs.EnvVars())
+	}
+
+	if anonymous := NewDataFetcherConfig("gs://public/", "/workspace/data"); anonymous.MountsCredentials() || len(anonymous.EnvVars()) != 2 {
+		t.Errorf("no credentials expected without a secret, got %v", anonymous.EnvVars())
+	}
+}
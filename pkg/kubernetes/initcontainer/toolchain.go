@@ -0,0 +1,140 @@
+package initcontainer
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ToolchainMountPath holds mise, its global config and, without a cache, the toolchains
+	ToolchainMountPath = "/kodama/tools"
+
+	// MiseInstallURL is the mise install script
+	MiseInstallURL = "https://mise.run"
+
+	// miseBinary is where mise is installed
+	miseBinary = ToolchainMountPath + "/bin/mise"
+)
+
+// ToolchainInstallerConfig configures an init container that installs the
+// session's toolchains with mise, e.g. go@1.23 and node@22. It runs the
+// session image, so the toolchains are built for the image's libc. mise reads
+// asdf plugins as well, so asdf-only tools work too.
+type ToolchainInstallerConfig struct {
+	// Tools are mise tool specs, e.g. go@1.23
+	Tools []string
+
+	// SessionImage is the image of the main container
+	SessionImage string
+
+	// VolumeName is the name of the volume to mount at ToolchainMountPath
+	VolumeName string
+
+	// CacheVolumeName and CacheMountPath are the shared cache; toolchains are
+	// installed there so other sessions reuse them. Empty installs into VolumeName.
+	CacheVolumeName string
+	CacheMountPath  string
+}
+
+// NewToolchainInstallerConfig creates a toolchain installer configuration
+func NewToolchainInstallerConfig(image string, tools []string, volumeName string) *ToolchainInstallerConfig {
+	return &ToolchainInstallerConfig{
+		Tools:        tools,
+		SessionImage: image,
+		VolumeName:   volumeName,
+	}
+}
+
+// WithCache installs the toolchains into the shared cache volume
+func (t *ToolchainInstallerConfig) WithCache(volumeName, mountPath string) *ToolchainInstallerConfig {
+	t.CacheVolumeName = volumeName
+	t.CacheMountPath = mountPath
+	return t
+}
+
+// Name returns the init container name
+func (t *ToolchainInstallerConfig) Name() string {
+	return "toolchain-installer"
+}
+
+// Image returns the session image
+func (t *ToolchainInstallerConfig) Image() string {
+	return t.SessionImage
+}
+
+// Command returns the shell command; the session image may not have bash
+func (t *ToolchainInstallerConfig) Command() []string {
+	return []string{"/bin/sh", "-c"}
+}
+
+// Args returns the installation script
+// mise is downloaded with curl or wget unless a previous start installed it.
+// Tools are passed as arguments of mise use, which validates them.
+func (t *ToolchainInstallerConfig) Args() []string {
+	script := BuildScript(t.StartMessage(), t.CompletionMessage(),
+		`mkdir -p `+ToolchainMountPath+`/bin "$MISE_DATA_DIR" "$MISE_CONFIG_DIR"`,
+		`if [ ! -x `+miseBinary+` ]; then`,
+		`  if command -v curl >/dev/null 2>&1; then curl -fsSL `+MiseInstallURL+` | MISE_INSTALL_PATH=`+miseBinary+` sh`,
+		`  elif command -v wget >/dev/null 2>&1; then wget -qO- `+MiseInstallURL+` | MISE_INSTALL_PATH=`+miseBinary+` sh`,
+		`  else echo "curl or wget is required in the session image to install mise" >&2; exit 1; fi`,
+		`fi`,
+		miseBinary+` use --global `+strings.Join(t.Tools, " "),
+		miseBinary+` reshim`,
+		miseBinary+` ls --current`,
+	)
+	return []string{script}
+}
+
+// VolumeMounts returns the toolchain volume and, when set, the shared cache
+func (t *ToolchainInstallerConfig) VolumeMounts() []corev1.VolumeMount {
+	mounts := []corev1.VolumeMount{{Name: t.VolumeName, MountPath: ToolchainMountPath}}
+	if t.CacheVolumeName != "" {
+		mounts = append(mounts, corev1.VolumeMount{Name: t.CacheVolumeName, MountPath: t.CacheMountPath})
+	}
+	return mounts
+}
+
+// EnvVars returns where mise keeps its data, config and downloads
+func (t *ToolchainInstallerConfig) EnvVars() []corev1.EnvVar {
+	return ToolchainEnvVars(t.CacheMountPath)
+}
+
+// StartMessage returns the installation start message
+func (t *ToolchainInstallerConfig) StartMessage() string {
+	return "Installing toolchains: " + strings.Join(t.Tools, " ")
+}
+
+// CompletionMessage returns the installation completion message
+func (t *ToolchainInstallerConfig) CompletionMessage() string {
+	return "Toolchains installed"
+}
+
+// ToolchainEnvVars returns the mise settings shared by the installer and the
+// main container. With a cache mount path, toolchains and downloads go to the
+// cache; the global config, which pins the session's versions, stays per pod.
+func ToolchainEnvVars(cacheMountPath string) []corev1.EnvVar {
+	cacheDir := ToolchainMountPath + "/cache"
+	if cacheMountPath != "" {
+		cacheDir = cacheMountPath + "/mise-cache"
+	}
+	return []corev1.EnvVar{
+		{Name: "MISE_DATA_DIR", Value: miseDataDir(cacheMountPath)},
+		{Name: "MISE_CACHE_DIR", Value: cacheDir},
+		{Name: "MISE_CONFIG_DIR", Value: ToolchainMountPath + "/config"},
+		{Name: "MISE_YES", Value: "1"},
+	}
+}
+
+// ToolchainPath returns the PATH entries for the mise shims and mise itself
+func ToolchainPath(cacheMountPath string) string {
+	return miseDataDir(cacheMountPath) + "/shims:" + ToolchainMountPath + "/bin"
+}
+
+// miseDataDir is where mise installs toolchains
+func miseDataDir(cacheMountPath string) string {
+	if cacheMountPath != "" {
+		return cacheMountPath + "/mise"
+	}
+	return ToolchainMountPath + "/data"
+}
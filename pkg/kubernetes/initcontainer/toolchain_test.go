@@ -0,0 +1,58 @@
+package initcontainer
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestToolchainInstallerConfig(t *testing.T) {
+	config := NewToolchainInstallerConfig("ubuntu:24.04", []string{"go@1.23", "node@22"}, "kodama-tools")
+
+	if config.Name() != "toolchain-installer" {
+		t.Errorf("Name() = %q", config.Name())
+	}
+	if config.Image() != "ubuntu:24.04" {
+		t.Errorf("Image() = %q, want the session image", config.Image())
+	}
+	script := config.Args()[0]
+	for _, want := range []string{"set -e", MiseInstallURL, miseBinary + " use --global go@1.23 node@22", miseBinary + " reshim"} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script does not contain %q:\n%s", want, script)
+		}
+	}
+	if mounts := config.VolumeMounts(); len(mounts) != 1 || mounts[0].MountPath != ToolchainMountPath {
+		t.Errorf("VolumeMounts() = %v, want only the toolchain volume", mounts)
+	}
+	if got := envValue(config.EnvVars(), "MISE_DATA_DIR"); got != "/kodama/tools/data" {
+		t.Errorf("MISE_DATA_DIR = %q without a cache", got)
+	}
+}
+
+func TestToolchainInstallerConfig_WithCache(t *testing.T) {
+	config := NewToolchainInstallerConfig("ubuntu:24.04", []string{"rust@stable"}, "kodama-tools").WithCache("kodama-cache", "/cache")
+
+	if mounts := config.VolumeMounts(); len(mounts) != 2 || mounts[1].Name != "kodama-cache" || mounts[1].MountPath != "/cache" {
+		t.Errorf("VolumeMounts() = %v, want the cache mounted at /cache", mounts)
+	}
+	env := config.EnvVars()
+	if got := envValue(env, "MISE_DATA_DIR"); got != "/cache/mise" {
+		t.Errorf("MISE_DATA_DIR = %q, want toolchains in the cache", got)
+	}
+	if got := envValue(env, "MISE_CONFIG_DIR"); got != "/kodama/tools/config" {
+		t.Errorf("MISE_CONFIG_DIR = %q, the pinned versions must stay per pod", got)
+	}
+	if got := ToolchainPath("/cache"); got != "/cache/mise/shims:/kodama/tools/bin" {
+		t.Errorf("ToolchainPath() = %q", got)
+	}
+}
+
+func envValue(env []corev1.EnvVar, name string) string {
+	for _, e := range env {
+		if e.Name == name {
+			return e.Value
+		}
+	}
+	return ""
+}
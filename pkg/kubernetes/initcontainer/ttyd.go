@@ -1,9 +1,30 @@
 package initcontainer
 
 import (
+	"fmt"
+
 	corev1 "k8s.io/api/core/v1"
 )
 
+// TtydReleaseURL is the base URL of ttyd release downloads
+const TtydReleaseURL = "https://github.com/tsl0922/ttyd/releases/download"
+
+// ttydAssets maps architectures, as reported by uname -m or named by GOARCH,
+// to ttyd release asset names
+var ttydAssets = map[string]string{
+	"x86_64":  "ttyd.x86_64",
+	"amd64":   "ttyd.x86_64",
+	"aarch64": "ttyd.aarch64",
+	"arm64":   "ttyd.aarch64",
+	"armv7l":  "ttyd.armhf",
+	"arm":     "ttyd.armhf",
+}
+
+// TtydAsset returns the release asset name for an architecture, or "" if there is none
+func TtydAsset(arch string) string {
+	return ttydAssets[arch]
+}
+
 // TtydInstallerConfig configures ttyd (web terminal) installation
 type TtydInstallerConfig struct {
 	// Version specifies the ttyd release version (e.g., "1.7.7")
@@ -11,6 +32,10 @@ type TtydInstallerConfig struct {
 
 	// BinVolumeName is the name of the volume to mount at /kodama/bin
 	BinVolumeName string
+
+	// Arch selects the release binary (e.g., "arm64"); empty detects the
+	// node's architecture with uname -m when the init container runs
+	Arch string
 }
 
 // NewTtydInstallerConfig creates a new ttyd installer configuration
@@ -28,6 +53,12 @@ func NewTtydInstallerConfig(version, binVolumeName string) *TtydInstallerConfig
 	}
 }
 
+// WithArch sets the architecture instead of detecting it
+func (t *TtydInstallerConfig) WithArch(arch string) *TtydInstallerConfig {
+	t.Arch = arch
+	return t
+}
+
 // Name returns the init container name
 func (t *TtydInstallerConfig) Name() string {
 	return "ttyd-installer"
@@ -44,20 +75,45 @@ func (t *TtydInstallerConfig) Command() []string {
 }
 
 // Args returns the installation script
+// The release binary for the node's architecture is verified against the
+// release's SHA256SUMS. The release binaries are statically linked, so they run
+// in any session image. When the download or the check fails, or there is no
+// release binary for the architecture, the init container fails.
 func (t *TtydInstallerConfig) Args() []string {
-	downloadURL := "https://github.com/tsl0922/ttyd/releases/download/" + t.Version + "/ttyd.x86_64"
+	releaseURL := TtydReleaseURL + "/" + t.Version
 	script := BuildScript(
 		t.StartMessage(),
 		t.CompletionMessage(),
 		"apt-get update -qq && apt-get install -y -qq curl ca-certificates",
-		"curl -fsSL "+downloadURL+" -o /tmp/ttyd",
-		"chmod +x /tmp/ttyd",
+		t.assetCommand(),
+		"if [ -n \"$ttyd_asset\" ] &&",
+		"  curl -fsSL "+releaseURL+"/\"$ttyd_asset\" -o /tmp/ttyd &&",
+		"  curl -fsSL "+releaseURL+"/SHA256SUMS -o /tmp/ttyd.sha256 &&",
+		"  [ \"$(awk -v f=\"$ttyd_asset\" '$2 == f || $2 == \"*\" f { print $1 }' /tmp/ttyd.sha256)\" = \"$(sha256sum /tmp/ttyd | cut -d' ' -f1)\" ]; then",
+		"  chmod +x /tmp/ttyd",
+		"else",
+		"  printf '%s\\n' \"ttyd: no verified release binary (asset: ${ttyd_asset:-none for $(uname -m)}; download failed, checksum mismatch or unsupported architecture)\" \"Provide a static ttyd with installer.source, or start the session with --ttyd=false\" >&2",
+		"  exit 1",
+		"fi",
 		"mkdir -p /kodama/bin",
 		"cp /tmp/ttyd /kodama/bin/ttyd",
 	)
 	return []string{script}
 }
 
+// assetCommand sets ttyd_asset to the release asset for Arch, or for the
+// architecture detected at run time; it is empty when there is no release binary
+func (t *TtydInstallerConfig) assetCommand() string {
+	if t.Arch != "" {
+		return fmt.Sprintf("ttyd_asset=%s", TtydAsset(t.Arch))
+	}
+	return "case \"$(uname -m)\" in" +
+		" x86_64) ttyd_asset=" + TtydAsset("x86_64") + " ;;" +
+		" aarch64|arm64) ttyd_asset=" + TtydAsset("aarch64") + " ;;" +
+		" armv7l) ttyd_asset=" + TtydAsset("armv7l") + " ;;" +
+		" *) ttyd_asset= ;; esac"
+}
+
 // VolumeMounts returns required volume mounts
 func (t *TtydInstallerConfig) VolumeMounts() []corev1.VolumeMount {
 	return []corev1.VolumeMount{
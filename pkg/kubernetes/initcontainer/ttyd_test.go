@@ -34,7 +34,11 @@ func TestTtydInstallerConfig(t *testing.T) {
 	expectedParts := []string{
 		"Installing ttyd...",
 		"apt-get update",
-		"curl -fsSL https://github.com/tsl0922/ttyd/releases/download/1.7.7/ttyd.x86_64",
+		`case "$(uname -m)" in x86_64) ttyd_asset=ttyd.x86_64 ;; aarch64|arm64) ttyd_asset=ttyd.aarch64 ;;`,
+		`curl -fsSL https://github.com/tsl0922/ttyd/releases/download/1.7.7/"$ttyd_asset" -o /tmp/ttyd`,
+		"curl -fsSL https://github.com/tsl0922/ttyd/releases/download/1.7.7/SHA256SUMS -o /tmp/ttyd.sha256",
+		"sha256sum /tmp/ttyd",
+		"exit 1",
 		"chmod +x /tmp/ttyd",
 		"cp /tmp/ttyd /kodama/bin/ttyd",
 		"ttyd installation complete",
@@ -86,7 +90,7 @@ func TestTtydInstallerCustomVersion(t *testing.T) {
 
 	script := args[0]
 	// Verify custom version is in the download URL
-	expectedURL := "https://github.com/tsl0922/ttyd/releases/download/1.8.0/ttyd.x86_64"
+	expectedURL := "https://github.com/tsl0922/ttyd/releases/download/1.8.0/\"$ttyd_asset\""
 	if !strings.Contains(script, expectedURL) {
 		t.Errorf("Script missing custom version URL: %s", expectedURL)
 	}
@@ -119,3 +123,35 @@ func TestTtydInstallerBuilder(t *testing.T) {
 		t.Errorf("Expected 1 volume mount, got %d", len(container.VolumeMounts))
 	}
 }
+
+func TestTtydInstallerArch(t *testing.T) {
+	tests := []struct {
+		arch  string
+		asset string
+	}{
+		{"amd64", "ttyd_asset=ttyd.x86_64\n"},
+		{"arm64", "ttyd_asset=ttyd.aarch64\n"},
+		{"riscv64", "ttyd_asset=\n"}, // No release binary, the install fails
+	}
+
+	for _, tt := range tests {
+		script := NewTtydInstallerConfig("1.7.7", "kodama-bin").WithArch(tt.arch).Args()[0]
+		if !strings.Contains(script, tt.asset) {
+			t.Errorf("arch %s: script missing %q", tt.arch, tt.asset)
+		}
+		if strings.Contains(script, "uname -m)\" in") {
+			t.Errorf("arch %s: explicit arch should not be detected", tt.arch)
+		}
+	}
+}
+
+func TestTtydInstallerCombinedKeepsFailureMessage(t *testing.T) {
+	// BuildCombined drops lines starting with echo, so the failure must not use it
+	container := NewBuilder().BuildCombined("tools-installer", NewTtydInstallerConfig("1.7.7", "kodama-bin"))
+	if !strings.Contains(container.Args[0], "ttyd: no verified release binary") {
+		t.Errorf("combined script lost the failure message:\n%s", container.Args[0])
+	}
+	if strings.Contains(container.Args[0], "apt-get install -y -qq ttyd") {
+		t.Errorf("combined script installs the dynamically linked ttyd package:\n%s", container.Args[0])
+	}
+}
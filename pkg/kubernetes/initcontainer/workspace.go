@@ -18,6 +18,18 @@ type WorkspaceInitializerConfig struct {
 	CloneDepth   int
 	SingleBranch bool
 	ExtraArgs    string
+	Attempts     int
+	MirrorURL    string
+
+	// Commit author set in the cloned repository's config (empty = unset)
+	GitUserName  string
+	GitUserEmail string
+
+	// Commit signing set in the cloned repository's config; the key itself
+	// is only mounted in the main container
+	GitSigning       bool
+	GitSigningFormat string
+	GitSigningKey    string
 
 	// WorkspaceVolumeName is the name of the volume to mount at /workspace
 	WorkspaceVolumeName string
@@ -35,6 +47,8 @@ func NewWorkspaceInitializerConfig(gitRepo, gitBranch string, opts *gitcmd.Clone
 		config.CloneDepth = opts.Depth
 		config.SingleBranch = opts.SingleBranch
 		config.ExtraArgs = opts.ExtraArgs
+		config.Attempts = opts.Attempts
+		config.MirrorURL = opts.MirrorURL
 	}
 
 	return config
@@ -46,6 +60,21 @@ func (w *WorkspaceInitializerConfig) WithWorkspaceVolume(volumeName string) *Wor
 	return w
 }
 
+// WithGitIdentity sets the commit author written to the cloned repository's config
+func (w *WorkspaceInitializerConfig) WithGitIdentity(name, email string) *WorkspaceInitializerConfig {
+	w.GitUserName = name
+	w.GitUserEmail = email
+	return w
+}
+
+// WithGitSigning makes the cloned repository sign commits with the mounted key
+func (w *WorkspaceInitializerConfig) WithGitSigning(enabled bool, format, key string) *WorkspaceInitializerConfig {
+	w.GitSigning = enabled
+	w.GitSigningFormat = format
+	w.GitSigningKey = key
+	return w
+}
+
 // IsEnabled returns true if workspace initialization should be performed
 func (w *WorkspaceInitializerConfig) IsEnabled() bool {
 	return w.GitRepo != ""
@@ -72,9 +101,15 @@ func (w *WorkspaceInitializerConfig) Args() []string {
 		Depth:        w.CloneDepth,
 		SingleBranch: w.SingleBranch,
 		ExtraArgs:    w.ExtraArgs,
+		Attempts:     w.Attempts,
+		MirrorURL:    w.MirrorURL,
 	}
 
 	script := gitcmd.BuildGitInitScript(w.GitRepo, w.GitBranch, opts)
+	script += gitcmd.BuildIdentityScript(gitcmd.Identity{Name: w.GitUserName, Email: w.GitUserEmail}, false)
+	if w.GitSigning {
+		script += gitcmd.BuildSigningScript(gitcmd.Signing{Format: w.GitSigningFormat, Key: w.GitSigningKey}, false)
+	}
 	return []string{script}
 }
 
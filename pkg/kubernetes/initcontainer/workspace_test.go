@@ -152,3 +152,21 @@ func TestWorkspaceInitializerBuilder(t *testing.T) {
 		t.Errorf("Expected 1 volume mount, got %d", len(container.VolumeMounts))
 	}
 }
+
+func TestWorkspaceInitializerConfig_GitIdentity(t *testing.T) {
+	config := NewWorkspaceInitializerConfig("https://github.com/example/repo.git", "", nil).
+		WithGitIdentity("Jane Doe", "jane@example.com")
+
+	script := config.Args()[0]
+	if !strings.Contains(script, "git -C /workspace config user.name 'Jane Doe'") {
+		t.Errorf("Expected script to set user.name, got:\n%s", script)
+	}
+	if !strings.Contains(script, "git -C /workspace config user.email 'jane@example.com'") {
+		t.Errorf("Expected script to set user.email, got:\n%s", script)
+	}
+
+	script = NewWorkspaceInitializerConfig("https://github.com/example/repo.git", "", nil).Args()[0]
+	if strings.Contains(script, "user.name") {
+		t.Errorf("Expected no identity without WithGitIdentity, got:\n%s", script)
+	}
+}
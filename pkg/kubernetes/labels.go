@@ -0,0 +1,44 @@
+package kubernetes
+
+import (
+	"maps"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// reservedLabels are set by kodama on session resources and select its pods,
+// so session labels cannot override them
+var reservedLabels = []string{"app", "session", "managed-by"}
+
+// withSessionLabels returns base with the session labels added
+// Reserved keys and labels Kubernetes would reject are skipped, see InvalidLabels.
+func withSessionLabels(base, sessionLabels map[string]string) map[string]string {
+	labels := make(map[string]string, len(base)+len(sessionLabels))
+	for key, value := range sessionLabels {
+		if slices.Contains(reservedLabels, key) || !isValidLabel(key, value) {
+			continue
+		}
+		labels[key] = value
+	}
+	maps.Copy(labels, base)
+	return labels
+}
+
+// InvalidLabels returns the sorted keys of labels that are not applied to
+// Kubernetes resources: reserved keys and invalid label keys or values
+func InvalidLabels(labels map[string]string) []string {
+	var invalid []string
+	for key, value := range labels {
+		if slices.Contains(reservedLabels, key) || !isValidLabel(key, value) {
+			invalid = append(invalid, key)
+		}
+	}
+	slices.Sort(invalid)
+	return invalid
+}
+
+// isValidLabel reports whether key=value is a valid Kubernetes label
+func isValidLabel(key, value string) bool {
+	return len(validation.IsQualifiedName(key)) == 0 && len(validation.IsValidLabelValue(value)) == 0
+}
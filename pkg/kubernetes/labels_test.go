@@ -0,0 +1,34 @@
+package kubernetes
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSessionLabels(t *testing.T) {
+	labels := withSessionLabels(map[string]string{"app": "kodama", "session": "work"}, map[string]string{
+		"team":        "payments",
+		"cost-center": "cc-1234",
+		"session":     "other",
+		"bad key":     "x",
+		"note":        "not a label value",
+	})
+
+	assert.Equal(t, map[string]string{
+		"app":         "kodama",
+		"session":     "work",
+		"team":        "payments",
+		"cost-center": "cc-1234",
+	}, labels)
+}
+
+func TestInvalidLabels(t *testing.T) {
+	assert.Empty(t, InvalidLabels(map[string]string{"team": "payments", "example.com/owner": "alice"}))
+	assert.Equal(t, []string{"bad key", "managed-by", "note"}, InvalidLabels(map[string]string{
+		"team":       "payments",
+		"managed-by": "me",
+		"bad key":    "x",
+		"note":       "not a label value",
+	}))
+}
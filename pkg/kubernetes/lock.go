@@ -0,0 +1,212 @@
+package kubernetes
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// LockAnnotation holds the session lock lease on the pod as JSON
+const LockAnnotation = "kodama.io/lock"
+
+// DefaultLockTTL is how long a lock lease is valid without renewal
+const DefaultLockTTL = 5 * time.Minute
+
+// ErrSessionLocked is returned when another user holds the session lock
+var ErrSessionLocked = errors.New("session is locked")
+
+// SessionLock is a lease on a session, taken by attach and agent runs so that
+// two users do not clobber each other's work
+type SessionLock struct {
+	Holder     string    `json:"holder"`
+	AcquiredAt time.Time `json:"acquiredAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// Expired reports whether the lease has run out at now
+func (l *SessionLock) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// LockHeldError reports the current holder of a session lock
+type LockHeldError struct {
+	Lock SessionLock
+}
+
+func (e *LockHeldError) Error() string {
+	return fmt.Sprintf("%v by %s since %s (expires %s)", ErrSessionLocked, e.Lock.Holder,
+		e.Lock.AcquiredAt.Local().Format(time.Kitchen), e.Lock.ExpiresAt.Local().Format(time.Kitchen))
+}
+
+func (e *LockHeldError) Unwrap() error {
+	return ErrSessionLocked
+}
+
+// LockHolder identifies this invocation as user@host/<id>. The random ID keeps
+// apart concurrent runs by the same user, e.g. agent runs through one 'serve'
+// process, so reuse the returned holder to renew and release a lock.
+func LockHolder() string {
+	name := "unknown"
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		name = u.Username
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		name += "@" + host
+	}
+
+	id := make([]byte, 4)
+	if _, err := rand.Read(id); err != nil {
+		return name
+	}
+	return name + "/" + hex.EncodeToString(id)
+}
+
+// AcquireSessionLock takes or renews the lock lease on a session pod for ttl.
+// A lease held by another holder is only taken over when it has expired or steal is set.
+func (c *Client) AcquireSessionLock(ctx context.Context, namespace, podName, holder string, ttl time.Duration, steal bool) (*SessionLock, error) {
+	var acquired *SessionLock
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pod, err := c.getPodForLock(ctx, namespace, podName)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		lock := &SessionLock{Holder: holder, AcquiredAt: now, ExpiresAt: now.Add(ttl)}
+		if current := sessionLockFromPod(pod); current != nil && !current.Expired(now) {
+			if current.Holder == holder {
+				lock.AcquiredAt = current.AcquiredAt
+			} else if !steal {
+				return &LockHeldError{Lock: *current}
+			}
+		}
+
+		data, err := json.Marshal(lock)
+		if err != nil {
+			return fmt.Errorf("failed to encode session lock: %w", err)
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[LockAnnotation] = string(data)
+
+		if _, err := c.clientset.CoreV1().Pods(namespace).Update(ctx, pod, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		acquired = lock
+		return nil
+	})
+	if err != nil {
+		var held *LockHeldError
+		if errors.As(err, &held) || errors.Is(err, ErrPodNotFound) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to acquire session lock: %w", err)
+	}
+	return acquired, nil
+}
+
+// ReleaseSessionLock removes the lock lease if holder still holds it
+func (c *Client) ReleaseSessionLock(ctx context.Context, namespace, podName, holder string) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		pod, err := c.getPodForLock(ctx, namespace, podName)
+		if err != nil {
+			return err
+		}
+
+		current := sessionLockFromPod(pod)
+		if current == nil || current.Holder != holder {
+			return nil
+		}
+
+		delete(pod.Annotations, LockAnnotation)
+		_, err = c.clientset.CoreV1().Pods(namespace).Update(ctx, pod, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil && !errors.Is(err, ErrPodNotFound) {
+		return fmt.Errorf("failed to release session lock: %w", err)
+	}
+	return nil
+}
+
+// SessionLocker takes and releases session lock leases, e.g. *Client
+type SessionLocker interface {
+	AcquireSessionLock(ctx context.Context, namespace, podName, holder string, ttl time.Duration, steal bool) (*SessionLock, error)
+	ReleaseSessionLock(ctx context.Context, namespace, podName, holder string) error
+}
+
+// HoldSessionLock acquires the session lock for holder and renews it every ttl/2
+// until the returned release function is called, which also releases the lock.
+// A failed renewal, e.g. because another user stole the lock, stops renewing
+// and is passed to onRenewError.
+func HoldSessionLock(ctx context.Context, locker SessionLocker, namespace, podName, holder string, ttl time.Duration, steal bool, onRenewError func(error)) (release func(), err error) {
+	if _, err := locker.AcquireSessionLock(ctx, namespace, podName, holder, ttl, steal); err != nil {
+		return nil, err
+	}
+
+	renewCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(ttl / 2)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-ticker.C:
+				// Never steal on renewal: whoever took the lock over wins
+				if _, err := locker.AcquireSessionLock(renewCtx, namespace, podName, holder, ttl, false); err != nil && renewCtx.Err() == nil {
+					if onRenewError != nil {
+						onRenewError(err)
+					}
+					return
+				}
+			}
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-done
+		// Release with a fresh context: ctx may already be cancelled by Ctrl+C
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer releaseCancel()
+		_ = locker.ReleaseSessionLock(releaseCtx, namespace, podName, holder)
+	}, nil
+}
+
+func (c *Client) getPodForLock(ctx context.Context, namespace, podName string) (*corev1.Pod, error) {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s in namespace %s", ErrPodNotFound, podName, namespace)
+		}
+		return nil, err
+	}
+	return pod, nil
+}
+
+// sessionLockFromPod parses the lock annotation; nil when unset or invalid
+func sessionLockFromPod(pod *corev1.Pod) *SessionLock {
+	value, ok := pod.Annotations[LockAnnotation]
+	if !ok {
+		return nil
+	}
+	var lock SessionLock
+	if err := json.Unmarshal([]byte(value), &lock); err != nil || lock.Holder == "" {
+		return nil
+	}
+	return &lock
+}
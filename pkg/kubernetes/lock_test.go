@@ -0,0 +1,142 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newLockTestClient() *Client {
+	return &Client{clientset: fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-work", Namespace: "dev"},
+	})}
+}
+
+func TestAcquireSessionLock(t *testing.T) {
+	client := newLockTestClient()
+	ctx := context.Background()
+
+	first, err := client.AcquireSessionLock(ctx, "dev", "kodama-work", "alice@laptop", time.Minute, false)
+	require.NoError(t, err)
+	assert.Equal(t, "alice@laptop", first.Holder)
+
+	// The holder can renew its own lease
+	renewed, err := client.AcquireSessionLock(ctx, "dev", "kodama-work", "alice@laptop", time.Minute, false)
+	require.NoError(t, err)
+	assert.Equal(t, first.AcquiredAt.Unix(), renewed.AcquiredAt.Unix())
+
+	// Others are rejected with the current holder
+	_, err = client.AcquireSessionLock(ctx, "dev", "kodama-work", "bob@desktop", time.Minute, false)
+	require.ErrorIs(t, err, ErrSessionLocked)
+	var held *LockHeldError
+	require.True(t, errors.As(err, &held))
+	assert.Equal(t, "alice@laptop", held.Lock.Holder)
+
+	// ... unless they steal it
+	stolen, err := client.AcquireSessionLock(ctx, "dev", "kodama-work", "bob@desktop", time.Minute, true)
+	require.NoError(t, err)
+	assert.Equal(t, "bob@desktop", stolen.Holder)
+
+	status, err := client.GetPod(ctx, "kodama-work", "dev")
+	require.NoError(t, err)
+	require.NotNil(t, status.Lock)
+	assert.Equal(t, "bob@desktop", status.Lock.Holder)
+}
+
+func TestAcquireSessionLock_Expired(t *testing.T) {
+	client := newLockTestClient()
+	ctx := context.Background()
+
+	_, err := client.AcquireSessionLock(ctx, "dev", "kodama-work", "alice@laptop", -time.Second, false)
+	require.NoError(t, err)
+
+	status, err := client.GetPod(ctx, "kodama-work", "dev")
+	require.NoError(t, err)
+	assert.Nil(t, status.Lock, "expired leases are not reported")
+
+	_, err = client.AcquireSessionLock(ctx, "dev", "kodama-work", "bob@desktop", time.Minute, false)
+	assert.NoError(t, err, "expired leases can be taken without --steal")
+}
+
+func TestReleaseSessionLock(t *testing.T) {
+	client := newLockTestClient()
+	ctx := context.Background()
+
+	_, err := client.AcquireSessionLock(ctx, "dev", "kodama-work", "alice@laptop", time.Minute, false)
+	require.NoError(t, err)
+
+	// Only the holder releases the lock
+	require.NoError(t, client.ReleaseSessionLock(ctx, "dev", "kodama-work", "bob@desktop"))
+	status, err := client.GetPod(ctx, "kodama-work", "dev")
+	require.NoError(t, err)
+	assert.NotNil(t, status.Lock)
+
+	require.NoError(t, client.ReleaseSessionLock(ctx, "dev", "kodama-work", "alice@laptop"))
+	status, err = client.GetPod(ctx, "kodama-work", "dev")
+	require.NoError(t, err)
+	assert.Nil(t, status.Lock)
+
+	assert.NoError(t, client.ReleaseSessionLock(ctx, "dev", "missing", "alice@laptop"), "missing pods are ignored")
+}
+
+func TestAcquireSessionLock_PodNotFound(t *testing.T) {
+	client := newLockTestClient()
+
+	_, err := client.AcquireSessionLock(context.Background(), "dev", "missing", "alice@laptop", time.Minute, false)
+	assert.ErrorIs(t, err, ErrPodNotFound)
+}
+
+func TestLockHolder_PerInvocation(t *testing.T) {
+	first, second := LockHolder(), LockHolder()
+	assert.NotEqual(t, first, second)
+
+	// Two runs by the same user exclude each other
+	client := newLockTestClient()
+	ctx := context.Background()
+	_, err := client.AcquireSessionLock(ctx, "dev", "kodama-work", first, time.Minute, false)
+	require.NoError(t, err)
+	_, err = client.AcquireSessionLock(ctx, "dev", "kodama-work", second, time.Minute, false)
+	assert.ErrorIs(t, err, ErrSessionLocked)
+}
+
+func TestHoldSessionLock(t *testing.T) {
+	client := newLockTestClient()
+	ctx := context.Background()
+	ttl := 200 * time.Millisecond
+
+	release, err := HoldSessionLock(ctx, client, "dev", "kodama-work", "alice@laptop/1", ttl, false, func(err error) {
+		t.Errorf("unexpected renewal error: %v", err)
+	})
+	require.NoError(t, err)
+
+	// Renewed past the original expiry
+	time.Sleep(2 * ttl)
+	_, err = client.AcquireSessionLock(ctx, "dev", "kodama-work", "alice@laptop/2", ttl, false)
+	assert.ErrorIs(t, err, ErrSessionLocked)
+
+	release()
+	status, err := client.GetPod(ctx, "kodama-work", "dev")
+	require.NoError(t, err)
+	assert.Nil(t, status.Lock)
+}
+
+func TestHoldSessionLock_Held(t *testing.T) {
+	client := newLockTestClient()
+	ctx := context.Background()
+	_, err := client.AcquireSessionLock(ctx, "dev", "kodama-work", "bob@desktop/1", time.Minute, false)
+	require.NoError(t, err)
+
+	_, err = HoldSessionLock(ctx, client, "dev", "kodama-work", "alice@laptop/1", time.Minute, false, nil)
+	assert.ErrorIs(t, err, ErrSessionLocked)
+
+	release, err := HoldSessionLock(ctx, client, "dev", "kodama-work", "alice@laptop/1", time.Minute, true, nil)
+	require.NoError(t, err)
+	release()
+}
@@ -0,0 +1,29 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// StreamPodLogs opens a log stream for a container in a pod
+// If follow is true, the stream stays open until ctx is canceled or the container exits.
+// tailLines limits the initial output (0 = all lines).
+func (c *Client) StreamPodLogs(ctx context.Context, name, namespace, container string, follow bool, tailLines int64) (io.ReadCloser, error) {
+	opts := &corev1.PodLogOptions{
+		Container: container,
+		Follow:    follow,
+	}
+	if tailLines > 0 {
+		opts.TailLines = &tailLines
+	}
+
+	stream, err := c.clientset.CoreV1().Pods(namespace).GetLogs(name, opts).Stream(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stream pod logs: %w", err)
+	}
+
+	return stream, nil
+}
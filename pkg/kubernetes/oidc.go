@@ -0,0 +1,105 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthv1 "k8s.io/client-go/pkg/apis/clientauthentication/v1"
+)
+
+// ExecCredentialAPIVersion is the exec credential plugin API served by 'kubectl-kodama credential'
+const ExecCredentialAPIVersion = "client.authentication.k8s.io/v1"
+
+// GitHub Actions exposes the OIDC token endpoint to jobs with 'id-token: write'
+const (
+	envActionsTokenURL   = "ACTIONS_ID_TOKEN_REQUEST_URL"
+	envActionsTokenToken = "ACTIONS_ID_TOKEN_REQUEST_TOKEN" // #nosec G101 -- variable name, not a credential
+)
+
+// GitHubOIDCToken requests a GitHub Actions OIDC token for audience
+// Returns the token and its expiry (zero if the token has no exp claim).
+func GitHubOIDCToken(ctx context.Context, audience string) (string, time.Time, error) {
+	requestURL, requestToken := os.Getenv(envActionsTokenURL), os.Getenv(envActionsTokenToken)
+	if requestURL == "" || requestToken == "" {
+		return "", time.Time{}, fmt.Errorf("GitHub Actions OIDC is unavailable: %s is not set (does the job have 'permissions: id-token: write'?)", envActionsTokenURL)
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("invalid %s: %w", envActionsTokenURL, err)
+	}
+	query := u.Query()
+	query.Set("audience", audience)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+requestToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to request OIDC token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", time.Time{}, fmt.Errorf("OIDC token request failed: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var payload struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to decode OIDC token response: %w", err)
+	}
+	if payload.Value == "" {
+		return "", time.Time{}, fmt.Errorf("OIDC token response did not contain a token")
+	}
+
+	return payload.Value, jwtExpiry(payload.Value), nil
+}
+
+// jwtExpiry reads the exp claim of a JWT without verifying it
+func jwtExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	data, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(data, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// ExecCredential renders token as an ExecCredential for kubectl and client-go
+func ExecCredential(token string, expiry time.Time) ([]byte, error) {
+	cred := clientauthv1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{APIVersion: ExecCredentialAPIVersion, Kind: "ExecCredential"},
+		Status:   &clientauthv1.ExecCredentialStatus{Token: token},
+	}
+	if !expiry.IsZero() {
+		exp := metav1.NewTime(expiry)
+		cred.Status.ExpirationTimestamp = &exp
+	}
+	return json.Marshal(cred)
+}
@@ -0,0 +1,62 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testJWT(exp int64) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"sub":"repo:org/app","exp":%d}`, exp)))
+	return "eyJhbGciOiJSUzI1NiJ9." + payload + ".sig"
+}
+
+func TestGitHubOIDCToken(t *testing.T) {
+	exp := time.Now().Add(5 * time.Minute).Unix()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer request-token", r.Header.Get("Authorization"))
+		assert.Equal(t, "kodama", r.URL.Query().Get("audience"))
+		assert.Equal(t, "1", r.URL.Query().Get("api-version"))
+		_ = json.NewEncoder(w).Encode(map[string]string{"value": testJWT(exp)})
+	}))
+	defer server.Close()
+
+	t.Setenv(envActionsTokenURL, server.URL+"?api-version=1")
+	t.Setenv(envActionsTokenToken, "request-token")
+
+	token, expiry, err := GitHubOIDCToken(context.Background(), "kodama")
+	require.NoError(t, err)
+	assert.Equal(t, testJWT(exp), token)
+	assert.Equal(t, exp, expiry.Unix())
+}
+
+func TestGitHubOIDCToken_Unavailable(t *testing.T) {
+	t.Setenv(envActionsTokenURL, "")
+	t.Setenv(envActionsTokenToken, "")
+
+	_, _, err := GitHubOIDCToken(context.Background(), "kodama")
+	assert.ErrorContains(t, err, "id-token: write")
+}
+
+func TestExecCredential(t *testing.T) {
+	expiry := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	data, err := ExecCredential("tok", expiry)
+	require.NoError(t, err)
+
+	var cred map[string]any
+	require.NoError(t, json.Unmarshal(data, &cred))
+	assert.Equal(t, "ExecCredential", cred["kind"])
+	assert.Equal(t, ExecCredentialAPIVersion, cred["apiVersion"])
+	status := cred["status"].(map[string]any)
+	assert.Equal(t, "tok", status["token"])
+	assert.Equal(t, "2030-01-01T00:00:00Z", status["expirationTimestamp"])
+}
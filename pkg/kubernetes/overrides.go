@@ -0,0 +1,45 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+)
+
+// ApplyPodOverrides strategic-merge-patches overrides (PodSpec fields as decoded
+// from YAML) onto spec. Lists with a merge key, such as containers and volumes,
+// are merged by name; other fields are replaced. Unknown fields are rejected so
+// that typos do not silently do nothing.
+func ApplyPodOverrides(spec *corev1.PodSpec, overrides map[string]interface{}) error {
+	if len(overrides) == 0 {
+		return nil
+	}
+
+	patch, err := json.Marshal(overrides)
+	if err != nil {
+		return fmt.Errorf("invalid pod overrides: %w", err)
+	}
+
+	original, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to encode pod spec: %w", err)
+	}
+
+	patched, err := strategicpatch.StrategicMergePatch(original, patch, corev1.PodSpec{})
+	if err != nil {
+		return fmt.Errorf("failed to apply pod overrides: %w", err)
+	}
+
+	var result corev1.PodSpec
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&result); err != nil {
+		return fmt.Errorf("invalid pod overrides: %w", err)
+	}
+
+	*spec = result
+	return nil
+}
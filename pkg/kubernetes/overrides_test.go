@@ -0,0 +1,58 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreatePod_PodOverrides(t *testing.T) {
+	var overrides map[string]interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+priorityClassName: high
+hostAliases:
+  - ip: 10.0.0.1
+    hostnames: [registry.internal]
+dnsConfig:
+  options:
+    - name: ndots
+      value: "2"
+containers:
+  - name: claude-code
+    securityContext:
+      runAsNonRoot: true
+`), &overrides))
+
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	pod, err := client.CreatePod(context.Background(), &PodSpec{
+		Name:         "kodama-work",
+		Namespace:    "dev",
+		Image:        "kodama:test",
+		PodOverrides: overrides,
+	}, true)
+	require.NoError(t, err)
+
+	assert.Equal(t, "high", pod.Spec.PriorityClassName)
+	assert.Equal(t, []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"registry.internal"}}}, pod.Spec.HostAliases)
+	require.NotNil(t, pod.Spec.DNSConfig)
+	assert.Equal(t, "ndots", pod.Spec.DNSConfig.Options[0].Name)
+
+	// Containers are merged by name, keeping the generated fields
+	require.Len(t, pod.Spec.Containers, 1)
+	main := pod.Spec.Containers[0]
+	assert.Equal(t, "kodama:test", main.Image)
+	require.NotNil(t, main.SecurityContext)
+	assert.True(t, *main.SecurityContext.RunAsNonRoot)
+	assert.NotEmpty(t, pod.Spec.Volumes, "volumes are kept")
+}
+
+func TestApplyPodOverrides_UnknownField(t *testing.T) {
+	spec := &corev1.PodSpec{}
+	err := ApplyPodOverrides(spec, map[string]interface{}{"priorityClass": "high"})
+	assert.Error(t, err)
+}
@@ -3,6 +3,8 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -15,21 +17,73 @@ import (
 	"github.com/illumination-k/kodama/pkg/kubernetes/initcontainer"
 )
 
+// ExpiresAtAnnotation holds the session expiration time (RFC3339, UTC)
+// Read by the reaper CronJob installed via 'kodama install-reaper'
+const ExpiresAtAnnotation = "kodama.io/expires-at"
+
+// ToolsInstallerName is the init container that puts Claude Code and ttyd into /kodama/bin
+const ToolsInstallerName = "tools-installer"
+
+// bundleVolumeName is the pod volume holding prebuilt binaries for the configmap and pvc sources
+const bundleVolumeName = "kodama-bundle"
+
+// isBundleSource reports whether binaries are copied rather than downloaded
+func isBundleSource(source string) bool {
+	return source == initcontainer.SourceConfigMap || source == initcontainer.SourcePVC || source == initcontainer.SourceImage
+}
+
+// bundleVolume returns the volume with prebuilt binaries for the configmap and pvc sources
+// The image source needs no volume, its init container runs the image itself.
+func bundleVolume(spec *PodSpec) (corev1.Volume, bool) {
+	switch spec.InstallerSource {
+	case initcontainer.SourceConfigMap:
+		mode := int32(0o755)
+		return corev1.Volume{
+			Name: bundleVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: spec.InstallerConfigMap},
+					DefaultMode:          &mode,
+				},
+			},
+		}, true
+	case initcontainer.SourcePVC:
+		return corev1.Volume{
+			Name: bundleVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: spec.InstallerPVC,
+					ReadOnly:  true,
+				},
+			},
+		}, true
+	}
+	return corev1.Volume{}, false
+}
+
 // buildInitContainers creates all required init containers based on PodSpec
 func buildInitContainers(spec *PodSpec) []corev1.Container {
 	builder := initcontainer.NewBuilder()
 	containers := make([]corev1.Container, 0, 2) // Pre-allocate for tools-installer + workspace-initializer
 
 	// Combine tool installers (Claude + ttyd) into a single init container for efficiency
-	toolConfigs := []initcontainer.InstallerConfig{
-		initcontainer.NewClaudeInstallerConfig("latest", "kodama-bin"),
-	}
-
-	if spec.TtydEnabled {
-		toolConfigs = append(toolConfigs, initcontainer.NewTtydInstallerConfig("1.7.7", "kodama-bin"))
+	var toolConfigs []initcontainer.InstallerConfig
+	if isBundleSource(spec.InstallerSource) {
+		// Air-gapped clusters: copy prebuilt binaries instead of downloading them
+		binaries := []string{"claude"}
+		if spec.TtydEnabled {
+			binaries = append(binaries, "ttyd")
+		}
+		toolConfigs = append(toolConfigs, initcontainer.NewBundleInstallerConfig(
+			spec.InstallerSource, spec.InstallerImage, spec.InstallerPath, "kodama-bin", bundleVolumeName, binaries...))
+	} else {
+		toolConfigs = append(toolConfigs, initcontainer.NewClaudeInstallerConfig(ClaudeVersion, "kodama-bin"))
+		if spec.TtydEnabled {
+			toolConfigs = append(toolConfigs, initcontainer.NewTtydInstallerConfig("1.7.7", "kodama-bin"))
+		}
 	}
 
-	containers = append(containers, builder.BuildCombined("tools-installer", toolConfigs...))
+	containers = append(containers, builder.BuildCombined(ToolsInstallerName, toolConfigs...))
 
 	// Add workspace initializer if git repo specified
 	if spec.GitRepo != "" {
@@ -37,12 +91,26 @@ func buildInitContainers(spec *PodSpec) []corev1.Container {
 			Depth:        spec.GitCloneDepth,
 			SingleBranch: spec.GitSingleBranch,
 			ExtraArgs:    spec.GitCloneArgs,
+			Attempts:     spec.GitCloneAttempts,
+			MirrorURL:    spec.GitMirror,
 		}
 		workspaceConfig := initcontainer.NewWorkspaceInitializerConfig(spec.GitRepo, spec.GitBranch, opts).
-			WithWorkspaceVolume("workspace")
+			WithWorkspaceVolume("workspace").
+			WithGitIdentity(spec.GitUserName, spec.GitUserEmail).
+			WithGitSigning(spec.GitSigningSecret != "", spec.GitSigningFormat, spec.GitSigningKey)
 		containers = append(containers, builder.Build(workspaceConfig))
 	}
 
+	// Fetch the dataset once the repository is cloned, since the clone needs an empty workspace
+	if spec.DataURI != "" {
+		containers = append(containers, builder.Build(dataFetcherConfig(spec)))
+	}
+
+	// Install toolchains with the session image, so they match its libc
+	if len(spec.Tools) > 0 {
+		containers = append(containers, builder.Build(toolchainInstallerConfig(spec)))
+	}
+
 	return containers
 }
 
@@ -68,8 +136,13 @@ func (c *Client) CreatePod(ctx context.Context, spec *PodSpec, dryRun bool) (*co
 		if spec.TtydOptions != "" {
 			ttydCmd += " " + spec.TtydOptions
 		}
-		ttydCmd += " bash"
-		containerCommand = []string{"/bin/bash", "-c", ttydCmd}
+		// Each connection runs the session shell, which the image may not have as bash
+		if spec.RecordTerminal {
+			ttydCmd += " /bin/sh -c '" + RecordedShellScript(spec.Shell) + "'"
+		} else {
+			ttydCmd += " /bin/sh -c '" + InteractiveShellScript(spec.Shell) + "'"
+		}
+		containerCommand = []string{"/bin/sh", "-c", ttydCmd}
 	}
 
 	pod := &corev1.Pod{
@@ -80,16 +153,16 @@ func (c *Client) CreatePod(ctx context.Context, spec *PodSpec, dryRun bool) (*co
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      spec.Name,
 			Namespace: spec.Namespace,
-			Labels: map[string]string{
+			Labels: withSessionLabels(map[string]string{
 				"app":     "kodama",
 				"session": spec.Name,
-			},
+			}, spec.Labels),
 		},
 		Spec: corev1.PodSpec{
 			InitContainers: initContainers,
 			Containers: []corev1.Container{
 				{
-					Name:       "claude-code",
+					Name:       MainContainerName,
 					Image:      spec.Image,
 					Command:    containerCommand,
 					WorkingDir: "/workspace",
@@ -99,6 +172,15 @@ func (c *Client) CreatePod(ctx context.Context, spec *PodSpec, dryRun bool) (*co
 			RestartPolicy: corev1.RestartPolicyNever,
 		},
 	}
+	if spec.RestartPolicy != "" {
+		pod.Spec.RestartPolicy = corev1.RestartPolicy(spec.RestartPolicy)
+	}
+
+	if spec.ExpiresAt != nil {
+		pod.Annotations = map[string]string{
+			ExpiresAtAnnotation: spec.ExpiresAt.UTC().Format(time.RFC3339),
+		}
+	}
 
 	// Add ttyd port if enabled
 	if spec.TtydEnabled {
@@ -127,6 +209,21 @@ func (c *Client) CreatePod(ctx context.Context, spec *PodSpec, dryRun bool) (*co
 		},
 	}
 
+	// A UTF-8 locale for ttyd, attach and the agent, so box-drawing and other
+	// non-ASCII characters render
+	locale := LocaleEnv(spec.Locale)
+	pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env,
+		corev1.EnvVar{Name: "LANG", Value: locale["LANG"]},
+		corev1.EnvVar{Name: "LC_ALL", Value: locale["LC_ALL"]},
+	)
+
+	if spec.Editor != "" {
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env,
+			corev1.EnvVar{Name: "EDITOR", Value: spec.Editor},
+			corev1.EnvVar{Name: "VISUAL", Value: spec.Editor},
+		)
+	}
+
 	// Inject environment variables from dotenv secret if specified
 	if spec.EnvSecretName != "" {
 		pod.Spec.Containers[0].EnvFrom = append(pod.Spec.Containers[0].EnvFrom,
@@ -221,9 +318,90 @@ func (c *Client) CreatePod(ctx context.Context, spec *PodSpec, dryRun bool) (*co
 		}
 	}
 
+	// Shared dependency cache, with package managers pointed at it
+	if spec.CachePVC != "" {
+		volume, mount := cacheVolume(spec.CachePVC)
+		volumes = append(volumes, volume)
+		volumeMounts = append(volumeMounts, mount)
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, CacheEnvVars()...)
+	}
+
+	// Scratch volume shared with the other sessions of the namespace
+	if spec.SharedPVC != "" {
+		volume, mount := sharedVolume(spec.SharedPVC)
+		volumes = append(volumes, volume)
+		volumeMounts = append(volumeMounts, mount)
+		pod.Spec.Containers[0].Env = append(pod.Spec.Containers[0].Env, corev1.EnvVar{Name: SharedEnvVar, Value: SharedMountPath})
+	}
+
+	// Prebuilt tool binaries for the bundle installer
+	if volume, ok := bundleVolume(spec); ok {
+		volumes = append(volumes, volume)
+	}
+
+	// Credentials files for the data fetcher
+	if volume, ok := dataCredentialsVolume(spec); ok {
+		volumes = append(volumes, volume)
+	}
+
+	// Editor config files
+	if spec.EditorConfigMap != "" && len(spec.EditorConfigFiles) > 0 {
+		volume, mounts := editorConfigVolume(spec.EditorConfigMap, spec.EditorConfigFiles)
+		volumes = append(volumes, volume)
+		volumeMounts = append(volumeMounts, mounts...)
+	}
+
+	// Template volumes, e.g. dataset PVCs
+	for _, v := range spec.Volumes {
+		volume, mount := userVolume(v)
+		volumes = append(volumes, volume)
+		volumeMounts = append(volumeMounts, mount)
+	}
+
 	pod.Spec.Volumes = volumes
 	pod.Spec.Containers[0].VolumeMounts = volumeMounts
 
+	// Browser-based VS Code sharing the workspace volume
+	if spec.CodeServerEnabled {
+		container, err := codeServerContainer(spec.CodeServerImage, spec.CodeServerPort)
+		if err != nil {
+			return nil, err
+		}
+		pod.Spec.Containers = append(pod.Spec.Containers, container)
+
+		// kubectl exec and logs target the main container without -c
+		if pod.Annotations == nil {
+			pod.Annotations = map[string]string{}
+		}
+		pod.Annotations[defaultContainerAnnotation] = MainContainerName
+	}
+
+	// Restart ttyd or the session command when it dies, see WatchdogScript
+	applyWatchdog(pod, spec)
+
+	// Toolchains installed by the toolchain installer, before the checkpointer copies the environment
+	applyToolchain(pod, spec)
+
+	// Writable copy of the workspace for the agent, with the canonical tree read-only
+	applySandbox(pod, spec)
+
+	// Spot tolerations and the workspace checkpointer, which picks up the network settings below
+	applySpotFriendly(pod, spec)
+
+	// Keep the cluster autoscaler from evicting the pod to scale down its node
+	applyNoEvict(pod, spec)
+
+	// Proxy and extra CA bundle, so the Claude installer, git clone and package managers work behind corporate proxies
+	applyNetworkSettings(pod, spec)
+
+	// Commit signing key for the main container
+	applyGitSigning(pod, spec)
+
+	// User-supplied fields kodama does not model, applied last so they win
+	if err := ApplyPodOverrides(&pod.Spec, spec.PodOverrides); err != nil {
+		return nil, err
+	}
+
 	// If dry-run, return the manifest without creating
 	if dryRun {
 		return pod, nil
@@ -234,7 +412,7 @@ func (c *Client) CreatePod(ctx context.Context, spec *PodSpec, dryRun bool) (*co
 		if errors.IsAlreadyExists(err) {
 			return nil, fmt.Errorf("pod %s already exists in namespace %s", spec.Name, spec.Namespace)
 		}
-		return nil, fmt.Errorf("failed to create pod %s in namespace %s: %w", spec.Name, spec.Namespace, err)
+		return nil, fmt.Errorf("failed to create pod %s in namespace %s: %w", spec.Name, spec.Namespace, wrapQuotaError(err, spec.Namespace))
 	}
 
 	return pod, nil
@@ -288,11 +466,33 @@ func (c *Client) GetPod(ctx context.Context, name, namespace string) (*PodStatus
 	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return nil, fmt.Errorf("pod %s not found in namespace %s", name, namespace)
+			return nil, fmt.Errorf("%w: %s in namespace %s", ErrPodNotFound, name, namespace)
 		}
 		return nil, fmt.Errorf("failed to get pod %s in namespace %s: %w", name, namespace, err)
 	}
 
+	return podStatusFromPod(pod), nil
+}
+
+// ListSessionPods returns the status of every kodama pod in namespace, keyed by pod name
+// One labeled List call replaces a Get per session when listing many sessions.
+func (c *Client) ListSessionPods(ctx context.Context, namespace string) (map[string]*PodStatus, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=kodama",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+
+	statuses := make(map[string]*PodStatus, len(pods.Items))
+	for i := range pods.Items {
+		statuses[pods.Items[i].Name] = podStatusFromPod(&pods.Items[i])
+	}
+	return statuses, nil
+}
+
+// podStatusFromPod summarizes a pod as a PodStatus
+func podStatusFromPod(pod *corev1.Pod) *PodStatus {
 	status := &PodStatus{
 		Phase:      pod.Status.Phase,
 		IP:         pod.Status.PodIP,
@@ -312,7 +512,45 @@ func (c *Client) GetPod(ctx context.Context, name, namespace string) (*PodStatus
 		}
 	}
 
-	return status, nil
+	if lock := sessionLockFromPod(pod); lock != nil && !lock.Expired(time.Now()) {
+		status.Lock = lock
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if container.Name == MainContainerName {
+			status.Image = container.Image
+			status.Limits = container.Resources.Limits
+		}
+	}
+
+	return status
+}
+
+// PodSpecChanges returns the settings of spec that differ from the running pod:
+// "image" and the names of resources whose limits differ
+// Such changes only take effect when the pod is recreated.
+func (c *Client) PodSpecChanges(status *PodStatus, spec *PodSpec) []string {
+	var changes []string
+	if status.Image != spec.Image {
+		changes = append(changes, "image")
+	}
+
+	limits := c.buildResourceRequirements(spec.CPULimit, spec.MemoryLimit, spec.CustomResources).Limits
+	names := map[corev1.ResourceName]bool{}
+	for name := range limits {
+		names[name] = true
+	}
+	for name := range status.Limits {
+		names[name] = true
+	}
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		want, wantOK := limits[name]
+		got, gotOK := status.Limits[name]
+		if wantOK != gotOK || want.Cmp(got) != 0 {
+			changes = append(changes, string(name))
+		}
+	}
+	return changes
 }
 
 // WaitForPodReady polls the pod until it reaches Ready state
@@ -329,8 +567,27 @@ func (c *Client) WaitForPodReady(ctx context.Context, name, namespace string, ti
 	}
 	defer watcher.Stop()
 
+	// The data fetcher's output is polled while it runs if the caller wants progress
+	progress := initProgressFor(ctx)
+	var tick <-chan time.Time
+	if progress != nil {
+		ticker := time.NewTicker(initProgressInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	fetching, lastLine := false, ""
+
 	for {
 		select {
+		case <-tick:
+			if !fetching {
+				continue
+			}
+			if line := c.lastLogLine(ctx, name, namespace, DataFetcherName); line != "" && line != lastLine {
+				lastLine = line
+				progress(DataFetcherName, line)
+			}
+
 		case event, ok := <-watcher.ResultChan():
 			if !ok {
 				return fmt.Errorf("watch channel closed unexpectedly for pod %s", name)
@@ -344,6 +601,7 @@ func (c *Client) WaitForPodReady(ctx context.Context, name, namespace string, ti
 			if !ok {
 				continue
 			}
+			fetching = dataFetcherRunning(pod)
 
 			// Check if pod is ready
 			for _, condition := range pod.Status.Conditions {
@@ -354,20 +612,37 @@ func (c *Client) WaitForPodReady(ctx context.Context, name, namespace string, ti
 
 			// Check for pod failure
 			if pod.Status.Phase == corev1.PodFailed {
-				return fmt.Errorf("pod %s failed: %s", name, pod.Status.Message)
+				return podFailedError(pod)
 			}
 
 		case <-ctx.Done():
+			if ctx.Err() == context.Canceled {
+				return fmt.Errorf("waiting for pod %s canceled: %w", name, ctx.Err())
+			}
+
 			// Timeout - get pod events for debugging
-			events, err := c.getPodEvents(context.Background(), name, namespace)
+			events, err := c.getPodEvents(context.WithoutCancel(ctx), name, namespace)
 			if err != nil {
-				return fmt.Errorf("pod %s did not become ready within %v", name, timeout)
+				return NewPodNotReadyError(name, namespace, fmt.Sprintf("did not become ready within %v", timeout))
 			}
-			return fmt.Errorf("pod %s did not become ready within %v. Recent events:\n%s", name, timeout, events)
+			return NewPodNotReadyError(name, namespace, fmt.Sprintf("did not become ready within %v. Recent events:\n%s", timeout, events))
 		}
 	}
 }
 
+// podFailedError classifies a failed pod, reporting repository setup failures as ErrCloneFailed
+func podFailedError(pod *corev1.Pod) error {
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name != workspaceInitializerName {
+			continue
+		}
+		if terminated := status.State.Terminated; terminated != nil && terminated.ExitCode != 0 {
+			return newCloneFailedError(pod.Name, pod.Namespace, terminated)
+		}
+	}
+	return NewPodNotReadyError(pod.Name, pod.Namespace, fmt.Sprintf("failed: %s", pod.Status.Message))
+}
+
 // getPodEvents retrieves recent events for a pod
 func (c *Client) getPodEvents(ctx context.Context, name, namespace string) (string, error) {
 	events, err := c.clientset.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
@@ -407,6 +682,22 @@ func (c *Client) DeletePod(ctx context.Context, name, namespace string) error {
 	return nil
 }
 
+// DeletePodKeepingDependents deletes a pod that is replaced by a new one,
+// orphaning the secrets it owns (see SetSecretsOwner) instead of letting
+// garbage collection delete them, so the replacement pod can use them
+func (c *Client) DeletePodKeepingDependents(ctx context.Context, name, namespace string) error {
+	gracePeriod := int64(30)
+	orphan := metav1.DeletePropagationOrphan
+	err := c.clientset.CoreV1().Pods(namespace).Delete(ctx, name, metav1.DeleteOptions{
+		GracePeriodSeconds: &gracePeriod,
+		PropagationPolicy:  &orphan,
+	})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete pod %s in namespace %s: %w", name, namespace, err)
+	}
+	return nil
+}
+
 // WaitForPodDeleted waits for a pod to be fully deleted from the cluster
 func (c *Client) WaitForPodDeleted(ctx context.Context, name, namespace string, timeout time.Duration) error {
 	ctx, cancel := context.WithTimeout(ctx, timeout)
@@ -0,0 +1,188 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestListSessionPods(t *testing.T) {
+	ready := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-a", Namespace: "dev", Labels: map[string]string{"app": "kodama"}},
+		Status: corev1.PodStatus{
+			Phase:      corev1.PodRunning,
+			Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	pending := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-b", Namespace: "dev", Labels: map[string]string{"app": "kodama"}},
+		Status:     corev1.PodStatus{Phase: corev1.PodPending},
+	}
+	unrelated := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "dev", Labels: map[string]string{"app": "web"}},
+	}
+	otherNamespace := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-c", Namespace: "prod", Labels: map[string]string{"app": "kodama"}},
+	}
+	client := &Client{clientset: fake.NewSimpleClientset(ready, pending, unrelated, otherNamespace)}
+
+	pods, err := client.ListSessionPods(context.Background(), "dev")
+	require.NoError(t, err)
+
+	assert.Len(t, pods, 2)
+	assert.True(t, pods["kodama-a"].Ready)
+	assert.False(t, pods["kodama-b"].Ready)
+	assert.Equal(t, corev1.PodPending, pods["kodama-b"].Phase)
+}
+
+func TestCreatePod_InstallerSource(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	tests := []struct {
+		name       string
+		spec       PodSpec
+		wantImage  string
+		wantVolume bool
+	}{
+		{"download", PodSpec{}, "ubuntu:24.04", false},
+		{"configmap", PodSpec{InstallerSource: "configmap", InstallerConfigMap: "kodama-tools"}, "ubuntu:24.04", true},
+		{"pvc", PodSpec{InstallerSource: "pvc", InstallerPVC: "kodama-tools"}, "ubuntu:24.04", true},
+		{"image", PodSpec{InstallerSource: "image", InstallerImage: "registry.corp/kodama-tools:1.0"}, "registry.corp/kodama-tools:1.0", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := tt.spec
+			spec.Name, spec.Namespace, spec.Image, spec.TtydEnabled = "kodama-test", "dev", "kodama:test", true
+
+			pod, err := client.CreatePod(context.Background(), &spec, true)
+			require.NoError(t, err)
+
+			installer := pod.Spec.InitContainers[0]
+			assert.Equal(t, ToolsInstallerName, installer.Name)
+			assert.Equal(t, tt.wantImage, installer.Image)
+			if tt.name == "download" {
+				assert.Contains(t, installer.Args[0], "claude.ai/install.sh")
+			} else {
+				assert.NotContains(t, installer.Args[0], "curl")
+				assert.Contains(t, installer.Args[0], "/ttyd")
+			}
+
+			var found bool
+			for _, volume := range pod.Spec.Volumes {
+				if volume.Name == bundleVolumeName {
+					found = true
+				}
+			}
+			assert.Equal(t, tt.wantVolume, found)
+		})
+	}
+}
+
+func TestCreatePod_RestartPolicy(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	spec := &PodSpec{Name: "kodama-test", Namespace: "dev", Image: "kodama:test"}
+
+	pod, err := client.CreatePod(context.Background(), spec, true)
+	require.NoError(t, err)
+	assert.Equal(t, corev1.RestartPolicyNever, pod.Spec.RestartPolicy)
+
+	spec.RestartPolicy = "OnFailure"
+	pod, err = client.CreatePod(context.Background(), spec, true)
+	require.NoError(t, err)
+	assert.Equal(t, corev1.RestartPolicyOnFailure, pod.Spec.RestartPolicy)
+}
+
+func TestCreatePod_GitSigning(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	spec := &PodSpec{
+		Name: "kodama-test", Namespace: "dev", Image: "kodama:test",
+		GitRepo: "https://github.com/org/repo", GitSigningSecret: "git-signing", GitSigningFormat: "ssh",
+	}
+
+	pod, err := client.CreatePod(context.Background(), spec, true)
+	require.NoError(t, err)
+
+	var volume *corev1.Volume
+	for i := range pod.Spec.Volumes {
+		if pod.Spec.Volumes[i].Name == signingKeyVolumeName {
+			volume = &pod.Spec.Volumes[i]
+		}
+	}
+	require.NotNil(t, volume)
+	assert.Equal(t, "git-signing", volume.Secret.SecretName)
+	assert.Equal(t, int32(0o400), *volume.Secret.DefaultMode)
+
+	// Only the main container gets the key; the initializer just writes git config
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		mounted := false
+		for _, mount := range container.VolumeMounts {
+			mounted = mounted || mount.Name == signingKeyVolumeName
+		}
+		assert.Equal(t, container.Name == MainContainerName, mounted, container.Name)
+		if container.Name == "workspace-initializer" {
+			assert.Contains(t, container.Args[0], "config commit.gpgsign 'true'")
+		}
+	}
+}
+
+func TestCreatePod_Volumes(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	spec := &PodSpec{
+		Name: "kodama-test", Namespace: "dev", Image: "kodama:test",
+		Volumes: []VolumeMountSpec{
+			{Name: "datasets", MountPath: "/workspace/data", ReadOnly: true, PVC: "imagenet"},
+			{Name: "models", MountPath: "/models", SubPath: "v2", HostPath: "/mnt/models"},
+		},
+	}
+
+	pod, err := client.CreatePod(context.Background(), spec, true)
+	require.NoError(t, err)
+
+	volumes := map[string]corev1.Volume{}
+	for _, volume := range pod.Spec.Volumes {
+		volumes[volume.Name] = volume
+	}
+	require.Contains(t, volumes, "vol-datasets")
+	assert.Equal(t, "imagenet", volumes["vol-datasets"].PersistentVolumeClaim.ClaimName)
+	assert.True(t, volumes["vol-datasets"].PersistentVolumeClaim.ReadOnly)
+	require.Contains(t, volumes, "vol-models")
+	assert.Equal(t, "/mnt/models", volumes["vol-models"].HostPath.Path)
+
+	assert.Contains(t, pod.Spec.Containers[0].VolumeMounts,
+		corev1.VolumeMount{Name: "vol-datasets", MountPath: "/workspace/data", ReadOnly: true})
+	assert.Contains(t, pod.Spec.Containers[0].VolumeMounts,
+		corev1.VolumeMount{Name: "vol-models", MountPath: "/models", SubPath: "v2"})
+}
+
+func TestPodSpecChanges(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	ctx := context.Background()
+	spec := &PodSpec{
+		Name:            "kodama-work",
+		Namespace:       "dev",
+		Image:           "ubuntu:24.04",
+		CPULimit:        "2",
+		MemoryLimit:     "4Gi",
+		CustomResources: map[string]string{"nvidia.com/gpu": "1"},
+	}
+	_, err := client.CreatePod(ctx, spec, false)
+	require.NoError(t, err)
+
+	status, err := client.GetPod(ctx, "kodama-work", "dev")
+	require.NoError(t, err)
+	assert.Equal(t, "ubuntu:24.04", status.Image)
+	assert.Empty(t, client.PodSpecChanges(status, spec))
+
+	changed := *spec
+	changed.Image = "ubuntu:25.04"
+	changed.MemoryLimit = "4096Mi" // Same quantity, different notation
+	changed.CPULimit = "4"
+	changed.CustomResources = nil
+	assert.Equal(t, []string{"image", "cpu", "nvidia.com/gpu"}, client.PodSpecChanges(status, &changed))
+}
@@ -6,20 +6,22 @@ import (
 	"net"
 	"os/exec"
 	"time"
+
+	"github.com/illumination-k/kodama/pkg/procutil"
 )
 
 // StartPortForward starts kubectl port-forward and waits for it to be ready
-func (c *Client) StartPortForward(ctx context.Context, podName string, localPort, remotePort int) (*exec.Cmd, error) {
+func (c *Client) StartPortForward(ctx context.Context, namespace, podName string, localPort, remotePort int) (*exec.Cmd, error) {
 	// Construct the kubectl port-forward command
 	args := []string{
 		"port-forward",
-		"-n", c.config.Namespace,
+		"-n", namespace,
 		podName,
 		fmt.Sprintf("%d:%d", localPort, remotePort),
 	}
 
 	//#nosec G204 -- kubectl port-forward with validated session data from config
-	cmd := exec.CommandContext(ctx, "kubectl", args...)
+	cmd := procutil.CommandContext(ctx, "kubectl", args...)
 
 	// Start the port-forward in the background
 	if err := cmd.Start(); err != nil {
@@ -27,9 +29,9 @@ func (c *Client) StartPortForward(ctx context.Context, podName string, localPort
 	}
 
 	// Wait for port-forward to be ready
-	if err := waitForPortForward(localPort, 30*time.Second); err != nil {
+	if err := waitForPortForward(ctx, localPort, 30*time.Second); err != nil {
 		// Kill the process if it failed to become ready
-		_ = cmd.Process.Kill()
+		procutil.Kill(cmd)
 		return nil, fmt.Errorf("port-forward failed to become ready: %w", err)
 	}
 
@@ -37,7 +39,7 @@ func (c *Client) StartPortForward(ctx context.Context, podName string, localPort
 }
 
 // waitForPortForward polls the local port until it's ready or times out
-func waitForPortForward(port int, timeout time.Duration) error {
+func waitForPortForward(ctx context.Context, port int, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 	ticker := time.NewTicker(500 * time.Millisecond)
 	defer ticker.Stop()
@@ -45,14 +47,18 @@ func waitForPortForward(port int, timeout time.Duration) error {
 	for time.Now().Before(deadline) {
 		// Try to connect to the local port
 		dialer := &net.Dialer{Timeout: 1 * time.Second}
-		conn, err := dialer.DialContext(context.Background(), "tcp", fmt.Sprintf("localhost:%d", port))
+		conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("localhost:%d", port))
 		if err == nil {
 			_ = conn.Close()
 			return nil
 		}
 
 		// Wait before next retry
-		<-ticker.C
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	}
 
 	return fmt.Errorf("timeout waiting for port %d to become ready", port)
@@ -0,0 +1,90 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"github.com/illumination-k/kodama/internal/version"
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// RequiredKubectlVerbs are the kubectl subcommands kodama shells out to
+var RequiredKubectlVerbs = []string{"cp", "exec", "port-forward"}
+
+// KubectlCheck is the result of CheckKubectl for one kubeconfig context
+type KubectlCheck struct {
+	KubectlPath    string    `json:"kubectlPath,omitempty"`
+	KubectlModTime time.Time `json:"kubectlModTime,omitempty"`
+	KubectlVersion string    `json:"kubectlVersion,omitempty"`
+	ServerVersion  string    `json:"serverVersion,omitempty"` // Empty when the cluster was unreachable
+	Problems       []string  `json:"problems,omitempty"`
+	CheckedAt      time.Time `json:"checkedAt"`
+}
+
+// KubectlCheckKey identifies the cluster a check applies to: the kubeconfig
+// file and its current context
+func KubectlCheckKey(kubeconfigPath string) string {
+	if kubeconfigPath == "" {
+		kubeconfigPath = getDefaultKubeconfigPath()
+	}
+	return kubeconfigPath + "#" + CurrentContext(kubeconfigPath)
+}
+
+// CheckKubectl verifies that kubectl is on PATH, supports RequiredKubectlVerbs
+// and is within the supported version skew of serverVersion. An empty
+// serverVersion skips the skew check.
+func CheckKubectl(ctx context.Context, serverVersion string) *KubectlCheck {
+	check := &KubectlCheck{ServerVersion: serverVersion, CheckedAt: time.Now()}
+
+	path, modTime, err := lookKubectl()
+	if err != nil {
+		check.Problems = append(check.Problems, "kubectl was not found on PATH; attach, sync, exec and port forwarding need it")
+		return check
+	}
+	check.KubectlPath, check.KubectlModTime = path, modTime
+
+	for _, verb := range RequiredKubectlVerbs {
+		cmd := procutil.CommandContext(ctx, "kubectl", verb, "--help")
+		if err := procutil.Err(ctx, cmd.Run()); err != nil {
+			check.Problems = append(check.Problems, fmt.Sprintf("kubectl does not support '%s'", verb))
+		}
+	}
+
+	kubectlVersion, err := KubectlVersion(ctx)
+	if err != nil {
+		check.Problems = append(check.Problems, err.Error())
+		return check
+	}
+	check.KubectlVersion = kubectlVersion
+	if serverVersion != "" {
+		if warning := version.CheckKubectlSkew(kubectlVersion, serverVersion); warning != "" {
+			check.Problems = append(check.Problems, warning)
+		}
+	}
+	return check
+}
+
+// KubectlChanged reports whether the kubectl on PATH differs from the checked one
+func (k *KubectlCheck) KubectlChanged() bool {
+	path, modTime, err := lookKubectl()
+	if err != nil {
+		return k.KubectlPath != ""
+	}
+	return path != k.KubectlPath || !modTime.Equal(k.KubectlModTime)
+}
+
+// lookKubectl returns the path and modification time of kubectl on PATH
+func lookKubectl() (string, time.Time, error) {
+	path, err := exec.LookPath("kubectl")
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return path, info.ModTime(), nil
+}
@@ -0,0 +1,50 @@
+package kubernetes
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// preflightScript reports gitVersion and fails for port-forward
+func preflightScript(gitVersion string) string {
+	return `case "$1" in
+  version) echo '{"clientVersion":{"gitVersion":"` + gitVersion + `"}}' ;;
+  port-forward) exit 1 ;;
+esac`
+}
+
+func TestCheckKubectl(t *testing.T) {
+	stubKubectl(t, preflightScript("v1.28.4"))
+
+	check := CheckKubectl(context.Background(), "v1.32.1")
+
+	assert.Equal(t, "kubectl", filepath.Base(check.KubectlPath))
+	assert.Equal(t, "v1.28.4", check.KubectlVersion)
+	assert.Equal(t, []string{
+		"kubectl does not support 'port-forward'",
+		"kubectl v1.28.4 is more than 1 minor version from the Kubernetes server v1.32.1",
+	}, check.Problems)
+	assert.False(t, check.KubectlChanged())
+}
+
+func TestCheckKubectl_SkipsSkewWithoutServer(t *testing.T) {
+	stubKubectl(t, preflightScript("v1.28.4"))
+
+	check := CheckKubectl(context.Background(), "")
+
+	assert.Equal(t, []string{"kubectl does not support 'port-forward'"}, check.Problems)
+}
+
+func TestCheckKubectl_Missing(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	check := CheckKubectl(context.Background(), "v1.32.1")
+
+	require.Len(t, check.Problems, 1)
+	assert.Contains(t, check.Problems[0], "kubectl was not found on PATH")
+	assert.False(t, check.KubectlChanged())
+}
@@ -0,0 +1,128 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DefaultPrepullTimeout bounds how long prepull waits for every node to pull the image
+	DefaultPrepullTimeout = 15 * time.Minute
+
+	// prepullPauseImage keeps the DaemonSet pods running after the image is pulled
+	prepullPauseImage = "registry.k8s.io/pause:3.9"
+)
+
+// PrepullOptions configures an image prepull
+type PrepullOptions struct {
+	Namespace    string
+	Image        string
+	NodeSelector map[string]string // Restrict to matching nodes (empty = all nodes)
+}
+
+// PrepullResult reports how many nodes pulled the image
+type PrepullResult struct {
+	Name    string // DaemonSet name
+	Desired int32  // Nodes the DaemonSet was scheduled to
+	Ready   int32  // Nodes where the image was pulled
+}
+
+// BuildPrepullDaemonSet builds a DaemonSet that pulls the image on every node
+// The image runs as an init container that exits immediately; the pod then
+// idles on the pause image so readiness means the pull has finished.
+func BuildPrepullDaemonSet(opts PrepullOptions) *appsv1.DaemonSet {
+	labels := map[string]string{"app": "kodama", "component": "prepull"}
+	tiny := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1m"),
+			corev1.ResourceMemory: resource.MustParse("8Mi"),
+		},
+	}
+
+	return &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "kodama-prepull-",
+			Namespace:    opts.Namespace,
+			Labels:       labels,
+		},
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: labels},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					NodeSelector: opts.NodeSelector,
+					// Tolerate every taint so tainted (e.g. GPU) nodes are warmed too
+					Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					InitContainers: []corev1.Container{{
+						Name:      "pull",
+						Image:     opts.Image,
+						Command:   []string{"/bin/sh", "-c", "true"},
+						Resources: tiny,
+					}},
+					Containers: []corev1.Container{{
+						Name:      "pause",
+						Image:     prepullPauseImage,
+						Resources: tiny,
+					}},
+				},
+			},
+		},
+	}
+}
+
+// PrepullImage pulls an image onto every node with a temporary DaemonSet
+// The DaemonSet is deleted when the pull completes, fails, or times out.
+func (c *Client) PrepullImage(ctx context.Context, opts PrepullOptions, timeout time.Duration) (*PrepullResult, error) {
+	daemonSets := c.clientset.AppsV1().DaemonSets(opts.Namespace)
+
+	created, err := daemonSets.Create(ctx, BuildPrepullDaemonSet(opts), metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prepull DaemonSet: %w", wrapQuotaError(err, opts.Namespace))
+	}
+	defer func() {
+		// Clean up even when the caller was interrupted
+		cleanupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		propagation := metav1.DeletePropagationBackground
+		_ = daemonSets.Delete(cleanupCtx, created.Name, metav1.DeleteOptions{PropagationPolicy: &propagation})
+	}()
+
+	result := &PrepullResult{Name: created.Name}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		ds, err := daemonSets.Get(ctx, created.Name, metav1.GetOptions{})
+		if err != nil {
+			return result, fmt.Errorf("failed to get prepull DaemonSet: %w", err)
+		}
+		result.Desired = ds.Status.DesiredNumberScheduled
+		result.Ready = ds.Status.NumberReady
+
+		// ObservedGeneration guards against reading the status before the controller has seen the DaemonSet
+		if ds.Status.ObservedGeneration >= ds.Generation {
+			if result.Desired == 0 {
+				return result, fmt.Errorf("no nodes match the prepull node selector")
+			}
+			if result.Ready >= result.Desired {
+				return result, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return result, fmt.Errorf("image pulled on %d/%d nodes before timeout: %w", result.Ready, result.Desired, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestBuildPrepullDaemonSet(t *testing.T) {
+	ds := BuildPrepullDaemonSet(PrepullOptions{
+		Namespace:    "dev",
+		Image:        "kodama:test",
+		NodeSelector: map[string]string{"pool": "agents"},
+	})
+
+	if ds.Namespace != "dev" {
+		t.Errorf("namespace = %s, want dev", ds.Namespace)
+	}
+
+	podSpec := ds.Spec.Template.Spec
+	if podSpec.InitContainers[0].Image != "kodama:test" {
+		t.Errorf("init container image = %s, want kodama:test", podSpec.InitContainers[0].Image)
+	}
+	if podSpec.Containers[0].Image != prepullPauseImage {
+		t.Errorf("container image = %s, want %s", podSpec.Containers[0].Image, prepullPauseImage)
+	}
+	if podSpec.NodeSelector["pool"] != "agents" {
+		t.Errorf("node selector = %v, want pool=agents", podSpec.NodeSelector)
+	}
+	if len(podSpec.Tolerations) != 1 || podSpec.Tolerations[0].Operator != "Exists" {
+		t.Errorf("tolerations = %v, want a single Exists toleration", podSpec.Tolerations)
+	}
+}
+
+func TestPrepullImage(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	// Fake clientset does not run controllers, so report the DaemonSet as ready on every get
+	fakeClientset.PrependReactor("get", "daemonsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		name := action.(k8stesting.GetAction).GetName()
+		return true, &appsv1.DaemonSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "dev"},
+			Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3},
+		}, nil
+	})
+	fakeClientset.PrependReactor("create", "daemonsets", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		ds := action.(k8stesting.CreateAction).GetObject().(*appsv1.DaemonSet)
+		ds.Name = "kodama-prepull-abc"
+		return false, ds, nil
+	})
+	client := &Client{clientset: fakeClientset}
+	ctx := context.Background()
+
+	result, err := client.PrepullImage(ctx, PrepullOptions{Namespace: "dev", Image: "kodama:test"}, time.Minute)
+	if err != nil {
+		t.Fatalf("PrepullImage() error = %v", err)
+	}
+	if result.Desired != 3 || result.Ready != 3 {
+		t.Errorf("result = %d/%d, want 3/3", result.Ready, result.Desired)
+	}
+
+	// The DaemonSet is removed afterwards
+	list, err := fakeClientset.AppsV1().DaemonSets("dev").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list DaemonSets: %v", err)
+	}
+	if len(list.Items) != 0 {
+		t.Errorf("expected prepull DaemonSet to be deleted, found %d", len(list.Items))
+	}
+}
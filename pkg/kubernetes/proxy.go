@@ -0,0 +1,88 @@
+package kubernetes
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// CABundleKey is the key of the PEM bundle in the extra CA bundle Secret
+	CABundleKey = "ca.crt"
+
+	// CABundleMountPath is where the extra CA bundle Secret is mounted
+	CABundleMountPath = "/etc/kodama/ca"
+
+	// caBundleVolumeName is the pod volume name for the extra CA bundle
+	caBundleVolumeName = "kodama-ca"
+)
+
+// ProxyEnvVars returns the proxy variables in both cases, since curl reads only
+// lowercase http_proxy while other tools read uppercase
+func ProxyEnvVars(httpProxy, httpsProxy, noProxy string) []corev1.EnvVar {
+	var vars []corev1.EnvVar
+	for _, v := range []struct{ name, value string }{
+		{"HTTP_PROXY", httpProxy},
+		{"HTTPS_PROXY", httpsProxy},
+		{"NO_PROXY", noProxy},
+	} {
+		if v.value == "" {
+			continue
+		}
+		vars = append(vars,
+			corev1.EnvVar{Name: v.name, Value: v.value},
+			corev1.EnvVar{Name: strings.ToLower(v.name), Value: v.value},
+		)
+	}
+	return vars
+}
+
+// CABundleEnvVars points curl, git, OpenSSL, npm, pip and Node.js at the extra CA bundle
+// Except for Node.js, these replace the default trust store, so the bundle must
+// also contain the public CAs needed for hosts reached without the proxy.
+func CABundleEnvVars() []corev1.EnvVar {
+	path := CABundleMountPath + "/" + CABundleKey
+	return []corev1.EnvVar{
+		{Name: "SSL_CERT_FILE", Value: path},
+		{Name: "CURL_CA_BUNDLE", Value: path},
+		{Name: "GIT_SSL_CAINFO", Value: path},
+		{Name: "REQUESTS_CA_BUNDLE", Value: path},
+		{Name: "PIP_CERT", Value: path},
+		{Name: "npm_config_cafile", Value: path},
+		{Name: "NODE_EXTRA_CA_CERTS", Value: path},
+	}
+}
+
+// applyNetworkSettings adds proxy variables and the extra CA bundle to every
+// init container and container of the pod
+func applyNetworkSettings(pod *corev1.Pod, spec *PodSpec) {
+	env := ProxyEnvVars(spec.HTTPProxy, spec.HTTPSProxy, spec.NoProxy)
+	var mounts []corev1.VolumeMount
+	if spec.CABundleSecret != "" {
+		env = append(env, CABundleEnvVars()...)
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      caBundleVolumeName,
+			MountPath: CABundleMountPath,
+			ReadOnly:  true,
+		})
+		pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+			Name: caBundleVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: spec.CABundleSecret,
+					Items:      []corev1.KeyToPath{{Key: CABundleKey, Path: CABundleKey}},
+				},
+			},
+		})
+	}
+	if len(env) == 0 {
+		return
+	}
+
+	for _, containers := range [][]corev1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for i := range containers {
+			containers[i].Env = append(containers[i].Env, env...)
+			containers[i].VolumeMounts = append(containers[i].VolumeMounts, mounts...)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreatePod_NetworkSettings(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{
+		Name:           "kodama-test",
+		Namespace:      "dev",
+		Image:          "kodama:test",
+		GitRepo:        "https://github.com/org/repo",
+		HTTPSProxy:     "http://proxy.corp:3128",
+		NoProxy:        ".corp",
+		CABundleSecret: "corp-ca",
+	}, true)
+	require.NoError(t, err)
+
+	require.Len(t, pod.Spec.InitContainers, 2)
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		assert.True(t, hasEnv(container.Env, "HTTPS_PROXY", "http://proxy.corp:3128"), container.Name)
+		assert.True(t, hasEnv(container.Env, "https_proxy", "http://proxy.corp:3128"), container.Name)
+		assert.True(t, hasEnv(container.Env, "no_proxy", ".corp"), container.Name)
+		assert.False(t, hasEnv(container.Env, "HTTP_PROXY", ""), "unset proxies are left out")
+		assert.True(t, hasEnv(container.Env, "GIT_SSL_CAINFO", CABundleMountPath+"/"+CABundleKey), container.Name)
+		assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: caBundleVolumeName, MountPath: CABundleMountPath, ReadOnly: true}, container.Name)
+	}
+
+	var secret string
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == caBundleVolumeName {
+			secret = volume.Secret.SecretName
+		}
+	}
+	assert.Equal(t, "corp-ca", secret)
+}
+
+func TestCreatePod_NoNetworkSettings(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{Name: "kodama-test", Namespace: "dev", Image: "kodama:test"}, true)
+	require.NoError(t, err)
+
+	for _, container := range pod.Spec.InitContainers {
+		assert.Empty(t, container.Env, container.Name)
+	}
+	for _, volume := range pod.Spec.Volumes {
+		assert.NotEqual(t, caBundleVolumeName, volume.Name)
+	}
+}
@@ -0,0 +1,205 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QuotaReport compares the resources of the kodama pods in a namespace with
+// the namespace's ResourceQuotas
+type QuotaReport struct {
+	Namespace string
+	Pods      int                 // Kodama pods that count towards the quotas
+	Requests  corev1.ResourceList // Summed over kodama pods
+	Limits    corev1.ResourceList // Summed over kodama pods
+	Quotas    []corev1.ResourceQuota
+}
+
+// GetQuotaReport sums the requests and limits of the kodama pods in namespace
+// and lists its ResourceQuotas
+func (c *Client) GetQuotaReport(ctx context.Context, namespace string) (*QuotaReport, error) {
+	pods, err := c.clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: "app=kodama",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+	}
+
+	quotas, err := c.clientset.CoreV1().ResourceQuotas(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas in namespace %s: %w", namespace, err)
+	}
+
+	report := &QuotaReport{
+		Namespace: namespace,
+		Requests:  corev1.ResourceList{},
+		Limits:    corev1.ResourceList{},
+		Quotas:    quotas.Items,
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		// Finished pods no longer count towards a quota
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		report.Pods++
+		requests, limits := podResources(pod)
+		addResources(report.Requests, requests)
+		addResources(report.Limits, limits)
+	}
+	return report, nil
+}
+
+// CheckQuota returns the ResourceQuota limits a pod built from spec would
+// exceed, e.g. "limits.memory: 8Gi needed, 2Gi of 16Gi left (quota team)"
+// Kubernetes would reject such a pod with a FailedCreate error.
+func (c *Client) CheckQuota(ctx context.Context, spec *PodSpec) ([]string, error) {
+	pod, err := c.CreatePod(ctx, spec, true)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas, err := c.clientset.CoreV1().ResourceQuotas(spec.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas in namespace %s: %w", spec.Namespace, err)
+	}
+
+	requests, limits := podResources(pod)
+	return QuotaShortfalls(quotas.Items, requests, limits), nil
+}
+
+// QuotaUsage is the state of one resource of a ResourceQuota
+type QuotaUsage struct {
+	Quota    string
+	Resource corev1.ResourceName
+	Hard     resource.Quantity
+	Used     resource.Quantity  // By every pod and object in the namespace
+	Kodama   *resource.Quantity // By kodama pods, nil for resources pods are not charged with
+}
+
+// Usage returns every resource of the report's quotas, sorted by quota and resource
+func (r *QuotaReport) Usage() []QuotaUsage {
+	var usage []QuotaUsage
+	for _, quota := range r.Quotas {
+		hardLimits := quotaHard(quota)
+		for _, name := range sortedResourceNames(hardLimits) {
+			row := QuotaUsage{
+				Quota:    quota.Name,
+				Resource: name,
+				Hard:     hardLimits[name],
+				Used:     quota.Status.Used[name],
+			}
+			if charge, ok := quotaCharge(name, r.Requests, r.Limits); ok {
+				if name == corev1.ResourcePods {
+					charge = *resource.NewQuantity(int64(r.Pods), resource.DecimalSI)
+				}
+				row.Kodama = &charge
+			}
+			usage = append(usage, row)
+		}
+	}
+	return usage
+}
+
+// QuotaShortfalls returns the quota limits a new pod with requests and limits
+// would exceed, one message per quota resource
+func QuotaShortfalls(quotas []corev1.ResourceQuota, requests, limits corev1.ResourceList) []string {
+	var shortfalls []string
+	for _, quota := range quotas {
+		hardLimits := quotaHard(quota)
+		for _, name := range sortedResourceNames(hardLimits) {
+			needed, ok := quotaCharge(name, requests, limits)
+			if !ok || needed.IsZero() {
+				continue
+			}
+			hard := hardLimits[name]
+			left := hard.DeepCopy()
+			left.Sub(quota.Status.Used[name])
+			if needed.Cmp(left) <= 0 {
+				continue
+			}
+			if left.Sign() < 0 {
+				left = resource.Quantity{}
+			}
+			shortfalls = append(shortfalls, fmt.Sprintf("%s: %s needed, %s of %s left (quota %s)",
+				name, needed.String(), left.String(), hard.String(), quota.Name))
+		}
+	}
+	return shortfalls
+}
+
+// quotaHard returns the enforced limits of a quota
+func quotaHard(quota corev1.ResourceQuota) corev1.ResourceList {
+	if len(quota.Status.Hard) == 0 {
+		// Not yet reconciled by the quota controller
+		return quota.Spec.Hard
+	}
+	return quota.Status.Hard
+}
+
+// sortedResourceNames returns the resource names of list in order
+func sortedResourceNames(list corev1.ResourceList) []corev1.ResourceName {
+	names := make([]corev1.ResourceName, 0, len(list))
+	for name := range list {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	return names
+}
+
+// quotaCharge returns what a pod with requests and limits adds to the quota
+// resource name. Returns false for resources that don't count pods' resources,
+// such as services or count/* quotas.
+func quotaCharge(name corev1.ResourceName, requests, limits corev1.ResourceList) (resource.Quantity, bool) {
+	switch {
+	case name == corev1.ResourcePods:
+		return *resource.NewQuantity(1, resource.DecimalSI), true
+	case strings.HasPrefix(string(name), "requests."):
+		return requests[corev1.ResourceName(strings.TrimPrefix(string(name), "requests."))], true
+	case strings.HasPrefix(string(name), "limits."):
+		return limits[corev1.ResourceName(strings.TrimPrefix(string(name), "limits."))], true
+	case name == corev1.ResourceCPU || name == corev1.ResourceMemory || name == corev1.ResourceEphemeralStorage:
+		// Bare names are shorthands for requests
+		return requests[name], true
+	}
+	return resource.Quantity{}, false
+}
+
+// podResources returns the requests and limits a pod is charged with: the sum
+// over its containers, or the largest init container if that is higher
+func podResources(pod *corev1.Pod) (requests, limits corev1.ResourceList) {
+	requests, limits = corev1.ResourceList{}, corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+		addResources(limits, container.Resources.Limits)
+	}
+	for _, container := range pod.Spec.InitContainers {
+		maxResources(requests, container.Resources.Requests)
+		maxResources(limits, container.Resources.Limits)
+	}
+	return requests, limits
+}
+
+// addResources adds the quantities of add to total
+func addResources(total, add corev1.ResourceList) {
+	for name, quantity := range add {
+		sum := total[name]
+		sum.Add(quantity)
+		total[name] = sum
+	}
+}
+
+// maxResources raises the quantities of total to those of other where higher
+func maxResources(total, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := total[name]; !ok || quantity.Cmp(current) > 0 {
+			total[name] = quantity.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func quotaTestPod(name string, phase corev1.PodPhase, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "dev", Labels: map[string]string{"app": "kodama"}},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: MainContainerName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memory)},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func quotaTestQuota() *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{Name: "team", Namespace: "dev"},
+		Status: corev1.ResourceQuotaStatus{
+			Hard: corev1.ResourceList{
+				corev1.ResourceLimitsMemory: resource.MustParse("16Gi"),
+				corev1.ResourceRequestsCPU:  resource.MustParse("4"),
+				corev1.ResourcePods:         resource.MustParse("10"),
+				"count/services":            resource.MustParse("5"),
+			},
+			Used: corev1.ResourceList{
+				corev1.ResourceLimitsMemory: resource.MustParse("12Gi"),
+				corev1.ResourceRequestsCPU:  resource.MustParse("3"),
+				corev1.ResourcePods:         resource.MustParse("3"),
+			},
+		},
+	}
+}
+
+func TestGetQuotaReport(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset(
+		quotaTestPod("kodama-a", corev1.PodRunning, "1", "4Gi"),
+		quotaTestPod("kodama-b", corev1.PodPending, "500m", "2Gi"),
+		quotaTestPod("kodama-done", corev1.PodSucceeded, "8", "32Gi"),
+		quotaTestQuota(),
+	)}
+
+	report, err := client.GetQuotaReport(context.Background(), "dev")
+	if err != nil {
+		t.Fatalf("GetQuotaReport() error = %v", err)
+	}
+
+	if report.Pods != 2 {
+		t.Errorf("pods = %d, want 2 (finished pods don't count)", report.Pods)
+	}
+	if cpu := report.Requests[corev1.ResourceCPU]; cpu.String() != "1500m" {
+		t.Errorf("requested cpu = %s, want 1500m", cpu.String())
+	}
+	if memory := report.Limits[corev1.ResourceMemory]; memory.String() != "6Gi" {
+		t.Errorf("memory limit = %s, want 6Gi", memory.String())
+	}
+
+	got := map[corev1.ResourceName]string{}
+	for _, u := range report.Usage() {
+		kodama := "-"
+		if u.Kodama != nil {
+			kodama = u.Kodama.String()
+		}
+		got[u.Resource] = kodama
+	}
+	want := map[corev1.ResourceName]string{
+		corev1.ResourceLimitsMemory: "6Gi",
+		corev1.ResourceRequestsCPU:  "1500m",
+		corev1.ResourcePods:         "2",
+		"count/services":            "-",
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("kodama usage of %s = %s, want %s", name, got[name], value)
+		}
+	}
+}
+
+func TestQuotaShortfalls(t *testing.T) {
+	quotas := []corev1.ResourceQuota{*quotaTestQuota()}
+
+	shortfalls := QuotaShortfalls(quotas,
+		corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+		corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+	)
+	if len(shortfalls) != 1 || !strings.HasPrefix(shortfalls[0], "requests.cpu: 2 needed, 1 of 4 left (quota team)") {
+		t.Errorf("shortfalls = %v, want only requests.cpu", shortfalls)
+	}
+
+	if shortfalls := QuotaShortfalls(quotas, corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")}, nil); len(shortfalls) != 0 {
+		t.Errorf("expected a pod within the quota to fit, got %v", shortfalls)
+	}
+}
+
+func TestCheckQuota(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset(quotaTestQuota())}
+
+	shortfalls, err := client.CheckQuota(context.Background(), &PodSpec{
+		Name:        "kodama-new",
+		Namespace:   "dev",
+		Image:       "kodama:test",
+		CPULimit:    "4",
+		MemoryLimit: "8Gi",
+	})
+	if err != nil {
+		t.Fatalf("CheckQuota() error = %v", err)
+	}
+
+	joined := strings.Join(shortfalls, "\n")
+	if !strings.Contains(joined, "limits.memory") || !strings.Contains(joined, "requests.cpu") {
+		t.Errorf("shortfalls = %v, want limits.memory and requests.cpu", shortfalls)
+	}
+}
@@ -0,0 +1,92 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAgentReadyAttempts is how often WaitForAgentBinaries checks the pod
+	DefaultAgentReadyAttempts = 10
+	// DefaultAgentReadyInterval is the pause between agent binary checks
+	DefaultAgentReadyInterval = 3 * time.Second
+)
+
+// ErrAgentNotReady is returned when the agent binaries still fail to run in a
+// pod after every check
+var ErrAgentNotReady = errors.New("agent binaries are not ready in the pod")
+
+// agentBinaryCheckScript prints "ok <binary> <version>" or "missing <binary>"
+// per binary and always exits 0, so a failing exec is not mistaken for a
+// missing binary
+func agentBinaryCheckScript(binaries []string) string {
+	return fmt.Sprintf(`for b in %s; do
+  if v=$("$b" --version 2>&1); then echo "ok $b $(echo "$v" | head -n 1)"; else echo "missing $b"; fi
+done`, strings.Join(binaries, " "))
+}
+
+// WaitForAgentBinaries runs '<binary> --version' in the pod for every binary
+// until all of them succeed. Pod readiness only says the containers started;
+// with prebuilt or partial images the binaries may still be appearing on PATH.
+// Exec failures are retried too, since a pod that just became ready may not
+// accept exec yet. It returns the first version line of each binary.
+func WaitForAgentBinaries(ctx context.Context, executor CommandExecutor, namespace, podName string, binaries []string, attempts int, interval time.Duration) (map[string]string, error) {
+	if attempts < 1 {
+		attempts = 1
+	}
+	script := agentBinaryCheckScript(binaries)
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		stdout, stderr, err := executor.ExecInPod(ctx, namespace, podName, []string{"sh", "-c", script})
+		if err == nil {
+			versions, missing := parseAgentBinaryCheck(stdout, binaries)
+			if len(missing) == 0 {
+				return versions, nil
+			}
+			lastErr = fmt.Errorf("%s --version failed", strings.Join(missing, ", "))
+		} else if strings.TrimSpace(stderr) != "" {
+			lastErr = fmt.Errorf("exec failed: %s: %w", lastLine(stderr), err)
+		} else {
+			lastErr = fmt.Errorf("exec failed: %w", err)
+		}
+
+		if attempt == attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+	return nil, fmt.Errorf("%w after %d attempts: %v", ErrAgentNotReady, attempts, lastErr)
+}
+
+// parseAgentBinaryCheck reads the output of agentBinaryCheckScript. Binaries
+// without a line count as missing.
+func parseAgentBinaryCheck(stdout string, binaries []string) (map[string]string, []string) {
+	versions := make(map[string]string, len(binaries))
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 3)
+		if len(fields) < 2 || fields[0] != "ok" {
+			continue
+		}
+		version := ""
+		if len(fields) == 3 {
+			version = fields[2]
+		}
+		versions[fields[1]] = version
+	}
+
+	var missing []string
+	for _, binary := range binaries {
+		if _, ok := versions[binary]; !ok {
+			missing = append(missing, binary)
+		}
+	}
+	return versions, missing
+}
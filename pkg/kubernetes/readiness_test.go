@@ -0,0 +1,61 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readinessTestExecutor answers binary checks from a queue of results
+type readinessTestExecutor struct {
+	outputs []string
+	errs    []error
+	calls   int
+}
+
+func (e *readinessTestExecutor) ExecInPod(ctx context.Context, namespace, podName string, command []string) (string, string, error) {
+	i := e.calls
+	e.calls++
+	var err error
+	if i < len(e.errs) {
+		err = e.errs[i]
+	}
+	if err != nil {
+		return "", "error: unable to upgrade connection", err
+	}
+	return e.outputs[i], "", nil
+}
+
+func TestWaitForAgentBinaries(t *testing.T) {
+	binaries := []string{"claude", "ttyd"}
+
+	t.Run("ready after retries", func(t *testing.T) {
+		executor := &readinessTestExecutor{
+			outputs: []string{"", "ok claude 2.0.1 (Claude Code)\nmissing ttyd\n", "ok claude 2.0.1 (Claude Code)\nok ttyd ttyd version 1.7.7\n"},
+			errs:    []error{errors.New("exit status 1")},
+		}
+		versions, err := WaitForAgentBinaries(context.Background(), executor, "dev", "kodama-work", binaries, 5, 0)
+		require.NoError(t, err)
+		assert.Equal(t, 3, executor.calls)
+		assert.Equal(t, map[string]string{"claude": "2.0.1 (Claude Code)", "ttyd": "ttyd version 1.7.7"}, versions)
+	})
+
+	t.Run("still missing", func(t *testing.T) {
+		executor := &readinessTestExecutor{outputs: []string{"ok claude 2.0.1\nmissing ttyd\n", "ok claude 2.0.1\nmissing ttyd\n"}}
+		_, err := WaitForAgentBinaries(context.Background(), executor, "dev", "kodama-work", binaries, 2, 0)
+		require.ErrorIs(t, err, ErrAgentNotReady)
+		assert.Contains(t, err.Error(), "ttyd --version failed")
+		assert.Equal(t, 2, executor.calls)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		executor := &readinessTestExecutor{outputs: []string{"missing claude\n"}}
+		_, err := WaitForAgentBinaries(ctx, executor, "dev", "kodama-work", []string{"claude"}, 3, DefaultAgentReadyInterval)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,214 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// ReaperName is the name shared by the reaper CronJob and its RBAC resources
+	ReaperName = "kodama-reaper"
+
+	// DefaultReaperSchedule runs the reaper every 15 minutes
+	DefaultReaperSchedule = "*/15 * * * *"
+
+	// DefaultReaperImage provides kubectl and a POSIX shell
+	// It is pinned to a release matching the client-go version kodama is built with.
+	DefaultReaperImage = "alpine/k8s:1.32.0"
+)
+
+// reaperResources are the session resources deleted along with an expired pod:
+// env and file secrets, editor ConfigMaps, share Services and Ingresses and
+// PodDisruptionBudgets. PersistentVolumeClaims hold data that may outlive the
+// session, so the reaper neither deletes them nor is allowed to.
+const reaperResources = "secrets,configmaps,services,ingresses.networking.k8s.io,poddisruptionbudgets.policy"
+
+// reaperScript deletes kodama pods whose expiration annotation is in the past,
+// along with the resources labeled with the session. Share resources are
+// labeled with the pod name, the others with the session name.
+// Expiration times are RFC 3339 in UTC, which sort in time order as strings.
+const reaperScript = `set -eu
+now=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+kubectl get pods -n "$NAMESPACE" -l app=kodama \
+  -o jsonpath='{range .items[*]}{.metadata.name}{" "}{.metadata.annotations.kodama\.io/expires-at}{"\n"}{end}' |
+while read -r pod expires; do
+  [ -n "$expires" ] || continue
+  if expr "$expires" \<= "$now" >/dev/null; then
+    session="${pod#kodama-}"
+    echo "Deleting expired session $session (expired at $expires)"
+    kubectl delete pod -n "$NAMESPACE" "$pod" --ignore-not-found --wait=false
+    kubectl delete "$RESOURCES" -n "$NAMESPACE" -l "app=kodama,session=$session" --ignore-not-found --wait=false
+    kubectl delete "$RESOURCES" -n "$NAMESPACE" -l "app=kodama,session=$pod" --ignore-not-found --wait=false
+  fi
+done`
+
+// ReaperOptions configures the expired-session reaper CronJob
+type ReaperOptions struct {
+	Namespace string
+	Schedule  string
+	Image     string
+}
+
+// BuildReaperManifests returns the ServiceAccount, Role, RoleBinding and CronJob
+// that delete expired kodama sessions in the namespace
+func BuildReaperManifests(opts ReaperOptions) []runtime.Object {
+	schedule := opts.Schedule
+	if schedule == "" {
+		schedule = DefaultReaperSchedule
+	}
+	image := opts.Image
+	if image == "" {
+		image = DefaultReaperImage
+	}
+
+	meta := metav1.ObjectMeta{
+		Name:      ReaperName,
+		Namespace: opts.Namespace,
+		Labels: map[string]string{
+			"component":  "reaper",
+			"managed-by": "kodama",
+		},
+	}
+
+	serviceAccount := &corev1.ServiceAccount{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ServiceAccount"},
+		ObjectMeta: *meta.DeepCopy(),
+	}
+
+	role := &rbacv1.Role{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "Role"},
+		ObjectMeta: *meta.DeepCopy(),
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"pods"},
+				Verbs:     []string{"get", "list", "delete"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"secrets", "configmaps", "services"},
+				Verbs:     []string{"list", "delete"},
+			},
+			{
+				APIGroups: []string{"networking.k8s.io"},
+				Resources: []string{"ingresses"},
+				Verbs:     []string{"list", "delete"},
+			},
+			{
+				APIGroups: []string{"policy"},
+				Resources: []string{"poddisruptionbudgets"},
+				Verbs:     []string{"list", "delete"},
+			},
+		},
+	}
+
+	roleBinding := &rbacv1.RoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "rbac.authorization.k8s.io/v1", Kind: "RoleBinding"},
+		ObjectMeta: *meta.DeepCopy(),
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      ReaperName,
+				Namespace: opts.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     ReaperName,
+		},
+	}
+
+	historyLimit := int32(1)
+	cronJob := &batchv1.CronJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "batch/v1", Kind: "CronJob"},
+		ObjectMeta: *meta.DeepCopy(),
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   schedule,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &historyLimit,
+			FailedJobsHistoryLimit:     &historyLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{
+							ServiceAccountName: ReaperName,
+							RestartPolicy:      corev1.RestartPolicyOnFailure,
+							Containers: []corev1.Container{
+								{
+									Name:    "reaper",
+									Image:   image,
+									Command: []string{"/bin/sh", "-c", reaperScript},
+									Env: []corev1.EnvVar{
+										{
+											Name: "NAMESPACE",
+											ValueFrom: &corev1.EnvVarSource{
+												FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.namespace"},
+											},
+										},
+										{Name: "RESOURCES", Value: reaperResources},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return []runtime.Object{serviceAccount, role, roleBinding, cronJob}
+}
+
+// InstallReaper creates or updates the reaper resources in the namespace
+func (c *Client) InstallReaper(ctx context.Context, opts ReaperOptions) error {
+	for _, obj := range BuildReaperManifests(opts) {
+		if err := c.applyReaperObject(ctx, obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyReaperObject creates obj, updating it in place if it already exists
+func (c *Client) applyReaperObject(ctx context.Context, obj runtime.Object) error {
+	var err error
+
+	switch o := obj.(type) {
+	case *corev1.ServiceAccount:
+		client := c.clientset.CoreV1().ServiceAccounts(o.Namespace)
+		if _, err = client.Create(ctx, o, metav1.CreateOptions{}); errors.IsAlreadyExists(err) {
+			// ServiceAccount has nothing to update
+			err = nil
+		}
+	case *rbacv1.Role:
+		client := c.clientset.RbacV1().Roles(o.Namespace)
+		if _, err = client.Create(ctx, o, metav1.CreateOptions{}); errors.IsAlreadyExists(err) {
+			_, err = client.Update(ctx, o, metav1.UpdateOptions{})
+		}
+	case *rbacv1.RoleBinding:
+		client := c.clientset.RbacV1().RoleBindings(o.Namespace)
+		if _, err = client.Create(ctx, o, metav1.CreateOptions{}); errors.IsAlreadyExists(err) {
+			_, err = client.Update(ctx, o, metav1.UpdateOptions{})
+		}
+	case *batchv1.CronJob:
+		client := c.clientset.BatchV1().CronJobs(o.Namespace)
+		if _, err = client.Create(ctx, o, metav1.CreateOptions{}); errors.IsAlreadyExists(err) {
+			_, err = client.Update(ctx, o, metav1.UpdateOptions{})
+		}
+	default:
+		return fmt.Errorf("unsupported reaper object type %T", obj)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to apply %s: %w", obj.GetObjectKind().GroupVersionKind().Kind, err)
+	}
+	return nil
+}
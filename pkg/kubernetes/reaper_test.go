@@ -0,0 +1,137 @@
+package kubernetes
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildReaperManifests(t *testing.T) {
+	objs := BuildReaperManifests(ReaperOptions{Namespace: "dev"})
+	if len(objs) != 4 {
+		t.Fatalf("BuildReaperManifests() returned %d objects, want 4", len(objs))
+	}
+
+	cronJob, ok := objs[3].(*batchv1.CronJob)
+	if !ok {
+		t.Fatalf("last object is %T, want *batchv1.CronJob", objs[3])
+	}
+	if cronJob.Namespace != "dev" {
+		t.Errorf("CronJob namespace = %s, want dev", cronJob.Namespace)
+	}
+	if cronJob.Spec.Schedule != DefaultReaperSchedule {
+		t.Errorf("CronJob schedule = %s, want %s", cronJob.Spec.Schedule, DefaultReaperSchedule)
+	}
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	if podSpec.ServiceAccountName != ReaperName {
+		t.Errorf("ServiceAccountName = %s, want %s", podSpec.ServiceAccountName, ReaperName)
+	}
+	if podSpec.Containers[0].Image != DefaultReaperImage {
+		t.Errorf("image = %s, want %s", podSpec.Containers[0].Image, DefaultReaperImage)
+	}
+	if strings.HasSuffix(DefaultReaperImage, ":latest") || !strings.Contains(DefaultReaperImage, ":") {
+		t.Errorf("default image %s is not pinned", DefaultReaperImage)
+	}
+	script := podSpec.Containers[0].Command[2]
+	if !strings.Contains(script, `kodama\.io/expires-at`) {
+		t.Errorf("reaper script does not read the %s annotation", ExpiresAtAnnotation)
+	}
+
+	role, ok := objs[1].(*rbacv1.Role)
+	if !ok {
+		t.Fatalf("second object is %T, want *rbacv1.Role", objs[1])
+	}
+	granted := map[string]bool{}
+	for _, rule := range role.Rules {
+		for _, resource := range rule.Resources {
+			granted[resource] = true
+		}
+	}
+	for _, resource := range []string{"pods", "secrets", "configmaps", "services", "ingresses"} {
+		if !granted[resource] {
+			t.Errorf("Role does not allow deleting %s", resource)
+		}
+	}
+	if granted["persistentvolumeclaims"] {
+		t.Error("Role allows deleting persistentvolumeclaims")
+	}
+	if strings.Contains(reaperResources, "persistentvolumeclaims") {
+		t.Errorf("reaper deletes persistentvolumeclaims: %s", reaperResources)
+	}
+}
+
+func TestReaperScript(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	// A fake kubectl lists two pods and records the delete calls
+	dir := t.TempDir()
+	calls := filepath.Join(dir, "calls")
+	fakeKubectl := `#!/bin/sh
+if [ "$1" = get ]; then
+  echo "kodama-old 2000-01-01T00:00:00Z"
+  echo "kodama-new 2999-01-01T00:00:00Z"
+  echo "kodama-forever "
+  exit 0
+fi
+echo "$*" >> ` + calls + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte(fakeKubectl), 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("sh", "-c", reaperScript)
+	cmd.Env = append(os.Environ(), "PATH="+dir+":"+os.Getenv("PATH"), "NAMESPACE=dev", "RESOURCES="+reaperResources)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("reaper script failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(calls)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "delete pod -n dev kodama-old --ignore-not-found --wait=false\n" +
+		"delete " + reaperResources + " -n dev -l app=kodama,session=old --ignore-not-found --wait=false\n" +
+		"delete " + reaperResources + " -n dev -l app=kodama,session=kodama-old --ignore-not-found --wait=false\n"
+	if string(data) != want {
+		t.Errorf("kubectl calls =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestInstallReaper(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	client := &Client{clientset: fakeClientset}
+	ctx := context.Background()
+
+	opts := ReaperOptions{Namespace: "dev", Schedule: "0 * * * *"}
+	if err := client.InstallReaper(ctx, opts); err != nil {
+		t.Fatalf("InstallReaper() error = %v", err)
+	}
+
+	// Re-installing with a new schedule updates the existing CronJob
+	opts.Schedule = "*/5 * * * *"
+	if err := client.InstallReaper(ctx, opts); err != nil {
+		t.Fatalf("InstallReaper() second run error = %v", err)
+	}
+
+	cronJob, err := fakeClientset.BatchV1().CronJobs("dev").Get(ctx, ReaperName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get CronJob: %v", err)
+	}
+	if cronJob.Spec.Schedule != "*/5 * * * *" {
+		t.Errorf("CronJob schedule = %s, want */5 * * * *", cronJob.Spec.Schedule)
+	}
+
+	if _, err := fakeClientset.RbacV1().RoleBindings("dev").Get(ctx, ReaperName, metav1.GetOptions{}); err != nil {
+		t.Errorf("RoleBinding not created: %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package kubernetes
+
+import "fmt"
+
+const (
+	// RecordingsParentDir holds kodama's files in the workspace; it is git-ignored
+	RecordingsParentDir = "/workspace/.kodama"
+
+	// RecordingsDir is where terminal recordings are written in the pod
+	RecordingsDir = RecordingsParentDir + "/recordings"
+)
+
+// RecordedShellScript returns a shell script that starts the session shell
+// (see ShellSetupScript) under util-linux script(1), writing <timestamp>.log
+// and <timestamp>.timing to RecordingsDir for replay with scriptreplay. Falls
+// back to a plain shell when script is not installed. RecordingsDir/latest
+// links to the newest recording so that observers can follow it. The script
+// contains no single quotes so it can be embedded in a single-quoted argument.
+func RecordedShellScript(shell string) string {
+	return ShellSetupScript(shell) + fmt.Sprintf(`mkdir -p %[1]s; `+
+		`[ -f %[2]s/.gitignore ] || printf "*\n" > %[2]s/.gitignore; `+
+		`if command -v script >/dev/null 2>&1; then `+
+		`f=%[1]s/$(date -u +%%Y%%m%%dT%%H%%M%%SZ)-$$; `+
+		`echo "Recording to $f.log"; `+
+		`ln -sfn $f.log %[1]s/latest; `+
+		`exec script -q -f --timing=$f.timing -c "$KODAMA_SHELL" $f.log; `+
+		`else echo "script not found; session is not recorded"; exec "$KODAMA_SHELL"; fi`, RecordingsDir, RecordingsParentDir)
+}
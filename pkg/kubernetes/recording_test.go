@@ -0,0 +1,49 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestRecordedShellScript(t *testing.T) {
+	script := RecordedShellScript("zsh")
+
+	if strings.Contains(script, "'") {
+		t.Error("script must not contain single quotes")
+	}
+	for _, want := range []string{
+		"mkdir -p " + RecordingsDir,
+		RecordingsParentDir + "/.gitignore",
+		"for s in zsh bash sh;",
+		`script -q -f --timing=$f.timing -c "$KODAMA_SHELL" $f.log`,
+		"ln -sfn $f.log " + RecordingsDir + "/latest",
+		"%Y%m%dT%H%M%SZ",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+}
+
+func TestCreatePod_RecordTerminal(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{
+		Name:           "kodama-test",
+		Namespace:      "dev",
+		Image:          "kodama:test",
+		TtydEnabled:    true,
+		RecordTerminal: true,
+	}, true)
+	if err != nil {
+		t.Fatalf("CreatePod() error = %v", err)
+	}
+
+	command := pod.Spec.Containers[0].Command[2]
+	if !strings.Contains(command, "/bin/sh -c '"+RecordedShellScript("")+"'") {
+		t.Errorf("ttyd command does not record the shell: %s", command)
+	}
+}
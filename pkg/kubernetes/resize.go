@@ -0,0 +1,148 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ErrInPlaceResizeUnsupported is returned by ResizePod when the cluster or node
+// cannot change the pod's resources without recreating it
+var ErrInPlaceResizeUnsupported = errors.New("in-place pod resize is not supported")
+
+// podResizePending is the pod condition used for resize status since Kubernetes 1.33
+const podResizePending corev1.PodConditionType = "PodResizePending"
+
+// ResizePod changes the CPU and memory of the pod's main container in place
+// Empty cpu or memory leaves that resource unchanged. The resize subresource
+// (Kubernetes 1.33+) is tried first, then a direct patch for clusters with the
+// InPlacePodVerticalScaling feature gate. Waits until the kubelet has applied it.
+func (c *Client) ResizePod(ctx context.Context, name, namespace, cpu, memory string, timeout time.Duration) error {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s in namespace %s", ErrPodNotFound, name, namespace)
+		}
+		return fmt.Errorf("failed to get pod %s: %w", name, err)
+	}
+	if len(pod.Spec.Containers) == 0 {
+		return fmt.Errorf("pod %s has no containers", name)
+	}
+
+	container := pod.Spec.Containers[0]
+	desired := resizedRequirements(container.Resources, cpu, memory)
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"containers": []map[string]interface{}{{
+				"name":      container.Name,
+				"resources": desired,
+			}},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to build resize patch: %w", err)
+	}
+
+	pods := c.clientset.CoreV1().Pods(namespace)
+	_, err = pods.Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "resize")
+	if apierrors.IsNotFound(err) || apierrors.IsMethodNotSupported(err) {
+		// No resize subresource before 1.33; patch the pod spec directly
+		_, err = pods.Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		if apierrors.IsInvalid(err) || apierrors.IsForbidden(err) || apierrors.IsBadRequest(err) {
+			return fmt.Errorf("%w: %v", ErrInPlaceResizeUnsupported, err)
+		}
+		return fmt.Errorf("failed to resize pod %s: %w", name, err)
+	}
+
+	return c.waitForResize(ctx, name, namespace, container.Name, desired, timeout)
+}
+
+// resizedRequirements applies new CPU and memory limits using the same request
+// ratio as pod creation, keeping any other resources
+func resizedRequirements(current corev1.ResourceRequirements, cpu, memory string) corev1.ResourceRequirements {
+	desired := corev1.ResourceRequirements{
+		Limits:   corev1.ResourceList{},
+		Requests: corev1.ResourceList{},
+	}
+	for name, quantity := range current.Limits {
+		desired.Limits[name] = quantity
+	}
+	for name, quantity := range current.Requests {
+		desired.Requests[name] = quantity
+	}
+
+	updated := (&Client{}).buildResourceRequirements(cpu, memory, nil)
+	for name, quantity := range updated.Limits {
+		desired.Limits[name] = quantity
+	}
+	for name, quantity := range updated.Requests {
+		desired.Requests[name] = quantity
+	}
+	return desired
+}
+
+// waitForResize polls until the container reports the desired limits
+func (c *Client) waitForResize(ctx context.Context, name, namespace, containerName string, desired corev1.ResourceRequirements, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("failed to get pod %s: %w", name, err)
+		}
+
+		if pod.Status.Resize == corev1.PodResizeStatusInfeasible {
+			return fmt.Errorf("%w: node %s cannot fit the new resources", ErrInPlaceResizeUnsupported, pod.Spec.NodeName)
+		}
+		for _, condition := range pod.Status.Conditions {
+			if condition.Type == podResizePending && condition.Reason == string(corev1.PodResizeStatusInfeasible) {
+				return fmt.Errorf("%w: %s", ErrInPlaceResizeUnsupported, condition.Message)
+			}
+		}
+
+		if resizeApplied(pod, containerName, desired) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("resize of pod %s not applied within %v (status: %s): %w", name, timeout, pod.Status.Resize, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// resizeApplied reports whether the kubelet runs the container with the desired limits
+func resizeApplied(pod *corev1.Pod, containerName string, desired corev1.ResourceRequirements) bool {
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.Name != containerName || status.Resources == nil {
+			continue
+		}
+		for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+			want, ok := desired.Limits[name]
+			if !ok {
+				continue
+			}
+			got, ok := status.Resources.Limits[name]
+			if !ok || got.Cmp(want) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func resizeTestPod(limitCPU, limitMemory string) *corev1.Pod {
+	limits := corev1.ResourceList{
+		corev1.ResourceCPU:    resource.MustParse(limitCPU),
+		corev1.ResourceMemory: resource.MustParse(limitMemory),
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "kodama-test", Namespace: "dev"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:      "claude-code",
+				Resources: corev1.ResourceRequirements{Limits: limits},
+			}},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:      "claude-code",
+				Resources: &corev1.ResourceRequirements{Limits: limits},
+			}},
+		},
+	}
+}
+
+func TestResizePod(t *testing.T) {
+	// The fake has no kubelet, so the status already reports the target limits
+	fakeClientset := fake.NewSimpleClientset(resizeTestPod("4", "8Gi"))
+	client := &Client{clientset: fakeClientset}
+
+	if err := client.ResizePod(context.Background(), "kodama-test", "dev", "4", "8Gi", time.Minute); err != nil {
+		t.Fatalf("ResizePod() error = %v", err)
+	}
+
+	var patched bool
+	for _, action := range fakeClientset.Actions() {
+		if action.GetVerb() == "patch" {
+			patched = true
+		}
+	}
+	if !patched {
+		t.Error("ResizePod() did not patch the pod")
+	}
+}
+
+func TestResizePod_Unsupported(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset(resizeTestPod("2", "4Gi"))
+	fakeClientset.PrependReactor("patch", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewInvalid(schema.GroupKind{Kind: "Pod"}, "kodama-test", field.ErrorList{
+			field.Forbidden(field.NewPath("spec"), "pod updates may not change fields other than image"),
+		})
+	})
+	client := &Client{clientset: fakeClientset}
+
+	err := client.ResizePod(context.Background(), "kodama-test", "dev", "4", "8Gi", time.Minute)
+	if !errors.Is(err, ErrInPlaceResizeUnsupported) {
+		t.Errorf("ResizePod() error = %v, want ErrInPlaceResizeUnsupported", err)
+	}
+}
+
+func TestResizePod_Infeasible(t *testing.T) {
+	pod := resizeTestPod("2", "4Gi")
+	pod.Status.Resize = corev1.PodResizeStatusInfeasible
+	client := &Client{clientset: fake.NewSimpleClientset(pod)}
+
+	err := client.ResizePod(context.Background(), "kodama-test", "dev", "64", "512Gi", time.Minute)
+	if !errors.Is(err, ErrInPlaceResizeUnsupported) {
+		t.Errorf("ResizePod() error = %v, want ErrInPlaceResizeUnsupported", err)
+	}
+}
+
+func TestResizePod_NotFound(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	err := client.ResizePod(context.Background(), "missing", "dev", "4", "", time.Minute)
+	if !errors.Is(err, ErrPodNotFound) {
+		t.Errorf("ResizePod() error = %v, want ErrPodNotFound", err)
+	}
+}
+
+func TestResizedRequirements(t *testing.T) {
+	current := corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			corev1.ResourceCPU:                    resource.MustParse("2"),
+			corev1.ResourceMemory:                 resource.MustParse("4Gi"),
+			corev1.ResourceName("nvidia.com/gpu"): resource.MustParse("1"),
+		},
+	}
+
+	desired := resizedRequirements(current, "", "8Gi")
+
+	if got := desired.Limits[corev1.ResourceCPU]; got.String() != "2" {
+		t.Errorf("cpu limit = %s, want unchanged 2", got.String())
+	}
+	if got := desired.Limits[corev1.ResourceMemory]; got.String() != "8Gi" {
+		t.Errorf("memory limit = %s, want 8Gi", got.String())
+	}
+	if got := desired.Requests[corev1.ResourceMemory]; got.String() != "4Gi" {
+		t.Errorf("memory request = %s, want 4Gi", got.String())
+	}
+	if got := desired.Limits[corev1.ResourceName("nvidia.com/gpu")]; got.String() != "1" {
+		t.Errorf("gpu limit = %s, want unchanged 1", got.String())
+	}
+}
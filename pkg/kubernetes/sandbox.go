@@ -0,0 +1,138 @@
+package kubernetes
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SandboxInitializerName is the init container that copies the canonical workspace into the sandbox
+	SandboxInitializerName = "sandbox-initializer"
+
+	// WorkspaceSyncContainerName is the sidecar of a sandbox session that
+	// mounts the canonical workspace writable; sync and apply-changes write
+	// through it
+	WorkspaceSyncContainerName = "workspace-sync"
+
+	// CanonicalWorkspacePath is where the main container of a sandbox session
+	// sees the canonical workspace, read-only
+	CanonicalWorkspacePath = "/kodama/canonical"
+
+	// sandboxVolumeName is the emptyDir holding the agent's copy of the workspace
+	sandboxVolumeName = "sandbox"
+)
+
+// SandboxSeedScript copies the canonical workspace into the sandbox, replacing
+// what the sandbox held before
+const SandboxSeedScript = `find /workspace -mindepth 1 -maxdepth 1 -exec rm -rf {} + && cp -a ` + CanonicalWorkspacePath + `/. /workspace/`
+
+// SandboxChangesScript lists the files that differ between the sandbox at
+// /workspace and the canonical workspace, one per line as "A <path>" (added),
+// "M <path>" (modified) or "D <path>" (deleted). The .git and .kodama
+// directories are left out.
+const SandboxChangesScript = `list() { cd "$1" && find . \( -path ./.git -o -path ./.kodama \) -prune -o \( -type f -o -type l \) -print | sed 's|^\./||'; }
+list /workspace | while IFS= read -r f; do
+    if [ ! -e "` + CanonicalWorkspacePath + `/$f" ] && [ ! -L "` + CanonicalWorkspacePath + `/$f" ]; then echo "A $f"
+    elif ! cmp -s "/workspace/$f" "` + CanonicalWorkspacePath + `/$f"; then echo "M $f"; fi
+done
+list ` + CanonicalWorkspacePath + ` | while IFS= read -r f; do
+    [ -e "/workspace/$f" ] || [ -L "/workspace/$f" ] || echo "D $f"
+done`
+
+// Kinds of sandbox changes
+const (
+	SandboxAdded    = "A"
+	SandboxModified = "M"
+	SandboxDeleted  = "D"
+)
+
+// SandboxChange is a file that differs between the sandbox and the canonical workspace
+type SandboxChange struct {
+	Kind string // SandboxAdded, SandboxModified or SandboxDeleted
+	Path string // Relative to /workspace
+}
+
+// ParseSandboxChanges parses the output of SandboxChangesScript, sorted by path
+func ParseSandboxChanges(output string) ([]SandboxChange, error) {
+	var changes []SandboxChange
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		kind, path, ok := strings.Cut(line, " ")
+		if !ok || path == "" || !slices.Contains([]string{SandboxAdded, SandboxModified, SandboxDeleted}, kind) {
+			return nil, fmt.Errorf("unexpected sandbox change line %q", line)
+		}
+		changes = append(changes, SandboxChange{Kind: kind, Path: path})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// sandboxInitializerContainer builds the init container that seeds the
+// sandbox with the cloned repository and dataset, once they are in place
+func sandboxInitializerContainer(image string) corev1.Container {
+	return corev1.Container{
+		Name:    SandboxInitializerName,
+		Image:   image,
+		Command: []string{"/bin/sh", "-c", SandboxSeedScript},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "workspace", MountPath: CanonicalWorkspacePath, ReadOnly: true},
+			{Name: sandboxVolumeName, MountPath: "/workspace"},
+		},
+	}
+}
+
+// workspaceSyncContainer builds the sidecar that keeps the canonical
+// workspace writable for sync and apply-changes. It runs the session image,
+// which has the tar the sync needs.
+func workspaceSyncContainer(image string) corev1.Container {
+	return corev1.Container{
+		Name:    WorkspaceSyncContainerName,
+		Image:   image,
+		Command: []string{"/bin/sh", "-c", `trap "exit 0" TERM INT; while true; do sleep 3600 & wait $!; done`},
+		VolumeMounts: []corev1.VolumeMount{
+			{Name: "workspace", MountPath: "/workspace"},
+		},
+	}
+}
+
+// applySandbox gives the main container a writable copy of the workspace: an
+// emptyDir at /workspace, seeded from the canonical workspace, which is
+// mounted read-only at CanonicalWorkspacePath. The agent's changes stay in the
+// copy until they are applied with apply-changes.
+func applySandbox(pod *corev1.Pod, spec *PodSpec) {
+	if !spec.Sandbox {
+		return
+	}
+
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name:         sandboxVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+
+	main := &pod.Spec.Containers[0]
+	for i, mount := range main.VolumeMounts {
+		if mount.MountPath == "/workspace" {
+			main.VolumeMounts[i].Name = sandboxVolumeName
+		}
+	}
+	main.VolumeMounts = append(main.VolumeMounts, corev1.VolumeMount{
+		Name:      "workspace",
+		MountPath: CanonicalWorkspacePath,
+		ReadOnly:  true,
+	})
+
+	pod.Spec.InitContainers = append(pod.Spec.InitContainers, sandboxInitializerContainer(spec.Image))
+	pod.Spec.Containers = append(pod.Spec.Containers, workspaceSyncContainer(spec.Image))
+
+	// kubectl exec and logs target the main container without -c
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[defaultContainerAnnotation] = MainContainerName
+}
@@ -0,0 +1,63 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreatePod_Sandbox(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	spec := &PodSpec{
+		Name: "kodama-test", Namespace: "dev", Image: "ubuntu:24.04",
+		GitRepo: "https://github.com/example/repo.git", Sandbox: true,
+	}
+
+	pod, err := client.CreatePod(context.Background(), spec, true)
+	require.NoError(t, err)
+
+	main := pod.Spec.Containers[0]
+	assert.Contains(t, main.VolumeMounts, corev1.VolumeMount{Name: sandboxVolumeName, MountPath: "/workspace"})
+	assert.Contains(t, main.VolumeMounts, corev1.VolumeMount{Name: "workspace", MountPath: CanonicalWorkspacePath, ReadOnly: true})
+	assert.Equal(t, MainContainerName, pod.Annotations[defaultContainerAnnotation])
+
+	// The sandbox is seeded after the clone
+	last := pod.Spec.InitContainers[len(pod.Spec.InitContainers)-1]
+	assert.Equal(t, SandboxInitializerName, last.Name)
+	assert.Equal(t, "ubuntu:24.04", last.Image)
+
+	require.Len(t, pod.Spec.Containers, 2)
+	writer := pod.Spec.Containers[1]
+	assert.Equal(t, WorkspaceSyncContainerName, writer.Name)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "workspace", MountPath: "/workspace"}}, writer.VolumeMounts)
+}
+
+func TestCreatePod_NoSandbox(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	pod, err := client.CreatePod(context.Background(), &PodSpec{Name: "kodama-test", Namespace: "dev", Image: "ubuntu:24.04"}, true)
+	require.NoError(t, err)
+
+	assert.Len(t, pod.Spec.Containers, 1)
+	assert.Contains(t, pod.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "workspace", MountPath: "/workspace"})
+}
+
+func TestParseSandboxChanges(t *testing.T) {
+	changes, err := ParseSandboxChanges("M src/main.go\nA docs/new file.md\nD old.txt\n")
+	require.NoError(t, err)
+	assert.Equal(t, []SandboxChange{
+		{Kind: SandboxAdded, Path: "docs/new file.md"},
+		{Kind: SandboxDeleted, Path: "old.txt"},
+		{Kind: SandboxModified, Path: "src/main.go"},
+	}, changes)
+
+	changes, err = ParseSandboxChanges("")
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = ParseSandboxChanges("X weird\n")
+	assert.Error(t, err)
+}
@@ -2,17 +2,41 @@ package kubernetes
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	corev1ac "k8s.io/client-go/applyconfigurations/core/v1"
 )
 
+// FieldManager identifies kodama as the owner of fields it applies
+const FieldManager = "kodama"
+
 // CreateSecret creates a Kubernetes secret with the given data
 // The secret is labeled with app=kodama and session=<name> for easy management
+// Session labels are added, see withSessionLabels
 // If dryRun is true, returns the manifest without creating it
-func (c *Client) CreateSecret(ctx context.Context, name, namespace string, data map[string]string, dryRun bool) (*corev1.Secret, error) {
+func (c *Client) CreateSecret(ctx context.Context, name, namespace string, data, labels map[string]string, dryRun bool) (*corev1.Secret, error) {
+	secret := NewEnvSecret(name, namespace, data, labels)
+
+	// If dry-run, return the manifest without creating
+	if dryRun {
+		return secret, nil
+	}
+
+	_, err := c.clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// NewEnvSecret builds the env secret of a session (see CreateSecret)
+func NewEnvSecret(name, namespace string, data, labels map[string]string) *corev1.Secret {
 	// Convert string map to byte map (K8s expects []byte values)
 	secretData := make(map[string][]byte)
 	for key, value := range data {
@@ -25,33 +49,80 @@ func (c *Client) CreateSecret(ctx context.Context, name, namespace string, data
 		sessionName = name[len("kodama-env-"):]
 	}
 
-	secret := &corev1.Secret{
+	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
 			Namespace: namespace,
-			Labels: map[string]string{
+			Labels: withSessionLabels(map[string]string{
 				"app":        "kodama",
 				"session":    sessionName,
 				"managed-by": "kodama",
-			},
+			}, labels),
 		},
 		Data: secretData,
 		Type: corev1.SecretTypeOpaque,
 	}
+}
 
-	// If dry-run, return the manifest without creating
+// ApplySecret creates or replaces a secret with server-side apply, so the
+// values are sent once and a secret left by a previous attempt is replaced in
+// the same request instead of being deleted and created again
+// If dryRun is true, returns the manifest without applying it
+func (c *Client) ApplySecret(ctx context.Context, secret *corev1.Secret, dryRun bool) (*corev1.Secret, error) {
 	if dryRun {
 		return secret, nil
 	}
 
-	_, err := c.clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	apply := corev1ac.Secret(secret.Name, secret.Namespace).
+		WithLabels(secret.Labels).
+		WithData(secret.Data).
+		WithType(secret.Type)
+	if len(secret.Annotations) > 0 {
+		apply = apply.WithAnnotations(secret.Annotations)
+	}
+
+	_, err := c.clientset.CoreV1().Secrets(secret.Namespace).Apply(ctx, apply, metav1.ApplyOptions{
+		FieldManager: FieldManager,
+		Force:        true,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create secret: %w", err)
+		return nil, fmt.Errorf("failed to apply secret: %w", err)
 	}
 
 	return secret, nil
 }
 
+// SetSecretsOwner makes the pod the owner of the secrets, so Kubernetes
+// garbage collection deletes them together with the pod, however it goes away
+func (c *Client) SetSecretsOwner(ctx context.Context, namespace, podName string, secretNames ...string) error {
+	pod, err := c.clientset.CoreV1().Pods(namespace).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pod: %w", err)
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"ownerReferences": []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       pod.Name,
+				UID:        pod.UID,
+			}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, name := range secretNames {
+		if _, err := c.clientset.CoreV1().Secrets(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+			return fmt.Errorf("failed to set owner of secret %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
 // DeleteSecret deletes a Kubernetes secret
 // Ignores "not found" errors (secret already deleted)
 func (c *Client) DeleteSecret(ctx context.Context, name, namespace string) error {
@@ -84,3 +155,40 @@ func (c *Client) SecretExists(ctx context.Context, name, namespace string) (bool
 
 	return true, nil
 }
+
+// GetSecretData retrieves the data of a secret as a string map
+func (c *Client) GetSecretData(ctx context.Context, name, namespace string) (map[string]string, error) {
+	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	data := make(map[string]string, len(secret.Data))
+	for key, value := range secret.Data {
+		data[key] = string(value)
+	}
+
+	return data, nil
+}
+
+// UpdateSecretData replaces the data of an existing secret
+// Labels and other metadata are preserved
+func (c *Client) UpdateSecretData(ctx context.Context, name, namespace string, data map[string]string) error {
+	secret, err := c.clientset.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get secret: %w", err)
+	}
+
+	secretData := make(map[string][]byte, len(data))
+	for key, value := range data {
+		secretData[key] = []byte(value)
+	}
+	secret.Data = secretData
+	secret.StringData = nil
+
+	if _, err := c.clientset.CoreV1().Secrets(namespace).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("failed to update secret: %w", err)
+	}
+
+	return nil
+}
@@ -54,7 +54,7 @@ func TestCreateSecret(t *testing.T) {
 			client := &Client{clientset: fakeClientset}
 
 			// Create secret (not dry-run)
-			_, err := client.CreateSecret(context.Background(), tt.secretName, tt.namespace, tt.data, false)
+			_, err := client.CreateSecret(context.Background(), tt.secretName, tt.namespace, tt.data, nil, false)
 
 			if (err != nil) != tt.wantErr {
 				t.Errorf("CreateSecret() error = %v, wantErr %v", err, tt.wantErr)
@@ -216,3 +216,105 @@ func TestSecretExists(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateSecretData(t *testing.T) {
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kodama-env-test",
+			Namespace: "default",
+			Labels: map[string]string{
+				"app":     "kodama",
+				"session": "test",
+			},
+		},
+		Data: map[string][]byte{
+			"OLD_VAR": []byte("old"),
+			"KEEP":    []byte("kept"),
+		},
+	}
+
+	fakeClientset := fake.NewSimpleClientset(existing)
+	client := &Client{clientset: fakeClientset}
+	ctx := context.Background()
+
+	err := client.UpdateSecretData(ctx, "kodama-env-test", "default", map[string]string{
+		"KEEP":    "kept",
+		"NEW_VAR": "new",
+	})
+	if err != nil {
+		t.Fatalf("UpdateSecretData() error = %v", err)
+	}
+
+	data, err := client.GetSecretData(ctx, "kodama-env-test", "default")
+	if err != nil {
+		t.Fatalf("GetSecretData() error = %v", err)
+	}
+
+	if len(data) != 2 || data["KEEP"] != "kept" || data["NEW_VAR"] != "new" {
+		t.Errorf("GetSecretData() = %v, want KEEP and NEW_VAR only", data)
+	}
+
+	secret, err := fakeClientset.CoreV1().Secrets("default").Get(ctx, "kodama-env-test", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get secret: %v", err)
+	}
+	if secret.Labels["session"] != "test" {
+		t.Errorf("labels not preserved: %v", secret.Labels)
+	}
+
+	if err := client.UpdateSecretData(ctx, "kodama-env-missing", "default", map[string]string{}); err == nil {
+		t.Error("UpdateSecretData() expected error for missing secret")
+	}
+}
+
+func TestApplySecret(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{clientset: fake.NewClientset()}
+
+	// Applying twice replaces the values instead of failing because the secret exists
+	for _, value := range []string{"first", "second"} {
+		secret := NewEnvSecret("kodama-env-work", "dev", map[string]string{"TOKEN": value}, nil)
+		if _, err := client.ApplySecret(ctx, secret, false); err != nil {
+			t.Fatalf("ApplySecret() error = %v", err)
+		}
+	}
+
+	data, err := client.GetSecretData(ctx, "kodama-env-work", "dev")
+	if err != nil {
+		t.Fatalf("GetSecretData() error = %v", err)
+	}
+	if data["TOKEN"] != "second" {
+		t.Errorf("TOKEN = %q, want the applied value", data["TOKEN"])
+	}
+}
+
+func TestSetSecretsOwner(t *testing.T) {
+	ctx := context.Background()
+	client := &Client{clientset: fake.NewSimpleClientset(
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "kodama-work", Namespace: "dev", UID: "pod-uid"}},
+		NewEnvSecret("kodama-env-work", "dev", map[string]string{"TOKEN": "x"}, nil),
+		NewFileSecret("kodama-secret-files-work", "dev", map[string][]byte{"/root/.netrc": []byte("x")}, nil),
+	)}
+
+	if err := client.SetSecretsOwner(ctx, "dev", "kodama-work", "kodama-env-work", "kodama-secret-files-work"); err != nil {
+		t.Fatalf("SetSecretsOwner() error = %v", err)
+	}
+
+	for _, name := range []string{"kodama-env-work", "kodama-secret-files-work"} {
+		secret, err := client.clientset.CoreV1().Secrets("dev").Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		refs := secret.OwnerReferences
+		if len(refs) != 1 || refs[0].Kind != "Pod" || refs[0].Name != "kodama-work" || refs[0].UID != "pod-uid" {
+			t.Errorf("%s ownerReferences = %+v, want the pod", name, refs)
+		}
+		if len(secret.Data) == 0 {
+			t.Errorf("%s lost its data", name)
+		}
+	}
+
+	if err := client.SetSecretsOwner(ctx, "dev", "missing", "kodama-env-work"); err == nil {
+		t.Error("SetSecretsOwner() without the pod should fail")
+	}
+}
@@ -0,0 +1,152 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// SharePort is the pod port of the read-only observer terminal
+	SharePort = 7682
+	// ShareUser is the basic auth user of the observer terminal
+	ShareUser = "observer"
+	// sharePIDFile holds the PID of the observer terminal in the pod
+	sharePIDFile = "/tmp/kodama-share.pid"
+)
+
+// ShareStartScript returns a shell script that (re)starts a read-only ttyd on
+// SharePort mirroring the session's live terminal recording, protected with
+// basic auth. A non-zero expires stops it after that duration.
+// The script reads the token from stdin, so it stays out of the kubectl and
+// sh command lines. ttyd only takes credentials as an argument, so the token
+// is still readable by processes in the pod (ps, /proc/<pid>/cmdline).
+func ShareStartScript(expires time.Duration) string {
+	timeout := ""
+	if expires > 0 {
+		timeout = fmt.Sprintf("timeout %d ", int(expires.Seconds()))
+	}
+	return fmt.Sprintf(`set -e
+IFS= read -r token
+if [ -f %[1]s ]; then kill "$(cat %[1]s)" 2>/dev/null || true; rm -f %[1]s; fi
+if [ ! -e %[2]s/latest ]; then
+  f=$(ls -t %[2]s/*.log 2>/dev/null | head -n 1)
+  [ -n "$f" ] || { echo "no terminal recording found in %[2]s" >&2; exit 1; }
+  ln -sfn "$f" %[2]s/latest
+fi
+nohup %[3]s/kodama/bin/ttyd -p %[4]d -c "%[5]s:$token" tail -n +1 -F %[2]s/latest </dev/null >/dev/null 2>&1 &
+echo $! > %[1]s
+`, sharePIDFile, RecordingsDir, timeout, SharePort, ShareUser)
+}
+
+// ShareStopScript returns a shell script that stops the observer terminal
+func ShareStopScript() string {
+	return fmt.Sprintf(`if [ -f %[1]s ]; then kill "$(cat %[1]s)" 2>/dev/null || true; rm -f %[1]s; fi`, sharePIDFile)
+}
+
+// ShareResourceName returns the name of the Service and Ingress exposing a pod's observer terminal
+func ShareResourceName(podName string) string {
+	return podName + "-share"
+}
+
+// CreateShareIngress exposes the observer terminal of a pod through a Service and
+// an Ingress for host. The expiry is recorded in the ExpiresAtAnnotation.
+// Session labels are added. Existing resources are replaced.
+func (c *Client) CreateShareIngress(ctx context.Context, namespace, podName, host, ingressClass string, sessionLabels map[string]string, expiresAt time.Time) error {
+	name := ShareResourceName(podName)
+	if err := c.DeleteShareIngress(ctx, namespace, podName); err != nil {
+		return err
+	}
+
+	labels := withSessionLabels(map[string]string{
+		"app":        "kodama",
+		"session":    podName,
+		"managed-by": "kodama",
+	}, sessionLabels)
+	annotations := map[string]string{}
+	if !expiresAt.IsZero() {
+		annotations[ExpiresAtAnnotation] = expiresAt.UTC().Format(time.RFC3339)
+	}
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels, Annotations: annotations},
+		Spec: corev1.ServiceSpec{
+			Selector: map[string]string{"app": "kodama", "session": podName},
+			Ports: []corev1.ServicePort{{
+				Name:       "share",
+				Port:       SharePort,
+				TargetPort: intstr.FromInt32(SharePort),
+			}},
+		},
+	}
+	if _, err := c.clientset.CoreV1().Services(namespace).Create(ctx, service, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create share service: %w", err)
+	}
+
+	pathType := networkingv1.PathTypePrefix
+	ingress := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels, Annotations: annotations},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				Host: host,
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{{
+							Path:     "/",
+							PathType: &pathType,
+							Backend: networkingv1.IngressBackend{
+								Service: &networkingv1.IngressServiceBackend{
+									Name: name,
+									Port: networkingv1.ServiceBackendPort{Number: SharePort},
+								},
+							},
+						}},
+					},
+				},
+			}},
+		},
+	}
+	if ingressClass != "" {
+		ingress.Spec.IngressClassName = &ingressClass
+	}
+	if _, err := c.clientset.NetworkingV1().Ingresses(namespace).Create(ctx, ingress, metav1.CreateOptions{}); err != nil {
+		return fmt.Errorf("failed to create share ingress: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteShareIngress deletes the Service and Ingress exposing a pod's observer terminal
+// Ignores "not found" errors.
+func (c *Client) DeleteShareIngress(ctx context.Context, namespace, podName string) error {
+	name := ShareResourceName(podName)
+	if err := c.clientset.NetworkingV1().Ingresses(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete share ingress: %w", err)
+	}
+	if err := c.clientset.CoreV1().Services(namespace).Delete(ctx, name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete share service: %w", err)
+	}
+	return nil
+}
+
+// ShareIngressExists reports whether a pod's observer terminal is exposed by a Service or Ingress
+func (c *Client) ShareIngressExists(ctx context.Context, namespace, podName string) (bool, error) {
+	name := ShareResourceName(podName)
+	if _, err := c.clientset.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		return true, nil
+	} else if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get share ingress: %w", err)
+	}
+	if _, err := c.clientset.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{}); err == nil {
+		return true, nil
+	} else if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get share service: %w", err)
+	}
+	return false, nil
+}
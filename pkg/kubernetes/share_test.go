@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestShareStartScript(t *testing.T) {
+	script := ShareStartScript(2 * time.Hour)
+
+	assert.Contains(t, script, "IFS= read -r token\n", "the token is read from stdin")
+	assert.Contains(t, script, `timeout 7200 /kodama/bin/ttyd -p 7682 -c "observer:$token" tail -n +1 -F `+RecordingsDir+"/latest")
+	assert.NotContains(t, script, " -W", "observers must not be able to type")
+
+	assert.NotContains(t, ShareStartScript(0), "timeout")
+}
+
+func TestCreateShareIngress(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	ctx := context.Background()
+	expiresAt := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	exists, err := client.ShareIngressExists(ctx, "dev", "kodama-work")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, client.CreateShareIngress(ctx, "dev", "kodama-work", "work.example.com", "nginx", nil, expiresAt))
+	exists, err = client.ShareIngressExists(ctx, "dev", "kodama-work")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	// Sharing again replaces the resources
+	require.NoError(t, client.CreateShareIngress(ctx, "dev", "kodama-work", "work.example.com", "nginx", nil, expiresAt))
+
+	service, err := client.clientset.CoreV1().Services("dev").Get(ctx, "kodama-work-share", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "kodama", "session": "kodama-work"}, service.Spec.Selector)
+	assert.Equal(t, int32(SharePort), service.Spec.Ports[0].TargetPort.IntVal)
+
+	ingress, err := client.clientset.NetworkingV1().Ingresses("dev").Get(ctx, "kodama-work-share", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "work.example.com", ingress.Spec.Rules[0].Host)
+	assert.Equal(t, "nginx", *ingress.Spec.IngressClassName)
+	assert.Equal(t, "2025-01-10T12:00:00Z", ingress.Annotations[ExpiresAtAnnotation])
+
+	require.NoError(t, client.DeleteShareIngress(ctx, "dev", "kodama-work"))
+	_, err = client.clientset.NetworkingV1().Ingresses("dev").Get(ctx, "kodama-work-share", metav1.GetOptions{})
+	assert.Error(t, err)
+	require.NoError(t, client.DeleteShareIngress(ctx, "dev", "kodama-work"), "deleting twice is not an error")
+}
+
+func TestShareStopScript(t *testing.T) {
+	assert.True(t, strings.HasPrefix(ShareStopScript(), "if [ -f "+sharePIDFile))
+}
@@ -0,0 +1,172 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// SharedMountPath is where the shared scratch PVC is mounted
+	SharedMountPath = "/shared"
+
+	// SharedEnvVar points agents and scripts at the shared scratch volume
+	SharedEnvVar = "KODAMA_SHARED"
+
+	// DefaultSharedPVC is the shared scratch PVC name used when none is configured
+	DefaultSharedPVC = "kodama-shared"
+
+	// DefaultSharedSize is the requested size when kodama creates the shared PVC
+	DefaultSharedSize = "10Gi"
+
+	// SharedWarnPercent is the usage of the shared volume from which start and
+	// 'kodama shared ls' warn that it is filling up
+	SharedWarnPercent = 85
+
+	// sharedVolumeName is the pod volume name for the shared scratch PVC
+	sharedVolumeName = "shared-scratch"
+)
+
+// SharedOptions identifies the shared scratch PVC of a namespace
+type SharedOptions struct {
+	Namespace    string
+	PVC          string
+	Size         string // PVC size if it has to be created (default: DefaultSharedSize)
+	StorageClass string // Storage class if the PVC has to be created
+}
+
+// sharedVolume returns the pod volume and mount for the shared scratch PVC
+func sharedVolume(pvcName string) (corev1.Volume, corev1.VolumeMount) {
+	return corev1.Volume{
+		Name: sharedVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: pvcName,
+			},
+		},
+	}, corev1.VolumeMount{
+		Name:      sharedVolumeName,
+		MountPath: SharedMountPath,
+	}
+}
+
+// BuildSharedPVC builds the shared scratch PVC
+// ReadWriteMany lets sessions on different nodes read each other's artifacts.
+func BuildSharedPVC(opts SharedOptions) (*corev1.PersistentVolumeClaim, error) {
+	return buildReadWriteManyPVC(opts.Namespace, opts.PVC, "shared", opts.Size, DefaultSharedSize, opts.StorageClass)
+}
+
+// EnsureSharedPVC creates the shared scratch PVC if it does not exist
+// Returns true if the PVC was created.
+func (c *Client) EnsureSharedPVC(ctx context.Context, opts SharedOptions) (bool, error) {
+	pvc, err := BuildSharedPVC(opts)
+	if err != nil {
+		return false, err
+	}
+	return c.ensurePVC(ctx, pvc)
+}
+
+// SharedUsageScript prints the size and usage of the shared volume in KiB
+const SharedUsageScript = `df -Pk ` + SharedMountPath + ` | tail -n 1`
+
+// SharedUsage is the capacity of the shared volume as reported by df
+type SharedUsage struct {
+	SizeKiB int64
+	UsedKiB int64
+}
+
+// ParseSharedUsage parses the output of SharedUsageScript
+func ParseSharedUsage(output string) (SharedUsage, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 {
+		return SharedUsage{}, fmt.Errorf("unexpected df output: %q", strings.TrimSpace(output))
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return SharedUsage{}, fmt.Errorf("unexpected df output: %q", strings.TrimSpace(output))
+	}
+	used, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return SharedUsage{}, fmt.Errorf("unexpected df output: %q", strings.TrimSpace(output))
+	}
+	return SharedUsage{SizeKiB: size, UsedKiB: used}, nil
+}
+
+// Percent returns the used share of the volume, rounded up
+func (u SharedUsage) Percent() int {
+	if u.SizeKiB <= 0 {
+		return 0
+	}
+	return int((u.UsedKiB*100 + u.SizeKiB - 1) / u.SizeKiB)
+}
+
+// NearlyFull reports whether usage reached SharedWarnPercent
+func (u SharedUsage) NearlyFull() bool {
+	return u.Percent() >= SharedWarnPercent
+}
+
+// String formats usage as e.g. "7.9Gi of 10Gi used (79%)"
+func (u SharedUsage) String() string {
+	return fmt.Sprintf("%s of %s used (%d%%)", FormatKiB(u.UsedKiB), FormatKiB(u.SizeKiB), u.Percent())
+}
+
+// SharedEntry is a top-level file or directory on the shared volume
+type SharedEntry struct {
+	Name     string
+	SizeKiB  int64
+	Modified time.Time
+}
+
+// SharedListScript prints "<KiB>\t<mtime>\t<name>" for each top-level entry of
+// the shared volume, skipping the filesystem's lost+found
+const SharedListScript = `cd ` + SharedMountPath + ` || exit 1
+for entry in * .[!.]* ..?*; do
+  [ -e "$entry" ] || [ -L "$entry" ] || continue
+  [ "$entry" = lost+found ] && continue
+  printf '%s\t%s\t%s\n' "$(du -sk -- "$entry" | cut -f1)" "$(stat -c %Y -- "$entry")" "$entry"
+done`
+
+// ParseSharedEntries parses the output of SharedListScript, largest first
+func ParseSharedEntries(output string) ([]SharedEntry, error) {
+	var entries []SharedEntry
+	for line := range strings.Lines(output) {
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			continue
+		}
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected shared volume listing: %q", line)
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected size in shared volume listing: %q", line)
+		}
+		modified, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected time in shared volume listing: %q", line)
+		}
+		entries = append(entries, SharedEntry{Name: fields[2], SizeKiB: size, Modified: time.Unix(modified, 0)})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].SizeKiB > entries[j].SizeKiB })
+	return entries, nil
+}
+
+// FormatKiB formats a size in KiB with a binary unit, e.g. "1.5Gi"
+func FormatKiB(kib int64) string {
+	value, units := float64(kib), []string{"Ki", "Mi", "Gi", "Ti"}
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if value == float64(int64(value)) {
+		return fmt.Sprintf("%d%s", int64(value), units[unit])
+	}
+	return fmt.Sprintf("%.1f%s", value, units[unit])
+}
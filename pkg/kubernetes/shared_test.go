@@ -0,0 +1,146 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEnsureSharedPVC(t *testing.T) {
+	fakeClientset := fake.NewSimpleClientset()
+	client := &Client{clientset: fakeClientset}
+	ctx := context.Background()
+
+	opts := SharedOptions{Namespace: "dev", PVC: "team-shared"}
+	created, err := client.EnsureSharedPVC(ctx, opts)
+	if err != nil {
+		t.Fatalf("EnsureSharedPVC() error = %v", err)
+	}
+	if !created {
+		t.Error("EnsureSharedPVC() created = false, want true")
+	}
+
+	pvc, err := fakeClientset.CoreV1().PersistentVolumeClaims("dev").Get(ctx, "team-shared", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("failed to get PVC: %v", err)
+	}
+	if pvc.Spec.AccessModes[0] != corev1.ReadWriteMany {
+		t.Errorf("access mode = %s, want ReadWriteMany", pvc.Spec.AccessModes[0])
+	}
+	if pvc.Labels["component"] != "shared" {
+		t.Errorf("component label = %s, want shared", pvc.Labels["component"])
+	}
+	if got := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; got.String() != DefaultSharedSize {
+		t.Errorf("size = %s, want %s", got.String(), DefaultSharedSize)
+	}
+
+	created, err = client.EnsureSharedPVC(ctx, opts)
+	if err != nil {
+		t.Fatalf("EnsureSharedPVC() second run error = %v", err)
+	}
+	if created {
+		t.Error("EnsureSharedPVC() created = true for existing PVC")
+	}
+}
+
+func TestCreatePod_SharedPVC(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{
+		Name:      "kodama-test",
+		Namespace: "dev",
+		Image:     "kodama:test",
+		SharedPVC: "team-shared",
+	}, true)
+	if err != nil {
+		t.Fatalf("CreatePod() error = %v", err)
+	}
+
+	var found bool
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == "team-shared" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("shared PVC volume not found in pod")
+	}
+	var mounted bool
+	for _, mount := range pod.Spec.Containers[0].VolumeMounts {
+		if mount.Name == sharedVolumeName && mount.MountPath == SharedMountPath {
+			mounted = true
+		}
+	}
+	if !mounted {
+		t.Errorf("shared volume not mounted at %s: %v", SharedMountPath, pod.Spec.Containers[0].VolumeMounts)
+	}
+	if !hasEnv(pod.Spec.Containers[0].Env, SharedEnvVar, SharedMountPath) {
+		t.Errorf("%s not set on main container: %v", SharedEnvVar, pod.Spec.Containers[0].Env)
+	}
+}
+
+func TestParseSharedUsage(t *testing.T) {
+	usage, err := ParseSharedUsage("nfs:/exports/shared  10485760  9437184  1048576  90% /shared\n")
+	if err != nil {
+		t.Fatalf("ParseSharedUsage() error = %v", err)
+	}
+	if usage.Percent() != 90 {
+		t.Errorf("Percent() = %d, want 90", usage.Percent())
+	}
+	if !usage.NearlyFull() {
+		t.Error("NearlyFull() = false at 90%")
+	}
+	if got := usage.String(); got != "9Gi of 10Gi used (90%)" {
+		t.Errorf("String() = %q", got)
+	}
+
+	if (SharedUsage{SizeKiB: 1000, UsedKiB: 10}).NearlyFull() {
+		t.Error("NearlyFull() = true at 1%")
+	}
+
+	if _, err := ParseSharedUsage("df: /shared: No such file or directory"); err == nil {
+		t.Error("ParseSharedUsage() expected error for unexpected output")
+	}
+}
+
+func TestParseSharedEntries(t *testing.T) {
+	entries, err := ParseSharedEntries("12\t1700000000\tapi-schema\n2048\t1700000100\tcodegen out\n")
+	if err != nil {
+		t.Fatalf("ParseSharedEntries() error = %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, want 2", len(entries))
+	}
+	if entries[0].Name != "codegen out" || entries[0].SizeKiB != 2048 {
+		t.Errorf("entries[0] = %+v, want the largest entry first", entries[0])
+	}
+	if !entries[1].Modified.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("entries[1].Modified = %v", entries[1].Modified)
+	}
+
+	if entries, err := ParseSharedEntries(""); err != nil || len(entries) != 0 {
+		t.Errorf("ParseSharedEntries(\"\") = %v, %v; want no entries", entries, err)
+	}
+	if _, err := ParseSharedEntries("garbage"); err == nil {
+		t.Error("ParseSharedEntries() expected error for malformed line")
+	}
+}
+
+func TestFormatKiB(t *testing.T) {
+	tests := map[int64]string{
+		512:                           "512Ki",
+		1024:                          "1Mi",
+		1536:                          "1.5Mi",
+		10 * 1024 * 1024:              "10Gi",
+		3 * 1024 * 1024 * 1024 * 1024: "3072Ti",
+	}
+	for kib, want := range tests {
+		if got := FormatKiB(kib); got != want {
+			t.Errorf("FormatKiB(%d) = %s, want %s", kib, got, want)
+		}
+	}
+}
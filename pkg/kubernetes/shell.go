@@ -0,0 +1,46 @@
+package kubernetes
+
+import (
+	"slices"
+	"strings"
+)
+
+// DefaultShells are tried in order when the session configures no shell, or
+// the configured one is missing from the image
+var DefaultShells = []string{"bash", "zsh", "sh"}
+
+// loginEnvScript sources the profiles a login shell reads, then puts
+// KODAMA_PATH (default /kodama/bin) back on PATH in case a profile replaced PATH
+const loginEnvScript = `[ -r /etc/profile ] && . /etc/profile >/dev/null 2>&1; ` +
+	`[ -r "$HOME/.profile" ] && . "$HOME/.profile" >/dev/null 2>&1; ` +
+	`kodama_path=${KODAMA_PATH:-/kodama/bin}; ` +
+	`case ":$PATH:" in *":$kodama_path:"*) ;; *) PATH="$kodama_path:$PATH" ;; esac; export PATH; `
+
+// ShellSetupScript returns POSIX sh that loads the login environment and sets
+// KODAMA_SHELL to shell, or to the first of DefaultShells found in the image.
+// shell must be validated with config.ValidateShell. The script contains no
+// single quotes so it can be embedded in a single-quoted argument.
+func ShellSetupScript(shell string) string {
+	candidates := slices.DeleteFunc(slices.Clone(DefaultShells), func(s string) bool { return s == shell })
+	if shell != "" {
+		candidates = append([]string{shell}, candidates...)
+	}
+	script := loginEnvScript +
+		`KODAMA_SHELL=; for s in ` + strings.Join(candidates, " ") + `; do ` +
+		`if command -v "$s" >/dev/null 2>&1; then KODAMA_SHELL=$s; break; fi; done; export KODAMA_SHELL; `
+	if shell != "" {
+		script += `[ "$KODAMA_SHELL" = "` + shell + `" ] || echo "` + shell + ` not found, using $KODAMA_SHELL" >&2; `
+	}
+	return script
+}
+
+// InteractiveShellScript returns POSIX sh that opens the session shell in /workspace
+func InteractiveShellScript(shell string) string {
+	return ShellSetupScript(shell) + `cd /workspace 2>/dev/null; exec "$KODAMA_SHELL"`
+}
+
+// ShellCommand returns the command line running command with the session
+// shell in /workspace. command is passed as an argument, not interpolated.
+func ShellCommand(shell, command string) []string {
+	return []string{"/bin/sh", "-c", ShellSetupScript(shell) + `cd /workspace && exec "$KODAMA_SHELL" -c "$1"`, "kodama", command}
+}
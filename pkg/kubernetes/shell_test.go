@@ -0,0 +1,70 @@
+package kubernetes
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runSetupScript runs ShellSetupScript(shell) with a ~/.profile that leaves only
+// the given shells on PATH, and returns what it prints for KODAMA_SHELL and
+// PATH, and its stderr
+func runSetupScript(t *testing.T, shell string, available ...string) (string, string) {
+	t.Helper()
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	for _, name := range available {
+		require.NoError(t, os.Symlink(sh, filepath.Join(dir, name)))
+	}
+
+	home := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".profile"), []byte("PATH="+dir+"\n"), 0o600))
+
+	cmd := exec.Command(sh, "-c", ShellSetupScript(shell)+`echo "$KODAMA_SHELL $PATH"`) //#nosec G204 -- test script
+	cmd.Env = []string{"PATH=" + dir, "HOME=" + home}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	require.NoError(t, err, stderr.String())
+	return strings.TrimSpace(string(out)), stderr.String()
+}
+
+func TestShellSetupScript(t *testing.T) {
+	out, stderr := runSetupScript(t, "", "zsh", "sh")
+	shell, path, _ := strings.Cut(out, " ")
+	assert.Equal(t, "zsh", shell, "bash is missing, so zsh is next")
+	// ~/.profile replaced PATH and /kodama/bin was put back in front
+	entries := filepath.SplitList(path)
+	require.Len(t, entries, 2, path)
+	assert.Equal(t, "/kodama/bin", entries[0])
+	assert.Empty(t, stderr)
+
+	out, _ = runSetupScript(t, "sh", "bash", "sh")
+	assert.True(t, strings.HasPrefix(out, "sh "), "the configured shell wins: %s", out)
+
+	out, stderr = runSetupScript(t, "fish", "sh")
+	assert.True(t, strings.HasPrefix(out, "sh "), out)
+	assert.Contains(t, stderr, "fish not found, using sh")
+}
+
+func TestShellSetupScript_NoSingleQuotes(t *testing.T) {
+	assert.NotContains(t, ShellSetupScript("/usr/bin/zsh"), "'")
+	assert.NotContains(t, InteractiveShellScript(""), "'")
+}
+
+func TestShellCommand(t *testing.T) {
+	command := ShellCommand("zsh", `echo "it's $HOME"`)
+
+	require.Len(t, command, 5)
+	assert.Equal(t, []string{"/bin/sh", "-c"}, command[:2])
+	assert.Contains(t, command[2], "for s in zsh bash sh;")
+	assert.Equal(t, `echo "it's $HOME"`, command[4], "the command is passed as an argument")
+}
@@ -0,0 +1,41 @@
+package kubernetes
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/gitcmd"
+)
+
+// signingKeyVolumeName is the pod volume name for the commit signing key
+const signingKeyVolumeName = "kodama-signing"
+
+// applyGitSigning mounts the commit signing key Secret read-only in the main
+// container, where git and the coding agent sign commits with it
+func applyGitSigning(pod *corev1.Pod, spec *PodSpec) {
+	if spec.GitSigningSecret == "" {
+		return
+	}
+
+	// ssh-keygen refuses private keys readable by others
+	mode := int32(0o400)
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name: signingKeyVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName:  spec.GitSigningSecret,
+				Items:       []corev1.KeyToPath{{Key: gitcmd.SigningKeyName, Path: gitcmd.SigningKeyName}},
+				DefaultMode: &mode,
+			},
+		},
+	})
+	for i := range pod.Spec.Containers {
+		if pod.Spec.Containers[i].Name != MainContainerName {
+			continue
+		}
+		pod.Spec.Containers[i].VolumeMounts = append(pod.Spec.Containers[i].VolumeMounts, corev1.VolumeMount{
+			Name:      signingKeyVolumeName,
+			MountPath: gitcmd.SigningKeyDir,
+			ReadOnly:  true,
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// CheckpointerContainerName is the sidecar that checkpoints spot-friendly workspaces
+	CheckpointerContainerName = "checkpointer"
+
+	// CheckpointRefPrefix is the remote ref namespace workspace checkpoints are pushed to
+	CheckpointRefPrefix = "refs/kodama/checkpoints/"
+
+	// SpotCheckpointInterval is how often the checkpointer pushes a checkpoint
+	SpotCheckpointInterval = 2 * time.Minute
+
+	// SpotGracePeriod leaves the checkpointer time for a final push when the
+	// pod is terminated; spot and preemptible nodes give about 30 seconds of notice
+	SpotGracePeriod = 30 * time.Second
+)
+
+// spotTolerations let the pod schedule onto spot and preemptible nodes of
+// GKE, AKS and EKS (Karpenter) clusters
+var spotTolerations = []corev1.Toleration{
+	{Key: "cloud.google.com/gke-spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "cloud.google.com/gke-preemptible", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: "kubernetes.azure.com/scalesetpriority", Operator: corev1.TolerationOpEqual, Value: "spot", Effect: corev1.TaintEffectNoSchedule},
+	{Key: "karpenter.sh/capacity-type", Operator: corev1.TolerationOpEqual, Value: "spot", Effect: corev1.TaintEffectNoSchedule},
+}
+
+// CheckpointRef returns the remote ref holding the workspace checkpoint of a session pod
+func CheckpointRef(podName string) string {
+	return CheckpointRefPrefix + podName
+}
+
+// CheckpointScript returns a shell script that commits the whole workspace,
+// including uncommitted and untracked files, on top of HEAD and force-pushes
+// it to ref on origin. A temporary index is used, so the branch, index and
+// working tree the agent sees are left untouched. The push is skipped when
+// the workspace did not change since the last checkpoint. The script contains
+// no single quotes so it can be embedded in a single-quoted argument.
+func CheckpointScript(ref string) string {
+	return fmt.Sprintf(`cd /workspace && git rev-parse -q --verify HEAD >/dev/null || exit 0; `+
+		`git config user.email >/dev/null || export GIT_AUTHOR_NAME=kodama GIT_AUTHOR_EMAIL=kodama@localhost GIT_COMMITTER_NAME=kodama GIT_COMMITTER_EMAIL=kodama@localhost; `+
+		`idx=$(mktemp); cp "$(git rev-parse --git-path index)" "$idx" 2>/dev/null; `+
+		`last=$(git rev-parse --git-path kodama-checkpoint); `+
+		`if tree=$(GIT_INDEX_FILE=$idx git add -A && GIT_INDEX_FILE=$idx git write-tree) && [ "$tree" != "$(cat "$last" 2>/dev/null)" ]; then `+
+		`commit=$(git commit-tree --no-gpg-sign -p HEAD -m "kodama checkpoint" "$tree") && `+
+		`git push -q -f origin "$commit:%s" && echo "$tree" > "$last" && echo "checkpoint $commit"; fi; `+
+		`rm -f "$idx"`, ref)
+}
+
+// RestoreCheckpointScript returns a shell script that restores the checkpoint
+// at ref onto a fresh clone: the branch is moved to the checkpoint's parent,
+// including commits that were never pushed, and the checkpoint's files are
+// left as uncommitted changes. It exits 0 without changes when origin has no
+// checkpoint.
+func RestoreCheckpointScript(ref string) string {
+	return fmt.Sprintf(`cd /workspace && git fetch -q origin "%[1]s" 2>/dev/null || { echo "no checkpoint"; exit 0; }; `+
+		`git reset -q --hard FETCH_HEAD && git reset -q "HEAD^" && `+
+		`git rev-parse "FETCH_HEAD^{tree}" > "$(git rev-parse --git-path kodama-checkpoint)" && echo "restored checkpoint"`, ref)
+}
+
+// checkpointerContainer builds the sidecar that pushes a workspace checkpoint
+// every SpotCheckpointInterval and once more when the pod is terminated
+// It runs the session image with the main container's environment, so git
+// finds the same credentials.
+func checkpointerContainer(main *corev1.Container, podName string) corev1.Container {
+	checkpoint := CheckpointScript(CheckpointRef(podName))
+	loop := fmt.Sprintf(`checkpoint() { ( %s ); }; `+
+		`trap "checkpoint; exit 0" TERM INT; `+
+		`while true; do sleep %d & wait $!; checkpoint; done`,
+		checkpoint, int(SpotCheckpointInterval.Seconds()))
+
+	return corev1.Container{
+		Name:    CheckpointerContainerName,
+		Image:   main.Image,
+		Command: []string{"sh", "-c", loop},
+		Env:     slices.Clone(main.Env),
+		EnvFrom: slices.Clone(main.EnvFrom),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				Name:      "workspace",
+				MountPath: "/workspace",
+			},
+		},
+	}
+}
+
+// applySpotFriendly lets a session pod run on spot or preemptible nodes:
+// it tolerates their taints, and for cloned repositories adds the
+// checkpointer sidecar so a recreated pod can resume the workspace
+func applySpotFriendly(pod *corev1.Pod, spec *PodSpec) {
+	if !spec.SpotFriendly {
+		return
+	}
+
+	pod.Spec.Tolerations = append(pod.Spec.Tolerations, spotTolerations...)
+
+	// A workspace PVC survives the node; a synced workspace is re-synced
+	if spec.GitRepo == "" || spec.WorkspacePVC != "" {
+		return
+	}
+
+	grace := int64(SpotGracePeriod.Seconds())
+	pod.Spec.TerminationGracePeriodSeconds = &grace
+	pod.Spec.Containers = append(pod.Spec.Containers, checkpointerContainer(&pod.Spec.Containers[0], spec.Name))
+
+	// kubectl exec and logs target the main container without -c
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[defaultContainerAnnotation] = MainContainerName
+}
@@ -0,0 +1,87 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckpointScripts(t *testing.T) {
+	ref := CheckpointRef("kodama-work")
+	assert.Equal(t, "refs/kodama/checkpoints/kodama-work", ref)
+
+	checkpoint := CheckpointScript(ref)
+	restore := RestoreCheckpointScript(ref)
+	for _, script := range []string{checkpoint, restore} {
+		assert.NotContains(t, script, "'", "script must not contain single quotes")
+	}
+
+	assert.Contains(t, checkpoint, `GIT_INDEX_FILE=$idx git add -A`)
+	assert.Contains(t, checkpoint, `git commit-tree --no-gpg-sign -p HEAD`)
+	assert.Contains(t, checkpoint, `git push -q -f origin "$commit:`+ref+`"`)
+	assert.Contains(t, restore, `git fetch -q origin "`+ref+`"`)
+	assert.Contains(t, restore, `git reset -q --hard FETCH_HEAD && git reset -q "HEAD^"`)
+}
+
+func TestCreatePod_SpotFriendly(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{
+		Name:          "kodama-work",
+		Namespace:     "dev",
+		Image:         "kodama:test",
+		GitRepo:       "https://github.com/example/app.git",
+		EnvSecretName: "kodama-env-work",
+		HTTPSProxy:    "http://proxy:3128",
+		SpotFriendly:  true,
+	}, true)
+	require.NoError(t, err)
+
+	assert.Contains(t, pod.Spec.Tolerations, corev1.Toleration{
+		Key: "cloud.google.com/gke-spot", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule,
+	})
+	require.NotNil(t, pod.Spec.TerminationGracePeriodSeconds)
+	assert.Equal(t, int64(30), *pod.Spec.TerminationGracePeriodSeconds)
+	assert.Equal(t, MainContainerName, pod.Annotations[defaultContainerAnnotation])
+
+	require.Len(t, pod.Spec.Containers, 2)
+	main, sidecar := pod.Spec.Containers[0], pod.Spec.Containers[1]
+	assert.Equal(t, CheckpointerContainerName, sidecar.Name)
+	assert.Equal(t, "kodama:test", sidecar.Image)
+	assert.True(t, strings.Contains(sidecar.Command[2], CheckpointScript(CheckpointRef("kodama-work"))))
+	assert.Contains(t, sidecar.Command[2], `trap "checkpoint; exit 0" TERM`)
+	assert.Equal(t, main.EnvFrom, sidecar.EnvFrom)
+	assert.Contains(t, sidecar.Env, corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"})
+	assert.Equal(t, "/workspace", sidecar.VolumeMounts[0].MountPath)
+}
+
+func TestCreatePod_SpotFriendlyWithoutCheckpoints(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	tests := []struct {
+		name string
+		spec PodSpec
+	}{
+		{"synced workspace", PodSpec{Name: "kodama-work", Namespace: "dev", Image: "kodama:test", SpotFriendly: true}},
+		{"workspace PVC", PodSpec{Name: "kodama-work", Namespace: "dev", Image: "kodama:test", SpotFriendly: true,
+			GitRepo: "https://github.com/example/app.git", WorkspacePVC: "kodama-work-workspace"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pod, err := client.CreatePod(context.Background(), &tt.spec, true)
+			require.NoError(t, err)
+			assert.NotEmpty(t, pod.Spec.Tolerations)
+			assert.Len(t, pod.Spec.Containers, 1)
+			assert.Nil(t, pod.Spec.TerminationGracePeriodSeconds)
+		})
+	}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{Name: "kodama-work", Namespace: "dev", Image: "kodama:test"}, true)
+	require.NoError(t, err)
+	assert.Empty(t, pod.Spec.Tolerations)
+}
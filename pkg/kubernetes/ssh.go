@@ -0,0 +1,42 @@
+package kubernetes
+
+import "fmt"
+
+const (
+	// SSHPort is the port sshd listens on in the main container. It is bound to
+	// the loopback interface and only reachable through port-forward.
+	SSHPort = 2222
+
+	sshdPidFile = "/run/kodama-sshd.pid"
+)
+
+// SSHDSetupScript returns a shell script that installs openssh-server if it is
+// missing, authorizes the public key passed as $1 for root, and starts sshd on
+// SSHPort unless it is already running. The container environment is written
+// to ~/.ssh/environment so that remote IDE terminals see the same variables as
+// kubectl exec. The script contains no single quotes.
+func SSHDSetupScript() string {
+	return fmt.Sprintf(`set -e; `+
+		`if ! command -v sshd >/dev/null 2>&1 && [ ! -x /usr/sbin/sshd ]; then `+
+		`echo "Installing openssh-server..."; `+
+		`if command -v apt-get >/dev/null 2>&1; then apt-get update -qq && DEBIAN_FRONTEND=noninteractive apt-get install -y -qq openssh-server >/dev/null; `+
+		`elif command -v apk >/dev/null 2>&1; then apk add --no-cache openssh-server >/dev/null; `+
+		`elif command -v dnf >/dev/null 2>&1; then dnf install -y -q openssh-server; `+
+		`else echo "no supported package manager to install openssh-server" >&2; exit 1; fi; fi; `+
+		`mkdir -p /root/.ssh /run/sshd; chmod 700 /root/.ssh; touch /root/.ssh/authorized_keys; `+
+		`grep -qxF "$1" /root/.ssh/authorized_keys || echo "$1" >> /root/.ssh/authorized_keys; `+
+		`chmod 600 /root/.ssh/authorized_keys; `+
+		`ssh-keygen -A >/dev/null; `+
+		`env | grep -vE "^(HOME|PWD|SHLVL|_)=" > /root/.ssh/environment || true; `+
+		`if [ -f %[1]s ] && kill -0 "$(cat %[1]s)" 2>/dev/null; then exit 0; fi; `+
+		`%[2]s`,
+		sshdPidFile, sshdStartCommand())
+}
+
+// sshdStartCommand returns the command that starts sshd on SSHPort, also used
+// by the watchdog to restart it
+func sshdStartCommand() string {
+	return fmt.Sprintf(`"$(command -v sshd || echo /usr/sbin/sshd)" -p %[1]d -o ListenAddress=127.0.0.1 -o PidFile=%[2]s `+
+		`-o PasswordAuthentication=no -o PermitRootLogin=prohibit-password -o PermitUserEnvironment=yes`,
+		SSHPort, sshdPidFile)
+}
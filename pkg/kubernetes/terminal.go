@@ -0,0 +1,49 @@
+package kubernetes
+
+import (
+	"regexp"
+	"strconv"
+)
+
+const (
+	// DefaultLocale is the locale of session terminals when none is configured.
+	// C.UTF-8 needs no generated locales, so it works in minimal images too.
+	DefaultLocale = "C.UTF-8"
+	// DefaultTerm is forwarded when the local terminal type is unknown
+	DefaultTerm = "xterm-256color"
+)
+
+// termPattern matches terminal types that are safe to pass to env
+var termPattern = regexp.MustCompile(`^[A-Za-z0-9_.+-]+$`)
+
+// LocaleEnv returns LANG and LC_ALL set to locale, or DefaultLocale when empty
+func LocaleEnv(locale string) map[string]string {
+	if locale == "" {
+		locale = DefaultLocale
+	}
+	return map[string]string{"LANG": locale, "LC_ALL": locale}
+}
+
+// TerminalEnv returns the environment of an interactive terminal: TERM set to
+// the local terminal type, and the session locale. Without a locale, programs
+// in the pod fall back to ASCII and draw box characters as garbage.
+func TerminalEnv(term, locale string) []string {
+	if term == "" || term == "dumb" || !termPattern.MatchString(term) {
+		term = DefaultTerm
+	}
+	env := LocaleEnv(locale)
+	return []string{"TERM=" + term, "LANG=" + env["LANG"], "LC_ALL=" + env["LC_ALL"]}
+}
+
+// TerminalCommand wraps command to run with env in a terminal of cols x rows.
+// kubectl and docker exec relay resizes (SIGWINCH) to the pod, but the first
+// one can arrive after the shell has read its size, leaving it at 80x24 until
+// the window is resized again. Setting the size up front avoids that race.
+// A size of zero leaves the terminal as it is.
+func TerminalCommand(command, env []string, cols, rows int) []string {
+	script := `exec env "$@"`
+	if cols > 0 && rows > 0 {
+		script = "stty cols " + strconv.Itoa(cols) + " rows " + strconv.Itoa(rows) + " 2>/dev/null; " + script
+	}
+	return append(append([]string{"/bin/sh", "-c", script, "kodama"}, env...), command...)
+}
@@ -0,0 +1,43 @@
+package kubernetes
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTerminalEnv(t *testing.T) {
+	assert.Equal(t, []string{"TERM=alacritty", "LANG=en_US.UTF-8", "LC_ALL=en_US.UTF-8"}, TerminalEnv("alacritty", "en_US.UTF-8"))
+	for _, term := range []string{"", "dumb", "xterm;id"} {
+		assert.Equal(t, []string{"TERM=xterm-256color", "LANG=C.UTF-8", "LC_ALL=C.UTF-8"}, TerminalEnv(term, ""), term)
+	}
+}
+
+func TestTerminalCommand(t *testing.T) {
+	env := []string{"TERM=xterm", "LANG=C.UTF-8"}
+	command := []string{"/bin/sh", "-c", "exec bash"}
+
+	assert.Equal(t, []string{
+		"/bin/sh", "-c", `stty cols 120 rows 40 2>/dev/null; exec env "$@"`, "kodama",
+		"TERM=xterm", "LANG=C.UTF-8", "/bin/sh", "-c", "exec bash",
+	}, TerminalCommand(command, env, 120, 40))
+
+	// Not a terminal: the size is left alone
+	assert.Equal(t, `exec env "$@"`, TerminalCommand(command, env, 0, 0)[2])
+}
+
+func TestCreatePod_Locale(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	for locale, want := range map[string]string{"": DefaultLocale, "en_US.UTF-8": "en_US.UTF-8"} {
+		pod, err := client.CreatePod(context.Background(), &PodSpec{Name: "kodama-work", Namespace: "dev", Image: "kodama:test", Locale: locale}, true)
+		require.NoError(t, err)
+		env := pod.Spec.Containers[0].Env
+		assert.Contains(t, env, corev1.EnvVar{Name: "LANG", Value: want})
+		assert.Contains(t, env, corev1.EnvVar{Name: "LC_ALL", Value: want})
+	}
+}
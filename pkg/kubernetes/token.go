@@ -0,0 +1,210 @@
+package kubernetes
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Environment variables for token-based cluster access (e.g. from CI jobs)
+const (
+	EnvServer       = "KODAMA_K8S_SERVER"        // API server URL
+	EnvCAData       = "KODAMA_K8S_CA_DATA"       // Cluster CA, PEM or base64-encoded PEM
+	EnvToken        = "KODAMA_K8S_TOKEN"         // Static bearer token
+	EnvTokenFile    = "KODAMA_K8S_TOKEN_FILE"    // File containing a bearer token, re-read on rotation
+	EnvOIDCAudience = "KODAMA_K8S_OIDC_AUDIENCE" // Fetch GitHub Actions OIDC tokens for this audience
+	EnvNamespace    = "KODAMA_K8S_NAMESPACE"     // Default namespace for the generated context
+)
+
+// tokenContextName names the cluster, user and context of generated kubeconfigs
+const tokenContextName = "kodama"
+
+// TokenAuth describes cluster access with a bearer token instead of a kubeconfig file
+// Exactly one of Token, TokenFile or OIDCAudience is set.
+type TokenAuth struct {
+	Server       string
+	CAData       []byte
+	Token        string
+	TokenFile    string
+	OIDCAudience string
+	Namespace    string
+}
+
+// TokenAuthFromEnv reads TokenAuth from KODAMA_K8S_* variables
+// Returns nil when KODAMA_K8S_SERVER is not set.
+func TokenAuthFromEnv() (*TokenAuth, error) {
+	server := os.Getenv(EnvServer)
+	if server == "" {
+		return nil, nil
+	}
+
+	auth := &TokenAuth{
+		Server:       server,
+		Token:        os.Getenv(EnvToken),
+		TokenFile:    os.Getenv(EnvTokenFile),
+		OIDCAudience: os.Getenv(EnvOIDCAudience),
+		Namespace:    os.Getenv(EnvNamespace),
+	}
+
+	sources := 0
+	for _, v := range []string{auth.Token, auth.TokenFile, auth.OIDCAudience} {
+		if v != "" {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return nil, fmt.Errorf("%s requires exactly one of %s, %s or %s", EnvServer, EnvToken, EnvTokenFile, EnvOIDCAudience)
+	}
+
+	if caData := os.Getenv(EnvCAData); caData != "" {
+		decoded, err := decodeCAData(caData)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", EnvCAData, err)
+		}
+		auth.CAData = decoded
+	}
+
+	return auth, nil
+}
+
+// decodeCAData accepts a PEM certificate as-is or base64-encoded (as in kubeconfig files)
+func decodeCAData(data string) ([]byte, error) {
+	if strings.HasPrefix(strings.TrimSpace(data), "-----BEGIN") {
+		return []byte(data), nil
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(data))
+}
+
+// NewClientWithToken creates a Kubernetes client that authenticates with a bearer token
+func NewClientWithToken(server string, caData []byte, token string) (*Client, error) {
+	auth := &TokenAuth{Server: server, CAData: caData, Token: token}
+
+	config, err := auth.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes clientset: %w", err)
+	}
+
+	return &Client{
+		clientset: clientset,
+		config:    &Config{},
+	}, nil
+}
+
+// RESTConfig builds a client-go config for the token source
+func (t *TokenAuth) RESTConfig() (*rest.Config, error) {
+	kubeconfig, err := t.kubeconfig()
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientcmd.NewDefaultClientConfig(*kubeconfig, &clientcmd.ConfigOverrides{}).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build config for %s: %w", t.Server, err)
+	}
+	return config, nil
+}
+
+// Kubeconfig renders the token source as a kubeconfig file, so that kubectl
+// subprocesses authenticate the same way as the client
+func (t *TokenAuth) Kubeconfig() ([]byte, error) {
+	kubeconfig, err := t.kubeconfig()
+	if err != nil {
+		return nil, err
+	}
+	return clientcmd.Write(*kubeconfig)
+}
+
+func (t *TokenAuth) kubeconfig() (*clientcmdapi.Config, error) {
+	user := clientcmdapi.NewAuthInfo()
+	switch {
+	case t.Token != "":
+		user.Token = t.Token
+	case t.TokenFile != "":
+		user.TokenFile = t.TokenFile
+	case t.OIDCAudience != "":
+		// Tokens expire within minutes, so fetch them on demand through
+		// 'kubectl-kodama credential' as an exec credential plugin
+		executable, err := os.Executable()
+		if err != nil {
+			return nil, fmt.Errorf("failed to locate kubectl-kodama for the credential plugin: %w", err)
+		}
+		user.Exec = &clientcmdapi.ExecConfig{
+			APIVersion:      ExecCredentialAPIVersion,
+			Command:         executable,
+			Args:            []string{"credential", "--audience", t.OIDCAudience},
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}
+	default:
+		return nil, fmt.Errorf("no token configured for %s", t.Server)
+	}
+
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = t.Server
+	cluster.CertificateAuthorityData = t.CAData
+
+	context := clientcmdapi.NewContext()
+	context.Cluster = tokenContextName
+	context.AuthInfo = tokenContextName
+	context.Namespace = t.Namespace
+
+	config := clientcmdapi.NewConfig()
+	config.Clusters[tokenContextName] = cluster
+	config.AuthInfos[tokenContextName] = user
+	config.Contexts[tokenContextName] = context
+	config.CurrentContext = tokenContextName
+	return config, nil
+}
+
+// ExportTokenKubeconfig writes a kubeconfig for KODAMA_K8S_* variables to a
+// temporary file and points KUBECONFIG at it. It does nothing when KUBECONFIG
+// is already set or no token source is configured. The returned cleanup
+// function removes the file.
+func ExportTokenKubeconfig() (cleanup func(), err error) {
+	cleanup = func() {}
+	if os.Getenv("KUBECONFIG") != "" {
+		return cleanup, nil
+	}
+
+	auth, err := TokenAuthFromEnv()
+	if err != nil || auth == nil {
+		return cleanup, err
+	}
+
+	data, err := auth.Kubeconfig()
+	if err != nil {
+		return cleanup, err
+	}
+
+	f, err := os.CreateTemp("", "kodama-kubeconfig-*")
+	if err != nil {
+		return cleanup, fmt.Errorf("failed to create kubeconfig: %w", err)
+	}
+	path := f.Name()
+	cleanup = func() { _ = os.Remove(path) }
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		cleanup()
+		return func() {}, fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return func() {}, fmt.Errorf("failed to write kubeconfig: %w", err)
+	}
+
+	if err := os.Setenv("KUBECONFIG", path); err != nil {
+		cleanup()
+		return func() {}, err
+	}
+	return cleanup, nil
+}
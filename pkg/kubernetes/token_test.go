@@ -0,0 +1,115 @@
+package kubernetes
+
+import (
+	"encoding/base64"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const testCAPEM = "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n"
+
+func clearTokenEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{EnvServer, EnvCAData, EnvToken, EnvTokenFile, EnvOIDCAudience, EnvNamespace} {
+		t.Setenv(name, "")
+	}
+}
+
+func TestTokenAuthFromEnv(t *testing.T) {
+	t.Run("unset", func(t *testing.T) {
+		clearTokenEnv(t)
+		auth, err := TokenAuthFromEnv()
+		require.NoError(t, err)
+		assert.Nil(t, auth)
+	})
+
+	t.Run("static token with base64 CA", func(t *testing.T) {
+		clearTokenEnv(t)
+		t.Setenv(EnvServer, "https://k8s.example.com")
+		t.Setenv(EnvToken, "secret")
+		t.Setenv(EnvCAData, base64.StdEncoding.EncodeToString([]byte(testCAPEM)))
+
+		auth, err := TokenAuthFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, "secret", auth.Token)
+		assert.Equal(t, testCAPEM, string(auth.CAData))
+	})
+
+	t.Run("PEM CA", func(t *testing.T) {
+		clearTokenEnv(t)
+		t.Setenv(EnvServer, "https://k8s.example.com")
+		t.Setenv(EnvTokenFile, "/var/run/token")
+		t.Setenv(EnvCAData, testCAPEM)
+
+		auth, err := TokenAuthFromEnv()
+		require.NoError(t, err)
+		assert.Equal(t, testCAPEM, string(auth.CAData))
+	})
+
+	t.Run("requires exactly one token source", func(t *testing.T) {
+		clearTokenEnv(t)
+		t.Setenv(EnvServer, "https://k8s.example.com")
+		_, err := TokenAuthFromEnv()
+		assert.Error(t, err)
+
+		t.Setenv(EnvToken, "secret")
+		t.Setenv(EnvOIDCAudience, "kodama")
+		_, err = TokenAuthFromEnv()
+		assert.Error(t, err)
+	})
+}
+
+func TestTokenAuthKubeconfig(t *testing.T) {
+	auth := &TokenAuth{Server: "https://k8s.example.com", CAData: []byte(testCAPEM), Token: "secret", Namespace: "ci"}
+
+	data, err := auth.Kubeconfig()
+	require.NoError(t, err)
+
+	config, err := clientcmd.Load(data)
+	require.NoError(t, err)
+	assert.Equal(t, "kodama", config.CurrentContext)
+	assert.Equal(t, "https://k8s.example.com", config.Clusters["kodama"].Server)
+	assert.Equal(t, testCAPEM, string(config.Clusters["kodama"].CertificateAuthorityData))
+	assert.Equal(t, "secret", config.AuthInfos["kodama"].Token)
+	assert.Equal(t, "ci", config.Contexts["kodama"].Namespace)
+}
+
+func TestTokenAuthRESTConfig_OIDC(t *testing.T) {
+	auth := &TokenAuth{Server: "https://k8s.example.com", OIDCAudience: "kodama"}
+
+	config, err := auth.RESTConfig()
+	require.NoError(t, err)
+	require.NotNil(t, config.ExecProvider)
+	assert.Equal(t, ExecCredentialAPIVersion, config.ExecProvider.APIVersion)
+	assert.Equal(t, []string{"credential", "--audience", "kodama"}, config.ExecProvider.Args)
+}
+
+func TestNewClientWithToken(t *testing.T) {
+	client, err := NewClientWithToken("https://k8s.example.com", nil, "secret")
+	require.NoError(t, err)
+	assert.NotNil(t, client.clientset)
+}
+
+func TestExportTokenKubeconfig(t *testing.T) {
+	clearTokenEnv(t)
+	t.Setenv("KUBECONFIG", "")
+	t.Setenv(EnvServer, "https://k8s.example.com")
+	t.Setenv(EnvToken, "secret")
+
+	cleanup, err := ExportTokenKubeconfig()
+	require.NoError(t, err)
+
+	path := os.Getenv("KUBECONFIG")
+	require.NotEmpty(t, path)
+	config, err := clientcmd.LoadFromFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", config.AuthInfos["kodama"].Token)
+
+	cleanup()
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
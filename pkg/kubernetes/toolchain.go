@@ -0,0 +1,60 @@
+package kubernetes
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes/initcontainer"
+)
+
+// ToolchainInstallerName is the init container that installs the session's toolchains with mise
+const ToolchainInstallerName = "toolchain-installer"
+
+// toolchainVolumeName is the pod volume holding mise and the session's tool versions
+const toolchainVolumeName = "kodama-tools"
+
+// toolchainCacheMountPath returns the cache mount path toolchains are
+// installed under, or "" when the pod has no shared cache
+func toolchainCacheMountPath(spec *PodSpec) string {
+	if spec.CachePVC == "" {
+		return ""
+	}
+	return CacheMountPath
+}
+
+// toolchainInstallerConfig returns the toolchain installer configuration for spec
+func toolchainInstallerConfig(spec *PodSpec) *initcontainer.ToolchainInstallerConfig {
+	config := initcontainer.NewToolchainInstallerConfig(spec.Image, spec.Tools, toolchainVolumeName)
+	if mountPath := toolchainCacheMountPath(spec); mountPath != "" {
+		config = config.WithCache(cacheVolumeName, mountPath)
+	}
+	return config
+}
+
+// applyToolchain mounts the toolchains installed by the toolchain installer
+// into the main container and puts their shims first on PATH. KODAMA_PATH
+// lets the session shell restore them when a profile resets PATH.
+func applyToolchain(pod *corev1.Pod, spec *PodSpec) {
+	if len(spec.Tools) == 0 {
+		return
+	}
+	pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+		Name:         toolchainVolumeName,
+		VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{}},
+	})
+
+	main := &pod.Spec.Containers[0]
+	main.VolumeMounts = append(main.VolumeMounts, corev1.VolumeMount{
+		Name:      toolchainVolumeName,
+		MountPath: initcontainer.ToolchainMountPath,
+	})
+
+	cacheMountPath := toolchainCacheMountPath(spec)
+	toolchainPath := initcontainer.ToolchainPath(cacheMountPath)
+	for i, env := range main.Env {
+		if env.Name == "PATH" {
+			main.Env[i].Value = toolchainPath + ":" + env.Value
+		}
+	}
+	main.Env = append(main.Env, initcontainer.ToolchainEnvVars(cacheMountPath)...)
+	main.Env = append(main.Env, corev1.EnvVar{Name: "KODAMA_PATH", Value: toolchainPath + ":/kodama/bin"})
+}
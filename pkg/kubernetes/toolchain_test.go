@@ -0,0 +1,67 @@
+package kubernetes
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes/initcontainer"
+)
+
+func TestCreatePod_Toolchain(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	spec := &PodSpec{
+		Name: "kodama-test", Namespace: "dev", Image: "ubuntu:24.04",
+		Tools: []string{"go@1.23", "node@22"},
+	}
+
+	pod, err := client.CreatePod(context.Background(), spec, true)
+	require.NoError(t, err)
+
+	var installer *corev1.Container
+	for i := range pod.Spec.InitContainers {
+		if pod.Spec.InitContainers[i].Name == ToolchainInstallerName {
+			installer = &pod.Spec.InitContainers[i]
+		}
+	}
+	require.NotNil(t, installer)
+	assert.Equal(t, "ubuntu:24.04", installer.Image, "toolchains are built for the session image")
+
+	main := pod.Spec.Containers[0]
+	assert.Contains(t, main.VolumeMounts, corev1.VolumeMount{Name: toolchainVolumeName, MountPath: initcontainer.ToolchainMountPath})
+	env := map[string]string{}
+	for _, e := range main.Env {
+		env[e.Name] = e.Value
+	}
+	assert.True(t, strings.HasPrefix(env["PATH"], "/kodama/tools/data/shims:/kodama/tools/bin:/kodama/bin:"), env["PATH"])
+	assert.Equal(t, "/kodama/tools/data/shims:/kodama/tools/bin:/kodama/bin", env["KODAMA_PATH"])
+	assert.Equal(t, "/kodama/tools/config", env["MISE_CONFIG_DIR"])
+
+	// With a shared cache the toolchains are installed there
+	spec.CachePVC = DefaultCachePVC
+	pod, err = client.CreatePod(context.Background(), spec, true)
+	require.NoError(t, err)
+	for _, e := range pod.Spec.Containers[0].Env {
+		if e.Name == "MISE_DATA_DIR" {
+			assert.Equal(t, CacheMountPath+"/mise", e.Value)
+		}
+	}
+}
+
+func TestCreatePod_NoToolchain(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+	pod, err := client.CreatePod(context.Background(), &PodSpec{Name: "kodama-test", Namespace: "dev", Image: "ubuntu:24.04"}, true)
+	require.NoError(t, err)
+
+	for _, container := range pod.Spec.InitContainers {
+		assert.NotEqual(t, ToolchainInstallerName, container.Name)
+	}
+	for _, volume := range pod.Spec.Volumes {
+		assert.NotEqual(t, toolchainVolumeName, volume.Name)
+	}
+}
@@ -1,6 +1,8 @@
 package kubernetes
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -24,6 +26,8 @@ type PodSpec struct {
 	Image           string
 	WorkspacePVC    string
 	ClaudeHomePVC   string
+	CachePVC        string // Shared dependency cache mounted at CacheMountPath
+	SharedPVC       string // Shared scratch volume mounted at SharedMountPath
 	CPULimit        string
 	MemoryLimit     string
 	CustomResources map[string]string // e.g., "nvidia.com/gpu": "1"
@@ -36,18 +40,96 @@ type PodSpec struct {
 	FileSecretName string            // K8s secret name for files
 	FileMappings   map[string]string // secretKey → destinationPath
 
+	// Proxy and extra CA bundle applied to every container, see applyNetworkSettings
+	HTTPProxy      string
+	HTTPSProxy     string
+	NoProxy        string
+	CABundleSecret string // Secret with a CABundleKey PEM bundle
+
+	// Source of Claude Code and ttyd binaries for the tools-installer init container
+	// An empty source or "download" fetches them from claude.ai and GitHub.
+	InstallerSource    string // download, configmap, pvc or image
+	InstallerConfigMap string
+	InstallerPVC       string
+	InstallerImage     string
+	InstallerPath      string
+
 	// Git repository configuration for workspace-initializer init container
-	GitRepo         string // Git repository URL (empty if no repo)
-	GitBranch       string // Feature branch name to create
-	GitCloneDepth   int    // Clone depth (0 for full clone)
-	GitSingleBranch bool   // Whether to clone single branch only
-	GitCloneArgs    string // Additional git clone arguments
+	GitRepo          string // Git repository URL (empty if no repo)
+	GitBranch        string // Feature branch name to create
+	GitCloneDepth    int    // Clone depth (0 for full clone)
+	GitSingleBranch  bool   // Whether to clone single branch only
+	GitCloneArgs     string // Additional git clone arguments
+	GitCloneAttempts int    // Clone attempts per remote (0 for default)
+	GitMirror        string // Alternate remote used when cloning GitRepo fails
+	GitUserName      string // Commit author written to the cloned repository's config
+	GitUserEmail     string
+	GitSigningSecret string // Secret with the commit signing key (empty = unsigned)
+	GitSigningFormat string // ssh or gpg
+	GitSigningKey    string // GPG key ID
+
+	// Dataset copied into the workspace by the data-fetcher init container, after the clone
+	DataURI               string // s3://, gs:// or rclone remote (empty = no fetch)
+	DataDestination       string // Absolute directory inside /workspace
+	DataCredentialsSecret string // Secret with the object store credentials (empty = pod identity)
+	DataImage             string // Replaces the default CLI image for the URI scheme
+
+	// Toolchains installed with mise by the toolchain-installer init container, e.g. go@1.23
+	// They are installed into the shared cache when CachePVC is set.
+	Tools []string
+
+	// Sandbox mounts the workspace read-only and gives the main container a
+	// writable copy at /workspace, see applySandbox
+	Sandbox bool
 
 	// Ttyd (Web-based terminal) configuration
 	TtydEnabled  bool
 	TtydPort     int
 	TtydOptions  string
 	TtydWritable bool
+
+	// Editor settings: EDITOR/VISUAL, config files from a ConfigMap mounted
+	// under EditorConfigHome, and an optional code-server sidecar
+	Editor            string
+	EditorConfigMap   string
+	EditorConfigFiles []string // Paths relative to EditorConfigHome
+	CodeServerEnabled bool
+	CodeServerImage   string
+	CodeServerPort    int
+
+	// Shell opened by ttyd connections, see ShellSetupScript (empty = detect)
+	Shell string
+
+	// Locale set as LANG and LC_ALL in the main container (empty = DefaultLocale)
+	Locale string
+
+	// RestartPolicy of the pod: Never, OnFailure or Always (empty = Never)
+	RestartPolicy string
+
+	// Watchdog runs the main container's command under WatchdogScript, restarting ttyd and sshd when they die
+	Watchdog bool
+
+	// RecordTerminal runs each ttyd connection under script(1), see RecordedShellScript
+	RecordTerminal bool
+
+	// SpotFriendly tolerates spot node taints and checkpoints cloned workspaces, see applySpotFriendly
+	SpotFriendly bool
+
+	// NoEvict annotates the pod as not safe to evict for the cluster autoscaler, see applyNoEvict
+	NoEvict bool
+
+	// Volumes are existing volumes from the session template mounted into the main container
+	Volumes []VolumeMountSpec
+
+	// Labels are session labels added to the pod, e.g. for cost allocation
+	Labels map[string]string
+
+	// PodOverrides are PodSpec fields patched onto the generated pod, see ApplyPodOverrides
+	PodOverrides map[string]interface{}
+
+	// ExpiresAt is recorded as a pod annotation so the reaper CronJob can
+	// delete the session even when the local machine is offline
+	ExpiresAt *time.Time
 }
 
 // PVCSpec contains specifications for creating a PersistentVolumeClaim
@@ -74,4 +156,9 @@ type PodStatus struct {
 	StartTime  string
 	Conditions []corev1.PodCondition
 	Ready      bool
+	Lock       *SessionLock // Current lock lease; nil when unlocked or expired
+
+	// Image and Limits of the main container
+	Image  string
+	Limits corev1.ResourceList
 }
@@ -0,0 +1,36 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// ServerVersion returns the Kubernetes API server version, e.g. "v1.32.1"
+func (c *Client) ServerVersion() (string, error) {
+	info, err := c.clientset.Discovery().ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("failed to get server version: %w", err)
+	}
+	return info.GitVersion, nil
+}
+
+// KubectlVersion returns the version of the kubectl binary on PATH, e.g. "v1.32.1"
+func KubectlVersion(ctx context.Context) (string, error) {
+	out, err := procutil.CommandContext(ctx, "kubectl", "version", "--client", "-o", "json").Output()
+	if err = procutil.Err(ctx, err); err != nil {
+		return "", fmt.Errorf("failed to run kubectl version: %w", err)
+	}
+
+	var payload struct {
+		ClientVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"clientVersion"`
+	}
+	if err := json.Unmarshal(out, &payload); err != nil {
+		return "", fmt.Errorf("failed to parse kubectl version: %w", err)
+	}
+	return payload.ClientVersion.GitVersion, nil
+}
@@ -0,0 +1,45 @@
+package kubernetes
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// userVolumePrefix keeps template volume names apart from kodama's own volumes
+const userVolumePrefix = "vol-"
+
+// VolumeMountSpec mounts an existing volume into the main container
+// Exactly one of PVC, ConfigMap, Secret and HostPath is set.
+type VolumeMountSpec struct {
+	Name      string
+	MountPath string
+	SubPath   string
+	ReadOnly  bool
+
+	PVC       string
+	ConfigMap string
+	Secret    string
+	HostPath  string
+}
+
+// userVolume returns the pod volume and main container mount for v
+func userVolume(v VolumeMountSpec) (corev1.Volume, corev1.VolumeMount) {
+	var source corev1.VolumeSource
+	switch {
+	case v.PVC != "":
+		source.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{ClaimName: v.PVC, ReadOnly: v.ReadOnly}
+	case v.ConfigMap != "":
+		source.ConfigMap = &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: v.ConfigMap}}
+	case v.Secret != "":
+		source.Secret = &corev1.SecretVolumeSource{SecretName: v.Secret}
+	case v.HostPath != "":
+		source.HostPath = &corev1.HostPathVolumeSource{Path: v.HostPath}
+	}
+
+	name := userVolumePrefix + v.Name
+	return corev1.Volume{Name: name, VolumeSource: source}, corev1.VolumeMount{
+		Name:      name,
+		MountPath: v.MountPath,
+		SubPath:   v.SubPath,
+		ReadOnly:  v.ReadOnly,
+	}
+}
@@ -0,0 +1,133 @@
+package kubernetes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/shellutil"
+)
+
+const (
+	// WatchdogDir holds the watchdog's heartbeat and restart log in the main container
+	WatchdogDir = "/tmp/kodama-watchdog"
+
+	// WatchdogInterval is how often the watchdog writes its heartbeat and checks its processes
+	WatchdogInterval = 10 * time.Second
+
+	// WatchdogStaleAfter is the heartbeat age after which the watchdog is
+	// considered hung; the liveness probe then restarts the main container
+	WatchdogStaleAfter = 6 * WatchdogInterval
+
+	watchdogHeartbeatFile = WatchdogDir + "/heartbeat"
+	watchdogRestartsFile  = WatchdogDir + "/restarts"
+	watchdogExitFile      = WatchdogDir + "/exit"
+)
+
+// WatchdogScript returns a shell script that runs as the main container's
+// entrypoint instead of command, e.g. ttyd or sleep infinity. It starts
+// command in the background, writes the current time to the heartbeat file
+// every WatchdogInterval, restarts command when it exits, and restarts sshd
+// when it was set up (see SSHDSetupScript) and died. Each restart is appended
+// to the restart log. The watchdog runs in the main container rather than a
+// sidecar, so restarted processes see the same filesystem and environment.
+// The script contains no single quotes outside of the quoted command.
+func WatchdogScript(command []string) string {
+	if len(command) == 0 {
+		command = []string{"sleep", "infinity"}
+	}
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = shellutil.Quote(arg)
+	}
+
+	return fmt.Sprintf(`mkdir -p %[1]s; `+
+		`start() { rm -f %[3]s; ( %[6]s; echo $? > %[3]s ) & pid=$!; }; `+
+		`stop() { kill "$pid" 2>/dev/null; [ -f %[5]s ] && kill "$(cat %[5]s)" 2>/dev/null; exit 0; }; `+
+		`restarted() { echo "$(date -u +%%Y-%%m-%%dT%%H:%%M:%%SZ) $1" >> %[4]s; }; `+
+		`trap stop TERM INT; start; `+
+		`while true; do date +%%s > %[2]s; `+
+		`if [ -f %[3]s ]; then restarted "main process exited with $(cat %[3]s)"; start; fi; `+
+		`if [ -f %[5]s ] && ! kill -0 "$(cat %[5]s)" 2>/dev/null; then restarted "sshd exited"; %[7]s; fi; `+
+		`sleep %[8]d & wait $!; done`,
+		WatchdogDir, watchdogHeartbeatFile, watchdogExitFile, watchdogRestartsFile,
+		sshdPidFile, strings.Join(quoted, " "), sshdStartCommand(), int(WatchdogInterval.Seconds()))
+}
+
+// watchdogLivenessProbe fails when the watchdog has not written its heartbeat
+// for WatchdogStaleAfter, so a hung main container is restarted
+func watchdogLivenessProbe() *corev1.Probe {
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"/bin/sh", "-c", fmt.Sprintf(`test $(( $(date +%%s) - $(cat %s) )) -lt %d`,
+					watchdogHeartbeatFile, int(WatchdogStaleAfter.Seconds()))},
+			},
+		},
+		InitialDelaySeconds: int32(WatchdogInterval.Seconds()),
+		PeriodSeconds:       int32(WatchdogStaleAfter.Seconds() / 2),
+		FailureThreshold:    2,
+	}
+}
+
+// applyWatchdog runs the main container's command under WatchdogScript and
+// adds the heartbeat liveness probe
+func applyWatchdog(pod *corev1.Pod, spec *PodSpec) {
+	if !spec.Watchdog {
+		return
+	}
+	main := &pod.Spec.Containers[0]
+	main.Command = []string{"/bin/sh", "-c", WatchdogScript(main.Command)}
+	main.LivenessProbe = watchdogLivenessProbe()
+}
+
+// WatchdogStatusScript prints the watchdog's heartbeat, number of restarts and
+// last restart as key=value lines for ParseWatchdogStatus
+func WatchdogStatusScript() string {
+	return fmt.Sprintf(`echo "heartbeat=$(cat %[1]s 2>/dev/null)"; `+
+		`echo "restarts=$(cat %[2]s 2>/dev/null | wc -l)"; `+
+		`echo "last=$(tail -n 1 %[2]s 2>/dev/null)"`, watchdogHeartbeatFile, watchdogRestartsFile)
+}
+
+// WatchdogStatus is the health of a session's watchdog
+type WatchdogStatus struct {
+	Heartbeat   time.Time // Last heartbeat; zero if the watchdog never wrote one
+	Restarts    int       // Processes restarted since the main container started
+	LastRestart string    // e.g. "2024-05-01T10:00:00Z main process exited with 1"
+}
+
+// Healthy reports whether the heartbeat is more recent than WatchdogStaleAfter
+func (s *WatchdogStatus) Healthy(now time.Time) bool {
+	return !s.Heartbeat.IsZero() && now.Sub(s.Heartbeat) < WatchdogStaleAfter
+}
+
+// ParseWatchdogStatus parses the output of WatchdogStatusScript
+func ParseWatchdogStatus(output string) (*WatchdogStatus, error) {
+	status := &WatchdogStatus{}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || value == "" {
+			continue
+		}
+		switch key {
+		case "heartbeat":
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid watchdog heartbeat %q", value)
+			}
+			status.Heartbeat = time.Unix(seconds, 0)
+		case "restarts":
+			restarts, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return nil, fmt.Errorf("invalid watchdog restart count %q", value)
+			}
+			status.Restarts = restarts
+		case "last":
+			status.LastRestart = value
+		}
+	}
+	return status, nil
+}
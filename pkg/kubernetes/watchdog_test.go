@@ -0,0 +1,114 @@
+package kubernetes
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestWatchdogScript(t *testing.T) {
+	script := WatchdogScript([]string{"sleep", "infinity"})
+
+	for _, want := range []string{
+		"mkdir -p " + WatchdogDir,
+		"( 'sleep' 'infinity'; echo $? > " + watchdogExitFile + " ) &",
+		"date +%s > " + watchdogHeartbeatFile,
+		"trap stop TERM INT",
+		">> " + watchdogRestartsFile,
+		sshdStartCommand(),
+		"sleep 10 & wait $!",
+	} {
+		if !strings.Contains(script, want) {
+			t.Errorf("script missing %q:\n%s", want, script)
+		}
+	}
+
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh not found")
+	}
+	if out, err := exec.Command(sh, "-n", "-c", script).CombinedOutput(); err != nil { //#nosec G204 -- test script
+		t.Errorf("script is not valid shell: %v\n%s", err, out)
+	}
+}
+
+func TestWatchdogScript_DefaultCommand(t *testing.T) {
+	if script := WatchdogScript(nil); !strings.Contains(script, "( 'sleep' 'infinity';") {
+		t.Errorf("script without a command should supervise sleep infinity:\n%s", script)
+	}
+}
+
+func TestCreatePod_Watchdog(t *testing.T) {
+	client := &Client{clientset: fake.NewSimpleClientset()}
+
+	pod, err := client.CreatePod(context.Background(), &PodSpec{
+		Name:        "kodama-test",
+		Namespace:   "dev",
+		Image:       "kodama:test",
+		TtydEnabled: true,
+		Watchdog:    true,
+	}, true)
+	if err != nil {
+		t.Fatalf("CreatePod() error = %v", err)
+	}
+
+	main := pod.Spec.Containers[0]
+	if len(main.Command) != 3 || !strings.Contains(main.Command[2], "heartbeat") {
+		t.Fatalf("main container does not run the watchdog: %v", main.Command)
+	}
+	if !strings.Contains(main.Command[2], "/kodama/bin/ttyd -p 7681") {
+		t.Errorf("watchdog does not supervise ttyd: %s", main.Command[2])
+	}
+	if main.LivenessProbe == nil || main.LivenessProbe.Exec == nil {
+		t.Fatal("main container has no heartbeat liveness probe")
+	}
+	if !strings.Contains(main.LivenessProbe.Exec.Command[2], watchdogHeartbeatFile) {
+		t.Errorf("liveness probe does not check the heartbeat: %v", main.LivenessProbe.Exec.Command)
+	}
+
+	pod, err = client.CreatePod(context.Background(), &PodSpec{Name: "kodama-plain", Namespace: "dev", Image: "kodama:test", Command: []string{"sleep", "infinity"}}, true)
+	if err != nil {
+		t.Fatalf("CreatePod() error = %v", err)
+	}
+	if pod.Spec.Containers[0].LivenessProbe != nil {
+		t.Error("pod without watchdog should have no liveness probe")
+	}
+}
+
+func TestParseWatchdogStatus(t *testing.T) {
+	status, err := ParseWatchdogStatus("heartbeat=1714557600\nrestarts=       2\nlast=2024-05-01T09:58:00Z main process exited with 1\n")
+	if err != nil {
+		t.Fatalf("ParseWatchdogStatus() error = %v", err)
+	}
+	if !status.Heartbeat.Equal(time.Unix(1714557600, 0)) {
+		t.Errorf("Heartbeat = %v", status.Heartbeat)
+	}
+	if status.Restarts != 2 {
+		t.Errorf("Restarts = %d, want 2", status.Restarts)
+	}
+	if status.LastRestart != "2024-05-01T09:58:00Z main process exited with 1" {
+		t.Errorf("LastRestart = %q", status.LastRestart)
+	}
+	if !status.Healthy(status.Heartbeat.Add(WatchdogInterval)) {
+		t.Error("recent heartbeat should be healthy")
+	}
+	if status.Healthy(status.Heartbeat.Add(WatchdogStaleAfter)) {
+		t.Error("stale heartbeat should not be healthy")
+	}
+
+	empty, err := ParseWatchdogStatus("heartbeat=\nrestarts=0\nlast=\n")
+	if err != nil {
+		t.Fatalf("ParseWatchdogStatus() error = %v", err)
+	}
+	if empty.Healthy(time.Now()) || empty.Restarts != 0 {
+		t.Errorf("status without heartbeat = %+v", empty)
+	}
+
+	if _, err := ParseWatchdogStatus("heartbeat=soon"); err == nil {
+		t.Error("expected an error for an invalid heartbeat")
+	}
+}
@@ -0,0 +1,115 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Result label values
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// Namespace prefixes all kodama metric names
+const Namespace = "kodama"
+
+// Registry holds all kodama metrics
+// A dedicated registry keeps CLI commands free of global registration side effects.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// SessionStartDuration observes how long starting a session takes
+	SessionStartDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "session_start_duration_seconds",
+		Help:      "Time taken to start a session, from request to ready pod.",
+		Buckets:   []float64{5, 10, 20, 30, 60, 90, 120, 180, 300, 600},
+	}, []string{"result"})
+
+	// SyncBytes counts bytes sent to pods by file sync
+	SyncBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "sync_bytes_total",
+		Help:      "Bytes sent from the local machine to session pods by file sync.",
+	})
+
+	// SyncFiles counts files copied to pods by continuous sync
+	SyncFiles = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "sync_files_total",
+		Help:      "Files copied to session pods by continuous sync.",
+	}, []string{"result"})
+
+	// AgentDuration observes coding agent task durations
+	AgentDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "agent_execution_duration_seconds",
+		Help:      "Duration of coding agent task executions.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"result"})
+
+	// AgentFailures counts failed coding agent task executions
+	AgentFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "agent_execution_failures_total",
+		Help:      "Coding agent task executions that failed.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		SessionStartDuration,
+		SyncBytes,
+		SyncFiles,
+		AgentDuration,
+		AgentFailures,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler returns an HTTP handler serving the registry in Prometheus text format
+// Extra gatherers are merged in, e.g. collectors owned by a server instance.
+func Handler(extra ...prometheus.Gatherer) http.Handler {
+	gatherers := append(prometheus.Gatherers{Registry}, extra...)
+	return promhttp.HandlerFor(gatherers, promhttp.HandlerOpts{})
+}
+
+// Result returns the result label value for err
+func Result(err error) string {
+	if err != nil {
+		return ResultFailure
+	}
+	return ResultSuccess
+}
+
+// ObserveSessionStart records a session start that began at start
+func ObserveSessionStart(start time.Time, err error) {
+	SessionStartDuration.WithLabelValues(Result(err)).Observe(time.Since(start).Seconds())
+}
+
+// ObserveAgentExecution records a coding agent execution that began at start
+func ObserveAgentExecution(start time.Time, err error) {
+	AgentDuration.WithLabelValues(Result(err)).Observe(time.Since(start).Seconds())
+	if err != nil {
+		AgentFailures.Inc()
+	}
+}
+
+// CountingReader counts bytes read through it into SyncBytes
+type CountingReader struct {
+	R io.Reader
+}
+
+// Read implements io.Reader
+func (c *CountingReader) Read(p []byte) (int, error) {
+	n, err := c.R.Read(p)
+	SyncBytes.Add(float64(n))
+	return n, err
+}
@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserveAgentExecution(t *testing.T) {
+	before := testutil.ToFloat64(AgentFailures)
+
+	ObserveAgentExecution(time.Now(), nil)
+	ObserveAgentExecution(time.Now(), errors.New("boom"))
+
+	assert.Equal(t, before+1, testutil.ToFloat64(AgentFailures))
+}
+
+func TestCountingReader(t *testing.T) {
+	before := testutil.ToFloat64(SyncBytes)
+
+	n, err := io.Copy(io.Discard, &CountingReader{R: bytes.NewReader(make([]byte, 1500))})
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(1500), n)
+	assert.Equal(t, before+1500, testutil.ToFloat64(SyncBytes))
+}
+
+func TestHandler(t *testing.T) {
+	ObserveSessionStart(time.Now().Add(-12*time.Second), nil)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `kodama_session_start_duration_seconds_bucket{result="success",le="20"}`)
+	assert.Contains(t, rec.Body.String(), "kodama_sync_bytes_total")
+}
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewApplyChangesCommand creates a new apply-changes command
+func NewApplyChangesCommand() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "apply-changes <name> [path...]",
+		Short: "Review and apply the agent's changes in a sandbox session",
+		Long: `Review and apply the changes the agent made in a session started with --sandbox.
+
+In sandbox sessions the synced or cloned workspace is mounted read-only at
+/kodama/canonical, and the agent works in a writable copy at /workspace.
+Without paths, the files that differ between the two are listed: A (added),
+M (modified) or D (deleted). With paths, the changes to those files, or to
+the files inside those directories, are copied into the workspace. For synced
+sessions they are copied into the local directory as well.
+
+Examples:
+  kubectl kodama apply-changes my-work
+  kubectl kodama apply-changes my-work src/parser.go docs/
+  kubectl kodama apply-changes my-work --all`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, paths := args[0], args[1:]
+
+			if len(paths) == 0 && !all {
+				changes, err := usecase.SandboxChanges(cmd.Context(), name)
+				if err != nil {
+					return err
+				}
+				if len(changes) == 0 {
+					fmt.Printf("No changes in the sandbox of '%s'\n", name)
+					return nil
+				}
+				for _, change := range changes {
+					fmt.Printf("%s %s\n", change.Kind, change.Path)
+				}
+				fmt.Printf("\nApply them with:\n  kubectl kodama apply-changes %s <path>... | --all\n", name)
+				return nil
+			}
+
+			applied, err := usecase.ApplySandboxChanges(cmd.Context(), usecase.ApplySandboxChangesOptions{
+				Name:  name,
+				Paths: paths,
+				All:   all,
+			})
+			if err != nil {
+				return err
+			}
+			if len(applied) == 0 {
+				fmt.Printf("No changes in the sandbox of '%s'\n", name)
+				return nil
+			}
+			for _, change := range applied {
+				fmt.Printf("%s %s\n", change.Kind, change.Path)
+			}
+			fmt.Printf("✓ Applied %d change(s) to the workspace\n", len(applied))
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Apply every change in the sandbox")
+
+	return cmd
+}
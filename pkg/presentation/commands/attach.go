@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/presentation/dashboard"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewAttachCommand creates a new attach command
+func NewAttachCommand() *cobra.Command {
+	var (
+		command   string
+		ttyMode   bool
+		localPort int
+		noBrowser bool
+		dashMode  bool
+		container string
+		steal     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "attach [name]",
+		Short: "Attach to a session",
+		Long: `Attach to a running session and start Claude Code.
+
+By default, uses ttyd (web-based terminal) if enabled in the session.
+Opens port-forward and launches browser automatically.
+Without a name, attaches to the session syncing the working directory, or else
+the current session set with 'kubectl kodama use'.
+
+Examples:
+  kubectl kodama attach my-work                 # Use ttyd (open browser)
+  kubectl kodama attach my-work --no-browser    # Use ttyd (no browser)
+  kubectl kodama attach my-work --tty           # Force TTY mode
+  kubectl kodama attach my-work --port 8080     # Custom local port
+  kubectl kodama attach my-work --command "claude --help"
+  kubectl kodama attach my-work --dashboard     # tmux: shell + agent output + sync log
+  kubectl kodama attach my-work -c diff-viewer  # Shell in a sidecar container (TTY mode)
+  kubectl kodama attach                         # Attach to this directory's or the current session`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			name, err := sessionNameArg(args)
+			if err != nil {
+				return err
+			}
+
+			if dashMode {
+				return runDashboard(cmd.Context(), name, command, kubeconfigPath)
+			}
+
+			opts := usecase.AttachSessionOptions{
+				Name:           name,
+				Command:        command,
+				KubeconfigPath: kubeconfigPath,
+				TtyMode:        ttyMode,
+				LocalPort:      localPort,
+				NoBrowser:      noBrowser,
+				Container:      container,
+				Steal:          steal,
+			}
+
+			return usecase.AttachSession(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&command, "command", "", "Command to run in pod (default: interactive shell)")
+	cmd.Flags().BoolVar(&ttyMode, "tty", false, "Force TTY mode (disable ttyd)")
+	cmd.Flags().IntVar(&localPort, "port", 0, "Local port for port-forward (default: same as pod port)")
+	cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Don't open browser automatically")
+	cmd.Flags().BoolVar(&dashMode, "dashboard", false, "Open a local tmux layout with shell, agent output, and sync log")
+	cmd.Flags().StringVarP(&container, "container", "c", "", "Container to attach to (default: main container)")
+	cmd.Flags().BoolVar(&steal, "steal", false, "Take over the session lock if another user is attached")
+
+	return cmd
+}
+
+// runDashboard opens the tmux dashboard for a session
+func runDashboard(ctx context.Context, name, command, kubeconfigPath string) error {
+	store, err := usecase.OpenStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize config store: %w", err)
+	}
+
+	session, err := store.LoadSession(name)
+	if err != nil {
+		if errors.Is(err, config.ErrSessionNotFound) {
+			return fmt.Errorf("%w: %s", config.ErrSessionNotFound, name)
+		}
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	binary, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate kodama binary: %w", err)
+	}
+
+	layout := dashboard.BuildLayout(session, dashboard.Options{
+		KubeconfigPath: kubeconfigPath,
+		Command:        command,
+		KodamaBinary:   binary,
+	})
+
+	return dashboard.Run(ctx, layout)
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/agent/auth"
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+)
+
+// NewAuthCommand creates the auth command for coding agent credentials
+func NewAuthCommand(sessionService *service.SessionService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auth",
+		Short: "Inspect coding agent authentication",
+	}
+
+	cmd.AddCommand(newAuthStatusCommand(sessionService))
+	cmd.AddCommand(newAuthLoginCommand(sessionService))
+
+	return cmd
+}
+
+func newAuthStatusCommand(sessionService *service.SessionService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show which auth provider the coding agent uses",
+		Long: `Check every auth provider the coding agent would try, in order.
+
+The providers come from claudeAuth in ~/.kodama/config.yaml: its chain, its
+single authType, or else CLAUDE_CODE_AUTH_TOKEN and then the auth file. The
+first provider with credentials is active. Providers whose credentials are
+about to expire are refreshed, and failed refreshes are reported.
+
+Examples:
+  kubectl kodama auth status`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			statuses, err := sessionService.AuthStatus(cmd.Context())
+			if err != nil {
+				return err
+			}
+			if err := writeAuthStatusTable(os.Stdout, statuses, time.Now()); err != nil {
+				return err
+			}
+			for _, status := range statuses {
+				if status.Active {
+					return nil
+				}
+			}
+			return fmt.Errorf("no auth provider has credentials")
+		},
+	}
+}
+
+func newAuthLoginCommand(sessionService *service.SessionService) *cobra.Command {
+	var opts service.LoginOptions
+
+	cmd := &cobra.Command{
+		Use:   "login",
+		Short: "Log in to an identity provider with the OAuth device flow",
+		Long: `Log in with the OAuth 2.0 device authorization flow and store the tokens.
+
+kodama prints a URL and a code to enter there, then waits until the login is
+approved in the browser. The access token and refresh token are stored in a
+profile of the auth cache (~/.kodama/claude-auth.json, encrypted when the store
+is encrypted), where the file auth provider refreshes them before they expire.
+
+The identity provider comes from claudeAuth.login in ~/.kodama/config.yaml;
+flags override it field by field. Request the offline_access scope if your
+identity provider only issues refresh tokens with it.
+
+Examples:
+  kubectl kodama auth login
+  kubectl kodama auth login --profile work
+  kubectl kodama auth login --device-auth-url https://idp.example.com/oauth/device/code \
+    --token-url https://idp.example.com/oauth/token --client-id kodama --scope openid --scope offline_access`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			result, err := sessionService.Login(cmd.Context(), opts, func(device *auth.DeviceAuthorization) {
+				writeDevicePrompt(os.Stdout, device)
+			})
+			if err != nil {
+				return fmt.Errorf("login failed: %w", err)
+			}
+
+			fmt.Printf("✓ Logged in, token stored in profile '%s'\n", result.Profile)
+			if result.ExpiresAt != nil {
+				fmt.Printf("  Expires: %s\n", result.ExpiresAt.Local().Format(time.RFC3339))
+			}
+			if !result.HasRefreshToken {
+				fmt.Println("  ⚠️  No refresh token was issued; log in again when the token expires")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Profile, "profile", "", "Auth cache profile to store the token in (default: claudeAuth.profile or 'default')")
+	cmd.Flags().StringVar(&opts.Flow.DeviceAuthURL, "device-auth-url", "", "Device authorization endpoint of the identity provider")
+	cmd.Flags().StringVar(&opts.Flow.TokenURL, "token-url", "", "Token endpoint of the identity provider")
+	cmd.Flags().StringVar(&opts.Flow.ClientID, "client-id", "", "OAuth client ID registered for the device flow")
+	cmd.Flags().StringArrayVar(&opts.Flow.Scopes, "scope", nil, "Scope to request (repeatable)")
+
+	return cmd
+}
+
+// writeDevicePrompt tells the user where to enter the login code
+func writeDevicePrompt(w io.Writer, device *auth.DeviceAuthorization) {
+	_, _ = fmt.Fprintf(w, "To log in, open %s\n", device.VerificationURI)
+	_, _ = fmt.Fprintf(w, "and enter the code: %s\n", device.UserCode)
+	if device.VerificationURIComplete != "" {
+		_, _ = fmt.Fprintf(w, "(or open %s)\n", device.VerificationURIComplete)
+	}
+	_, _ = fmt.Fprintln(w, "⏳ Waiting for the login to be approved...")
+}
+
+// writeAuthStatusTable prints one row per provider in chain order
+func writeAuthStatusTable(w io.Writer, statuses []auth.ProviderStatus, now time.Time) error {
+	t := table.New(
+		table.Column{Header: "#"},
+		table.Column{Header: "PROVIDER"},
+		table.Column{Header: "STATUS", Color: authStatusColor},
+		table.Column{Header: "EXPIRES"},
+		table.Column{Header: "DETAIL", MaxWidth: 100},
+	)
+	for i, status := range statuses {
+		state, detail := "available", "-"
+		switch {
+		case status.Active:
+			state = "active"
+		case !status.Available:
+			state, detail = "unavailable", status.Error
+		}
+		if status.RefreshError != "" {
+			detail = "refresh failed: " + status.RefreshError
+		}
+		expires := "never"
+		if !status.Available {
+			expires = "-"
+		} else if status.ExpiresAt != nil {
+			expires = table.RelativeTime(*status.ExpiresAt, now)
+		}
+		t.AddRow(fmt.Sprint(i+1), string(status.Type), state, expires, detail)
+	}
+	return t.Render(w, table.Options{Color: table.ColorEnabled(w)})
+}
+
+// authStatusColor highlights the active provider and unavailable ones
+func authStatusColor(state string) table.Color {
+	switch state {
+	case "active":
+		return table.ColorGreen
+	case "unavailable":
+		return table.ColorRed
+	default:
+		return table.ColorNone
+	}
+}
@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/agent/auth"
+)
+
+func TestWriteAuthStatusTable(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	expires := now.Add(45 * time.Minute)
+	var buf bytes.Buffer
+	err := writeAuthStatusTable(&buf, []auth.ProviderStatus{
+		{Type: auth.AuthTypeGoogleADC, Error: "metadata server unreachable"},
+		{Type: auth.AuthTypeFile, Active: true, Available: true, ExpiresAt: &expires, RefreshError: "no refresh URL"},
+		{Type: auth.AuthTypeToken, Available: true},
+	}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("got %d lines, want header and 3 rows:\n%s", len(lines), buf.String())
+	}
+	for i, want := range [][]string{
+		{"1", "google-adc", "unavailable", "metadata server unreachable"},
+		{"2", "file", "active", "in 45m", "refresh failed: no refresh URL"},
+		{"3", "token", "available", "never"},
+	} {
+		for _, field := range want {
+			if !strings.Contains(lines[i+1], field) {
+				t.Errorf("row %d = %q, want %q", i+1, lines[i+1], field)
+			}
+		}
+	}
+}
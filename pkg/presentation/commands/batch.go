@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewBatchCommand creates a new batch command
+func NewBatchCommand() *cobra.Command {
+	var (
+		parallelism int
+		expires     time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "batch <file>",
+		Short: "Start many sessions concurrently from a batch file",
+		Long: `Start a session for each task in a YAML batch file, running up to
+--parallel starts at a time, then print a summary of the agent results.
+
+Batch file format:
+  defaults:                  # optional, applied to every task
+    namespace: dev
+    prompt: "Migrate from ioutil to os/io"
+  tasks:
+    - name: svc-auth
+      repo: https://github.com/org/svc-auth
+    - name: svc-billing
+      repo: https://github.com/org/svc-billing
+      branch: main
+      promptFile: ./prompts/billing.md
+
+Task fields: name, repo, branch, prompt, promptFile, namespace, image, cpu,
+memory, config (session template path).
+
+Examples:
+  kubectl kodama batch refactor.yaml
+  kubectl kodama batch refactor.yaml --parallel 8 --expires 24h`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := usecase.LoadBatchFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			// --namespace applies to tasks without their own namespace
+			namespace, _ := cmd.Flags().GetString("namespace")
+			for i := range file.Tasks {
+				file.Tasks[i].Namespace = config.CoalesceString(file.Tasks[i].Namespace, namespace)
+			}
+
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			fmt.Printf("🚀 Starting %d session(s) with parallelism %d\n\n", len(file.Tasks), effectiveParallelism(parallelism))
+
+			results := usecase.RunBatch(cmd.Context(), file, usecase.BatchOptions{
+				Parallelism:    parallelism,
+				KubeconfigPath: kubeconfigPath,
+				Expires:        expires,
+				Progress: func(done, total int, result usecase.BatchResult) {
+					if result.Err != nil {
+						fmt.Printf("\n[%d/%d] ❌ %s failed: %v\n", done, total, result.Task.Name, result.Err)
+						return
+					}
+					fmt.Printf("\n[%d/%d] ✓ %s started (%s)\n", done, total, result.Task.Name, result.Duration.Round(time.Second))
+				},
+			})
+
+			fmt.Println()
+			printBatchSummary(results)
+
+			failed := 0
+			for _, r := range results {
+				if r.Err != nil {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return fmt.Errorf("%d of %d task(s) failed", failed, len(results))
+			}
+
+			fmt.Printf("\n✨ All %d session(s) started\n", len(results))
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&parallelism, "parallel", usecase.DefaultBatchParallelism, "Maximum number of sessions started concurrently")
+	cmd.Flags().DurationVar(&expires, "expires", 0, "Session lifetime applied to every task (e.g., 24h)")
+
+	return cmd
+}
+
+func effectiveParallelism(parallelism int) int {
+	if parallelism <= 0 {
+		return usecase.DefaultBatchParallelism
+	}
+	return parallelism
+}
+
+// printBatchSummary prints a table of batch results in task order
+func printBatchSummary(results []usecase.BatchResult) {
+	t := table.New(
+		table.Column{Header: "NAME"},
+		table.Column{Header: "SESSION", Color: table.StatusColor},
+		table.Column{Header: "AGENT", Color: table.StatusColor},
+		table.Column{Header: "TASK ID"},
+		table.Column{Header: "DURATION"},
+		table.Column{Header: "ERROR", MaxWidth: 80},
+	)
+
+	for _, r := range results {
+		sessionStatus := "Failed"
+		agentStatus := "-"
+		taskID := "-"
+		errMsg := "-"
+
+		if r.Err != nil {
+			errMsg = r.Err.Error()
+		}
+		if r.Session != nil {
+			sessionStatus = string(r.Session.Status)
+			if exec := r.Session.GetLastAgentExecution(); exec != nil {
+				agentStatus = exec.Status
+				if exec.TaskID != "" {
+					taskID = exec.TaskID
+				}
+				if exec.Error != "" && r.Err == nil {
+					errMsg = exec.Error
+				}
+			}
+		}
+
+		t.AddRow(
+			r.Task.Name,
+			sessionStatus,
+			agentStatus,
+			taskID,
+			r.Duration.Round(time.Second).String(),
+			errMsg,
+		)
+	}
+
+	_ = t.Render(os.Stdout, table.Options{Color: table.ColorEnabled(os.Stdout)})
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// NewCacheCommand creates the cache command
+func NewCacheCommand(sessionService *service.SessionService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the shared dependency cache",
+		Long: `Manage the shared dependency cache PVC.
+
+When 'defaults.cache.pvc' is set in ~/.kodama/config.yaml (or 'cache.pvc' in a
+template), every session mounts the PVC at /cache and points the Go, npm, pnpm,
+yarn, pip and uv caches at it, so dependencies are downloaded once per namespace.`,
+	}
+
+	cmd.AddCommand(newCacheWarmCommand(sessionService))
+
+	return cmd
+}
+
+func newCacheWarmCommand(sessionService *service.SessionService) *cobra.Command {
+	var (
+		opts    kubernetes.CacheWarmOptions
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "warm",
+		Short: "Populate the shared dependency cache",
+		Long: `Run a Job that downloads dependencies into the shared cache PVC.
+
+For each --repo the Job clones the repository and runs 'go mod download',
+'npm ci' / 'pnpm fetch' / 'yarn install', and 'uv sync' / 'pip download'
+depending on which lock files exist and which tools the image provides.
+--command adds arbitrary shell commands. The PVC is created (ReadWriteMany)
+if it does not exist.
+
+Examples:
+  kubectl kodama cache warm --repo https://github.com/user/app.git
+  kubectl kodama cache warm --repo https://github.com/user/api.git --repo https://github.com/user/web.git
+  kubectl kodama cache warm --command "go install golang.org/x/tools/gopls@latest"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(opts.Repos) == 0 && len(opts.Commands) == 0 {
+				return fmt.Errorf("at least one --repo or --command is required")
+			}
+
+			namespaceFlag, _ := cmd.Flags().GetString("namespace")
+			namespace, err := sessionService.ResolveNamespace(namespaceFlag)
+			if err != nil {
+				return err
+			}
+			opts.Namespace = namespace
+
+			if err := sessionService.ResolveCacheOptions(&opts); err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			created, err := sessionService.EnsureCachePVC(ctx, opts)
+			if err != nil {
+				return err
+			}
+			if created {
+				fmt.Printf("✓ Created cache PVC '%s' in namespace '%s'\n", opts.PVC, namespace)
+			}
+
+			fmt.Printf("🔥 Warming cache '%s'...\n", opts.PVC)
+			jobName, err := sessionService.WarmCache(ctx, opts, timeout)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Cache warmed by job '%s'\n", jobName)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&opts.Repos, "repo", nil, "Repository whose dependencies are downloaded (repeatable)")
+	cmd.Flags().StringArrayVar(&opts.Commands, "command", nil, "Extra shell command run in the warm job (repeatable)")
+	cmd.Flags().StringVar(&opts.PVC, "pvc", "", "Cache PVC name (default: config 'defaults.cache.pvc' or "+kubernetes.DefaultCachePVC+")")
+	cmd.Flags().StringVar(&opts.Image, "image", "", "Image providing git and the package managers (default: session image from config)")
+	cmd.Flags().StringVar(&opts.Size, "size", kubernetes.DefaultCacheSize, "PVC size if it is created")
+	cmd.Flags().StringVar(&opts.StorageClass, "storage-class", "", "Storage class (must support ReadWriteMany) if the PVC is created")
+	cmd.Flags().DurationVar(&timeout, "timeout", 30*time.Minute, "Maximum time to wait for the job")
+	_ = cmd.RegisterFlagCompletionFunc("image", completeImages)
+
+	return cmd
+}
@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// completeNamespaces suggests namespaces in the cluster for --namespace
+func completeNamespaces(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+	return usecase.CompleteNamespaces(ctx, kubeconfigPath, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeImages suggests the images listed in the global config for --image
+func completeImages(cmd *cobra.Command, _ []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+	return usecase.CompleteImages(kubeconfigPath, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRuntimes suggests the values of --runtime
+var completeRuntimes = cobra.FixedCompletions(
+	[]string{config.RuntimeKubernetes, config.RuntimeDocker, config.RuntimePodman},
+	cobra.ShellCompDirectiveNoFileComp,
+)
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+)
+
+// NewConfigCommand creates the config command for managing kodama's config files
+func NewConfigCommand(sessionService *service.SessionService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage kodama config files (~/.kodama)",
+	}
+
+	cmd.AddCommand(newConfigMigrateCommand(sessionService))
+
+	return cmd
+}
+
+func newConfigMigrateCommand(sessionService *service.SessionService) *cobra.Command {
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Rewrite config and session files in the current schema version",
+		Long: `Upgrade ~/.kodama/config.yaml and the session files in ~/.kodama/sessions
+to the current schema version and write them back.
+
+Older files are upgraded in memory whenever they are loaded, so migrating is
+optional. Rewriting them keeps the files readable after later releases remove
+support for old field names. Encrypted session files stay encrypted.
+
+Examples:
+  kubectl kodama config migrate --dry-run
+  kubectl kodama config migrate`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			migrations, err := sessionService.MigrateConfig(dryRun)
+			for _, m := range migrations {
+				fmt.Printf("📝 %s (schema version %d → %d)\n", m.Path, m.From, m.To)
+			}
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case len(migrations) == 0:
+				fmt.Println("✓ All files are up to date")
+			case dryRun:
+				fmt.Printf("\n%d file(s) would be migrated\n", len(migrations))
+			default:
+				fmt.Printf("\n✓ Migrated %d file(s)\n", len(migrations))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List the files that would be migrated without writing them")
+
+	return cmd
+}
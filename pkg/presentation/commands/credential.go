@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// NewCredentialCommand creates the exec credential plugin command used by
+// kubeconfigs generated for GitHub Actions OIDC
+func NewCredentialCommand() *cobra.Command {
+	var audience string
+
+	cmd := &cobra.Command{
+		Use:   "credential",
+		Short: "Print a GitHub Actions OIDC token as an ExecCredential",
+		Long: `Print a GitHub Actions OIDC token as a client.authentication.k8s.io/v1
+ExecCredential. kubectl and kubectl-kodama run this as an exec credential
+plugin when KODAMA_K8S_OIDC_AUDIENCE is set, so short-lived tokens are
+refreshed automatically.`,
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			token, expiry, err := kubernetes.GitHubOIDCToken(cmd.Context(), audience)
+			if err != nil {
+				return err
+			}
+			data, err := kubernetes.ExecCredential(token, expiry)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(os.Stdout, string(data))
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&audience, "audience", "", "OIDC token audience")
+	_ = cmd.MarkFlagRequired("audience")
+
+	return cmd
+}
+
+// NewKubeconfigCommand creates the kubeconfig command
+func NewKubeconfigCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "kubeconfig",
+		Short: "Generate a kubeconfig from KODAMA_K8S_* variables",
+		Long: `Generate a kubeconfig for token-based cluster access, so other tools in a CI
+job (kubectl, helm) authenticate the same way as kubectl-kodama.
+
+Variables:
+  KODAMA_K8S_SERVER         API server URL (required)
+  KODAMA_K8S_CA_DATA        Cluster CA certificate, PEM or base64-encoded PEM
+  KODAMA_K8S_NAMESPACE      Default namespace
+and exactly one of:
+  KODAMA_K8S_TOKEN          Static bearer token
+  KODAMA_K8S_TOKEN_FILE     File containing a bearer token
+  KODAMA_K8S_OIDC_AUDIENCE  Fetch GitHub Actions OIDC tokens for this audience
+
+Examples:
+  kubectl kodama kubeconfig -o "$RUNNER_TEMP/kubeconfig"
+  echo "KUBECONFIG=$RUNNER_TEMP/kubeconfig" >> "$GITHUB_ENV"`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			auth, err := kubernetes.TokenAuthFromEnv()
+			if err != nil {
+				return err
+			}
+			if auth == nil {
+				return fmt.Errorf("%s is not set", kubernetes.EnvServer)
+			}
+
+			data, err := auth.Kubeconfig()
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				_, err = os.Stdout.Write(data)
+				return err
+			}
+			if err := os.WriteFile(output, data, 0o600); err != nil {
+				return fmt.Errorf("failed to write kubeconfig: %w", err)
+			}
+			fmt.Printf("✓ Kubeconfig written to %s\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the kubeconfig to a file instead of stdout")
+
+	return cmd
+}
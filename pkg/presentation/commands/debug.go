@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"strings"
@@ -57,7 +56,8 @@ This is useful for:
 - Debugging issues before deployment
 - CI/CD validation
 
-By default, secret values are redacted. Use --show-secrets to reveal them.
+By default, secret values are redacted. Use --show-secrets to reveal them,
+unless the session seals its secrets (sealedSecrets).
 
 Examples:
   # Generate manifests from flags
@@ -83,7 +83,7 @@ Examples:
 
 			if fromConfig {
 				// Load from existing session config
-				store, err := config.NewStore()
+				store, err := usecase.OpenStore()
 				if err != nil {
 					return fmt.Errorf("failed to initialize config store: %w", err)
 				}
@@ -153,7 +153,7 @@ Examples:
 			opts.DryRun = true
 
 			// Call StartSession with dry-run enabled
-			session, err := usecase.StartSession(context.Background(), opts)
+			session, err := usecase.StartSession(cmd.Context(), opts)
 			if err != nil {
 				return fmt.Errorf("failed to generate manifests: %w", err)
 			}
@@ -168,6 +168,11 @@ Examples:
 				return fmt.Errorf("invalid manifests type")
 			}
 
+			// Sealed secret values never leave the cluster in plaintext
+			if showSecrets && session.SealedSecrets {
+				return fmt.Errorf("--show-secrets is not available for sessions with sealed secrets")
+			}
+
 			// Apply secret redaction if not showing secrets
 			if !showSecrets {
 				manifests = usecase.RedactSecrets(manifests)
@@ -213,6 +218,8 @@ Examples:
 	cmd.Flags().StringSliceVar(&envFiles, "env-file", []string{}, "Dotenv file(s) to load")
 	cmd.Flags().StringSliceVar(&envExclude, "env-exclude", []string{}, "Environment variables to exclude")
 	cmd.Flags().StringSliceVar(&secretFiles, "secret-file", []string{}, "Inject file as secret (format: source:destination)")
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	_ = cmd.RegisterFlagCompletionFunc("image", completeImages)
 
 	return cmd
 }
@@ -229,28 +236,37 @@ func sessionConfigToOptions(session *config.SessionConfig, kubeconfigPath string
 	}
 
 	return usecase.StartSessionOptions{
-		Name:            session.Name,
-		Repo:            session.Repo,
-		SyncPath:        session.Sync.LocalPath,
-		Namespace:       session.Namespace,
-		CPU:             session.Resources.CPU,
-		Memory:          session.Resources.Memory,
-		CustomResources: session.Resources.CustomResources,
-		Branch:          session.Branch,
-		KubeconfigPath:  kubeconfigPath,
-		Image:           session.Image,
-		Command:         strings.Join(session.Command, " "),
-		CloneDepth:      session.GitClone.Depth,
-		SingleBranch:    session.GitClone.SingleBranch,
-		GitCloneArgs:    session.GitClone.ExtraArgs,
-		TtydEnabled:     session.Ttyd.Enabled != nil,
-		TtydEnabledVal:  session.Ttyd.Enabled != nil && *session.Ttyd.Enabled,
-		TtydPort:        session.Ttyd.Port,
-		TtydOptions:     session.Ttyd.Options,
-		TtydReadonly:    session.Ttyd.Writable != nil && !*session.Ttyd.Writable,
-		TtydReadonlySet: session.Ttyd.Writable != nil,
-		EnvFiles:        session.Env.DotenvFiles,
-		EnvExclude:      session.Env.ExcludeVars,
-		SecretFiles:     secretFileMappings,
+		Name:             session.Name,
+		Repo:             session.Repo,
+		SyncPath:         session.Sync.LocalPath,
+		Namespace:        session.Namespace,
+		CPU:              session.Resources.CPU,
+		Memory:           session.Resources.Memory,
+		CustomResources:  session.Resources.CustomResources,
+		Branch:           session.Branch,
+		KubeconfigPath:   kubeconfigPath,
+		Image:            session.Image,
+		Command:          strings.Join(session.Command, " "),
+		CloneDepth:       session.GitClone.Depth,
+		SingleBranch:     session.GitClone.SingleBranch,
+		GitCloneArgs:     session.GitClone.ExtraArgs,
+		CloneAttempts:    session.GitClone.Attempts,
+		GitMirror:        session.GitClone.Mirror,
+		TtydEnabled:      session.Ttyd.Enabled != nil,
+		TtydEnabledVal:   session.Ttyd.Enabled != nil && *session.Ttyd.Enabled,
+		TtydPort:         session.Ttyd.Port,
+		TtydOptions:      session.Ttyd.Options,
+		TtydReadonly:     session.Ttyd.Writable != nil && !*session.Ttyd.Writable,
+		TtydReadonlySet:  session.Ttyd.Writable != nil,
+		EnvFiles:         session.Env.DotenvFiles,
+		EnvExclude:       session.Env.ExcludeVars,
+		SecretFiles:      secretFileMappings,
+		SealedSecrets:    session.SealedSecrets,
+		RestartPolicy:    session.RestartPolicy,
+		Watchdog:         session.Watchdog,
+		Labels:           session.Labels,
+		PodOverrides:     session.PodOverrides,
+		NoEvict:          session.Disruption.NoEvict,
+		DisruptionBudget: session.Disruption.PodDisruptionBudget,
 	}
 }
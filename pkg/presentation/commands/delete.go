@@ -2,23 +2,27 @@ package commands
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/illumination-k/kodama/pkg/application/service"
 	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+	"github.com/illumination-k/kodama/pkg/usecase"
 )
 
 // NewDeleteCommand creates a new delete command
 func NewDeleteCommand(sessionService *service.SessionService) *cobra.Command {
 	var keepConfig bool
 	var force bool
+	var dryRun bool
 
 	cmd := &cobra.Command{
 		Use:   "delete <name>",
@@ -30,30 +34,46 @@ Steps:
   2. Delete Kubernetes pod
   3. Remove session config (unless --keep-config)
 
+With --dry-run, nothing is deleted: the pod, secrets, config maps, sync,
+port-forwards and local files that would be removed are listed, along with
+the PVCs that are kept.
+
 Examples:
   kubectl kodama delete my-work
+  kubectl kodama delete my-work --dry-run
   kubectl kodama delete my-work --keep-config
   kubectl kodama delete my-work --force`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDelete(sessionService, args[0], keepConfig, force)
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+			if dryRun {
+				plan, err := usecase.PlanDeleteSession(cmd.Context(), usecase.DeleteSessionOptions{
+					Name:           args[0],
+					KubeconfigPath: kubeconfigPath,
+					KeepConfig:     keepConfig,
+				})
+				if err != nil {
+					return err
+				}
+				return printDeletePlan(os.Stdout, args[0], plan)
+			}
+			return runDelete(cmd.Context(), sessionService, args[0], keepConfig, force, kubeconfigPath)
 		},
 	}
 
 	cmd.Flags().BoolVar(&keepConfig, "keep-config", false, "Keep session config file")
 	cmd.Flags().BoolVarP(&force, "force", "f", false, "Skip confirmation prompt")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "List what would be deleted without deleting anything")
 
 	return cmd
 }
 
-func runDelete(sessionService *service.SessionService, name string, keepConfig, force bool) error {
-	ctx := context.Background()
-
+func runDelete(ctx context.Context, sessionService *service.SessionService, name string, keepConfig, force bool, kubeconfigPath string) error {
 	// 1. Load session
 	session, err := sessionService.LoadSession(name)
 	if err != nil {
 		if errors.Is(err, config.ErrSessionNotFound) {
-			return fmt.Errorf("session '%s' not found\n\nAvailable sessions:\n  kubectl kodama list", name)
+			return fmt.Errorf("%w: %s", config.ErrSessionNotFound, name)
 		}
 		return fmt.Errorf("failed to load session: %w", err)
 	}
@@ -79,69 +99,37 @@ func runDelete(sessionService *service.SessionService, name string, keepConfig,
 		}
 	}
 
-	// 3. Stop file sync
-	if session.Sync.Enabled && session.Sync.MutagenSession != "" {
-		fmt.Println("⏳ Stopping file sync...")
-		if syncErr := sessionService.StopSync(ctx, session.Sync.MutagenSession); syncErr != nil {
-			fmt.Printf("⚠️  Warning: Failed to stop sync: %v\n", syncErr)
-		} else {
-			fmt.Println("✓ Sync stopped")
-		}
-	}
-
-	// 4. Delete Kubernetes resources
-	// 4a. Delete environment secret if exists
-	if session.Env.SecretCreated && session.Env.SecretName != "" {
-		fmt.Println("🗑️  Deleting environment secret...")
-		if err := sessionService.DeleteSecret(ctx, session.Env.SecretName, session.Namespace); err != nil {
-			fmt.Printf("⚠️  Warning: Failed to delete secret: %v\n", err)
-		} else {
-			fmt.Println("✓ Secret deleted")
-		}
-	}
+	// 3. Stop sync, delete Kubernetes resources and the session config
+	return usecase.DeleteSession(ctx, usecase.DeleteSessionOptions{
+		Name:           name,
+		KubeconfigPath: kubeconfigPath,
+		KeepConfig:     keepConfig,
+	})
+}
 
-	// 4b. Delete secret file if exists
-	if session.SecretFile.SecretCreated && session.SecretFile.SecretName != "" {
-		fmt.Println("🗑️  Deleting secret file...")
-		if err := sessionService.DeleteSecret(ctx, session.SecretFile.SecretName, session.Namespace); err != nil {
-			fmt.Printf("⚠️  Warning: Failed to delete secret file: %v\n", err)
-		} else {
-			fmt.Println("✓ Secret file deleted")
-		}
+// printDeletePlan prints what a delete would remove and keep
+func printDeletePlan(w io.Writer, name string, plan *usecase.DeletePlan) error {
+	fmt.Fprintf(w, "Dry run: nothing was deleted. Deleting session '%s' would remove:\n\n", name)
+	if err := writeDeletePlanItems(w, plan.Remove); err != nil {
+		return err
 	}
-
-	// 4c. Delete pod
-	fmt.Println("⏳ Deleting pod...")
-	if err := sessionService.DeletePod(ctx, session.PodName, session.Namespace); err != nil {
-		fmt.Printf("⚠️  Warning: Failed to delete pod: %v\n", err)
-	} else {
-		fmt.Println("✓ Pod deletion initiated")
-
-		// Wait for pod to be fully deleted
-		fmt.Println("⏳ Waiting for pod termination...")
-		waitTimeout := 2 * time.Minute
-		if err := sessionService.GetKubernetesClient().WaitForPodDeleted(ctx, session.PodName, session.Namespace, waitTimeout); err != nil {
-			fmt.Printf("⚠️  Warning: Failed to confirm pod deletion: %v\n", err)
-		} else {
-			fmt.Println("✓ Pod fully terminated and removed")
-		}
+	if len(plan.Keep) > 0 {
+		fmt.Fprintln(w, "\nand keep:")
+		fmt.Fprintln(w)
+		return writeDeletePlanItems(w, plan.Keep)
 	}
+	return nil
+}
 
-	// 5. Delete session config (unless --keep-config)
-	if !keepConfig {
-		if err := sessionService.DeleteSessionConfig(name); err != nil {
-			return fmt.Errorf("failed to delete session config: %w", err)
-		}
-		fmt.Println("✓ Session config deleted")
-	} else {
-		session.UpdateStatus(config.StatusStopped)
-		if err := sessionService.SaveSession(session); err != nil {
-			return fmt.Errorf("failed to update session status: %w", err)
-		}
-		fmt.Println("✓ Session config kept (status: Stopped)")
+// writeDeletePlanItems prints plan items as a KIND NAME NOTE table
+func writeDeletePlanItems(w io.Writer, items []usecase.DeletePlanItem) error {
+	t := table.New(
+		table.Column{Header: "KIND"},
+		table.Column{Header: "NAME"},
+		table.Column{Header: "NOTE"},
+	)
+	for _, item := range items {
+		t.AddRow(item.Kind, item.Name, cmp.Or(item.Note, "-"))
 	}
-
-	fmt.Printf("\n✨ Session '%s' deleted\n", name)
-
-	return nil
+	return t.Render(w, table.Options{})
 }
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewDevCommand creates a new dev command that combines start and attach
+func NewDevCommand() *cobra.Command {
+	var (
+		flags     startFlags
+		attachCmd string
+		ttyMode   bool
+		localPort int
+		noBrowser bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "dev <name>",
+		Short: "Start a new session and attach to it",
+		Long: `Start a new Claude Code session and immediately attach to it.
+
+This command combines 'start' and 'attach' into a single workflow.
+By default, uses ttyd (web-based terminal) if enabled in the session.
+
+Examples:
+  kubectl kodama dev my-work --sync ~/projects/myrepo
+  kubectl kodama dev my-work --repo https://github.com/user/repo --branch main
+  kubectl kodama dev my-work --namespace dev --cpu 2 --memory 4Gi
+  kubectl kodama dev my-work --tty                    # Force TTY mode
+  kubectl kodama dev my-work --no-browser             # Use ttyd without opening browser`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			startOpts, err := flags.options(cmd, args[0])
+			if err != nil {
+				return err
+			}
+			ctx := cmd.Context()
+
+			// 1. Start the session
+			session, err := usecase.StartSession(ctx, startOpts)
+			if err != nil {
+				return err
+			}
+
+			// Print success message
+			fmt.Printf("\n✨ Session '%s' is ready!\n", session.Name)
+
+			if session.Sync.Enabled {
+				fmt.Printf("📁 Files synced from %s\n", session.Sync.LocalPath)
+			}
+
+			// 2. Attach to the session
+			fmt.Printf("\n🔗 Attaching to session '%s'...\n", session.Name)
+
+			attachOpts := usecase.AttachSessionOptions{
+				Name:           session.Name,
+				Command:        attachCmd,
+				KubeconfigPath: startOpts.KubeconfigPath,
+				TtyMode:        ttyMode,
+				LocalPort:      localPort,
+				NoBrowser:      noBrowser,
+			}
+
+			return usecase.AttachSession(ctx, attachOpts)
+		},
+	}
+
+	// Start flags
+	flags.register(cmd)
+
+	// Attach flags
+	cmd.Flags().StringVar(&attachCmd, "attach-command", "", "Command to run when attaching (default: interactive shell)")
+	cmd.Flags().BoolVar(&ttyMode, "tty", false, "Force TTY mode (disable ttyd)")
+	cmd.Flags().IntVar(&localPort, "port", 0, "Local port for port-forward when using ttyd (default: same as pod port)")
+	cmd.Flags().BoolVar(&noBrowser, "no-browser", false, "Don't open browser automatically when using ttyd")
+
+	return cmd
+}
@@ -0,0 +1,159 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/env"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+)
+
+// NewEnvCommand creates the env command for managing session environment variables at runtime
+func NewEnvCommand(sessionService *service.SessionService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Manage session environment variables without recreating the pod",
+		Long: `Manage environment variables of a running session.
+
+Changes are stored in the session's env secret and written to
+` + env.ShellFilePath + ` inside the pod, which is sourced by new shells.
+Processes that are already running keep their old environment.`,
+	}
+
+	cmd.AddCommand(newEnvSetCommand(sessionService))
+	cmd.AddCommand(newEnvUnsetCommand(sessionService))
+	cmd.AddCommand(newEnvListCommand(sessionService))
+
+	return cmd
+}
+
+func newEnvSetCommand(sessionService *service.SessionService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "set <name> KEY=VALUE [KEY=VALUE...]",
+		Short: "Set environment variables for a session",
+		Long: `Set or update environment variables for a session.
+
+Examples:
+  kubectl kodama env set my-work GITHUB_TOKEN=ghp_xxx
+  kubectl kodama env set my-work AWS_ACCESS_KEY_ID=... AWS_SECRET_ACCESS_KEY=...`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vars := make(map[string]string, len(args)-1)
+			for _, arg := range args[1:] {
+				key, value, ok := strings.Cut(arg, "=")
+				if !ok || key == "" {
+					return fmt.Errorf("invalid argument '%s': expected KEY=VALUE", arg)
+				}
+				vars[key] = value
+			}
+
+			ctx := cmd.Context()
+			session, err := sessionService.SetEnv(ctx, args[0], vars)
+			if err != nil {
+				return wrapEnvError(args[0], err)
+			}
+
+			fmt.Printf("✓ Set %d variable(s) in secret %s\n", len(vars), session.Env.SecretName)
+			writeEnvFile(ctx, sessionService, session)
+			return nil
+		},
+	}
+}
+
+func newEnvUnsetCommand(sessionService *service.SessionService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset <name> KEY [KEY...]",
+		Short: "Remove environment variables from a session",
+		Long: `Remove environment variables from a session.
+
+Examples:
+  kubectl kodama env unset my-work GITHUB_TOKEN`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			session, err := sessionService.UnsetEnv(ctx, args[0], args[1:])
+			if err != nil {
+				return wrapEnvError(args[0], err)
+			}
+
+			fmt.Printf("✓ Removed %d variable(s) from secret %s\n", len(args)-1, session.Env.SecretName)
+			writeEnvFile(ctx, sessionService, session)
+			return nil
+		},
+	}
+}
+
+func newEnvListCommand(sessionService *service.SessionService) *cobra.Command {
+	var showValues bool
+	var noHeaders bool
+
+	cmd := &cobra.Command{
+		Use:   "list <name>",
+		Short: "List environment variables of a session",
+		Long: `List environment variables stored in a session's env secret.
+Values are masked unless --show-values is given.
+
+Examples:
+  kubectl kodama env list my-work
+  kubectl kodama env list my-work --show-values`,
+		Aliases: []string{"ls"},
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			vars, err := sessionService.ListEnv(cmd.Context(), args[0])
+			if err != nil {
+				return wrapEnvError(args[0], err)
+			}
+
+			if len(vars) == 0 {
+				fmt.Println("No environment variables set")
+				return nil
+			}
+
+			keys := make([]string, 0, len(vars))
+			for key := range vars {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			t := table.New(table.Column{Header: "KEY"}, table.Column{Header: "VALUE"})
+			for _, key := range keys {
+				value := "********"
+				if showValues {
+					value = vars[key]
+				}
+				t.AddRow(key, value)
+			}
+			return t.Render(os.Stdout, table.Options{NoHeaders: noHeaders})
+		},
+	}
+
+	cmd.Flags().BoolVar(&showValues, "show-values", false, "Show variable values instead of masking them")
+	cmd.Flags().BoolVar(&noHeaders, "no-headers", false, "Don't print the table header row")
+
+	return cmd
+}
+
+// writeEnvFile refreshes the runtime env file in the pod, warning on failure
+// The secret is already updated, so the change applies on the next pod start regardless
+func writeEnvFile(ctx context.Context, sessionService *service.SessionService, session *config.SessionConfig) {
+	if err := sessionService.WriteEnvFile(ctx, session); err != nil {
+		fmt.Printf("⚠️  Warning: Failed to update env file in pod: %v\n", err)
+		return
+	}
+	fmt.Printf("✓ Updated %s (new shells will pick up the changes)\n", env.ShellFilePath)
+}
+
+func wrapEnvError(name string, err error) error {
+	if errors.Is(err, config.ErrSessionNotFound) {
+		return fmt.Errorf("%w: %s", config.ErrSessionNotFound, name)
+	}
+	return err
+}
@@ -0,0 +1,163 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// Exit codes returned by kubectl-kodama
+const (
+	ExitOK           = 0
+	ExitError        = 1 // Unclassified failure
+	ExitConfigError  = 2 // Invalid flags, config, or unknown session
+	ExitClusterError = 3 // Kubernetes API or pod failure
+	ExitAgentError   = 4 // Coding agent failed (with --fail-on-agent-error)
+	ExitSyncError    = 5 // File sync failed
+)
+
+var configErrors = []error{
+	config.ErrSessionNotFound,
+	config.ErrSessionNameRequired,
+	config.ErrInvalidSessionName,
+	config.ErrNamespaceRequired,
+	config.ErrRepoRequired,
+	config.ErrNewerSchema,
+	config.ErrNoCurrentSession,
+	config.ErrAmbiguousSession,
+	config.ErrMissingLabels,
+	config.ErrInvalidClaudeAuth,
+	usecase.ErrNoTestCommand,
+	usecase.ErrClusterOnly,
+}
+
+var clusterErrors = []error{
+	kubernetes.ErrPodNotFound,
+	kubernetes.ErrPodNotReady,
+	kubernetes.ErrQuotaExceeded,
+	kubernetes.ErrContainerNotFound,
+	kubernetes.ErrInPlaceResizeUnsupported,
+}
+
+// usageError is an invalid flag or argument, reported by cobra
+type usageError struct {
+	command string // Command path, e.g. "kubectl kodama start"
+	err     error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// RenderError prints err with a troubleshooting hint and returns the exit code
+func RenderError(w io.Writer, err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	_, _ = fmt.Fprintf(w, "Error: %v\n", err)
+	if hint := errorHint(err); hint != "" {
+		_, _ = fmt.Fprintf(w, "\n💡 %s\n", hint)
+	}
+	return ExitCode(err)
+}
+
+// ExitCode maps an error to the documented exit code
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	// Failed tests exit with the test command's own exit code
+	var testErr *usecase.TestFailedError
+	if errors.As(err, &testErr) {
+		return testErr.ExitCode
+	}
+
+	// Agent and sync failures take precedence over the cluster errors they wrap
+	switch {
+	case errors.Is(err, usecase.ErrAgentFailed):
+		return ExitAgentError
+	case errors.Is(err, usecase.ErrSyncFailed), errors.Is(err, usecase.ErrSyncConflict):
+		return ExitSyncError
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return ExitConfigError
+	}
+	for _, target := range configErrors {
+		if errors.Is(err, target) {
+			return ExitConfigError
+		}
+	}
+
+	var cloneErr *kubernetes.ErrCloneFailed
+	if errors.As(err, &cloneErr) {
+		return ExitClusterError
+	}
+	for _, target := range clusterErrors {
+		if errors.Is(err, target) {
+			return ExitClusterError
+		}
+	}
+	var status apierrors.APIStatus
+	if errors.As(err, &status) {
+		return ExitClusterError
+	}
+
+	return ExitError
+}
+
+// errorHint returns troubleshooting advice for known errors
+func errorHint(err error) string {
+	var cloneErr *kubernetes.ErrCloneFailed
+	if errors.As(err, &cloneErr) {
+		return cloneErr.Hint
+	}
+
+	var hintErr *kubernetes.HintError
+	if errors.As(err, &hintErr) {
+		return hintErr.Hint
+	}
+
+	var usageErr *usageError
+	if errors.As(err, &usageErr) {
+		return fmt.Sprintf("Run '%s --help' for usage", usageErr.command)
+	}
+
+	switch {
+	case errors.Is(err, config.ErrSessionNotFound):
+		return "Run 'kubectl kodama list' to see available sessions"
+	case errors.Is(err, config.ErrNoCurrentSession):
+		return "Pass a session name, run from a synced directory, or set a default with 'kubectl kodama use <name>'"
+	case errors.Is(err, config.ErrAmbiguousSession):
+		return "Pass a session name, or pick one with 'kubectl kodama use <name>'"
+	case errors.Is(err, usecase.ErrNoTestCommand):
+		return "Pass --cmd, or set test.command in .kodama.yaml before starting the session"
+	case errors.Is(err, usecase.ErrSyncConflict):
+		return "Pass --on-conflict pull to keep the pod's versions, or --on-conflict push to overwrite them"
+	case errors.Is(err, config.ErrMissingLabels):
+		return "Pass --label key=value, or set labels in the session template"
+	case errors.Is(err, config.ErrInvalidClaudeAuth):
+		return "Fix claudeAuth in ~/.kodama/config.yaml, then check it with 'kubectl kodama auth status'"
+	case errors.Is(err, config.ErrNewerSchema):
+		return "Upgrade kubectl-kodama, or use the version that wrote this file"
+	case errors.Is(err, config.ErrInvalidSessionName):
+		return "Pass --sanitize-name to convert the name into a valid one automatically"
+	case errors.Is(err, usecase.ErrAgentFailed):
+		return "The session is still running. Inspect it with 'kubectl kodama attach <name>'"
+	case errors.Is(err, usecase.ErrProtectedPathsChanged):
+		return "Review the patch, then push from the session, or set agent.onProtectedChange: revert"
+	case errors.Is(err, kubernetes.ErrSessionLocked):
+		return "Another user is attached to the session. Wait for them, or pass --steal to take over"
+	case errors.Is(err, kubernetes.ErrPodNotFound):
+		return "The session pod is gone. Start it again with 'kubectl kodama start <name>'"
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitOK},
+		{"generic", errors.New("boom"), ExitError},
+		{"session not found", fmt.Errorf("%w: demo", config.ErrSessionNotFound), ExitConfigError},
+		{"repo required", config.ErrRepoRequired, ExitConfigError},
+		{"no current session", config.ErrNoCurrentSession, ExitConfigError},
+		{"ambiguous session", fmt.Errorf("%w: a, b", config.ErrAmbiguousSession), ExitConfigError},
+		{"missing labels", fmt.Errorf("%w: cost-center", config.ErrMissingLabels), ExitConfigError},
+		{"newer schema", fmt.Errorf("failed to load session config: %w", config.ErrNewerSchema), ExitConfigError},
+		{"invalid claudeAuth", fmt.Errorf("%w: unknown auth type: bogus", config.ErrInvalidClaudeAuth), ExitConfigError},
+		{"invalid session name", config.ValidateSessionName("My_Work"), ExitConfigError},
+		{"pod not ready", kubernetes.NewPodNotReadyError("kodama-demo", "default", "(status: Pending)"), ExitClusterError},
+		{"clone failed", fmt.Errorf("start: %w", &kubernetes.ErrCloneFailed{Stage: "clone"}), ExitClusterError},
+		{"agent failed", fmt.Errorf("%w: exit status 1", usecase.ErrAgentFailed), ExitAgentError},
+		{"tests failed", &usecase.TestFailedError{ExitCode: 7}, 7},
+		{"no test command", fmt.Errorf("%w for session demo", usecase.ErrNoTestCommand), ExitConfigError},
+		{"cluster only", fmt.Errorf("snapshot is %w: session 'demo' runs as a local docker container", usecase.ErrClusterOnly), ExitConfigError},
+		{"sync failed", fmt.Errorf("%w: %w", usecase.ErrSyncFailed, kubernetes.ErrPodNotReady), ExitSyncError},
+		{"api error", apierrors.NewNotFound(schema.GroupResource{Resource: "pods"}, "kodama-demo"), ExitClusterError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderError_Hints(t *testing.T) {
+	var buf bytes.Buffer
+	code := RenderError(&buf, fmt.Errorf("%w: demo", config.ErrSessionNotFound))
+
+	if code != ExitConfigError {
+		t.Errorf("code = %d, want %d", code, ExitConfigError)
+	}
+	if !strings.Contains(buf.String(), "Error: session not found: demo") {
+		t.Errorf("missing error line: %q", buf.String())
+	}
+	if !strings.Contains(buf.String(), "kubectl kodama list") {
+		t.Errorf("missing hint: %q", buf.String())
+	}
+
+	buf.Reset()
+	RenderError(&buf, &kubernetes.ErrCloneFailed{Stage: "clone", Hint: "check GH_TOKEN"})
+	if !strings.Contains(buf.String(), "repository setup failed during clone") || !strings.Contains(buf.String(), "check GH_TOKEN") {
+		t.Errorf("unexpected clone failure output: %q", buf.String())
+	}
+	buf.Reset()
+	RenderError(&buf, &kubernetes.LockHeldError{Lock: kubernetes.SessionLock{Holder: "alice@laptop"}})
+	if !strings.Contains(buf.String(), "session is locked by alice@laptop") || !strings.Contains(buf.String(), "--steal") {
+		t.Errorf("unexpected lock output: %q", buf.String())
+	}
+}
@@ -0,0 +1,138 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+)
+
+// NewEventsCommand creates the events command
+func NewEventsCommand(sessionService *service.SessionService) *cobra.Command {
+	var (
+		outputFormat string
+		since        time.Duration
+		types        []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "events [name]",
+		Short: "Show the event timeline of a session",
+		Long: `Show what happened to a session, oldest first: when it was created, its
+pod became ready, syncs completed (with the files they changed), agent tasks
+started and finished, attaches were opened, and what failed.
+
+Without a name, the session syncing the working directory or the one
+selected with 'kodama use' is shown.
+
+Examples:
+  kubectl kodama events my-work
+  kubectl kodama events my-work --since 12h
+  kubectl kodama events my-work --type error,agent-finished -o json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch outputFormat {
+			case "table", "yaml", "json":
+			default:
+				return fmt.Errorf("invalid output format: %s (must be table, yaml or json)", outputFormat)
+			}
+
+			name, err := sessionNameArg(args)
+			if err != nil {
+				return err
+			}
+			events, err := sessionService.SessionEvents(name)
+			if err != nil {
+				return err
+			}
+
+			var after time.Time
+			if since > 0 {
+				after = time.Now().Add(-since)
+			}
+			events = filterEvents(events, after, types)
+
+			switch outputFormat {
+			case "yaml":
+				return yaml.NewEncoder(os.Stdout).Encode(events)
+			case "json":
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(events)
+			}
+			if len(events) == 0 {
+				fmt.Printf("No events for session '%s'\n", name)
+				return nil
+			}
+			return writeEventsTable(os.Stdout, events)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format: table, yaml, json")
+	cmd.Flags().DurationVar(&since, "since", 0, "Only show events newer than this duration (e.g., 12h)")
+	cmd.Flags().StringSliceVar(&types, "type", []string{}, "Only show events of these types (created, pod-ready, agent-ready, sync-completed, agent-started, agent-finished, attach-opened, error)")
+
+	return cmd
+}
+
+// filterEvents returns the events after the given time (zero = all) with one
+// of the given types (empty = all)
+func filterEvents(events []config.SessionEvent, after time.Time, types []string) []config.SessionEvent {
+	filtered := []config.SessionEvent{}
+	for _, event := range events {
+		if !after.IsZero() && event.Time.Before(after) {
+			continue
+		}
+		if len(types) > 0 && !slices.Contains(types, event.Type) {
+			continue
+		}
+		filtered = append(filtered, event)
+	}
+	return filtered
+}
+
+// writeEventsTable prints one row per event, with its details as sorted key=value pairs
+func writeEventsTable(w io.Writer, events []config.SessionEvent) error {
+	t := table.New(
+		table.Column{Header: "TIME"},
+		table.Column{Header: "TYPE", Color: eventColor},
+		table.Column{Header: "MESSAGE", MaxWidth: 80},
+		table.Column{Header: "DETAILS"},
+	)
+	for _, event := range events {
+		t.AddRow(event.Time.Local().Format(time.DateTime), event.Type, event.Message, formatEventDetails(event.Details))
+	}
+	return t.Render(w, table.Options{Color: table.ColorEnabled(w)})
+}
+
+// eventColor highlights errors in the TYPE column
+func eventColor(eventType string) table.Color {
+	if eventType == config.EventError {
+		return table.ColorRed
+	}
+	return table.ColorNone
+}
+
+// formatEventDetails formats details as "key=value" pairs sorted by key
+func formatEventDetails(details map[string]string) string {
+	if len(details) == 0 {
+		return "-"
+	}
+	pairs := make([]string, 0, len(details))
+	for key, value := range details {
+		pairs = append(pairs, key+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
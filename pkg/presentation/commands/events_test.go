@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+func TestFilterEvents(t *testing.T) {
+	now := time.Now()
+	events := []config.SessionEvent{
+		{Time: now.Add(-48 * time.Hour), Type: config.EventCreated},
+		{Time: now.Add(-2 * time.Hour), Type: config.EventAgentFinished},
+		{Time: now.Add(-time.Hour), Type: config.EventError},
+	}
+
+	if got := filterEvents(events, time.Time{}, nil); len(got) != 3 {
+		t.Errorf("filterEvents() without filters kept %d events, want 3", len(got))
+	}
+	if got := filterEvents(events, now.Add(-12*time.Hour), nil); len(got) != 2 {
+		t.Errorf("filterEvents(since 12h) kept %d events, want 2", len(got))
+	}
+	got := filterEvents(events, now.Add(-12*time.Hour), []string{config.EventError, config.EventCreated})
+	if len(got) != 1 || got[0].Type != config.EventError {
+		t.Errorf("filterEvents(since 12h, error/created) = %v, want the error", got)
+	}
+}
+
+func TestFormatEventDetails(t *testing.T) {
+	if got := formatEventDetails(nil); got != "-" {
+		t.Errorf("formatEventDetails(nil) = %q, want -", got)
+	}
+	got := formatEventDetails(map[string]string{"modified": "2", "added": "3"})
+	if got != "added=3 modified=2" {
+		t.Errorf("formatEventDetails() = %q", got)
+	}
+}
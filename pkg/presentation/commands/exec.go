@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewExecCommand creates a new exec command
+func NewExecCommand() *cobra.Command {
+	var container string
+
+	cmd := &cobra.Command{
+		Use:   "exec [name] -- <command> [args...]",
+		Short: "Run a command in a session container",
+		Long: `Run a command in a session's pod, like 'kubectl exec'.
+
+Use --container to target a sidecar instead of the main container. The name is
+checked against the live pod spec. Without a name before --, the command runs in
+the session syncing the working directory, or else the current session set with
+'kubectl kodama use'.
+
+Examples:
+  kubectl kodama exec my-work -- git status
+  kubectl kodama exec my-work --container diff-viewer -- sh
+  kubectl kodama exec -- git status             # Current session`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			// Arguments before -- name the session; without --, the first one does
+			nameArgs, command := args[:1], args[1:]
+			if dash := cmd.ArgsLenAtDash(); dash == 0 {
+				nameArgs, command = nil, args
+			} else if dash > 1 {
+				return fmt.Errorf("expected at most one session name before --, got %d", dash)
+			}
+			name, err := sessionNameArg(nameArgs)
+			if err != nil {
+				return err
+			}
+
+			return usecase.ExecSession(cmd.Context(), usecase.ExecSessionOptions{
+				Name:           name,
+				KubeconfigPath: kubeconfigPath,
+				Container:      container,
+				Command:        command,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&container, "container", "c", "", "Container to run in (default: main container)")
+
+	return cmd
+}
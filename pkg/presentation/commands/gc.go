@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+)
+
+// NewGCCommand creates the gc command
+func NewGCCommand(sessionService *service.SessionService) *cobra.Command {
+	var (
+		opts          service.GCOptions
+		allNamespaces bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove resources left behind by deleted sessions",
+		Long: `Remove what deleted or failed sessions left behind.
+
+By default only local leftovers are removed: session and SSH directories in
+~/.kodama whose session config is gone. With --cluster, kodama Secrets and
+ConfigMaps in the namespace are removed as well when their session has no pod
+and they are older than --min-age. Resources are judged by their pod, not by
+your local sessions, so teammates' sessions in a shared namespace are kept.
+
+Examples:
+  kubectl kodama gc
+  kubectl kodama gc --cluster --dry-run
+  kubectl kodama gc --cluster -A --min-age 1h`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Cluster && !allNamespaces {
+				namespaceFlag, _ := cmd.Flags().GetString("namespace")
+				namespace, err := sessionService.ResolveNamespace(namespaceFlag)
+				if err != nil {
+					return err
+				}
+				opts.Namespace = namespace
+			}
+
+			result, err := sessionService.CollectGarbage(cmd.Context(), opts)
+			if result != nil {
+				printGCResult(result, opts.DryRun)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().BoolVar(&opts.Cluster, "cluster", false, "Also remove session Secrets and ConfigMaps whose pod is gone")
+	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "With --cluster, sweep every namespace (ignores --namespace)")
+	cmd.Flags().DurationVar(&opts.MinAge, "min-age", service.DefaultGCMinAge, "Only remove cluster resources older than this")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Only list what would be removed")
+
+	return cmd
+}
+
+// printGCResult lists what was removed, or would be with --dry-run
+func printGCResult(result *service.GCResult, dryRun bool) {
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	for _, dir := range result.LocalDirs {
+		fmt.Printf("🗑️  %s %s\n", verb, dir)
+	}
+	for _, resource := range result.Resources {
+		fmt.Printf("🗑️  %s %s %s/%s (session %s)\n", verb, resource.Kind, resource.Namespace, resource.Name, resource.Session)
+	}
+	if len(result.LocalDirs) == 0 && len(result.Resources) == 0 {
+		fmt.Println("✓ Nothing to clean up")
+	}
+}
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/internal/krew"
+	"github.com/illumination-k/kodama/internal/version"
+)
+
+// NewKrewManifestCommand creates the release helper that generates the krew plugin manifest
+func NewKrewManifestCommand() *cobra.Command {
+	var (
+		releaseVersion string
+		checksumsPath  string
+		output         string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "krew-manifest",
+		Short: "Generate the krew plugin manifest for a release",
+		Long: `Generate the krew plugin manifest (kodama.yaml) for a GitHub release.
+
+The checksums file lists the sha256 of each release archive, named
+kubectl-kodama_<version>_<os>_<arch>.tar.gz, in sha256sum format.
+
+Examples:
+  kubectl kodama krew-manifest --version v0.5.0 --checksums dist/checksums.txt -o plugins/kodama.yaml`,
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// #nosec G304 -- user-provided path
+			f, err := os.Open(checksumsPath)
+			if err != nil {
+				return fmt.Errorf("failed to open checksums: %w", err)
+			}
+			defer func() { _ = f.Close() }()
+
+			checksums, err := krew.ParseChecksums(f)
+			if err != nil {
+				return err
+			}
+			platforms, err := krew.ReleasePlatforms(releaseVersion, checksums)
+			if err != nil {
+				return err
+			}
+			data, err := krew.Manifest(releaseVersion, platforms)
+			if err != nil {
+				return err
+			}
+
+			if output == "" {
+				_, err = os.Stdout.Write(data)
+				return err
+			}
+			if err := os.WriteFile(output, data, 0o600); err != nil {
+				return fmt.Errorf("failed to write manifest: %w", err)
+			}
+			fmt.Printf("✓ Krew manifest written to %s\n", output)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&releaseVersion, "version", "", "Release tag, e.g. v0.5.0")
+	cmd.Flags().StringVar(&checksumsPath, "checksums", "", "Checksums file of the release archives")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Write the manifest to a file instead of stdout")
+	_ = cmd.MarkFlagRequired("version")
+	_ = cmd.MarkFlagRequired("checksums")
+
+	return cmd
+}
+
+// NewInstallKrewCommand creates the developer helper that installs the running
+// binary through krew, to test the plugin as users install it
+func NewInstallKrewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "install-krew",
+		Short: "Install this binary as the kodama krew plugin (development)",
+		Long: `Package the running binary into a krew archive with a local manifest and
+install it with 'kubectl krew install', replacing any installed kodama plugin.
+Requires krew (https://krew.sigs.k8s.io).
+
+Examples:
+  go build -o bin/kubectl-kodama ./cmd/kubectl-kodama && bin/kubectl-kodama install-krew`,
+		Hidden: true,
+		Args:   cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			binaryPath, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to locate executable: %w", err)
+			}
+
+			dir, err := os.MkdirTemp("", "kodama-krew-")
+			if err != nil {
+				return fmt.Errorf("failed to create temp directory: %w", err)
+			}
+			defer func() { _ = os.RemoveAll(dir) }()
+
+			archivePath := filepath.Join(dir, krew.ArchiveName(version.Version, runtime.GOOS, runtime.GOARCH))
+			// #nosec G304 -- path in our temp directory
+			archive, err := os.Create(archivePath)
+			if err != nil {
+				return fmt.Errorf("failed to create archive: %w", err)
+			}
+			sum, err := krew.WriteArchive(archive, binaryPath)
+			_ = archive.Close()
+			if err != nil {
+				return err
+			}
+
+			// krew requires a semver version in the manifest
+			manifestVersion := version.Version
+			if _, err := version.ParseSemver(manifestVersion); err != nil {
+				manifestVersion = "v0.0.0-dev"
+			}
+			data, err := krew.Manifest(manifestVersion, []krew.Platform{{
+				OS:     runtime.GOOS,
+				Arch:   runtime.GOARCH,
+				URI:    "file://" + archivePath,
+				SHA256: sum,
+			}})
+			if err != nil {
+				return err
+			}
+			manifestPath := filepath.Join(dir, krew.PluginName+".yaml")
+			if err := os.WriteFile(manifestPath, data, 0o600); err != nil {
+				return fmt.Errorf("failed to write manifest: %w", err)
+			}
+
+			fmt.Printf("⏳ Installing %s (%s/%s) with krew...\n", binaryPath, runtime.GOOS, runtime.GOARCH)
+			// Reinstall so repeated runs pick up the new build
+			_ = exec.CommandContext(cmd.Context(), "kubectl", "krew", "uninstall", krew.PluginName).Run()
+
+			// #nosec G204 -- arguments are paths in our temp directory
+			install := exec.CommandContext(cmd.Context(), "kubectl", "krew", "install", "--manifest="+manifestPath, "--archive="+archivePath)
+			install.Stdout = os.Stdout
+			install.Stderr = os.Stderr
+			if err := install.Run(); err != nil {
+				return fmt.Errorf("kubectl krew install failed: %w", err)
+			}
+
+			fmt.Println("✓ Installed. Run 'kubectl kodama version' to check")
+			return nil
+		},
+	}
+}
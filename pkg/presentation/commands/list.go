@@ -1,102 +1,190 @@
 package commands
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
-	"text/tabwriter"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v3"
 
 	"github.com/illumination-k/kodama/pkg/application/service"
 	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
 )
 
+// listOptions holds the list command flags
+type listOptions struct {
+	allNamespaces bool
+	outputFormat  string
+	noHeaders     bool
+	status        string
+	labels        []string
+	sortBy        string
+	groupBy       string
+}
+
 // NewListCommand creates a new list command
 func NewListCommand(sessionService *service.SessionService) *cobra.Command {
-	var allNamespaces bool
-	var outputFormat string
+	var opts listOptions
 
 	cmd := &cobra.Command{
 		Use:     "list",
 		Short:   "List all sessions",
 		Aliases: []string{"ls"},
+		Long: `List sessions, optionally filtered, sorted and grouped.
+
+Examples:
+  kubectl kodama list
+  kubectl kodama list --namespace dev --status running
+  kubectl kodama list --label team=payments --sort created
+  kubectl kodama list --group-by namespace`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runList(sessionService, outputFormat)
+			namespace, _ := cmd.Flags().GetString("namespace")
+			if opts.allNamespaces {
+				namespace = ""
+			}
+
+			labels, err := config.ParseLabels(opts.labels)
+			if err != nil {
+				return err
+			}
+
+			switch opts.groupBy {
+			case "", groupByNamespace, groupByStatus:
+			default:
+				return fmt.Errorf("invalid group-by field: %s (must be namespace or status)", opts.groupBy)
+			}
+
+			query := config.SessionQuery{
+				Namespace: namespace,
+				Status:    config.SessionStatus(opts.status),
+				Labels:    labels,
+				SortBy:    config.SessionSort(opts.sortBy),
+			}
+			return runList(cmd.Context(), sessionService, query, opts)
 		},
 	}
 
-	cmd.Flags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "List sessions from all namespaces")
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "table", "Output format: table, yaml, json")
+	cmd.Flags().BoolVarP(&opts.allNamespaces, "all-namespaces", "A", false, "List sessions from all namespaces (ignores --namespace)")
+	cmd.Flags().StringVarP(&opts.outputFormat, "output", "o", "table", "Output format: table, yaml, json")
+	cmd.Flags().BoolVar(&opts.noHeaders, "no-headers", false, "Don't print the table header row")
+	cmd.Flags().StringVar(&opts.status, "status", "", "Only list sessions with this status (e.g., running, failed)")
+	cmd.Flags().StringSliceVarP(&opts.labels, "label", "l", []string{}, "Only list sessions with this label (format: key=value, can be specified multiple times)")
+	cmd.Flags().StringVar(&opts.sortBy, "sort", "name", "Sort by: name, created, updated, status, namespace")
+	cmd.Flags().StringVar(&opts.groupBy, "group-by", "", "Group the table by: namespace, status")
 
 	return cmd
 }
 
-func runList(sessionService *service.SessionService, outputFormat string) error {
-	ctx := context.Background()
+// Fields sessions can be grouped by in table output
+const (
+	groupByNamespace = "namespace"
+	groupByStatus    = "status"
+)
 
-	// 1. Load sessions from ~/.kodama/sessions/
-	sessions, err := sessionService.ListSessions()
+func runList(ctx context.Context, sessionService *service.SessionService, query config.SessionQuery, opts listOptions) error {
+	// 1. Load matching sessions from ~/.kodama/sessions/ with their pods
+	statuses, err := sessionService.ListSessionStatuses(ctx, query)
 	if err != nil {
 		return fmt.Errorf("failed to list sessions: %w", err)
 	}
 
-	if len(sessions) == 0 {
-		fmt.Println("No sessions found")
-		return nil
+	// 2. Reconcile sessions with actual pod and sync status
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(service.ListConcurrency)
+	for _, status := range statuses {
+		g.Go(func() error {
+			reconcileSession(gctx, sessionService, status)
+			return nil
+		})
 	}
+	_ = g.Wait()
 
-	// 2. Enrich sessions with actual pod and sync status
-	for _, session := range sessions {
-		// Verify pod status
-		podStatus, err := sessionService.GetPod(ctx, session.PodName, session.Namespace)
-		if err != nil {
-			// Pod doesn't exist or error
-			if session.Status == config.StatusRunning {
-				session.UpdateStatus(config.StatusStopped)
-				_ = sessionService.SaveSession(session) // Best effort update
-			}
-		} else {
-			// Update status based on pod phase
-			if podStatus.Ready && session.Status != config.StatusRunning {
-				session.UpdateStatus(config.StatusRunning)
-				_ = sessionService.SaveSession(session) // Best effort update
-			} else if !podStatus.Ready && session.Status == config.StatusRunning {
-				session.UpdateStatus(config.StatusFailed)
-				_ = sessionService.SaveSession(session) // Best effort update
+	// Reconciliation may change the status, so drop sessions that no longer match
+	sessions := make([]*config.SessionConfig, 0, len(statuses))
+	holders := make(map[string]string)
+	for _, status := range statuses {
+		if query.Matches(status.Session) {
+			sessions = append(sessions, status.Session)
+			if status.Pod != nil && status.Pod.Lock != nil {
+				holders[status.Session.Name] = status.Pod.Lock.Holder
 			}
 		}
+	}
 
-		// Check sync session status if enabled
-		if session.Sync.Enabled && session.Sync.MutagenSession != "" {
-			_, err := sessionService.GetSyncManager().Status(ctx, session.Sync.MutagenSession)
-			if err != nil {
-				// Sync session is gone
-				session.Sync.Enabled = false
-				_ = sessionService.SaveSession(session) // Best effort update
-			}
-		}
+	if len(sessions) == 0 {
+		fmt.Println("No sessions found")
+		return nil
 	}
 
+	// The current session is marked in table output; a failed read just leaves it unmarked
+	current, _ := sessionService.CurrentSession()
+
 	// 3. Display in requested format
-	switch outputFormat {
+	switch opts.outputFormat {
 	case "yaml":
-		return outputYAML(sessions)
+		return outputYAML(withRecentEvents(sessionService, sessions))
 	case "json":
-		return outputJSON(sessions)
+		return outputJSON(withRecentEvents(sessionService, sessions))
 	default:
-		return outputTable(sessions)
+		if opts.groupBy != "" {
+			return outputGroupedTable(sessions, holders, current, opts.groupBy, opts.noHeaders)
+		}
+		return outputTable(sessions, holders, current, opts.noHeaders)
 	}
 }
 
-func outputTable(sessions []*config.SessionConfig) error {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer func() { _ = w.Flush() }()
+// reconcileSession updates a session's stored status from its pod and sync session
+func reconcileSession(ctx context.Context, sessionService *service.SessionService, status service.SessionStatus) {
+	session := status.Session
+	if status.PodErr != nil {
+		// Pod doesn't exist or error
+		if session.Status == config.StatusRunning {
+			session.UpdateStatus(config.StatusStopped)
+			_ = sessionService.SaveSession(session) // Best effort update
+		}
+	} else {
+		// Update status based on pod phase
+		if status.Pod.Ready && session.Status != config.StatusRunning {
+			session.UpdateStatus(config.StatusRunning)
+			_ = sessionService.SaveSession(session) // Best effort update
+		} else if !status.Pod.Ready && session.Status == config.StatusRunning {
+			session.UpdateStatus(config.StatusFailed)
+			_ = sessionService.SaveSession(session) // Best effort update
+		}
+	}
+
+	// Check sync session status if enabled
+	if session.Sync.Enabled && session.Sync.MutagenSession != "" {
+		_, err := sessionService.GetSyncManager().Status(ctx, session.Sync.MutagenSession)
+		if err != nil {
+			// Sync session is gone
+			session.Sync.Enabled = false
+			_ = sessionService.SaveSession(session) // Best effort update
+		}
+	}
+}
 
-	_, _ = fmt.Fprintln(w, "NAME\tSTATUS\tNAMESPACE\tPATH\tSYNC\tAGE")
+// outputTable prints sessions; holders maps session names to their lock holder,
+// and the current session is marked with * like kubectl config get-contexts
+func outputTable(sessions []*config.SessionConfig, holders map[string]string, current string, noHeaders bool) error {
+	t := table.New(
+		table.Column{Header: "CURRENT"},
+		table.Column{Header: "NAME"},
+		table.Column{Header: "STATUS", Color: table.StatusColor},
+		table.Column{Header: "NAMESPACE"},
+		table.Column{Header: "PATH", MaxWidth: 50},
+		table.Column{Header: "SYNC", Color: table.StatusColor},
+		table.Column{Header: "LOCKED BY"},
+		table.Column{Header: "CREATED"},
+	)
 
+	now := time.Now()
 	for _, session := range sessions {
 		syncStatus := "-"
 		if session.Sync.Enabled {
@@ -111,22 +199,77 @@ func outputTable(sessions []*config.SessionConfig) error {
 			pathDisplay = session.Sync.LocalPath
 		}
 
-		age := formatDuration(time.Since(session.CreatedAt))
+		marker := ""
+		if session.Name == current {
+			marker = "*"
+		}
 
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+		t.AddRow(
+			marker,
 			session.Name,
-			session.Status,
+			string(session.Status),
 			session.Namespace,
 			pathDisplay,
 			syncStatus,
-			age,
+			cmp.Or(holders[session.Name], "-"),
+			table.RelativeTime(session.CreatedAt, now),
 		)
 	}
 
+	return t.Render(os.Stdout, table.Options{NoHeaders: noHeaders, Color: table.ColorEnabled(os.Stdout)})
+}
+
+// outputGroupedTable prints one table per namespace or status, in order of first appearance
+func outputGroupedTable(sessions []*config.SessionConfig, holders map[string]string, current, groupBy string, noHeaders bool) error {
+	var keys []string
+	groups := make(map[string][]*config.SessionConfig)
+	for _, session := range sessions {
+		key := session.Namespace
+		if groupBy == groupByStatus {
+			key = string(session.Status)
+		}
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], session)
+	}
+
+	for i, key := range keys {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%s: %s (%d)\n", strings.ToUpper(groupBy), key, len(groups[key]))
+		if err := outputTable(groups[key], holders, current, noHeaders); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func outputYAML(sessions []*config.SessionConfig) error {
+// recentEventsLimit is the number of timeline events shown per session in yaml and json output
+const recentEventsLimit = 5
+
+// sessionOutput is a session in yaml and json output, with the end of its timeline
+type sessionOutput struct {
+	config.SessionConfig `yaml:",inline"`
+	RecentEvents         []config.SessionEvent `yaml:"recentEvents,omitempty"`
+}
+
+// withRecentEvents attaches the most recent events of each session; a timeline
+// that cannot be read is left out
+func withRecentEvents(sessionService *service.SessionService, sessions []*config.SessionConfig) []sessionOutput {
+	outputs := make([]sessionOutput, 0, len(sessions))
+	for _, session := range sessions {
+		events, _ := sessionService.GetSessionRepository().LoadEvents(session.Name)
+		if len(events) > recentEventsLimit {
+			events = events[len(events)-recentEventsLimit:]
+		}
+		outputs = append(outputs, sessionOutput{SessionConfig: *session, RecentEvents: events})
+	}
+	return outputs
+}
+
+func outputYAML(sessions []sessionOutput) error {
 	encoder := yaml.NewEncoder(os.Stdout)
 	encoder.SetIndent(2)
 	defer func() { _ = encoder.Close() }()
@@ -141,7 +284,7 @@ func outputYAML(sessions []*config.SessionConfig) error {
 	return nil
 }
 
-func outputJSON(sessions []*config.SessionConfig) error {
+func outputJSON(sessions []sessionOutput) error {
 	// For JSON output, we'll use YAML library which can produce JSON-like output
 	// A proper JSON implementation would use encoding/json
 	data, err := yaml.Marshal(sessions)
@@ -152,20 +295,3 @@ func outputJSON(sessions []*config.SessionConfig) error {
 	fmt.Println(string(data))
 	return nil
 }
-
-func formatDuration(d time.Duration) string {
-	switch {
-	case d < time.Minute:
-		return fmt.Sprintf("%ds", int(d.Seconds()))
-	case d < time.Hour:
-		return fmt.Sprintf("%dm", int(d.Minutes()))
-	case d < 24*time.Hour:
-		return fmt.Sprintf("%dh", int(d.Hours()))
-	case d < 7*24*time.Hour:
-		return fmt.Sprintf("%dd", int(d.Hours()/24))
-	case d < 30*24*time.Hour:
-		return fmt.Sprintf("%dw", int(d.Hours()/(24*7)))
-	default:
-		return fmt.Sprintf("%dmo", int(d.Hours()/(24*30)))
-	}
-}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewLogsCommand creates a new logs command
+func NewLogsCommand() *cobra.Command {
+	var (
+		container string
+		follow    bool
+		tail      int64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "logs [name]",
+		Short: "Show logs of a session container",
+		Long: `Show the logs of a session's main container, a sidecar, or an init container.
+Without a name, shows the logs of the session syncing the working directory, or
+else the current session set with 'kubectl kodama use'.
+
+Examples:
+  kubectl kodama logs my-work
+  kubectl kodama logs my-work -f --tail 100
+  kubectl kodama logs my-work --container workspace-initializer`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			name, err := sessionNameArg(args)
+			if err != nil {
+				return err
+			}
+
+			return usecase.SessionLogs(cmd.Context(), usecase.SessionLogsOptions{
+				Name:           name,
+				KubeconfigPath: kubeconfigPath,
+				Container:      container,
+				Follow:         follow,
+				TailLines:      tail,
+			}, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&container, "container", "c", "", "Container to show logs for (default: main container)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Stream new log lines")
+	cmd.Flags().Int64Var(&tail, "tail", 0, "Number of recent lines to show (default: all)")
+
+	return cmd
+}
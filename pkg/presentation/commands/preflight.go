@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/internal/plugin"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// skipKubectlCheck lists the commands that never run kubectl or reach the cluster
+var skipKubectlCheck = map[string]bool{
+	"version":                       true, // Reports the versions itself
+	"help":                          true,
+	"completion":                    true,
+	cobra.ShellCompRequestCmd:       true,
+	cobra.ShellCompNoDescRequestCmd: true,
+	"config":                        true,
+	"store":                         true,
+	"report":                        true,
+	"credential":                    true,
+	"krew-manifest":                 true,
+	"install-krew":                  true,
+}
+
+// warnKubectlProblems prints one warning listing the kubectl problems found
+// for the current kubeconfig context, before a command fails halfway on them.
+// Set KODAMA_SKIP_KUBECTL_CHECK to disable the check.
+func warnKubectlProblems(cmd *cobra.Command, w io.Writer) {
+	if os.Getenv("KODAMA_SKIP_KUBECTL_CHECK") != "" {
+		return
+	}
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if skipKubectlCheck[c.Name()] {
+			return
+		}
+	}
+
+	kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+	problems := usecase.KubectlProblems(cmd.Context(), kubeconfigPath)
+	if len(problems) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "⚠️  Warning: kubectl may not work with this cluster:")
+	for _, problem := range problems {
+		fmt.Fprintf(w, "   - %s\n", problem)
+	}
+	fmt.Fprintf(w, "   Run '%s version' for details, or set KODAMA_SKIP_KUBECTL_CHECK=1 to skip this check\n", plugin.CommandName())
+}
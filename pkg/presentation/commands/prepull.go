@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// NewPrepullCommand creates the prepull command
+func NewPrepullCommand(sessionService *service.SessionService) *cobra.Command {
+	var (
+		opts    kubernetes.PrepullOptions
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prepull",
+		Short: "Pull the session image onto every node ahead of time",
+		Long: `Pull the session image onto every node so new sessions don't wait on
+ImagePull when they land on a fresh node.
+
+A temporary DaemonSet runs the image once on each node (tolerating all taints)
+and is deleted as soon as every node has pulled it, or when the command times
+out or is interrupted.
+
+Examples:
+  kubectl kodama prepull
+  kubectl kodama prepull --image ghcr.io/myorg/dev:latest
+  kubectl kodama prepull --node-selector pool=agents`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespaceFlag, _ := cmd.Flags().GetString("namespace")
+			namespace, err := sessionService.ResolveNamespace(namespaceFlag)
+			if err != nil {
+				return err
+			}
+			opts.Namespace = namespace
+
+			opts.Image, err = sessionService.ResolveImage(opts.Image)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("📥 Pulling %s onto nodes...\n", opts.Image)
+			result, err := sessionService.PrepullImage(cmd.Context(), opts, timeout)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Image pulled on %d/%d nodes\n", result.Ready, result.Desired)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.Image, "image", "", "Image to pull (default: session image from config)")
+	cmd.Flags().StringToStringVar(&opts.NodeSelector, "node-selector", nil, "Only pull on nodes with these labels (e.g. pool=agents)")
+	cmd.Flags().DurationVar(&timeout, "timeout", kubernetes.DefaultPrepullTimeout, "Maximum time to wait for all nodes")
+	_ = cmd.RegisterFlagCompletionFunc("image", completeImages)
+
+	return cmd
+}
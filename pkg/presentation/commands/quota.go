@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+)
+
+// NewQuotaCommand creates the quota command
+func NewQuotaCommand(sessionService *service.SessionService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Show the resources of kodama sessions against the namespace quotas",
+		Long: `Sum the requests and limits of all kodama pods in the namespace and
+compare them with the namespace's ResourceQuotas.
+
+KODAMA is what kodama pods use of a quota resource, USED what every pod and
+object in the namespace uses, and LEFT what a new session can still take.
+'kubectl kodama start' warns when a new session would exceed what is left.
+
+Examples:
+  kubectl kodama quota
+  kubectl kodama quota -n team-a`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespaceFlag, _ := cmd.Flags().GetString("namespace")
+			namespace, err := sessionService.ResolveNamespace(namespaceFlag)
+			if err != nil {
+				return err
+			}
+
+			report, err := sessionService.QuotaReport(cmd.Context(), namespace)
+			if err != nil {
+				return err
+			}
+
+			printQuotaReport(report)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// printQuotaReport prints the kodama totals and a table of quota resources
+func printQuotaReport(report *kubernetes.QuotaReport) {
+	fmt.Printf("Namespace %s: %d kodama pod(s)\n", report.Namespace, report.Pods)
+	fmt.Printf("  Requests: %s\n", formatResourceList(report.Requests))
+	fmt.Printf("  Limits:   %s\n", formatResourceList(report.Limits))
+
+	usage := report.Usage()
+	if len(usage) == 0 {
+		fmt.Println("\nNo ResourceQuota in this namespace")
+		return
+	}
+	fmt.Println()
+
+	t := table.New(
+		table.Column{Header: "QUOTA"},
+		table.Column{Header: "RESOURCE"},
+		table.Column{Header: "KODAMA"},
+		table.Column{Header: "USED"},
+		table.Column{Header: "HARD"},
+		table.Column{Header: "LEFT"},
+	)
+	for _, u := range usage {
+		kodama := "-"
+		if u.Kodama != nil {
+			kodama = u.Kodama.String()
+		}
+		left := u.Hard.DeepCopy()
+		left.Sub(u.Used)
+		if left.Sign() < 0 {
+			left.Set(0)
+		}
+		t.AddRow(u.Quota, string(u.Resource), kodama, u.Used.String(), u.Hard.String(), left.String())
+	}
+	_ = t.Render(os.Stdout, table.Options{Color: table.ColorEnabled(os.Stdout)})
+}
+
+// formatResourceList formats resources as "cpu=2, memory=4Gi", sorted by name
+func formatResourceList(list corev1.ResourceList) string {
+	if len(list) == 0 {
+		return "none"
+	}
+	parts := make([]string, 0, len(list))
+	for name, quantity := range list {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, quantity.String()))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ", ")
+}
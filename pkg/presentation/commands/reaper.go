@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// NewInstallReaperCommand creates the install-reaper command
+func NewInstallReaperCommand(sessionService *service.SessionService) *cobra.Command {
+	var (
+		schedule string
+		image    string
+		dryRun   bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install-reaper",
+		Short: "Install a CronJob that deletes expired sessions",
+		Long: `Install a CronJob in the namespace that deletes kodama pods whose
+expiration (set with 'start --expires') has passed, along with their secrets.
+
+The reaper runs in the cluster, so expired sessions are cleaned up even when
+your machine is offline. Running the command again updates the CronJob.
+
+Examples:
+  kubectl kodama install-reaper --namespace dev
+  kubectl kodama install-reaper --schedule "0 * * * *"
+  kubectl kodama install-reaper --dry-run > reaper.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			namespaceFlag, _ := cmd.Flags().GetString("namespace")
+			namespace, err := sessionService.ResolveNamespace(namespaceFlag)
+			if err != nil {
+				return err
+			}
+
+			opts := kubernetes.ReaperOptions{
+				Namespace: namespace,
+				Schedule:  schedule,
+				Image:     image,
+			}
+
+			if dryRun {
+				return writeReaperManifests(opts)
+			}
+
+			if err := sessionService.InstallReaper(cmd.Context(), opts); err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Reaper CronJob '%s' installed in namespace '%s'\n", kubernetes.ReaperName, namespace)
+			fmt.Printf("  Schedule: %s\n", opts.Schedule)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&schedule, "schedule", kubernetes.DefaultReaperSchedule, "Cron schedule for the reaper")
+	cmd.Flags().StringVar(&image, "image", kubernetes.DefaultReaperImage, "Container image providing kubectl")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print manifests instead of applying them")
+
+	return cmd
+}
+
+// writeReaperManifests prints the reaper manifests as multi-document YAML
+func writeReaperManifests(opts kubernetes.ReaperOptions) error {
+	for i, obj := range kubernetes.BuildReaperManifests(opts) {
+		if i > 0 {
+			fmt.Println("---")
+		}
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+		if _, err := os.Stdout.Write(data); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+	return nil
+}
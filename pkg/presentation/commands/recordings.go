@@ -0,0 +1,85 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewRecordingsCommand creates a new recordings command
+func NewRecordingsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recordings",
+		Short: "List and download terminal recordings",
+		Long: `List and download terminal recordings of sessions started with --record.
+
+Each ttyd connection and interactive attach shell is recorded with script(1)
+as a pair of <timestamp>.log and <timestamp>.timing files in
+/workspace/.kodama/recordings. Replay one locally with:
+
+  scriptreplay --timing=<timestamp>.timing <timestamp>.log`,
+	}
+
+	cmd.AddCommand(newRecordingsListCommand())
+	cmd.AddCommand(newRecordingsPullCommand())
+
+	return cmd
+}
+
+func newRecordingsListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list <name>",
+		Short: "List terminal recordings of a session",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			recordings, err := usecase.ListRecordings(cmd.Context(), args[0], kubeconfigPath)
+			if err != nil {
+				return err
+			}
+
+			if len(recordings) == 0 {
+				fmt.Printf("No recordings for session '%s'\n", args[0])
+				return nil
+			}
+			for _, recording := range recordings {
+				fmt.Println(recording)
+			}
+			return nil
+		},
+	}
+}
+
+func newRecordingsPullCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "pull <name>",
+		Short: "Download terminal recordings of a session",
+		Long: `Download a session's recordings directory.
+
+Examples:
+  kubectl kodama recordings pull my-work
+  kubectl kodama recordings pull my-work -o ./review/my-work`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			dir, err := usecase.PullRecordings(cmd.Context(), args[0], kubeconfigPath, output)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Recordings saved to %s\n", dir)
+			fmt.Printf("\nReplay with:\n  scriptreplay --timing=%s/<recording>.timing %s/<recording>.log\n", dir, dir)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", ".", "Directory to save the recordings in")
+
+	return cmd
+}
@@ -0,0 +1,149 @@
+package commands
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+)
+
+// NewReportCommand creates the report command
+func NewReportCommand(sessionService *service.SessionService) *cobra.Command {
+	var (
+		by     []string
+		format string
+		since  string
+		until  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "report",
+		Short: "Aggregate session runtime by label for cost allocation",
+		Long: `Sum the runtime of the sessions in the local store by label values,
+with CPU-hours and memory GiB-hours weighted by each session's limits.
+
+Sessions are grouped by the --by label keys, or by requiredLabels of the
+global config. A session runs from its creation until now while it is
+active, or until its last status change once it is stopped. Sessions
+deleted without --keep-config are no longer counted.
+
+Examples:
+  kubectl kodama report
+  kubectl kodama report --by team,cost-center --since 2025-03-01 --until 2025-04-01
+  kubectl kodama report --format csv > chargeback.csv`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "csv" {
+				return fmt.Errorf("invalid format: %s (must be table or csv)", format)
+			}
+			sinceTime, err := parseReportTime(since)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			untilTime, err := parseReportTime(until)
+			if err != nil {
+				return fmt.Errorf("invalid --until: %w", err)
+			}
+
+			namespace, _ := cmd.Flags().GetString("namespace")
+			report, err := sessionService.UsageReport(service.UsageReportOptions{
+				By:        by,
+				Namespace: namespace,
+				Since:     sinceTime,
+				Until:     untilTime,
+			})
+			if err != nil {
+				return err
+			}
+
+			if format == "csv" {
+				return writeUsageCSV(os.Stdout, report)
+			}
+			return writeUsageTable(os.Stdout, report)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&by, "by", []string{}, "Label keys to group by (default: requiredLabels of the global config)")
+	cmd.Flags().StringVar(&format, "format", "table", "Output format: table, csv")
+	cmd.Flags().StringVar(&since, "since", "", "Only count runtime from this date (YYYY-MM-DD or RFC 3339)")
+	cmd.Flags().StringVar(&until, "until", "", "Only count runtime before this date (YYYY-MM-DD or RFC 3339, default: now)")
+
+	return cmd
+}
+
+// parseReportTime parses a local date or an RFC 3339 timestamp; "" is the zero time
+func parseReportTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// writeUsageCSV writes one row per label combination, for spreadsheets and billing tools
+func writeUsageCSV(w io.Writer, report *service.UsageReport) error {
+	cw := csv.NewWriter(w)
+	header := append(append([]string{}, report.By...), "sessions", "hours", "cpu_hours", "memory_gib_hours")
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range report.Rows {
+		record := append(append([]string{}, row.Labels...),
+			strconv.Itoa(row.Sessions),
+			strconv.FormatFloat(row.Hours, 'f', 2, 64),
+			strconv.FormatFloat(row.CPUHours, 'f', 2, 64),
+			strconv.FormatFloat(row.MemoryGiBHours, 'f', 2, 64),
+		)
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeUsageTable prints the report with a column per label key
+func writeUsageTable(w io.Writer, report *service.UsageReport) error {
+	if len(report.Rows) == 0 {
+		_, err := fmt.Fprintln(w, "No session runtime in this period")
+		return err
+	}
+
+	columns := make([]table.Column, 0, len(report.By)+4)
+	for _, key := range report.By {
+		columns = append(columns, table.Column{Header: strings.ToUpper(key)})
+	}
+	columns = append(columns,
+		table.Column{Header: "SESSIONS"},
+		table.Column{Header: "HOURS"},
+		table.Column{Header: "CPU-HOURS"},
+		table.Column{Header: "MEMORY-GIB-HOURS"},
+	)
+
+	t := table.New(columns...)
+	for _, row := range report.Rows {
+		cells := make([]string, 0, len(columns))
+		for _, value := range row.Labels {
+			cells = append(cells, cmp.Or(value, "-"))
+		}
+		cells = append(cells,
+			strconv.Itoa(row.Sessions),
+			fmt.Sprintf("%.2f", row.Hours),
+			fmt.Sprintf("%.2f", row.CPUHours),
+			fmt.Sprintf("%.2f", row.MemoryGiBHours),
+		)
+		t.AddRow(cells...)
+	}
+	return t.Render(w, table.Options{})
+}
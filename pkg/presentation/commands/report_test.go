@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+)
+
+func TestWriteUsageCSV(t *testing.T) {
+	report := &service.UsageReport{
+		By: []string{"team", "cost-center"},
+		Rows: []service.UsageRow{
+			{Labels: []string{"", ""}, Sessions: 1, Hours: 2},
+			{Labels: []string{"payments", "cc-1"}, Sessions: 2, Hours: 13, CPUHours: 21.5, MemoryGiBHours: 43},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := writeUsageCSV(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `team,cost-center,sessions,hours,cpu_hours,memory_gib_hours
+,,1,2.00,0.00,0.00
+payments,cc-1,2,13.00,21.50,43.00
+`
+	if buf.String() != want {
+		t.Errorf("writeUsageCSV() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestParseReportTime(t *testing.T) {
+	if got, err := parseReportTime(""); err != nil || !got.IsZero() {
+		t.Errorf("parseReportTime(\"\") = %v, %v", got, err)
+	}
+	if got, err := parseReportTime("2025-03-01"); err != nil || got.Day() != 1 || got.Month() != 3 {
+		t.Errorf("parseReportTime(date) = %v, %v", got, err)
+	}
+	if _, err := parseReportTime("2025-03-01T10:00:00Z"); err != nil {
+		t.Errorf("parseReportTime(RFC 3339) error = %v", err)
+	}
+	if _, err := parseReportTime("March"); err == nil {
+		t.Error("parseReportTime(\"March\") expected an error")
+	}
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewResizeCommand creates a new resize command
+func NewResizeCommand() *cobra.Command {
+	var (
+		cpu      string
+		memory   string
+		recreate bool
+		yes      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "resize <name>",
+		Short: "Change the CPU and memory of a running session",
+		Long: `Change the CPU and memory limits of a running session.
+
+The pod is resized in place when the cluster supports it (Kubernetes 1.33+, or
+earlier versions with the InPlacePodVerticalScaling feature gate), so nothing
+is restarted. Otherwise kodama explains what a recreate keeps and loses and
+asks before deleting the pod and starting a new one with the new limits.
+Persistent volumes, secrets and the session branch are preserved.
+
+Examples:
+  kubectl kodama resize my-work --cpu 4 --memory 8Gi
+  kubectl kodama resize my-work --memory 16Gi --yes
+  kubectl kodama resize my-work --cpu 8 --recreate`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			opts := usecase.ResizeSessionOptions{
+				Name:           args[0],
+				KubeconfigPath: kubeconfigPath,
+				CPU:            cpu,
+				Memory:         memory,
+				Recreate:       recreate,
+			}
+
+			err := usecase.ResizeSession(cmd.Context(), opts)
+			if !errors.Is(err, usecase.ErrResizeNeedsRecreate) {
+				return err
+			}
+
+			if !yes {
+				fmt.Printf("\nRecreate session '%s' with the new resources? [y/N]: ", args[0])
+				reader := bufio.NewReader(os.Stdin)
+				response, readErr := reader.ReadString('\n')
+				if readErr != nil {
+					return fmt.Errorf("failed to read confirmation: %w", readErr)
+				}
+
+				response = strings.TrimSpace(strings.ToLower(response))
+				if response != "y" && response != "yes" {
+					fmt.Println("Canceled")
+					return nil
+				}
+			}
+
+			opts.Recreate = true
+			return usecase.ResizeSession(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&cpu, "cpu", "", "New CPU limit (e.g., '4', '2500m')")
+	cmd.Flags().StringVar(&memory, "memory", "", "New memory limit (e.g., '8Gi')")
+	cmd.Flags().BoolVar(&recreate, "recreate", false, "Recreate the pod without trying an in-place resize")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Recreate without asking if in-place resize is not possible")
+
+	return cmd
+}
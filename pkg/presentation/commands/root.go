@@ -1,13 +1,12 @@
 package commands
 
 import (
-	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 
-	"github.com/illumination-k/kodama/internal/version"
+	"github.com/illumination-k/kodama/internal/plugin"
 	"github.com/illumination-k/kodama/pkg/application"
-	"github.com/illumination-k/kodama/pkg/commands"
 )
 
 // NewRootCommand creates the root command for kubectl-kodama with dependency injection
@@ -17,33 +16,115 @@ func NewRootCommand(app *application.App) *cobra.Command {
 		Short: "Manage Claude Code sessions in Kubernetes",
 		Long: `kubectl-kodama is a kubectl plugin for managing Claude Code development sessions.
 It provides a simple interface to start, stop, and manage containerized development
-environments in your Kubernetes cluster.`,
+environments in your Kubernetes cluster.
+
+Exit codes:
+  0  Success
+  1  Unclassified failure
+  2  Configuration error (invalid flags or config, unknown session)
+  3  Cluster error (Kubernetes API, pod, clone, or quota failure)
+  4  Coding agent failure (start/dev with --fail-on-agent-error)
+  5  File sync failure`,
 		SilenceUsage:  true,
 		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Check flags before cobra does, so that missing or conflicting
+			// flags exit with ExitConfigError like invalid ones
+			if err := cmd.ValidateRequiredFlags(); err != nil {
+				return &usageError{command: cmd.CommandPath(), err: err}
+			}
+			if err := cmd.ValidateFlagGroups(); err != nil {
+				return &usageError{command: cmd.CommandPath(), err: err}
+			}
+			warnKubectlProblems(cmd, cmd.ErrOrStderr())
+			return nil
+		},
 	}
+	cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return &usageError{command: cmd.CommandPath(), err: err}
+	})
 
 	// Global flags
 	cmd.PersistentFlags().StringP("namespace", "n", "", "Kubernetes namespace")
 	cmd.PersistentFlags().String("kubeconfig", "", "Path to kubeconfig file")
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
 
 	// Add subcommands with dependency injection
-	cmd.AddCommand(commands.NewStartCommand())           // Keep using old start command for now
-	cmd.AddCommand(NewListCommand(app.SessionService))   // New refactored command
-	cmd.AddCommand(commands.NewAttachCommand())          // Keep using old attach command for now
-	cmd.AddCommand(NewDeleteCommand(app.SessionService)) // New refactored command
-	cmd.AddCommand(commands.NewDebugCommand())           // Debug command for manifest generation
-	cmd.AddCommand(commands.NewDevCommand())             // Keep using old dev command for now
-	cmd.AddCommand(newVersionCommand())
+	cmd.AddCommand(NewStartCommand())
+	cmd.AddCommand(NewListCommand(app.SessionService))
+	cmd.AddCommand(NewStatusCommand(app.SessionService))
+	cmd.AddCommand(NewUseCommand(app.SessionService))
+	cmd.AddCommand(NewAttachCommand())
+	cmd.AddCommand(NewDeleteCommand(app.SessionService))
+	cmd.AddCommand(NewDebugCommand())
+	cmd.AddCommand(NewDevCommand())
+	cmd.AddCommand(NewBatchCommand())
+	cmd.AddCommand(NewSyncCommand())
+	cmd.AddCommand(NewWatchCommand())
+	cmd.AddCommand(NewResizeCommand())
+	cmd.AddCommand(NewExecCommand())
+	cmd.AddCommand(NewTestCommand())
+	cmd.AddCommand(NewSSHCommand())
+	cmd.AddCommand(NewShareCommand())
+	cmd.AddCommand(NewLogsCommand())
+	cmd.AddCommand(NewEventsCommand(app.SessionService))
+	cmd.AddCommand(NewSnapshotCommand())
+	cmd.AddCommand(NewRestoreCommand())
+	cmd.AddCommand(NewRecordingsCommand())
+	cmd.AddCommand(NewApplyChangesCommand())
+	cmd.AddCommand(NewRunCommand())
+	cmd.AddCommand(NewEnvCommand(app.SessionService))
+	cmd.AddCommand(NewInstallReaperCommand(app.SessionService))
+	cmd.AddCommand(NewGCCommand(app.SessionService))
+	cmd.AddCommand(NewCacheCommand(app.SessionService))
+	cmd.AddCommand(NewSharedCommand(app.SessionService))
+	cmd.AddCommand(NewPrepullCommand(app.SessionService))
+	cmd.AddCommand(NewQuotaCommand(app.SessionService))
+	cmd.AddCommand(NewReportCommand(app.SessionService))
+	cmd.AddCommand(NewUICommand(app.SessionService))
+	cmd.AddCommand(NewServeCommand(app.SessionService))
+	cmd.AddCommand(NewStoreCommand(app.SessionService))
+	cmd.AddCommand(NewConfigCommand(app.SessionService))
+	cmd.AddCommand(NewKubeconfigCommand())
+	cmd.AddCommand(NewCredentialCommand())
+	cmd.AddCommand(NewAuthCommand(app.SessionService))
+	cmd.AddCommand(NewVersionCommand())
+	cmd.AddCommand(NewKrewManifestCommand())
+	cmd.AddCommand(NewInstallKrewCommand())
+
+	wrapArgsErrors(cmd)
+
+	// Show usage as users invoke the binary: "kubectl kodama" or standalone "kodama"
+	name := plugin.CommandName()
+	cmd.Annotations = map[string]string{cobra.CommandDisplayNameAnnotation: name}
+	if !plugin.IsKubectlPlugin() {
+		localizeHelp(cmd, name)
+	}
 
 	return cmd
 }
 
-func newVersionCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "version",
-		Short: "Print version information",
-		Run: func(cmd *cobra.Command, args []string) {
-			fmt.Printf("kubectl-kodama version %s\n", version.Version)
-		},
+// localizeHelp rewrites "kubectl kodama" in help text and examples to name
+func localizeHelp(cmd *cobra.Command, name string) {
+	cmd.Long = strings.ReplaceAll(cmd.Long, "kubectl kodama", name)
+	cmd.Example = strings.ReplaceAll(cmd.Example, "kubectl kodama", name)
+	for _, sub := range cmd.Commands() {
+		localizeHelp(sub, name)
+	}
+}
+
+// wrapArgsErrors makes the argument errors of cmd and its subcommands usage
+// errors, which exit with ExitConfigError
+func wrapArgsErrors(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return &usageError{command: cmd.CommandPath(), err: err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		wrapArgsErrors(sub)
 	}
 }
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/application"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestNewRootCommand_RegistersSessionCommands(t *testing.T) {
+	root := NewRootCommand(&application.App{})
+
+	seen := map[string]bool{}
+	for _, sub := range root.Commands() {
+		assert.False(t, seen[sub.Name()], "command %q registered twice", sub.Name())
+		seen[sub.Name()] = true
+	}
+	for _, name := range []string{"start", "dev", "attach", "list", "delete", "sync", "exec", "run", "batch"} {
+		assert.True(t, seen[name], "command %q not registered", name)
+	}
+}
+
+func TestNewRootCommand_FlagCompletion(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	root := NewRootCommand(&application.App{})
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{cobra.ShellCompRequestCmd, "start", "--runtime", "do"})
+	require.NoError(t, root.Execute())
+
+	assert.Equal(t, []string{"kubernetes", "docker", "podman", ":4"}, strings.Fields(out.String()))
+
+	out.Reset()
+	root.SetArgs([]string{cobra.ShellCompRequestCmd, "prepull", "--image", "ghcr.io/illumination-k/"})
+	require.NoError(t, root.Execute())
+	assert.Contains(t, out.String(), "ghcr.io/illumination-k/kodama:latest")
+}
+
+func TestNewApp_InvalidClaudeAuthOnlyFailsAgentRuns(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("KUBECONFIG", "")
+	t.Setenv(kubernetes.EnvServer, "https://k8s.example.com")
+	t.Setenv(kubernetes.EnvToken, "secret")
+	require.NoError(t, os.MkdirAll(filepath.Join(home, ".kodama"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(home, ".kodama", "config.yaml"),
+		[]byte("claudeAuth:\n  authType: bogus\n"), 0o600))
+
+	app, err := application.NewApp("")
+	require.NoError(t, err)
+
+	for _, args := range [][]string{{"version", "--client"}, {"config", "migrate"}} {
+		root := NewRootCommand(app)
+		root.SetArgs(args)
+		assert.NoError(t, root.Execute(), "kubectl kodama %s", strings.Join(args, " "))
+	}
+
+	// The error surfaces once the coding agent runs
+	_, err = app.SessionService.GetAgentExecutor().TaskStart(context.Background(), "default", "kodama-demo", "fix the tests", "")
+	require.ErrorIs(t, err, config.ErrInvalidClaudeAuth)
+	assert.Equal(t, ExitConfigError, ExitCode(err))
+}
+
+func TestNewRootCommand_UsageErrorsExitWithConfigError(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	for _, args := range [][]string{
+		{"list", "--bogus"},
+		{"start"},
+		{"use", "a", "b"},
+		{"restore", "copy"},
+	} {
+		root := NewRootCommand(&application.App{})
+		root.SetArgs(args)
+		err := root.Execute()
+		require.Error(t, err, "kubectl kodama %s", strings.Join(args, " "))
+		assert.Equal(t, ExitConfigError, ExitCode(err), "kubectl kodama %s: %v", strings.Join(args, " "), err)
+
+		var buf bytes.Buffer
+		RenderError(&buf, err)
+		assert.Contains(t, buf.String(), "--help' for usage")
+	}
+}
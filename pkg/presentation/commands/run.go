@@ -0,0 +1,238 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// runTeardownTimeout bounds session deletion after 'kodama run'
+const runTeardownTimeout = 3 * time.Minute
+
+// NewRunCommand creates a new run command
+func NewRunCommand() *cobra.Command {
+	var (
+		repo          string
+		branch        string
+		prompt        string
+		promptFile    string
+		attachFiles   []string
+		planFile      string
+		image         string
+		cpu           string
+		memory        string
+		configFile    string
+		envFiles      []string
+		output        string
+		push          bool
+		pr            bool
+		prBase        string
+		prTitle       string
+		commitMessage string
+		timeout       time.Duration
+		keep          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run <name>",
+		Short: "Run a coding agent task end-to-end and collect its changes",
+		Long: `Run a coding agent task headlessly, for CI pipelines.
+
+Steps:
+  1. Start a session that clones --repo
+  2. Run the coding agent with the prompt, or each step of the --plan,
+     and wait for it to finish
+  3. Write the agent's changes as a patch (--output, default: <name>.patch)
+  4. Optionally commit and push them to the session branch (--push)
+     and open a pull request with the GitHub CLI (--pr)
+  5. Delete the session, even when a step fails (unless --keep)
+
+Pushing uses the GH_TOKEN the repository was cloned with, so pass it to the
+session (e.g. with --env-file). Under GitHub Actions
+each step is a collapsible log group, and patch, changed, pushed, branch and
+pr-url are written to $GITHUB_OUTPUT.
+
+Exits with code 4 if the agent fails (see 'kubectl kodama --help').
+
+Examples:
+  kubectl kodama run fix-lint --repo https://github.com/org/repo --prompt "Fix all lint warnings"
+  kubectl kodama run deps --repo https://github.com/org/repo --prompt-file task.md --env-file .env.ci --pr
+  kubectl kodama run triage --repo https://github.com/org/repo -p "Investigate #123" -o triage.patch --keep
+  kubectl kodama run upgrade --repo https://github.com/org/repo --plan upgrade-plan.yaml --pr`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			name := args[0]
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+			namespace, _ := cmd.Flags().GetString("namespace")
+
+			ctx := cmd.Context()
+			if timeout > 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, timeout)
+				defer cancel()
+			}
+
+			gha := newActionsLog()
+			defer func() {
+				gha.endGroup()
+				if err != nil {
+					gha.error(err)
+				}
+			}()
+
+			result, err := usecase.RunPipeline(ctx, usecase.RunOptions{
+				Start: usecase.StartSessionOptions{
+					Name:           name,
+					Repo:           repo,
+					Branch:         branch,
+					Prompt:         prompt,
+					PromptFile:     promptFile,
+					PlanFile:       planFile,
+					AttachFiles:    attachFiles,
+					Namespace:      namespace,
+					KubeconfigPath: kubeconfigPath,
+					Image:          image,
+					CPU:            cpu,
+					Memory:         memory,
+					ConfigFile:     configFile,
+					EnvFiles:       envFiles,
+					TtydEnabled:    true, // Headless: no web terminal
+					TtydEnabledVal: false,
+				},
+				Output:        output,
+				Push:          push,
+				PR:            pr,
+				PRBase:        prBase,
+				PRTitle:       prTitle,
+				CommitMessage: commitMessage,
+				Step:          gha.group,
+			})
+
+			if result != nil && result.Session != nil {
+				if keep {
+					fmt.Printf("\n📌 Session '%s' kept (--keep). Delete it with:\n  kubectl kodama delete %s\n", name, name)
+				} else {
+					gha.group("Teardown")
+					teardownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), runTeardownTimeout)
+					defer cancel()
+					if deleteErr := usecase.DeleteSession(teardownCtx, usecase.DeleteSessionOptions{Name: name, KubeconfigPath: kubeconfigPath}); deleteErr != nil && !errors.Is(deleteErr, config.ErrSessionNotFound) {
+						fmt.Printf("⚠️  Warning: Failed to delete session: %v\n", deleteErr)
+					}
+				}
+			}
+			if err != nil {
+				return err
+			}
+
+			gha.output("patch", result.PatchPath)
+			gha.output("changed", fmt.Sprint(result.Changed))
+			gha.output("pushed", fmt.Sprint(result.Pushed))
+			gha.output("branch", result.Session.Branch)
+			gha.output("pr-url", result.PRURL)
+
+			fmt.Println()
+			switch {
+			case result.PRURL != "":
+				fmt.Printf("✨ Pull request opened: %s\n", result.PRURL)
+			case result.Pushed:
+				fmt.Printf("✨ Changes pushed to %s\n", result.Session.Branch)
+			case result.Changed:
+				fmt.Printf("✨ Changes saved to %s\n", result.PatchPath)
+			default:
+				fmt.Println("✨ Agent finished without changes")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&repo, "repo", "", "Git repository URL to clone")
+	cmd.Flags().StringVar(&branch, "branch", "", "Branch for the agent's changes (default: kodama/<name>)")
+	cmd.Flags().StringVarP(&prompt, "prompt", "p", "", "Prompt for coding agent")
+	cmd.Flags().StringVar(&promptFile, "prompt-file", "", "File containing prompt for coding agent")
+	cmd.Flags().StringVar(&planFile, "plan", "", "YAML task plan whose steps the agent runs one after another (instead of --prompt)")
+	cmd.Flags().StringArrayVar(&attachFiles, "attach-file", nil, "Local file to upload to /workspace/.kodama/context and reference in the prompt (can be specified multiple times)")
+	cmd.Flags().StringVar(&image, "image", "", "Container image to use (overrides global default)")
+	cmd.Flags().StringVar(&cpu, "cpu", "", "CPU limit (e.g., '1', '2')")
+	cmd.Flags().StringVar(&memory, "memory", "", "Memory limit (e.g., '2Gi', '4Gi')")
+	cmd.Flags().StringVar(&configFile, "config", "", "Path to session template config file")
+	cmd.Flags().StringSliceVar(&envFiles, "env-file", []string{}, "Dotenv file(s) to load (can be specified multiple times)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Patch file path (default: <name>.patch)")
+	cmd.Flags().BoolVar(&push, "push", false, "Commit and push the changes to the session branch")
+	cmd.Flags().BoolVar(&pr, "pr", false, "Open a pull request with the GitHub CLI (implies --push)")
+	cmd.Flags().StringVar(&prBase, "base", "", "Pull request base branch (default: repository default branch)")
+	cmd.Flags().StringVar(&prTitle, "title", "", "Pull request title (default: first line of the prompt)")
+	cmd.Flags().StringVar(&commitMessage, "commit-message", usecase.DefaultRunCommitMessage, "Commit message for --push")
+	cmd.Flags().DurationVar(&timeout, "timeout", time.Hour, "Maximum time for the whole run (0 = no limit)")
+	cmd.Flags().BoolVar(&keep, "keep", false, "Keep the session instead of deleting it at the end")
+	_ = cmd.MarkFlagRequired("repo")
+	_ = cmd.RegisterFlagCompletionFunc("image", completeImages)
+
+	return cmd
+}
+
+// actionsLog emits GitHub Actions workflow commands when running under Actions
+// and plain step headers otherwise
+type actionsLog struct {
+	enabled    bool
+	outputPath string
+	inGroup    bool
+}
+
+func newActionsLog() *actionsLog {
+	return &actionsLog{
+		enabled:    os.Getenv("GITHUB_ACTIONS") == "true",
+		outputPath: os.Getenv("GITHUB_OUTPUT"),
+	}
+}
+
+// group starts a collapsible log group, closing the previous one
+func (a *actionsLog) group(title string) {
+	if !a.enabled {
+		fmt.Printf("\n▶ %s\n", title)
+		return
+	}
+	a.endGroup()
+	fmt.Printf("::group::%s\n", title)
+	a.inGroup = true
+}
+
+func (a *actionsLog) endGroup() {
+	if a.inGroup {
+		fmt.Println("::endgroup::")
+		a.inGroup = false
+	}
+}
+
+// error annotates the workflow run with err
+func (a *actionsLog) error(err error) {
+	if a.enabled {
+		fmt.Printf("::error title=kodama run::%s\n", escapeWorkflowData(err.Error()))
+	}
+}
+
+// output appends a step output to $GITHUB_OUTPUT
+func (a *actionsLog) output(name, value string) {
+	if !a.enabled || a.outputPath == "" {
+		return
+	}
+	f, err := os.OpenFile(a.outputPath, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600) // #nosec G304 -- path set by the Actions runner
+	if err != nil {
+		fmt.Printf("⚠️  Warning: Failed to write GitHub output %s: %v\n", name, err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	_, _ = fmt.Fprintf(f, "%s=%s\n", name, value)
+}
+
+// escapeWorkflowData escapes a workflow command message per the Actions spec
+func escapeWorkflowData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
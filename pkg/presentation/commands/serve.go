@@ -0,0 +1,122 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/presentation/server"
+	"github.com/illumination-k/kodama/pkg/presentation/slack"
+)
+
+// NewServeCommand creates the serve command
+func NewServeCommand(sessionService *service.SessionService) *cobra.Command {
+	var (
+		addr               string
+		token              string
+		slackSigningSecret string
+		slackBotToken      string
+		templatesDir       string
+		slackTemplatesDir  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve a REST/JSON API for managing sessions",
+		Long: `Run an HTTP server exposing kodama sessions as a REST/JSON API, so other
+tools can create sessions, run agent tasks and follow logs.
+
+Endpoints:
+  GET    /healthz
+  GET    /metrics                         Prometheus metrics
+  GET    /api/v1/sessions
+  POST   /api/v1/sessions                 {"name", "repo", "branch", "prompt", ...}
+  GET    /api/v1/sessions/{name}
+  DELETE /api/v1/sessions/{name}
+  POST   /api/v1/sessions/{name}/agent    {"prompt"}
+  GET    /api/v1/sessions/{name}/logs     ?follow=true&tail=100 (server-sent events)
+
+Session creation is asynchronous: POST returns 202 and the session status is
+reported by GET /api/v1/sessions/{name}. A "config" in the request names a
+template in --templates-dir, read as <config>.yaml.
+
+/api requests must send "Authorization: Bearer <token>" with the token from
+--token or KODAMA_API_TOKEN. Without one, a random token is generated and
+printed at startup. Request bodies must be sent as application/json, and
+requests must be addressed to a loopback name or the --addr host.
+
+POST /api/v1/sessions/{name}/agent waits for the task to finish and returns
+200 with the reviewed result.
+
+Slack integration:
+  When a signing secret and bot token are set (flags or SLACK_SIGNING_SECRET
+  and SLACK_BOT_TOKEN), POST /slack/commands handles the /kodama slash
+  command. Progress and agent completion summaries are posted in a thread.
+  Templates for '/kodama start <name> <template>' are read from
+  --slack-templates-dir (default: --templates-dir) as <template>.yaml.
+
+Examples:
+  kubectl kodama serve
+  KODAMA_API_TOKEN=s3cret kubectl kodama serve --addr 0.0.0.0:8080
+  kubectl kodama serve --templates-dir ~/.kodama/templates`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			if token == "" {
+				token = os.Getenv("KODAMA_API_TOKEN")
+			}
+			if token == "" {
+				generated, err := server.GenerateToken()
+				if err != nil {
+					return err
+				}
+				token = generated
+				fmt.Printf("🔑 Generated API token (set --token or KODAMA_API_TOKEN to choose one): %s\n", token)
+			}
+
+			srv := server.New(sessionService, server.Options{
+				Addr:           addr,
+				Token:          token,
+				KubeconfigPath: kubeconfigPath,
+				TemplatesDir:   templatesDir,
+			})
+
+			slackSigningSecret = config.CoalesceString(slackSigningSecret, os.Getenv("SLACK_SIGNING_SECRET"))
+			slackBotToken = config.CoalesceString(slackBotToken, os.Getenv("SLACK_BOT_TOKEN"))
+			switch {
+			case slackSigningSecret != "" && slackBotToken != "":
+				bot := slack.NewBot(sessionService, slack.NewClient(slackBotToken), slack.Options{
+					SigningSecret:  slackSigningSecret,
+					TemplatesDir:   config.CoalesceString(slackTemplatesDir, templatesDir),
+					KubeconfigPath: kubeconfigPath,
+				})
+				defer bot.Close()
+				srv.Handle("POST /slack/commands", bot)
+				fmt.Println("💬 Slack slash commands enabled at /slack/commands")
+			case slackSigningSecret != "" || slackBotToken != "":
+				return fmt.Errorf("slack integration requires both a signing secret and a bot token")
+			}
+
+			fmt.Printf("🌐 Serving kodama API on http://%s\n", addr)
+			if err := srv.ListenAndServe(cmd.Context()); err != nil {
+				return fmt.Errorf("server error: %w", err)
+			}
+
+			fmt.Println("✓ Server stopped")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8080", "Address to listen on")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token required for API requests (default: $KODAMA_API_TOKEN)")
+	cmd.Flags().StringVar(&slackSigningSecret, "slack-signing-secret", "", "Slack app signing secret (default: $SLACK_SIGNING_SECRET)")
+	cmd.Flags().StringVar(&slackBotToken, "slack-bot-token", "", "Slack bot token for posting thread updates (default: $SLACK_BOT_TOKEN)")
+	cmd.Flags().StringVar(&templatesDir, "templates-dir", "", "Directory of session templates (<name>.yaml) that API requests can name as \"config\"")
+	cmd.Flags().StringVar(&slackTemplatesDir, "slack-templates-dir", "", "Directory of session templates available to '/kodama start' (default: --templates-dir)")
+
+	return cmd
+}
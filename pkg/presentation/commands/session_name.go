@@ -0,0 +1,14 @@
+package commands
+
+import (
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// sessionNameArg returns the session named by the first argument, or when no
+// name was given, the session resolved from the working directory or 'kodama use'
+func sessionNameArg(args []string) (string, error) {
+	if len(args) > 0 {
+		return args[0], nil
+	}
+	return usecase.ResolveSessionName("")
+}
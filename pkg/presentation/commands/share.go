@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewShareCommand creates a new share command
+func NewShareCommand() *cobra.Command {
+	var (
+		expires      time.Duration
+		ingressHost  string
+		ingressClass string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "share <name>",
+		Short: "Share a read-only view of a session's terminal",
+		Long: `Start a second, read-only web terminal in the session that mirrors its live
+terminal, so a reviewer can watch the agent working without being able to type.
+
+The view follows the session's terminal recording, so the session must have been
+started with --record. Access is protected with a random token (basic auth user
+"observer"). Running share again replaces the token.
+
+Examples:
+  kubectl kodama share my-work                                  # Access via port-forward
+  kubectl kodama share my-work --expires 2h
+  kubectl kodama share my-work --ingress-host my-work.kodama.example.com
+  kubectl kodama share revoke my-work`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			return usecase.ShareSession(cmd.Context(), usecase.ShareSessionOptions{
+				Name:           args[0],
+				KubeconfigPath: kubeconfigPath,
+				Expires:        expires,
+				IngressHost:    ingressHost,
+				IngressClass:   ingressClass,
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&expires, "expires", time.Hour, "Stop sharing after this duration (0 = until revoked)")
+	cmd.Flags().StringVar(&ingressHost, "ingress-host", "", "Expose the view through an Ingress for this host")
+	cmd.Flags().StringVar(&ingressClass, "ingress-class", "", "IngressClass for --ingress-host (default: cluster default)")
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "revoke <name>",
+		Short: "Stop sharing a session and delete its ingress",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+			return usecase.RevokeShare(cmd.Context(), args[0], kubeconfigPath)
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+)
+
+// NewSharedCommand creates the shared command
+func NewSharedCommand(sessionService *service.SessionService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shared",
+		Short: "Manage the shared scratch volume of a namespace",
+		Long: `Manage the scratch volume shared by the sessions of a namespace.
+
+Sessions started with --shared (or 'shared.enabled: true' in ~/.kodama/config.yaml
+or a template) mount the same ReadWriteMany PVC at /shared, and $KODAMA_SHARED
+points at it. One session's agent can leave artifacts there, e.g. generated code,
+for another to consume. The PVC is created on first use and is never deleted
+with a session; clean it up with 'shared clean'.
+
+The volume is read through the pod of one of your running sessions that mounts it.`,
+	}
+
+	cmd.AddCommand(newSharedLsCommand(sessionService))
+	cmd.AddCommand(newSharedCleanCommand(sessionService))
+
+	return cmd
+}
+
+func newSharedLsCommand(sessionService *service.SessionService) *cobra.Command {
+	var opts service.SharedOptions
+
+	cmd := &cobra.Command{
+		Use:   "ls",
+		Short: "List the shared scratch volume",
+		Long: `List the top-level entries of the shared scratch volume, largest first,
+and how full the volume is. A volume that is nearly full is flagged, since a
+full volume fails the writes of every session that mounts it.
+
+Examples:
+  kubectl kodama shared ls
+  kubectl kodama shared ls -n team-a --pvc team-shared`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := resolveSharedOptions(cmd, sessionService, &opts); err != nil {
+				return err
+			}
+
+			listing, err := sessionService.ListShared(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			return printSharedListing(os.Stdout, listing)
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.PVC, "pvc", "", "Shared PVC name (default: config 'defaults.shared.pvc' or "+kubernetes.DefaultSharedPVC+")")
+
+	return cmd
+}
+
+func newSharedCleanCommand(sessionService *service.SessionService) *cobra.Command {
+	var opts service.SharedCleanOptions
+
+	cmd := &cobra.Command{
+		Use:   "clean [ENTRY...]",
+		Short: "Remove entries from the shared scratch volume",
+		Long: `Remove top-level entries from the shared scratch volume: the named ones,
+those not modified for --older-than, or all of them with --all. Other sessions
+may still read what is removed, so nothing is removed without one of these.
+
+Examples:
+  kubectl kodama shared clean codegen-output
+  kubectl kodama shared clean --older-than 168h --dry-run
+  kubectl kodama shared clean --all`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := resolveSharedOptions(cmd, sessionService, &opts.SharedOptions); err != nil {
+				return err
+			}
+			opts.Names = args
+
+			removed, err := sessionService.CleanShared(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			verb := "Removed"
+			if opts.DryRun {
+				verb = "Would remove"
+			}
+			var total int64
+			for _, entry := range removed {
+				fmt.Printf("🗑️  %s %s (%s)\n", verb, entry.Name, kubernetes.FormatKiB(entry.SizeKiB))
+				total += entry.SizeKiB
+			}
+			if len(removed) == 0 {
+				fmt.Println("✓ Nothing to clean up")
+			} else if !opts.DryRun {
+				fmt.Printf("✓ Freed %s on shared volume %s\n", kubernetes.FormatKiB(total), opts.PVC)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.PVC, "pvc", "", "Shared PVC name (default: config 'defaults.shared.pvc' or "+kubernetes.DefaultSharedPVC+")")
+	cmd.Flags().DurationVar(&opts.OlderThan, "older-than", 0, "Remove entries not modified for this long (e.g. 168h)")
+	cmd.Flags().BoolVar(&opts.All, "all", false, "Remove every entry")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "Only list what would be removed")
+
+	return cmd
+}
+
+// resolveSharedOptions fills the namespace and PVC of opts from the flags and config
+func resolveSharedOptions(cmd *cobra.Command, sessionService *service.SessionService, opts *service.SharedOptions) error {
+	namespaceFlag, _ := cmd.Flags().GetString("namespace")
+	namespace, err := sessionService.ResolveNamespace(namespaceFlag)
+	if err != nil {
+		return err
+	}
+	opts.Namespace = namespace
+
+	opts.PVC, err = sessionService.ResolveSharedPVC(opts.PVC)
+	return err
+}
+
+// printSharedListing prints the volume usage and a table of its entries
+func printSharedListing(w io.Writer, listing *service.SharedListing) error {
+	switch {
+	case listing.Usage == nil:
+		fmt.Fprintf(w, "Shared volume %s (via session %s): usage unknown: %v\n", listing.PVC, listing.Session, listing.UsageErr)
+	case listing.Usage.NearlyFull():
+		fmt.Fprintf(w, "Shared volume %s (via session %s): %s\n", listing.PVC, listing.Session, listing.Usage)
+		fmt.Fprintf(w, "⚠️  Warning: The volume is nearly full; free space with 'kubectl kodama shared clean'\n")
+	default:
+		fmt.Fprintf(w, "Shared volume %s (via session %s): %s\n", listing.PVC, listing.Session, listing.Usage)
+	}
+
+	if len(listing.Entries) == 0 {
+		fmt.Fprintln(w, "\nThe volume is empty")
+		return nil
+	}
+	fmt.Fprintln(w)
+
+	t := table.New(
+		table.Column{Header: "NAME"},
+		table.Column{Header: "SIZE"},
+		table.Column{Header: "MODIFIED"},
+	)
+	for _, entry := range listing.Entries {
+		t.AddRow(entry.Name, kubernetes.FormatKiB(entry.SizeKiB), entry.Modified.Local().Format(time.DateTime))
+	}
+	return t.Render(w, table.Options{Color: table.ColorEnabled(w)})
+}
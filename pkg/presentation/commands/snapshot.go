@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewSnapshotCommand creates a new snapshot command
+func NewSnapshotCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "snapshot <name>",
+		Short: "Archive a session workspace",
+		Long: `Archive a session's /workspace as a .tar.gz, including its git history.
+
+The archive is written to --output, which may be a local path or an s3:// or
+gs:// URL (uploaded with the aws or gcloud CLI). Without --output it is named
+<name>-<timestamp>.tar.gz and written under 'snapshot.store' from
+~/.kodama/config.yaml, or to the current directory.
+
+Sync exclude patterns and 'snapshot.exclude' are left out of the archive.
+
+Examples:
+  kubectl kodama snapshot my-work
+  kubectl kodama snapshot my-work -o experiments/attempt-1.tar.gz
+  kubectl kodama snapshot my-work -o s3://team-bucket/kodama/attempt-1.tar.gz`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			location, err := usecase.SnapshotSession(cmd.Context(), usecase.SnapshotSessionOptions{
+				Name:           args[0],
+				KubeconfigPath: kubeconfigPath,
+				Output:         output,
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\nRestore it into a new session with:\n  kubectl kodama restore <new-name> --from %s\n", location)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Local path or s3:// / gs:// URL for the archive")
+
+	return cmd
+}
+
+// NewRestoreCommand creates a new restore command
+func NewRestoreCommand() *cobra.Command {
+	var (
+		from       string
+		image      string
+		cpu        string
+		memory     string
+		configFile string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "restore <name>",
+		Short: "Start a new session from a workspace snapshot",
+		Long: `Start a new session with an empty workspace and extract a snapshot
+created by 'kubectl kodama snapshot' into it.
+
+Examples:
+  kubectl kodama restore attempt-1-review --from my-work-20250101-120000.tar.gz
+  kubectl kodama restore replay --from s3://team-bucket/kodama/attempt-1.tar.gz --cpu 4`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+			namespace, _ := cmd.Flags().GetString("namespace")
+
+			session, err := usecase.RestoreSession(cmd.Context(), usecase.RestoreSessionOptions{
+				From: from,
+				Start: usecase.StartSessionOptions{
+					Name:           args[0],
+					Namespace:      namespace,
+					KubeconfigPath: kubeconfigPath,
+					Image:          image,
+					CPU:            cpu,
+					Memory:         memory,
+					ConfigFile:     configFile,
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("\n✨ Session '%s' restored\n", session.Name)
+			fmt.Printf("\nAttach with:\n  kubectl kodama attach %s\n", session.Name)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Snapshot to restore (local path or s3:// / gs:// URL)")
+	cmd.Flags().StringVar(&image, "image", "", "Container image (default: from config)")
+	cmd.Flags().StringVar(&cpu, "cpu", "", "CPU limit (e.g., '1', '2')")
+	cmd.Flags().StringVar(&memory, "memory", "", "Memory limit (e.g., '2Gi', '4Gi')")
+	cmd.Flags().StringVar(&configFile, "config", "", "Path to session template config file")
+	_ = cmd.MarkFlagRequired("from")
+	_ = cmd.RegisterFlagCompletionFunc("image", completeImages)
+
+	return cmd
+}
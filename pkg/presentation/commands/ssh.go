@@ -0,0 +1,42 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewSSHCommand creates a new ssh command
+func NewSSHCommand() *cobra.Command {
+	var localPort int
+
+	cmd := &cobra.Command{
+		Use:   "ssh <name>",
+		Short: "Expose a session over SSH for remote IDEs",
+		Long: `Start sshd in a running session and port-forward it to localhost.
+
+A key pair is generated for the session on first use and stored in
+~/.kodama/ssh/<name>/ together with an ssh_config entry for host kodama-<name>.
+JetBrains Gateway and VS Code Remote-SSH can use that entry to connect to the
+session as a remote development host. sshd is installed in the session image
+if it is missing.
+
+Examples:
+  kubectl kodama ssh my-work              # Forward localhost:2222
+  kubectl kodama ssh my-work --port 2022  # Custom local port`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			return usecase.SSHSession(cmd.Context(), usecase.SSHSessionOptions{
+				Name:           args[0],
+				KubeconfigPath: kubeconfigPath,
+				LocalPort:      localPort,
+			})
+		},
+	}
+
+	cmd.Flags().IntVar(&localPort, "port", 0, "Local port for port-forward (default: 2222)")
+
+	return cmd
+}
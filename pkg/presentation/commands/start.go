@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewStartCommand creates a new start command
+func NewStartCommand() *cobra.Command {
+	var flags startFlags
+
+	cmd := &cobra.Command{
+		Use:   "start <name>",
+		Short: "Start a new Claude Code session",
+		Long: `Start a new Claude Code session in Kubernetes.
+
+Creates a pod running claude-code and syncs files from your local machine.
+
+Examples:
+  kubectl kodama start my-work --sync ~/projects/myrepo
+  kubectl kodama start my-work --repo https://github.com/user/repo --branch main
+  kubectl kodama start my-work --namespace dev --cpu 2 --memory 4Gi
+  kubectl kodama start my-work --runtime docker --image ubuntu:24.04   # Local container, no cluster`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts, err := flags.options(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			session, err := usecase.StartSession(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+
+			// Print success message
+			fmt.Printf("\n✨ Session '%s' is ready!\n", session.Name)
+
+			// Local containers run neither ttyd nor code-server
+			local := session.IsLocalRuntime()
+			isTtydEnabled := session.Ttyd.Enabled != nil && *session.Ttyd.Enabled && !local
+			if isTtydEnabled {
+				fmt.Printf("\n🌐 Web-based terminal (ttyd) is enabled\n")
+				fmt.Printf("   The session will be accessible via browser\n")
+			}
+
+			fmt.Printf("\nNext steps:\n")
+			if isTtydEnabled {
+				fmt.Printf("  kubectl kodama attach %s           # Open in browser (ttyd)\n", session.Name)
+				fmt.Printf("  kubectl kodama attach %s --tty     # Use traditional TTY mode\n", session.Name)
+			} else {
+				fmt.Printf("  kubectl kodama attach %s           # Attach to session\n", session.Name)
+			}
+			fmt.Printf("  kubectl kodama list                # List all sessions\n")
+			fmt.Printf("  kubectl kodama delete %s           # Delete session\n", session.Name)
+
+			if session.Editor.CodeServer.IsEnabled() && !local {
+				port := config.CoalesceInt(session.Editor.CodeServer.Port, kubernetes.DefaultCodeServerPort)
+				fmt.Printf("\n💻 code-server is running. Open it with:\n")
+				fmt.Printf("  kubectl port-forward -n %s pod/%s %d:%d   # then http://localhost:%d\n", session.Namespace, session.PodName, port, port, port)
+			}
+
+			if session.Sync.Enabled {
+				fmt.Printf("\n📁 Files are syncing between %s and pod\n", session.Sync.LocalPath)
+				fmt.Println("   Tip: Use 'kubectl kodama attach --sync' for live sync during development")
+			} else if local && session.Sync.LocalPath != "" {
+				fmt.Printf("\n📁 %s is mounted at /workspace in the %s container\n", session.Sync.LocalPath, session.Runtime)
+			}
+
+			return nil
+		},
+	}
+
+	flags.register(cmd)
+
+	return cmd
+}
@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// startFlags holds the session flags shared by start and dev
+type startFlags struct {
+	repo            string
+	syncPath        string
+	syncInclude     []string
+	remotePath      string
+	localChanges    bool
+	shared          bool
+	namespace       string
+	cpu             string
+	memory          string
+	customResources []string
+	branch          string
+	prompt          string
+	promptFile      string
+	attachFiles     []string
+	planFile        string
+	failOnAgentErr  bool
+	saveAgentLog    bool
+	image           string
+	command         string
+	cloneDepth      int
+	singleBranch    bool
+	gitCloneArgs    string
+	cloneAttempts   int
+	gitMirror       string
+	configFile      string
+	ttydEnabled     bool
+	ttydPort        int
+	ttydOptions     string
+	ttydReadonly    bool
+	expires         time.Duration
+	record          bool
+	spotFriendly    bool
+	sealedSecrets   bool
+	restartPolicy   string
+	watchdog        bool
+	sandbox         bool
+	noEvict         bool
+	pdb             bool
+	runtime         string
+	envFiles        []string
+	envExclude      []string
+	secretFiles     []string
+	sanitizeName    bool
+	labels          []string
+	setValues       []string
+	overlay         string
+}
+
+// register adds the session flags to cmd
+func (f *startFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.repo, "repo", "", "Git repository URL to clone (combine with --sync only with --remote-path)")
+	cmd.Flags().StringVar(&f.syncPath, "sync", "", "Local path to sync (default: current directory without --repo)")
+	cmd.Flags().StringVar(&f.remotePath, "remote-path", "", "Directory inside /workspace to sync --sync into, e.g. /workspace/services/payments of the cloned --repo")
+	cmd.Flags().BoolVar(&f.localChanges, "local-changes", false, "Apply the uncommitted changes of the current directory's git repository to the --repo clone")
+	cmd.Flags().BoolVar(&f.shared, "shared", false, "Mount the namespace-wide shared scratch volume at /shared, created on first use (see shared ls)")
+	cmd.Flags().StringSliceVar(&f.syncInclude, "sync-include", nil, "Only sync these paths relative to the sync root, e.g. 'src/**' or go.mod (can be specified multiple times)")
+	cmd.Flags().StringVarP(&f.namespace, "namespace", "n", "", "Kubernetes namespace")
+	cmd.Flags().StringVar(&f.cpu, "cpu", "", "CPU limit (e.g., '1', '2')")
+	cmd.Flags().StringVar(&f.memory, "memory", "", "Memory limit (e.g., '2Gi', '4Gi')")
+	cmd.Flags().StringSliceVar(&f.customResources, "resource", []string{}, "Custom resource (can be specified multiple times, e.g., --resource nvidia.com/gpu=1 --resource amd.com/gpu=2)")
+	cmd.Flags().StringVar(&f.branch, "branch", "", "Git branch to clone (default: repository default branch)")
+	cmd.Flags().StringVarP(&f.prompt, "prompt", "p", "", "Prompt for coding agent")
+	cmd.Flags().StringVar(&f.promptFile, "prompt-file", "", "File containing prompt for coding agent")
+	cmd.Flags().StringVar(&f.planFile, "plan", "", "YAML task plan whose steps the agent runs one after another (instead of --prompt)")
+	cmd.Flags().StringArrayVar(&f.attachFiles, "attach-file", nil, "Local file to upload to /workspace/.kodama/context and reference in the prompt (can be specified multiple times)")
+	cmd.Flags().BoolVar(&f.failOnAgentErr, "fail-on-agent-error", false, "Exit with code 4 if the coding agent fails (the session is kept running)")
+	cmd.Flags().BoolVar(&f.saveAgentLog, "save-agent-log", false, "Copy the agent output log to ~/.kodama/sessions/<name>/artifacts")
+	cmd.Flags().StringVar(&f.image, "image", "", "Container image to use (overrides global default)")
+	cmd.Flags().StringVar(&f.command, "cmd", "", "Pod command override (space-separated, e.g., 'sh -c echo hello')")
+	cmd.Flags().IntVar(&f.cloneDepth, "clone-depth", 0, "Create a shallow clone with specified depth (0 = full clone)")
+	cmd.Flags().BoolVar(&f.singleBranch, "single-branch", false, "Clone only the specified branch (or default branch)")
+	cmd.Flags().StringVar(&f.gitCloneArgs, "git-clone-args", "", "Additional arguments to pass to git clone (advanced)")
+	cmd.Flags().IntVar(&f.cloneAttempts, "clone-attempts", 0, "Clone attempts per remote with exponential backoff (default 3)")
+	cmd.Flags().StringVar(&f.gitMirror, "git-mirror", "", "Alternate remote to clone from when the repository is unreachable")
+	cmd.Flags().StringVar(&f.configFile, "config", "", "Path to session template config file")
+	cmd.Flags().BoolVar(&f.ttydEnabled, "ttyd", true, "Enable ttyd (web-based terminal)")
+	cmd.Flags().IntVar(&f.ttydPort, "ttyd-port", 0, "Ttyd port (default: 7681)")
+	cmd.Flags().StringVar(&f.ttydOptions, "ttyd-options", "", "Additional ttyd options")
+	cmd.Flags().BoolVar(&f.ttydReadonly, "ttyd-readonly", false, "Enable read-only mode for ttyd (disables terminal input)")
+	cmd.Flags().DurationVar(&f.expires, "expires", 0, "Session lifetime (e.g., 24h); expired pods are deleted by the reaper (see install-reaper)")
+	cmd.Flags().BoolVar(&f.record, "record", false, "Record interactive terminals (ttyd and attach) to /workspace/.kodama/recordings")
+	cmd.Flags().BoolVar(&f.spotFriendly, "spot-friendly", false, "Schedule on spot/preemptible nodes, checkpoint the workspace and recreate the pod when preempted (with watch)")
+	cmd.Flags().BoolVar(&f.sandbox, "sandbox", false, "Mount the synced or cloned workspace read-only and give the agent a writable copy; review and apply its changes with apply-changes")
+	cmd.Flags().BoolVar(&f.noEvict, "no-evict", false, "Annotate the pod so the cluster autoscaler does not evict it to scale down its node")
+	cmd.Flags().BoolVar(&f.pdb, "pdb", false, "Create a PodDisruptionBudget that blocks evictions of the pod, including node drains")
+	cmd.Flags().StringVar(&f.restartPolicy, "restart-policy", "", "Pod restart policy: Never, OnFailure or Always (default: OnFailure with --cmd, Never otherwise)")
+	cmd.Flags().BoolVar(&f.watchdog, "watchdog", false, "Restart ttyd, the session command and sshd inside the pod when they die")
+	cmd.Flags().StringVar(&f.runtime, "runtime", config.RuntimeKubernetes, "Where the session runs: kubernetes, or docker/podman for a local container with the workspace bind-mounted")
+	cmd.Flags().StringSliceVar(&f.envFiles, "env-file", []string{}, "Dotenv file(s) to load (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&f.envExclude, "env-exclude", []string{}, "Environment variable names to exclude from injection (can be specified multiple times)")
+	cmd.Flags().StringSliceVar(&f.labels, "label", []string{}, "Session label for filtering with list --label (format: key=value, can be specified multiple times)")
+	cmd.Flags().StringArrayVar(&f.setValues, "set", []string{}, "Set a template value for {{ .Values.key }} expressions in the session template (format: key=value, can be specified multiple times)")
+	cmd.Flags().StringVar(&f.overlay, "overlay", "", "Session template overlay to apply over the template (e.g., staging)")
+	cmd.Flags().BoolVar(&f.sanitizeName, "sanitize-name", false, "Convert the session name into a valid Kubernetes name (lowercase, '-' for invalid characters, truncated)")
+	cmd.Flags().StringSliceVar(&f.secretFiles, "secret-file", []string{}, "Inject file as secret (format: source:destination, e.g., ~/.ssh/id_rsa:/root/.ssh/id_rsa, can be specified multiple times)")
+	cmd.Flags().BoolVar(&f.sealedSecrets, "sealed-secrets", false, "Apply --env-file and --secret-file secrets server-side and make the pod their owner, so they are deleted with it")
+
+	_ = cmd.RegisterFlagCompletionFunc("namespace", completeNamespaces)
+	_ = cmd.RegisterFlagCompletionFunc("image", completeImages)
+	_ = cmd.RegisterFlagCompletionFunc("runtime", completeRuntimes)
+}
+
+// options converts the parsed flags into StartSessionOptions
+// Merging with the template and global config is left to usecase.StartSession.
+func (f *startFlags) options(cmd *cobra.Command, name string) (usecase.StartSessionOptions, error) {
+	// Validate mutual exclusivity of prompt flags
+	if f.prompt != "" && f.promptFile != "" {
+		return usecase.StartSessionOptions{}, fmt.Errorf("cannot specify both --prompt and --prompt-file")
+	}
+	if f.planFile != "" && (f.prompt != "" || f.promptFile != "") {
+		return usecase.StartSessionOptions{}, fmt.Errorf("--plan cannot be combined with --prompt or --prompt-file")
+	}
+
+	if f.sanitizeName {
+		sanitized := config.SanitizeSessionName(name)
+		if sanitized == "" {
+			return usecase.StartSessionOptions{}, fmt.Errorf("%w '%s': no valid characters to build a name from", config.ErrInvalidSessionName, name)
+		}
+		if sanitized != name {
+			fmt.Printf("📝 Using session name '%s'\n", sanitized)
+			name = sanitized
+		}
+	}
+
+	// Parse custom resources
+	customResourcesMap := make(map[string]string)
+	for _, res := range f.customResources {
+		parts := strings.Split(res, "=")
+		if len(parts) != 2 {
+			return usecase.StartSessionOptions{}, fmt.Errorf("invalid resource format: %s (expected format: resourceName=quantity, e.g., nvidia.com/gpu=1)", res)
+		}
+		customResourcesMap[parts[0]] = parts[1]
+	}
+
+	labels, err := config.ParseLabels(f.labels)
+	if err != nil {
+		return usecase.StartSessionOptions{}, err
+	}
+
+	templateValues, err := config.ParseSetValues(f.setValues)
+	if err != nil {
+		return usecase.StartSessionOptions{}, err
+	}
+
+	// Parse secret files (Docker -v style: source:destination)
+	secretFileMappings := make([]usecase.SecretFileMapping, 0, len(f.secretFiles))
+	for _, mapping := range f.secretFiles {
+		parts := strings.SplitN(mapping, ":", 2)
+		if len(parts) != 2 {
+			return usecase.StartSessionOptions{}, fmt.Errorf("invalid secret file format: %s (expected format: source:destination, e.g., ~/.ssh/id_rsa:/root/.ssh/id_rsa)", mapping)
+		}
+		secretFileMappings = append(secretFileMappings, usecase.SecretFileMapping{
+			Source:      parts[0],
+			Destination: parts[1],
+		})
+	}
+
+	kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+	return usecase.StartSessionOptions{
+		Name:             name,
+		Repo:             f.repo,
+		SyncPath:         f.syncPath,
+		SyncInclude:      f.syncInclude,
+		SyncRemotePath:   f.remotePath,
+		LocalChanges:     f.localChanges,
+		Shared:           f.shared,
+		Namespace:        f.namespace,
+		CPU:              f.cpu,
+		Memory:           f.memory,
+		CustomResources:  customResourcesMap,
+		Branch:           f.branch,
+		KubeconfigPath:   kubeconfigPath,
+		Prompt:           f.prompt,
+		PromptFile:       f.promptFile,
+		PlanFile:         f.planFile,
+		AttachFiles:      f.attachFiles,
+		FailOnAgentError: f.failOnAgentErr,
+		SaveAgentLog:     f.saveAgentLog,
+		Image:            f.image,
+		Command:          f.command,
+		CloneDepth:       f.cloneDepth,
+		SingleBranch:     f.singleBranch,
+		GitCloneArgs:     f.gitCloneArgs,
+		CloneAttempts:    f.cloneAttempts,
+		GitMirror:        f.gitMirror,
+		ConfigFile:       f.configFile,
+		TtydEnabled:      cmd.Flags().Changed("ttyd"),
+		TtydEnabledVal:   f.ttydEnabled,
+		TtydPort:         f.ttydPort,
+		TtydOptions:      f.ttydOptions,
+		TtydReadonly:     f.ttydReadonly,
+		TtydReadonlySet:  cmd.Flags().Changed("ttyd-readonly"),
+		Expires:          f.expires,
+		Record:           f.record,
+		SpotFriendly:     f.spotFriendly,
+		SealedSecrets:    f.sealedSecrets,
+		Sandbox:          f.sandbox,
+		NoEvict:          f.noEvict,
+		DisruptionBudget: f.pdb,
+		Runtime:          f.runtime,
+		RestartPolicy:    f.restartPolicy,
+		Watchdog:         f.watchdog,
+		EnvFiles:         f.envFiles,
+		EnvExclude:       f.envExclude,
+		SecretFiles:      secretFileMappings,
+		Labels:           labels,
+		TemplateValues:   templateValues,
+		Overlay:          f.overlay,
+	}, nil
+}
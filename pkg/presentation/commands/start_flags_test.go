@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStartFlagsCommand(t *testing.T, args ...string) (*cobra.Command, *startFlags) {
+	t.Helper()
+	flags := &startFlags{}
+	cmd := &cobra.Command{Use: "start"}
+	cmd.Flags().String("kubeconfig", "", "")
+	flags.register(cmd)
+	require.NoError(t, cmd.ParseFlags(args))
+	return cmd, flags
+}
+
+func TestStartFlags_Options(t *testing.T) {
+	cmd, flags := newStartFlagsCommand(t,
+		"--repo", "https://github.com/example/repo",
+		"--resource", "nvidia.com/gpu=1",
+		"--secret-file", "~/.ssh/id_rsa:/root/.ssh/id_rsa",
+		"--ttyd=false",
+	)
+
+	opts, err := flags.options(cmd, "my-work")
+	require.NoError(t, err)
+
+	assert.Equal(t, "my-work", opts.Name)
+	assert.Equal(t, "https://github.com/example/repo", opts.Repo)
+	assert.Equal(t, map[string]string{"nvidia.com/gpu": "1"}, opts.CustomResources)
+	require.Len(t, opts.SecretFiles, 1)
+	assert.Equal(t, "/root/.ssh/id_rsa", opts.SecretFiles[0].Destination)
+	assert.True(t, opts.TtydEnabled)
+	assert.False(t, opts.TtydEnabledVal)
+	assert.False(t, opts.TtydReadonlySet)
+}
+
+func TestStartFlags_OptionsErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"prompt and prompt file", []string{"--prompt", "x", "--prompt-file", "p.md"}},
+		{"malformed resource", []string{"--resource", "nvidia.com/gpu"}},
+		{"malformed secret file", []string{"--secret-file", "id_rsa"}},
+		{"malformed label", []string{"--label", "team"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, flags := newStartFlagsCommand(t, tt.args...)
+			_, err := flags.options(cmd, "my-work")
+			assert.Error(t, err)
+		})
+	}
+}
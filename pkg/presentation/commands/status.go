@@ -0,0 +1,136 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+)
+
+// NewStatusCommand creates the status command
+func NewStatusCommand(sessionService *service.SessionService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status [name]",
+		Short: "Show the status of a session and why its container crashed",
+		Long: `Show a session, its pod and the restart policy of the pod.
+
+When the main container has terminated, its termination reason (e.g.
+OOMKilled), exit code, restart count and last log lines are shown, so a
+crashed command can be diagnosed without kubectl.
+
+Without a name, the session syncing the working directory or the one
+selected with 'kodama use' is shown.
+
+Examples:
+  kubectl kodama status my-work`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := sessionNameArg(args)
+			if err != nil {
+				return err
+			}
+			status, err := sessionService.GetSessionStatus(cmd.Context(), name)
+			if err != nil {
+				return err
+			}
+			writeSessionStatus(os.Stdout, status, time.Now())
+			return nil
+		},
+	}
+}
+
+// writeSessionStatus prints a session, its pod and the last crash of its main container
+func writeSessionStatus(w io.Writer, status *service.SessionStatus, now time.Time) {
+	session := status.Session
+	_, _ = fmt.Fprintf(w, "Session:   %s\n", session.Name)
+	_, _ = fmt.Fprintf(w, "Status:    %s\n", session.Status)
+	if session.IsLocalRuntime() {
+		_, _ = fmt.Fprintf(w, "Runtime:   %s\n", session.Runtime)
+	} else {
+		_, _ = fmt.Fprintf(w, "Namespace: %s\n", session.Namespace)
+	}
+	if session.Repo != "" {
+		_, _ = fmt.Fprintf(w, "Repo:      %s (%s)\n", session.Repo, session.Branch)
+	}
+	_, _ = fmt.Fprintf(w, "Created:   %s\n", table.RelativeTime(session.CreatedAt, now))
+
+	switch {
+	case errors.Is(status.PodErr, kubernetes.ErrPodNotFound):
+		_, _ = fmt.Fprintf(w, "Pod:       %s (not found)\n", session.PodName)
+	case status.PodErr != nil:
+		_, _ = fmt.Fprintf(w, "Pod:       %s (%v)\n", session.PodName, status.PodErr)
+	default:
+		ready := "not ready"
+		if status.Pod.Ready {
+			ready = "ready"
+		}
+		_, _ = fmt.Fprintf(w, "Pod:       %s (%s, %s)\n", session.PodName, status.Pod.Phase, ready)
+	}
+	if !session.IsLocalRuntime() {
+		_, _ = fmt.Fprintf(w, "Restart:   %s\n", session.PodRestartPolicy())
+	}
+	writeWatchdogStatus(w, status, now)
+
+	crash := status.Crash
+	if crash == nil {
+		return
+	}
+
+	reason := crash.Reason
+	if reason == "" {
+		reason = "terminated"
+	}
+	_, _ = fmt.Fprintf(w, "\n⚠️  Main container %s: exit code %d", reason, crash.ExitCode)
+	if crash.Signal != 0 {
+		_, _ = fmt.Fprintf(w, ", signal %d", crash.Signal)
+	}
+	if !crash.FinishedAt.IsZero() {
+		_, _ = fmt.Fprintf(w, " (%s)", table.RelativeTime(crash.FinishedAt, now))
+	}
+	_, _ = fmt.Fprintln(w)
+	if crash.Restarted {
+		state := "running again"
+		if crash.Waiting != "" {
+			state = crash.Waiting
+		}
+		_, _ = fmt.Fprintf(w, "   Restarted %d times, now %s\n", crash.RestartCount, state)
+	} else if session.PodRestartPolicy() == config.RestartPolicyNever {
+		_, _ = fmt.Fprintln(w, "   Not restarted: start the session with --restart-policy OnFailure to restart crashed containers")
+	}
+	if crash.Message != "" {
+		_, _ = fmt.Fprintf(w, "   Message: %s\n", crash.Message)
+	}
+	if len(crash.LogTail) > 0 {
+		_, _ = fmt.Fprintln(w, "   Last log lines:")
+		for _, line := range crash.LogTail {
+			_, _ = fmt.Fprintf(w, "     %s\n", line)
+		}
+	}
+}
+
+// writeWatchdogStatus prints the watchdog line of a session with a watchdog
+func writeWatchdogStatus(w io.Writer, status *service.SessionStatus, now time.Time) {
+	switch watchdog := status.Watchdog; {
+	case status.WatchdogErr != nil:
+		_, _ = fmt.Fprintf(w, "Watchdog:  unknown (%v)\n", status.WatchdogErr)
+	case watchdog == nil:
+		return
+	case watchdog.Healthy(now):
+		_, _ = fmt.Fprintf(w, "Watchdog:  healthy (heartbeat %s ago, %d restarts)\n", table.Duration(now.Sub(watchdog.Heartbeat)), watchdog.Restarts)
+	case watchdog.Heartbeat.IsZero():
+		_, _ = fmt.Fprintln(w, "Watchdog:  ⚠️  no heartbeat")
+	default:
+		_, _ = fmt.Fprintf(w, "Watchdog:  ⚠️  stale (heartbeat %s, %d restarts)\n", table.RelativeTime(watchdog.Heartbeat, now), watchdog.Restarts)
+	}
+	if status.Watchdog != nil && status.Watchdog.LastRestart != "" {
+		_, _ = fmt.Fprintf(w, "           Last restart: %s\n", status.Watchdog.LastRestart)
+	}
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestWriteSessionStatus_Crash(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	writeSessionStatus(&buf, &service.SessionStatus{
+		Session: &config.SessionConfig{
+			Name: "work", Namespace: "dev", PodName: "kodama-work", Status: config.StatusRunning,
+			Command: []string{"make", "serve"}, CreatedAt: now.Add(-time.Hour),
+		},
+		Pod: &kubernetes.PodStatus{Phase: corev1.PodRunning},
+		Crash: &kubernetes.ContainerCrash{
+			Reason: "OOMKilled", ExitCode: 137, FinishedAt: now.Add(-5 * time.Minute),
+			RestartCount: 3, Waiting: "CrashLoopBackOff", Restarted: true,
+			LogTail: []string{"allocating buffers", "killed"},
+		},
+	}, now)
+
+	out := buf.String()
+	for _, want := range []string{
+		"Pod:       kodama-work (Running, not ready)",
+		"Restart:   OnFailure",
+		"Main container OOMKilled: exit code 137 (5m ago)",
+		"Restarted 3 times, now CrashLoopBackOff",
+		"     killed",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteSessionStatus_NoCrash(t *testing.T) {
+	var buf bytes.Buffer
+	writeSessionStatus(&buf, &service.SessionStatus{
+		Session: &config.SessionConfig{Name: "work", Namespace: "dev", PodName: "kodama-work"},
+		PodErr:  kubernetes.ErrPodNotFound,
+	}, time.Now())
+
+	out := buf.String()
+	if !strings.Contains(out, "kodama-work (not found)") || !strings.Contains(out, "Restart:   Never") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if strings.Contains(out, "Main container") {
+		t.Errorf("output reports a crash without one:\n%s", out)
+	}
+}
+
+func TestWriteSessionStatus_Watchdog(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	session := &config.SessionConfig{Name: "work", Namespace: "dev", PodName: "kodama-work", Watchdog: true, CreatedAt: now.Add(-time.Hour)}
+	pod := &kubernetes.PodStatus{Phase: corev1.PodRunning, Ready: true}
+
+	var buf bytes.Buffer
+	writeSessionStatus(&buf, &service.SessionStatus{
+		Session: session, Pod: pod,
+		Watchdog: &kubernetes.WatchdogStatus{
+			Heartbeat: now.Add(-5 * time.Second), Restarts: 2,
+			LastRestart: "2025-03-01T11:58:00Z main process exited with 1",
+		},
+	}, now)
+	for _, want := range []string{
+		"Watchdog:  healthy (heartbeat 5s ago, 2 restarts)",
+		"Last restart: 2025-03-01T11:58:00Z main process exited with 1",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+
+	buf.Reset()
+	writeSessionStatus(&buf, &service.SessionStatus{
+		Session: session, Pod: pod,
+		Watchdog: &kubernetes.WatchdogStatus{Heartbeat: now.Add(-10 * time.Minute)},
+	}, now)
+	if !strings.Contains(buf.String(), "Watchdog:  ⚠️  stale (heartbeat 10m ago, 0 restarts)") {
+		t.Errorf("stale heartbeat not reported:\n%s", buf.String())
+	}
+}
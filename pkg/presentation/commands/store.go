@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+)
+
+// NewStoreCommand creates the store command for managing the local session store
+func NewStoreCommand(sessionService *service.SessionService) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "store",
+		Short: "Manage the local session store (~/.kodama)",
+	}
+
+	cmd.AddCommand(newStoreEncryptCommand(sessionService))
+
+	return cmd
+}
+
+func newStoreEncryptCommand(sessionService *service.SessionService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "encrypt",
+		Short: "Encrypt existing session files and enable encryption at rest",
+		Long: `Encrypt existing plaintext session files and the auth cache in ~/.kodama,
+and enable encryption for all future writes.
+
+A random AES-256 key is generated if one does not exist, and kept outside
+~/.kodama according to store.keySource in ~/.kodama/config.yaml:
+
+  keychain  OS keychain (macOS Keychain, or the Secret Service via
+            'secret-tool' on Linux). This is the default.
+  age       ~/.kodama/store.key.age, encrypted to store.ageRecipient and
+            decrypted with store.ageIdentity using the 'age' CLI.
+
+A plaintext ~/.kodama/store.key written by older versions is moved into the
+key source. Set KODAMA_STORE_KEY to a hex-encoded key to supply the key from
+elsewhere (e.g., a CI secret).
+
+Examples:
+  kubectl kodama store encrypt`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			migrated, err := sessionService.EncryptStore()
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf("✓ Encrypted %d file(s)\n", migrated)
+			fmt.Println("✓ Encryption at rest enabled (store.encrypt: true)")
+			fmt.Println("\n⚠️  Keep the store key safe - encrypted sessions cannot be read without it")
+			return nil
+		},
+	}
+}
@@ -0,0 +1,222 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/sync"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewSyncCommand creates the sync command group
+func NewSyncCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Sync local files to a session",
+	}
+
+	cmd.AddCommand(newSyncStartCommand())
+	cmd.AddCommand(newSyncOnceCommand())
+	cmd.AddCommand(newSyncStatusCommand())
+
+	return cmd
+}
+
+func newSyncStartCommand() *cobra.Command {
+	var onChange string
+	var onConflict string
+
+	cmd := &cobra.Command{
+		Use:   "start [name]",
+		Short: "Continuously push local changes to a session",
+		Long: `Watch the session's synced directory and push changes to the pod until
+interrupted with Ctrl+C. The session must have been started with --sync.
+
+With --on-change, the command runs with sh in /workspace after each batch of
+changes is pushed, and a one-line pass/fail result is printed. Failed runs also
+show the last lines of their output. Changes synced while the command runs
+queue a single re-run.
+
+Before the first push, files changed in the pod since the last sync (e.g. by
+the agent) are listed instead of being overwritten. Choose with --on-conflict:
+push overwrites them, pull copies the pod's versions to the local directory
+first, abort stops. Without the flag you are asked when stdin is a terminal.
+
+Examples:
+  kubectl kodama sync start my-work
+  kubectl kodama sync start my-work --on-change "go test ./..."
+  kubectl kodama sync start --on-change "npm test"   # Session of this directory
+  kubectl kodama sync start my-work --on-conflict pull`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := sessionNameArg(args)
+			if err != nil {
+				return err
+			}
+
+			if err := usecase.ValidateSyncConflictMode(onConflict); err != nil {
+				return err
+			}
+
+			opts := usecase.WatchSyncOptions{
+				Name:       name,
+				OnChange:   onChange,
+				OnConflict: onConflict,
+			}
+			err = usecase.WatchSync(cmd.Context(), opts)
+			if !errors.Is(err, usecase.ErrSyncConflict) || onConflict != "" || !stdinIsTerminal() {
+				return err
+			}
+
+			if opts.OnConflict, err = askSyncConflictMode(); err != nil || opts.OnConflict == "" {
+				return err
+			}
+			return usecase.WatchSync(cmd.Context(), opts)
+		},
+	}
+
+	cmd.Flags().StringVar(&onChange, "on-change", "", "Command to run in the pod after each sync, e.g. \"go test ./...\"")
+	cmd.Flags().StringVar(&onConflict, "on-conflict", "", "How to handle files changed in the pod since the last sync: push, pull or abort")
+
+	return cmd
+}
+
+func newSyncOnceCommand() *cobra.Command {
+	var onConflict string
+
+	cmd := &cobra.Command{
+		Use:   "once [name]",
+		Short: "Push local changes to a session once",
+		Long: `Push the local changes made since the last sync to the pod and exit.
+
+A manifest of the synced files (path, size, modification time and hash) is kept
+in ~/.kodama/sessions/<name>/ and in the pod at /workspace/.kodama. Only files
+added or changed since the last sync are copied, and files deleted locally are
+removed from the pod. The first sync of a session pushes the whole directory.
+
+Files changed in the pod since the last sync are handled as in 'sync start'.
+
+Examples:
+  kubectl kodama sync once my-work
+  kubectl kodama sync once --on-conflict pull   # Session of this directory`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := sessionNameArg(args)
+			if err != nil {
+				return err
+			}
+
+			if err := usecase.ValidateSyncConflictMode(onConflict); err != nil {
+				return err
+			}
+
+			opts := usecase.SyncOnceOptions{Name: name, OnConflict: onConflict}
+			_, err = usecase.SyncOnce(cmd.Context(), opts)
+			if !errors.Is(err, usecase.ErrSyncConflict) || onConflict != "" || !stdinIsTerminal() {
+				return err
+			}
+
+			if opts.OnConflict, err = askSyncConflictMode(); err != nil || opts.OnConflict == "" {
+				return err
+			}
+			_, err = usecase.SyncOnce(cmd.Context(), opts)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&onConflict, "on-conflict", "", "How to handle files changed in the pod since the last sync: push, pull or abort")
+
+	return cmd
+}
+
+func newSyncStatusCommand() *cobra.Command {
+	var showFiles bool
+
+	cmd := &cobra.Command{
+		Use:   "status [name]",
+		Short: "Show files changed locally and in the pod since the last sync",
+		Long: `Compare the local directory and the pod workspace with the manifests recorded
+after the last sync, and report how many files were added, modified or deleted
+on each side. Only files whose size or modification time changed are hashed.
+
+Examples:
+  kubectl kodama sync status my-work
+  kubectl kodama sync status --files   # Session of this directory`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, err := sessionNameArg(args)
+			if err != nil {
+				return err
+			}
+
+			drift, err := usecase.SyncStatus(cmd.Context(), name)
+			if err != nil {
+				return err
+			}
+
+			if !drift.Recorded {
+				fmt.Println("⚠️  No sync recorded yet; all files count as added")
+			}
+			printSyncDiff("Local", drift.Local, showFiles)
+			printSyncDiff("Pod", drift.Pod, showFiles)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&showFiles, "files", false, "List the changed files")
+
+	return cmd
+}
+
+// printSyncDiff prints the drift counts of one side of a sync
+func printSyncDiff(side string, diff sync.ManifestDiff, showFiles bool) {
+	if diff.Count() == 0 {
+		fmt.Printf("%s: in sync\n", side)
+		return
+	}
+	fmt.Printf("%s: %d added, %d modified, %d deleted\n", side, len(diff.Added), len(diff.Modified), len(diff.Deleted))
+	if !showFiles {
+		return
+	}
+	for _, path := range diff.Added {
+		fmt.Printf("   + %s\n", path)
+	}
+	for _, path := range diff.Modified {
+		fmt.Printf("   ~ %s\n", path)
+	}
+	for _, path := range diff.Deleted {
+		fmt.Printf("   - %s\n", path)
+	}
+}
+
+// askSyncConflictMode asks how to handle files changed in the pod
+// Returns "" when the user aborts.
+func askSyncConflictMode() (string, error) {
+	fmt.Print("\nOverwrite them (push), copy them here first (pull) or abort? [push/pull/abort]: ")
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return "", fmt.Errorf("failed to read choice: %w", err)
+	}
+
+	mode := strings.TrimSpace(strings.ToLower(response))
+	if mode != usecase.SyncConflictPush && mode != usecase.SyncConflictPull {
+		fmt.Println("Canceled")
+		return "", nil
+	}
+	return mode, nil
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewTestCommand creates a new test command
+func NewTestCommand() *cobra.Command {
+	var command string
+
+	cmd := &cobra.Command{
+		Use:   "test [name]",
+		Short: "Run the project's test suite in a session",
+		Long: `Run the test command in /workspace of a session and stream its output.
+
+The command comes from --cmd, or from test.command in the .kodama.yaml the
+session was started with. The result (pass/fail, exit code, duration) is
+recorded in the session, and kubectl kodama exits with the test command's exit
+code, so CI pipelines can gate on it after an agent run.
+
+Examples:
+  kubectl kodama test my-work
+  kubectl kodama test my-work --cmd "go test ./..."
+  kubectl kodama test                           # Current session`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+			name, err := sessionNameArg(args)
+			if err != nil {
+				return err
+			}
+
+			_, err = usecase.RunTests(cmd.Context(), usecase.RunTestsOptions{
+				Name:           name,
+				KubeconfigPath: kubeconfigPath,
+				Command:        command,
+			}, os.Stdout, os.Stderr)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&command, "cmd", "", "Test command to run (default: test.command from the session template)")
+
+	return cmd
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/presentation/tui"
+)
+
+// NewUICommand creates the ui command
+func NewUICommand(sessionService *service.SessionService) *cobra.Command {
+	return &cobra.Command{
+		Use:   "ui",
+		Short: "Browse and manage sessions in an interactive terminal UI",
+		Long: `Open an interactive session browser with live pod status.
+
+Keybindings:
+  ↑/↓, j/k   Move selection
+  enter, a   Attach with an interactive shell
+  t          Open the web terminal (ttyd)
+  l          View pod logs
+  d          Delete the session
+  r          Refresh now
+  q          Quit`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			binary, err := os.Executable()
+			if err != nil {
+				return fmt.Errorf("failed to locate kodama binary: %w", err)
+			}
+
+			return tui.Run(sessionService, tui.Options{
+				KodamaBinary:   binary,
+				KubeconfigPath: kubeconfigPath,
+			})
+		},
+	}
+}
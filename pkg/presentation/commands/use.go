@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/application/service"
+)
+
+// NewUseCommand creates the use command for selecting the current session
+func NewUseCommand(sessionService *service.SessionService) *cobra.Command {
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "use [name]",
+		Short: "Set the current session for commands run without a name",
+		Long: `Set the current session, like 'kubectl config use-context'.
+
+attach, exec and logs operate on the current session when no name is given,
+and 'kubectl kodama list' marks it with *. Without arguments, prints the
+current session.
+
+Examples:
+  kubectl kodama use my-work
+  kubectl kodama attach            # Attaches to my-work
+  kubectl kodama exec -- git status
+  kubectl kodama use               # Prints my-work
+  kubectl kodama use --clear`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case clear:
+				if len(args) > 0 {
+					return fmt.Errorf("--clear does not take a session name")
+				}
+				if err := sessionService.UseSession(""); err != nil {
+					return err
+				}
+				fmt.Println("✓ Current session cleared")
+			case len(args) == 1:
+				if err := sessionService.UseSession(args[0]); err != nil {
+					return err
+				}
+				fmt.Printf("✓ Switched to session %q\n", args[0])
+			default:
+				current, err := sessionService.CurrentSession()
+				if err != nil {
+					return err
+				}
+				if current == "" {
+					fmt.Println("No current session (set one with 'kubectl kodama use <name>')")
+					return nil
+				}
+				fmt.Println(current)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clear, "clear", false, "Unset the current session")
+
+	return cmd
+}
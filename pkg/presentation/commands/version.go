@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/internal/version"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// NewVersionCommand creates the version command
+func NewVersionCommand() *cobra.Command {
+	var (
+		clientOnly  bool
+		checkUpdate bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "version",
+		Short: "Print version information",
+		Long: `Print the kubectl-kodama version together with the kubectl and Kubernetes
+server versions, and warn when they fall outside the supported version skew.
+
+Examples:
+  kubectl kodama version                 # Client, kubectl and server versions
+  kubectl kodama version --client        # Client version only
+  kubectl kodama version --check-update  # Also check GitHub for a newer release`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			fmt.Printf("kubectl-kodama version %s (commit %s)\n", version.Version, version.Commit)
+
+			if !clientOnly {
+				printClusterVersions(cmd)
+			}
+
+			if checkUpdate {
+				release, err := version.LatestRelease(cmd.Context())
+				if err != nil {
+					fmt.Printf("⚠️  %v\n", err)
+					return nil
+				}
+				if version.IsNewer(release.Version, version.Version) {
+					fmt.Printf("✨ A new release is available: %s (%s)\n", release.Version, release.URL)
+				} else {
+					fmt.Printf("✓ Up to date (latest release: %s)\n", release.Version)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&clientOnly, "client", false, "Only print the kubectl-kodama version")
+	cmd.Flags().BoolVar(&checkUpdate, "check-update", false, "Check GitHub releases for a newer version")
+
+	return cmd
+}
+
+// printClusterVersions prints the kubectl and server versions with skew warnings.
+// Failures are reported as warnings so the client version is always shown.
+func printClusterVersions(cmd *cobra.Command) {
+	kubectlVersion, err := kubernetes.KubectlVersion(cmd.Context())
+	if err != nil {
+		fmt.Printf("⚠️  kubectl: %v\n", err)
+	} else {
+		fmt.Printf("kubectl version %s\n", kubectlVersion)
+	}
+
+	kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+	client, err := kubernetes.NewClient(kubeconfigPath)
+	if err != nil {
+		fmt.Printf("⚠️  Kubernetes server: %v\n", err)
+		return
+	}
+	serverVersion, err := client.ServerVersion()
+	if err != nil {
+		fmt.Printf("⚠️  Kubernetes server: %v\n", err)
+		return
+	}
+	fmt.Printf("Kubernetes server version %s\n", serverVersion)
+
+	if warning := version.CheckServerSkew(serverVersion); warning != "" {
+		fmt.Printf("⚠️  %s\n", warning)
+	}
+	if kubectlVersion != "" {
+		if warning := version.CheckKubectlSkew(kubectlVersion, serverVersion); warning != "" {
+			fmt.Printf("⚠️  %s\n", warning)
+		}
+	}
+}
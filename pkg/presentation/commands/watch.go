@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// NewWatchCommand creates a new watch command
+func NewWatchCommand() *cobra.Command {
+	var (
+		interval     time.Duration
+		autoRecreate bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "watch <name>",
+		Short: "Monitor a session and recover from pod eviction or node failure",
+		Long: `Watch a session's pod until interrupted. If the pod is evicted, deleted,
+or its node becomes NotReady or disappears, the session is marked Degraded.
+
+With --auto-recreate the pod is rebuilt from the session config (reusing its
+secrets and any persistent volumes) and the initial file sync is re-run.
+Sessions started with --spot-friendly are always recreated, and resume from
+their last workspace checkpoint.
+
+Examples:
+  kubectl kodama watch my-work
+  kubectl kodama watch my-work --auto-recreate --interval 30s`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfigPath, _ := cmd.Flags().GetString("kubeconfig")
+
+			return usecase.WatchSession(cmd.Context(), usecase.WatchSessionOptions{
+				Name:           args[0],
+				KubeconfigPath: kubeconfigPath,
+				Interval:       interval,
+				AutoRecreate:   autoRecreate,
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", usecase.DefaultWatchInterval, "How often to check the pod")
+	cmd.Flags().BoolVar(&autoRecreate, "auto-recreate", false, "Recreate the pod and re-run the initial sync when it is lost")
+
+	return cmd
+}
@@ -0,0 +1,187 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/shellutil"
+)
+
+// ErrTmuxNotInstalled is returned when tmux is not available on PATH
+var ErrTmuxNotInstalled = errors.New("tmux not found in PATH (install tmux to use --dashboard)")
+
+// Pane is a single tmux pane running a shell command
+type Pane struct {
+	Title   string
+	Command []string
+}
+
+// Layout describes the dashboard tmux session for a kodama session
+//
+// The shell pane takes the left half; agent output and sync log are
+// stacked on the right.
+type Layout struct {
+	SessionName string // tmux session name
+	Shell       Pane
+	Agent       Pane
+	Sync        *Pane // nil when the session has no local sync
+}
+
+// Options configures how the dashboard panes are built
+type Options struct {
+	KubeconfigPath string
+	Command        string // Command for the shell pane, run with the session shell (default: interactive shell)
+	KodamaBinary   string // Path to this binary, used for the sync pane
+}
+
+// BuildLayout builds the dashboard layout for a session
+func BuildLayout(session *config.SessionConfig, opts Options) *Layout {
+	kubectl := []string{"kubectl"}
+	if opts.KubeconfigPath != "" {
+		kubectl = append(kubectl, "--kubeconfig", opts.KubeconfigPath)
+	}
+
+	shellCmd := []string{"/bin/sh", "-c", kubernetes.InteractiveShellScript(session.Shell)}
+	if opts.Command != "" {
+		shellCmd = kubernetes.ShellCommand(session.Shell, opts.Command)
+	}
+
+	// Agent tasks run through exec, so their output is in the run logs, not the container log
+	agentCmd := []string{"sh", "-c", agent.LatestRunLogScript(200, true)}
+
+	layout := &Layout{
+		SessionName: "kodama-" + session.Name,
+		Shell: Pane{
+			Title:   "shell",
+			Command: append(append(append([]string{}, kubectl...), "exec", "-it", "-n", session.Namespace, session.PodName, "-c", kubernetes.MainContainerName, "--"), shellCmd...),
+		},
+		Agent: Pane{
+			Title:   "agent",
+			Command: append(append(append([]string{}, kubectl...), "exec", "-n", session.Namespace, session.PodName, "-c", kubernetes.MainContainerName, "--"), agentCmd...),
+		},
+	}
+
+	if session.Sync.Enabled && session.Sync.LocalPath != "" {
+		syncCmd := []string{opts.KodamaBinary}
+		if opts.KubeconfigPath != "" {
+			syncCmd = append(syncCmd, "--kubeconfig", opts.KubeconfigPath)
+		}
+		syncCmd = append(syncCmd, "sync", "start", session.Name)
+
+		layout.Sync = &Pane{
+			Title:   "sync",
+			Command: syncCmd,
+		}
+	}
+
+	return layout
+}
+
+// windowName names the dashboard window, so it is targeted without assuming
+// base-index
+const windowName = "kodama"
+
+// paneIDFormat makes new-session and split-window print the new pane's id
+const paneIDFormat = "#{pane_id}"
+
+// tmuxRunner runs tmux with args and returns its trimmed output
+type tmuxRunner func(args ...string) (string, error)
+
+// create builds the layout in a detached tmux session with run
+// Panes are targeted by the ids tmux prints, so base-index and
+// pane-base-index do not matter.
+func (l *Layout) create(run tmuxRunner) error {
+	shellPane, err := run("new-session", "-d", "-s", l.SessionName, "-n", windowName, "-P", "-F", paneIDFormat, paneCommand(l.Shell))
+	if err != nil {
+		return err
+	}
+	if _, err := run("set-option", "-w", "-t", l.SessionName+":"+windowName, "pane-border-status", "top"); err != nil {
+		return err
+	}
+	if _, err := run("select-pane", "-t", shellPane, "-T", l.Shell.Title); err != nil {
+		return err
+	}
+
+	agentPane, err := run("split-window", "-h", "-t", shellPane, "-P", "-F", paneIDFormat, paneCommand(l.Agent))
+	if err != nil {
+		return err
+	}
+	if _, err := run("select-pane", "-t", agentPane, "-T", l.Agent.Title); err != nil {
+		return err
+	}
+
+	if l.Sync != nil {
+		syncPane, err := run("split-window", "-v", "-t", agentPane, "-P", "-F", paneIDFormat, paneCommand(*l.Sync))
+		if err != nil {
+			return err
+		}
+		if _, err := run("select-pane", "-t", syncPane, "-T", l.Sync.Title); err != nil {
+			return err
+		}
+	}
+
+	// Focus the interactive shell
+	_, err = run("select-pane", "-t", shellPane)
+	return err
+}
+
+// Run creates the tmux session (reusing it if it already exists) and attaches to it
+func Run(ctx context.Context, layout *Layout) error {
+	if _, err := exec.LookPath("tmux"); err != nil {
+		return ErrTmuxNotInstalled
+	}
+
+	//#nosec G204 -- tmux with generated session name
+	hasSession := exec.CommandContext(ctx, "tmux", "has-session", "-t", layout.SessionName)
+	if hasSession.Run() != nil {
+		err := layout.create(func(args ...string) (string, error) {
+			//#nosec G204 -- tmux args built from session config
+			output, err := exec.CommandContext(ctx, "tmux", args...).CombinedOutput()
+			if err != nil {
+				return "", fmt.Errorf("tmux %s failed: %s: %w", args[0], strings.TrimSpace(string(output)), err)
+			}
+			return strings.TrimSpace(string(output)), nil
+		})
+		if err != nil {
+			_ = exec.CommandContext(ctx, "tmux", "kill-session", "-t", layout.SessionName).Run() //#nosec G204
+			return err
+		}
+	} else {
+		fmt.Printf("♻️  Reusing existing dashboard '%s'\n", layout.SessionName)
+	}
+
+	// Inside tmux, switch the current client instead of nesting sessions
+	attach := "attach-session"
+	if os.Getenv("TMUX") != "" {
+		attach = "switch-client"
+	}
+
+	//#nosec G204 -- tmux with generated session name
+	cmd := exec.CommandContext(ctx, "tmux", attach, "-t", layout.SessionName)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to attach to dashboard: %w", err)
+	}
+
+	return nil
+}
+
+// paneCommand renders a pane command as a shell string for tmux
+// The pane stays open after the command exits so errors remain visible.
+func paneCommand(p Pane) string {
+	quoted := make([]string, len(p.Command))
+	for i, arg := range p.Command {
+		quoted[i] = shellutil.Quote(arg)
+	}
+	return strings.Join(quoted, " ") + "; echo; echo '[" + p.Title + " exited - press Enter to close]'; read _"
+}
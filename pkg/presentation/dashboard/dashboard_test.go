@@ -0,0 +1,107 @@
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestBuildLayout(t *testing.T) {
+	session := &config.SessionConfig{
+		Name:      "my-work",
+		Namespace: "dev",
+		PodName:   "kodama-my-work",
+		Shell:     "zsh",
+	}
+
+	layout := BuildLayout(session, Options{KodamaBinary: "/usr/local/bin/kubectl-kodama"})
+
+	assert.Equal(t, "kodama-my-work", layout.SessionName)
+	assert.Equal(t, []string{"kubectl", "exec", "-it", "-n", "dev", "kodama-my-work", "-c", "claude-code", "--"}, layout.Shell.Command[:9])
+	assert.Equal(t, []string{"/bin/sh", "-c", kubernetes.InteractiveShellScript("zsh")}, layout.Shell.Command[9:])
+	assert.Equal(t, []string{"kubectl", "exec", "-n", "dev", "kodama-my-work", "-c", "claude-code", "--", "sh", "-c"}, layout.Agent.Command[:10])
+	assert.Contains(t, layout.Agent.Command[10], agent.RunsDir)
+	assert.Nil(t, layout.Sync, "no sync pane without local sync")
+
+	session.Sync = config.SyncConfig{Enabled: true, LocalPath: "/src"}
+	layout = BuildLayout(session, Options{
+		KodamaBinary:   "/usr/local/bin/kubectl-kodama",
+		KubeconfigPath: "/tmp/kubeconfig",
+		Command:        "claude --continue",
+	})
+
+	require.NotNil(t, layout.Sync)
+	assert.Equal(t, []string{"/usr/local/bin/kubectl-kodama", "--kubeconfig", "/tmp/kubeconfig", "sync", "start", "my-work"}, layout.Sync.Command)
+	assert.Equal(t, kubernetes.ShellCommand("zsh", "claude --continue"), layout.Shell.Command[len(layout.Shell.Command)-5:])
+	assert.Equal(t, "--kubeconfig", layout.Agent.Command[1])
+}
+
+func TestLayoutCreate_TargetsPaneIDs(t *testing.T) {
+	session := &config.SessionConfig{
+		Name:      "my-work",
+		Namespace: "dev",
+		PodName:   "kodama-my-work",
+		Sync:      config.SyncConfig{Enabled: true, LocalPath: "/src"},
+	}
+	layout := BuildLayout(session, Options{KodamaBinary: "kubectl-kodama"})
+
+	// Pane ids as tmux prints them with base-index and pane-base-index set to 1
+	nextID := 7
+	var calls [][]string
+	err := layout.create(func(args ...string) (string, error) {
+		calls = append(calls, args)
+		if slices.Contains(args, "-P") {
+			nextID++
+			return fmt.Sprintf("%%%d", nextID), nil
+		}
+		return "", nil
+	})
+	require.NoError(t, err)
+
+	var targets []string
+	for _, args := range calls {
+		if i := slices.Index(args, "-t"); i >= 0 {
+			targets = append(targets, args[0]+" "+args[i+1])
+		}
+	}
+	assert.Equal(t, []string{
+		"set-option kodama-my-work:kodama",
+		"select-pane %8",
+		"split-window %8",
+		"select-pane %9",
+		"split-window %9",
+		"select-pane %10",
+		"select-pane %8",
+	}, targets)
+	assert.Equal(t, []string{"select-pane", "-t", "%8", "-T", "shell"}, calls[2])
+	assert.Equal(t, []string{"select-pane", "-t", "%10", "-T", "sync"}, calls[6])
+}
+
+func TestLayoutCreate_StopsOnError(t *testing.T) {
+	layout := BuildLayout(&config.SessionConfig{Name: "my-work", Namespace: "dev", PodName: "kodama-my-work"}, Options{})
+
+	var calls int
+	err := layout.create(func(args ...string) (string, error) {
+		calls++
+		if args[0] == "split-window" {
+			return "", errors.New("tmux split-window failed: no space for new pane")
+		}
+		return "%1", nil
+	})
+	require.ErrorContains(t, err, "no space for new pane")
+	assert.Equal(t, 4, calls)
+}
+
+func TestPaneCommand(t *testing.T) {
+	cmd := paneCommand(Pane{Title: "shell", Command: []string{"echo", "it's"}})
+	assert.True(t, strings.HasPrefix(cmd, `'echo' 'it'\''s';`), cmd)
+}
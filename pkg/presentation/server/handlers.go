@@ -0,0 +1,378 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// maxBodyBytes limits request bodies (prompts included)
+const maxBodyBytes = 1 << 20
+
+// sessionResponse is the JSON representation of a session
+type sessionResponse struct {
+	CreatedAt  time.Time      `json:"createdAt"`
+	ExpiresAt  *time.Time     `json:"expiresAt,omitempty"`
+	LastAgent  *agentResponse `json:"lastAgent,omitempty"`
+	Name       string         `json:"name"`
+	Namespace  string         `json:"namespace"`
+	PodName    string         `json:"podName,omitempty"`
+	Repo       string         `json:"repo,omitempty"`
+	Branch     string         `json:"branch,omitempty"`
+	CommitHash string         `json:"commitHash,omitempty"`
+	Status     string         `json:"status"`
+	PodPhase   string         `json:"podPhase,omitempty"`
+	Error      string         `json:"error,omitempty"`
+}
+
+// agentResponse is the JSON representation of an agent execution
+type agentResponse struct {
+	ExecutedAt   time.Time `json:"executedAt"`
+	Prompt       string    `json:"prompt,omitempty"`
+	TaskID       string    `json:"taskId,omitempty"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	DiffStat     string    `json:"diffStat,omitempty"`
+	ChangedFiles []string  `json:"changedFiles,omitempty"`
+	// ProtectedChanges lists changed protected paths; set with status "needs-review"
+	ProtectedChanges  []string `json:"protectedChanges,omitempty"`
+	ProtectedReverted bool     `json:"protectedReverted,omitempty"`
+}
+
+// createSessionRequest is the body of POST /api/v1/sessions
+type createSessionRequest struct {
+	Name      string `json:"name"`
+	Repo      string `json:"repo"`
+	Branch    string `json:"branch,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Image     string `json:"image,omitempty"`
+	CPU       string `json:"cpu,omitempty"`
+	Memory    string `json:"memory,omitempty"`
+	Prompt    string `json:"prompt,omitempty"`
+	Config    string `json:"config,omitempty"`  // Session template name in the server's templates directory
+	Expires   string `json:"expires,omitempty"` // Go duration, e.g. "24h"
+}
+
+// runAgentRequest is the body of POST /api/v1/sessions/{name}/agent
+type runAgentRequest struct {
+	Prompt       string `json:"prompt"`
+	IgnoreBudget bool   `json:"ignoreBudget,omitempty"` // Run even if agent.maxRuns or agent.maxDuration is used up
+	Steal        bool   `json:"steal,omitempty"`        // Take over the session lock from another user or run
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleListSessions(w http.ResponseWriter, r *http.Request) {
+	sessions, err := s.sessions.ListSessions()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp = append(resp, toSessionResponse(session))
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"sessions": resp})
+}
+
+func (s *Server) handleGetSession(w http.ResponseWriter, r *http.Request) {
+	name, ok := sessionName(w, r)
+	if !ok {
+		return
+	}
+
+	inProgress, startErr := s.startState(name)
+
+	session, err := s.sessions.LoadSession(name)
+	if err != nil {
+		// The session config is only written part-way through start
+		switch {
+		case inProgress:
+			writeJSON(w, http.StatusOK, sessionResponse{Name: name, Status: string(config.StatusStarting)})
+		case startErr != nil:
+			writeJSON(w, http.StatusOK, sessionResponse{Name: name, Status: string(config.StatusFailed), Error: startErr.Error()})
+		default:
+			writeError(w, http.StatusNotFound, fmt.Errorf("session '%s' not found", name))
+		}
+		return
+	}
+
+	resp := toSessionResponse(session)
+	if inProgress {
+		// A resumed start reuses the stored config, which may still say Failed
+		resp.Status = string(config.StatusStarting)
+	}
+	if startErr != nil {
+		resp.Error = startErr.Error()
+	}
+	if pod, podErr := s.sessions.GetPod(r.Context(), session.PodName, session.Namespace); podErr == nil && pod != nil {
+		resp.PodPhase = string(pod.Phase)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) handleCreateSession(w http.ResponseWriter, r *http.Request) {
+	var req createSessionRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	if err := config.ValidateSessionName(req.Name); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Repo == "" && req.Config == "" {
+		writeError(w, http.StatusBadRequest, config.ErrRepoRequired)
+		return
+	}
+
+	var configFile string
+	if req.Config != "" {
+		path, err := config.TemplatePath(s.opts.TemplatesDir, req.Config)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		configFile = path
+	}
+
+	var expires time.Duration
+	if req.Expires != "" {
+		d, err := time.ParseDuration(req.Expires)
+		if err != nil || d < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid expires %q", req.Expires))
+			return
+		}
+		expires = d
+	}
+
+	// Incomplete sessions are resumed by StartSession
+	if existing, err := s.sessions.LoadSession(req.Name); err == nil && !existing.IsResumable() {
+		writeError(w, http.StatusConflict, fmt.Errorf("session '%s' already exists", req.Name))
+		return
+	}
+
+	opts := usecase.StartSessionOptions{
+		Name:           req.Name,
+		Repo:           req.Repo,
+		Branch:         req.Branch,
+		Namespace:      req.Namespace,
+		Image:          req.Image,
+		CPU:            req.CPU,
+		Memory:         req.Memory,
+		Prompt:         req.Prompt,
+		ConfigFile:     configFile,
+		Expires:        expires,
+		KubeconfigPath: s.opts.KubeconfigPath,
+	}
+
+	if !s.startAsync(opts) {
+		writeError(w, http.StatusConflict, fmt.Errorf("session '%s' is already starting", req.Name))
+		return
+	}
+
+	w.Header().Set("Location", "/api/v1/sessions/"+req.Name)
+	writeJSON(w, http.StatusAccepted, sessionResponse{Name: req.Name, Status: string(config.StatusStarting)})
+}
+
+func (s *Server) handleDeleteSession(w http.ResponseWriter, r *http.Request) {
+	name, ok := sessionName(w, r)
+	if !ok {
+		return
+	}
+
+	if !s.sessions.SessionExists(name) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("session '%s' not found", name))
+		return
+	}
+
+	if err := s.sessions.DeleteSession(r.Context(), name); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleRunAgent(w http.ResponseWriter, r *http.Request) {
+	name, ok := sessionName(w, r)
+	if !ok {
+		return
+	}
+
+	var req runAgentRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+	if req.Prompt == "" {
+		writeError(w, http.StatusBadRequest, errors.New("prompt is required"))
+		return
+	}
+
+	if !s.sessions.SessionExists(name) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("session '%s' not found", name))
+		return
+	}
+
+	session, err := s.sessions.RunAgentWithOptions(r.Context(), name, req.Prompt, config.AgentRunOptions{IgnoreBudget: req.IgnoreBudget, StealLock: req.Steal})
+	if errors.Is(err, kubernetes.ErrSessionLocked) {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	if errors.Is(err, config.ErrAgentBudgetExceeded) {
+		writeError(w, http.StatusTooManyRequests, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// The task has finished and been reviewed by the time it returns
+	writeJSON(w, http.StatusOK, toSessionResponse(session))
+}
+
+// handleLogs streams pod logs as server-sent events, one event per line
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	name, ok := sessionName(w, r)
+	if !ok {
+		return
+	}
+
+	follow := r.URL.Query().Get("follow") == "true"
+	var tail int64
+	if v := r.URL.Query().Get("tail"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid tail %q", v))
+			return
+		}
+		tail = n
+	}
+
+	if !s.sessions.SessionExists(name) {
+		writeError(w, http.StatusNotFound, fmt.Errorf("session '%s' not found", name))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, errors.New("streaming not supported"))
+		return
+	}
+
+	stream, err := s.sessions.StreamLogs(r.Context(), name, follow, tail)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	defer func() { _ = stream.Close() }()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), maxBodyBytes)
+	for scanner.Scan() {
+		if _, err := fmt.Fprintf(w, "data: %s\n\n", scanner.Text()); err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+
+	if err := scanner.Err(); err != nil && r.Context().Err() == nil {
+		_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", err.Error())
+	} else {
+		_, _ = fmt.Fprint(w, "event: end\ndata: \n\n")
+	}
+	flusher.Flush()
+}
+
+// sessionName returns the validated {name} path value
+// An invalid name is answered with 400 Bad Request, so it never reaches the
+// session store as a path.
+func sessionName(w http.ResponseWriter, r *http.Request) (string, bool) {
+	name := r.PathValue("name")
+	if err := config.ValidateSessionName(name); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return "", false
+	}
+	return name, true
+}
+
+func toSessionResponse(session *config.SessionConfig) sessionResponse {
+	resp := sessionResponse{
+		CreatedAt:  session.CreatedAt,
+		ExpiresAt:  session.ExpiresAt,
+		Name:       session.Name,
+		Namespace:  session.Namespace,
+		PodName:    session.PodName,
+		Repo:       session.Repo,
+		Branch:     session.Branch,
+		CommitHash: session.CommitHash,
+		Status:     string(session.Status),
+	}
+
+	if exec := session.GetLastAgentExecution(); exec != nil {
+		resp.LastAgent = &agentResponse{
+			ExecutedAt:        exec.ExecutedAt,
+			Prompt:            exec.Prompt,
+			TaskID:            exec.TaskID,
+			Status:            exec.Status,
+			Error:             exec.Error,
+			DiffStat:          exec.DiffStat,
+			ChangedFiles:      exec.ChangedFiles,
+			ProtectedChanges:  exec.ProtectedChanges,
+			ProtectedReverted: exec.ProtectedReverted,
+		}
+	}
+
+	return resp
+}
+
+// decodeJSON decodes a JSON request body into v
+// A body that is not declared as application/json is answered with 415
+// Unsupported Media Type, so a browser form cannot post to the API; other
+// failures are answered with 400 Bad Request.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mediaType != "application/json" {
+		writeError(w, http.StatusUnsupportedMediaType, errors.New("request body must be sent as Content-Type: application/json"))
+		return false
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxBodyBytes)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
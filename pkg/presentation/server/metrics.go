@@ -0,0 +1,60 @@
+package server
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/metrics"
+)
+
+// sessionLister lists stored sessions
+type sessionLister interface {
+	ListSessions() ([]*config.SessionConfig, error)
+}
+
+// sessionCollector reports stored sessions by status at scrape time
+type sessionCollector struct {
+	lister sessionLister
+	desc   *prometheus.Desc
+}
+
+func newSessionCollector(lister sessionLister) *sessionCollector {
+	return &sessionCollector{
+		lister: lister,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(metrics.Namespace, "", "sessions"),
+			"Number of sessions by status.",
+			[]string{"status"}, nil,
+		),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *sessionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect implements prometheus.Collector
+func (c *sessionCollector) Collect(ch chan<- prometheus.Metric) {
+	sessions, err := c.lister.ListSessions()
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	// Report every known status so dashboards see zeros rather than gaps
+	counts := map[config.SessionStatus]int{
+		config.StatusPending:  0,
+		config.StatusStarting: 0,
+		config.StatusRunning:  0,
+		config.StatusStopped:  0,
+		config.StatusFailed:   0,
+	}
+	for _, s := range sessions {
+		counts[s.Status]++
+	}
+
+	for status, n := range counts {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(n), string(status))
+	}
+}
@@ -0,0 +1,251 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/metrics"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// Sessions is the subset of the session service used by the API server
+type Sessions interface {
+	ListSessions() ([]*config.SessionConfig, error)
+	LoadSession(name string) (*config.SessionConfig, error)
+	SessionExists(name string) bool
+	GetPod(ctx context.Context, name, namespace string) (*kubernetes.PodStatus, error)
+	DeleteSession(ctx context.Context, name string) error
+	RunAgentWithOptions(ctx context.Context, name, prompt string, opts config.AgentRunOptions) (*config.SessionConfig, error)
+	StreamLogs(ctx context.Context, name string, follow bool, tailLines int64) (io.ReadCloser, error)
+}
+
+// StartFunc starts a session; usecase.StartSession in production
+type StartFunc func(ctx context.Context, opts usecase.StartSessionOptions) (*config.SessionConfig, error)
+
+// Options configures the API server
+type Options struct {
+	Addr           string
+	Token          string // Bearer token required on /api routes (empty = no auth)
+	KubeconfigPath string
+	TemplatesDir   string    // Directory of session templates (<name>.yaml) usable as "config"
+	Start          StartFunc // Defaults to usecase.StartSession
+	Logger         *log.Logger
+}
+
+// Server exposes kodama sessions over a REST/JSON API
+type Server struct {
+	sessions Sessions
+	opts     Options
+	mux      *http.ServeMux
+
+	// starting tracks sessions whose asynchronous start is in progress
+	mu       sync.Mutex
+	starting map[string]error
+	wg       sync.WaitGroup
+	baseCtx  context.Context
+	cancel   context.CancelFunc
+}
+
+// New creates an API server
+func New(sessions Sessions, opts Options) *Server {
+	if opts.Start == nil {
+		opts.Start = usecase.StartSession
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.Default()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		sessions: sessions,
+		opts:     opts,
+		mux:      http.NewServeMux(),
+		starting: make(map[string]error),
+		baseCtx:  ctx,
+		cancel:   cancel,
+	}
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	sessionMetrics := prometheus.NewRegistry()
+	sessionMetrics.MustRegister(newSessionCollector(s.sessions))
+
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.Handle("GET /metrics", metrics.Handler(sessionMetrics))
+	s.mux.Handle("GET /api/v1/sessions", s.auth(s.handleListSessions))
+	s.mux.Handle("POST /api/v1/sessions", s.auth(s.handleCreateSession))
+	s.mux.Handle("GET /api/v1/sessions/{name}", s.auth(s.handleGetSession))
+	s.mux.Handle("DELETE /api/v1/sessions/{name}", s.auth(s.handleDeleteSession))
+	s.mux.Handle("POST /api/v1/sessions/{name}/agent", s.auth(s.handleRunAgent))
+	s.mux.Handle("GET /api/v1/sessions/{name}/logs", s.auth(s.handleLogs))
+}
+
+// Handle registers an additional handler, such as an integration webhook
+// The handler is not wrapped with bearer authentication and must verify requests itself.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Handler returns the HTTP handler for the API
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// ListenAndServe serves the API until ctx is canceled, then shuts down gracefully
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	srv := &http.Server{
+		Addr:              s.opts.Addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		s.cancel()
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := srv.Shutdown(shutdownCtx)
+
+	// Abort in-flight session starts and wait for them to unwind
+	s.cancel()
+	s.wg.Wait()
+
+	if err != nil {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	return nil
+}
+
+// auth wraps a handler with Host checking and with bearer token
+// authentication when a token is configured
+func (s *Server) auth(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.allowedHost(r.Host) {
+			writeError(w, http.StatusForbidden, fmt.Errorf("host %q is not allowed", r.Host))
+			return
+		}
+		if s.opts.Token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.opts.Token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="kodama"`)
+				writeError(w, http.StatusUnauthorized, errors.New("missing or invalid bearer token"))
+				return
+			}
+		}
+		next(w, r)
+	})
+}
+
+// allowedHost reports whether a request addressed to host may reach the API
+// Loopback names and the listen address are allowed, so a DNS rebinding page
+// cannot drive the API through a browser. When listening on every interface
+// any name may reach the server, which is only allowed behind a token.
+func (s *Server) allowedHost(host string) bool {
+	name := hostName(host)
+	if isLoopbackHost(name) {
+		return true
+	}
+
+	bound := hostName(s.opts.Addr)
+	if ip := net.ParseIP(bound); bound == "" || ip != nil && ip.IsUnspecified() {
+		return s.opts.Token != ""
+	}
+	return strings.EqualFold(name, bound)
+}
+
+// hostName returns the host of a host[:port] value, without IPv6 brackets
+func hostName(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return strings.Trim(hostport, "[]")
+}
+
+// GenerateToken returns a random bearer token for the API
+func GenerateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate API token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// startAsync runs a session start in the background, recording failures
+// so they can be reported by GET /api/v1/sessions/{name}
+func (s *Server) startAsync(opts usecase.StartSessionOptions) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err, inProgress := s.starting[opts.Name]; inProgress && err == nil {
+		return false
+	}
+	s.starting[opts.Name] = nil
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		_, err := s.opts.Start(s.baseCtx, opts)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if err != nil {
+			s.opts.Logger.Printf("session %s failed to start: %v", opts.Name, err)
+			s.starting[opts.Name] = err
+			return
+		}
+		delete(s.starting, opts.Name)
+	}()
+
+	return true
+}
+
+// startState reports whether a start is in progress and its failure, if any
+func (s *Server) startState(name string) (inProgress bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err, ok := s.starting[name]
+	return ok && err == nil, err
+}
+
+// IsLoopback reports whether addr binds only to a loopback interface
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	return err == nil && isLoopbackHost(host)
+}
+
+// isLoopbackHost reports whether host names a loopback interface
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,338 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+type fakeSessions struct {
+	sessions map[string]*config.SessionConfig
+	logs     string
+	deleted  []string
+	prompts  []string
+}
+
+func (f *fakeSessions) ListSessions() ([]*config.SessionConfig, error) {
+	var out []*config.SessionConfig
+	for _, s := range f.sessions {
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (f *fakeSessions) LoadSession(name string) (*config.SessionConfig, error) {
+	if s, ok := f.sessions[name]; ok {
+		return s, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeSessions) SessionExists(name string) bool {
+	_, ok := f.sessions[name]
+	return ok
+}
+
+func (f *fakeSessions) GetPod(ctx context.Context, name, namespace string) (*kubernetes.PodStatus, error) {
+	return &kubernetes.PodStatus{Phase: "Running", Ready: true}, nil
+}
+
+func (f *fakeSessions) DeleteSession(ctx context.Context, name string) error {
+	f.deleted = append(f.deleted, name)
+	delete(f.sessions, name)
+	return nil
+}
+
+func (f *fakeSessions) RunAgentWithOptions(ctx context.Context, name, prompt string, opts config.AgentRunOptions) (*config.SessionConfig, error) {
+	f.prompts = append(f.prompts, prompt)
+	s := f.sessions[name]
+	if !opts.IgnoreBudget {
+		if err := s.CheckAgentBudget(); err != nil {
+			return s, err
+		}
+	}
+	s.AgentExecutions = append(s.AgentExecutions, config.AgentExecution{Prompt: prompt, TaskID: "task-1", Status: "running"})
+	return s, nil
+}
+
+func (f *fakeSessions) StreamLogs(ctx context.Context, name string, follow bool, tailLines int64) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(f.logs)), nil
+}
+
+func newFake() *fakeSessions {
+	return &fakeSessions{
+		sessions: map[string]*config.SessionConfig{
+			"demo": {Name: "demo", Namespace: "default", PodName: "kodama-demo", Status: config.StatusRunning},
+		},
+		logs: "line one\nline two\n",
+	}
+}
+
+func do(t *testing.T, h http.Handler, method, path, body, token string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Host = "localhost:8080"
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Routes(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"health", "GET", "/healthz", "", http.StatusOK, `"ok"`},
+		{"list", "GET", "/api/v1/sessions", "", http.StatusOK, `"name":"demo"`},
+		{"get", "GET", "/api/v1/sessions/demo", "", http.StatusOK, `"podPhase":"Running"`},
+		{"get missing", "GET", "/api/v1/sessions/nope", "", http.StatusNotFound, "not found"},
+		{"create missing name", "POST", "/api/v1/sessions", `{"repo":"https://example.com/r"}`, http.StatusBadRequest, "session name is required"},
+		{"create missing repo", "POST", "/api/v1/sessions", `{"name":"x"}`, http.StatusBadRequest, "repository URL is required"},
+		{"create existing", "POST", "/api/v1/sessions", `{"name":"demo","repo":"r"}`, http.StatusConflict, "already exists"},
+		{"create bad expires", "POST", "/api/v1/sessions", `{"name":"x","repo":"r","expires":"soon"}`, http.StatusBadRequest, "invalid expires"},
+		{"create unknown field", "POST", "/api/v1/sessions", `{"name":"x","repo":"r","bogus":1}`, http.StatusBadRequest, "invalid request body"},
+		{"agent", "POST", "/api/v1/sessions/demo/agent", `{"prompt":"fix tests"}`, http.StatusOK, `"taskId":"task-1"`},
+		{"agent empty prompt", "POST", "/api/v1/sessions/demo/agent", `{}`, http.StatusBadRequest, "prompt is required"},
+		{"agent missing session", "POST", "/api/v1/sessions/nope/agent", `{"prompt":"x"}`, http.StatusNotFound, "not found"},
+		{"logs", "GET", "/api/v1/sessions/demo/logs", "", http.StatusOK, "data: line one\n\ndata: line two\n\nevent: end"},
+		{"logs bad tail", "GET", "/api/v1/sessions/demo/logs?tail=-1", "", http.StatusBadRequest, "invalid tail"},
+		{"delete missing", "DELETE", "/api/v1/sessions/nope", "", http.StatusNotFound, "not found"},
+		{"get traversal", "GET", "/api/v1/sessions/..%2Fconfig", "", http.StatusBadRequest, "invalid session name"},
+		{"delete traversal", "DELETE", "/api/v1/sessions/..%2F..%2Fetc", "", http.StatusBadRequest, "invalid session name"},
+		{"agent traversal", "POST", "/api/v1/sessions/..%2Fx/agent", `{"prompt":"x"}`, http.StatusBadRequest, "invalid session name"},
+		{"logs traversal", "GET", "/api/v1/sessions/..%2Fx/logs", "", http.StatusBadRequest, "invalid session name"},
+		{"create invalid name", "POST", "/api/v1/sessions", `{"name":"../x","repo":"r"}`, http.StatusBadRequest, "invalid session name"},
+		{"create config path", "POST", "/api/v1/sessions", `{"name":"x","config":"/etc/kodama.yaml"}`, http.StatusBadRequest, "no templates directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(newFake(), Options{})
+			rec := do(t, s.Handler(), tt.method, tt.path, tt.body, "")
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestServer_Delete(t *testing.T) {
+	fake := newFake()
+	s := New(fake, Options{})
+
+	rec := do(t, s.Handler(), "DELETE", "/api/v1/sessions/demo", "", "")
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, []string{"demo"}, fake.deleted)
+}
+
+func TestServer_CreateSession(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		started []usecase.StartSessionOptions
+	)
+	release := make(chan struct{})
+
+	fake := newFake()
+	fake.sessions["new"] = &config.SessionConfig{Name: "new", Status: config.StatusFailed}
+	s := New(fake, Options{
+		KubeconfigPath: "/tmp/kubeconfig",
+		Start: func(ctx context.Context, opts usecase.StartSessionOptions) (*config.SessionConfig, error) {
+			mu.Lock()
+			started = append(started, opts)
+			mu.Unlock()
+			<-release
+			return nil, errors.New("clone failed")
+		},
+	})
+
+	body := `{"name":"new","repo":"https://example.com/r","branch":"main","expires":"2h"}`
+	rec := do(t, s.Handler(), "POST", "/api/v1/sessions", body, "")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "/api/v1/sessions/new", rec.Header().Get("Location"))
+
+	// A second start while the first is running is rejected
+	rec = do(t, s.Handler(), "POST", "/api/v1/sessions", body, "")
+	assert.Equal(t, http.StatusConflict, rec.Code)
+
+	rec = do(t, s.Handler(), "GET", "/api/v1/sessions/new", "", "")
+	assert.Contains(t, rec.Body.String(), `"status":"Starting"`)
+
+	close(release)
+	s.wg.Wait()
+
+	rec = do(t, s.Handler(), "GET", "/api/v1/sessions/new", "", "")
+	assert.Contains(t, rec.Body.String(), `"status":"Failed"`)
+	assert.Contains(t, rec.Body.String(), "clone failed")
+
+	require.Len(t, started, 1)
+	assert.Equal(t, "main", started[0].Branch)
+	assert.Equal(t, "/tmp/kubeconfig", started[0].KubeconfigPath)
+	assert.Equal(t, "2h0m0s", started[0].Expires.String())
+}
+
+func TestServer_CreateSessionTemplate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "backend.yaml"), nil, 0o600))
+
+	var got usecase.StartSessionOptions
+	s := New(newFake(), Options{
+		TemplatesDir: dir,
+		Start: func(ctx context.Context, opts usecase.StartSessionOptions) (*config.SessionConfig, error) {
+			got = opts
+			return nil, nil
+		},
+	})
+
+	rec := do(t, s.Handler(), "POST", "/api/v1/sessions", `{"name":"new","config":"../backend"}`, "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "invalid template name")
+
+	rec = do(t, s.Handler(), "POST", "/api/v1/sessions", `{"name":"new","config":"backend"}`, "")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	s.wg.Wait()
+	assert.Equal(t, filepath.Join(dir, "backend.yaml"), got.ConfigFile)
+}
+
+func TestServer_AgentBudget(t *testing.T) {
+	fake := newFake()
+	fake.sessions["demo"].Agent.MaxRuns = 1
+	fake.sessions["demo"].AgentUsage.Runs = 1
+	s := New(fake, Options{})
+
+	rec := do(t, s.Handler(), "POST", "/api/v1/sessions/demo/agent", `{"prompt":"x"}`, "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), "agent budget exceeded")
+
+	rec = do(t, s.Handler(), "POST", "/api/v1/sessions/demo/agent", `{"prompt":"x","ignoreBudget":true}`, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestServer_Auth(t *testing.T) {
+	s := New(newFake(), Options{Token: "secret"})
+
+	assert.Equal(t, http.StatusOK, do(t, s.Handler(), "GET", "/healthz", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do(t, s.Handler(), "GET", "/api/v1/sessions", "", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, do(t, s.Handler(), "GET", "/api/v1/sessions", "", "wrong").Code)
+	assert.Equal(t, http.StatusOK, do(t, s.Handler(), "GET", "/api/v1/sessions", "", "secret").Code)
+}
+
+func TestServer_ContentType(t *testing.T) {
+	s := New(newFake(), Options{})
+
+	for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded"} {
+		req := httptest.NewRequest("POST", "/api/v1/sessions/demo/agent", strings.NewReader(`{"prompt":"x"}`))
+		req.Host = "localhost:8080"
+		req.Header.Set("Content-Type", contentType)
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code, contentType)
+	}
+
+	req := httptest.NewRequest("POST", "/api/v1/sessions/demo/agent", strings.NewReader(`{"prompt":"x"}`))
+	req.Host = "localhost:8080"
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestServer_AllowedHost(t *testing.T) {
+	tests := []struct {
+		addr  string
+		token string
+		host  string
+		want  bool
+	}{
+		{"127.0.0.1:8080", "", "localhost:8080", true},
+		{"127.0.0.1:8080", "", "127.0.0.1:8080", true},
+		{"127.0.0.1:8080", "", "[::1]:8080", true},
+		{"127.0.0.1:8080", "", "attacker.example:8080", false},
+		{"10.0.0.5:8080", "", "10.0.0.5:8080", true},
+		{"kodama.internal:8080", "", "KODAMA.internal:8080", true},
+		{"10.0.0.5:8080", "secret", "kodama.example", false},
+		{"0.0.0.0:8080", "", "kodama.example", false},
+		{"0.0.0.0:8080", "secret", "kodama.example", true},
+		{":8080", "secret", "kodama.example", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr+" "+tt.host, func(t *testing.T) {
+			s := New(newFake(), Options{Addr: tt.addr, Token: tt.token})
+			assert.Equal(t, tt.want, s.allowedHost(tt.host))
+		})
+	}
+
+	s := New(newFake(), Options{Addr: "127.0.0.1:8080"})
+	req := httptest.NewRequest("GET", "/api/v1/sessions", nil)
+	req.Host = "attacker.example"
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestGenerateToken(t *testing.T) {
+	a, err := GenerateToken()
+	require.NoError(t, err)
+	b, err := GenerateToken()
+	require.NoError(t, err)
+	assert.Len(t, a, 64)
+	assert.NotEqual(t, a, b)
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"127.0.0.1:8080", true},
+		{"localhost:8080", true},
+		{"[::1]:8080", true},
+		{"0.0.0.0:8080", false},
+		{":8080", false},
+		{"10.0.0.5:8080", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsLoopback(tt.addr))
+		})
+	}
+}
+
+func TestServer_Metrics(t *testing.T) {
+	fake := newFake()
+	fake.sessions["broken"] = &config.SessionConfig{Name: "broken", Status: config.StatusFailed}
+	s := New(fake, Options{Token: "secret"})
+
+	rec := do(t, s.Handler(), "GET", "/metrics", "", "")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `kodama_sessions{status="Running"} 1`)
+	assert.Contains(t, rec.Body.String(), `kodama_sessions{status="Failed"} 1`)
+	assert.Contains(t, rec.Body.String(), `kodama_sessions{status="Stopped"} 0`)
+}
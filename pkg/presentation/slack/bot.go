@@ -0,0 +1,412 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+// maxDiffLines limits the diff summary posted to a thread
+const maxDiffLines = 30
+
+// Sessions is the subset of the session service used by the bot
+type Sessions interface {
+	ListSessions() ([]*config.SessionConfig, error)
+	LoadSession(name string) (*config.SessionConfig, error)
+	SessionExists(name string) bool
+	DeleteSession(ctx context.Context, name string) error
+	RunAgent(ctx context.Context, name, prompt string) (*config.SessionConfig, error)
+	DiffSummary(ctx context.Context, name string) (string, error)
+}
+
+// Poster posts messages to Slack; *Client in production
+type Poster interface {
+	PostMessage(ctx context.Context, channel, threadTS, text string) (string, error)
+}
+
+// StartFunc starts a session; usecase.StartSession in production
+type StartFunc func(ctx context.Context, opts usecase.StartSessionOptions) (*config.SessionConfig, error)
+
+// Options configures the Slack bot
+type Options struct {
+	SigningSecret  string
+	TemplatesDir   string // Directory of session templates (<name>.yaml) usable from 'start'
+	KubeconfigPath string
+	Start          StartFunc // Defaults to usecase.StartSession
+	Logger         *log.Logger
+	Now            func() time.Time // Defaults to time.Now, used for signature checks
+}
+
+// Bot handles /kodama slash commands and reports results in channel threads
+type Bot struct {
+	sessions Sessions
+	poster   Poster
+	opts     Options
+
+	wg      sync.WaitGroup
+	baseCtx context.Context
+	cancel  context.CancelFunc
+}
+
+// slashCommand is a parsed Slack slash command request
+type slashCommand struct {
+	ChannelID string
+	UserID    string
+	Text      string
+}
+
+// NewBot creates a Slack bot
+func NewBot(sessions Sessions, poster Poster, opts Options) *Bot {
+	if opts.Start == nil {
+		opts.Start = usecase.StartSession
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.Default()
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bot{
+		sessions: sessions,
+		poster:   poster,
+		opts:     opts,
+		baseCtx:  ctx,
+		cancel:   cancel,
+	}
+}
+
+// Close cancels in-flight commands and waits for them to finish
+func (b *Bot) Close() {
+	b.cancel()
+	b.wg.Wait()
+}
+
+// ServeHTTP implements http.Handler for the slash command endpoint
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+	if err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if err := VerifySignature(
+		b.opts.SigningSecret,
+		r.Header.Get("X-Slack-Request-Timestamp"),
+		r.Header.Get("X-Slack-Signature"),
+		body,
+		b.opts.Now(),
+	); err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(w, "invalid form body", http.StatusBadRequest)
+		return
+	}
+
+	cmd := slashCommand{
+		ChannelID: form.Get("channel_id"),
+		UserID:    form.Get("user_id"),
+		Text:      strings.TrimSpace(form.Get("text")),
+	}
+
+	// Slack requires a response within 3 seconds; slow work continues in a thread
+	text := b.dispatch(cmd)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = io.WriteString(w, text)
+}
+
+// dispatch runs a command and returns the immediate (ephemeral) reply
+func (b *Bot) dispatch(cmd slashCommand) string {
+	fields := strings.Fields(cmd.Text)
+	if len(fields) == 0 {
+		return usage
+	}
+
+	args := fields[1:]
+
+	// Session names come from any workspace member; reject those that are not
+	// valid before they reach the session store
+	if slices.Contains(sessionCommands, fields[0]) && len(args) > 0 {
+		if err := config.ValidateSessionName(args[0]); err != nil {
+			return "❌ " + err.Error()
+		}
+	}
+
+	switch fields[0] {
+	case "list":
+		return b.list()
+	case "status":
+		if len(args) != 1 {
+			return "Usage: /kodama status <name>"
+		}
+		return b.status(args[0])
+	case "start":
+		if len(args) < 2 {
+			return "Usage: /kodama start <name> <template|repo-url> [prompt]"
+		}
+		return b.start(cmd, args[0], args[1], strings.Join(args[2:], " "))
+	case "run":
+		if len(args) < 2 {
+			return "Usage: /kodama run <name> <prompt>"
+		}
+		return b.run(cmd, args[0], strings.Join(args[1:], " "))
+	case "diff":
+		if len(args) != 1 {
+			return "Usage: /kodama diff <name>"
+		}
+		return b.diff(cmd, args[0])
+	case "delete":
+		if len(args) != 1 {
+			return "Usage: /kodama delete <name>"
+		}
+		return b.delete(cmd, args[0])
+	default:
+		return usage
+	}
+}
+
+// sessionCommands take a session name as their first argument
+var sessionCommands = []string{"status", "start", "run", "diff", "delete"}
+
+const usage = `Usage: /kodama <command>
+  list                                      List sessions
+  status <name>                             Show session status
+  start <name> <template|repo-url> [prompt] Start a session, optionally running a prompt
+  run <name> <prompt>                       Run a coding agent prompt
+  diff <name>                               Show changes in the workspace
+  delete <name>                             Delete a session`
+
+func (b *Bot) list() string {
+	sessions, err := b.sessions.ListSessions()
+	if err != nil {
+		return "❌ " + err.Error()
+	}
+	if len(sessions) == 0 {
+		return "No sessions found."
+	}
+
+	var sb strings.Builder
+	for _, s := range sessions {
+		fmt.Fprintf(&sb, "• *%s* %s", s.Name, s.Status)
+		if s.Repo != "" {
+			fmt.Fprintf(&sb, " — %s@%s", s.Repo, s.Branch)
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (b *Bot) status(name string) string {
+	session, err := b.sessions.LoadSession(name)
+	if err != nil {
+		return fmt.Sprintf("❌ Session '%s' not found", name)
+	}
+	return sessionSummary(session)
+}
+
+func (b *Bot) start(cmd slashCommand, name, source, prompt string) string {
+	// Incomplete sessions are resumed by StartSession
+	if existing, err := b.sessions.LoadSession(name); err == nil && !existing.IsResumable() {
+		return fmt.Sprintf("❌ Session '%s' already exists", name)
+	}
+
+	opts := usecase.StartSessionOptions{
+		Name:           name,
+		Prompt:         prompt,
+		KubeconfigPath: b.opts.KubeconfigPath,
+	}
+	if isRepoURL(source) {
+		opts.Repo = source
+	} else {
+		path, err := b.templatePath(source)
+		if err != nil {
+			return "❌ " + err.Error()
+		}
+		opts.ConfigFile = path
+	}
+
+	b.async(cmd, fmt.Sprintf("🚀 <@%s> is starting session *%s* from `%s`", cmd.UserID, name, source),
+		func(ctx context.Context, reply func(string)) {
+			startedAt := time.Now()
+			session, err := b.opts.Start(ctx, opts)
+			if err != nil {
+				reply(fmt.Sprintf("❌ Failed to start session: %v", err))
+				return
+			}
+
+			reply(fmt.Sprintf("✓ Session *%s* is %s (%s)", name, session.Status, time.Since(startedAt).Round(time.Second)))
+			if prompt != "" {
+				reply(b.completionSummary(ctx, session))
+			}
+		})
+
+	return fmt.Sprintf("⏳ Starting session '%s'...", name)
+}
+
+func (b *Bot) run(cmd slashCommand, name, prompt string) string {
+	if !b.sessions.SessionExists(name) {
+		return fmt.Sprintf("❌ Session '%s' not found", name)
+	}
+
+	b.async(cmd, fmt.Sprintf("🤖 <@%s> ran the agent on *%s*:\n>%s", cmd.UserID, name, prompt),
+		func(ctx context.Context, reply func(string)) {
+			session, err := b.sessions.RunAgent(ctx, name, prompt)
+			if err != nil {
+				reply(fmt.Sprintf("❌ Agent failed: %v", err))
+				return
+			}
+			reply(b.completionSummary(ctx, session))
+		})
+
+	return fmt.Sprintf("⏳ Running agent on '%s'...", name)
+}
+
+func (b *Bot) diff(cmd slashCommand, name string) string {
+	if !b.sessions.SessionExists(name) {
+		return fmt.Sprintf("❌ Session '%s' not found", name)
+	}
+
+	b.async(cmd, fmt.Sprintf("📝 Changes in *%s*", name), func(ctx context.Context, reply func(string)) {
+		reply(b.diffText(ctx, name))
+	})
+
+	return fmt.Sprintf("⏳ Collecting changes in '%s'...", name)
+}
+
+func (b *Bot) delete(cmd slashCommand, name string) string {
+	if !b.sessions.SessionExists(name) {
+		return fmt.Sprintf("❌ Session '%s' not found", name)
+	}
+
+	b.async(cmd, fmt.Sprintf("🗑️ <@%s> is deleting session *%s*", cmd.UserID, name), func(ctx context.Context, reply func(string)) {
+		if err := b.sessions.DeleteSession(ctx, name); err != nil {
+			reply(fmt.Sprintf("❌ Failed to delete session: %v", err))
+			return
+		}
+		reply(fmt.Sprintf("✓ Session *%s* deleted", name))
+	})
+
+	return fmt.Sprintf("⏳ Deleting session '%s'...", name)
+}
+
+// async posts a thread root message to the channel and runs fn in the background,
+// posting its replies into the thread
+func (b *Bot) async(cmd slashCommand, root string, fn func(ctx context.Context, reply func(string))) {
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		ctx := b.baseCtx
+
+		threadTS, err := b.poster.PostMessage(ctx, cmd.ChannelID, "", root)
+		if err != nil {
+			b.opts.Logger.Printf("slack: failed to post message: %v", err)
+			return
+		}
+
+		fn(ctx, func(text string) {
+			if _, err := b.poster.PostMessage(ctx, cmd.ChannelID, threadTS, text); err != nil {
+				b.opts.Logger.Printf("slack: failed to post reply: %v", err)
+			}
+		})
+	}()
+}
+
+// completionSummary reports the last agent execution and the resulting diff
+func (b *Bot) completionSummary(ctx context.Context, session *config.SessionConfig) string {
+	exec := session.GetLastAgentExecution()
+	if exec == nil {
+		return "⚠️ No agent execution recorded"
+	}
+
+	var sb strings.Builder
+	switch {
+	case exec.Status == "failed":
+		fmt.Fprintf(&sb, "❌ Agent failed: %s\n", exec.Error)
+	case exec.Status == config.AgentStatusNeedsReview:
+		action := "need review"
+		if exec.ProtectedReverted {
+			action = "were reverted"
+		}
+		fmt.Fprintf(&sb, "⚠️ Agent changes to protected paths %s: `%s`\n", action, strings.Join(exec.ProtectedChanges, "`, `"))
+	default:
+		fmt.Fprintf(&sb, "✅ Agent %s", exec.Status)
+		if exec.TaskID != "" {
+			fmt.Fprintf(&sb, " (task `%s`)", exec.TaskID)
+		}
+		sb.WriteString("\n")
+	}
+
+	// Runs record their changes; older records fall back to a fresh diff
+	if exec.DiffStat != "" || exec.ChangedFiles != nil {
+		sb.WriteString(formatDiff(exec.DiffStat))
+	} else {
+		sb.WriteString(b.diffText(ctx, session.Name))
+	}
+	return sb.String()
+}
+
+// diffText formats the workspace diff summary for Slack
+func (b *Bot) diffText(ctx context.Context, name string) string {
+	diff, err := b.sessions.DiffSummary(ctx, name)
+	if err != nil {
+		return fmt.Sprintf("⚠️ Could not collect changes: %v", err)
+	}
+	return formatDiff(diff)
+}
+
+// formatDiff formats a diff summary as a Slack code block
+func formatDiff(diff string) string {
+	if diff == "" {
+		return "No changes in the workspace."
+	}
+
+	lines := strings.Split(diff, "\n")
+	if len(lines) > maxDiffLines {
+		omitted := len(lines) - maxDiffLines
+		lines = append(lines[:maxDiffLines], fmt.Sprintf("... %d more line(s)", omitted))
+	}
+	return "```\n" + strings.Join(lines, "\n") + "\n```"
+}
+
+// templatePath resolves a template name to a file in the templates directory
+func (b *Bot) templatePath(name string) (string, error) {
+	if b.opts.TemplatesDir == "" {
+		return "", errors.New("no templates directory configured; pass a repository URL instead")
+	}
+	return config.TemplatePath(b.opts.TemplatesDir, name)
+}
+
+// sessionSummary formats a session for a status reply
+func sessionSummary(s *config.SessionConfig) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%s* — %s\n", s.Name, s.Status)
+	if s.Repo != "" {
+		fmt.Fprintf(&sb, "Repo: %s@%s\n", s.Repo, s.Branch)
+	}
+	fmt.Fprintf(&sb, "Pod: %s/%s", s.Namespace, s.PodName)
+	if exec := s.GetLastAgentExecution(); exec != nil {
+		fmt.Fprintf(&sb, "\nLast agent run: %s (%s)", exec.Status, exec.ExecutedAt.Format(time.RFC822))
+	}
+	return sb.String()
+}
+
+func isRepoURL(s string) bool {
+	return strings.Contains(s, "://") || strings.HasPrefix(s, "git@")
+}
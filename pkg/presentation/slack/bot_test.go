@@ -0,0 +1,267 @@
+package slack
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/usecase"
+)
+
+const testSecret = "signing-secret"
+
+type postedMessage struct {
+	channel  string
+	threadTS string
+	text     string
+}
+
+type fakePoster struct {
+	mu       sync.Mutex
+	messages []postedMessage
+}
+
+func (p *fakePoster) PostMessage(ctx context.Context, channel, threadTS, text string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, postedMessage{channel: channel, threadTS: threadTS, text: text})
+	return "ts-" + strconv.Itoa(len(p.messages)), nil
+}
+
+type fakeSessions struct {
+	sessions map[string]*config.SessionConfig
+	diff     string
+	deleted  []string
+}
+
+func (f *fakeSessions) ListSessions() ([]*config.SessionConfig, error) {
+	var out []*config.SessionConfig
+	for _, s := range f.sessions {
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+func (f *fakeSessions) LoadSession(name string) (*config.SessionConfig, error) {
+	if s, ok := f.sessions[name]; ok {
+		return s, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeSessions) SessionExists(name string) bool {
+	_, ok := f.sessions[name]
+	return ok
+}
+
+func (f *fakeSessions) DeleteSession(ctx context.Context, name string) error {
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+
+func (f *fakeSessions) RunAgent(ctx context.Context, name, prompt string) (*config.SessionConfig, error) {
+	s := f.sessions[name]
+	s.AgentExecutions = append(s.AgentExecutions, config.AgentExecution{Prompt: prompt, TaskID: "task-7", Status: "completed"})
+	return s, nil
+}
+
+func (f *fakeSessions) DiffSummary(ctx context.Context, name string) (string, error) {
+	return f.diff, nil
+}
+
+func newFakeSessions() *fakeSessions {
+	return &fakeSessions{
+		sessions: map[string]*config.SessionConfig{
+			"demo": {Name: "demo", Namespace: "default", PodName: "kodama-demo", Repo: "https://example.com/r", Branch: "main", Status: config.StatusRunning},
+		},
+		diff: " main.go | 2 +-\n 1 file changed, 1 insertion(+), 1 deletion(-)",
+	}
+}
+
+func sign(body string, ts time.Time) (string, string) {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte("v0:" + timestamp + ":" + body))
+	return timestamp, "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func slashRequest(text string, ts time.Time) *http.Request {
+	body := url.Values{"text": {text}, "channel_id": {"C1"}, "user_id": {"U1"}}.Encode()
+	timestamp, signature := sign(body, ts)
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", signature)
+	return req
+}
+
+func TestVerifySignature(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	timestamp, signature := sign("body", now)
+
+	tests := []struct {
+		name      string
+		timestamp string
+		signature string
+		body      string
+		wantErr   error
+	}{
+		{"valid", timestamp, signature, "body", nil},
+		{"tampered body", timestamp, signature, "other", ErrInvalidSignature},
+		{"bad timestamp", "abc", signature, "body", ErrInvalidSignature},
+		{"stale", strconv.FormatInt(now.Add(-10*time.Minute).Unix(), 10), signature, "body", ErrStaleRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(testSecret, tt.timestamp, tt.signature, []byte(tt.body), now)
+			assert.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestBot_ServeHTTP(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		req      *http.Request
+		wantCode int
+		wantBody string
+	}{
+		{"help", slashRequest("", now), http.StatusOK, "Usage: /kodama"},
+		{"list", slashRequest("list", now), http.StatusOK, "*demo* Running"},
+		{"status", slashRequest("status demo", now), http.StatusOK, "Pod: default/kodama-demo"},
+		{"status missing", slashRequest("status nope", now), http.StatusOK, "not found"},
+		{"start existing", slashRequest("start demo https://example.com/r", now), http.StatusOK, "already exists"},
+		{"start without templates dir", slashRequest("start new backend", now), http.StatusOK, "no templates directory"},
+		{"run usage", slashRequest("run demo", now), http.StatusOK, "Usage: /kodama run"},
+		{"stale request", slashRequest("list", now.Add(-time.Hour)), http.StatusUnauthorized, "too old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bot := NewBot(newFakeSessions(), &fakePoster{}, Options{SigningSecret: testSecret})
+			rec := httptest.NewRecorder()
+			bot.ServeHTTP(rec, tt.req)
+			bot.Close()
+
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Contains(t, rec.Body.String(), tt.wantBody)
+		})
+	}
+}
+
+func TestBot_RejectsInvalidSessionNames(t *testing.T) {
+	for _, text := range []string{"delete ../config", "status ../config", "run ../../x prompt", "diff Demo"} {
+		t.Run(text, func(t *testing.T) {
+			sessions := newFakeSessions()
+			bot := NewBot(sessions, &fakePoster{}, Options{SigningSecret: testSecret})
+			rec := httptest.NewRecorder()
+			bot.ServeHTTP(rec, slashRequest(text, time.Now()))
+			bot.Close()
+
+			assert.Contains(t, rec.Body.String(), "invalid session name")
+			assert.Empty(t, sessions.deleted)
+		})
+	}
+}
+
+func TestBot_RunPostsCompletionInThread(t *testing.T) {
+	poster := &fakePoster{}
+	bot := NewBot(newFakeSessions(), poster, Options{SigningSecret: testSecret})
+
+	rec := httptest.NewRecorder()
+	bot.ServeHTTP(rec, slashRequest("run demo fix the flaky test", time.Now()))
+	bot.Close()
+
+	assert.Contains(t, rec.Body.String(), "Running agent")
+	require.Len(t, poster.messages, 2)
+
+	root, reply := poster.messages[0], poster.messages[1]
+	assert.Equal(t, "C1", root.channel)
+	assert.Empty(t, root.threadTS)
+	assert.Contains(t, root.text, "fix the flaky test")
+
+	assert.Equal(t, "ts-1", reply.threadTS)
+	assert.Contains(t, reply.text, "Agent completed (task `task-7`)")
+	assert.Contains(t, reply.text, "main.go | 2 +-")
+}
+
+func TestBot_CompletionSummaryUsesRecordedChanges(t *testing.T) {
+	sessions := newFakeSessions()
+	sessions.diff = "stale"
+	bot := NewBot(sessions, &fakePoster{}, Options{SigningSecret: testSecret})
+	defer bot.Close()
+
+	session := &config.SessionConfig{Name: "demo", AgentExecutions: []config.AgentExecution{{
+		Status: "completed", DiffStat: " api.go | 4 ++--", ChangedFiles: []string{"api.go"},
+	}}}
+
+	summary := bot.completionSummary(context.Background(), session)
+	assert.Contains(t, summary, "api.go | 4 ++--")
+	assert.NotContains(t, summary, "stale")
+}
+
+func TestBot_StartFromTemplate(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "backend.yaml"), []byte("repo: https://example.com/r\n"), 0o600))
+
+	var got usecase.StartSessionOptions
+	poster := &fakePoster{}
+	bot := NewBot(newFakeSessions(), poster, Options{
+		SigningSecret: testSecret,
+		TemplatesDir:  dir,
+		Start: func(ctx context.Context, opts usecase.StartSessionOptions) (*config.SessionConfig, error) {
+			got = opts
+			return &config.SessionConfig{
+				Name:            opts.Name,
+				Status:          config.StatusRunning,
+				AgentExecutions: []config.AgentExecution{{Status: "completed", TaskID: "task-1"}},
+			}, nil
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	bot.ServeHTTP(rec, slashRequest("start new backend add a health endpoint", time.Now()))
+	bot.Close()
+
+	assert.Contains(t, rec.Body.String(), "Starting session 'new'")
+	assert.Equal(t, filepath.Join(dir, "backend.yaml"), got.ConfigFile)
+	assert.Equal(t, "add a health endpoint", got.Prompt)
+
+	require.Len(t, poster.messages, 3)
+	assert.Contains(t, poster.messages[1].text, "Session *new* is Running")
+	assert.Contains(t, poster.messages[2].text, "Agent completed")
+}
+
+func TestBot_TemplatePath(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "ok.yaml"), nil, 0o600))
+	bot := NewBot(newFakeSessions(), &fakePoster{}, Options{TemplatesDir: dir})
+
+	_, err := bot.templatePath("ok")
+	assert.NoError(t, err)
+
+	for _, name := range []string{"missing", "../ok", ".hidden", "a/b"} {
+		_, err := bot.templatePath(name)
+		assert.Error(t, err, name)
+	}
+}
@@ -0,0 +1,112 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultAPIURL is the Slack Web API base URL
+const DefaultAPIURL = "https://slack.com/api"
+
+// maxRequestAge rejects replayed slash command requests
+const maxRequestAge = 5 * time.Minute
+
+var (
+	// ErrInvalidSignature is returned when a request is not signed with the signing secret
+	ErrInvalidSignature = errors.New("invalid slack request signature")
+
+	// ErrStaleRequest is returned when a request timestamp is too old
+	ErrStaleRequest = errors.New("slack request timestamp too old")
+)
+
+// VerifySignature checks a request against Slack's v0 signing scheme
+// See https://api.slack.com/authentication/verifying-requests-from-slack
+func VerifySignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if math.Abs(now.Sub(time.Unix(ts, 0)).Seconds()) > maxRequestAge.Seconds() {
+		return ErrStaleRequest
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = fmt.Fprintf(mac, "v0:%s:", timestamp)
+	_, _ = mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Client posts messages with the Slack Web API
+type Client struct {
+	httpClient *http.Client
+	token      string
+	baseURL    string
+}
+
+// NewClient creates a Slack API client for a bot token
+func NewClient(token string) *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 15 * time.Second},
+		token:      token,
+		baseURL:    DefaultAPIURL,
+	}
+}
+
+// PostMessage posts text to a channel, optionally as a reply in a thread
+// Returns the message timestamp, which identifies the thread for replies.
+func (c *Client) PostMessage(ctx context.Context, channel, threadTS, text string) (string, error) {
+	payload := map[string]string{
+		"channel": channel,
+		"text":    text,
+	}
+	if threadTS != "" {
+		payload["thread_ts"] = threadTS
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/chat.postMessage", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to post slack message: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		TS    string `json:"ts"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode slack response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if !result.OK {
+		return "", fmt.Errorf("slack API error: %s", result.Error)
+	}
+
+	return result.TS, nil
+}
@@ -0,0 +1,191 @@
+// Package table renders aligned, optionally colored tables for command output
+package table
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Color is an ANSI SGR color code
+type Color string
+
+// Colors used for status values
+const (
+	ColorNone   Color = ""
+	ColorRed    Color = "31"
+	ColorGreen  Color = "32"
+	ColorYellow Color = "33"
+	ColorGray   Color = "90"
+)
+
+// columnGap separates columns
+const columnGap = "  "
+
+// ellipsis marks truncated values
+const ellipsis = "…"
+
+// Column describes a table column
+type Column struct {
+	Header   string
+	MaxWidth int                      // Longer values are truncated with "…" (0 = unlimited)
+	Color    func(value string) Color // Optional per-value color, e.g. StatusColor
+}
+
+// Options controls how a table is rendered
+type Options struct {
+	NoHeaders bool // Omit the header row, e.g. for piping into other tools
+	Color     bool // Color values; see ColorEnabled
+}
+
+// Table is a set of rows rendered with aligned columns
+type Table struct {
+	columns []Column
+	rows    [][]string
+}
+
+// New creates a table with the given columns
+func New(columns ...Column) *Table {
+	return &Table{columns: columns}
+}
+
+// AddRow appends a row. Missing cells are rendered empty and extra cells are dropped.
+func (t *Table) AddRow(cells ...string) {
+	row := make([]string, len(t.columns))
+	for i := range row {
+		if i < len(cells) {
+			row[i] = truncate(cells[i], t.columns[i].MaxWidth)
+		}
+	}
+	t.rows = append(t.rows, row)
+}
+
+// Render writes the table to w
+func (t *Table) Render(w io.Writer, opts Options) error {
+	widths := make([]int, len(t.columns))
+	if !opts.NoHeaders {
+		for i, col := range t.columns {
+			widths[i] = utf8.RuneCountInString(col.Header)
+		}
+	}
+	for _, row := range t.rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	var b strings.Builder
+	writeRow := func(cells []string, colorize bool) {
+		for i, cell := range cells {
+			last := i == len(cells)-1
+			text := cell
+			if !last {
+				text += strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+			}
+			if colorize && opts.Color && t.columns[i].Color != nil {
+				if c := t.columns[i].Color(cell); c != ColorNone {
+					// Color only the value so that padding stays uncolored
+					text = "\x1b[" + string(c) + "m" + cell + "\x1b[0m" + text[len(cell):]
+				}
+			}
+			b.WriteString(text)
+			if !last {
+				b.WriteString(columnGap)
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	if !opts.NoHeaders {
+		headers := make([]string, len(t.columns))
+		for i, col := range t.columns {
+			headers[i] = col.Header
+		}
+		writeRow(headers, false)
+	}
+	for _, row := range t.rows {
+		writeRow(row, true)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// truncate shortens s to at most width runes, ending with "…"
+func truncate(s string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:width-1]) + ellipsis
+}
+
+// ColorEnabled reports whether output written to w should be colored: w must be
+// a terminal, NO_COLOR (https://no-color.org) must be unset and TERM not "dumb"
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// StatusColor colors session, pod and agent status values
+func StatusColor(status string) Color {
+	switch strings.ToLower(status) {
+	case "running", "completed", "succeeded", "active":
+		return ColorGreen
+	case "pending", "starting", "degraded":
+		return ColorYellow
+	case "failed", "error":
+		return ColorRed
+	case "stopped", "-":
+		return ColorGray
+	default:
+		return ColorNone
+	}
+}
+
+// RelativeTime formats t relative to now, e.g. "2h ago" or "in 3d"
+// A zero time is rendered as "-".
+func RelativeTime(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	d := now.Sub(t)
+	if d < 0 {
+		return "in " + Duration(-d)
+	}
+	if d < time.Minute {
+		return "just now"
+	}
+	return Duration(d) + " ago"
+}
+
+// Duration formats d in the largest whole unit, kubectl style (45s, 3m, 2h, 5d, 2w, 4mo)
+func Duration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	case d < 7*24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dw", int(d.Hours()/(24*7)))
+	default:
+		return fmt.Sprintf("%dmo", int(d.Hours()/(24*30)))
+	}
+}
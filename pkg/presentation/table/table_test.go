@@ -0,0 +1,79 @@
+package table
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRender_Aligned(t *testing.T) {
+	tbl := New(Column{Header: "NAME"}, Column{Header: "STATUS", Color: StatusColor}, Column{Header: "AGE"})
+	tbl.AddRow("my-work", "Running", "2h ago")
+	tbl.AddRow("x", "Failed", "just now")
+
+	var buf bytes.Buffer
+	require.NoError(t, tbl.Render(&buf, Options{}))
+
+	assert.Equal(t, ""+
+		"NAME     STATUS   AGE\n"+
+		"my-work  Running  2h ago\n"+
+		"x        Failed   just now\n", buf.String())
+}
+
+func TestRender_NoHeaders(t *testing.T) {
+	tbl := New(Column{Header: "NAMESPACE"}, Column{Header: "NAME"})
+	tbl.AddRow("dev", "a")
+
+	var buf bytes.Buffer
+	require.NoError(t, tbl.Render(&buf, Options{NoHeaders: true}))
+
+	assert.Equal(t, "dev  a\n", buf.String(), "headers do not widen columns")
+}
+
+func TestRender_Color(t *testing.T) {
+	tbl := New(Column{Header: "STATUS", Color: StatusColor}, Column{Header: "NAME"})
+	tbl.AddRow("Running", "a")
+	tbl.AddRow("Custom", "b")
+
+	var buf bytes.Buffer
+	require.NoError(t, tbl.Render(&buf, Options{Color: true}))
+
+	assert.Equal(t, ""+
+		"STATUS   NAME\n"+
+		"\x1b[32mRunning\x1b[0m  a\n"+
+		"Custom   b\n", buf.String())
+}
+
+func TestRender_Truncate(t *testing.T) {
+	tbl := New(Column{Header: "PATH", MaxWidth: 10}, Column{Header: "SYNC"})
+	tbl.AddRow("https://github.com/org/repo", "-")
+
+	var buf bytes.Buffer
+	require.NoError(t, tbl.Render(&buf, Options{}))
+
+	assert.Equal(t, ""+
+		"PATH        SYNC\n"+
+		"https://g…  -\n", buf.String())
+}
+
+func TestColorEnabled(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	assert.False(t, ColorEnabled(&bytes.Buffer{}), "non-terminal writers are not colored")
+
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, ColorEnabled(&bytes.Buffer{}))
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "-", RelativeTime(time.Time{}, now))
+	assert.Equal(t, "just now", RelativeTime(now.Add(-10*time.Second), now))
+	assert.Equal(t, "5m ago", RelativeTime(now.Add(-5*time.Minute), now))
+	assert.Equal(t, "2h ago", RelativeTime(now.Add(-2*time.Hour), now))
+	assert.Equal(t, "3d ago", RelativeTime(now.Add(-72*time.Hour), now))
+	assert.Equal(t, "in 1h", RelativeTime(now.Add(90*time.Minute), now))
+}
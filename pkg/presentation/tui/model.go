@@ -0,0 +1,221 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/application/service"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/shellutil"
+)
+
+// refreshInterval controls how often pod status is polled
+const refreshInterval = 3 * time.Second
+
+// Options configures the session browser
+type Options struct {
+	KodamaBinary   string // Path to this binary, used to run attach/delete
+	KubeconfigPath string
+}
+
+// sessionRow is a session together with its live pod status
+type sessionRow struct {
+	session *config.SessionConfig
+	pod     *kubernetes.PodStatus
+	podErr  error
+}
+
+type (
+	refreshMsg struct {
+		err  error
+		rows []sessionRow
+	}
+	tickMsg     time.Time
+	execDoneMsg struct {
+		err    error
+		action string
+	}
+)
+
+// Model is the bubbletea model for the session browser
+type Model struct {
+	err            error
+	sessionService *service.SessionService
+	opts           Options
+	message        string
+	rows           []sessionRow
+	cursor         int
+	width          int
+	loading        bool
+}
+
+// NewModel creates a session browser model
+func NewModel(sessionService *service.SessionService, opts Options) Model {
+	return Model{
+		sessionService: sessionService,
+		opts:           opts,
+		loading:        true,
+	}
+}
+
+// Run starts the session browser in the alternate screen
+func Run(sessionService *service.SessionService, opts Options) error {
+	p := tea.NewProgram(NewModel(sessionService, opts), tea.WithAltScreen())
+	_, err := p.Run()
+	return err
+}
+
+// Init implements tea.Model
+func (m Model) Init() tea.Cmd {
+	return tea.Batch(m.refresh(), tick())
+}
+
+// Update implements tea.Model
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		return m, nil
+
+	case refreshMsg:
+		m.loading = false
+		m.err = msg.err
+		if msg.err == nil {
+			m.rows = msg.rows
+			if m.cursor >= len(m.rows) {
+				m.cursor = max(len(m.rows)-1, 0)
+			}
+		}
+		return m, nil
+
+	case tickMsg:
+		return m, tea.Batch(m.refresh(), tick())
+
+	case execDoneMsg:
+		if msg.err != nil {
+			m.message = fmt.Sprintf("%s failed: %v", msg.action, msg.err)
+		} else {
+			m.message = ""
+		}
+		return m, m.refresh()
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+// handleKey processes key presses
+func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c", "esc":
+		return m, tea.Quit
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.rows)-1 {
+			m.cursor++
+		}
+	case "r":
+		m.message = "Refreshing..."
+		return m, m.refresh()
+	}
+
+	row := m.selected()
+	if row == nil {
+		return m, nil
+	}
+	name := row.session.Name
+
+	switch msg.String() {
+	case "enter", "a":
+		return m, m.runKodama("attach", "attach", name, "--tty")
+	case "t":
+		return m, m.runKodama("open ttyd", "attach", name)
+	case "l":
+		return m, m.viewLogs(row.session)
+	case "d":
+		return m, m.runKodama("delete", "delete", name)
+	}
+
+	return m, nil
+}
+
+// selected returns the row under the cursor
+func (m Model) selected() *sessionRow {
+	if m.cursor < 0 || m.cursor >= len(m.rows) {
+		return nil
+	}
+	return &m.rows[m.cursor]
+}
+
+// refresh loads sessions and their pod status
+func (m Model) refresh() tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		statuses, err := m.sessionService.ListSessionStatuses(ctx, config.SessionQuery{})
+		if err != nil {
+			return refreshMsg{err: err}
+		}
+
+		rows := make([]sessionRow, 0, len(statuses))
+		for _, status := range statuses {
+			rows = append(rows, sessionRow{session: status.Session, pod: status.Pod, podErr: status.PodErr})
+		}
+		return refreshMsg{rows: rows}
+	}
+}
+
+// runKodama suspends the UI and runs a kodama subcommand in the terminal
+func (m Model) runKodama(action string, args ...string) tea.Cmd {
+	if m.opts.KubeconfigPath != "" {
+		args = append([]string{"--kubeconfig", m.opts.KubeconfigPath}, args...)
+	}
+
+	//#nosec G204 -- re-executes this binary with session name from config store
+	cmd := exec.Command(m.opts.KodamaBinary, args...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return execDoneMsg{action: action, err: err}
+	})
+}
+
+// viewLogs shows the log of the latest agent task in a pager
+func (m Model) viewLogs(session *config.SessionConfig) tea.Cmd {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less -R"
+	}
+
+	kubectl := "kubectl"
+	if m.opts.KubeconfigPath != "" {
+		kubectl += " --kubeconfig " + shellutil.Quote(m.opts.KubeconfigPath)
+	}
+
+	script := fmt.Sprintf("%s exec -n %s %s -c %s -- sh -c %s 2>&1 | %s",
+		kubectl, shellutil.Quote(session.Namespace), shellutil.Quote(session.PodName), kubernetes.MainContainerName,
+		shellutil.Quote(agent.LatestRunLogScript(1000, false)), pager)
+
+	//#nosec G204 -- kubectl exec with session data from config store
+	cmd := exec.Command("sh", "-c", script)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return execDoneMsg{action: "logs", err: err}
+	})
+}
+
+func tick() tea.Cmd {
+	return tea.Tick(refreshInterval, func(t time.Time) tea.Msg {
+		return tickMsg(t)
+	})
+}
@@ -0,0 +1,94 @@
+package tui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func testRows() []sessionRow {
+	now := time.Now()
+	return []sessionRow{
+		{
+			session: &config.SessionConfig{
+				Name:      "alpha",
+				Namespace: "dev",
+				PodName:   "kodama-alpha",
+				Repo:      "https://github.com/org/alpha",
+				Branch:    "kodama/alpha",
+				Status:    config.StatusRunning,
+				CreatedAt: now,
+				AgentExecutions: []config.AgentExecution{
+					{ExecutedAt: now, Prompt: "add tests", TaskID: "task-1", Status: "running"},
+				},
+			},
+			pod: &kubernetes.PodStatus{Phase: corev1.PodRunning, Ready: true},
+		},
+		{
+			session: &config.SessionConfig{
+				Name:      "beta",
+				Namespace: "dev",
+				PodName:   "kodama-beta",
+				Status:    config.StatusStopped,
+				CreatedAt: now,
+			},
+			podErr: errors.New("pod not found"),
+		},
+	}
+}
+
+func TestModel_Navigation(t *testing.T) {
+	m := NewModel(nil, Options{})
+	updated, _ := m.Update(refreshMsg{rows: testRows()})
+	m = updated.(Model)
+
+	assert.False(t, m.loading)
+	assert.Equal(t, 0, m.cursor)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	assert.Equal(t, 1, m.cursor)
+
+	// Cursor stops at the last row
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("j")})
+	m = updated.(Model)
+	assert.Equal(t, 1, m.cursor)
+
+	updated, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	m = updated.(Model)
+	assert.Equal(t, 0, m.cursor)
+
+	// Cursor is clamped when sessions disappear
+	m.cursor = 1
+	updated, _ = m.Update(refreshMsg{rows: testRows()[:1]})
+	m = updated.(Model)
+	assert.Equal(t, 0, m.cursor)
+}
+
+func TestModel_View(t *testing.T) {
+	m := NewModel(nil, Options{})
+	updated, _ := m.Update(refreshMsg{rows: testRows()})
+	m = updated.(Model)
+
+	view := m.View()
+	assert.Contains(t, view, "alpha")
+	assert.Contains(t, view, "beta")
+	assert.Contains(t, view, "Ready")
+	assert.Contains(t, view, "NotFound")
+	assert.Contains(t, view, "kodama/alpha")
+	assert.Contains(t, view, "task-1")
+}
+
+func TestModel_Quit(t *testing.T) {
+	m := NewModel(nil, Options{})
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	assert.NotNil(t, cmd)
+	assert.IsType(t, tea.QuitMsg{}, cmd())
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/illumination-k/kodama/pkg/presentation/table"
+)
+
+var (
+	titleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	headerStyle   = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("8"))
+	selectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("0")).Background(lipgloss.Color("14"))
+	detailStyle   = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1)
+	labelStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	errorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	helpStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// View implements tea.Model
+func (m Model) View() string {
+	var b strings.Builder
+
+	b.WriteString(titleStyle.Render("🌳 kodama sessions"))
+	b.WriteString("\n\n")
+
+	switch {
+	case m.err != nil:
+		b.WriteString(errorStyle.Render("Error: " + m.err.Error()))
+		b.WriteString("\n")
+	case m.loading:
+		b.WriteString("Loading sessions...\n")
+	case len(m.rows) == 0:
+		b.WriteString("No sessions found. Start one with: kubectl kodama start <name>\n")
+	default:
+		b.WriteString(m.renderTable())
+		b.WriteString("\n")
+		b.WriteString(m.renderDetail())
+		b.WriteString("\n")
+	}
+
+	if m.message != "" {
+		b.WriteString("\n" + errorStyle.Render(m.message) + "\n")
+	}
+
+	b.WriteString("\n")
+	b.WriteString(helpStyle.Render("↑/↓ move • enter attach • t ttyd • l logs • d delete • r refresh • q quit"))
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// renderTable renders the session list
+func (m Model) renderTable() string {
+	var b strings.Builder
+	format := "%-24s %-10s %-12s %-16s %-6s"
+
+	b.WriteString(headerStyle.Render(fmt.Sprintf(format, "NAME", "STATUS", "POD", "NAMESPACE", "AGE")))
+	b.WriteString("\n")
+
+	for i, row := range m.rows {
+		line := fmt.Sprintf(format,
+			truncate(row.session.Name, 24),
+			row.session.Status,
+			podPhase(row),
+			truncate(row.session.Namespace, 16),
+			table.Duration(time.Since(row.session.CreatedAt)),
+		)
+		if i == m.cursor {
+			line = selectedStyle.Render(line)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderDetail renders the detail pane for the selected session
+func (m Model) renderDetail() string {
+	row := m.selected()
+	if row == nil {
+		return ""
+	}
+	s := row.session
+
+	lines := []string{
+		field("Name", s.Name),
+		field("Pod", fmt.Sprintf("%s (%s)", s.PodName, podPhase(*row))),
+	}
+
+	if s.Repo != "" {
+		lines = append(lines, field("Repo", s.Repo))
+		branch := s.Branch
+		if s.CommitHash != "" {
+			branch += " @ " + shortHash(s.CommitHash)
+		}
+		lines = append(lines, field("Branch", branch))
+	}
+	if s.Sync.Enabled {
+		lines = append(lines, field("Sync", s.Sync.LocalPath))
+	}
+	if s.ExpiresAt != nil {
+		lines = append(lines, field("Expires", s.ExpiresAt.Local().Format(time.RFC822)))
+	}
+
+	if exec := s.GetLastAgentExecution(); exec != nil {
+		agent := exec.Status
+		if exec.TaskID != "" {
+			agent += " (task " + exec.TaskID + ")"
+		}
+		lines = append(lines, field("Agent", agent))
+		if exec.Prompt != "" {
+			lines = append(lines, field("Prompt", truncate(exec.Prompt, 60)))
+		}
+		if exec.Error != "" {
+			lines = append(lines, field("Error", exec.Error))
+		}
+	} else {
+		lines = append(lines, field("Agent", "-"))
+	}
+
+	if row.podErr != nil {
+		lines = append(lines, errorStyle.Render(row.podErr.Error()))
+	}
+
+	style := detailStyle
+	if m.width > 4 {
+		style = style.Width(min(m.width-4, 100))
+	}
+	return style.Render(strings.Join(lines, "\n"))
+}
+
+func field(label, value string) string {
+	return labelStyle.Render(fmt.Sprintf("%-8s", label)) + " " + value
+}
+
+// podPhase summarizes the live pod status
+func podPhase(row sessionRow) string {
+	switch {
+	case row.podErr != nil || row.pod == nil:
+		return "NotFound"
+	case row.pod.Phase == corev1.PodRunning && row.pod.Ready:
+		return "Ready"
+	default:
+		return string(row.pod.Phase)
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 8 {
+		return hash[:8]
+	}
+	return hash
+}
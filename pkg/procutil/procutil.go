@@ -0,0 +1,44 @@
+// Package procutil runs local helper processes (kubectl, tar, sops, ...) so
+// that cancelling their context stops them and is reported as a cancellation
+package procutil
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// WaitDelay bounds how long Wait waits for a killed command's output pipes to
+// close, in case a process that escaped the kill still holds them
+const WaitDelay = 5 * time.Second
+
+// CommandContext is exec.CommandContext for non-interactive commands. The
+// command runs in its own process group and cancelling ctx kills the whole
+// group, so the processes it started (kubectl's auth plugins, tar, sh) do not
+// outlive it. Interactive commands must keep the terminal's process group and
+// use exec.CommandContext.
+func CommandContext(ctx context.Context, name string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, name, args...)
+	setProcessGroup(cmd)
+	cmd.WaitDelay = WaitDelay
+	return cmd
+}
+
+// Kill stops a command started with CommandContext, with its process group,
+// and waits for it. It is for giving up on a command before ctx is done.
+func Kill(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	_ = cmd.Cancel()
+	_ = cmd.Wait()
+}
+
+// Err returns ctx.Err() when err is set and ctx is done, so a command killed
+// by a cancellation reports context.Canceled instead of "signal: killed"
+func Err(ctx context.Context, err error) error {
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
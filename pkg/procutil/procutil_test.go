@@ -0,0 +1,70 @@
+package procutil
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErr(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	failed := errors.New("signal: killed")
+
+	assert.NoError(t, Err(ctx, nil))
+	assert.Equal(t, failed, Err(ctx, failed))
+
+	cancel()
+	assert.NoError(t, Err(ctx, nil))
+	assert.ErrorIs(t, Err(ctx, failed), context.Canceled)
+}
+
+func TestCommandContext_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := CommandContext(ctx, "sh", "-c", "exit 0").Run()
+	assert.ErrorIs(t, Err(ctx, err), context.Canceled)
+}
+
+// TestCommandContext_KillsProcessGroup checks a child the command started in
+// the background dies with it, rather than keeping its output pipe open
+func TestCommandContext_KillsProcessGroup(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process groups are not used on windows")
+	}
+
+	marker := filepath.Join(t.TempDir(), "child-survived")
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	cmd := CommandContext(ctx, "sh", "-c", `(sleep 1; touch "$1") & wait`, "sh", marker)
+	began := time.Now()
+	out, err := cmd.CombinedOutput()
+	require.Error(t, err, string(out))
+	assert.ErrorIs(t, Err(ctx, err), context.DeadlineExceeded)
+	assert.Less(t, time.Since(began), WaitDelay, "Wait returned only after WaitDelay")
+
+	time.Sleep(1500 * time.Millisecond)
+	_, statErr := os.Stat(marker)
+	assert.True(t, os.IsNotExist(statErr), "background child outlived the cancelled command")
+}
+
+func TestKill(t *testing.T) {
+	cmd := CommandContext(context.Background(), "sleep", "30")
+	require.NoError(t, cmd.Start())
+
+	began := time.Now()
+	Kill(cmd)
+	assert.Less(t, time.Since(began), WaitDelay)
+	assert.NotNil(t, cmd.ProcessState, "Kill waits for the command")
+
+	// Commands that never started are left alone
+	Kill(CommandContext(context.Background(), "true"))
+}
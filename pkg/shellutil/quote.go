@@ -0,0 +1,10 @@
+// Package shellutil builds POSIX shell snippets that run in session pods
+package shellutil
+
+import "strings"
+
+// Quote wraps s in single quotes, escaping embedded single quotes, so a shell
+// reads it back as one literal word
+func Quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
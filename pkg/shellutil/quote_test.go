@@ -0,0 +1,33 @@
+package shellutil
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "''"},
+		{"plain", "'plain'"},
+		{"with space", "'with space'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME `id` \"x\"", "'$HOME `id` \"x\"'"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Quote(tt.in))
+	}
+}
+
+func TestQuote_RoundTripsThroughShell(t *testing.T) {
+	in := "a'b $(echo x) \\n; rm -rf /"
+	out, err := exec.Command("sh", "-c", "printf %s "+Quote(in)).Output()
+	require.NoError(t, err)
+	assert.Equal(t, in, string(out))
+}
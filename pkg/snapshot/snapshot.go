@@ -0,0 +1,156 @@
+// Package snapshot archives a session workspace to a local file or object store
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// WorkspacePath is the directory archived by a snapshot
+const WorkspacePath = "/workspace"
+
+// IsRemote reports whether a snapshot location is an object store URL
+func IsRemote(location string) bool {
+	return strings.HasPrefix(location, "s3://") || strings.HasPrefix(location, "gs://")
+}
+
+// Destination returns where a snapshot is written
+// An explicit output wins; otherwise the snapshot is named after the session
+// and timestamp, under the configured store or in the current directory.
+func Destination(output, store, sessionName string, now time.Time) string {
+	if output != "" {
+		return output
+	}
+
+	fileName := fmt.Sprintf("%s-%s.tar.gz", sessionName, now.UTC().Format("20060102-150405"))
+	if store == "" {
+		return fileName
+	}
+	return strings.TrimSuffix(store, "/") + "/" + fileName
+}
+
+// CreateArgs returns the tar command run in the pod to archive the workspace
+func CreateArgs(excludes []string) []string {
+	args := []string{"tar", "czf", "-"}
+	for _, pattern := range excludes {
+		args = append(args, "--exclude="+pattern)
+	}
+	return append(args, "-C", WorkspacePath, ".")
+}
+
+// ExtractArgs returns the tar command run in the pod to restore the workspace
+func ExtractArgs() []string {
+	return []string{"tar", "xzf", "-", "-C", WorkspacePath}
+}
+
+// Create opens a writer for a new snapshot
+// Object store uploads are streamed through the aws or gcloud CLI; Close waits
+// for the upload to finish and reports its error.
+func Create(ctx context.Context, location string) (io.WriteCloser, error) {
+	if !IsRemote(location) {
+		if dir := filepath.Dir(location); dir != "." {
+			if err := os.MkdirAll(dir, 0o750); err != nil {
+				return nil, fmt.Errorf("failed to create directory %s: %w", dir, err)
+			}
+		}
+		file, err := os.Create(location) // #nosec G304 -- user-chosen output path
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s: %w", location, err)
+		}
+		return file, nil
+	}
+
+	cmd := uploadCommand(ctx, location)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", cmd.Args[0], err)
+	}
+	return &commandWriter{WriteCloser: stdin, cmd: cmd, stderr: &stderr}, nil
+}
+
+// Open opens an existing snapshot for reading
+func Open(ctx context.Context, location string) (io.ReadCloser, error) {
+	if !IsRemote(location) {
+		file, err := os.Open(location) // #nosec G304 -- user-chosen snapshot path
+		if err != nil {
+			return nil, fmt.Errorf("failed to open snapshot: %w", err)
+		}
+		return file, nil
+	}
+
+	cmd := downloadCommand(ctx, location)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create pipe: %w", err)
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", cmd.Args[0], err)
+	}
+	return &commandReader{ReadCloser: stdout, cmd: cmd, stderr: &stderr}, nil
+}
+
+// uploadCommand streams stdin to an object store URL
+func uploadCommand(ctx context.Context, location string) *exec.Cmd {
+	if strings.HasPrefix(location, "gs://") {
+		//#nosec G204 -- gcloud with user-configured snapshot location
+		return procutil.CommandContext(ctx, "gcloud", "storage", "cp", "-", location)
+	}
+	//#nosec G204 -- aws CLI with user-configured snapshot location
+	return procutil.CommandContext(ctx, "aws", "s3", "cp", "-", location)
+}
+
+// downloadCommand streams an object store URL to stdout
+func downloadCommand(ctx context.Context, location string) *exec.Cmd {
+	if strings.HasPrefix(location, "gs://") {
+		//#nosec G204 -- gcloud with user-provided snapshot location
+		return procutil.CommandContext(ctx, "gcloud", "storage", "cat", location)
+	}
+	//#nosec G204 -- aws CLI with user-provided snapshot location
+	return procutil.CommandContext(ctx, "aws", "s3", "cp", location, "-")
+}
+
+// commandWriter closes stdin of an upload command and waits for it
+type commandWriter struct {
+	io.WriteCloser
+	cmd    *exec.Cmd
+	stderr *strings.Builder
+}
+
+func (w *commandWriter) Close() error {
+	closeErr := w.WriteCloser.Close()
+	if err := w.cmd.Wait(); err != nil {
+		return fmt.Errorf("upload failed: %w: %s", err, strings.TrimSpace(w.stderr.String()))
+	}
+	return closeErr
+}
+
+// commandReader waits for a download command when closed
+type commandReader struct {
+	io.ReadCloser
+	cmd    *exec.Cmd
+	stderr *strings.Builder
+}
+
+func (r *commandReader) Close() error {
+	// Drain so the command can exit even if the reader stopped early
+	_, _ = io.Copy(io.Discard, r.ReadCloser)
+	if err := r.cmd.Wait(); err != nil {
+		return fmt.Errorf("download failed: %w: %s", err, strings.TrimSpace(r.stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package snapshot
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRemote(t *testing.T) {
+	tests := []struct {
+		location string
+		want     bool
+	}{
+		{location: "s3://bucket/snap.tar.gz", want: true},
+		{location: "gs://bucket/snap.tar.gz", want: true},
+		{location: "snap.tar.gz", want: false},
+		{location: "/tmp/s3://odd.tar.gz", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRemote(tt.location))
+		})
+	}
+}
+
+func TestDestination(t *testing.T) {
+	now := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		output string
+		store  string
+		want   string
+	}{
+		{name: "explicit output", output: "out.tar.gz", store: "s3://bucket", want: "out.tar.gz"},
+		{name: "local default", want: "work-20250304-050607.tar.gz"},
+		{name: "store", store: "s3://bucket/snapshots/", want: "s3://bucket/snapshots/work-20250304-050607.tar.gz"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Destination(tt.output, tt.store, "work", now))
+		})
+	}
+}
+
+func TestCreateArgs(t *testing.T) {
+	args := CreateArgs([]string{"node_modules", "*.log"})
+
+	assert.Equal(t, []string{
+		"tar", "czf", "-", "--exclude=node_modules", "--exclude=*.log", "-C", WorkspacePath, ".",
+	}, args)
+}
+
+func TestCreateOpen_LocalFile(t *testing.T) {
+	ctx := context.Background()
+	location := filepath.Join(t.TempDir(), "nested", "snap.tar.gz")
+
+	w, err := Create(ctx, location)
+	require.NoError(t, err)
+	_, err = w.Write([]byte("archive"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	r, err := Open(ctx, location)
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "archive", string(data))
+}
+
+func TestOpen_MissingFile(t *testing.T) {
+	_, err := Open(context.Background(), filepath.Join(t.TempDir(), "missing.tar.gz"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
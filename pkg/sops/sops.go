@@ -0,0 +1,80 @@
+package sops
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Format represents a SOPS file format
+type Format string
+
+const (
+	FormatYAML   Format = "yaml"
+	FormatJSON   Format = "json"
+	FormatDotenv Format = "dotenv"
+)
+
+// ErrSopsNotInstalled is returned when an encrypted file is found but sops is not on PATH
+var ErrSopsNotInstalled = errors.New("sops binary not found in PATH (install from https://github.com/getsops/sops)")
+
+// Metadata markers written by sops into encrypted files
+var (
+	yamlMarker   = regexp.MustCompile(`(?m)^sops:\s*$`)
+	jsonMarker   = regexp.MustCompile(`"sops"\s*:\s*\{`)
+	dotenvMarker = regexp.MustCompile(`(?m)^sops_(version|mac|lastmodified)=`)
+)
+
+// IsEncrypted reports whether data contains SOPS metadata
+func IsEncrypted(data []byte) bool {
+	return yamlMarker.Match(data) || jsonMarker.Match(data) || dotenvMarker.Match(data)
+}
+
+// FormatForPath infers the SOPS format from a file extension
+// Anything that is not YAML or JSON is treated as dotenv
+func FormatForPath(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML
+	case ".json":
+		return FormatJSON
+	default:
+		return FormatDotenv
+	}
+}
+
+// Decrypt decrypts a SOPS-encrypted file using the sops CLI
+// The plaintext is read from stdout and never written to disk.
+// Key selection (age, KMS, PGP) follows the user's sops configuration.
+func Decrypt(ctx context.Context, path string) ([]byte, error) {
+	if _, err := exec.LookPath("sops"); err != nil {
+		return nil, ErrSopsNotInstalled
+	}
+
+	format := string(FormatForPath(path))
+
+	//#nosec G204 -- sops with user-provided config/env file path
+	cmd := exec.CommandContext(ctx, "sops", "--decrypt",
+		"--input-type", format,
+		"--output-type", format,
+		path,
+	)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to decrypt %s with sops: %s: %w", path, strings.TrimSpace(stderr.String()), err)
+	}
+
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,60 @@
+package sops
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsEncrypted(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want bool
+	}{
+		{
+			name: "plain yaml",
+			data: "namespace: dev\nimage: ubuntu\n",
+			want: false,
+		},
+		{
+			name: "sops yaml",
+			data: "namespace: ENC[AES256_GCM,data:abc=,type:str]\nsops:\n    age:\n        - recipient: age1xyz\n    version: 3.9.0\n",
+			want: true,
+		},
+		{
+			name: "sops json",
+			data: `{"token": "ENC[AES256_GCM,data:abc=]", "sops": {"version": "3.9.0"}}`,
+			want: true,
+		},
+		{
+			name: "plain json mentioning sops",
+			data: `{"tool": "sops"}`,
+			want: false,
+		},
+		{
+			name: "sops dotenv",
+			data: "API_KEY=ENC[AES256_GCM,data:abc=,type:str]\nsops_version=3.9.0\nsops_mac=ENC[...]\n",
+			want: true,
+		},
+		{
+			name: "plain dotenv",
+			data: "API_KEY=secret\nSOPS_AGE_KEY_FILE=/tmp/key\n",
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsEncrypted([]byte(tt.data)))
+		})
+	}
+}
+
+func TestFormatForPath(t *testing.T) {
+	assert.Equal(t, FormatYAML, FormatForPath(".kodama.yaml"))
+	assert.Equal(t, FormatYAML, FormatForPath("config.YML"))
+	assert.Equal(t, FormatJSON, FormatForPath("secrets.json"))
+	assert.Equal(t, FormatDotenv, FormatForPath(".env"))
+	assert.Equal(t, FormatDotenv, FormatForPath(".env.local"))
+}
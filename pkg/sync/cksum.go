@@ -0,0 +1,108 @@
+package sync
+
+// The POSIX cksum CRC-32 (polynomial 0x04C11DB7, MSB first, no reflection),
+// rolled over a fixed-size window for delta transfers. CRCs are polynomials
+// over GF(2) modulo crcPoly, so the contribution of the byte leaving the
+// window and the length cksum appends can both be removed arithmetically.
+
+// crcPoly is the CRC-32 generator polynomial without its x^32 term
+const crcPoly = 0x04C11DB7
+
+// crcXInverse is x^-1 modulo the generator: (x^32 + crcPoly - 1) / x
+const crcXInverse = 0x82608EDB
+
+var crcTable = func() [256]uint32 {
+	var table [256]uint32
+	for i := range table {
+		c := uint32(i) << 24 //#nosec G115 -- i < 256
+		for range 8 {
+			if c&0x80000000 != 0 {
+				c = c<<1 ^ crcPoly
+			} else {
+				c <<= 1
+			}
+		}
+		table[i] = c
+	}
+	return table
+}()
+
+// crcUpdate feeds data into crc
+func crcUpdate(crc uint32, data []byte) uint32 {
+	for _, b := range data {
+		crc = crc<<8 ^ crcTable[byte(crc>>24)^b]
+	}
+	return crc
+}
+
+// rawCRC returns the CRC of data without cksum's length suffix and complement
+func rawCRC(data []byte) uint32 {
+	return crcUpdate(0, data)
+}
+
+// cksumLength returns the length suffix cksum appends: the size in as few
+// bytes as needed, least significant first
+func cksumLength(size int64) []byte {
+	var suffix []byte
+	for ; size > 0; size >>= 8 {
+		suffix = append(suffix, byte(size))
+	}
+	return suffix
+}
+
+// Cksum returns the checksum printed by the POSIX cksum command for data
+func Cksum(data []byte) uint32 {
+	return ^crcUpdate(rawCRC(data), cksumLength(int64(len(data))))
+}
+
+// rawCRCFromCksum recovers rawCRC of a block of size bytes from its cksum:
+// cksum = ^(raw·x^(8n) + crc(length suffix)) for an n-byte suffix
+func rawCRCFromCksum(sum uint32, size int64) uint32 {
+	suffix := cksumLength(size)
+	withoutSuffix := ^sum ^ rawCRC(suffix)
+	return gfMul(withoutSuffix, gfPow(crcXInverse, uint64(8*len(suffix))))
+}
+
+// crcOutTable returns, for every byte value, its contribution to the CRC of a
+// window once window more bytes follow it
+func crcOutTable(window int64) *[256]uint32 {
+	shift := gfPow(2, uint64(8*window)) //#nosec G115 -- window is a positive block size
+	var table [256]uint32
+	for i := range table {
+		table[i] = gfMul(crcTable[i], shift)
+	}
+	return &table
+}
+
+// rollCRC moves a window's CRC one byte forward, dropping out and adding in
+func rollCRC(crc uint32, out, in byte, outTable *[256]uint32) uint32 {
+	return crc<<8 ^ crcTable[byte(crc>>24)^in] ^ outTable[out]
+}
+
+// gfMul multiplies two polynomials modulo the generator
+func gfMul(a, b uint32) uint32 {
+	var p uint32
+	for i := 31; i >= 0; i-- {
+		if p&0x80000000 != 0 {
+			p = p<<1 ^ crcPoly
+		} else {
+			p <<= 1
+		}
+		if b&(1<<i) != 0 {
+			p ^= a
+		}
+	}
+	return p
+}
+
+// gfPow raises a polynomial to the power n modulo the generator
+func gfPow(a uint32, n uint64) uint32 {
+	result := uint32(1)
+	for ; n > 0; n >>= 1 {
+		if n&1 != 0 {
+			result = gfMul(result, a)
+		}
+		a = gfMul(a, a)
+	}
+	return result
+}
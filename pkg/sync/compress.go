@@ -0,0 +1,268 @@
+package sync
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/illumination-k/kodama/pkg/metrics"
+	"github.com/illumination-k/kodama/pkg/procutil"
+	"github.com/illumination-k/kodama/pkg/sync/exclude"
+)
+
+// Compression selects how tar streams pushed to the pod are compressed
+type Compression string
+
+const (
+	// CompressionAuto uses zstd when the pod has it and gzip otherwise
+	CompressionAuto Compression = ""
+
+	// CompressionNone sends a plain tar stream
+	CompressionNone Compression = "none"
+
+	// CompressionGzip compresses with gzip, extracted by tar -z in the pod
+	CompressionGzip Compression = "gzip"
+
+	// CompressionZstd compresses with zstd, which must be installed in the pod
+	CompressionZstd Compression = "zstd"
+)
+
+// ParseCompression validates a sync.compression setting ("" or "auto" selects CompressionAuto)
+func ParseCompression(s string) (Compression, error) {
+	switch c := Compression(s); c {
+	case CompressionAuto, CompressionNone, CompressionGzip, CompressionZstd:
+		return c, nil
+	case "auto":
+		return CompressionAuto, nil
+	default:
+		return "", fmt.Errorf("unknown sync compression %q (want none, gzip or zstd)", s)
+	}
+}
+
+// podHasZstd caches whether each pod has a zstd binary, keyed by
+// namespace/pod/container
+var podHasZstd sync.Map
+
+// hasZstd reports whether zstd is installed in the pod container opts selects
+// Tests replace it to avoid running kubectl.
+var hasZstd = func(ctx context.Context, opts Options, namespace, podName string) bool {
+	key := namespace + "/" + podName + "/" + opts.Container
+	if found, ok := podHasZstd.Load(key); ok {
+		return found.(bool)
+	}
+
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	found := procutil.CommandContext(ctx, "kubectl", opts.kubectlArgs("exec", "-n", namespace, podName, "--",
+		"sh", "-c", "command -v zstd")...).Run() == nil
+	// A cancelled check says nothing about the pod
+	if ctx.Err() == nil {
+		podHasZstd.Store(key, found)
+	}
+	return found
+}
+
+// resolveCompression returns the compression to push to the pod with
+func resolveCompression(ctx context.Context, opts Options, namespace, podName string) Compression {
+	c := opts.Compression
+	if c != CompressionAuto {
+		return c
+	}
+	if hasZstd(ctx, opts, namespace, podName) {
+		return CompressionZstd
+	}
+	return CompressionGzip
+}
+
+// extractCommand returns the command extracting a c-compressed tar stream from
+// stdin into remotePath in the pod
+func extractCommand(c Compression, remotePath string) []string {
+	switch c {
+	case CompressionNone:
+		return []string{"tar", "xf", "-", "-C", remotePath}
+	case CompressionZstd:
+		return []string{"sh", "-c", extractPipeline(c, `"$1"`), "sh", remotePath}
+	default:
+		return []string{"tar", "xzf", "-", "-C", remotePath}
+	}
+}
+
+// extractPipeline returns the shell pipeline extracting a c-compressed tar
+// stream from stdin into dir, a quoted shell word
+func extractPipeline(c Compression, dir string) string {
+	switch c {
+	case CompressionNone:
+		return "tar xf - -C " + dir
+	case CompressionZstd:
+		return "zstd -dcq | tar xf - -C " + dir
+	default:
+		return "tar xzf - -C " + dir
+	}
+}
+
+// compressWriter wraps w in the encoder for c
+func compressWriter(w io.Writer, c Compression) (io.WriteCloser, error) {
+	switch c {
+	case CompressionNone:
+		return nopWriteCloser{w}, nil
+	case CompressionZstd:
+		return zstd.NewWriter(w, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	default:
+		return gzip.NewWriter(w), nil
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// WriteArchive writes a c-compressed tar archive of root to w. With files set,
+// only those paths (relative to root) are archived; otherwise the whole tree
+// is walked, skipping .git and paths excludeMgr excludes. Like tar, it keeps
+// directories, regular files and symlinks with their mode, owner and mtime.
+func WriteArchive(w io.Writer, root string, files []string, excludeMgr *exclude.Manager, c Compression) error {
+	cw, err := compressWriter(w, c)
+	if err != nil {
+		return fmt.Errorf("failed to create %s encoder: %w", c, err)
+	}
+	tw := tar.NewWriter(cw)
+
+	if files != nil {
+		for _, rel := range files {
+			if err := addArchiveEntry(tw, root, filepath.Join(root, filepath.FromSlash(rel))); err != nil {
+				return err
+			}
+		}
+	} else {
+		err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if path == root {
+				return nil
+			}
+			if info.IsDir() {
+				if info.Name() == ".git" || (excludeMgr != nil && excludeMgr.ShouldExcludeDir(path)) {
+					return filepath.SkipDir
+				}
+			} else if excludeMgr != nil && excludeMgr.ShouldExclude(path) {
+				return nil
+			}
+			return addArchiveEntry(tw, root, path)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive %s: %w", root, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return cw.Close()
+}
+
+// addArchiveEntry writes the header, and for regular files the content, of path
+func addArchiveEntry(tw *tar.Writer, root, path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return err
+	}
+
+	var link string
+	switch mode := info.Mode(); {
+	case mode&os.ModeSymlink != 0:
+		if link, err = os.Readlink(path); err != nil {
+			return err
+		}
+	case mode.IsDir(), mode.IsRegular():
+	default:
+		// Sockets, devices and pipes are not synced
+		return nil
+	}
+
+	header, err := tar.FileInfoHeader(info, link)
+	if err != nil {
+		return fmt.Errorf("failed to archive %s: %w", path, err)
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return err
+	}
+	header.Name = "./" + filepath.ToSlash(rel)
+	if info.IsDir() {
+		header.Name += "/"
+	}
+	// GNU tar reads PAX headers, which keep long names and sub-second mtimes
+	header.Format = tar.FormatPAX
+
+	if err := tw.WriteHeader(header); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", path, err)
+	}
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+
+	f, err := os.Open(path) //#nosec G304 -- path is inside the synced directory
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("failed to archive %s: %w", path, err)
+	}
+	return nil
+}
+
+// pushArchive streams a tar archive of root (or files under it) into
+// remotePath in the pod, compressed as opts selects
+func pushArchive(ctx context.Context, opts Options, root, remotePath, namespace, podName string, files []string, excludeMgr *exclude.Manager) error {
+	c := resolveCompression(ctx, opts, namespace, podName)
+
+	args := append([]string{"exec", "-i", "-n", namespace, podName, "--"}, extractCommand(c, remotePath)...)
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	untarCmd := procutil.CommandContext(ctx, "kubectl", opts.kubectlArgs(args...)...)
+
+	pr, pw := io.Pipe()
+	untarCmd.Stdin = &metrics.CountingReader{R: pr}
+	var stderr strings.Builder
+	untarCmd.Stderr = &stderr
+
+	if err := untarCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start kubectl exec: %w", err)
+	}
+
+	archiveErr := make(chan error, 1)
+	go func() {
+		err := WriteArchive(pw, root, files, excludeMgr, c)
+		_ = pw.CloseWithError(err)
+		archiveErr <- err
+	}()
+
+	waitErr := untarCmd.Wait()
+	// Unblock the archive writer if the extraction ended early
+	_ = pr.Close()
+	if ctx.Err() != nil {
+		<-archiveErr
+		return ctx.Err()
+	}
+	// A closed pipe only means kubectl exited first; its error says why
+	if err := <-archiveErr; err != nil && !errors.Is(err, io.ErrClosedPipe) {
+		return err
+	}
+	if waitErr != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("kubectl exec failed: %w (output: %s)", waitErr, msg)
+		}
+		return fmt.Errorf("kubectl exec failed: %w", waitErr)
+	}
+	return nil
+}
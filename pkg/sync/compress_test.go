@@ -0,0 +1,281 @@
+package sync
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/sync/exclude"
+)
+
+// readArchive decompresses a WriteArchive stream and returns its entries by name
+func readArchive(t *testing.T, data []byte, c Compression) map[string]string {
+	t.Helper()
+
+	var r io.Reader = bytes.NewReader(data)
+	switch c {
+	case CompressionGzip:
+		gz, err := gzip.NewReader(r)
+		require.NoError(t, err)
+		r = gz
+	case CompressionZstd:
+		zr, err := zstd.NewReader(r)
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	}
+
+	entries := map[string]string{}
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		content, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		if header.Typeflag == tar.TypeSymlink {
+			content = []byte("-> " + header.Linkname)
+		}
+		entries[header.Name] = string(content)
+	}
+	return entries
+}
+
+func writeTree(t testing.TB, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestWriteArchive(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{
+		"main.go":             "package main\n",
+		"pkg/util.go":         "package pkg\n",
+		"debug.log":           "noise",
+		"node_modules/dep.js": "dep",
+		".git/HEAD":           "ref: refs/heads/main\n",
+	})
+	require.NoError(t, os.Symlink("main.go", filepath.Join(root, "link.go")))
+
+	excludeMgr, err := exclude.NewManager(exclude.Config{BasePath: root, Patterns: []string{"*.log", "node_modules/"}})
+	require.NoError(t, err)
+
+	for _, c := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		t.Run(string(c), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, WriteArchive(&buf, root, nil, excludeMgr, c))
+
+			assert.Equal(t, map[string]string{
+				"./main.go":     "package main\n",
+				"./pkg/":        "",
+				"./pkg/util.go": "package pkg\n",
+				"./link.go":     "-> main.go",
+			}, readArchive(t, buf.Bytes(), c))
+		})
+	}
+}
+
+func TestWriteArchive_Files(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"a.txt": "a", "sub/b.txt": "b", "c.txt": "c"})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteArchive(&buf, root, []string{"a.txt", "sub/b.txt"}, nil, CompressionZstd))
+	assert.Equal(t, map[string]string{"./a.txt": "a", "./sub/b.txt": "b"}, readArchive(t, buf.Bytes(), CompressionZstd))
+
+	err := WriteArchive(io.Discard, root, []string{"missing.txt"}, nil, CompressionGzip)
+	assert.Error(t, err)
+}
+
+// TestWriteArchive_SystemTar checks the archives extract with the tar and
+// zstd commands the pod runs
+func TestWriteArchive_SystemTar(t *testing.T) {
+	if _, err := exec.LookPath("tar"); err != nil {
+		t.Skip("tar not installed")
+	}
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"main.go": "package main\n", "deep/er/file.txt": "x"})
+
+	for _, c := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		t.Run(string(c), func(t *testing.T) {
+			if _, err := exec.LookPath("zstd"); c == CompressionZstd && err != nil {
+				t.Skip("zstd not installed")
+			}
+
+			dest := t.TempDir()
+			command := extractCommand(c, dest)
+			cmd := exec.Command(command[0], command[1:]...) //#nosec G204 -- test command
+			pr, pw := io.Pipe()
+			cmd.Stdin = pr
+			go func() { _ = pw.CloseWithError(WriteArchive(pw, root, nil, nil, c)) }()
+			out, err := cmd.CombinedOutput()
+			require.NoError(t, err, string(out))
+
+			content, err := os.ReadFile(filepath.Join(dest, "deep/er/file.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, "x", string(content))
+		})
+	}
+}
+
+// stubKubectl puts a kubectl that runs script first on PATH
+func stubKubectl(t *testing.T, script string) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubectl"), []byte("#!/bin/sh\n"+script+"\n"), 0o700)) //#nosec G306 -- test executable
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func TestPushArchive_Canceled(t *testing.T) {
+	// kubectl hangs without reading the archive, like an unreachable pod
+	stubKubectl(t, "sleep 30 & wait")
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"main.go": "package main\n"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	began := time.Now()
+	err := pushArchive(ctx, Options{Compression: CompressionGzip}, root, "/workspace", "dev", "kodama-work", nil, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(began), 5*time.Second)
+}
+
+func TestSyncManagerWithOptions_UsesCompression(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "args")
+	stubKubectl(t, `echo "$@" > `+argsFile+`; cat > /dev/null`)
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"main.go": "package main\n"})
+
+	mgr := NewSyncManagerWithOptions(Options{Compression: CompressionNone})
+	require.NoError(t, mgr.InitialSync(context.Background(), root, "dev", "kodama-work", nil))
+
+	args, err := os.ReadFile(argsFile) //#nosec G304 -- test file
+	require.NoError(t, err)
+	assert.Equal(t, "exec -i -n dev kodama-work -- tar xf - -C /workspace\n", string(args))
+}
+
+func TestSyncManagerWithOptions_WritesRemoteRootInContainer(t *testing.T) {
+	argsFile := filepath.Join(t.TempDir(), "args")
+	stubKubectl(t, `echo "$@" >> `+argsFile+`; cat > /dev/null`)
+
+	root := t.TempDir()
+	writeTree(t, root, map[string]string{"main.go": "package main\n"})
+
+	mgr := NewSyncManagerWithOptions(Options{
+		RemoteRoot:  "/workspace/services/payments",
+		Container:   "workspace-sync",
+		Compression: CompressionNone,
+	})
+	require.NoError(t, mgr.InitialSync(context.Background(), root, "dev", "kodama-work", nil))
+
+	args, err := os.ReadFile(argsFile) //#nosec G304 -- test file
+	require.NoError(t, err)
+	assert.Equal(t, "exec -c workspace-sync -n dev kodama-work -- mkdir -p /workspace/services/payments\n"+
+		"exec -c workspace-sync -i -n dev kodama-work -- tar xf - -C /workspace/services/payments\n", string(args))
+}
+
+func TestHasZstd_CanceledIsNotCached(t *testing.T) {
+	stubKubectl(t, "exit 0")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.False(t, hasZstd(ctx, Options{}, "dev", "kodama-cancel"))
+	assert.True(t, hasZstd(context.Background(), Options{}, "dev", "kodama-cancel"), "the cancelled check was cached")
+}
+
+func TestParseCompression(t *testing.T) {
+	for input, want := range map[string]Compression{
+		"": CompressionAuto, "auto": CompressionAuto, "none": CompressionNone, "gzip": CompressionGzip, "zstd": CompressionZstd,
+	} {
+		got, err := ParseCompression(input)
+		require.NoError(t, err)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ParseCompression("brotli")
+	assert.Error(t, err)
+}
+
+func TestResolveCompression(t *testing.T) {
+	orig := hasZstd
+	t.Cleanup(func() { hasZstd = orig })
+
+	podHas := false
+	hasZstd = func(ctx context.Context, opts Options, namespace, podName string) bool { return podHas }
+
+	ctx := context.Background()
+	assert.Equal(t, CompressionGzip, resolveCompression(ctx, Options{}, "dev", "kodama-work"))
+	podHas = true
+	assert.Equal(t, CompressionZstd, resolveCompression(ctx, Options{}, "dev", "kodama-work"))
+	assert.Equal(t, CompressionNone, resolveCompression(ctx, Options{Compression: CompressionNone}, "dev", "kodama-work"))
+	assert.Equal(t, CompressionGzip, resolveCompression(ctx, Options{Compression: CompressionGzip}, "dev", "kodama-work"))
+}
+
+func TestExtractCommand(t *testing.T) {
+	assert.Equal(t, []string{"tar", "xzf", "-", "-C", "/workspace"}, extractCommand(CompressionGzip, "/workspace"))
+	assert.Equal(t, []string{"tar", "xf", "-", "-C", "/workspace"}, extractCommand(CompressionNone, "/workspace"))
+	assert.Equal(t, "/workspace", extractCommand(CompressionZstd, "/workspace")[4])
+	assert.True(t, strings.HasPrefix(extractCommand(CompressionZstd, "/workspace")[2], "zstd -dcq |"))
+}
+
+// BenchmarkWriteArchive archives a source tree of 2,000 files, reporting the
+// compressed size of each setting
+func BenchmarkWriteArchive(b *testing.B) {
+	root := b.TempDir()
+	files := map[string]string{}
+	for i := 0; i < 2000; i++ {
+		var content strings.Builder
+		for line := 0; line < 100; line++ {
+			fmt.Fprintf(&content, "func handler%d_%d(w http.ResponseWriter, r *http.Request) { log.Printf(%q, r.URL) }\n", i, line, "request")
+		}
+		files[fmt.Sprintf("svc-%d/pkg/file-%d.go", i%50, i)] = content.String()
+	}
+	writeTree(b, root, files)
+
+	for _, c := range []Compression{CompressionNone, CompressionGzip, CompressionZstd} {
+		b.Run(string(c), func(b *testing.B) {
+			var size int64
+			for i := 0; i < b.N; i++ {
+				counter := &countingWriter{}
+				if err := WriteArchive(counter, root, nil, nil, c); err != nil {
+					b.Fatal(err)
+				}
+				size = counter.n
+			}
+			b.ReportMetric(float64(size), "bytes/archive")
+		})
+	}
+}
+
+type countingWriter struct{ n int64 }
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,185 @@
+package sync
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/procutil"
+	"github.com/illumination-k/kodama/pkg/sync/exclude"
+)
+
+// LocalManifest hashes every file below root that the sync would push
+func LocalManifest(root string, excludeCfg *exclude.Config) (Manifest, error) {
+	return ScanManifest(root, excludeCfg, nil)
+}
+
+// ParseRemoteManifest parses sha256sum output run in /workspace, optionally
+// followed by a "--" line and a "<mtime> <path>" line per file. Files the sync
+// excludes are dropped so they are never reported as conflicts.
+func ParseRemoteManifest(output, root string, excludeCfg *exclude.Config) (Manifest, error) {
+	excludeMgr, err := newConflictExcludeManager(excludeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := Manifest{}
+	inTimes := false
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if line == "--" {
+			inTimes = true
+			continue
+		}
+
+		if !inTimes {
+			hash, path, ok := strings.Cut(line, "  ")
+			if !ok {
+				return nil, fmt.Errorf("unexpected checksum line: %q", line)
+			}
+			state := manifest[cleanRemotePath(path)]
+			state.Hash = hash
+			manifest[cleanRemotePath(path)] = state
+			continue
+		}
+
+		secs, path, ok := strings.Cut(line, " ")
+		if !ok {
+			return nil, fmt.Errorf("unexpected stat line: %q", line)
+		}
+		unix, err := strconv.ParseInt(secs, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected stat line: %q", line)
+		}
+		if state, exists := manifest[cleanRemotePath(path)]; exists {
+			state.ModTime = time.Unix(unix, 0)
+			manifest[cleanRemotePath(path)] = state
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if excludeMgr != nil {
+		for path := range manifest {
+			if excludeMgr.ShouldExclude(filepath.Join(root, filepath.FromSlash(path))) {
+				delete(manifest, path)
+			}
+		}
+	}
+	return manifest, nil
+}
+
+// DetectConflicts returns the files a push from local would overwrite even
+// though they were changed in the pod. base holds the hashes recorded after the
+// last sync: a file whose pod copy no longer matches it was changed in the pod.
+// Files missing from base fall back to comparing modification times.
+func DetectConflicts(local, remote Manifest, base map[string]string) []string {
+	var conflicts []string
+	for path, remoteState := range remote {
+		localState, exists := local[path]
+		if !exists || localState.Hash == remoteState.Hash {
+			// A push only overwrites files that exist locally, with other content
+			continue
+		}
+
+		if baseHash, synced := base[path]; synced {
+			if remoteState.Hash != baseHash {
+				conflicts = append(conflicts, path)
+			}
+			continue
+		}
+		if remoteState.ModTime.After(localState.ModTime) {
+			conflicts = append(conflicts, path)
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// PullFiles copies files from remotePath in the pod into localPath, overwriting local copies
+func PullFiles(ctx context.Context, opts Options, localPath, remotePath, namespace, podName string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	// Create tar archive of the files in the pod
+	tarArgs := append([]string{"exec", "-n", namespace, podName, "--", "tar", "czf", "-", "-C", remotePath, "--"}, files...)
+	tarCmd := procutil.CommandContext(ctx, "kubectl", opts.kubectlArgs(tarArgs...)...)
+
+	// Extract locally
+	untarCmd := procutil.CommandContext(ctx, "tar", "xzf", "-", "-C", localPath)
+
+	pipe, err := tarCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	untarCmd.Stdin = pipe
+
+	if err := tarCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start kubectl exec: %w", err)
+	}
+	if err := untarCmd.Start(); err != nil {
+		procutil.Kill(tarCmd)
+		return fmt.Errorf("failed to start tar: %w", err)
+	}
+
+	tarErr := tarCmd.Wait()
+	untarErr := untarCmd.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if tarErr != nil {
+		return fmt.Errorf("kubectl exec failed: %w", tarErr)
+	}
+	if untarErr != nil {
+		return fmt.Errorf("tar command failed: %w", untarErr)
+	}
+	return nil
+}
+
+// newConflictExcludeManager builds the exclude manager for excludeCfg, or nil without one
+func newConflictExcludeManager(excludeCfg *exclude.Config) (*exclude.Manager, error) {
+	if excludeCfg == nil {
+		return nil, nil
+	}
+	excludeMgr, err := exclude.NewManager(*excludeCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create exclude manager: %w", err)
+	}
+	return excludeMgr, nil
+}
+
+// cleanRemotePath turns a find path like "./src/main.go" into "src/main.go"
+func cleanRemotePath(path string) string {
+	return strings.TrimPrefix(path, "./")
+}
+
+// hashFile returns the hex sha256 of the file at path
+func hashFile(path string) (string, error) {
+	// #nosec G304 -- path comes from walking the sync root
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,124 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/sync/exclude"
+)
+
+func TestDetectConflicts(t *testing.T) {
+	older := time.Unix(1_700_000_000, 0)
+	newer := older.Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		local  Manifest
+		remote Manifest
+		base   map[string]string
+		want   []string
+	}{
+		{
+			name:   "changed on both sides",
+			local:  Manifest{"main.go": {Hash: "local", ModTime: newer}},
+			remote: Manifest{"main.go": {Hash: "remote", ModTime: older}},
+			base:   map[string]string{"main.go": "base"},
+			want:   []string{"main.go"},
+		},
+		{
+			name:   "changed only locally",
+			local:  Manifest{"main.go": {Hash: "local", ModTime: older}},
+			remote: Manifest{"main.go": {Hash: "base", ModTime: newer}},
+			base:   map[string]string{"main.go": "base"},
+			want:   nil,
+		},
+		{
+			name:   "changed only in the pod",
+			local:  Manifest{"main.go": {Hash: "base", ModTime: newer}},
+			remote: Manifest{"main.go": {Hash: "remote", ModTime: older}},
+			base:   map[string]string{"main.go": "base"},
+			want:   []string{"main.go"},
+		},
+		{
+			name:   "same content on both sides",
+			local:  Manifest{"main.go": {Hash: "same", ModTime: older}},
+			remote: Manifest{"main.go": {Hash: "same", ModTime: newer}},
+			base:   map[string]string{"main.go": "base"},
+			want:   nil,
+		},
+		{
+			name:   "deleted locally is never overwritten",
+			local:  Manifest{},
+			remote: Manifest{"old.go": {Hash: "remote", ModTime: newer}},
+			base:   map[string]string{"old.go": "base"},
+			want:   nil,
+		},
+		{
+			name:   "deleted in the pod is recreated without conflict",
+			local:  Manifest{"main.go": {Hash: "local", ModTime: newer}},
+			remote: Manifest{},
+			base:   map[string]string{"main.go": "base"},
+			want:   nil,
+		},
+		{
+			name:   "no base falls back to newer pod copy",
+			local:  Manifest{"a.go": {Hash: "l", ModTime: older}, "b.go": {Hash: "l", ModTime: newer}},
+			remote: Manifest{"a.go": {Hash: "r", ModTime: newer}, "b.go": {Hash: "r", ModTime: older}},
+			base:   map[string]string{},
+			want:   []string{"a.go"},
+		},
+		{
+			name: "conflicts are sorted",
+			local: Manifest{
+				"z.go": {Hash: "l"},
+				"a.go": {Hash: "l"},
+			},
+			remote: Manifest{
+				"z.go": {Hash: "r"},
+				"a.go": {Hash: "r"},
+			},
+			base: map[string]string{"z.go": "b", "a.go": "b"},
+			want: []string{"a.go", "z.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, DetectConflicts(tt.local, tt.remote, tt.base))
+		})
+	}
+}
+
+func TestParseRemoteManifest(t *testing.T) {
+	output := "aaa  ./main.go\nbbb  ./node_modules/x.js\n--\n1700000000 ./main.go\n1700000001 ./node_modules/x.js\n"
+	excludeCfg := &exclude.Config{BasePath: "/src", Patterns: []string{"node_modules/"}}
+
+	manifest, err := ParseRemoteManifest(output, "/src", excludeCfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, Manifest{"main.go": {Hash: "aaa", ModTime: time.Unix(1_700_000_000, 0)}}, manifest)
+}
+
+func TestParseRemoteManifest_Malformed(t *testing.T) {
+	_, err := ParseRemoteManifest("not a checksum line\n", "/src", nil)
+	assert.Error(t, err)
+}
+
+func TestLocalManifest(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.go"), []byte("package main\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, ".git"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("ref\n"), 0o600))
+
+	manifest, err := LocalManifest(root, nil)
+	require.NoError(t, err)
+
+	require.Len(t, manifest, 1)
+	// sha256 of "package main\n"
+	assert.Equal(t, "df1d036cbbf3df46e2045071e082245ece204c7f53ecf0a4e022bff9bb228f47", manifest["main.go"].Hash)
+}
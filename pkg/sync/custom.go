@@ -29,30 +29,34 @@ func (c *CustomDirSyncManager) SyncCustomDirs(
 	namespace, podName string,
 	globalConfig *config.GlobalConfig,
 ) error {
+	out := OutputFor(ctx)
 	if len(customDirs) == 0 {
 		return nil
 	}
 
 	// Expand recursive entries into individual subdirectory syncs
-	expandedDirs, err := c.expandCustomDirs(customDirs, globalConfig)
+	expandedDirs, err := c.expandCustomDirs(filterCustomDirs(customDirs, (*config.CustomDirSync).Pushes), globalConfig)
 	if err != nil {
 		return fmt.Errorf("failed to expand custom directories: %w", err)
 	}
+	if len(expandedDirs) == 0 {
+		return nil
+	}
 
-	fmt.Printf("🔄 Syncing %d custom director%s...\n", len(expandedDirs), pluralize(len(expandedDirs)))
+	fmt.Fprintf(out, "🔄 Syncing %d custom director%s...\n", len(expandedDirs), pluralize(len(expandedDirs)))
 
 	successCount := 0
 	for i, customDir := range expandedDirs {
 		// Validate custom directory config
 		if err := customDir.Validate(); err != nil {
-			fmt.Printf("⚠️  Warning: Skipping custom directory %d: %v\n", i+1, err)
+			fmt.Fprintf(out, "⚠️  Warning: Skipping custom directory %d: %v\n", i+1, err)
 			continue
 		}
 
 		// Resolve source path
 		resolvedSource, err := customDir.ResolveSource()
 		if err != nil {
-			fmt.Printf("⚠️  Warning: Failed to resolve source path '%s': %v\n", customDir.Source, err)
+			fmt.Fprintf(out, "⚠️  Warning: Failed to resolve source path '%s': %v\n", customDir.Source, err)
 			continue
 		}
 
@@ -68,12 +72,19 @@ func (c *CustomDirSyncManager) SyncCustomDirs(
 			podName,
 			excludeCfg,
 		); err != nil {
-			fmt.Printf("⚠️  Warning: Failed to sync '%s' to '%s': %v\n",
+			fmt.Fprintf(out, "⚠️  Warning: Failed to sync '%s' to '%s': %v\n",
 				customDir.Source, customDir.Destination, err)
 			continue
 		}
 
-		fmt.Printf("✓ Synced: %s → %s\n", customDir.Source, customDir.Destination)
+		// Fix ownership and mode, e.g. 0600 for SSH keys
+		if customDir.Chown != "" || customDir.Chmod != "" {
+			if err := c.syncMgr.ApplyOwnership(ctx, customDir.Destination, namespace, podName, customDir.Chown, customDir.Chmod); err != nil {
+				fmt.Fprintf(out, "⚠️  Warning: Failed to set ownership of '%s': %v\n", customDir.Destination, err)
+			}
+		}
+
+		fmt.Fprintf(out, "✓ Synced: %s → %s\n", customDir.Source, customDir.Destination)
 		successCount++
 	}
 
@@ -81,12 +92,72 @@ func (c *CustomDirSyncManager) SyncCustomDirs(
 		return fmt.Errorf("failed to sync any custom directories")
 	}
 
-	fmt.Printf("✓ Successfully synced %d/%d custom director%s\n",
+	fmt.Fprintf(out, "✓ Successfully synced %d/%d custom director%s\n",
 		successCount, len(expandedDirs), pluralize(len(expandedDirs)))
 
 	return nil
 }
 
+// PullCustomDirs copies custom directories with direction pull or both from the
+// pod back to their local source, e.g. credentials generated in the session
+func (c *CustomDirSyncManager) PullCustomDirs(
+	ctx context.Context,
+	customDirs []config.CustomDirSync,
+	namespace, podName string,
+	globalConfig *config.GlobalConfig,
+) error {
+	out := OutputFor(ctx)
+	expandedDirs, err := c.expandCustomDirs(filterCustomDirs(customDirs, (*config.CustomDirSync).Pulls), globalConfig)
+	if err != nil {
+		return fmt.Errorf("failed to expand custom directories: %w", err)
+	}
+	if len(expandedDirs) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(out, "🔄 Pulling %d custom director%s...\n", len(expandedDirs), pluralize(len(expandedDirs)))
+
+	successCount := 0
+	for i, customDir := range expandedDirs {
+		if err := customDir.Validate(); err != nil {
+			fmt.Fprintf(out, "⚠️  Warning: Skipping custom directory %d: %v\n", i+1, err)
+			continue
+		}
+
+		resolvedSource, err := customDir.ResolveSource()
+		if err != nil {
+			fmt.Fprintf(out, "⚠️  Warning: Failed to resolve source path '%s': %v\n", customDir.Source, err)
+			continue
+		}
+
+		if err := c.syncMgr.PullFromCustomPath(ctx, customDir.Destination, resolvedSource, namespace, podName); err != nil {
+			fmt.Fprintf(out, "⚠️  Warning: Failed to pull '%s' to '%s': %v\n",
+				customDir.Destination, customDir.Source, err)
+			continue
+		}
+
+		fmt.Fprintf(out, "✓ Pulled: %s → %s\n", customDir.Destination, customDir.Source)
+		successCount++
+	}
+
+	if successCount == 0 {
+		return fmt.Errorf("failed to pull any custom directories")
+	}
+
+	return nil
+}
+
+// filterCustomDirs returns the entries for which keep returns true
+func filterCustomDirs(customDirs []config.CustomDirSync, keep func(*config.CustomDirSync) bool) []config.CustomDirSync {
+	var result []config.CustomDirSync
+	for i := range customDirs {
+		if keep(&customDirs[i]) {
+			result = append(result, customDirs[i])
+		}
+	}
+	return result
+}
+
 // expandCustomDirs expands recursive directory entries into individual subdirectory syncs
 func (c *CustomDirSyncManager) expandCustomDirs(
 	customDirs []config.CustomDirSync,
@@ -157,6 +228,9 @@ func (c *CustomDirSyncManager) discoverSubdirectories(
 			Exclude:      parentDir.Exclude,
 			UseGitignore: parentDir.UseGitignore,
 			Recursive:    false, // Do NOT recurse further
+			Chown:        parentDir.Chown,
+			Chmod:        parentDir.Chmod,
+			Direction:    parentDir.Direction,
 		}
 
 		result = append(result, subdirSync)
@@ -3,6 +3,7 @@ package sync
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/illumination-k/kodama/pkg/config"
@@ -12,11 +13,15 @@ import (
 // mockSyncManager implements SyncManager for testing
 type mockSyncManager struct {
 	syncedPaths map[string]string // localPath -> remotePath
+	pulledPaths map[string]string // remotePath -> localPath
+	ownership   map[string]string // remotePath -> "chown chmod"
 }
 
 func newMockSyncManager() *mockSyncManager {
 	return &mockSyncManager{
 		syncedPaths: make(map[string]string),
+		pulledPaths: make(map[string]string),
+		ownership:   make(map[string]string),
 	}
 }
 
@@ -30,6 +35,18 @@ func (m *mockSyncManager) InitialSyncToCustomPath(ctx context.Context, localPath
 	return nil
 }
 
+func (m *mockSyncManager) ApplyOwnership(ctx context.Context, remotePath, namespace, podName, chown, chmod string) error {
+	m.ownership[remotePath] = chown + " " + chmod
+	return nil
+}
+
+func (m *mockSyncManager) PullFromCustomPath(ctx context.Context, remotePath, localPath, namespace, podName string) error {
+	m.pulledPaths[remotePath] = localPath
+	return nil
+}
+
+func (m *mockSyncManager) OnFlush(fn func(files []string)) {}
+
 func (m *mockSyncManager) Start(ctx context.Context, sessionName, localPath, namespace, podName string, excludeCfg *exclude.Config) error {
 	return nil
 }
@@ -358,3 +375,40 @@ func TestCustomDirSyncManager_expandCustomDirs(t *testing.T) {
 		}
 	})
 }
+
+func TestCustomDirSyncManager_Directions(t *testing.T) {
+	mockMgr := newMockSyncManager()
+	customMgr := NewCustomDirSyncManager(mockMgr)
+
+	ctx := context.Background()
+	globalConfig := config.DefaultGlobalConfig()
+
+	tmpDir := t.TempDir()
+	keyFile := filepath.Join(tmpDir, "id_ed25519")
+	if err := os.WriteFile(keyFile, []byte("key"), 0o600); err != nil {
+		t.Fatalf("Failed to create key file: %v", err)
+	}
+	pulledDir := filepath.Join(tmpDir, "gh")
+
+	customDirs := []config.CustomDirSync{
+		{Source: keyFile, Destination: "/home/dev/.ssh/id_ed25519", Chown: "dev:dev", Chmod: "0600"},
+		{Source: pulledDir, Destination: "/root/.config/gh", Direction: config.SyncDirectionPull},
+	}
+
+	if err := customMgr.SyncCustomDirs(ctx, customDirs, "default", "test-pod", globalConfig); err != nil {
+		t.Fatalf("SyncCustomDirs failed: %v", err)
+	}
+	if len(mockMgr.syncedPaths) != 1 || mockMgr.syncedPaths[keyFile] != "/home/dev/.ssh/id_ed25519" {
+		t.Errorf("Expected only the push entry to be synced, got: %v", mockMgr.syncedPaths)
+	}
+	if got := mockMgr.ownership["/home/dev/.ssh/id_ed25519"]; got != "dev:dev 0600" {
+		t.Errorf("Expected ownership to be applied, got: %q", got)
+	}
+
+	if err := customMgr.PullCustomDirs(ctx, customDirs, "default", "test-pod", globalConfig); err != nil {
+		t.Fatalf("PullCustomDirs failed: %v", err)
+	}
+	if len(mockMgr.pulledPaths) != 1 || mockMgr.pulledPaths["/root/.config/gh"] != pulledDir {
+		t.Errorf("Expected only the pull entry to be pulled, got: %v", mockMgr.pulledPaths)
+	}
+}
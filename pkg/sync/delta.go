@@ -0,0 +1,422 @@
+package sync
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/metrics"
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// DeltaMinSize is the file size from which continuous sync sends only the
+// blocks that changed instead of the whole file
+const DeltaMinSize = 8 << 20
+
+const (
+	// deltaMinBlock and deltaMaxBlocks bound the block size: small enough to
+	// find unchanged regions, large enough to keep the signature cheap
+	deltaMinBlock  = 128 << 10
+	deltaMaxBlocks = 4096
+
+	// deltaReadChunk is how much of the local file is read at a time
+	deltaReadChunk = 256 << 10
+
+	// deltaMaxLiteral flushes literal runs so memory stays bounded
+	deltaMaxLiteral = 4 << 20
+)
+
+// errNoBaseFile is returned when the pod has no copy of the file to patch
+var errNoBaseFile = errors.New("file does not exist in the pod")
+
+// DeltaStats describes one delta transfer
+type DeltaStats struct {
+	Size     int64 // Size of the local file
+	Sent     int64 // Literal bytes sent to the pod
+	Matched  int   // Blocks reused from the pod's copy
+	Duration time.Duration
+}
+
+// deltaSignatureScript splits the pod's copy of $1 into $2-byte blocks and
+// prints the cksum (CRC-32) and sha256 of each. It uses only POSIX tools and
+// coreutils, so it runs in any session image; the blocks take as much
+// temporary disk space as the file.
+const deltaSignatureScript = `[ -f "$1" ] || { echo missing; exit 0; }
+d=$(mktemp -d) || exit 1
+trap 'rm -rf "$d"' EXIT
+cd "$d" && split -b "$2" -a 6 "$1" b. || exit 1
+set -- b.*
+[ -e "$1" ] || exit 0
+cksum "$@" && sha256sum "$@"`
+
+// blockSignature is the weak (cksum) and strong (sha256) checksum of a block
+type blockSignature struct {
+	weak   uint32 // Raw CRC, see rawCRCFromCksum
+	strong [sha256.Size]byte
+}
+
+// deltaOp copies Count blocks starting at Block from the pod's copy, or
+// Length literal bytes starting at Offset in the literals file
+type deltaOp struct {
+	Copy   bool
+	Block  int64
+	Count  int64
+	Offset int64
+	Length int64
+}
+
+// deltaBlockSize returns the block size for a file of size bytes: at least
+// deltaMinBlock and at most deltaMaxBlocks blocks, a multiple of 4 KiB so
+// page-aligned writes (SQLite, model checkpoints) change as few blocks as possible
+func deltaBlockSize(size int64) int64 {
+	bs := (size + deltaMaxBlocks - 1) / deltaMaxBlocks
+	bs = (bs + 4095) &^ 4095
+	return max(bs, deltaMinBlock)
+}
+
+// parseSignatures parses deltaSignatureScript output into signatures of the
+// full-size blocks, keyed by weak checksum
+func parseSignatures(output string, blockSize int64) (map[uint32][]int64, []blockSignature, error) {
+	if strings.TrimSpace(output) == "missing" {
+		return nil, nil, errNoBaseFile
+	}
+
+	type entry struct {
+		cksum  uint32
+		size   int64
+		strong []byte
+	}
+	blocks := map[string]*entry{}
+	var names []string
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		switch len(fields) {
+		case 0:
+			continue
+		case 3: // cksum: <crc> <size> <name>
+			crc, err := strconv.ParseUint(fields[0], 10, 32)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unexpected cksum line: %q", scanner.Text())
+			}
+			size, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				return nil, nil, fmt.Errorf("unexpected cksum line: %q", scanner.Text())
+			}
+			blocks[fields[2]] = &entry{cksum: uint32(crc), size: size}
+			names = append(names, fields[2])
+		case 2: // sha256sum: <hash>  <name>
+			e, ok := blocks[fields[1]]
+			strong, err := hex.DecodeString(fields[0])
+			if !ok || err != nil || len(strong) != sha256.Size {
+				return nil, nil, fmt.Errorf("unexpected sha256sum line: %q", scanner.Text())
+			}
+			e.strong = strong
+		default:
+			return nil, nil, fmt.Errorf("unexpected signature line: %q", scanner.Text())
+		}
+	}
+
+	index := map[uint32][]int64{}
+	sigs := make([]blockSignature, len(names))
+	for i, name := range names {
+		e := blocks[name]
+		if e.strong == nil {
+			return nil, nil, fmt.Errorf("missing sha256 of block %s", name)
+		}
+		sigs[i].weak = rawCRCFromCksum(e.cksum, e.size)
+		copy(sigs[i].strong[:], e.strong)
+		// The short last block can only match at the end of the file; it is sent instead
+		if e.size == blockSize {
+			index[sigs[i].weak] = append(index[sigs[i].weak], int64(i))
+		}
+	}
+	return index, sigs, nil
+}
+
+// computeDelta reads the local file from r and writes the ops that rebuild it
+// from the pod's blocks, with the bytes not found there written to literals.
+// This is the rsync algorithm: a rolling CRC over every offset of the local
+// file is looked up in the pod's block checksums and confirmed with sha256.
+func computeDelta(r io.Reader, index map[uint32][]int64, sigs []blockSignature, blockSize int64, literals io.Writer) ([]deltaOp, *DeltaStats, error) {
+	stats := &DeltaStats{}
+	var ops []deltaOp
+	bs := int(blockSize)
+	out := crcOutTable(blockSize)
+
+	// Most offsets match no block; a bitset rules them out before the map lookup
+	var filter [1 << 14]uint64
+	for weak := range index {
+		filter[weak>>6&(1<<14-1)] |= 1 << (weak & 63)
+	}
+
+	emitLiteral := func(data []byte) error {
+		if len(data) == 0 {
+			return nil
+		}
+		if _, err := literals.Write(data); err != nil {
+			return err
+		}
+		if n := len(ops); n > 0 && !ops[n-1].Copy {
+			ops[n-1].Length += int64(len(data))
+		} else {
+			ops = append(ops, deltaOp{Offset: stats.Sent, Length: int64(len(data))})
+		}
+		stats.Sent += int64(len(data))
+		return nil
+	}
+	emitCopy := func(block int64) {
+		stats.Matched++
+		if n := len(ops); n > 0 && ops[n-1].Copy && ops[n-1].Block+ops[n-1].Count == block {
+			ops[n-1].Count++
+			return
+		}
+		ops = append(ops, deltaOp{Copy: true, Block: block, Count: 1})
+	}
+
+	var (
+		buf      []byte // Unsent data; buf[start:start+bs] is the window
+		start    int
+		eof      bool
+		crc      uint32
+		crcValid bool
+	)
+	chunk := make([]byte, deltaReadChunk)
+	fill := func(n int) error {
+		for !eof && len(buf)-start < n {
+			read, err := r.Read(chunk)
+			buf = append(buf, chunk[:read]...)
+			stats.Size += int64(read)
+			if errors.Is(err, io.EOF) {
+				eof = true
+			} else if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for {
+		// Keep the window and one more byte to roll in
+		if err := fill(bs + 1); err != nil {
+			return nil, nil, err
+		}
+		if len(buf)-start < bs {
+			break
+		}
+
+		if !crcValid {
+			crc = rawCRC(buf[start : start+bs])
+			crcValid = true
+		}
+
+		if filter[crc>>6&(1<<14-1)]&(1<<(crc&63)) != 0 && len(index[crc]) > 0 {
+			if block, found := matchBlock(index[crc], sigs, sha256.Sum256(buf[start:start+bs])); found {
+				if err := emitLiteral(buf[:start]); err != nil {
+					return nil, nil, err
+				}
+				emitCopy(block)
+				buf = append(buf[:0], buf[start+bs:]...)
+				start = 0
+				crcValid = false
+				continue
+			}
+		}
+
+		if len(buf)-start == bs {
+			// Last window, nothing left to roll in
+			break
+		}
+		crc = rollCRC(crc, buf[start], buf[start+bs], out)
+		start++
+
+		if start >= deltaMaxLiteral {
+			if err := emitLiteral(buf[:start]); err != nil {
+				return nil, nil, err
+			}
+			buf = append(buf[:0], buf[start:]...)
+			start = 0
+		}
+	}
+
+	if err := emitLiteral(buf); err != nil {
+		return nil, nil, err
+	}
+	return ops, stats, nil
+}
+
+// matchBlock returns the first of blocks whose sha256 is strong
+func matchBlock(blocks []int64, sigs []blockSignature, strong [sha256.Size]byte) (int64, bool) {
+	for _, block := range blocks {
+		if sigs[block].strong == strong {
+			return block, true
+		}
+	}
+	return 0, false
+}
+
+// deltaApplyScript returns the script that rebuilds $1 in the pod from its
+// current blocks and the literals file $2, checks the result against the
+// local file's sha256 and replaces $1 with it
+func deltaApplyScript(ops []deltaOp, blockSize int64, sha string, mode os.FileMode) string {
+	var b strings.Builder
+	// The partial file is removed however the script ends; after mv it is gone
+	b.WriteString("set -e\ntmp=\"$1.kodama-delta\"\ntrap 'rm -f \"$tmp\"' EXIT\ntrap 'exit 1' HUP INT TERM PIPE\n{\n")
+	for _, op := range ops {
+		if op.Copy {
+			fmt.Fprintf(&b, "dd if=\"$1\" bs=%d skip=%d count=%d 2>/dev/null\n", blockSize, op.Block, op.Count)
+		} else {
+			fmt.Fprintf(&b, "tail -c +%d \"$2\" | head -c %d\n", op.Offset+1, op.Length)
+		}
+	}
+	b.WriteString("} > \"$tmp\"\n")
+	fmt.Fprintf(&b, "if [ \"$(sha256sum < \"$tmp\" | cut -d' ' -f1)\" != %s ]; then echo \"delta checksum mismatch\" >&2; exit 3; fi\n", sha)
+	fmt.Fprintf(&b, "chmod %o \"$tmp\"\nmv -f \"$tmp\" \"$1\"\n", mode.Perm())
+	return b.String()
+}
+
+// deltaApplyCommand returns the command that extracts the ops and literals
+// archive from stdin and runs the apply script on remotePath
+func deltaApplyCommand(c Compression, remotePath string) []string {
+	script := `d=$(mktemp -d) || exit 1
+trap 'rm -rf "$d"' EXIT
+` + extractPipeline(c, `"$d"`) + ` && sh "$d/apply.sh" "$1" "$d/literals"`
+	return []string{"sh", "-c", script, "sh", remotePath}
+}
+
+// runInPod runs command in the pod with stdin and returns its stdout
+// Tests replace it to run the scripts locally.
+var runInPod = func(ctx context.Context, opts Options, namespace, podName string, stdin io.Reader, command []string) (string, error) {
+	args := append([]string{"exec", "-i", "-n", namespace, podName, "--"}, command...)
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	cmd := procutil.CommandContext(ctx, "kubectl", opts.kubectlArgs(args...)...)
+	cmd.Stdin = stdin
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return stdout.String(), ctx.Err()
+		}
+		return stdout.String(), fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// PushDelta updates remotePath in the pod to match localPath, sending only
+// the blocks that are not already in the pod's copy
+// Returns errNoBaseFile when the pod has no copy to patch.
+func PushDelta(ctx context.Context, opts Options, localPath, remotePath, namespace, podName string) (*DeltaStats, error) {
+	began := time.Now()
+
+	f, err := os.Open(localPath) //#nosec G304 -- path is inside the synced directory
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	blockSize := deltaBlockSize(info.Size())
+
+	output, err := runInPod(ctx, opts, namespace, podName, nil,
+		[]string{"sh", "-c", deltaSignatureScript, "sh", remotePath, strconv.FormatInt(blockSize, 10)})
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read block checksums: %w", err)
+	}
+	index, sigs, err := parseSignatures(output, blockSize)
+	if err != nil {
+		return nil, err
+	}
+
+	literals, err := os.CreateTemp("", "kodama-delta-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create literals file: %w", err)
+	}
+	defer func() {
+		_ = literals.Close()
+		_ = os.Remove(literals.Name())
+	}()
+
+	hash := sha256.New()
+	ops, stats, err := computeDelta(io.TeeReader(f, hash), index, sigs, blockSize, literals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute delta: %w", err)
+	}
+	script := deltaApplyScript(ops, blockSize, hex.EncodeToString(hash.Sum(nil)), info.Mode())
+
+	c := resolveCompression(ctx, opts, namespace, podName)
+	pr, pw := io.Pipe()
+	go func() { _ = pw.CloseWithError(writeDeltaArchive(pw, script, literals, stats.Sent, c)) }()
+	_, err = runInPod(ctx, opts, namespace, podName, &metrics.CountingReader{R: pr}, deltaApplyCommand(c, remotePath))
+	_ = pr.Close()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply delta: %w", err)
+	}
+
+	stats.Duration = time.Since(began)
+	return stats, nil
+}
+
+// writeDeltaArchive writes the apply script and the literals as a c-compressed tar archive
+func writeDeltaArchive(w io.Writer, script string, literals *os.File, size int64, c Compression) error {
+	cw, err := compressWriter(w, c)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(cw)
+
+	if err := tw.WriteHeader(&tar.Header{Name: "apply.sh", Mode: 0o600, Size: int64(len(script))}); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(tw, script); err != nil {
+		return err
+	}
+
+	if _, err := literals.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: "literals", Mode: 0o600, Size: size}); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(tw, literals, size); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return cw.Close()
+}
+
+// formatBytes renders a byte count for sync output
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,206 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCksum(t *testing.T) {
+	assert.Equal(t, uint32(4294967295), Cksum(nil))
+	assert.Equal(t, uint32(930766865), Cksum([]byte("123456789")))
+
+	if _, err := exec.LookPath("cksum"); err != nil {
+		t.Skip("cksum not installed")
+	}
+	data := make([]byte, 300000)
+	rand.New(rand.NewSource(1)).Read(data)
+	cmd := exec.Command("cksum")
+	cmd.Stdin = bytes.NewReader(data)
+	out, err := cmd.Output()
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%d %d", Cksum(data), len(data)), strings.TrimSpace(string(out)))
+}
+
+func TestRawCRCFromCksum(t *testing.T) {
+	for _, size := range []int{1, 255, 256, 4096, 131072, 70000} {
+		block := make([]byte, size)
+		rand.New(rand.NewSource(int64(size))).Read(block)
+		assert.Equal(t, rawCRC(block), rawCRCFromCksum(Cksum(block), int64(size)), "size %d", size)
+	}
+	assert.Equal(t, uint32(1), gfMul(2, crcXInverse))
+}
+
+func TestRollCRC(t *testing.T) {
+	data := make([]byte, 5000)
+	rand.New(rand.NewSource(2)).Read(data)
+	const window = 1024
+	out := crcOutTable(window)
+
+	crc := rawCRC(data[:window])
+	for i := 1; i+window <= len(data); i++ {
+		crc = rollCRC(crc, data[i-1], data[i+window-1], out)
+		if crc != rawCRC(data[i:i+window]) {
+			t.Fatalf("rolled CRC at offset %d does not match", i)
+		}
+	}
+}
+
+func TestDeltaBlockSize(t *testing.T) {
+	assert.Equal(t, int64(deltaMinBlock), deltaBlockSize(DeltaMinSize))
+	assert.Equal(t, int64(256<<10), deltaBlockSize(1<<30))
+	assert.Zero(t, deltaBlockSize(10<<30)%4096)
+}
+
+// runLocally replaces runInPod with running the command on this machine
+func runLocally(t *testing.T) {
+	t.Helper()
+	for _, tool := range []string{"sh", "split", "cksum", "sha256sum", "dd", "tail", "head", "tar"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not installed", tool)
+		}
+	}
+
+	origRun, origZstd := runInPod, hasZstd
+	t.Cleanup(func() { runInPod, hasZstd = origRun, origZstd })
+	hasZstd = func(ctx context.Context, opts Options, namespace, podName string) bool { return false }
+	runInPod = func(ctx context.Context, opts Options, namespace, podName string, stdin io.Reader, command []string) (string, error) {
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...) //#nosec G204 -- test command
+		cmd.Stdin = stdin
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return string(out), fmt.Errorf("%w: %s", err, stderr.String())
+		}
+		return string(out), nil
+	}
+}
+
+func TestPushDelta(t *testing.T) {
+	runLocally(t)
+
+	rng := rand.New(rand.NewSource(3))
+	original := make([]byte, 3<<20)
+	rng.Read(original)
+
+	insert := make([]byte, 1000)
+	rng.Read(insert)
+	patch := []byte("patched page")
+
+	tests := []struct {
+		name     string
+		modify   func(data []byte) []byte
+		maxSent  int64
+		minMatch int
+	}{
+		{"unchanged", func(data []byte) []byte { return data }, 0, 24},
+		{"page rewritten", func(data []byte) []byte {
+			copy(data[1<<20:], patch)
+			return data
+		}, deltaMinBlock, 23},
+		{"bytes inserted at the start", func(data []byte) []byte {
+			return append(append([]byte{}, insert...), data...)
+		}, int64(len(insert)), 24},
+		{"appended", func(data []byte) []byte { return append(data, insert...) }, int64(len(insert)), 24},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			remote := filepath.Join(dir, "remote.db")
+			local := filepath.Join(dir, "local.db")
+			require.NoError(t, os.WriteFile(remote, original, 0o600))
+			want := tt.modify(append([]byte{}, original...))
+			require.NoError(t, os.WriteFile(local, want, 0o640))
+
+			stats, err := PushDelta(context.Background(), Options{}, local, remote, "dev", "kodama-work")
+			require.NoError(t, err)
+
+			got, err := os.ReadFile(remote)
+			require.NoError(t, err)
+			assert.True(t, bytes.Equal(want, got), "remote file differs from local")
+			info, err := os.Stat(remote)
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+
+			assert.Equal(t, int64(len(want)), stats.Size)
+			assert.LessOrEqual(t, stats.Sent, tt.maxSent)
+			assert.GreaterOrEqual(t, stats.Matched, tt.minMatch)
+		})
+	}
+}
+
+func TestPushDelta_NoBaseFile(t *testing.T) {
+	runLocally(t)
+
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.db")
+	require.NoError(t, os.WriteFile(local, []byte("data"), 0o600))
+
+	_, err := PushDelta(context.Background(), Options{}, local, filepath.Join(dir, "missing.db"), "dev", "kodama-work")
+	assert.ErrorIs(t, err, errNoBaseFile)
+}
+
+func TestPushDelta_Canceled(t *testing.T) {
+	stubKubectl(t, "sleep 30 & wait")
+
+	dir := t.TempDir()
+	local := filepath.Join(dir, "local.db")
+	require.NoError(t, os.WriteFile(local, []byte("data"), 0o600))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err := PushDelta(ctx, Options{}, local, "/workspace/local.db", "dev", "kodama-work")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestDeltaApplyScript_RemovesPartialFile checks a failed apply leaves only
+// the original file behind
+func TestDeltaApplyScript_RemovesPartialFile(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "model.bin")
+	require.NoError(t, os.WriteFile(target, []byte("original"), 0o600))
+	literals := filepath.Join(dir, "literals")
+	require.NoError(t, os.WriteFile(literals, []byte("new"), 0o600))
+
+	script := deltaApplyScript([]deltaOp{{Length: 3}}, deltaMinBlock, strings.Repeat("0", 64), 0o600)
+	out, err := exec.Command("sh", "-c", script, "sh", target, literals).CombinedOutput() //#nosec G204 -- test script
+	require.Error(t, err)
+	assert.Contains(t, string(out), "delta checksum mismatch")
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "the partial file was left behind")
+	content, err := os.ReadFile(target)
+	require.NoError(t, err)
+	assert.Equal(t, "original", string(content))
+}
+
+func TestParseSignatures(t *testing.T) {
+	block := bytes.Repeat([]byte("a"), 8)
+	output := fmt.Sprintf("%d 8 b.aaaaaa\n%d 3 b.aaaaab\n%x  b.aaaaaa\n%x  b.aaaaab\n",
+		Cksum(block), Cksum([]byte("end")), sha256.Sum256(block), sha256.Sum256([]byte("end")))
+
+	index, sigs, err := parseSignatures(output, 8)
+	require.NoError(t, err)
+	require.Len(t, sigs, 2)
+	assert.Equal(t, []int64{0}, index[rawCRC(block)])
+	assert.Len(t, index, 1, "the short last block is not indexed")
+
+	_, _, err = parseSignatures("missing\n", 8)
+	assert.ErrorIs(t, err, errNoBaseFile)
+	_, _, err = parseSignatures("1 8 b.aaaaaa\n", 8)
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package exclude
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// includeSet holds include patterns split into path components. Unlike
+// excludes, include patterns are anchored at the sync root: "src/**" selects
+// the src directory and "go.mod" only the go.mod next to it. A pattern that
+// matches a directory includes everything below it, and "**" matches any
+// number of directories.
+type includeSet [][]string
+
+// compileIncludes splits include patterns into components
+func compileIncludes(patterns []string) includeSet {
+	var set includeSet
+	for _, pattern := range patterns {
+		trimmed := strings.Trim(filepath.ToSlash(pattern), "/")
+		trimmed = strings.TrimPrefix(trimmed, "./")
+		if trimmed == "" {
+			continue
+		}
+		set = append(set, strings.Split(trimmed, "/"))
+	}
+	return set
+}
+
+// includes reports whether relPath or one of its parent directories matches a pattern
+func (s includeSet) includes(relPath string) bool {
+	parts := strings.Split(filepath.ToSlash(relPath), "/")
+	for _, pattern := range s {
+		for n := 1; n <= len(parts); n++ {
+			if matchComponents(pattern, parts[:n]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mayContain reports whether the directory relDir is included or may contain
+// an included path, so a walk must descend into it
+func (s includeSet) mayContain(relDir string) bool {
+	if s.includes(relDir) {
+		return true
+	}
+	parts := strings.Split(filepath.ToSlash(relDir), "/")
+	for _, pattern := range s {
+		if matchPrefix(pattern, parts) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchComponents matches path components against pattern components, with
+// "**" matching zero or more components
+func matchComponents(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return len(parts) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchComponents(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if matched, err := filepath.Match(pattern[0], parts[0]); err != nil || !matched {
+		return false
+	}
+	return matchComponents(pattern[1:], parts[1:])
+}
+
+// matchPrefix reports whether parts can be the leading components of a path
+// matching pattern, i.e. the pattern continues below them
+func matchPrefix(pattern, parts []string) bool {
+	if len(parts) == 0 {
+		return len(pattern) > 0
+	}
+	if len(pattern) == 0 {
+		return false
+	}
+	if pattern[0] == "**" {
+		return true
+	}
+	if matched, err := filepath.Match(pattern[0], parts[0]); err != nil || !matched {
+		return false
+	}
+	return matchPrefix(pattern[1:], parts[1:])
+}
@@ -3,7 +3,6 @@ package exclude
 import (
 	"os"
 	"path/filepath"
-	"strings"
 
 	ignore "github.com/sabhiram/go-gitignore"
 )
@@ -13,6 +12,8 @@ type Manager struct {
 	gitignoreMatcher *ignore.GitIgnore
 	basePath         string
 	configPatterns   []string
+	patterns         *patternIndex
+	includes         includeSet
 }
 
 // Config holds configuration for the exclude manager
@@ -25,6 +26,10 @@ type Config struct {
 
 	// UseGitignore enables automatic .gitignore loading
 	UseGitignore bool
+
+	// Include limits sync to these paths relative to BasePath, e.g. "src/**"
+	// and "go.mod". They are evaluated before excludes; empty includes everything.
+	Include []string
 }
 
 // NewManager creates a new exclude pattern manager
@@ -32,6 +37,8 @@ func NewManager(cfg Config) (*Manager, error) {
 	m := &Manager{
 		basePath:       cfg.BasePath,
 		configPatterns: cfg.Patterns,
+		patterns:       compilePatterns(cfg.Patterns),
+		includes:       compileIncludes(cfg.Include),
 	}
 
 	// Load .gitignore if enabled
@@ -63,72 +70,48 @@ func (m *Manager) ShouldExclude(absPath string) bool {
 		return false
 	}
 
-	// Check config patterns first (these take precedence)
-	if m.matchesConfigPatterns(relPath) {
+	// Paths outside the include patterns are excluded before anything else
+	if len(m.includes) > 0 && relPath != "." && !m.includes.includes(relPath) {
 		return true
 	}
 
-	// Check gitignore patterns
-	if m.gitignoreMatcher != nil && m.gitignoreMatcher.MatchesPath(relPath) {
-		return true
-	}
-
-	return false
+	return m.matchesExcludes(relPath)
 }
 
 // ShouldExcludeDir returns true if the directory should be excluded
-// This is optimized for directory traversal (uses filepath.SkipDir)
+// This is optimized for directory traversal (uses filepath.SkipDir). With
+// include patterns, directories leading to an included path are kept.
 func (m *Manager) ShouldExcludeDir(absPath string) bool {
-	return m.ShouldExclude(absPath)
-}
-
-// matchesConfigPatterns checks if path matches any config pattern
-func (m *Manager) matchesConfigPatterns(relPath string) bool {
-	for _, pattern := range m.configPatterns {
-		if m.matchPattern(pattern, relPath) {
-			return true
-		}
+	relPath, err := filepath.Rel(m.basePath, absPath)
+	if err != nil {
+		return false
 	}
-	return false
-}
 
-// matchPattern matches a single gitignore-style pattern
-func (m *Manager) matchPattern(pattern, path string) bool {
-	// Handle directory-only patterns (ending with /)
-	if strings.HasSuffix(pattern, "/") {
-		pattern = strings.TrimSuffix(pattern, "/")
-		// Only match directories - check if path contains this as dir
-		if strings.Contains(path, pattern+"/") ||
-			strings.HasPrefix(path, pattern+"/") ||
-			path == pattern {
-			return true
-		}
+	if len(m.includes) > 0 && relPath != "." && !m.includes.mayContain(relPath) {
+		return true
 	}
 
-	// Handle ** wildcards for any directory depth
-	if strings.Contains(pattern, "**") {
-		pattern = strings.ReplaceAll(pattern, "**", "*")
-	}
+	return m.matchesExcludes(relPath)
+}
 
-	// Use filepath.Match for glob patterns
-	matched, err := filepath.Match(pattern, path)
-	if err == nil && matched {
+// matchesExcludes checks config patterns, which take precedence, and then gitignore patterns
+func (m *Manager) matchesExcludes(relPath string) bool {
+	if m.matchesConfigPatterns(relPath) {
 		return true
 	}
 
-	// Also check if pattern matches any path component
-	// (e.g., "node_modules" should match "foo/node_modules/bar")
-	parts := strings.Split(path, string(filepath.Separator))
-	for _, part := range parts {
-		matched, err := filepath.Match(pattern, part)
-		if err == nil && matched {
-			return true
-		}
+	if m.gitignoreMatcher != nil && m.gitignoreMatcher.MatchesPath(relPath) {
+		return true
 	}
 
 	return false
 }
 
+// matchesConfigPatterns checks if path matches any config pattern
+func (m *Manager) matchesConfigPatterns(relPath string) bool {
+	return m.patterns.matches(relPath)
+}
+
 // GetTarExcludeArgs returns --exclude arguments for tar command
 func (m *Manager) GetTarExcludeArgs() []string {
 	args := []string{}
@@ -1,15 +1,16 @@
 package exclude
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 )
 
 func TestShouldExclude_SimplePattern(t *testing.T) {
-	m := &Manager{
-		basePath:       "/tmp/test",
-		configPatterns: []string{"*.log"},
+	m, err := NewManager(Config{BasePath: "/tmp/test", Patterns: []string{"*.log"}})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
 	}
 
 	tests := []struct {
@@ -31,9 +32,9 @@ func TestShouldExclude_SimplePattern(t *testing.T) {
 }
 
 func TestShouldExclude_DirectoryPattern(t *testing.T) {
-	m := &Manager{
-		basePath:       "/tmp/test",
-		configPatterns: []string{"node_modules"},
+	m, err := NewManager(Config{BasePath: "/tmp/test", Patterns: []string{"node_modules"}})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
 	}
 
 	tests := []struct {
@@ -54,9 +55,9 @@ func TestShouldExclude_DirectoryPattern(t *testing.T) {
 }
 
 func TestShouldExclude_DirectorySlashPattern(t *testing.T) {
-	m := &Manager{
-		basePath:       "/tmp/test",
-		configPatterns: []string{".vscode/"},
+	m, err := NewManager(Config{BasePath: "/tmp/test", Patterns: []string{".vscode/"}})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
 	}
 
 	tests := []struct {
@@ -78,9 +79,9 @@ func TestShouldExclude_DirectorySlashPattern(t *testing.T) {
 }
 
 func TestShouldExclude_MultiplePatterns(t *testing.T) {
-	m := &Manager{
-		basePath:       "/tmp/test",
-		configPatterns: []string{"*.log", "*.tmp", "node_modules"},
+	m, err := NewManager(Config{BasePath: "/tmp/test", Patterns: []string{"*.log", "*.tmp", "node_modules"}})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
 	}
 
 	tests := []struct {
@@ -207,9 +208,9 @@ func TestNewManager_GitignoreDisabled(t *testing.T) {
 }
 
 func TestGetTarExcludeArgs(t *testing.T) {
-	m := &Manager{
-		basePath:       "/tmp/test",
-		configPatterns: []string{"*.log", "node_modules"},
+	m, err := NewManager(Config{BasePath: "/tmp/test", Patterns: []string{"*.log", "node_modules"}})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
 	}
 
 	args := m.GetTarExcludeArgs()
@@ -228,9 +229,9 @@ func TestGetTarExcludeArgs(t *testing.T) {
 }
 
 func TestGetTarExcludeArgs_WithGitPattern(t *testing.T) {
-	m := &Manager{
-		basePath:       "/tmp/test",
-		configPatterns: []string{"*.log", ".git"},
+	m, err := NewManager(Config{BasePath: "/tmp/test", Patterns: []string{"*.log", ".git"}})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
 	}
 
 	args := m.GetTarExcludeArgs()
@@ -249,8 +250,6 @@ func TestGetTarExcludeArgs_WithGitPattern(t *testing.T) {
 }
 
 func TestMatchPattern_Wildcards(t *testing.T) {
-	m := &Manager{basePath: "/test"}
-
 	tests := []struct {
 		pattern string
 		path    string
@@ -266,9 +265,143 @@ func TestMatchPattern_Wildcards(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := m.matchPattern(tt.pattern, tt.path)
+		got := compilePatterns([]string{tt.pattern}).matches(tt.path)
 		if got != tt.want {
-			t.Errorf("matchPattern(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			t.Errorf("match(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatchPattern_PathPatterns(t *testing.T) {
+	idx := compilePatterns([]string{"build/out/", "docs/generated.md", "vendor/"})
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"build/out", true},
+		{"build/out/app.bin", true},
+		{"cmd/build/out/app.bin", true},
+		{"build/output.txt", false},
+		{"docs/generated.md", true},
+		{"site/docs/generated.md", false},
+		{"docs/generated.md.bak", false},
+		{"vendor/github.com/x/y.go", true},
+		{"src/vendor.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := idx.matches(filepath.FromSlash(tt.path)); got != tt.want {
+			t.Errorf("matches(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkShouldExclude checks a 50k-file monorepo tree against a typical
+// set of patterns, as the initial walk of a sync does
+func BenchmarkShouldExclude(b *testing.B) {
+	m, err := NewManager(Config{
+		BasePath: "/repo",
+		Patterns: []string{
+			".git/", "node_modules/", "dist/", "build/", ".next/", "coverage/", "target/",
+			"*.log", "*.tmp", "*.pyc", "__pycache__/", ".venv/", "*.egg-info/", "services/legacy/out/",
+		},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	paths := make([]string, 0, 50000)
+	for i := 0; len(paths) < cap(paths); i++ {
+		dir := filepath.Join("/repo", "services", fmt.Sprintf("svc-%d", i%200), "pkg", fmt.Sprintf("mod-%d", i%37))
+		paths = append(paths, filepath.Join(dir, fmt.Sprintf("file-%d.go", i)))
+		if i%10 == 0 {
+			paths = append(paths, filepath.Join(dir, "node_modules", "dep", "index.js"))
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, path := range paths {
+			m.ShouldExclude(path)
+		}
+	}
+}
+
+func TestShouldExclude_Include(t *testing.T) {
+	m, err := NewManager(Config{
+		BasePath: "/tmp/test",
+		Include:  []string{"src/**", "go.mod", "services/*/api/"},
+		Patterns: []string{"*.log"},
+	})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/tmp/test/go.mod", false},
+		{"/tmp/test/src/main.go", false},
+		{"/tmp/test/src/deep/nested/file.go", false},
+		{"/tmp/test/src/debug.log", true}, // Excludes still apply within includes
+		{"/tmp/test/services/payments/api/handler.go", false},
+		{"/tmp/test/services/payments/worker/main.go", true},
+		{"/tmp/test/vendor/go.mod", true}, // Includes are anchored at the root
+		{"/tmp/test/README.md", true},
+	}
+
+	for _, tt := range tests {
+		if got := m.ShouldExclude(tt.path); got != tt.want {
+			t.Errorf("ShouldExclude(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestShouldExcludeDir_Include(t *testing.T) {
+	m, err := NewManager(Config{BasePath: "/tmp/test", Include: []string{"src/**", "services/*/api"}})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/tmp/test", false},
+		{"/tmp/test/src", false},
+		{"/tmp/test/src/pkg", false},
+		{"/tmp/test/services", false}, // Leads to services/*/api
+		{"/tmp/test/services/payments", false},
+		{"/tmp/test/services/payments/api", false},
+		{"/tmp/test/services/payments/worker", true},
+		{"/tmp/test/node_modules", true},
+	}
+
+	for _, tt := range tests {
+		if got := m.ShouldExcludeDir(tt.path); got != tt.want {
+			t.Errorf("ShouldExcludeDir(%q) = %v, want %v", tt.path, got, tt.want)
 		}
 	}
 }
+
+func TestShouldExclude_IncludeDoubleStar(t *testing.T) {
+	m, err := NewManager(Config{BasePath: "/tmp/test", Include: []string{"**/*.proto"}})
+	if err != nil {
+		t.Fatalf("NewManager() error = %v", err)
+	}
+
+	if m.ShouldExclude("/tmp/test/api/v1/service.proto") {
+		t.Error("nested .proto file should be included")
+	}
+	if m.ShouldExclude("/tmp/test/service.proto") {
+		t.Error("top-level .proto file should be included")
+	}
+	if !m.ShouldExclude("/tmp/test/api/v1/service.go") {
+		t.Error("non-.proto file should be excluded")
+	}
+	if m.ShouldExcludeDir("/tmp/test/api") {
+		t.Error("directories may contain .proto files and must be walked")
+	}
+}
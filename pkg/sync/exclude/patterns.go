@@ -0,0 +1,148 @@
+package exclude
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// patternIndex holds config patterns compiled once, so matching a path costs a
+// few map lookups per path component instead of a glob match per pattern.
+// The initial walk of a large monorepo checks every file against it.
+type patternIndex struct {
+	// names are literal patterns without '/', matching any path component
+	names map[string]struct{}
+	// paths are literal patterns containing '/', indexed by component
+	paths *pathNode
+	// suffixes and prefixes are "*.log" and "test*" patterns, the common
+	// globs, matched against each path component without filepath.Match
+	suffixes []string
+	prefixes []string
+	// componentGlobs are other wildcard patterns without '/', matched against
+	// each path component
+	componentGlobs []string
+	// pathGlobs are wildcard patterns containing '/', matched against the whole
+	// path, with '**' reduced to '*'
+	pathGlobs []string
+}
+
+// pathNode is a node of the component trie of literal path patterns
+type pathNode struct {
+	children map[string]*pathNode
+	// dir is set for "a/b/" patterns, which match a/b and everything below it
+	dir bool
+	// exact is set for "a/b" patterns, which match only the path a/b
+	exact bool
+}
+
+// globChars are the characters that make a pattern a glob
+const globChars = "*?[\\"
+
+// addGlob adds a wildcard pattern to the index
+// A pattern without '/' matches any path component, as "node_modules" does.
+func (idx *patternIndex) addGlob(glob string) {
+	switch {
+	case strings.Contains(glob, "/"):
+		idx.pathGlobs = append(idx.pathGlobs, glob)
+	case strings.HasPrefix(glob, "*") && !strings.ContainsAny(glob[1:], globChars):
+		idx.suffixes = append(idx.suffixes, glob[1:])
+	case strings.HasSuffix(glob, "*") && !strings.ContainsAny(glob[:len(glob)-1], globChars):
+		idx.prefixes = append(idx.prefixes, glob[:len(glob)-1])
+	default:
+		idx.componentGlobs = append(idx.componentGlobs, glob)
+	}
+}
+
+// compilePatterns builds the index of gitignore-style patterns
+func compilePatterns(patterns []string) *patternIndex {
+	idx := &patternIndex{
+		names: make(map[string]struct{}),
+		paths: &pathNode{},
+	}
+
+	for _, pattern := range patterns {
+		dirOnly := strings.HasSuffix(pattern, "/")
+		trimmed := strings.TrimSuffix(pattern, "/")
+		if trimmed == "" {
+			continue
+		}
+
+		if strings.ContainsAny(trimmed, globChars) {
+			idx.addGlob(strings.ReplaceAll(trimmed, "**", "*"))
+			continue
+		}
+		if !strings.Contains(trimmed, "/") {
+			idx.names[trimmed] = struct{}{}
+			continue
+		}
+
+		node := idx.paths
+		for _, part := range strings.Split(trimmed, "/") {
+			child, ok := node.children[part]
+			if !ok {
+				child = &pathNode{}
+				if node.children == nil {
+					node.children = make(map[string]*pathNode)
+				}
+				node.children[part] = child
+			}
+			node = child
+		}
+		if dirOnly {
+			node.dir = true
+		} else {
+			node.exact = true
+		}
+	}
+
+	return idx
+}
+
+// matches reports whether relPath matches any pattern in the index
+func (idx *patternIndex) matches(relPath string) bool {
+	parts := strings.Split(relPath, string(filepath.Separator))
+
+	for _, part := range parts {
+		if _, ok := idx.names[part]; ok {
+			return true
+		}
+	}
+
+	// Directory patterns match at any depth, exact ones only the whole path
+	for start := range parts {
+		node := idx.paths
+		for i := start; i < len(parts); i++ {
+			if node = node.children[parts[i]]; node == nil {
+				break
+			}
+			if node.dir || (node.exact && start == 0 && i == len(parts)-1) {
+				return true
+			}
+		}
+	}
+
+	for _, part := range parts {
+		for _, suffix := range idx.suffixes {
+			if strings.HasSuffix(part, suffix) {
+				return true
+			}
+		}
+		for _, prefix := range idx.prefixes {
+			if strings.HasPrefix(part, prefix) {
+				return true
+			}
+		}
+		for _, glob := range idx.componentGlobs {
+			if matched, err := filepath.Match(glob, part); err == nil && matched {
+				return true
+			}
+		}
+	}
+
+	for _, glob := range idx.pathGlobs {
+		if matched, err := filepath.Match(glob, relPath); err == nil && matched {
+			return true
+		}
+	}
+
+	return false
+}
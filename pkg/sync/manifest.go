@@ -0,0 +1,215 @@
+package sync
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/procutil"
+	"github.com/illumination-k/kodama/pkg/shellutil"
+	"github.com/illumination-k/kodama/pkg/sync/exclude"
+)
+
+// FileState is the size, modification time and content hash of a synced file
+type FileState = config.SyncedFile
+
+// Manifest maps slash-separated paths relative to the sync root to their state
+type Manifest = config.SyncManifest
+
+// RemoteManifestPath is where the pod keeps the manifest of the last sync, next
+// to the local copy in the session's config directory
+const RemoteManifestPath = "/workspace/.kodama/sync-manifest"
+
+// RemoteStatScript returns a script printing a "<size> <mtime> <path>" line per
+// file below root, the sync root in the pod. .git and kodama's own .kodama
+// directory are skipped.
+func RemoteStatScript(root string) string {
+	return `cd ` + shellutil.Quote(root) + ` 2>/dev/null || exit 0
+find . \( -path ./.git -o -path ./.kodama \) -prune -o -type f -exec stat -c '%s %Y %n' {} +`
+}
+
+// ManifestDiff lists the files that differ between two manifests, sorted by path
+type ManifestDiff struct {
+	Added    []string
+	Modified []string
+	Deleted  []string
+}
+
+// Count returns the number of differing files
+func (d ManifestDiff) Count() int {
+	return len(d.Added) + len(d.Modified) + len(d.Deleted)
+}
+
+// Changed returns the added and modified files, the ones a push has to copy
+func (d ManifestDiff) Changed() []string {
+	changed := append(append([]string{}, d.Added...), d.Modified...)
+	sort.Strings(changed)
+	return changed
+}
+
+// DiffManifests compares current with the manifest old was recorded as
+func DiffManifests(old, current Manifest) ManifestDiff {
+	var diff ManifestDiff
+	for path, state := range current {
+		oldState, exists := old[path]
+		switch {
+		case !exists:
+			diff.Added = append(diff.Added, path)
+		case oldState.Hash != state.Hash:
+			diff.Modified = append(diff.Modified, path)
+		}
+	}
+	for path := range old {
+		if _, exists := current[path]; !exists {
+			diff.Deleted = append(diff.Deleted, path)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Modified)
+	sort.Strings(diff.Deleted)
+	return diff
+}
+
+// ScanManifest lists every file below root that the sync would push. Files
+// whose size and modification time still match prev keep their recorded hash,
+// so only new and changed files are read.
+func ScanManifest(root string, excludeCfg *exclude.Config, prev Manifest) (Manifest, error) {
+	excludeMgr, err := newConflictExcludeManager(excludeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := Manifest{}
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" || (path != root && excludeMgr != nil && excludeMgr.ShouldExcludeDir(path)) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() || (excludeMgr != nil && excludeMgr.ShouldExclude(path)) {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		state := FileState{Size: info.Size(), ModTime: info.ModTime()}
+		if recorded, ok := prev[rel]; ok && recorded.Size == state.Size && recorded.ModTime.Equal(state.ModTime) {
+			state.Hash = recorded.Hash
+		} else if state.Hash, err = hashFile(path); err != nil {
+			return err
+		}
+		manifest[rel] = state
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+	}
+	return manifest, nil
+}
+
+// ParseRemoteStat parses the output of RemoteStatScript into a manifest without
+// hashes, dropping files the sync excludes
+func ParseRemoteStat(output, root string, excludeCfg *exclude.Config) (Manifest, error) {
+	excludeMgr, err := newConflictExcludeManager(excludeCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := Manifest{}
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		fields := strings.SplitN(line, " ", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected stat line: %q", line)
+		}
+		size, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected stat line: %q", line)
+		}
+		unix, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected stat line: %q", line)
+		}
+
+		path := cleanRemotePath(fields[2])
+		if excludeMgr != nil && excludeMgr.ShouldExclude(filepath.Join(root, filepath.FromSlash(path))) {
+			continue
+		}
+		manifest[path] = FileState{Size: size, ModTime: time.Unix(unix, 0)}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// FillHashes copies the hashes of recorded into current for files whose size
+// and modification time are unchanged, and returns the files still to be hashed.
+// Times are compared in whole seconds, the resolution stat prints in the pod.
+func FillHashes(current, recorded Manifest) []string {
+	var stale []string
+	for path, state := range current {
+		prev, ok := recorded[path]
+		if ok && prev.Hash != "" && prev.Size == state.Size && prev.ModTime.Unix() == state.ModTime.Unix() {
+			state.Hash = prev.Hash
+			current[path] = state
+			continue
+		}
+		stale = append(stale, path)
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// PushFiles copies files from localPath into remotePath in the pod in a single
+// tar stream, overwriting the pod's copies
+func PushFiles(ctx context.Context, opts Options, localPath, remotePath, namespace, podName string, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+	return pushArchive(ctx, opts, localPath, remotePath, namespace, podName, files, nil)
+}
+
+// WriteRemoteManifest stores manifest at RemoteManifestPath in the pod
+// The manifest is streamed on stdin, since it may exceed the argument size limit.
+func WriteRemoteManifest(ctx context.Context, opts Options, namespace, podName string, manifest Manifest) error {
+	script := fmt.Sprintf("mkdir -p %s && cat > %s", filepath.Dir(RemoteManifestPath), RemoteManifestPath)
+
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	cmd := procutil.CommandContext(ctx, "kubectl", opts.kubectlArgs("exec", "-i",
+		"-n", namespace,
+		podName,
+		"--",
+		"sh", "-c", script,
+	)...)
+	cmd.Stdin = bytes.NewReader(config.EncodeSyncManifest(manifest))
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to write %s: %w (output: %s)", RemoteManifestPath, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package sync
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/sync/exclude"
+)
+
+func TestDiffManifests(t *testing.T) {
+	old := Manifest{
+		"same.go":    {Hash: "a"},
+		"changed.go": {Hash: "b"},
+		"gone.go":    {Hash: "c"},
+	}
+	current := Manifest{
+		"same.go":    {Hash: "a"},
+		"changed.go": {Hash: "B"},
+		"new.go":     {Hash: "d"},
+	}
+
+	diff := DiffManifests(old, current)
+	assert.Equal(t, []string{"new.go"}, diff.Added)
+	assert.Equal(t, []string{"changed.go"}, diff.Modified)
+	assert.Equal(t, []string{"gone.go"}, diff.Deleted)
+	assert.Equal(t, 3, diff.Count())
+	assert.Equal(t, []string{"changed.go", "new.go"}, diff.Changed())
+}
+
+func TestScanManifest_ReusesHashes(t *testing.T) {
+	root := t.TempDir()
+	path := filepath.Join(root, "main.go")
+	require.NoError(t, os.WriteFile(path, []byte("package main\n"), 0o600))
+
+	first, err := ScanManifest(root, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(13), first["main.go"].Size)
+
+	// An unchanged size and time keep the recorded hash without reading the file
+	prev := Manifest{"main.go": {Hash: "recorded", Size: first["main.go"].Size, ModTime: first["main.go"].ModTime}}
+	second, err := ScanManifest(root, nil, prev)
+	require.NoError(t, err)
+	assert.Equal(t, "recorded", second["main.go"].Hash)
+
+	// A new time means the file is hashed again
+	require.NoError(t, os.Chtimes(path, time.Now(), first["main.go"].ModTime.Add(time.Second)))
+	third, err := ScanManifest(root, nil, prev)
+	require.NoError(t, err)
+	assert.Equal(t, first["main.go"].Hash, third["main.go"].Hash)
+}
+
+func TestParseRemoteStat(t *testing.T) {
+	output := "13 1700000000 ./main.go\n5 1700000001 ./node_modules/x.js\n2 1700000002 ./dir/with space.txt\n"
+	excludeCfg := &exclude.Config{Patterns: []string{"node_modules/"}, BasePath: "/src"}
+
+	manifest, err := ParseRemoteStat(output, "/src", excludeCfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, Manifest{
+		"main.go":            {Size: 13, ModTime: time.Unix(1_700_000_000, 0)},
+		"dir/with space.txt": {Size: 2, ModTime: time.Unix(1_700_000_002, 0)},
+	}, manifest)
+
+	_, err = ParseRemoteStat("garbage\n", "/src", nil)
+	assert.Error(t, err)
+}
+
+func TestFillHashes(t *testing.T) {
+	at := time.Unix(1_700_000_000, 0)
+	recorded := Manifest{
+		"same.go":    {Hash: "a", Size: 1, ModTime: at.Add(500 * time.Millisecond)},
+		"resized.go": {Hash: "b", Size: 1, ModTime: at},
+	}
+	current := Manifest{
+		"same.go":    {Size: 1, ModTime: at},
+		"resized.go": {Size: 2, ModTime: at},
+		"new.go":     {Size: 1, ModTime: at},
+	}
+
+	stale := FillHashes(current, recorded)
+	assert.Equal(t, []string{"new.go", "resized.go"}, stale)
+	assert.Equal(t, "a", current["same.go"].Hash)
+}
+
+func TestWriteRemoteManifest_Canceled(t *testing.T) {
+	stubKubectl(t, "sleep 30 & wait")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	err := WriteRemoteManifest(ctx, Options{}, "dev", "kodama-work", Manifest{})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -2,14 +2,18 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
+	"path"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/fsnotify/fsnotify"
 
+	"github.com/illumination-k/kodama/pkg/metrics"
+	"github.com/illumination-k/kodama/pkg/procutil"
 	"github.com/illumination-k/kodama/pkg/sync/exclude"
 )
 
@@ -18,6 +22,8 @@ type simpleSyncManager struct {
 	watchers        map[string]*fsnotify.Watcher
 	stopChan        map[string]chan struct{}
 	excludeManagers map[string]*exclude.Manager
+	onFlush         func(files []string)
+	opts            Options
 }
 
 // Compile-time check that simpleSyncManager implements SyncManager
@@ -25,7 +31,13 @@ var _ SyncManager = (*simpleSyncManager)(nil)
 
 // NewSimpleSyncManager creates a new SyncManager instance using simple sync
 func NewSimpleSyncManager() SyncManager {
+	return newSimpleSyncManager(Options{})
+}
+
+// newSimpleSyncManager creates a simple SyncManager whose transfers use opts
+func newSimpleSyncManager(opts Options) *simpleSyncManager {
 	return &simpleSyncManager{
+		opts:            opts,
 		watchers:        make(map[string]*fsnotify.Watcher),
 		stopChan:        make(map[string]chan struct{}),
 		excludeManagers: make(map[string]*exclude.Manager),
@@ -45,7 +57,7 @@ func (s *simpleSyncManager) InitialSync(ctx context.Context, localPath, namespac
 		return fmt.Errorf("local path does not exist: %w", err)
 	}
 
-	return s.initialSync(ctx, absPath, "/workspace", namespace, podName, excludeCfg)
+	return s.initialSyncToRoot(ctx, absPath, namespace, podName, excludeCfg)
 }
 
 // InitialSyncToCustomPath performs one-time sync from local to custom path in pod
@@ -62,23 +74,171 @@ func (s *simpleSyncManager) InitialSyncToCustomPath(ctx context.Context, localPa
 	}
 
 	// Ensure parent directory exists in pod
-	remoteDir := filepath.Dir(remotePath)
+	if err := makeRemoteDir(ctx, s.opts, namespace, podName, filepath.Dir(remotePath)); err != nil {
+		return err
+	}
+
+	return s.initialSync(ctx, absPath, remotePath, namespace, podName, excludeCfg)
+}
+
+// initialSyncToRoot syncs localPath into s.opts.RemoteRoot, creating a sync
+// root below /workspace that the cloned repository does not have yet
+func (s *simpleSyncManager) initialSyncToRoot(ctx context.Context, localPath, namespace, podName string, excludeCfg *exclude.Config) error {
+	root := s.opts.remoteRoot()
+	if root != "/workspace" {
+		if err := makeRemoteDir(ctx, s.opts, namespace, podName, root); err != nil {
+			return err
+		}
+	}
+	return s.initialSync(ctx, localPath, root, namespace, podName, excludeCfg)
+}
+
+// makeRemoteDir creates dir and its parents in the pod
+func makeRemoteDir(ctx context.Context, opts Options, namespace, podName, dir string) error {
 	//#nosec G204 -- kubectl exec with namespace/pod from session config
-	mkdirCmd := exec.CommandContext(ctx, "kubectl", "exec",
+	mkdirCmd := procutil.CommandContext(ctx, "kubectl", opts.kubectlArgs("exec",
 		"-n", namespace,
 		podName,
 		"--",
-		"mkdir", "-p", remoteDir,
-	)
+		"mkdir", "-p", dir,
+	)...)
 	if err := mkdirCmd.Run(); err != nil {
-		return fmt.Errorf("failed to create parent directory %s in pod: %w", remoteDir, err)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to create directory %s in pod: %w", dir, err)
+	}
+	return nil
+}
+
+// ApplyOwnership sets the owner of remotePath recursively and the mode of the
+// regular files under it. Directories keep their mode so they stay traversable.
+func (s *simpleSyncManager) ApplyOwnership(ctx context.Context, remotePath, namespace, podName, chown, chmod string) error {
+	if chown != "" {
+		//#nosec G204 -- kubectl exec with namespace/pod from session config, chown validated in config
+		chownCmd := procutil.CommandContext(ctx, "kubectl", s.opts.kubectlArgs("exec",
+			"-n", namespace,
+			podName,
+			"--",
+			"chown", "-R", chown, remotePath,
+		)...)
+		if out, err := chownCmd.CombinedOutput(); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to chown %s: %w (output: %s)", remotePath, err, strings.TrimSpace(string(out)))
+		}
 	}
 
-	return s.initialSync(ctx, absPath, remotePath, namespace, podName, excludeCfg)
+	if chmod != "" {
+		//#nosec G204 -- kubectl exec with namespace/pod from session config, chmod validated in config
+		chmodCmd := procutil.CommandContext(ctx, "kubectl", s.opts.kubectlArgs("exec",
+			"-n", namespace,
+			podName,
+			"--",
+			"find", remotePath, "-type", "f", "-exec", "chmod", chmod, "{}", "+",
+		)...)
+		if out, err := chmodCmd.CombinedOutput(); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to chmod %s: %w (output: %s)", remotePath, err, strings.TrimSpace(string(out)))
+		}
+	}
+
+	return nil
+}
+
+// PullFromCustomPath copies remotePath from the pod to localPath. A directory
+// is extracted into localPath; a file is written to localPath.
+func (s *simpleSyncManager) PullFromCustomPath(ctx context.Context, remotePath, localPath, namespace, podName string) error {
+	absPath, err := filepath.Abs(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve absolute path: %w", err)
+	}
+
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	testCmd := procutil.CommandContext(ctx, "kubectl", s.opts.kubectlArgs("exec",
+		"-n", namespace,
+		podName,
+		"--",
+		"test", "-d", remotePath,
+	)...)
+	if err := testCmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		// Not a directory: copy a single file
+		if err := os.MkdirAll(filepath.Dir(absPath), 0o750); err != nil {
+			return fmt.Errorf("failed to create local directory: %w", err)
+		}
+		//#nosec G204 -- kubectl cp with namespace/pod from session config
+		cpCmd := procutil.CommandContext(ctx, "kubectl", s.opts.kubectlArgs("cp",
+			"-n", namespace,
+			fmt.Sprintf("%s:%s", podName, remotePath),
+			absPath,
+		)...)
+		if out, err := cpCmd.CombinedOutput(); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to copy %s from pod: %w (output: %s)", remotePath, err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(absPath, 0o750); err != nil {
+		return fmt.Errorf("failed to create local directory: %w", err)
+	}
+
+	// Use kubectl exec + tar, the reverse of initialSync
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	tarCmd := procutil.CommandContext(ctx, "kubectl", s.opts.kubectlArgs("exec",
+		"-n", namespace,
+		podName,
+		"--",
+		"tar", "czf", "-", "-C", remotePath, ".",
+	)...)
+	untarCmd := procutil.CommandContext(ctx, "tar", "xzf", "-", "-C", absPath)
+
+	pipe, err := tarCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	untarCmd.Stdin = &metrics.CountingReader{R: pipe}
+
+	if err := tarCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start kubectl exec: %w", err)
+	}
+
+	if err := untarCmd.Start(); err != nil {
+		procutil.Kill(tarCmd)
+		return fmt.Errorf("failed to start tar: %w", err)
+	}
+
+	// Wait for the reader first so the pipe is drained before it is closed
+	if err := untarCmd.Wait(); err != nil {
+		// Nothing reads the pipe any more, so kubectl would block on it
+		procutil.Kill(tarCmd)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("tar command failed: %w", err)
+	}
+
+	if err := tarCmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("kubectl exec failed: %w", err)
+	}
+
+	return nil
 }
 
 // Start creates a new sync session using kubectl cp and fsnotify
 func (s *simpleSyncManager) Start(ctx context.Context, sessionName, localPath, namespace, podName string, excludeCfg *exclude.Config) error {
+	out := OutputFor(ctx)
 	// Check if session already exists
 	if _, exists := s.watchers[sessionName]; exists {
 		return fmt.Errorf("sync session '%s' already exists", sessionName)
@@ -107,11 +267,11 @@ func (s *simpleSyncManager) Start(ctx context.Context, sessionName, localPath, n
 	}
 
 	// Initial sync: copy all files to pod
-	fmt.Println("🔄 Performing initial sync...")
-	if syncErr := s.initialSync(ctx, absPath, "/workspace", namespace, podName, excludeCfg); syncErr != nil {
+	fmt.Fprintln(out, "🔄 Performing initial sync...")
+	if syncErr := s.initialSyncToRoot(ctx, absPath, namespace, podName, excludeCfg); syncErr != nil {
 		return fmt.Errorf("initial sync failed: %w", syncErr)
 	}
-	fmt.Println("✓ Initial sync completed")
+	fmt.Fprintln(out, "✓ Initial sync completed")
 
 	// Create file watcher
 	watcher, err := fsnotify.NewWatcher()
@@ -133,71 +293,25 @@ func (s *simpleSyncManager) Start(ctx context.Context, sessionName, localPath, n
 	s.stopChan[sessionName] = stopChan
 
 	// Start watching in background
-	go s.watchFiles(ctx, absPath, namespace, podName, watcher, stopChan, excludeMgr)
+	go s.watchFiles(ctx, absPath, namespace, podName, watcher, stopChan, excludeMgr, s.onFlush)
 
 	return nil
 }
 
 // initialSync performs initial sync of all files
+// The tar archive is built in-process and compressed as s.opts selects.
 func (s *simpleSyncManager) initialSync(ctx context.Context, localPath, remotePath, namespace, podName string, excludeCfg *exclude.Config) error {
-	// Use tar + kubectl exec for efficient initial sync
-
-	// Build tar command arguments
-	tarArgs := []string{"czf", "-"}
-
-	// Add exclude arguments from config
+	// Without a config only .git is excluded, as a safety measure
+	var excludeMgr *exclude.Manager
 	if excludeCfg != nil {
-		excludeMgr, err := exclude.NewManager(*excludeCfg)
+		var err error
+		excludeMgr, err = exclude.NewManager(*excludeCfg)
 		if err != nil {
 			return fmt.Errorf("failed to create exclude manager: %w", err)
 		}
-		tarArgs = append(tarArgs, excludeMgr.GetTarExcludeArgs()...)
-	} else {
-		// Fallback: always exclude .git as safety measure
-		tarArgs = append(tarArgs, "--exclude=.git")
-	}
-
-	// Add source directory
-	tarArgs = append(tarArgs, "-C", localPath, ".")
-
-	// Create tar archive
-	tarCmd := exec.CommandContext(ctx, "tar", tarArgs...)
-
-	// Pipe to kubectl exec to extract in pod
-	untarCmd := exec.CommandContext(ctx, "kubectl", "exec", "-i",
-		"-n", namespace,
-		podName,
-		"--",
-		"tar", "xzf", "-", "-C", remotePath,
-	)
-
-	// Connect pipes
-	pipe, err := tarCmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("failed to create pipe: %w", err)
-	}
-	untarCmd.Stdin = pipe
-
-	// Start both commands
-	if err := tarCmd.Start(); err != nil {
-		return fmt.Errorf("failed to start tar: %w", err)
-	}
-
-	if err := untarCmd.Start(); err != nil {
-		_ = tarCmd.Process.Kill()
-		return fmt.Errorf("failed to start kubectl exec: %w", err)
-	}
-
-	// Wait for completion
-	if err := tarCmd.Wait(); err != nil {
-		return fmt.Errorf("tar command failed: %w", err)
-	}
-
-	if err := untarCmd.Wait(); err != nil {
-		return fmt.Errorf("kubectl exec failed: %w", err)
 	}
 
-	return nil
+	return pushArchive(ctx, s.opts, localPath, remotePath, namespace, podName, nil, excludeMgr)
 }
 
 // addDirRecursive adds directory and subdirectories to watcher
@@ -221,7 +335,8 @@ func (s *simpleSyncManager) addDirRecursive(watcher *fsnotify.Watcher, path stri
 }
 
 // watchFiles monitors file changes and syncs to pod
-func (s *simpleSyncManager) watchFiles(ctx context.Context, localPath, namespace, podName string, watcher *fsnotify.Watcher, stopChan chan struct{}, excludeMgr *exclude.Manager) {
+func (s *simpleSyncManager) watchFiles(ctx context.Context, localPath, namespace, podName string, watcher *fsnotify.Watcher, stopChan chan struct{}, excludeMgr *exclude.Manager, onFlush func(files []string)) {
+	out := OutputFor(ctx)
 	// Debounce timer to batch rapid changes
 	var timer *time.Timer
 	pendingFiles := make(map[string]bool)
@@ -232,45 +347,81 @@ func (s *simpleSyncManager) watchFiles(ctx context.Context, localPath, namespace
 		}
 
 		// Copy pending files to pod
+		var synced []string
 		for file := range pendingFiles {
+			// A cancelled sync stops mid-batch instead of failing every remaining file
+			if ctx.Err() != nil {
+				break
+			}
+
 			relPath, err := filepath.Rel(localPath, file)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to get relative path for %s: %v\n", file, err)
 				continue
 			}
 
-			remotePath := filepath.Join("/workspace", relPath)
+			remotePath := path.Join(s.opts.remoteRoot(), filepath.ToSlash(relPath))
 			remoteDir := filepath.Dir(remotePath)
 
 			// Create parent directory in pod if needed
 			//#nosec G204 -- kubectl exec with namespace/pod from session config
-			mkdirCmd := exec.CommandContext(ctx, "kubectl", "exec",
+			mkdirCmd := procutil.CommandContext(ctx, "kubectl", s.opts.kubectlArgs("exec",
 				"-n", namespace,
 				podName,
 				"--",
 				"mkdir", "-p", remoteDir,
-			)
-			if err := mkdirCmd.Run(); err != nil {
+			)...)
+			if err := mkdirCmd.Run(); err != nil && ctx.Err() == nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to create directory %s: %v\n", remoteDir, err)
 			}
 
+			// Large files only send the blocks the pod's copy lacks
+			if info, statErr := os.Stat(file); statErr == nil && info.Size() >= DeltaMinSize {
+				stats, err := PushDelta(ctx, s.opts, file, remotePath, namespace, podName)
+				if err == nil {
+					metrics.SyncFiles.WithLabelValues(metrics.ResultSuccess).Inc()
+					fmt.Fprintf(out, "📤 Synced: %s (delta: sent %s of %s)\n", relPath, formatBytes(stats.Sent), formatBytes(stats.Size))
+					synced = append(synced, relPath)
+					continue
+				}
+				if ctx.Err() != nil {
+					break
+				}
+				if !errors.Is(err, errNoBaseFile) {
+					fmt.Fprintf(os.Stderr, "Warning: delta sync of %s failed, copying the whole file: %v\n", relPath, err)
+				}
+			}
+
 			// Copy file to pod
 			//#nosec G204 -- kubectl cp with namespace/pod from session config
-			cpCmd := exec.CommandContext(ctx, "kubectl", "cp",
+			cpCmd := procutil.CommandContext(ctx, "kubectl", s.opts.kubectlArgs("cp",
 				"-n", namespace,
 				file,
 				fmt.Sprintf("%s:%s", podName, remotePath),
-			)
+			)...)
 
-			if output, err := cpCmd.CombinedOutput(); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to copy %s: %v (output: %s)\n", relPath, err, string(output))
+			if cpOutput, err := cpCmd.CombinedOutput(); err != nil {
+				if ctx.Err() != nil {
+					break
+				}
+				metrics.SyncFiles.WithLabelValues(metrics.ResultFailure).Inc()
+				fmt.Fprintf(os.Stderr, "Warning: failed to copy %s: %v (output: %s)\n", relPath, err, string(cpOutput))
 			} else {
-				fmt.Printf("📤 Synced: %s\n", relPath)
+				metrics.SyncFiles.WithLabelValues(metrics.ResultSuccess).Inc()
+				if info, statErr := os.Stat(file); statErr == nil {
+					metrics.SyncBytes.Add(float64(info.Size()))
+				}
+				fmt.Fprintf(out, "📤 Synced: %s\n", relPath)
+				synced = append(synced, relPath)
 			}
 		}
 
 		// Clear pending files
 		pendingFiles = make(map[string]bool)
+
+		if onFlush != nil && len(synced) > 0 {
+			onFlush(synced)
+		}
 	}
 
 	for {
@@ -312,6 +463,11 @@ func (s *simpleSyncManager) watchFiles(ctx context.Context, localPath, namespace
 	}
 }
 
+// OnFlush registers a callback run after each batch of changes is pushed
+func (s *simpleSyncManager) OnFlush(fn func(files []string)) {
+	s.onFlush = fn
+}
+
 // Stop terminates a sync session
 func (s *simpleSyncManager) Stop(ctx context.Context, sessionName string) error {
 	watcher, exists := s.watchers[sessionName]
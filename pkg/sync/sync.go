@@ -1,23 +1,144 @@
 package sync
 
 import (
+	"bytes"
 	"context"
+	"io"
+	"os"
+	"sync"
 	"time"
 
 	"github.com/illumination-k/kodama/pkg/sync/exclude"
 )
 
+// output receives sync progress messages
+var output = &lockedWriter{w: os.Stdout}
+
+// SetOutput redirects sync progress messages (default: os.Stdout)
+func SetOutput(w io.Writer) {
+	output.set(w)
+}
+
+// Output returns the writer sync progress messages go to. Writes to it are
+// serialized with the watcher's, so other goroutines can report alongside it.
+func Output() io.Writer {
+	return output
+}
+
+// lockedWriter serializes writes and destination changes from concurrent goroutines
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
+
+func (l *lockedWriter) set(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w = w
+}
+
+// outputKey is the context key for the progress writer of one operation
+type outputKey struct{}
+
+// WithOutput returns a context whose progress messages go to w instead of the
+// shared output, e.g. to tell concurrent sessions of a batch apart
+func WithOutput(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, outputKey{}, w)
+}
+
+// OutputFor returns the progress writer for ctx (default: Output())
+func OutputFor(ctx context.Context) io.Writer {
+	if w, ok := ctx.Value(outputKey{}).(io.Writer); ok {
+		return w
+	}
+	return output
+}
+
+// PrefixWriter prefixes every line written to it and passes complete lines
+// on in a single Write, so lines from concurrent writers never mix
+type PrefixWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    []byte
+}
+
+// NewPrefixWriter creates a PrefixWriter writing to w
+func NewPrefixWriter(w io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{w: w, prefix: prefix}
+}
+
+func (p *PrefixWriter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.buf = append(p.buf, b...)
+	end := bytes.LastIndexByte(p.buf, '\n')
+	if end < 0 {
+		return len(b), nil
+	}
+
+	if _, err := p.w.Write(p.prefixLines(p.buf[:end+1])); err != nil {
+		return 0, err
+	}
+	p.buf = append(p.buf[:0], p.buf[end+1:]...)
+	return len(b), nil
+}
+
+// Flush writes a trailing line that has no newline yet
+func (p *PrefixWriter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.buf) == 0 {
+		return nil
+	}
+	_, err := p.w.Write(p.prefixLines(append(p.buf, '\n')))
+	p.buf = p.buf[:0]
+	return err
+}
+
+// prefixLines prefixes each newline-terminated line in lines
+func (p *PrefixWriter) prefixLines(lines []byte) []byte {
+	var out bytes.Buffer
+	for _, line := range bytes.SplitAfter(lines, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		out.WriteString(p.prefix)
+		out.Write(line)
+	}
+	return out.Bytes()
+}
+
 // SyncManager provides interface for managing file synchronization sessions
 type SyncManager interface {
-	// InitialSync performs one-time sync from local to pod
+	// InitialSync performs one-time sync from local to pod, into Options.RemoteRoot
 	InitialSync(ctx context.Context, localPath, namespace, podName string, excludeCfg *exclude.Config) error
 
 	// InitialSyncToCustomPath performs one-time sync from local to custom path in pod
 	InitialSyncToCustomPath(ctx context.Context, localPath, remotePath, namespace, podName string, excludeCfg *exclude.Config) error
 
+	// ApplyOwnership sets the owner and file mode of a synced path in the pod
+	// Empty chown or chmod leaves that attribute unchanged
+	ApplyOwnership(ctx context.Context, remotePath, namespace, podName, chown, chmod string) error
+
+	// PullFromCustomPath performs one-time sync from a path in the pod to local
+	PullFromCustomPath(ctx context.Context, remotePath, localPath, namespace, podName string) error
+
 	// Start creates a continuous sync session (for attach --sync)
 	Start(ctx context.Context, sessionName, localPath, namespace, podName string, excludeCfg *exclude.Config) error
 
+	// OnFlush registers fn to be called with the relative paths pushed after
+	// each debounced batch of local changes in sessions started afterwards
+	OnFlush(fn func(files []string))
+
 	// Stop terminates a sync session
 	Stop(ctx context.Context, sessionName string) error
 
@@ -35,8 +156,45 @@ type SyncStatus struct {
 	Errors     []string
 }
 
-// NewSyncManager creates a SyncManager instance
+// Options holds the settings of a SyncManager's transfers to the pod
+type Options struct {
+	// RemoteRoot is the directory in the pod InitialSync and Start write
+	// below (default: /workspace), e.g. a subdirectory of a cloned repository
+	RemoteRoot string
+
+	// Container is the container kubectl exec and cp commands run in (default:
+	// the pod's default container), e.g. the sidecar that writes the canonical
+	// workspace of a sandbox session
+	Container string
+
+	// Compression selects how tar streams pushed to the pod are compressed
+	Compression Compression
+}
+
+// remoteRoot returns the directory in the pod the options sync to
+func (o Options) remoteRoot() string {
+	if o.RemoteRoot != "" {
+		return o.RemoteRoot
+	}
+	return "/workspace"
+}
+
+// kubectlArgs adds "-c <container>" after the kubectl verb in args when the
+// options select a container
+func (o Options) kubectlArgs(args ...string) []string {
+	if o.Container == "" || len(args) == 0 {
+		return args
+	}
+	return append([]string{args[0], "-c", o.Container}, args[1:]...)
+}
+
+// NewSyncManager creates a SyncManager instance with default options
 // Currently uses the simple implementation (fsnotify + kubectl cp)
 func NewSyncManager() SyncManager {
 	return NewSimpleSyncManager()
 }
+
+// NewSyncManagerWithOptions creates a SyncManager whose transfers use opts
+func NewSyncManagerWithOptions(opts Options) SyncManager {
+	return newSimpleSyncManager(opts)
+}
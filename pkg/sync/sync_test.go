@@ -0,0 +1,82 @@
+package sync
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	gosync "sync"
+	"testing"
+)
+
+func TestOutput_ConcurrentWrites(t *testing.T) {
+	var out bytes.Buffer
+	SetOutput(&out)
+	t.Cleanup(func() { SetOutput(os.Stdout) })
+
+	var wg gosync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				fmt.Fprintf(Output(), "writer %d line %d\n", i, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 400 {
+		t.Fatalf("got %d lines, want 400", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "writer ") {
+			t.Errorf("interleaved line %q", line)
+		}
+	}
+}
+
+func TestPrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := NewPrefixWriter(&out, "[a] ")
+
+	fmt.Fprint(w, "one\ntw")
+	if got := out.String(); got != "[a] one\n" {
+		t.Errorf("after partial line got %q", got)
+	}
+	fmt.Fprint(w, "o\nthree")
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := "[a] one\n[a] two\n[a] three\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestOptions_KubectlArgs(t *testing.T) {
+	args := []string{"exec", "-n", "dev", "kodama-work", "--", "true"}
+	if got := (Options{}).kubectlArgs(args...); !reflect.DeepEqual(got, args) {
+		t.Errorf("without a container got %v", got)
+	}
+
+	want := []string{"exec", "-c", "workspace-sync", "-n", "dev", "kodama-work", "--", "true"}
+	if got := (Options{Container: "workspace-sync"}).kubectlArgs(args...); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestOptions_RemoteRoot(t *testing.T) {
+	if got := (Options{}).remoteRoot(); got != "/workspace" {
+		t.Errorf("default remoteRoot() = %q, want /workspace", got)
+	}
+	if got := (Options{RemoteRoot: "/workspace/services/payments"}).remoteRoot(); got != "/workspace/services/payments" {
+		t.Errorf("remoteRoot() = %q", got)
+	}
+	if script := RemoteStatScript("/workspace/services/payments"); !strings.HasPrefix(script, "cd '/workspace/services/payments' 2>/dev/null") {
+		t.Errorf("RemoteStatScript() does not list the sync root:\n%s", script)
+	}
+}
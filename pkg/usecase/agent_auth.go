@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"fmt"
+
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// newConfiguredAgentExecutor returns the coding agent executor, running commands
+// with cmdExec (nil = kubectl exec). It authenticates as claudeAuth in the
+// global config selects, or else with CLAUDE_CODE_AUTH_TOKEN or the auth file.
+func newConfiguredAgentExecutor(store *config.Store, cmdExec kubernetes.CommandExecutor) (agent.CodingAgentExecutor, error) {
+	globalConfig, err := store.LoadGlobalConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global config: %w", err)
+	}
+	provider, err := globalConfig.ClaudeAuth.NewProvider()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", config.ErrInvalidClaudeAuth, err)
+	}
+	if provider == nil {
+		if cmdExec == nil {
+			return agent.NewCodingAgentExecutor(), nil
+		}
+		return agent.NewCodingAgentExecutorWithCommandExecutor(cmdExec), nil
+	}
+	return agent.NewCodingAgentExecutorWithAuth(provider, cmdExec), nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// saveAgentLog copies the output log of the session's last agent execution into
+// its local artifacts directory and records the copy on the execution.
+// Failures only warn; the log is still in the pod.
+func saveAgentLog(ctx context.Context, store *config.Store, session *config.SessionConfig) {
+	out := sync.OutputFor(ctx)
+	execution := session.GetLastAgentExecution()
+	if execution == nil || execution.LogPath == "" {
+		return
+	}
+
+	artifactPath, err := copyAgentLog(ctx, store, session, execution.LogPath)
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: Failed to save agent log: %v\n", err)
+		fmt.Fprintf(out, "   The log is still in the pod at %s\n", execution.LogPath)
+		return
+	}
+	execution.ArtifactPath = artifactPath
+	fmt.Fprintf(out, "📝 Agent log saved to %s\n", artifactPath)
+}
+
+// reviewAgentRun records the workspace changes after the session's last agent
+// execution and enforces its protected paths. Failures only warn.
+func reviewAgentRun(ctx context.Context, session *config.SessionConfig) {
+	out := sync.OutputFor(ctx)
+	if err := session.ReviewAgentRun(ctx, sessionExecutor(session)); err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: Failed to review agent changes: %v\n", err)
+	}
+	printProtectedChanges(out, session.GetLastAgentExecution())
+}
+
+// printProtectedChanges warns about agent changes to protected paths
+func printProtectedChanges(out io.Writer, execution *config.AgentExecution) {
+	if execution == nil || len(execution.ProtectedChanges) == 0 {
+		return
+	}
+	if execution.ProtectedReverted {
+		fmt.Fprintf(out, "🔄 Reverted agent changes to %d protected file(s):\n", len(execution.ProtectedChanges))
+	} else {
+		fmt.Fprintf(out, "⚠️  Agent modified %d protected file(s), review before pushing:\n", len(execution.ProtectedChanges))
+	}
+	for _, file := range execution.ProtectedChanges {
+		fmt.Fprintf(out, "   %s\n", file)
+	}
+}
+
+// printAgentChanges prints the diff summary of an agent execution
+func printAgentChanges(out io.Writer, execution *config.AgentExecution) {
+	if execution == nil {
+		return
+	}
+	if len(execution.ChangedFiles) == 0 {
+		fmt.Fprintln(out, "✓ Agent made no changes")
+		return
+	}
+	fmt.Fprintf(out, "📝 Agent changed %d file(s):\n", len(execution.ChangedFiles))
+	for _, line := range strings.Split(execution.DiffStat, "\n") {
+		fmt.Fprintf(out, "   %s\n", line)
+	}
+}
+
+// copyAgentLog reads logPath from the session pod and writes it to the artifacts directory
+func copyAgentLog(ctx context.Context, store *config.Store, session *config.SessionConfig, logPath string) (string, error) {
+	stdout, stderr, err := sessionExecutor(session).ExecInPod(ctx, session.Namespace, session.PodName, []string{"cat", logPath})
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w: %s", logPath, err, strings.TrimSpace(stderr))
+	}
+
+	dir := store.GetArtifactsDir(session.Name)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", dir, err)
+	}
+	artifactPath := filepath.Join(dir, path.Base(logPath))
+	if err := os.WriteFile(artifactPath, []byte(stdout), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", artifactPath, err)
+	}
+	return artifactPath, nil
+}
@@ -0,0 +1,198 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	kodamasync "github.com/illumination-k/kodama/pkg/sync"
+)
+
+// DefaultBatchParallelism is the number of sessions started concurrently by default
+const DefaultBatchParallelism = 4
+
+// BatchTask describes one session to start in a batch
+type BatchTask struct {
+	Name       string `yaml:"name"`
+	Repo       string `yaml:"repo,omitempty"`
+	Branch     string `yaml:"branch,omitempty"`
+	Prompt     string `yaml:"prompt,omitempty"`
+	PromptFile string `yaml:"promptFile,omitempty"`
+	Namespace  string `yaml:"namespace,omitempty"`
+	Image      string `yaml:"image,omitempty"`
+	CPU        string `yaml:"cpu,omitempty"`
+	Memory     string `yaml:"memory,omitempty"`
+	Config     string `yaml:"config,omitempty"`
+}
+
+// BatchFile is the YAML document read by 'kodama batch'
+// Fields in Defaults apply to every task that doesn't set them
+type BatchFile struct {
+	Defaults BatchTask   `yaml:"defaults,omitempty"`
+	Tasks    []BatchTask `yaml:"tasks"`
+}
+
+// BatchResult holds the outcome of starting one batch task
+type BatchResult struct {
+	Session  *config.SessionConfig
+	Err      error
+	Task     BatchTask
+	Duration time.Duration
+}
+
+// BatchOptions controls how a batch is executed
+type BatchOptions struct {
+	// Progress is called after each task finishes (may be nil)
+	Progress       func(done, total int, result BatchResult)
+	KubeconfigPath string
+	Parallelism    int
+	Expires        time.Duration
+}
+
+// startSessionFunc is replaced in tests
+var startSessionFunc = StartSession
+
+// LoadBatchFile reads and validates a batch file, applying defaults to each task
+func LoadBatchFile(path string) (*BatchFile, error) {
+	data, err := os.ReadFile(path) // #nosec G304 -- user-specified batch file
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch file: %w", err)
+	}
+
+	var file BatchFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse batch file: %w", err)
+	}
+
+	if len(file.Tasks) == 0 {
+		return nil, fmt.Errorf("batch file %s has no tasks", path)
+	}
+
+	seen := make(map[string]bool, len(file.Tasks))
+	for i := range file.Tasks {
+		task := &file.Tasks[i]
+		applyBatchDefaults(task, file.Defaults)
+
+		if task.Name == "" {
+			return nil, fmt.Errorf("task #%d: name is required", i+1)
+		}
+		if err := config.ValidateSessionName(task.Name); err != nil {
+			return nil, fmt.Errorf("task #%d: %w", i+1, err)
+		}
+		if seen[task.Name] {
+			return nil, fmt.Errorf("task '%s': duplicate name", task.Name)
+		}
+		seen[task.Name] = true
+
+		if task.Repo == "" {
+			return nil, fmt.Errorf("task '%s': repo is required", task.Name)
+		}
+		if task.Prompt != "" && task.PromptFile != "" {
+			return nil, fmt.Errorf("task '%s': cannot specify both prompt and promptFile", task.Name)
+		}
+	}
+
+	return &file, nil
+}
+
+// RunBatch starts all tasks using a bounded worker pool
+// Results are returned in task order; a failing task does not stop the others.
+func RunBatch(ctx context.Context, file *BatchFile, opts BatchOptions) []BatchResult {
+	parallelism := opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = DefaultBatchParallelism
+	}
+
+	total := len(file.Tasks)
+	results := make([]BatchResult, total)
+	indexes := make(chan int)
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		done int
+	)
+
+	for w := 0; w < parallelism && w < total; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				result := runBatchTask(ctx, file.Tasks[i], opts)
+				results[i] = result
+
+				mu.Lock()
+				done++
+				if opts.Progress != nil {
+					opts.Progress(done, total, result)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range file.Tasks {
+		if ctx.Err() != nil {
+			results[i] = BatchResult{Task: file.Tasks[i], Err: ctx.Err()}
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// runBatchTask starts a single session for a batch task
+// Its progress lines are prefixed with the task name, since sessions start concurrently.
+func runBatchTask(ctx context.Context, task BatchTask, opts BatchOptions) BatchResult {
+	started := time.Now()
+
+	out := kodamasync.NewPrefixWriter(kodamasync.OutputFor(ctx), "["+task.Name+"] ")
+	defer func() { _ = out.Flush() }()
+
+	session, err := startSessionFunc(kodamasync.WithOutput(ctx, out), StartSessionOptions{
+		Name:           task.Name,
+		Repo:           task.Repo,
+		Branch:         task.Branch,
+		Prompt:         task.Prompt,
+		PromptFile:     task.PromptFile,
+		Namespace:      task.Namespace,
+		Image:          task.Image,
+		CPU:            task.CPU,
+		Memory:         task.Memory,
+		ConfigFile:     task.Config,
+		KubeconfigPath: opts.KubeconfigPath,
+		Expires:        opts.Expires,
+	})
+
+	return BatchResult{
+		Task:     task,
+		Session:  session,
+		Err:      err,
+		Duration: time.Since(started),
+	}
+}
+
+// applyBatchDefaults fills unset task fields from defaults
+func applyBatchDefaults(task *BatchTask, defaults BatchTask) {
+	task.Repo = config.CoalesceString(task.Repo, defaults.Repo)
+	task.Branch = config.CoalesceString(task.Branch, defaults.Branch)
+	task.Namespace = config.CoalesceString(task.Namespace, defaults.Namespace)
+	task.Image = config.CoalesceString(task.Image, defaults.Image)
+	task.CPU = config.CoalesceString(task.CPU, defaults.CPU)
+	task.Memory = config.CoalesceString(task.Memory, defaults.Memory)
+	task.Config = config.CoalesceString(task.Config, defaults.Config)
+
+	// A task-level prompt of either kind replaces both default prompt fields
+	if task.Prompt == "" && task.PromptFile == "" {
+		task.Prompt = defaults.Prompt
+		task.PromptFile = defaults.PromptFile
+	}
+}
@@ -0,0 +1,186 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	kodamasync "github.com/illumination-k/kodama/pkg/sync"
+)
+
+func TestLoadBatchFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+		check   func(t *testing.T, file *BatchFile)
+	}{
+		{
+			name: "defaults applied to tasks",
+			content: `defaults:
+  namespace: dev
+  prompt: "Upgrade to Go 1.25"
+tasks:
+  - name: svc-a
+    repo: https://github.com/org/a
+  - name: svc-b
+    repo: https://github.com/org/b
+    namespace: staging
+    promptFile: ./other.md
+`,
+			check: func(t *testing.T, file *BatchFile) {
+				a, b := file.Tasks[0], file.Tasks[1]
+				if a.Namespace != "dev" || a.Prompt != "Upgrade to Go 1.25" {
+					t.Errorf("task a = %+v, want defaults applied", a)
+				}
+				if b.Namespace != "staging" {
+					t.Errorf("task b namespace = %s, want staging", b.Namespace)
+				}
+				if b.Prompt != "" || b.PromptFile != "./other.md" {
+					t.Errorf("task b prompt = %q / %q, want only promptFile", b.Prompt, b.PromptFile)
+				}
+			},
+		},
+		{
+			name:    "no tasks",
+			content: "tasks: []\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing repo",
+			content: "tasks:\n  - name: a\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid name",
+			content: "defaults:\n  repo: r\ntasks:\n  - name: Svc_A\n",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate names",
+			content: "defaults:\n  repo: r\ntasks:\n  - name: a\n  - name: a\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "batch.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write batch file: %v", err)
+			}
+
+			file, err := LoadBatchFile(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadBatchFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, file)
+			}
+		})
+	}
+}
+
+func TestRunBatch(t *testing.T) {
+	var running, maxRunning int32
+
+	original := startSessionFunc
+	defer func() { startSessionFunc = original }()
+
+	startSessionFunc = func(ctx context.Context, opts StartSessionOptions) (*config.SessionConfig, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+
+		if opts.Name == "bad" {
+			return nil, errors.New("boom")
+		}
+		return &config.SessionConfig{Name: opts.Name}, nil
+	}
+
+	file := &BatchFile{Tasks: []BatchTask{
+		{Name: "a", Repo: "r"},
+		{Name: "bad", Repo: "r"},
+		{Name: "c", Repo: "r"},
+		{Name: "d", Repo: "r"},
+		{Name: "e", Repo: "r"},
+	}}
+
+	var progressCalls int
+	results := RunBatch(context.Background(), file, BatchOptions{
+		Parallelism: 2,
+		Progress: func(done, total int, result BatchResult) {
+			progressCalls++
+			if total != 5 {
+				t.Errorf("progress total = %d, want 5", total)
+			}
+		},
+	})
+
+	if len(results) != 5 {
+		t.Fatalf("got %d results, want 5", len(results))
+	}
+	if progressCalls != 5 {
+		t.Errorf("progress called %d times, want 5", progressCalls)
+	}
+	if maxRunning > 2 {
+		t.Errorf("max concurrent starts = %d, want <= 2", maxRunning)
+	}
+
+	for i, want := range []string{"a", "bad", "c", "d", "e"} {
+		if results[i].Task.Name != want {
+			t.Errorf("results[%d] = %s, want %s (task order)", i, results[i].Task.Name, want)
+		}
+	}
+	if results[1].Err == nil {
+		t.Error("expected error for task 'bad'")
+	}
+	if results[0].Err != nil || results[0].Session == nil {
+		t.Errorf("task 'a' result = %+v, want success", results[0])
+	}
+}
+
+func TestRunBatch_PrefixesSessionOutput(t *testing.T) {
+	var out bytes.Buffer
+	kodamasync.SetOutput(&out)
+	t.Cleanup(func() { kodamasync.SetOutput(os.Stdout) })
+
+	original := startSessionFunc
+	defer func() { startSessionFunc = original }()
+
+	startSessionFunc = func(ctx context.Context, opts StartSessionOptions) (*config.SessionConfig, error) {
+		w := kodamasync.OutputFor(ctx)
+		for i := 0; i < 20; i++ {
+			fmt.Fprintf(w, "step %d\n", i)
+		}
+		fmt.Fprint(w, "done")
+		return &config.SessionConfig{Name: opts.Name}, nil
+	}
+
+	file := &BatchFile{Tasks: []BatchTask{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	RunBatch(context.Background(), file, BatchOptions{Parallelism: 3})
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != 63 {
+		t.Fatalf("got %d lines, want 63", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasPrefix(line, "[a] ") && !strings.HasPrefix(line, "[b] ") && !strings.HasPrefix(line, "[c] ") {
+			t.Errorf("line without session prefix: %q", line)
+		}
+	}
+}
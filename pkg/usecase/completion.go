@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// completionTimeout bounds the cluster queries made while completing a flag,
+// so a slow or unreachable cluster doesn't hang the shell
+const completionTimeout = 3 * time.Second
+
+// CompleteNamespaces returns the namespaces starting with prefix, listed from
+// the cluster. Without permission to list namespaces it falls back to the
+// configured default and the current kubeconfig namespace.
+func CompleteNamespaces(ctx context.Context, kubeconfigPath, prefix string) []string {
+	ctx, cancel := context.WithTimeout(ctx, completionTimeout)
+	defer cancel()
+
+	var candidates []string
+	if client, err := KubernetesClient(kubeconfigPath); err == nil {
+		if names, err := client.ListNamespaces(ctx); err == nil {
+			return filterPrefix(names, prefix)
+		}
+		if namespace, err := client.GetCurrentNamespace(); err == nil {
+			candidates = append(candidates, namespace)
+		}
+	}
+	if store, err := OpenStore(); err == nil {
+		if globalConfig, err := store.LoadGlobalConfig(); err == nil {
+			globalConfig = globalConfig.ForContext(kubernetes.CurrentContext(kubeconfigPath))
+			candidates = append(candidates, globalConfig.Defaults.Namespace)
+		}
+	}
+	return filterPrefix(candidates, prefix)
+}
+
+// CompleteImages returns the images starting with prefix from the images list
+// and the default image in the global config
+func CompleteImages(kubeconfigPath, prefix string) []string {
+	store, err := OpenStore()
+	if err != nil {
+		return nil
+	}
+	globalConfig, err := store.LoadGlobalConfig()
+	if err != nil {
+		return nil
+	}
+	globalConfig = globalConfig.ForContext(kubernetes.CurrentContext(kubeconfigPath))
+
+	candidates := append([]string{globalConfig.Defaults.Image}, globalConfig.Images...)
+	return filterPrefix(candidates, prefix)
+}
+
+// filterPrefix returns the distinct non-empty values starting with prefix, sorted
+func filterPrefix(values []string, prefix string) []string {
+	var matches []string
+	for _, value := range values {
+		if value != "" && strings.HasPrefix(value, prefix) && !slices.Contains(matches, value) {
+			matches = append(matches, value)
+		}
+	}
+	slices.Sort(matches)
+	return matches
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeGlobalConfig(t *testing.T, content string) {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	dir := filepath.Join(home, ".kodama")
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompleteImages(t *testing.T) {
+	writeGlobalConfig(t, `defaults:
+  image: ghcr.io/org/dev:latest
+images:
+  - ghcr.io/org/python:3.12
+  - ghcr.io/org/dev:latest
+  - docker.io/library/golang:1.25
+`)
+	kubeconfig := filepath.Join(t.TempDir(), "missing")
+
+	got := CompleteImages(kubeconfig, "ghcr.io/")
+	want := []string{"ghcr.io/org/dev:latest", "ghcr.io/org/python:3.12"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CompleteImages() = %v, want %v", got, want)
+	}
+}
+
+func TestCompleteNamespaces_WithoutCluster(t *testing.T) {
+	writeGlobalConfig(t, "defaults:\n  namespace: agents\n")
+	kubeconfig := filepath.Join(t.TempDir(), "missing")
+
+	if got := CompleteNamespaces(context.Background(), kubeconfig, "a"); !reflect.DeepEqual(got, []string{"agents"}) {
+		t.Errorf("CompleteNamespaces() = %v, want the configured default", got)
+	}
+	if got := CompleteNamespaces(context.Background(), kubeconfig, "x"); len(got) != 0 {
+		t.Errorf("CompleteNamespaces() = %v, want no matches", got)
+	}
+}
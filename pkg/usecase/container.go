@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/container"
+	"github.com/illumination-k/kodama/pkg/gitcmd"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// ErrClusterOnly is returned by commands that need a Kubernetes pod when the
+// session runs as a local docker or podman container
+var ErrClusterOnly = errors.New("not supported for docker/podman sessions")
+
+// requireCluster returns ErrClusterOnly for local sessions, naming command
+func requireCluster(session *config.SessionConfig, command string) error {
+	if session.IsLocalRuntime() {
+		return fmt.Errorf("%s is %w: session '%s' runs as a local %s container", command, ErrClusterOnly, session.Name, session.Runtime)
+	}
+	return nil
+}
+
+// sessionExecutor returns the executor running commands in the session's
+// main container: docker or podman exec for local sessions, kubectl otherwise
+func sessionExecutor(session *config.SessionConfig) kubernetes.CommandExecutor {
+	if session.IsLocalRuntime() {
+		return kubernetes.NewContainerExecutor(session.Runtime)
+	}
+	return newRunExecutor()
+}
+
+// sessionExecArgs returns the program and arguments running command in the
+// session's main container: docker or podman exec in /workspace for local
+// sessions, kubectl exec otherwise
+func sessionExecArgs(session *config.SessionConfig, command []string) (string, []string) {
+	if session.IsLocalRuntime() {
+		return session.Runtime, container.ExecArgs(session.PodName, false, command)
+	}
+	return "kubectl", append([]string{"exec", "-n", session.Namespace, session.PodName, "-c", kubernetes.MainContainerName, "--"}, command...)
+}
+
+// requireRunningContainer returns an error unless a local session's container is running
+func requireRunningContainer(ctx context.Context, session *config.SessionConfig) error {
+	engine := container.Engine(session.Runtime)
+	running, err := engine.Running(ctx, session.PodName)
+	if err != nil {
+		return err
+	}
+	if !running {
+		return fmt.Errorf("container %s is not running\n\nStart the session with:\n  kubectl kodama start %s --runtime %s", session.PodName, session.Name, engine)
+	}
+	return nil
+}
+
+// startContainerSession runs a session saved by StartSession as a local docker
+// or podman container. A synced directory is bind-mounted as the workspace
+// instead of being copied, and a repository is cloned into a volume.
+func startContainerSession(ctx context.Context, store *config.Store, session *config.SessionConfig, resumed bool, localChanges *gitcmd.LocalChanges, opts StartSessionOptions) (*config.SessionConfig, error) {
+	out := sync.OutputFor(ctx)
+	engine := container.Engine(session.Runtime)
+
+	var (
+		containerCreated bool
+		startSucceeded   bool // Set to true at the very end to skip cleanup
+	)
+	defer func() {
+		if startSucceeded {
+			return
+		}
+		// Cleanup must still run after Ctrl+C has canceled ctx
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+		defer cancel()
+
+		if session.Status != config.StatusFailed {
+			session.UpdateStatus(config.StatusFailed)
+			_ = store.SaveSession(session) // Best effort update
+		}
+		if containerCreated {
+			fmt.Fprintln(out, "\n⚠️  Start command failed. Cleaning up created resources...")
+			if removeErr := engine.Remove(ctx, session.PodName); removeErr != nil {
+				fmt.Fprintf(out, "⚠️  Warning: Failed to remove container: %v\n", removeErr)
+				fmt.Fprintf(out, "   Manual cleanup: %s rm -f -v %s\n", engine, session.PodName)
+			} else {
+				fmt.Fprintln(out, "✓ Container removed")
+			}
+		}
+	}()
+
+	session.UpdateStatus(config.StatusStarting)
+	if err := store.SaveSession(session); err != nil {
+		return nil, fmt.Errorf("failed to update session status: %w", err)
+	}
+	fmt.Fprintf(out, "Creating session '%s' with %s...\n", session.Name, engine)
+
+	if session.Image == "" {
+		return nil, fmt.Errorf("container image is required. Specify via --image flag or set default in ~/.kodama/config.yaml")
+	}
+	if len(session.SecretFile.Files) > 0 {
+		fmt.Fprintf(out, "⚠️  Warning: Secret files are not supported with --runtime %s and were skipped\n", engine)
+	}
+	if len(session.Volumes) > 0 {
+		fmt.Fprintf(out, "⚠️  Warning: Template volumes are not supported with --runtime %s and were skipped\n", engine)
+	}
+	if session.Data.IsEnabled() {
+		fmt.Fprintf(out, "⚠️  Warning: Dataset fetching is not supported with --runtime %s and was skipped\n", engine)
+	}
+	if len(session.Tools) > 0 {
+		fmt.Fprintf(out, "⚠️  Warning: Template tools are not supported with --runtime %s and were skipped\n", engine)
+	}
+	if session.Sandbox {
+		fmt.Fprintf(out, "⚠️  Warning: Sandbox mode is not supported with --runtime %s and was skipped\n", engine)
+	}
+	if session.Shared.Enabled {
+		fmt.Fprintf(out, "⚠️  Warning: The shared volume is not supported with --runtime %s and was skipped\n", engine)
+	}
+
+	// Dotenv variables are passed in a file readable only by the user
+	var envFile string
+	if len(session.Env.DotenvFiles) > 0 {
+		fmt.Fprintf(out, "📝 Loading dotenv files...\n")
+		envVars, err := loadDotenvVars(session)
+		if err != nil {
+			return nil, err
+		}
+		if len(envVars) > 0 {
+			if envFile, err = writeEnvFile(envVars); err != nil {
+				return nil, err
+			}
+			defer func() { _ = os.Remove(envFile) }()
+			fmt.Fprintf(out, "✅ Loaded %d environment variables\n", len(envVars))
+		} else {
+			fmt.Fprintf(out, "⚠️  All variables were excluded - no environment variables will be injected\n")
+		}
+	}
+
+	// The synced directory is mounted directly, so there is nothing to keep in sync
+	var workspace string
+	if session.Sync.Enabled {
+		workspace = session.Sync.LocalPath
+		session.Sync.Enabled = false
+	}
+
+	// A container left by the previous attempt is replaced
+	if resumed {
+		if err := engine.Remove(ctx, session.PodName); err != nil {
+			return nil, fmt.Errorf("failed to remove container from previous attempt: %w", err)
+		}
+	}
+
+	fmt.Fprintln(out, "⏳ Creating container...")
+	labels := map[string]string{"app": "kodama", "session": session.PodName}
+	maps.Copy(labels, session.Labels)
+	err := engine.Run(ctx, container.Spec{
+		Name:      session.PodName,
+		Image:     session.Image,
+		Workspace: workspace,
+		EnvFile:   envFile,
+		Labels:    labels,
+		CPU:       session.Resources.CPU,
+		Memory:    session.Resources.Memory,
+		Command:   session.Command,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create container: %w", err)
+	}
+	containerCreated = true
+	if workspace != "" {
+		fmt.Fprintf(out, "✓ Container created with %s mounted at %s\n", workspace, container.WorkspacePath)
+	} else {
+		fmt.Fprintln(out, "✓ Container created")
+	}
+
+	executor := sessionExecutor(session)
+	if session.Repo != "" {
+		fmt.Fprintf(out, "⏳ Cloning repository: %s...\n", session.Repo)
+		script := gitcmd.BuildGitInitScript(session.Repo, session.Branch, &gitcmd.CloneOptions{
+			Depth:        session.GitClone.Depth,
+			SingleBranch: session.GitClone.SingleBranch,
+			ExtraArgs:    session.GitClone.ExtraArgs,
+			Attempts:     session.GitClone.Attempts,
+			MirrorURL:    session.GitClone.Mirror,
+		})
+		if _, stderr, err := executor.ExecInPod(ctx, "", session.PodName, []string{"bash", "-c", script}); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			return nil, fmt.Errorf("failed to clone repository: %w\n%s", err, strings.TrimSpace(stderr))
+		}
+		fmt.Fprintln(out, "✓ Repository cloned")
+	}
+	if err := overlayLocalChanges(ctx, executor, session, localChanges); err != nil {
+		return nil, err
+	}
+
+	// Images are used as they are, so install the CLI the init containers would
+	ensureClaude(ctx, session)
+	ensureGitIdentity(ctx, session)
+
+	if session.Git.InstallHooks {
+		fmt.Fprintln(out, "⏳ Installing git hooks...")
+		if err := installGitHooks(ctx, executor, session); err != nil {
+			fmt.Fprintf(out, "⚠️  Warning: Failed to install git hooks: %v\n", err)
+		} else {
+			fmt.Fprintln(out, "✓ Git hooks installed")
+		}
+	}
+	bootstrapDotfiles(ctx, session)
+
+	session.UpdateStatus(config.StatusRunning)
+	session.UpdatedAt = time.Now()
+	if err := store.SaveSession(session); err != nil {
+		return nil, fmt.Errorf("failed to save final session state: %w", err)
+	}
+
+	agentErr := runStartPrompt(ctx, store, session, opts)
+
+	// Mark start as successful to skip cleanup
+	startSucceeded = true
+
+	if agentErr != nil && opts.FailOnAgentError {
+		return session, fmt.Errorf("%w: %w", ErrAgentFailed, agentErr)
+	}
+	return session, nil
+}
+
+// writeEnvFile writes vars to a temporary --env-file readable only by the user
+func writeEnvFile(vars map[string]string) (string, error) {
+	var content strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(vars)) {
+		// Env files have one variable per line
+		if strings.ContainsAny(vars[name], "\r\n") {
+			return "", fmt.Errorf("environment variable '%s' spans several lines, which container env files cannot hold", name)
+		}
+		fmt.Fprintf(&content, "%s=%s\n", name, vars[name])
+	}
+
+	f, err := os.CreateTemp("", "kodama-env-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create env file: %w", err)
+	}
+	if _, err := f.WriteString(content.String()); err != nil {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write env file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write env file: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// attachToContainer opens a shell, or runs command, in a local session's container
+func attachToContainer(ctx context.Context, session *config.SessionConfig, command string) error {
+	if err := requireRunningContainer(ctx, session); err != nil {
+		return err
+	}
+
+	shellCommand := []string{"/bin/sh", "-c", kubernetes.InteractiveShellScript(session.Shell)}
+	switch {
+	case command != "":
+		shellCommand = kubernetes.ShellCommand(session.Shell, command)
+	case session.Record:
+		shellCommand = []string{"/bin/sh", "-c", kubernetes.RecordedShellScript(session.Shell)}
+	}
+
+	fmt.Fprintf(output, "Attaching to session '%s'...\n", session.Name)
+	//#nosec G204 -- docker or podman exec with user command is the intended functionality
+	execCmd := exec.CommandContext(ctx, session.Runtime, container.ExecArgs(session.PodName, true, terminalCommand(session, shellCommand))...)
+	execCmd.Stdin = os.Stdin
+	execCmd.Stdout = os.Stdout
+	execCmd.Stderr = os.Stderr
+	return execCmd.Run()
+}
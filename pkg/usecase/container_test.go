@@ -0,0 +1,272 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+// stubDocker puts a docker that logs its arguments first on PATH and returns the log
+func stubDocker(t *testing.T) string {
+	t.Helper()
+	return stubDockerScript(t, "")
+}
+
+// stubDockerScript is stubDocker running body after logging the arguments
+func stubDockerScript(t *testing.T, body string) string {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	log := filepath.Join(dir, "docker.log")
+	script := "#!/bin/sh\necho \"$@\" >> '" + log + "'\n" + body
+	if err := os.WriteFile(filepath.Join(dir, "docker"), []byte(script), 0o700); err != nil { //#nosec G306 -- test executable
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return log
+}
+
+func TestStartSession_DockerRuntime(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	log := stubDocker(t)
+	workspace := t.TempDir()
+
+	session, err := StartSession(context.Background(), StartSessionOptions{
+		Name:     "local",
+		SyncPath: workspace,
+		Image:    "ubuntu:24.04",
+		Runtime:  config.RuntimeDocker,
+	})
+	if err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+	if session.Status != config.StatusRunning || session.Runtime != config.RuntimeDocker || session.Namespace != "" {
+		t.Errorf("session = %s/%s in namespace %q, want Running docker session without namespace", session.Status, session.Runtime, session.Namespace)
+	}
+	if session.Sync.Enabled || session.Sync.LocalPath != workspace {
+		t.Errorf("sync = %+v, want the mounted %s without continuous sync", session.Sync, workspace)
+	}
+
+	calls, err := os.ReadFile(log) //#nosec G304 -- test log
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantRun := "run -d --name kodama-local --hostname kodama-local -w /workspace -v " + workspace + ":/workspace"
+	if !strings.Contains(string(calls), wantRun) || !strings.Contains(string(calls), "ubuntu:24.04 sleep infinity") {
+		t.Errorf("docker calls = %q, want %q", calls, wantRun)
+	}
+
+	if err := DeleteSession(context.Background(), DeleteSessionOptions{Name: "local"}); err != nil {
+		t.Fatalf("DeleteSession() error = %v", err)
+	}
+	calls, _ = os.ReadFile(log) //#nosec G304 -- test log
+	if !strings.Contains(string(calls), "rm -f -v kodama-local") {
+		t.Errorf("docker calls = %q, want the container removed", calls)
+	}
+}
+
+func TestStartSession_LocalRuntimeRejectsDryRun(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := StartSession(context.Background(), StartSessionOptions{
+		Name: "local", Image: "ubuntu:24.04", Runtime: config.RuntimePodman, DryRun: true, NoSync: true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--dry-run") {
+		t.Errorf("StartSession() error = %v, want --dry-run rejected", err)
+	}
+}
+
+func TestWriteEnvFile(t *testing.T) {
+	path, err := writeEnvFile(map[string]string{"B": "2", "A": "one two"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.Remove(path) }()
+
+	content, _ := os.ReadFile(path) //#nosec G304 -- test file
+	if string(content) != "A=one two\nB=2\n" {
+		t.Errorf("env file = %q", content)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0o600 {
+		t.Errorf("env file mode = %v, want 0600", info.Mode().Perm())
+	}
+
+	if _, err := writeEnvFile(map[string]string{"KEY": "line1\nline2"}); err == nil {
+		t.Error("writeEnvFile() accepted a multi-line value")
+	}
+}
+
+func TestStartSession_LocalChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("HOME", t.TempDir())
+	log := stubDocker(t)
+	// Keep what the apply script receives on stdin
+	patchLog := filepath.Join(filepath.Dir(log), "stdin.log")
+	script := "#!/bin/sh\necho \"$@\" >> '" + log + "'\nif [ \"$2\" = -i ]; then cat > '" + patchLog + "'; fi\n"
+	if err := os.WriteFile(filepath.Join(filepath.Dir(log), "docker"), []byte(script), 0o700); err != nil { //#nosec G306 -- test executable
+		t.Fatal(err)
+	}
+
+	repo := t.TempDir()
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"-c", "user.name=Jane", "-c", "user.email=jane@example.com", "commit", "-q", "--allow-empty", "-m", "initial"},
+		{"remote", "add", "origin", "git@github.com:org/repo.git"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", repo}, args...)...).CombinedOutput(); err != nil { //#nosec G204 -- test command
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(repo, "wip.go"), []byte("package wip\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(repo)
+
+	_, err := StartSession(context.Background(), StartSessionOptions{
+		Name: "norepo", LocalChanges: true, Image: "ubuntu:24.04", Runtime: config.RuntimeDocker,
+	})
+	if err == nil || !strings.Contains(err.Error(), "--local-changes requires --repo") {
+		t.Errorf("StartSession() error = %v, want --repo required", err)
+	}
+
+	_, err = StartSession(context.Background(), StartSessionOptions{
+		Name: "other", Repo: "https://github.com/org/other.git", LocalChanges: true, Image: "ubuntu:24.04", Runtime: config.RuntimeDocker,
+	})
+	if err == nil || !strings.Contains(err.Error(), "not of --repo") {
+		t.Errorf("StartSession() error = %v, want the origin mismatch refused", err)
+	}
+
+	if _, err := StartSession(context.Background(), StartSessionOptions{
+		Name:         "hybrid",
+		Repo:         "https://github.com/org/repo.git",
+		LocalChanges: true,
+		Image:        "ubuntu:24.04",
+		Runtime:      config.RuntimeDocker,
+	}); err != nil {
+		t.Fatalf("StartSession() error = %v", err)
+	}
+
+	calls, _ := os.ReadFile(log) //#nosec G304 -- test log
+	if !strings.Contains(string(calls), "exec -i kodama-hybrid sh -c set -e") {
+		t.Errorf("docker calls = %q, want the apply script run with stdin", calls)
+	}
+	patch, _ := os.ReadFile(patchLog) //#nosec G304 -- test log
+	if !strings.Contains(string(patch), "+package wip") {
+		t.Errorf("patch = %q, want the untracked wip.go", patch)
+	}
+}
+
+// saveDockerSession stores a running docker session named local
+func saveDockerSession(t *testing.T) {
+	t.Helper()
+	store, err := config.NewStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.EnsureConfigDir(); err != nil {
+		t.Fatal(err)
+	}
+	err = store.SaveSession(&config.SessionConfig{
+		Name:    "local",
+		PodName: "kodama-local",
+		Runtime: config.RuntimeDocker,
+		Status:  config.StatusRunning,
+		Test:    config.TestConfig{Command: "go test ./..."},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalRuntime_CommandsUseEngine(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	log := stubDockerScript(t, `case "$1" in
+inspect) echo true ;;
+logs) echo "container started" ;;
+exec) case "$*" in *"ls -1"*) echo 20260101-120000.log ;; esac ;;
+esac
+`)
+	saveDockerSession(t)
+	ctx := context.Background()
+
+	if err := ExecSession(ctx, ExecSessionOptions{Name: "local", Command: []string{"ls", "-la"}}); err != nil {
+		t.Errorf("ExecSession() error = %v", err)
+	}
+
+	var logs strings.Builder
+	if err := SessionLogs(ctx, SessionLogsOptions{Name: "local", Follow: true, TailLines: 20}, &logs); err != nil {
+		t.Errorf("SessionLogs() error = %v", err)
+	}
+	if logs.String() != "container started\n" {
+		t.Errorf("SessionLogs() output = %q", logs.String())
+	}
+
+	var testOut strings.Builder
+	result, err := RunTests(ctx, RunTestsOptions{Name: "local"}, &testOut, &testOut)
+	if err != nil || !result.Passed {
+		t.Errorf("RunTests() = %+v, %v", result, err)
+	}
+
+	recordings, err := ListRecordings(ctx, "local", "")
+	if err != nil || len(recordings) != 1 || recordings[0] != "20260101-120000" {
+		t.Errorf("ListRecordings() = %v, %v", recordings, err)
+	}
+
+	calls, err := os.ReadFile(log) //#nosec G304 -- test log
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"kodama-local ls -la",
+		"logs --follow --tail 20 kodama-local",
+		"exec -w /workspace kodama-local sh -c cd /workspace && go test ./...",
+		"exec kodama-local sh -c ls -1 /workspace/.kodama/recordings",
+	} {
+		if !strings.Contains(string(calls), want) {
+			t.Errorf("docker calls = %q, want %q", calls, want)
+		}
+	}
+
+	err = ExecSession(ctx, ExecSessionOptions{Name: "local", Container: "sidecar", Command: []string{"ls"}})
+	if err == nil || !strings.Contains(err.Error(), "without sidecars") {
+		t.Errorf("ExecSession() in a sidecar error = %v, want it rejected", err)
+	}
+}
+
+func TestLocalRuntime_RejectsClusterOnlyCommands(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saveDockerSession(t)
+	clientset := useFakeCluster(t)
+	ctx := context.Background()
+
+	_, snapshotErr := SnapshotSession(ctx, SnapshotSessionOptions{Name: "local"})
+	_, restoreErr := RestoreSession(ctx, RestoreSessionOptions{
+		From:  "snapshot.tar.gz",
+		Start: StartSessionOptions{Name: "restored", Runtime: config.RuntimePodman},
+	})
+	for name, err := range map[string]error{
+		"snapshot": snapshotErr,
+		"restore":  restoreErr,
+		"share":    ShareSession(ctx, ShareSessionOptions{Name: "local"}),
+		"revoke":   RevokeShare(ctx, "local", ""),
+		"ssh":      SSHSession(ctx, SSHSessionOptions{Name: "local"}),
+	} {
+		if !errors.Is(err, ErrClusterOnly) {
+			t.Errorf("%s error = %v, want ErrClusterOnly", name, err)
+		}
+	}
+	if actions := clientset.Actions(); len(actions) != 0 {
+		t.Errorf("cluster actions = %v, want none", actions)
+	}
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// contextFiles returns the files attached to a prompt: the session's
+// agent.contextFiles followed by those given with --attach-file
+func contextFiles(session *config.SessionConfig, attach []string) []string {
+	files := slices.Clone(session.Agent.ContextFiles)
+	for _, file := range attach {
+		if !slices.Contains(files, file) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// checkContextFiles fails before a session is started when an attached file
+// cannot be uploaded later
+func checkContextFiles(files []string) error {
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("cannot attach %s: %w", file, err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("cannot attach %s: is a directory", file)
+		}
+	}
+	return nil
+}
+
+// attachContextFiles uploads files to agent.ContextDir in the session and
+// returns their pod paths for agent.PromptWithContextFiles
+func attachContextFiles(ctx context.Context, executor kubernetes.CommandExecutor, session *config.SessionConfig, files []string) ([]string, error) {
+	if len(files) == 0 {
+		return nil, nil
+	}
+	inputExecutor, ok := executor.(kubernetes.InputExecutor)
+	if !ok {
+		return nil, fmt.Errorf("cannot upload attached files to the session")
+	}
+	out := sync.OutputFor(ctx)
+	fmt.Fprintf(out, "⏳ Attaching %d context files...\n", len(files))
+	paths, err := agent.UploadContextFiles(ctx, inputExecutor, session.Namespace, session.PodName, files)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(out, "✓ Context files attached in %s\n", agent.ContextDir)
+	return paths, nil
+}
@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+// ResolveSessionName returns name, or when name is empty, the session syncing
+// the working directory, falling back to the current session set with 'kodama use'
+func ResolveSessionName(name string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+
+	store, err := OpenStore()
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize config store: %w", err)
+	}
+	current, err := store.CurrentSession()
+	if err != nil {
+		return "", err
+	}
+
+	cwd, err := os.Getwd()
+	if err == nil {
+		sessions, listErr := store.ListSessions()
+		if listErr != nil {
+			return "", listErr
+		}
+		if name, err := sessionForDirectory(sessions, cwd, current); name != "" || err != nil {
+			return name, err
+		}
+	}
+
+	if current == "" {
+		return "", config.ErrNoCurrentSession
+	}
+	return current, nil
+}
+
+// sessionForDirectory picks the session syncing dir; when several do, the
+// current session wins and anything else is ambiguous
+func sessionForDirectory(sessions []*config.SessionConfig, dir, current string) (string, error) {
+	matches := config.SessionsForDirectory(sessions, dir)
+	switch len(matches) {
+	case 0:
+		return "", nil
+	case 1:
+		return matches[0].Name, nil
+	}
+
+	names := make([]string, 0, len(matches))
+	for _, session := range matches {
+		if session.Name == current {
+			return current, nil
+		}
+		names = append(names, session.Name)
+	}
+	slices.Sort(names)
+	return "", fmt.Errorf("%w: %s", config.ErrAmbiguousSession, strings.Join(names, ", "))
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+func TestSessionForDirectory(t *testing.T) {
+	sessions := []*config.SessionConfig{
+		{Name: "a", Sync: config.SyncConfig{Enabled: true, LocalPath: "/src/app"}},
+		{Name: "b", Sync: config.SyncConfig{Enabled: true, LocalPath: "/src/app"}},
+		{Name: "lib", Sync: config.SyncConfig{Enabled: true, LocalPath: "/src/lib"}},
+	}
+
+	name, err := sessionForDirectory(sessions, "/src/lib/pkg", "a")
+	require.NoError(t, err)
+	assert.Equal(t, "lib", name)
+
+	name, err = sessionForDirectory(sessions, "/tmp", "a")
+	require.NoError(t, err)
+	assert.Empty(t, name)
+
+	// Ties are broken by the current session
+	name, err = sessionForDirectory(sessions, "/src/app", "b")
+	require.NoError(t, err)
+	assert.Equal(t, "b", name)
+
+	_, err = sessionForDirectory(sessions, "/src/app", "lib")
+	require.ErrorIs(t, err, config.ErrAmbiguousSession)
+	assert.Contains(t, err.Error(), "a, b")
+}
@@ -0,0 +1,269 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/container"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// podDeleteTimeout bounds the wait for the session pod to terminate
+const podDeleteTimeout = 2 * time.Minute
+
+// DeleteSessionOptions contains options for deleting a session
+type DeleteSessionOptions struct {
+	Name           string
+	KubeconfigPath string
+	KeepConfig     bool // Keep the session config with status Stopped
+}
+
+// DeleteSession stops file sync, deletes the session's Kubernetes resources or local container
+// and removes its config. Failures to clean up cluster resources are reported
+// as warnings so that a session whose pod is already gone can still be deleted.
+func DeleteSession(ctx context.Context, opts DeleteSessionOptions) error {
+	// 1. Load session
+	store, err := OpenStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize config store: %w", err)
+	}
+
+	session, err := store.LoadSession(opts.Name)
+	if err != nil {
+		if errors.Is(err, config.ErrSessionNotFound) {
+			return fmt.Errorf("%w: %s", config.ErrSessionNotFound, opts.Name)
+		}
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	// 2. Stop file sync
+	if session.Sync.Enabled && session.Sync.MutagenSession != "" {
+		fmt.Fprintln(output, "⏳ Stopping file sync...")
+		syncMgr := sync.NewSyncManager()
+		if syncErr := syncMgr.Stop(ctx, session.Sync.MutagenSession); syncErr != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Failed to stop sync: %v\n", syncErr)
+		} else {
+			fmt.Fprintln(output, "✓ Sync stopped")
+		}
+	}
+
+	// 3. Delete the container of a local session, or the Kubernetes resources
+	if session.IsLocalRuntime() {
+		fmt.Fprintln(output, "⏳ Removing container...")
+		if err := container.Engine(session.Runtime).Remove(ctx, session.PodName); err != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Failed to remove container: %v\n", err)
+		} else {
+			fmt.Fprintln(output, "✓ Container removed")
+		}
+	} else if k8sClient, err := KubernetesClient(opts.KubeconfigPath); err != nil {
+		fmt.Fprintf(output, "⚠️  Warning: Failed to create kubernetes client: %v\n", err)
+	} else {
+		// 3a. Pull custom directories back from the pod before it is deleted
+		if globalConfig, err := store.LoadGlobalConfig(); err != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Failed to load global config: %v\n", err)
+		} else if customDirs := determineCustomDirs(globalConfig, session); len(customDirs) > 0 {
+			customSyncMgr := sync.NewCustomDirSyncManager(sync.NewSyncManager())
+			if err := customSyncMgr.PullCustomDirs(ctx, customDirs, session.Namespace, session.PodName, globalConfig); err != nil {
+				fmt.Fprintf(output, "⚠️  Warning: Failed to pull custom directories: %v\n", err)
+			}
+		}
+
+		// 3b. Delete the session's secrets and ConfigMaps, tracked or labeled
+		deleted, err := k8sClient.DeleteSessionResources(ctx, session.Namespace, session.Name, session.TrackedResources())
+		for _, resource := range deleted {
+			fmt.Fprintf(output, "✓ Deleted %s %s\n", resource.Kind, resource.Name)
+		}
+		if err != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Failed to delete session secrets and config maps: %v\n", err)
+		}
+
+		// 3c. Delete share ingress, if the session was shared
+		if err := k8sClient.DeleteShareIngress(ctx, session.Namespace, session.PodName); err != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Failed to delete share ingress: %v\n", err)
+		}
+
+		// 3d. Delete pod disruption budget if the session has one
+		if session.Disruption.PodDisruptionBudget {
+			if err := k8sClient.DeletePodDisruptionBudget(ctx, session.Namespace, session.PodName); err != nil {
+				fmt.Fprintf(output, "⚠️  Warning: Failed to delete pod disruption budget: %v\n", err)
+			}
+		}
+
+		// 3e. Delete pod
+		fmt.Fprintln(output, "⏳ Deleting pod...")
+		if err := k8sClient.DeletePod(ctx, session.PodName, session.Namespace); err != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Failed to delete pod: %v\n", err)
+		} else {
+			fmt.Fprintln(output, "✓ Pod deletion initiated")
+
+			// Wait for pod to be fully deleted
+			fmt.Fprintln(output, "⏳ Waiting for pod termination...")
+			if err := k8sClient.WaitForPodDeleted(ctx, session.PodName, session.Namespace, podDeleteTimeout); err != nil {
+				fmt.Fprintf(output, "⚠️  Warning: Failed to confirm pod deletion: %v\n", err)
+			} else {
+				fmt.Fprintln(output, "✓ Pod fully terminated and removed")
+			}
+		}
+	}
+
+	// 4. Delete session config (unless KeepConfig)
+	if !opts.KeepConfig {
+		if err := store.DeleteSession(opts.Name); err != nil {
+			return fmt.Errorf("failed to delete session config: %w", err)
+		}
+		if err := os.RemoveAll(store.GetSSHDir(opts.Name)); err != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Failed to remove SSH keys: %v\n", err)
+		}
+		if err := os.RemoveAll(store.GetSessionDir(opts.Name)); err != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Failed to remove session artifacts: %v\n", err)
+		}
+		fmt.Fprintln(output, "✓ Session config deleted")
+	} else {
+		session.UpdateStatus(config.StatusStopped)
+		if err := store.SaveSession(session); err != nil {
+			return fmt.Errorf("failed to update session status: %w", err)
+		}
+		fmt.Fprintln(output, "✓ Session config kept (status: Stopped)")
+	}
+
+	fmt.Fprintf(output, "\n✨ Session '%s' deleted\n", opts.Name)
+
+	return nil
+}
+
+// DeletePlanItem is a resource or file that DeleteSession removes or keeps
+type DeletePlanItem struct {
+	Kind string // e.g. Pod, Secret, PersistentVolumeClaim, File
+	Name string
+	Note string // Optional detail, e.g. why it is kept
+}
+
+// DeletePlan lists what DeleteSession would remove and what it keeps
+type DeletePlan struct {
+	Remove []DeletePlanItem
+	Keep   []DeletePlanItem
+}
+
+// PlanDeleteSession lists what DeleteSession would do with opts, without
+// changing anything. Secrets and ConfigMaps come from the session's tracked
+// resources and those labeled with it in the cluster; when the cluster cannot
+// be reached, the tracked ones are listed and a warning is printed.
+func PlanDeleteSession(ctx context.Context, opts DeleteSessionOptions) (*DeletePlan, error) {
+	store, err := OpenStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize config store: %w", err)
+	}
+	session, err := store.LoadSession(opts.Name)
+	if err != nil {
+		if errors.Is(err, config.ErrSessionNotFound) {
+			return nil, fmt.Errorf("%w: %s", config.ErrSessionNotFound, opts.Name)
+		}
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	plan := &DeletePlan{}
+	remove := func(kind, name, note string) {
+		plan.Remove = append(plan.Remove, DeletePlanItem{Kind: kind, Name: name, Note: note})
+	}
+	keep := func(kind, name, note string) {
+		plan.Keep = append(plan.Keep, DeletePlanItem{Kind: kind, Name: name, Note: note})
+	}
+
+	if session.Sync.Enabled && session.Sync.MutagenSession != "" {
+		remove("Sync", session.Sync.MutagenSession, "syncing "+session.Sync.LocalPath)
+	}
+
+	if session.IsLocalRuntime() {
+		remove("Container", session.PodName, session.Runtime)
+	} else {
+		planClusterDelete(ctx, session, opts.KubeconfigPath, remove)
+		remove("Port-forwards", "to "+session.PodName, "running attach, ssh and share port-forwards end with the pod")
+		for _, pvc := range []string{session.WorkspacePVC, session.ClaudeHomePVC} {
+			if pvc != "" {
+				keep("PersistentVolumeClaim", session.Namespace+"/"+pvc, "persistent volumes are never deleted")
+			}
+		}
+	}
+
+	sessionPath, err := store.GetSessionPath(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	if opts.KeepConfig {
+		keep("File", sessionPath, "kept with status Stopped (--keep-config)")
+	} else {
+		remove("File", sessionPath, "session config")
+		for _, dir := range []string{store.GetSessionDir(opts.Name), store.GetSSHDir(opts.Name)} {
+			if _, err := os.Stat(dir); err == nil {
+				remove("Directory", dir, "")
+			}
+		}
+	}
+	return plan, nil
+}
+
+// planClusterDelete lists the Kubernetes resources DeleteSession removes
+// The pod is listed even when it cannot be looked up.
+func planClusterDelete(ctx context.Context, session *config.SessionConfig, kubeconfigPath string, remove func(kind, name, note string)) {
+	qualified := func(name string) string { return session.Namespace + "/" + name }
+
+	k8sClient, err := KubernetesClient(kubeconfigPath)
+	if err != nil {
+		fmt.Fprintf(output, "⚠️  Warning: Failed to create kubernetes client, listing tracked resources only: %v\n", err)
+		remove("Pod", qualified(session.PodName), "")
+		for _, resource := range session.TrackedResources() {
+			remove(resource.Kind, qualified(resource.Name), "tracked")
+		}
+		return
+	}
+
+	podNote := ""
+	if _, err := k8sClient.GetPod(ctx, session.PodName, session.Namespace); errors.Is(err, kubernetes.ErrPodNotFound) {
+		podNote = "already gone"
+	}
+	remove("Pod", qualified(session.PodName), podNote)
+
+	resources := session.TrackedResources()
+	labeled, err := k8sClient.ListSessionResources(ctx, session.Namespace, session.Name)
+	if err != nil {
+		fmt.Fprintf(output, "⚠️  Warning: Failed to list labeled resources, listing tracked ones only: %v\n", err)
+	}
+	tracked := make(map[string]bool, len(resources))
+	for _, resource := range resources {
+		tracked[resource.Kind+"/"+resource.Name] = true
+	}
+	for _, resource := range labeled {
+		if !tracked[resource.Kind+"/"+resource.Name] {
+			resources = append(resources, resource)
+		}
+	}
+	for _, resource := range resources {
+		note := "labeled session=" + session.Name
+		if tracked[resource.Kind+"/"+resource.Name] {
+			note = "tracked"
+		}
+		remove(resource.Kind, qualified(resource.Name), note)
+	}
+
+	if shared, err := k8sClient.ShareIngressExists(ctx, session.Namespace, session.PodName); err == nil && shared {
+		remove("Service/Ingress", qualified(kubernetes.ShareResourceName(session.PodName)), "share link")
+	}
+	if session.Disruption.PodDisruptionBudget {
+		remove("PodDisruptionBudget", qualified(session.PodName), "")
+	}
+}
+
+// trackResource records a Secret or ConfigMap in the session before it is
+// created, so a delete after any failure still finds it
+func trackResource(store *config.Store, session *config.SessionConfig, kind, name string) error {
+	session.TrackResource(kind, name)
+	if err := store.SaveSession(session); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
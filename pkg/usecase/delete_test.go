@@ -0,0 +1,46 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+func TestPlanDeleteSession_LocalRuntime(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store, err := OpenStore()
+	require.NoError(t, err)
+	session := &config.SessionConfig{
+		Name: "work", Namespace: "default", PodName: "kodama-work", Runtime: "docker", Status: config.StatusRunning,
+		Sync: config.SyncConfig{Enabled: true, LocalPath: "/src/work", MutagenSession: "kodama-work"},
+	}
+	require.NoError(t, store.SaveSession(session))
+	require.NoError(t, store.SaveSyncManifest("work", config.SyncManifest{}))
+
+	sessionPath, err := store.GetSessionPath("work")
+	require.NoError(t, err)
+
+	plan, err := PlanDeleteSession(context.Background(), DeleteSessionOptions{Name: "work"})
+	require.NoError(t, err)
+	assert.Equal(t, []DeletePlanItem{
+		{Kind: "Sync", Name: "kodama-work", Note: "syncing /src/work"},
+		{Kind: "Container", Name: "kodama-work", Note: "docker"},
+		{Kind: "File", Name: sessionPath, Note: "session config"},
+		{Kind: "Directory", Name: store.GetSessionDir("work")},
+	}, plan.Remove)
+	assert.Empty(t, plan.Keep)
+
+	// Nothing was deleted
+	assert.True(t, store.SessionExists("work"))
+
+	plan, err = PlanDeleteSession(context.Background(), DeleteSessionOptions{Name: "work", KeepConfig: true})
+	require.NoError(t, err)
+	assert.Equal(t, []DeletePlanItem{{Kind: "File", Name: sessionPath, Note: "kept with status Stopped (--keep-config)"}}, plan.Keep)
+
+	_, err = PlanDeleteSession(context.Background(), DeleteSessionOptions{Name: "missing"})
+	assert.ErrorIs(t, err, config.ErrSessionNotFound)
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"io"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// Dependencies provides the config store and Kubernetes clients used by usecases
+// application.NewApp installs a caching implementation so that a command reads
+// the store and kubeconfig once per process.
+type Dependencies interface {
+	Store() (*config.Store, error)
+	KubernetesClient(kubeconfigPath string) (*kubernetes.Client, error)
+}
+
+// defaultDependencies constructs new instances on every call
+type defaultDependencies struct{}
+
+func (defaultDependencies) Store() (*config.Store, error) {
+	return config.NewStore()
+}
+
+func (defaultDependencies) KubernetesClient(kubeconfigPath string) (*kubernetes.Client, error) {
+	return kubernetes.NewClient(kubeconfigPath)
+}
+
+var deps Dependencies = defaultDependencies{}
+
+// SetDependencies replaces the dependencies used by usecases
+func SetDependencies(d Dependencies) {
+	deps = d
+}
+
+// OpenStore returns the config store
+func OpenStore() (*config.Store, error) {
+	return deps.Store()
+}
+
+// KubernetesClient returns the Kubernetes client for kubeconfigPath
+func KubernetesClient(kubeconfigPath string) (*kubernetes.Client, error) {
+	return deps.KubernetesClient(kubeconfigPath)
+}
+
+// output receives progress messages from usecases. It is shared with the sync
+// package, so messages from usecases and the file watcher never interleave.
+var output = sync.Output()
+
+// SetOutput redirects usecase and file sync progress messages (default: os.Stdout)
+// Interactive commands such as AttachSession still use the process terminal.
+func SetOutput(w io.Writer) {
+	sync.SetOutput(w)
+}
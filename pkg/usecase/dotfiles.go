@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/gitcmd"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// bootstrapDotfiles clones the session's dotfiles repository in the main
+// container and runs its installer. Failures only warn: the session is usable
+// without the user's shell configuration.
+func bootstrapDotfiles(ctx context.Context, session *config.SessionConfig) {
+	if !session.Dotfiles.IsEnabled() {
+		return
+	}
+	out := sync.OutputFor(ctx)
+	fmt.Fprintf(out, "⏳ Installing dotfiles from %s...\n", session.Dotfiles.Repo)
+	if err := installDotfiles(ctx, sessionExecutor(session), session); err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: Failed to install dotfiles: %v\n", err)
+		return
+	}
+	fmt.Fprintln(out, "✓ Dotfiles installed")
+}
+
+// installDotfiles runs the dotfiles script in the session's main container
+func installDotfiles(ctx context.Context, executor kubernetes.CommandExecutor, session *config.SessionConfig) error {
+	script := gitcmd.BuildDotfilesScript(gitcmd.Dotfiles{
+		Repo:           session.Dotfiles.Repo,
+		Branch:         session.Dotfiles.Branch,
+		InstallCommand: session.Dotfiles.InstallCommand,
+	})
+	_, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", script})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%s: %w", strings.TrimSpace(stderr), err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestInstallDotfiles(t *testing.T) {
+	session := &config.SessionConfig{
+		Name: "demo", Namespace: "default", PodName: "kodama-demo",
+		Dotfiles: config.DotfilesConfig{Repo: "https://github.com/me/dotfiles", InstallCommand: "chezmoi init --apply --source ."},
+	}
+
+	executor := kubernetes.NewMockExecutor()
+	if err := installDotfiles(context.Background(), executor, session); err != nil {
+		t.Fatalf("installDotfiles() error = %v", err)
+	}
+	if len(executor.Commands) != 1 || !strings.Contains(executor.Commands[0].Command[2], "chezmoi init --apply --source .\n") {
+		t.Errorf("unexpected commands: %v", executor.Commands)
+	}
+
+	executor = kubernetes.NewMockExecutor()
+	executor.SetResponse("sh -c", "", "fatal: repository not found\n", errors.New("exit status 128"))
+	err := installDotfiles(context.Background(), executor, session)
+	if err == nil || !strings.Contains(err.Error(), "repository not found") {
+		t.Errorf("expected the clone error, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// maxEditorConfigSize keeps editor config files within the 1 MiB ConfigMap limit
+const maxEditorConfigSize = 1024 * 1024
+
+// loadEditorConfigFiles reads every regular file under dir, keyed by its
+// slash-separated path relative to dir. A missing directory yields no files.
+func loadEditorConfigFiles(dir string) (map[string]string, error) {
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	files := make(map[string]string)
+	total := 0
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		content, err := os.ReadFile(path) // #nosec G304 -- reading the user's editor config directory
+		if err != nil {
+			return err
+		}
+
+		total += len(content)
+		if total > maxEditorConfigSize {
+			return fmt.Errorf("editor config files in %s exceed %d bytes", dir, maxEditorConfigSize)
+		}
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load editor config files: %w", err)
+	}
+
+	return files, nil
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadEditorConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "helix"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "helix", "config.toml"), []byte("theme = \"onedark\"\n"), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "zellij"), 0o750))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "zellij", "config.kdl"), []byte("pane_frames false\n"), 0o600))
+
+	files, err := loadEditorConfigFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"helix/config.toml": "theme = \"onedark\"\n",
+		"zellij/config.kdl": "pane_frames false\n",
+	}, files)
+}
+
+func TestLoadEditorConfigFiles_MissingDir(t *testing.T) {
+	files, err := loadEditorConfigFiles(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestLoadEditorConfigFiles_TooLarge(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "big"), []byte(strings.Repeat("x", maxEditorConfigSize+1)), 0o600))
+
+	_, err := loadEditorConfigFiles(dir)
+	assert.Error(t, err)
+}
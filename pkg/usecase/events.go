@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// recordEvent appends an event to the session's timeline. The timeline is a
+// debugging aid, so failing to record one only warns.
+func recordEvent(ctx context.Context, store *config.Store, name, eventType, message string, details map[string]string) {
+	if err := store.RecordEvent(name, config.NewSessionEvent(eventType, message, details)); err != nil {
+		fmt.Fprintf(sync.OutputFor(ctx), "⚠️  Warning: Failed to record session event: %v\n", err)
+	}
+}
+
+// recordSyncEvent records a completed sync with the files it changed in the pod
+func recordSyncEvent(ctx context.Context, store *config.Store, name, message string, diff sync.ManifestDiff, started time.Time) {
+	recordEvent(ctx, store, name, config.EventSyncCompleted, message, map[string]string{
+		"added":    strconv.Itoa(len(diff.Added)),
+		"modified": strconv.Itoa(len(diff.Modified)),
+		"deleted":  strconv.Itoa(len(diff.Deleted)),
+		"duration": time.Since(started).Round(time.Millisecond).String(),
+	})
+}
+
+// recordAgentStarted records the start of an agent task
+func recordAgentStarted(ctx context.Context, store *config.Store, name string) {
+	recordEvent(ctx, store, name, config.EventAgentStarted, "Agent task started", nil)
+}
+
+// recordAgentFinished records how the session's last agent task ended
+func recordAgentFinished(ctx context.Context, store *config.Store, session *config.SessionConfig, agentErr error) {
+	event := config.AgentFinishedEvent(session.GetLastAgentExecution(), agentErr)
+	if err := store.RecordEvent(session.Name, event); err != nil {
+		fmt.Fprintf(sync.OutputFor(ctx), "⚠️  Warning: Failed to record session event: %v\n", err)
+	}
+}
+
+// recordAttachEvent records an interactive attach; one-off commands are not recorded
+func recordAttachEvent(ctx context.Context, store *config.Store, session *config.SessionConfig, opts AttachSessionOptions, mode string) {
+	if opts.Command != "" {
+		return
+	}
+	details := map[string]string{"mode": mode}
+	if opts.Container != "" {
+		details["container"] = opts.Container
+	}
+	recordEvent(ctx, store, session.Name, config.EventAttachOpened, "Attach opened", details)
+}
@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/container"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// ExecSessionOptions contains options for running a command in a session
+type ExecSessionOptions struct {
+	Name           string
+	KubeconfigPath string
+	Container      string   // Default: main container
+	Command        []string // Command and arguments, run without a shell
+}
+
+// ExecSession runs a command in a session container, like kubectl exec
+// A TTY is allocated when stdin is a terminal.
+func ExecSession(ctx context.Context, opts ExecSessionOptions) error {
+	if len(opts.Command) == 0 {
+		return fmt.Errorf("command is required (e.g. kubectl kodama exec %s -- ls)", opts.Name)
+	}
+
+	session, err := loadSession(opts.Name)
+	if err != nil {
+		return err
+	}
+	if session.IsLocalRuntime() {
+		return execInContainer(ctx, session, opts)
+	}
+
+	k8sClient, err := KubernetesClient(opts.KubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	containerName := opts.Container
+	if containerName == "" {
+		containerName = kubernetes.MainContainerName
+	}
+	if err := k8sClient.ValidateContainer(ctx, session.PodName, session.Namespace, containerName, false); err != nil {
+		return err
+	}
+
+	args := []string{"exec", "-i"}
+	if stdinIsTerminal() {
+		args = append(args, "-t")
+	}
+	args = append(args, "-n", session.Namespace, session.PodName, "-c", containerName, "--")
+	args = append(args, opts.Command...)
+
+	//#nosec G204 -- kubectl exec with user command is the intended functionality
+	execCmd := exec.CommandContext(ctx, "kubectl", args...)
+	execCmd.Stdin = os.Stdin
+	execCmd.Stdout = os.Stdout
+	execCmd.Stderr = os.Stderr
+
+	return execCmd.Run()
+}
+
+// execInContainer runs a command in a local session's container with docker or podman exec
+func execInContainer(ctx context.Context, session *config.SessionConfig, opts ExecSessionOptions) error {
+	if err := requireMainContainer(session, opts.Container); err != nil {
+		return err
+	}
+	if err := requireRunningContainer(ctx, session); err != nil {
+		return err
+	}
+
+	tty := stdinIsTerminal()
+	args := container.ExecArgs(session.PodName, tty, opts.Command)
+	if !tty {
+		// Keep stdin open so piped input reaches the command
+		args = slices.Insert(args, 1, "-i")
+	}
+
+	//#nosec G204 -- docker or podman exec with user command is the intended functionality
+	execCmd := exec.CommandContext(ctx, session.Runtime, args...)
+	execCmd.Stdin = os.Stdin
+	execCmd.Stdout = os.Stdout
+	execCmd.Stderr = os.Stderr
+
+	return execCmd.Run()
+}
+
+// SessionLogsOptions contains options for showing session logs
+type SessionLogsOptions struct {
+	Name           string
+	KubeconfigPath string
+	Container      string // Default: main container; init containers are allowed
+	Follow         bool
+	TailLines      int64 // 0 = all lines
+}
+
+// SessionLogs copies a session container's logs to out
+func SessionLogs(ctx context.Context, opts SessionLogsOptions, out io.Writer) error {
+	session, err := loadSession(opts.Name)
+	if err != nil {
+		return err
+	}
+	if session.IsLocalRuntime() {
+		return containerLogs(ctx, session, opts, out)
+	}
+
+	k8sClient, err := KubernetesClient(opts.KubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+	containerName := opts.Container
+	if containerName == "" {
+		containerName = kubernetes.MainContainerName
+	}
+	if err := k8sClient.ValidateContainer(ctx, session.PodName, session.Namespace, containerName, true); err != nil {
+		return err
+	}
+
+	stream, err := k8sClient.StreamPodLogs(ctx, session.PodName, session.Namespace, containerName, opts.Follow, opts.TailLines)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stream.Close() }()
+
+	if _, err := io.Copy(out, stream); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read logs: %w", err)
+	}
+	return nil
+}
+
+// containerLogs copies a local session's container logs to out
+func containerLogs(ctx context.Context, session *config.SessionConfig, opts SessionLogsOptions, out io.Writer) error {
+	if err := requireMainContainer(session, opts.Container); err != nil {
+		return err
+	}
+
+	//#nosec G204 -- docker or podman logs with the session's container name
+	logsCmd := procutil.CommandContext(ctx, session.Runtime, container.LogsArgs(session.PodName, opts.Follow, opts.TailLines)...)
+	logsCmd.Stdout = out
+	logsCmd.Stderr = out
+	if err := logsCmd.Run(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("failed to read logs: %w", err)
+	}
+	return nil
+}
+
+// requireMainContainer rejects containers other than the main one for local
+// sessions, which run without sidecars
+func requireMainContainer(session *config.SessionConfig, name string) error {
+	if name != "" && name != kubernetes.MainContainerName {
+		return fmt.Errorf("session '%s' runs as a single %s container without sidecars", session.Name, session.Runtime)
+	}
+	return nil
+}
+
+// loadSession loads a session config
+func loadSession(name string) (*config.SessionConfig, error) {
+	store, err := OpenStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize config store: %w", err)
+	}
+
+	session, err := store.LoadSession(name)
+	if err != nil {
+		if errors.Is(err, config.ErrSessionNotFound) {
+			return nil, fmt.Errorf("%w: %s", config.ErrSessionNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	return session, nil
+}
+
+// loadSessionWithClient loads a session config and creates a Kubernetes client
+func loadSessionWithClient(name, kubeconfigPath string) (*config.SessionConfig, *kubernetes.Client, error) {
+	session, err := loadSession(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	k8sClient, err := KubernetesClient(kubeconfigPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	return session, k8sClient, nil
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/gitcmd"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/kubernetes/initcontainer"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// ensureClaude checks the Claude Code CLI in the session pod and reinstalls it in
+// place when it is missing. Failures only warn: a shell is still useful without
+// the CLI, and pod problems are reported by the attach itself.
+func ensureClaude(ctx context.Context, session *config.SessionConfig) {
+	out := sync.OutputFor(ctx)
+	executor := sessionExecutor(session)
+	repaired, err := kubernetes.EnsureClaude(ctx, executor, session.Namespace, session.PodName)
+	switch {
+	case errors.Is(err, kubernetes.ErrClaudeUnavailable):
+		fmt.Fprintf(out, "⚠️  Warning: %v\n", err)
+		fmt.Fprintf(out, "   Reinstalling needs network access to %s and curl or apt-get in the image\n", initcontainer.ClaudeInstallScriptURL)
+	case err != nil:
+		// The pod is unreachable; attaching reports why
+	case repaired:
+		fmt.Fprintln(out, "✓ Claude Code CLI was missing and has been reinstalled")
+	}
+}
+
+// Checks of the agent binaries after the pod becomes ready, variables so tests
+// need not wait
+var (
+	agentReadyAttempts = kubernetes.DefaultAgentReadyAttempts
+	agentReadyInterval = kubernetes.DefaultAgentReadyInterval
+)
+
+// verifyAgentBinaries waits until 'claude --version', and 'ttyd --version' when
+// ttyd is enabled, succeed in the session pod. Pod readiness alone races the
+// installation with prebuilt or partial images, so the first agent run could
+// find no binary. It returns the version of each binary.
+func verifyAgentBinaries(ctx context.Context, session *config.SessionConfig) (map[string]string, error) {
+	binaries := []string{"claude"}
+	if session.Ttyd.Enabled != nil && *session.Ttyd.Enabled {
+		binaries = append(binaries, "ttyd")
+	}
+	return kubernetes.WaitForAgentBinaries(ctx, sessionExecutor(session), session.Namespace, session.PodName,
+		binaries, agentReadyAttempts, agentReadyInterval)
+}
+
+// agentReadyDetails returns the versions as event details
+func agentReadyDetails(versions map[string]string, elapsed time.Duration) map[string]string {
+	details := map[string]string{"duration": elapsed.Round(time.Millisecond).String()}
+	for binary, version := range versions {
+		if version != "" {
+			details[binary] = version
+		}
+	}
+	return details
+}
+
+// ensureGitIdentity writes the session's commit author and signing settings to
+// the git config in the pod, so commits made after attaching are attributed to
+// the user and signed. Failures only warn, like ensureClaude.
+func ensureGitIdentity(ctx context.Context, session *config.SessionConfig) {
+	out := sync.OutputFor(ctx)
+	script := gitcmd.BuildIdentityScript(gitcmd.Identity{Name: session.GitIdentity.Name, Email: session.GitIdentity.Email}, true) +
+		gitSigningScript(session)
+	if script == "" {
+		return
+	}
+	_, stderr, err := sessionExecutor(session).ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", "set -e\n" + script})
+	// Without stderr the pod is unreachable, which the attach itself reports
+	if err != nil && strings.TrimSpace(stderr) != "" {
+		fmt.Fprintf(out, "⚠️  Warning: failed to configure git: %s\n", strings.TrimSpace(stderr))
+	}
+}
+
+// gitSigningScript returns the script configuring commit signing in the main
+// container, or "" when the session does not sign commits
+func gitSigningScript(session *config.SessionConfig) string {
+	if !session.GitSigning.IsEnabled() {
+		return ""
+	}
+	return gitcmd.BuildSigningScript(gitcmd.Signing{Format: session.GitSigning.Format, Key: session.GitSigning.Key}, true)
+}
+
+// resolveGitIdentity fills the fields missing from configured with the local
+// git config as seen from dir (the synced directory, or the working directory)
+func resolveGitIdentity(ctx context.Context, configured config.GitIdentityConfig, dir string) config.GitIdentityConfig {
+	if configured.Name != "" && configured.Email != "" {
+		return configured
+	}
+	local := gitcmd.LocalIdentity(ctx, dir)
+	return config.GitIdentityConfig{Name: local.Name, Email: local.Email}.Merge(configured)
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/gitcmd"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// installGitHooks installs git hooks in the workspace of a started session, so
+// commits made in the pod run the same checks as local development. It runs in
+// the main container, where the hooks and their tools run at commit time.
+func installGitHooks(ctx context.Context, executor kubernetes.CommandExecutor, session *config.SessionConfig) error {
+	script := gitcmd.BuildHooksScript(session.Git.Hooks)
+	_, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", script})
+	if err != nil {
+		return fmt.Errorf("%s: %w", strings.TrimSpace(stderr), err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestInstallGitHooks(t *testing.T) {
+	session := &config.SessionConfig{
+		Name: "demo", Namespace: "default", PodName: "kodama-demo",
+		Git: config.GitConfig{InstallHooks: true, Hooks: map[string]string{"pre-commit": "scripts/pre-commit.sh"}},
+	}
+
+	executor := kubernetes.NewMockExecutor()
+	if err := installGitHooks(context.Background(), executor, session); err != nil {
+		t.Fatalf("installGitHooks() error = %v", err)
+	}
+	if len(executor.Commands) != 1 || !strings.Contains(executor.Commands[0].Command[2], "cp 'scripts/pre-commit.sh'") {
+		t.Errorf("unexpected commands: %v", executor.Commands)
+	}
+
+	session.Git.Hooks = nil
+	executor = kubernetes.NewMockExecutor()
+	executor.SetResponse("sh -c", "", "pre-commit is not installed in the image\n", errors.New("exit status 1"))
+	err := installGitHooks(context.Background(), executor, session)
+	if err == nil || !strings.Contains(err.Error(), "pre-commit is not installed") {
+		t.Errorf("expected the script's reason in the error, got %v", err)
+	}
+}
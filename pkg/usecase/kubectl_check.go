@@ -0,0 +1,86 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+const (
+	// kubectlCheckTTL is how long a passing check is trusted
+	kubectlCheckTTL = 24 * time.Hour
+
+	// kubectlRecheckTTL is how long a check with problems, or one that could not
+	// reach the cluster, is trusted, so the warning recurs until it is fixed
+	kubectlRecheckTTL = time.Hour
+
+	// serverVersionTimeout bounds the startup check against an unreachable cluster
+	serverVersionTimeout = 5 * time.Second
+)
+
+// KubectlProblems checks kubectl against the current context of kubeconfigPath
+// and returns the problems found. Checks are cached in the config directory per
+// kubeconfig context, so the problems are only returned when the check runs:
+// at most once per kubectlCheckTTL, or kubectlRecheckTTL while there are
+// problems. Failing to read or write the cache only repeats the check.
+func KubectlProblems(ctx context.Context, kubeconfigPath string) []string {
+	store, err := OpenStore()
+	if err != nil {
+		return nil
+	}
+	cachePath := store.GetKubectlCheckPath()
+	key := kubernetes.KubectlCheckKey(kubeconfigPath)
+
+	checks := map[string]*kubernetes.KubectlCheck{}
+	if data, err := os.ReadFile(cachePath); err == nil { //#nosec G304 -- path in the config directory
+		_ = json.Unmarshal(data, &checks)
+	}
+	if cached, ok := checks[key]; ok && cached != nil && kubectlCheckFresh(cached, time.Now()) {
+		return nil
+	}
+
+	check := kubernetes.CheckKubectl(ctx, serverVersion(kubeconfigPath))
+	checks[key] = check
+	if data, err := json.MarshalIndent(checks, "", "  "); err == nil {
+		if err := os.MkdirAll(filepath.Dir(cachePath), 0o700); err == nil {
+			_ = os.WriteFile(cachePath, data, 0o600)
+		}
+	}
+	return check.Problems
+}
+
+// kubectlCheckFresh reports whether a cached check still applies at now
+func kubectlCheckFresh(check *kubernetes.KubectlCheck, now time.Time) bool {
+	ttl := kubectlCheckTTL
+	if len(check.Problems) > 0 || check.ServerVersion == "" {
+		ttl = kubectlRecheckTTL
+	}
+	if now.Sub(check.CheckedAt) > ttl {
+		return false
+	}
+	return !check.KubectlChanged()
+}
+
+// serverVersion returns the Kubernetes server version, or "" when the cluster
+// is unreachable within serverVersionTimeout
+func serverVersion(kubeconfigPath string) string {
+	client, err := KubernetesClient(kubeconfigPath)
+	if err != nil {
+		return ""
+	}
+	result := make(chan string, 1)
+	go func() {
+		v, _ := client.ServerVersion()
+		result <- v
+	}()
+	select {
+	case v := <-result:
+		return v
+	case <-time.After(serverVersionTimeout):
+		return ""
+	}
+}
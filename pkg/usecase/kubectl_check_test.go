@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestKubectlProblems_Cached(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+	dir := t.TempDir()
+	log := filepath.Join(dir, "kubectl.log")
+	script := "#!/bin/sh\necho \"$@\" >> '" + log + "'\n[ \"$1\" = exec ] && exit 1\nexit 0\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "kubectl"), []byte(script), 0o700)) //#nosec G306 -- test executable
+	t.Setenv("PATH", dir)
+
+	problems := KubectlProblems(context.Background(), "")
+	require.NotEmpty(t, problems)
+	assert.Equal(t, "kubectl does not support 'exec'", problems[0])
+	calls, err := os.ReadFile(log)
+	require.NoError(t, err)
+
+	// The cached check is not repeated nor reported again
+	assert.Empty(t, KubectlProblems(context.Background(), ""))
+	again, err := os.ReadFile(log)
+	require.NoError(t, err)
+	assert.Equal(t, string(calls), string(again))
+}
+
+func TestKubectlCheckFresh(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	now := time.Now()
+
+	tests := []struct {
+		name  string
+		check kubernetes.KubectlCheck
+		want  bool
+	}{
+		{"passing", kubernetes.KubectlCheck{ServerVersion: "v1.32.1", CheckedAt: now.Add(-2 * time.Hour)}, true},
+		{"expired", kubernetes.KubectlCheck{ServerVersion: "v1.32.1", CheckedAt: now.Add(-25 * time.Hour)}, false},
+		{"problems recheck sooner", kubernetes.KubectlCheck{ServerVersion: "v1.32.1", Problems: []string{"x"}, CheckedAt: now.Add(-2 * time.Hour)}, false},
+		{"unreachable cluster rechecks sooner", kubernetes.KubectlCheck{CheckedAt: now.Add(-2 * time.Hour)}, false},
+		{"kubectl removed since", kubernetes.KubectlCheck{KubectlPath: "/usr/bin/kubectl", ServerVersion: "v1.32.1", CheckedAt: now}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, kubectlCheckFresh(&tt.check, now))
+		})
+	}
+}
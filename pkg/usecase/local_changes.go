@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/gitcmd"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// readLocalChanges reads the uncommitted changes of the repository containing
+// the current directory, which --local-changes applies to the clone of repo
+// The local repository must be a clone of repo, so the changes are not applied
+// to another project.
+func readLocalChanges(ctx context.Context, repo string) (*gitcmd.LocalChanges, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+	origin, err := gitcmd.OriginURL(ctx, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("--local-changes: %w", err)
+	}
+	if !gitcmd.SameRepository(origin, repo) {
+		return nil, fmt.Errorf("--local-changes: the local repository is a clone of %s, not of --repo %s", origin, repo)
+	}
+	changes, err := gitcmd.ReadLocalChanges(ctx, cwd)
+	if err != nil {
+		return nil, fmt.Errorf("--local-changes: %w", err)
+	}
+	return changes, nil
+}
+
+// overlayLocalChanges applies local changes to the cloned workspace of a
+// started session, so the agent starts from the local working tree without
+// uploading all of it. Only the diff is sent, on the stdin of the apply script.
+func overlayLocalChanges(ctx context.Context, executor kubernetes.CommandExecutor, session *config.SessionConfig, changes *gitcmd.LocalChanges) error {
+	if changes == nil {
+		return nil
+	}
+	out := sync.OutputFor(ctx)
+	if len(changes.Files) == 0 {
+		fmt.Fprintln(out, "✓ No local changes to apply")
+		return nil
+	}
+
+	inputExecutor, ok := executor.(kubernetes.InputExecutor)
+	if !ok {
+		return fmt.Errorf("cannot send local changes to the session")
+	}
+	fmt.Fprintf(out, "⏳ Applying local changes on %.12s (%d files)...\n", changes.Base, len(changes.Files))
+	script := gitcmd.BuildApplyChangesScript(changes.Base, changes.Files)
+	_, stderr, err := inputExecutor.ExecInPodWithInput(ctx, session.Namespace, session.PodName,
+		[]string{"sh", "-c", script}, bytes.NewReader(changes.Patch))
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to apply local changes: %s: %w", strings.TrimSpace(stderr), err)
+	}
+	fmt.Fprintln(out, "✓ Local changes applied")
+	return nil
+}
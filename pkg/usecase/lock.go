@@ -0,0 +1,31 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// lockSession takes the session lock for this invocation and renews it in the
+// background until the returned release function is called. A missing pod is
+// not an error here so that callers can report it with their own hints.
+func lockSession(ctx context.Context, session *config.SessionConfig, kubeconfigPath string, steal bool) (release func(), err error) {
+	out := sync.OutputFor(ctx)
+	k8sClient, err := KubernetesClient(kubeconfigPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	release, err = kubernetes.HoldSessionLock(ctx, k8sClient, session.Namespace, session.PodName, kubernetes.LockHolder(), kubernetes.DefaultLockTTL, steal, func(err error) {
+		// Another user may steal the lock; keep the attach alive and let them win
+		fmt.Fprintf(out, "\n⚠️  Warning: Failed to renew session lock: %v\n", err)
+	})
+	if errors.Is(err, kubernetes.ErrPodNotFound) {
+		return func() {}, nil
+	}
+	return release, err
+}
@@ -46,6 +46,19 @@ func WriteManifestsYAML(manifests *ManifestCollection, w io.Writer) error {
 		needsSeparator = true
 	}
 
+	// Write editor config map if present
+	if manifests.EditorConfigMap != nil {
+		if needsSeparator {
+			if _, err := fmt.Fprintln(w, "---"); err != nil {
+				return fmt.Errorf("failed to write separator: %w", err)
+			}
+		}
+		if err := writeYAML(manifests.EditorConfigMap, w); err != nil {
+			return fmt.Errorf("failed to write editor config map: %w", err)
+		}
+		needsSeparator = true
+	}
+
 	// Write pod (required)
 	if manifests.Pod == nil {
 		return fmt.Errorf("pod manifest is required but not present")
@@ -59,6 +72,16 @@ func WriteManifestsYAML(manifests *ManifestCollection, w io.Writer) error {
 		return fmt.Errorf("failed to write pod: %w", err)
 	}
 
+	// Write pod disruption budget if present
+	if manifests.DisruptionBudget != nil {
+		if _, err := fmt.Fprintln(w, "---"); err != nil {
+			return fmt.Errorf("failed to write separator: %w", err)
+		}
+		if err := writeYAML(manifests.DisruptionBudget, w); err != nil {
+			return fmt.Errorf("failed to write pod disruption budget: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -83,8 +106,16 @@ func WriteManifestsJSON(manifests *ManifestCollection, w io.Writer) error {
 		items = append(items, manifests.FileSecret)
 	}
 
+	if manifests.EditorConfigMap != nil {
+		items = append(items, manifests.EditorConfigMap)
+	}
+
 	items = append(items, manifests.Pod)
 
+	if manifests.DisruptionBudget != nil {
+		items = append(items, manifests.DisruptionBudget)
+	}
+
 	// Create Kubernetes List object
 	list := map[string]interface{}{
 		"apiVersion": "v1",
@@ -124,7 +155,9 @@ func RedactSecrets(manifests *ManifestCollection) *ManifestCollection {
 
 	// Create a deep copy to avoid modifying original
 	redacted := &ManifestCollection{
-		Pod: manifests.Pod.DeepCopy(),
+		Pod:              manifests.Pod.DeepCopy(),
+		EditorConfigMap:  manifests.EditorConfigMap, // Editor config files are not secret
+		DisruptionBudget: manifests.DisruptionBudget,
 	}
 
 	if manifests.EnvSecret != nil {
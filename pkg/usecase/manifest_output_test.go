@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -63,6 +64,21 @@ func TestWriteManifestsYAML(t *testing.T) {
 			wantErr:  false,
 			contains: []string{"kind: Secret", "kind: Pod", "---", "name: test-secret", "name: test-pod"},
 		},
+		{
+			name: "pod with disruption budget",
+			manifests: &ManifestCollection{
+				Pod: &corev1.Pod{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Pod"},
+					ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+				},
+				DisruptionBudget: &policyv1.PodDisruptionBudget{
+					TypeMeta:   metav1.TypeMeta{APIVersion: "policy/v1", Kind: "PodDisruptionBudget"},
+					ObjectMeta: metav1.ObjectMeta{Name: "test-pod", Namespace: "default"},
+				},
+			},
+			wantErr:  false,
+			contains: []string{"kind: Pod", "---", "kind: PodDisruptionBudget"},
+		},
 		{
 			name:      "nil manifests",
 			manifests: nil,
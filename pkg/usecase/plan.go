@@ -0,0 +1,47 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// runPlan runs the steps of plan one after another with runStep, following
+// each step's error policy (see config.TaskPlan.Next). It returns the error of
+// the step that stopped the plan; failed steps the plan continued after only
+// warn. An exhausted agent budget or a canceled ctx always stops the plan.
+func runPlan(ctx context.Context, plan *config.TaskPlan, runStep func(step config.PlanStep) error) error {
+	out := sync.OutputFor(ctx)
+	var failed []string
+	for i := 0; i < len(plan.Steps); {
+		step := plan.Steps[i]
+		fmt.Fprintf(out, "\n📋 Plan step %d/%d: %s\n", i+1, len(plan.Steps), step.Name)
+		err := runStep(step)
+		if err != nil && (ctx.Err() != nil || errors.Is(err, config.ErrAgentBudgetExceeded)) {
+			return fmt.Errorf("plan step %q: %w", step.Name, err)
+		}
+
+		next, stop := plan.Next(i, err != nil)
+		if stop {
+			return fmt.Errorf("plan step %q failed: %w", step.Name, err)
+		}
+		if err != nil {
+			failed = append(failed, step.Name)
+			if next < len(plan.Steps) {
+				fmt.Fprintf(out, "↪️  Continuing with step %s\n", plan.Steps[next].Name)
+			}
+		}
+		i = next
+	}
+
+	if len(failed) > 0 {
+		fmt.Fprintf(out, "\n⚠️  Plan finished with failed steps: %s\n", strings.Join(failed, ", "))
+	} else {
+		fmt.Fprintln(out, "\n✓ Plan finished")
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestRunPlan(t *testing.T) {
+	plan := &config.TaskPlan{Steps: []config.PlanStep{
+		{Name: "build", Prompt: "build", OnError: "fix"},
+		{Name: "lint", Prompt: "lint", ContinueOnError: true},
+		{Name: "fix", Prompt: "fix"},
+		{Name: "docs", Prompt: "docs"},
+	}}
+
+	tests := []struct {
+		name    string
+		failing map[string]bool
+		want    []string
+		wantErr string
+	}{
+		{"all succeed", nil, []string{"build", "lint", "docs"}, ""},
+		{"handler runs on failure", map[string]bool{"build": true}, []string{"build", "fix", "docs"}, ""},
+		{"continue on error", map[string]bool{"lint": true}, []string{"build", "lint", "docs"}, ""},
+		{"failure stops", map[string]bool{"build": true, "fix": true}, []string{"build", "fix"}, `plan step "fix" failed`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran []string
+			err := runPlan(context.Background(), plan, func(step config.PlanStep) error {
+				ran = append(ran, step.Name)
+				if tt.failing[step.Name] {
+					return errors.New("agent failed")
+				}
+				return nil
+			})
+			if strings.Join(ran, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ran %v, want %v", ran, tt.want)
+			}
+			if (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("runPlan() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("budget exceeded stops", func(t *testing.T) {
+		var ran int
+		err := runPlan(context.Background(), plan, func(step config.PlanStep) error {
+			ran++
+			return config.ErrAgentBudgetExceeded
+		})
+		if ran != 1 || !errors.Is(err, config.ErrAgentBudgetExceeded) {
+			t.Errorf("ran %d steps, error = %v; want to stop after the first", ran, err)
+		}
+	})
+}
+
+func TestRunPipeline_Plan(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("git -C /workspace rev-parse HEAD", "abc123\n", "", nil)
+	agentExecutor := agent.NewMockCodingAgentExecutor()
+	var prompts []string
+	agentExecutor.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
+		prompts = append(prompts, prompt)
+		if prompt == "Run the migration" {
+			return "", errors.New("migration failed")
+		}
+		return "task", nil
+	}
+	stubRunPipeline(t, executor, agentExecutor)
+
+	planPath := filepath.Join(t.TempDir(), "plan.yaml")
+	if err := os.WriteFile(planPath, []byte(`steps:
+  - name: migrate
+    prompt: Run the migration
+    continueOnError: true
+  - name: tests
+    prompt: Fix the tests
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := RunPipeline(context.Background(), RunOptions{
+		Start:  StartSessionOptions{Name: "ci", Repo: "https://github.com/org/repo", PlanFile: planPath},
+		Output: filepath.Join(t.TempDir(), "out.patch"),
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+	if strings.Join(prompts, "|") != "Run the migration|Fix the tests" {
+		t.Errorf("prompts = %q", prompts)
+	}
+
+	executions := result.Session.AgentExecutions
+	if len(executions) != 2 {
+		t.Fatalf("executions = %+v, want one per step", executions)
+	}
+	if executions[0].Step != "migrate" || executions[0].Status != "failed" || executions[1].Step != "tests" || executions[1].BaseCommit != "abc123" {
+		t.Errorf("executions = %+v", executions)
+	}
+
+	_, err = RunPipeline(context.Background(), RunOptions{
+		Start: StartSessionOptions{Name: "ci", Repo: "https://github.com/org/repo", PlanFile: planPath, Prompt: "also this"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "--plan cannot be combined") {
+		t.Errorf("RunPipeline() error = %v, want --plan and --prompt rejected", err)
+	}
+}
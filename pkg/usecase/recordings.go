@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// ListRecordings returns the terminal recordings of a session, oldest first
+// Each entry is a recording name; its files are <name>.log and <name>.timing.
+func ListRecordings(ctx context.Context, name, kubeconfigPath string) ([]string, error) {
+	session, err := loadSession(name)
+	if err != nil {
+		return nil, err
+	}
+
+	executor := sessionExecutor(session)
+	stdout, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, []string{
+		"sh", "-c", fmt.Sprintf("ls -1 %s 2>/dev/null || true", kubernetes.RecordingsDir),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recordings: %w: %s", err, strings.TrimSpace(stderr))
+	}
+
+	var recordings []string
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.HasSuffix(line, ".log") {
+			recordings = append(recordings, strings.TrimSuffix(line, ".log"))
+		}
+	}
+	return recordings, nil
+}
+
+// PullRecordings copies a session's recordings directory into outDir
+// Returns the local directory containing the recordings.
+func PullRecordings(ctx context.Context, name, kubeconfigPath, outDir string) (string, error) {
+	session, err := loadSession(name)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(outDir, 0o750); err != nil {
+		return "", fmt.Errorf("failed to create %s: %w", outDir, err)
+	}
+
+	program, args := sessionExecArgs(session, []string{
+		"tar", "czf", "-", "-C", path.Dir(kubernetes.RecordingsDir), path.Base(kubernetes.RecordingsDir),
+	})
+	//#nosec G204 -- kubectl, docker or podman exec with the pod or container from session config
+	tarCmd := procutil.CommandContext(ctx, program, args...)
+	//#nosec G204 -- tar extracting into a user-chosen directory
+	untarCmd := procutil.CommandContext(ctx, "tar", "xzf", "-", "-C", outDir)
+
+	pipe, err := tarCmd.StdoutPipe()
+	if err != nil {
+		return "", fmt.Errorf("failed to create pipe: %w", err)
+	}
+	untarCmd.Stdin = pipe
+	var remoteErr strings.Builder
+	tarCmd.Stderr = &remoteErr
+
+	if err := untarCmd.Start(); err != nil {
+		return "", fmt.Errorf("failed to start tar: %w", err)
+	}
+	if err := tarCmd.Run(); err != nil {
+		procutil.Kill(untarCmd)
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		if strings.Contains(remoteErr.String(), "No such file") {
+			return "", fmt.Errorf("session '%s' has no recordings (start it with --record)", session.Name)
+		}
+		return "", fmt.Errorf("failed to archive recordings: %w: %s", err, strings.TrimSpace(remoteErr.String()))
+	}
+	if err := untarCmd.Wait(); err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("failed to extract recordings: %w", err)
+	}
+
+	return strings.TrimSuffix(outDir, "/") + "/" + path.Base(kubernetes.RecordingsDir), nil
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// ErrResizeNeedsRecreate is returned by ResizeSession when the pod cannot be
+// resized in place and Recreate was not requested
+var ErrResizeNeedsRecreate = errors.New("pod must be recreated to resize")
+
+// ResizeSessionOptions contains options for resizing a session
+type ResizeSessionOptions struct {
+	Name           string
+	KubeconfigPath string
+	CPU            string
+	Memory         string
+	Recreate       bool // Skip the in-place attempt and recreate the pod
+}
+
+// ResizeSession changes a session's CPU and memory limits
+// The pod is resized in place when the cluster supports it. Otherwise a guide
+// is printed and ErrResizeNeedsRecreate returned; calling again with Recreate
+// rebuilds the pod with the new limits, keeping its PVCs, secrets and branch.
+func ResizeSession(ctx context.Context, opts ResizeSessionOptions) error {
+	if opts.CPU == "" && opts.Memory == "" {
+		return fmt.Errorf("at least one of --cpu or --memory is required")
+	}
+	for flag, value := range map[string]string{"cpu": opts.CPU, "memory": opts.Memory} {
+		if value == "" {
+			continue
+		}
+		if _, err := resource.ParseQuantity(value); err != nil {
+			return fmt.Errorf("invalid --%s %q: %w", flag, value, err)
+		}
+	}
+
+	store, err := OpenStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize config store: %w", err)
+	}
+
+	session, err := store.LoadSession(opts.Name)
+	if err != nil {
+		if errors.Is(err, config.ErrSessionNotFound) {
+			return fmt.Errorf("%w: %s", config.ErrSessionNotFound, opts.Name)
+		}
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+	// A failed recreate leaves the session Failed; allow retrying it with --recreate
+	if !session.IsRunning() && !(opts.Recreate && session.Status == config.StatusFailed) {
+		return fmt.Errorf("session '%s' is %s; only running sessions can be resized", opts.Name, session.Status)
+	}
+
+	k8sClient, err := KubernetesClient(opts.KubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	cpu := config.CoalesceString(opts.CPU, session.Resources.CPU)
+	memory := config.CoalesceString(opts.Memory, session.Resources.Memory)
+
+	if !opts.Recreate {
+		fmt.Fprintf(output, "⏳ Resizing session '%s' in place (cpu: %s, memory: %s)...\n", session.Name, displayResource(cpu), displayResource(memory))
+		err := k8sClient.ResizePod(ctx, session.PodName, session.Namespace, opts.CPU, opts.Memory, 2*time.Minute)
+		if err == nil {
+			session.Resources.CPU = cpu
+			session.Resources.Memory = memory
+			session.UpdatedAt = time.Now()
+			if err := store.SaveSession(session); err != nil {
+				return fmt.Errorf("failed to save session: %w", err)
+			}
+			fmt.Fprintf(output, "✨ Session '%s' resized without restarting\n", session.Name)
+			return nil
+		}
+		if !errors.Is(err, kubernetes.ErrInPlaceResizeUnsupported) {
+			return err
+		}
+
+		printRecreateGuide(ctx, session, err)
+		return ErrResizeNeedsRecreate
+	}
+
+	fmt.Fprintf(output, "♻️  Recreating session '%s' (cpu: %s, memory: %s)...\n", session.Name, displayResource(cpu), displayResource(memory))
+
+	if err := k8sClient.DeletePodKeepingDependents(ctx, session.PodName, session.Namespace); err != nil {
+		return err
+	}
+	if err := k8sClient.WaitForPodDeleted(ctx, session.PodName, session.Namespace, 2*time.Minute); err != nil {
+		return err
+	}
+	fmt.Fprintln(output, "✓ Old pod deleted")
+
+	session.Resources.CPU = cpu
+	session.Resources.Memory = memory
+	if err := rebuildSessionPod(ctx, store, k8sClient, session); err != nil {
+		session.UpdateStatus(config.StatusFailed)
+		_ = store.SaveSession(session)
+		return fmt.Errorf("failed to recreate pod: %w\n\nRetry with:\n  kubectl kodama resize %s --recreate", err, session.Name)
+	}
+
+	fmt.Fprintf(output, "✨ Session '%s' resized\n", session.Name)
+	return nil
+}
+
+// printRecreateGuide explains why the pod must be recreated and what survives
+func printRecreateGuide(ctx context.Context, session *config.SessionConfig, cause error) {
+	fmt.Fprintf(output, "⚠️  In-place resize is not possible: %v\n\n", cause)
+	fmt.Fprintln(output, "The pod can be recreated with the new resources instead:")
+	if session.WorkspacePVC != "" {
+		fmt.Fprintf(output, "  ✓ Workspace kept (PVC %s)\n", session.WorkspacePVC)
+	} else if session.Repo != "" {
+		fmt.Fprintf(output, "  ✓ Repository re-cloned on branch %s\n", session.Branch)
+		fmt.Fprintln(output, "  ✗ Uncommitted or unpushed changes in the pod are lost")
+		if dirty := unsavedWorkspaceChanges(ctx, session); dirty != "" {
+			fmt.Fprintf(output, "    The workspace currently has unsaved work:\n%s\n", indent(dirty, "      "))
+			fmt.Fprintf(output, "    Push it first: kubectl kodama attach %s\n", session.Name)
+		}
+	}
+	if session.ClaudeHomePVC != "" {
+		fmt.Fprintf(output, "  ✓ Claude home kept (PVC %s)\n", session.ClaudeHomePVC)
+	}
+	if session.Sync.Enabled {
+		fmt.Fprintf(output, "  ✓ Local files re-synced from %s\n", session.Sync.LocalPath)
+	}
+	fmt.Fprintln(output, "  ✓ Env and file secrets reused")
+	fmt.Fprintln(output, "  ✗ Running processes and attach connections are stopped")
+}
+
+// unsavedWorkspaceChanges returns uncommitted and unpushed git changes in the
+// pod workspace, or "" if there are none or they cannot be determined
+func unsavedWorkspaceChanges(ctx context.Context, session *config.SessionConfig) string {
+	executor := kubernetes.NewKubectlExecutor()
+	stdout, _, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, []string{
+		"sh", "-c", "cd /workspace && git status --porcelain && git log --oneline @{upstream}..HEAD 2>/dev/null; true",
+	})
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(stdout)
+}
+
+// displayResource shows "unlimited" for an empty limit
+func displayResource(value string) string {
+	if value == "" {
+		return "unlimited"
+	}
+	return value
+}
+
+// indent prefixes every line of s
+func indent(s, prefix string) string {
+	return prefix + strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
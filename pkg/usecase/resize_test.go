@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResizeSession_InvalidOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    ResizeSessionOptions
+		wantErr string
+	}{
+		{
+			name:    "no resources",
+			opts:    ResizeSessionOptions{Name: "work"},
+			wantErr: "at least one of --cpu or --memory is required",
+		},
+		{
+			name:    "invalid cpu",
+			opts:    ResizeSessionOptions{Name: "work", CPU: "four"},
+			wantErr: `invalid --cpu "four"`,
+		},
+		{
+			name:    "invalid memory",
+			opts:    ResizeSessionOptions{Name: "work", Memory: "8 gigs"},
+			wantErr: `invalid --memory "8 gigs"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ResizeSession(context.Background(), tt.opts)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// fakeClusterDeps serves the store from $HOME and a client backed by a fake clientset
+type fakeClusterDeps struct {
+	client *kubernetes.Client
+}
+
+func (fakeClusterDeps) Store() (*config.Store, error) {
+	return config.NewStore()
+}
+
+func (d fakeClusterDeps) KubernetesClient(string) (*kubernetes.Client, error) {
+	return d.client, nil
+}
+
+// useFakeCluster makes usecases talk to a fake clientset holding objects
+func useFakeCluster(t *testing.T, objects ...runtime.Object) *fake.Clientset {
+	t.Helper()
+	clientset := fake.NewSimpleClientset(objects...)
+	orig := deps
+	t.Cleanup(func() { deps = orig })
+	SetDependencies(fakeClusterDeps{client: kubernetes.NewClientForClientset(clientset)})
+	return clientset
+}
+
+// saveFailedSession stores a session left behind by a failed start
+func saveFailedSession(t *testing.T, name, namespace string) {
+	t.Helper()
+	store, err := config.NewStore()
+	require.NoError(t, err)
+	require.NoError(t, store.EnsureConfigDir())
+	require.NoError(t, store.SaveSession(&config.SessionConfig{
+		Name:      name,
+		Namespace: namespace,
+		PodName:   "kodama-" + name,
+		Status:    config.StatusFailed,
+		CreatedAt: time.Now().Add(-time.Hour),
+	}))
+}
+
+// resumeActions returns the verb and resource of the actions on name, in order
+func resumeActions(clientset *fake.Clientset, name string) []string {
+	var actions []string
+	for _, action := range clientset.Actions() {
+		var actionName string
+		switch a := action.(type) {
+		case k8stesting.CreateAction:
+			if obj, ok := a.GetObject().(metav1.Object); ok {
+				actionName = obj.GetName()
+			}
+		case k8stesting.DeleteAction:
+			actionName = a.GetName()
+		default:
+			continue
+		}
+		if actionName == name {
+			actions = append(actions, action.GetVerb()+" "+action.GetResource().Resource)
+		}
+	}
+	return actions
+}
+
+func TestPreparePodForResume_ReusesPendingPod(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	client := kubernetes.NewClientForClientset(clientset)
+	ctx := context.Background()
+
+	spec := &kubernetes.PodSpec{Name: "kodama-work", Namespace: "dev", Image: "ubuntu:24.04", CPULimit: "1", MemoryLimit: "2Gi"}
+	pod, err := client.CreatePod(ctx, spec, false)
+	require.NoError(t, err)
+	pod.Status.Phase = corev1.PodPending
+	_, err = clientset.CoreV1().Pods("dev").UpdateStatus(ctx, pod, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	clientset.ClearActions()
+
+	reused, err := preparePodForResume(ctx, client, spec)
+	require.NoError(t, err)
+	assert.True(t, reused)
+	assert.Empty(t, resumeActions(clientset, "kodama-work"), "a pending pod with the same spec is kept")
+
+	// A changed image replaces the pod instead
+	changed := *spec
+	changed.Image = "ubuntu:25.04"
+	reused, err = preparePodForResume(ctx, client, &changed)
+	require.NoError(t, err)
+	assert.False(t, reused)
+	assert.Equal(t, []string{"delete pods"}, resumeActions(clientset, "kodama-work"))
+}
+
+func TestStartSession_ResumeRecreatesFailedPodAndSecrets(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saveFailedSession(t, "work", "dev")
+
+	dir := t.TempDir()
+	envFile := filepath.Join(dir, ".env")
+	require.NoError(t, os.WriteFile(envFile, []byte("API_TOKEN=current\n"), 0o600))
+	keyFile := filepath.Join(dir, "id_ed25519")
+	require.NoError(t, os.WriteFile(keyFile, []byte("current key\n"), 0o600))
+
+	labels := map[string]string{"app": "kodama", "session": "work"}
+	clientset := useFakeCluster(t,
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "kodama-work", Namespace: "dev", Labels: labels},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: kubernetes.MainContainerName, Image: "ubuntu:24.04"}}},
+			Status:     corev1.PodStatus{Phase: corev1.PodFailed},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kodama-env-work", Namespace: "dev", Labels: labels},
+			Data:       map[string][]byte{"API_TOKEN": []byte("stale")},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "kodama-secret-files-work", Namespace: "dev", Labels: labels},
+			Data:       map[string][]byte{"stale": []byte("stale key")},
+		},
+	)
+
+	// The fake pod never becomes ready, so the start stops after the pod is created
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err := StartSession(ctx, StartSessionOptions{
+		Name:        "work",
+		Namespace:   "dev",
+		Repo:        "https://github.com/org/repo.git",
+		Image:       "ubuntu:24.04",
+		EnvFiles:    []string{envFile},
+		SecretFiles: []SecretFileMapping{{Source: keyFile, Destination: "/home/dev/.ssh/id_ed25519"}},
+	})
+	require.ErrorContains(t, err, "pod failed to start")
+
+	assert.Equal(t, []string{"delete pods", "create pods", "delete pods"}, resumeActions(clientset, "kodama-work"),
+		"the failed pod is deleted and recreated, then cleaned up after the failed wait")
+
+	for _, name := range []string{"kodama-env-work", "kodama-secret-files-work"} {
+		actions := resumeActions(clientset, name)
+		require.GreaterOrEqual(t, len(actions), 2, name)
+		assert.Equal(t, []string{"delete secrets", "create secrets"}, actions[:2], "%s is replaced", name)
+	}
+
+	var envData, fileData map[string][]byte
+	for _, action := range clientset.Actions() {
+		create, ok := action.(k8stesting.CreateAction)
+		if !ok {
+			continue
+		}
+		if secret, ok := create.GetObject().(*corev1.Secret); ok {
+			switch secret.Name {
+			case "kodama-env-work":
+				envData = secret.Data
+			case "kodama-secret-files-work":
+				fileData = secret.Data
+			}
+		}
+	}
+	assert.Equal(t, "current", string(envData["API_TOKEN"]))
+	require.NotEmpty(t, fileData)
+	assert.True(t, slices.ContainsFunc(slices.Collect(maps.Values(fileData)), func(v []byte) bool { return string(v) == "current key\n" }),
+		"file secret holds the current file: %v", fileData)
+}
+
+func TestStartSession_ResumeRefusesNamespaceChange(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	saveFailedSession(t, "work", "old")
+	clientset := useFakeCluster(t)
+
+	_, err := StartSession(context.Background(), StartSessionOptions{
+		Name:      "work",
+		Namespace: "dev",
+		Repo:      "https://github.com/org/repo.git",
+		Image:     "ubuntu:24.04",
+	})
+	require.ErrorContains(t, err, "was started in namespace 'old' (now 'dev')")
+	assert.Empty(t, clientset.Actions(), "nothing is created in the new namespace")
+
+	store, err := config.NewStore()
+	require.NoError(t, err)
+	session, err := store.LoadSession("work")
+	require.NoError(t, err)
+	assert.Equal(t, "old", session.Namespace, "the stored session is left as it was")
+}
@@ -0,0 +1,311 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// DefaultRunCommitMessage is used when 'kodama run --push' commits the agent's changes
+const DefaultRunCommitMessage = "Apply changes from kodama agent"
+
+// RunOptions contains options for the headless start → agent → diff pipeline
+type RunOptions struct {
+	Start         StartSessionOptions // Repo mode; Prompt/PromptFile hold the agent task
+	Output        string              // Patch file path (default: <name>.patch)
+	Push          bool                // Commit and push the agent's changes to the session branch
+	PR            bool                // Open a pull request with the GitHub CLI (implies Push)
+	PRBase        string              // Pull request base branch (default: repository default branch)
+	PRTitle       string
+	CommitMessage string
+
+	// Step is called when a pipeline stage begins (may be nil)
+	Step func(title string)
+}
+
+// RunResult holds the artifacts produced by RunPipeline
+type RunResult struct {
+	Session   *config.SessionConfig
+	PatchPath string
+	Changed   bool // Whether the agent modified the workspace
+	Pushed    bool
+	PRURL     string
+}
+
+// ErrProtectedPathsChanged is returned by RunPipeline instead of pushing changes
+// the agent made to protected paths
+var ErrProtectedPathsChanged = errors.New("agent modified protected paths")
+
+// Replaced in tests
+var (
+	newRunExecutor    = kubernetes.NewKubectlExecutor
+	newAgentExecutor  = newConfiguredAgentExecutor
+	createPullRequest = ghCreatePullRequest
+)
+
+// RunPipeline starts a repo-mode session, runs the coding agent to completion,
+// and collects its changes as a patch. The caller is responsible for teardown,
+// which is needed even when an error is returned after the session started.
+func RunPipeline(ctx context.Context, opts RunOptions) (*RunResult, error) {
+	if opts.Start.Repo == "" {
+		return nil, config.ErrRepoRequired
+	}
+	if opts.Start.Prompt == "" && opts.Start.PromptFile == "" && opts.Start.PlanFile == "" {
+		return nil, fmt.Errorf("a prompt is required (--prompt, --prompt-file or --plan)")
+	}
+	if opts.Start.Prompt != "" && opts.Start.PromptFile != "" {
+		return nil, fmt.Errorf("cannot specify both --prompt and --prompt-file")
+	}
+	if opts.Start.PlanFile != "" && (opts.Start.Prompt != "" || opts.Start.PromptFile != "") {
+		return nil, fmt.Errorf("--plan cannot be combined with --prompt or --prompt-file")
+	}
+
+	prompt := opts.Start.Prompt
+	var plan *config.TaskPlan
+	switch {
+	case opts.Start.PlanFile != "":
+		var err error
+		if plan, err = config.LoadTaskPlan(opts.Start.PlanFile); err != nil {
+			return nil, err
+		}
+		// The pull request describes all steps
+		prompts := make([]string, len(plan.Steps))
+		for i, s := range plan.Steps {
+			prompts[i] = s.Prompt
+		}
+		prompt = strings.Join(prompts, "\n\n")
+	case opts.Start.PromptFile != "":
+		var err error
+		if prompt, err = config.ReadPromptFromFile(opts.Start.PromptFile); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkContextFiles(opts.Start.AttachFiles); err != nil {
+		return nil, err
+	}
+
+	result := &RunResult{PatchPath: opts.Output}
+	if result.PatchPath == "" {
+		result.PatchPath = opts.Start.Name + ".patch"
+	}
+
+	// 1. Start the session without a prompt so the base commit can be recorded first
+	step(opts, "Start session")
+	startOpts := opts.Start
+	startOpts.Prompt = ""
+	startOpts.PromptFile = ""
+	startOpts.PlanFile = ""
+	startOpts.AttachFiles = nil
+	session, err := startSessionFunc(ctx, startOpts)
+	if err != nil {
+		return result, err
+	}
+	result.Session = session
+
+	executor := newRunExecutor()
+	base, err := workspaceGit(ctx, executor, session, "rev-parse", "HEAD")
+	if err != nil {
+		return result, err
+	}
+	base = strings.TrimSpace(base)
+
+	// 2. Run the agent and wait for it to finish
+	step(opts, "Run coding agent")
+	fmt.Fprintln(output, "🤖 Running coding agent...")
+	store, err := OpenStore()
+	if err != nil {
+		return result, fmt.Errorf("failed to initialize config store: %w", err)
+	}
+	agentExecutor, err := newAgentExecutor(store, nil)
+	if err != nil {
+		return result, err
+	}
+	contextPaths, err := attachContextFiles(ctx, executor, session, contextFiles(session, opts.Start.AttachFiles))
+	if err != nil {
+		return result, err
+	}
+	runTask := func(prompt, stepName, base string) error {
+		recordAgentStarted(ctx, store, session.Name)
+		agentErr := session.StartAgentWithOptions(ctx, agentExecutor, agent.PromptWithContextFiles(prompt, contextPaths),
+			config.AgentRunOptions{BaseCommit: base, Step: stepName})
+		if agentErr == nil {
+			reviewAgentRun(ctx, session)
+		}
+		recordAgentFinished(ctx, store, session, agentErr)
+		if opts.Start.SaveAgentLog {
+			saveAgentLog(ctx, store, session)
+		}
+		if err := store.SaveSession(session); err != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Failed to save agent execution record: %v\n", err)
+		}
+		return agentErr
+	}
+	firstExecution := len(session.AgentExecutions)
+	var agentErr error
+	if plan != nil {
+		agentErr = runPlan(ctx, plan, func(s config.PlanStep) error {
+			stepBase, err := workspaceGit(ctx, executor, session, "rev-parse", "HEAD")
+			if err != nil {
+				return err
+			}
+			return runTask(s.Prompt, s.Name, strings.TrimSpace(stepBase))
+		})
+	} else {
+		agentErr = runTask(prompt, "", base)
+	}
+	if agentErr != nil {
+		return result, fmt.Errorf("%w: %w", ErrAgentFailed, agentErr)
+	}
+	fmt.Fprintln(output, "✓ Agent finished")
+
+	// 3. Collect everything the agent changed since the base commit, committed or not
+	step(opts, "Collect diff")
+	if _, err := workspaceGit(ctx, executor, session, "add", "-A"); err != nil {
+		return result, err
+	}
+	patch, err := workspaceGit(ctx, executor, session, "diff", "--cached", "--binary", base)
+	if err != nil {
+		return result, err
+	}
+	if err := os.WriteFile(result.PatchPath, []byte(patch), 0o600); err != nil {
+		return result, fmt.Errorf("failed to write patch: %w", err)
+	}
+	result.Changed = patch != ""
+	if !result.Changed {
+		fmt.Fprintln(output, "✓ Agent made no changes")
+		return result, nil
+	}
+	fmt.Fprintf(output, "✓ Patch written to %s\n", result.PatchPath)
+
+	if !opts.Push && !opts.PR {
+		return result, nil
+	}
+	for _, execution := range session.AgentExecutions[firstExecution:] {
+		if execution.Status == config.AgentStatusNeedsReview && !execution.ProtectedReverted {
+			return result, fmt.Errorf("%w: %s", ErrProtectedPathsChanged, strings.Join(execution.ProtectedChanges, ", "))
+		}
+	}
+
+	// 4. Commit and push from the pod, where the clone remote carries GH_TOKEN
+	step(opts, "Push branch")
+	if err := commitAndPush(ctx, executor, session, config.CoalesceString(opts.CommitMessage, DefaultRunCommitMessage)); err != nil {
+		return result, err
+	}
+	result.Pushed = true
+	fmt.Fprintf(output, "✓ Pushed branch %s\n", session.Branch)
+
+	if !opts.PR {
+		return result, nil
+	}
+
+	// 5. Open the pull request from the local machine
+	step(opts, "Open pull request")
+	title := config.CoalesceString(opts.PRTitle, firstLine(prompt))
+	body := fmt.Sprintf("Changes generated by a kodama coding agent session.\n\nPrompt:\n\n```\n%s\n```\n", strings.TrimSpace(prompt))
+	url, err := createPullRequest(ctx, session.Repo, session.Branch, opts.PRBase, title, body)
+	if err != nil {
+		return result, err
+	}
+	result.PRURL = url
+	fmt.Fprintf(output, "✓ Pull request: %s\n", url)
+
+	return result, nil
+}
+
+func step(opts RunOptions, title string) {
+	if opts.Step != nil {
+		opts.Step(title)
+	}
+}
+
+// workspaceGit runs git in the session's /workspace and returns stdout
+func workspaceGit(ctx context.Context, executor kubernetes.CommandExecutor, session *config.SessionConfig, args ...string) (string, error) {
+	command := append([]string{"git", "-C", "/workspace"}, args...)
+	stdout, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, command)
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %s: %w", args[0], strings.TrimSpace(stderr), err)
+	}
+	return stdout, nil
+}
+
+// commitAndPush commits staged changes (if any) and pushes HEAD to the session branch
+func commitAndPush(ctx context.Context, executor kubernetes.CommandExecutor, session *config.SessionConfig, message string) error {
+	status, err := workspaceGit(ctx, executor, session, "status", "--porcelain")
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(status) != "" {
+		// Import the GPG key and enable signing before committing
+		if script := gitSigningScript(session); script != "" {
+			if _, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", "set -e\n" + script}); err != nil {
+				return fmt.Errorf("failed to configure commit signing: %s: %w", strings.TrimSpace(stderr), err)
+			}
+		}
+
+		// Commits are attributed to the session's git identity, falling back to kodama
+		author := config.GitIdentityConfig{Name: "kodama", Email: "kodama@localhost"}.Merge(session.GitIdentity)
+		_, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, []string{
+			"env", "GIT_AUTHOR_NAME=" + author.Name, "GIT_AUTHOR_EMAIL=" + author.Email,
+			"GIT_COMMITTER_NAME=" + author.Name, "GIT_COMMITTER_EMAIL=" + author.Email,
+			"git", "-C", "/workspace", "commit", "-q", "-m", message,
+		})
+		if err != nil {
+			return fmt.Errorf("git commit failed: %s: %w", strings.TrimSpace(stderr), err)
+		}
+	}
+	_, err = workspaceGit(ctx, executor, session, "push", "-q", "origin", "HEAD:refs/heads/"+session.Branch)
+	return err
+}
+
+// ghCreatePullRequest opens a pull request with the GitHub CLI and returns its URL
+func ghCreatePullRequest(ctx context.Context, repo, head, base, title, body string) (string, error) {
+	args := []string{"pr", "create", "--repo", ghRepo(repo), "--head", head, "--title", title, "--body", body}
+	if base != "" {
+		args = append(args, "--base", base)
+	}
+
+	// #nosec G204 -- arguments are passed directly, not through a shell
+	out, err := exec.CommandContext(ctx, "gh", args...).Output()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("gh pr create failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("gh pr create failed (is the GitHub CLI installed?): %w", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ghRepo converts a clone URL into the [HOST/]OWNER/REPO form accepted by gh --repo
+func ghRepo(repo string) string {
+	r := strings.TrimSuffix(repo, ".git")
+	r = strings.TrimPrefix(r, "https://")
+	r = strings.TrimPrefix(r, "ssh://")
+	r = strings.TrimPrefix(r, "git@")
+	r = strings.Replace(r, ":", "/", 1)
+	return strings.TrimPrefix(r, "github.com/")
+}
+
+// firstLine returns the first non-empty line of s, truncated for use as a title
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			if runes := []rune(line); len(runes) > 72 {
+				line = string(runes[:69]) + "..."
+			}
+			return line
+		}
+	}
+	return "kodama agent changes"
+}
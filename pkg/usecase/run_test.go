@@ -0,0 +1,270 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/illumination-k/kodama/pkg/agent"
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// stubRunPipeline replaces the session, exec, agent and PR dependencies of RunPipeline
+func stubRunPipeline(t *testing.T, executor *kubernetes.MockExecutor, agentExecutor *agent.MockCodingAgentExecutor) *[]string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	origStart, origExec, origAgent, origPR := startSessionFunc, newRunExecutor, newAgentExecutor, createPullRequest
+	t.Cleanup(func() {
+		startSessionFunc, newRunExecutor, newAgentExecutor, createPullRequest = origStart, origExec, origAgent, origPR
+	})
+
+	startSessionFunc = func(ctx context.Context, opts StartSessionOptions) (*config.SessionConfig, error) {
+		if opts.Prompt != "" || opts.PromptFile != "" {
+			t.Errorf("session should be started without a prompt")
+		}
+		return &config.SessionConfig{
+			Name: opts.Name, Namespace: "default", PodName: "kodama-" + opts.Name,
+			Repo: opts.Repo, Branch: "kodama/" + opts.Name, Status: config.StatusRunning,
+		}, nil
+	}
+	newRunExecutor = func() kubernetes.CommandExecutor { return executor }
+	newAgentExecutor = func(*config.Store, kubernetes.CommandExecutor) (agent.CodingAgentExecutor, error) {
+		return agentExecutor, nil
+	}
+
+	var prCalls []string
+	createPullRequest = func(ctx context.Context, repo, head, base, title, body string) (string, error) {
+		prCalls = append(prCalls, strings.Join([]string{repo, head, base, title}, "|"))
+		return "https://github.com/org/repo/pull/1", nil
+	}
+	return &prCalls
+}
+
+func TestRunPipeline_PushAndPR(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("git -C /workspace rev-parse HEAD", "abc123\n", "", nil)
+	executor.SetResponse("git -C /workspace diff --cached --binary abc123", "diff --git a/x b/x\n", "", nil)
+	executor.SetResponse("git -C /workspace status --porcelain", "M  x\n", "", nil)
+	prCalls := stubRunPipeline(t, executor, agent.NewMockCodingAgentExecutor())
+
+	var steps []string
+	patchPath := filepath.Join(t.TempDir(), "out.patch")
+	result, err := RunPipeline(context.Background(), RunOptions{
+		Start:  StartSessionOptions{Name: "ci", Repo: "https://github.com/org/repo", Prompt: "Fix the flaky test\nDetails..."},
+		Output: patchPath,
+		PR:     true,
+		Step:   func(title string) { steps = append(steps, title) },
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	if !result.Changed || !result.Pushed || result.PRURL == "" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	data, err := os.ReadFile(patchPath)
+	if err != nil || string(data) != "diff --git a/x b/x\n" {
+		t.Errorf("patch = %q, %v", data, err)
+	}
+	if len(steps) != 5 {
+		t.Errorf("steps = %v, want 5 stages", steps)
+	}
+
+	var pushed bool
+	for _, cmd := range executor.Commands {
+		if strings.Join(cmd.Command, " ") == "git -C /workspace push -q origin HEAD:refs/heads/kodama/ci" {
+			pushed = true
+		}
+	}
+	if !pushed {
+		t.Errorf("expected push to session branch, commands: %v", executor.Commands)
+	}
+	if len(*prCalls) != 1 || (*prCalls)[0] != "https://github.com/org/repo|kodama/ci||Fix the flaky test" {
+		t.Errorf("pr calls = %v", *prCalls)
+	}
+}
+
+func TestRunPipeline_NoChanges(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+	prCalls := stubRunPipeline(t, executor, agent.NewMockCodingAgentExecutor())
+
+	result, err := RunPipeline(context.Background(), RunOptions{
+		Start:  StartSessionOptions{Name: "ci", Repo: "https://github.com/org/repo", Prompt: "noop"},
+		Output: filepath.Join(t.TempDir(), "out.patch"),
+		PR:     true,
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+	if result.Changed || result.Pushed || len(*prCalls) != 0 {
+		t.Errorf("expected nothing to push, got %+v", result)
+	}
+}
+
+func TestRunPipeline_AgentFailure(t *testing.T) {
+	agentExecutor := agent.NewMockCodingAgentExecutor()
+	agentExecutor.TaskStartFunc = func(ctx context.Context, namespace, podName, prompt, logPath string) (string, error) {
+		return "", errors.New("agent crashed")
+	}
+	stubRunPipeline(t, kubernetes.NewMockExecutor(), agentExecutor)
+
+	result, err := RunPipeline(context.Background(), RunOptions{
+		Start:  StartSessionOptions{Name: "ci", Repo: "https://github.com/org/repo", Prompt: "task"},
+		Output: filepath.Join(t.TempDir(), "out.patch"),
+	})
+	if !errors.Is(err, ErrAgentFailed) {
+		t.Fatalf("expected ErrAgentFailed, got %v", err)
+	}
+	if result == nil || result.Session == nil {
+		t.Error("result should carry the session so the caller can tear it down")
+	}
+}
+
+func TestGhRepo(t *testing.T) {
+	tests := map[string]string{
+		"https://github.com/org/repo.git":   "org/repo",
+		"git@github.com:org/repo.git":       "org/repo",
+		"https://ghe.example.com/org/repo":  "ghe.example.com/org/repo",
+		"ssh://git@github.com/org/repo.git": "org/repo",
+	}
+	for in, want := range tests {
+		if got := ghRepo(in); got != want {
+			t.Errorf("ghRepo(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRunPipeline_SaveAgentLog(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("git -C /workspace rev-parse HEAD", "abc123\n", "", nil)
+	executor.SetResponse("cat "+agent.RunsDir, "agent output\n", "", nil)
+	stubRunPipeline(t, executor, agent.NewMockCodingAgentExecutor())
+
+	result, err := RunPipeline(context.Background(), RunOptions{
+		Start:  StartSessionOptions{Name: "ci", Repo: "https://github.com/org/repo", Prompt: "Fix it", SaveAgentLog: true},
+		Output: filepath.Join(t.TempDir(), "out.patch"),
+	})
+	if err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	execution := result.Session.GetLastAgentExecution()
+	store, _ := OpenStore()
+	if want := filepath.Join(store.GetArtifactsDir("ci"), filepath.Base(execution.LogPath)); execution.ArtifactPath != want {
+		t.Fatalf("ArtifactPath = %q, want %q", execution.ArtifactPath, want)
+	}
+	content, err := os.ReadFile(execution.ArtifactPath)
+	if err != nil || string(content) != "agent output\n" {
+		t.Errorf("artifact content = %q, %v", content, err)
+	}
+}
+
+func TestRunPipeline_AttachFiles(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+	agentExecutor := agent.NewMockCodingAgentExecutor()
+	var prompt string
+	agentExecutor.TaskStartFunc = func(ctx context.Context, namespace, podName, p, logPath string) (string, error) {
+		prompt = p
+		return "task-1", nil
+	}
+	stubRunPipeline(t, executor, agentExecutor)
+
+	errorLog := filepath.Join(t.TempDir(), "error.log")
+	if err := os.WriteFile(errorLog, []byte("panic: nil map\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := RunPipeline(context.Background(), RunOptions{
+		Start:  StartSessionOptions{Name: "ci", Repo: "https://github.com/org/repo", Prompt: "Fix it", AttachFiles: []string{errorLog + ".missing"}},
+		Output: filepath.Join(t.TempDir(), "out.patch"),
+	})
+	if err == nil || !strings.Contains(err.Error(), "cannot attach") {
+		t.Fatalf("RunPipeline() error = %v, want a missing attachment rejected before start", err)
+	}
+
+	if _, err := RunPipeline(context.Background(), RunOptions{
+		Start:  StartSessionOptions{Name: "ci", Repo: "https://github.com/org/repo", Prompt: "Fix it", AttachFiles: []string{errorLog}},
+		Output: filepath.Join(t.TempDir(), "out.patch"),
+	}); err != nil {
+		t.Fatalf("RunPipeline() error = %v", err)
+	}
+
+	var uploaded bool
+	for _, cmd := range executor.Commands {
+		if cmd.Input == "panic: nil map\n" && cmd.Command[len(cmd.Command)-1] == agent.ContextDir+"/error.log" {
+			uploaded = true
+		}
+	}
+	if !uploaded {
+		t.Errorf("attached file not uploaded, commands: %v", executor.Commands)
+	}
+	if !strings.Contains(prompt, "Fix it\n\nAttached context files") || !strings.Contains(prompt, "- "+agent.ContextDir+"/error.log") {
+		t.Errorf("prompt = %q, want a reference to the attached file", prompt)
+	}
+}
+
+func TestRunPipeline_ProtectedPathsBlockPush(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("git -C /workspace rev-parse HEAD", "abc123\n", "", nil)
+	executor.SetResponse("git -C /workspace diff --cached --binary abc123", "diff --git a/infra/main.tf b/infra/main.tf\n", "", nil)
+	executor.SetResponse("sh -c cd /workspace && git diff --name-only", "infra/main.tf\n", "", nil)
+	prCalls := stubRunPipeline(t, executor, agent.NewMockCodingAgentExecutor())
+
+	stubStart := startSessionFunc
+	startSessionFunc = func(ctx context.Context, opts StartSessionOptions) (*config.SessionConfig, error) {
+		session, err := stubStart(ctx, opts)
+		session.Agent.ProtectedPaths = []string{"infra/**"}
+		return session, err
+	}
+
+	result, err := RunPipeline(context.Background(), RunOptions{
+		Start:  StartSessionOptions{Name: "ci", Repo: "https://github.com/org/repo", Prompt: "Fix it"},
+		Output: filepath.Join(t.TempDir(), "out.patch"),
+		PR:     true,
+	})
+	if !errors.Is(err, ErrProtectedPathsChanged) {
+		t.Fatalf("expected ErrProtectedPathsChanged, got %v", err)
+	}
+	if result.Pushed || len(*prCalls) != 0 {
+		t.Errorf("changes to protected paths must not be pushed")
+	}
+	if !strings.Contains(err.Error(), "infra/main.tf") {
+		t.Errorf("error should name the protected file: %v", err)
+	}
+}
+
+func TestCommitAndPush_IdentityAndSigning(t *testing.T) {
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("git -C /workspace status --porcelain", "M  x\n", "", nil)
+	session := &config.SessionConfig{
+		Name: "ci", Namespace: "default", PodName: "kodama-ci", Branch: "kodama/ci",
+		GitIdentity: config.GitIdentityConfig{Name: "Jane Doe", Email: "jane@example.com"},
+		GitSigning:  config.GitSigningConfig{Format: config.GitSigningGPG, Secret: "gpg-key", Key: "ABCD1234"},
+	}
+
+	if err := commitAndPush(context.Background(), executor, session, "Apply changes"); err != nil {
+		t.Fatalf("commitAndPush() error = %v", err)
+	}
+
+	var signingAt, commitAt int
+	for i, cmd := range executor.Commands {
+		joined := strings.Join(cmd.Command, " ")
+		switch {
+		case strings.Contains(joined, "gpg --batch --quiet --import"):
+			signingAt = i
+		case strings.Contains(joined, " commit "):
+			commitAt = i
+			if !strings.Contains(joined, "GIT_AUTHOR_NAME=Jane Doe") || !strings.Contains(joined, "GIT_COMMITTER_EMAIL=jane@example.com") {
+				t.Errorf("commit not attributed to the session identity: %s", joined)
+			}
+		}
+	}
+	if signingAt == 0 || commitAt <= signingAt {
+		t.Errorf("expected signing to be configured before the commit, commands: %v", executor.Commands)
+	}
+}
@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/procutil"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// ApplySandboxChangesOptions contains options for applying the agent's
+// sandbox changes to the canonical workspace
+type ApplySandboxChangesOptions struct {
+	Name  string
+	Paths []string // Files or directories to apply, relative to /workspace
+	All   bool     // Apply every change
+}
+
+// workspaceSyncOptions returns the sync manager options of a session (see
+// syncOptions) for writing its sync root (see SyncConfig.RemoteRoot) of the
+// canonical workspace: through the workspace-sync sidecar in sandbox
+// sessions, where the main container only has a read-only view of it
+func workspaceSyncOptions(globalCfg *config.GlobalConfig, session *config.SessionConfig) sync.Options {
+	opts := syncOptions(globalCfg, session)
+	opts.RemoteRoot = session.Sync.RemoteRoot()
+	if session.Sandbox {
+		opts.Container = kubernetes.WorkspaceSyncContainerName
+	}
+	return opts
+}
+
+// workspaceExecutor returns the executor whose /workspace is the canonical workspace
+func workspaceExecutor(session *config.SessionConfig) kubernetes.CommandExecutor {
+	if !session.Sandbox {
+		return newRunExecutor()
+	}
+	return kubernetes.NewKubectlContainerExecutor(kubernetes.WorkspaceSyncContainerName)
+}
+
+// seedSandbox copies the canonical workspace into the sandbox once it has
+// been synced; cloned repositories are copied by the sandbox-initializer
+func seedSandbox(ctx context.Context, session *config.SessionConfig) {
+	if !session.Sandbox {
+		return
+	}
+	command := []string{"sh", "-c", kubernetes.SandboxSeedScript}
+	if _, stderr, err := newRunExecutor().ExecInPod(ctx, session.Namespace, session.PodName, command); err != nil {
+		fmt.Fprintf(sync.OutputFor(ctx), "⚠️  Warning: Failed to copy the workspace into the sandbox: %v: %s\n", err, strings.TrimSpace(stderr))
+		return
+	}
+	fmt.Fprintln(sync.OutputFor(ctx), "✓ Sandbox ready (the workspace is read-only at "+kubernetes.CanonicalWorkspacePath+")")
+}
+
+// SandboxChanges lists the files the agent changed in the sandbox of a session
+func SandboxChanges(ctx context.Context, name string) ([]kubernetes.SandboxChange, error) {
+	_, session, err := loadSandboxSession(name)
+	if err != nil {
+		return nil, err
+	}
+	return sandboxChanges(ctx, session)
+}
+
+// ApplySandboxChanges copies the selected sandbox changes into the canonical
+// workspace, and for synced sessions into the local directory as well, so the
+// next sync keeps them. It returns the changes that were applied.
+func ApplySandboxChanges(ctx context.Context, opts ApplySandboxChangesOptions) ([]kubernetes.SandboxChange, error) {
+	store, session, err := loadSandboxSession(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := sandboxChanges(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := changes
+	if !opts.All {
+		if selected, err = selectSandboxChanges(changes, opts.Paths); err != nil {
+			return nil, err
+		}
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	var copied, deleted []string
+	for _, change := range selected {
+		if change.Kind == kubernetes.SandboxDeleted {
+			deleted = append(deleted, change.Path)
+		} else {
+			copied = append(copied, change.Path)
+		}
+	}
+
+	if err := copySandboxFiles(ctx, session, copied); err != nil {
+		return nil, err
+	}
+	if err := deletePodFiles(ctx, session, deleted); err != nil {
+		return nil, err
+	}
+
+	if session.Sync.Enabled && session.Sync.LocalPath != "" {
+		if err := pullSyncFiles(ctx, sync.Options{}, session.Sync.LocalPath, "/workspace", session.Namespace, session.PodName, copied); err != nil {
+			return nil, fmt.Errorf("failed to copy changes to %s: %w", session.Sync.LocalPath, err)
+		}
+		for _, file := range deleted {
+			if err := os.Remove(filepath.Join(session.Sync.LocalPath, filepath.FromSlash(file))); err != nil && !os.IsNotExist(err) {
+				return nil, fmt.Errorf("failed to delete %s: %w", file, err)
+			}
+		}
+
+		// Both ends now hold the applied files; record them so the next sync skips them
+		globalConfig, err := store.LoadGlobalConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load global config: %w", err)
+		}
+		excludeCfg := buildExcludeConfig(session.Sync.LocalPath, globalConfig, session)
+		recordSyncManifest(ctx, workspaceSyncOptions(globalConfig, session), store, session, excludeCfg)
+	}
+	return selected, nil
+}
+
+// loadSandboxSession loads a session that was started with --sandbox
+func loadSandboxSession(name string) (*config.Store, *config.SessionConfig, error) {
+	store, err := OpenStore()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize config store: %w", err)
+	}
+	session, err := store.LoadSession(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	if !session.Sandbox {
+		return nil, nil, fmt.Errorf("session '%s' was not started with --sandbox", name)
+	}
+	return store, session, nil
+}
+
+// sandboxChanges compares the sandbox with the canonical workspace in the pod
+func sandboxChanges(ctx context.Context, session *config.SessionConfig) ([]kubernetes.SandboxChange, error) {
+	command := []string{"sh", "-c", kubernetes.SandboxChangesScript}
+	stdout, stderr, err := newRunExecutor().ExecInPod(ctx, session.Namespace, session.PodName, command)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare the sandbox with the workspace: %w: %s", err, strings.TrimSpace(stderr))
+	}
+	return kubernetes.ParseSandboxChanges(stdout)
+}
+
+// selectSandboxChanges returns the changes to the given files or inside the given directories
+// Every path must match a change, so typos are not silently ignored.
+func selectSandboxChanges(changes []kubernetes.SandboxChange, paths []string) ([]kubernetes.SandboxChange, error) {
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no paths given: pass the files to apply, or --all")
+	}
+
+	var selected []kubernetes.SandboxChange
+	matched := make(map[string]bool, len(paths))
+	for _, change := range changes {
+		for _, p := range paths {
+			p = strings.Trim(filepath.ToSlash(filepath.Clean(p)), "/")
+			if p == "." || change.Path == p || strings.HasPrefix(change.Path, p+"/") {
+				selected = append(selected, change)
+				matched[p] = true
+				break
+			}
+		}
+	}
+	for _, p := range paths {
+		p = strings.Trim(filepath.ToSlash(filepath.Clean(p)), "/")
+		if !matched[p] {
+			return nil, fmt.Errorf("no sandbox changes under %s", p)
+		}
+	}
+	return selected, nil
+}
+
+// copySandboxFiles streams files from the sandbox into the canonical workspace
+// with tar, from the main container to the workspace-sync sidecar
+func copySandboxFiles(ctx context.Context, session *config.SessionConfig, files []string) error {
+	if len(files) == 0 {
+		return nil
+	}
+
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	tarCmd := procutil.CommandContext(ctx, "kubectl", append([]string{"exec",
+		"-n", session.Namespace, session.PodName, "-c", kubernetes.MainContainerName,
+		"--", "tar", "cf", "-", "-C", "/workspace", "--",
+	}, files...)...)
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	untarCmd := procutil.CommandContext(ctx, "kubectl", "exec", "-i",
+		"-n", session.Namespace, session.PodName, "-c", kubernetes.WorkspaceSyncContainerName,
+		"--", "tar", "xf", "-", "-C", "/workspace")
+
+	pipe, err := tarCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("failed to create pipe: %w", err)
+	}
+	untarCmd.Stdin = pipe
+	var tarStderr, untarStderr strings.Builder
+	tarCmd.Stderr = &tarStderr
+	untarCmd.Stderr = &untarStderr
+
+	if err := tarCmd.Start(); err != nil {
+		return fmt.Errorf("failed to start kubectl exec: %w", err)
+	}
+	if err := untarCmd.Start(); err != nil {
+		procutil.Kill(tarCmd)
+		return fmt.Errorf("failed to start kubectl exec: %w", err)
+	}
+
+	tarErr := tarCmd.Wait()
+	untarErr := untarCmd.Wait()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if tarErr != nil {
+		return fmt.Errorf("failed to archive sandbox files: %w: %s", tarErr, strings.TrimSpace(tarStderr.String()))
+	}
+	if untarErr != nil {
+		return fmt.Errorf("failed to extract into the workspace: %w: %s", untarErr, strings.TrimSpace(untarStderr.String()))
+	}
+	return nil
+}
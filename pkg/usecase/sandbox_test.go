@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+func TestSelectSandboxChanges(t *testing.T) {
+	changes := []kubernetes.SandboxChange{
+		{Kind: kubernetes.SandboxModified, Path: "README.md"},
+		{Kind: kubernetes.SandboxAdded, Path: "src/new.go"},
+		{Kind: kubernetes.SandboxDeleted, Path: "src/old.go"},
+		{Kind: kubernetes.SandboxModified, Path: "srcfoo.go"},
+	}
+
+	selected, err := selectSandboxChanges(changes, []string{"src/", "./README.md"})
+	require.NoError(t, err)
+	assert.Equal(t, []kubernetes.SandboxChange{changes[0], changes[1], changes[2]}, selected)
+
+	selected, err = selectSandboxChanges(changes, []string{"."})
+	require.NoError(t, err)
+	assert.Equal(t, changes, selected)
+
+	_, err = selectSandboxChanges(changes, []string{"docs"})
+	assert.ErrorContains(t, err, "no sandbox changes under docs")
+
+	_, err = selectSandboxChanges(changes, nil)
+	assert.Error(t, err)
+}
+
+func TestWorkspaceExecutor_Sandbox(t *testing.T) {
+	executor := workspaceExecutor(&config.SessionConfig{Sandbox: true})
+	assert.Equal(t, &kubernetes.KubectlExecutor{Container: kubernetes.WorkspaceSyncContainerName}, executor)
+}
+
+func TestWorkspaceSyncOptions(t *testing.T) {
+	globalCfg := &config.GlobalConfig{}
+	globalCfg.Sync.Compression = "gzip"
+
+	session := &config.SessionConfig{}
+	session.Sync.RemotePath = "services/payments"
+	assert.Equal(t, sync.Options{RemoteRoot: "/workspace/services/payments", Compression: sync.CompressionGzip},
+		workspaceSyncOptions(globalCfg, session))
+
+	session.Sandbox = true
+	assert.Equal(t, kubernetes.WorkspaceSyncContainerName, workspaceSyncOptions(globalCfg, session).Container)
+	assert.Empty(t, syncOptions(globalCfg, session).Container, "custom directories stay in the main container")
+}
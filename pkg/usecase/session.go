@@ -4,19 +4,25 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"time"
 
+	"golang.org/x/term"
 	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
 
 	"github.com/illumination-k/kodama/pkg/agent"
 	"github.com/illumination-k/kodama/pkg/config"
 	"github.com/illumination-k/kodama/pkg/env"
 	"github.com/illumination-k/kodama/pkg/gitcmd"
 	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/metrics"
 	"github.com/illumination-k/kodama/pkg/secretfile"
 	"github.com/illumination-k/kodama/pkg/sync"
 	"github.com/illumination-k/kodama/pkg/sync/exclude"
@@ -30,41 +36,87 @@ type SecretFileMapping struct {
 
 // ManifestCollection holds Kubernetes manifests generated during dry-run
 type ManifestCollection struct {
-	EnvSecret  *corev1.Secret // Optional environment variable secret
-	FileSecret *corev1.Secret // Optional file secret
-	Pod        *corev1.Pod    // Required pod manifest
+	EnvSecret        *corev1.Secret                // Optional environment variable secret
+	FileSecret       *corev1.Secret                // Optional file secret
+	EditorConfigMap  *corev1.ConfigMap             // Optional editor config files
+	Pod              *corev1.Pod                   // Required pod manifest
+	DisruptionBudget *policyv1.PodDisruptionBudget // Optional eviction protection
 }
 
+// ErrAgentFailed is returned by StartSession when the coding agent fails and
+// StartSessionOptions.FailOnAgentError is set. The session is left running.
+var ErrAgentFailed = errors.New("coding agent failed")
+
+// branchNamer names the branch of repo sessions started without --branch
+// Tests replace it with a fixed clock and entropy.
+var branchNamer = gitcmd.NewBranchNamer()
+
+// cleanupTimeout bounds resource cleanup after a failed or interrupted start
+const cleanupTimeout = time.Minute
+
+// dataReadyTimeout bounds the pod start when a dataset is fetched, which can
+// take much longer than installing tools and cloning
+const dataReadyTimeout = time.Hour
+
+// toolchainReadyTimeout bounds the pod start when toolchains are installed,
+// which may compile them from source
+const toolchainReadyTimeout = 30 * time.Minute
+
 // StartSessionOptions contains all options for starting a session
 type StartSessionOptions struct {
-	Name            string
-	Repo            string
-	SyncPath        string
-	Namespace       string
-	CPU             string
-	Memory          string
-	CustomResources map[string]string // e.g., "nvidia.com/gpu": "1"
-	Branch          string
-	KubeconfigPath  string
-	Prompt          string
-	PromptFile      string
-	Image           string
-	Command         string
-	CloneDepth      int
-	SingleBranch    bool
-	GitCloneArgs    string
-	ConfigFile      string
-	TtydEnabled     bool
-	TtydEnabledVal  bool
-	TtydPort        int
-	TtydOptions     string
-	TtydReadonly    bool
-	TtydReadonlySet bool
-	EnvFiles        []string
-	EnvExclude      []string
-	SecretFiles     []SecretFileMapping
-	DryRun          bool                // If true, generate manifests without creating resources
-	Manifests       *ManifestCollection // Populated when DryRun is true
+	Name             string
+	Repo             string
+	SyncPath         string
+	SyncInclude      []string               // Only sync these paths relative to the sync root (overrides template)
+	SyncRemotePath   string                 // Directory inside /workspace SyncPath is synced to; allows --repo with --sync
+	NoSync           bool                   // Start with an empty workspace instead of syncing the current directory
+	LocalChanges     bool                   // Apply the current directory's uncommitted changes to the Repo clone
+	Shared           bool                   // Mount the namespace-wide shared scratch volume at /shared
+	Record           bool                   // Record interactive terminals (ttyd and attach) in the pod
+	SpotFriendly     bool                   // Run on spot nodes with workspace checkpoints
+	SealedSecrets    bool                   // Apply env and file secrets server-side, owned by the pod
+	Sandbox          bool                   // Give the agent a writable copy of the workspace, see ApplySandboxChanges
+	NoEvict          bool                   // Annotate the pod as not safe to evict for the cluster autoscaler
+	DisruptionBudget bool                   // Create a PodDisruptionBudget for the pod
+	Runtime          string                 // docker or podman to run a local container instead of a pod
+	RestartPolicy    string                 // Pod restart policy: Never, OnFailure or Always (overrides template)
+	Watchdog         bool                   // Restart ttyd, the session command and sshd in the pod when they die
+	Labels           map[string]string      // Merged over template labels
+	PodOverrides     map[string]interface{} // Replaces template podOverrides
+	TemplateValues   map[string]interface{} // Overlaid on global values when rendering the template
+	Overlay          string                 // Template overlay merged after the template, e.g. staging
+	Namespace        string
+	CPU              string
+	Memory           string
+	CustomResources  map[string]string // e.g., "nvidia.com/gpu": "1"
+	Branch           string
+	KubeconfigPath   string
+	Prompt           string
+	PromptFile       string
+	PlanFile         string   // Task plan run step by step instead of Prompt, see config.LoadTaskPlan
+	AttachFiles      []string // Local files uploaded for the prompt after agent.contextFiles, see agent.ContextDir
+	FailOnAgentError bool     // Return ErrAgentFailed instead of warning when the agent fails
+	SaveAgentLog     bool     // Copy the agent output log into the session's local artifacts
+	Image            string
+	Command          string
+	CloneDepth       int
+	SingleBranch     bool
+	GitCloneArgs     string
+	CloneAttempts    int
+	GitMirror        string
+	ConfigFile       string
+	TtydEnabled      bool
+	TtydEnabledVal   bool
+	TtydPort         int
+	TtydOptions      string
+	TtydReadonly     bool
+	TtydReadonlySet  bool
+	EnvFiles         []string
+	EnvExclude       []string
+	SecretFiles      []SecretFileMapping
+	Expires          time.Duration       // Session lifetime (0 = never expires)
+	DryRun           bool                // If true, generate manifests without creating resources
+	Manifests        *ManifestCollection // Populated when DryRun is true
 }
 
 // AttachSessionOptions contains all options for attaching to a session
@@ -75,12 +127,24 @@ type AttachSessionOptions struct {
 	TtyMode        bool
 	LocalPort      int
 	NoBrowser      bool
+	Container      string // Container to exec into (default: main container)
+	Steal          bool   // Take over the session lock from another user
 }
 
 // StartSession starts a new Claude Code session and returns the session config
-func StartSession(ctx context.Context, opts StartSessionOptions) (*config.SessionConfig, error) {
+func StartSession(ctx context.Context, opts StartSessionOptions) (_ *config.SessionConfig, err error) {
+	out := sync.OutputFor(ctx)
+	if !opts.DryRun {
+		defer func(start time.Time) { metrics.ObserveSessionStart(start, err) }(time.Now())
+	}
+
+	// 0. Validate the session name before any resource is named after it
+	if err := config.ValidateSessionName(opts.Name); err != nil {
+		return nil, err
+	}
+
 	// 1. Load global config for defaults
-	store, err := config.NewStore()
+	store, err := OpenStore()
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize config store: %w", err)
 	}
@@ -89,9 +153,17 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 	if err != nil {
 		return nil, fmt.Errorf("failed to load global config: %w", err)
 	}
+	if kubeContext := kubernetes.CurrentContext(opts.KubeconfigPath); kubeContext != "" {
+		if _, ok := globalConfig.Contexts[kubeContext]; ok {
+			globalConfig = globalConfig.ForContext(kubeContext)
+			if !opts.DryRun {
+				fmt.Fprintf(out, "Using defaults for context '%s'\n", kubeContext)
+			}
+		}
+	}
 
 	// 1.5 Load session template config if specified or found in current directory
-	var templateConfig *config.SessionConfig
+	var templateConfigs []*config.SessionConfig
 	configFile := opts.ConfigFile
 
 	// Auto-detect .kodama.yaml in current directory if --config not specified
@@ -102,7 +174,7 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 			if _, statErr := os.Stat(candidatePath); statErr == nil {
 				configFile = candidatePath
 				if !opts.DryRun {
-					fmt.Printf("📄 Found .kodama.yaml in current directory\n")
+					fmt.Fprintf(out, "📄 Found .kodama.yaml in current directory\n")
 				}
 			}
 		}
@@ -110,32 +182,39 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 
 	if configFile != "" {
 		if !opts.DryRun {
-			fmt.Printf("Loading session template from: %s\n", configFile)
+			fmt.Fprintf(out, "Loading session template from: %s\n", configFile)
 		}
-		var loadedTemplate *config.SessionConfig
-		loadedTemplate, err = store.LoadSessionTemplate(configFile)
+		templateConfigs, err = store.LoadSessionTemplates(configFile, opts.TemplateValues)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load session template: %w", err)
 		}
-		templateConfig = loadedTemplate
 		if !opts.DryRun {
-			fmt.Println("✓ Template loaded")
+			if len(templateConfigs) > 1 {
+				fmt.Fprintf(out, "✓ Template loaded (extends %d base templates)\n", len(templateConfigs)-1)
+			} else {
+				fmt.Fprintln(out, "✓ Template loaded")
+			}
 		}
-	}
-
-	// 2. Check if session already exists (skip if dry-run)
-	if !opts.DryRun {
-		var existingSessions []*config.SessionConfig
-		existingSessions, err = store.ListSessions()
+		templateConfigs, err = config.ApplyOverlay(templateConfigs, opts.Overlay)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list existing sessions: %w", err)
+			return nil, err
 		}
+		if opts.Overlay != "" && !opts.DryRun {
+			fmt.Fprintf(out, "✓ Overlay '%s' applied\n", opts.Overlay)
+		}
+	} else if opts.Overlay != "" {
+		return nil, fmt.Errorf("--overlay %s requires a session template (--config or .kodama.yaml)", opts.Overlay)
+	}
 
-		for _, s := range existingSessions {
-			if s.Name == opts.Name {
-				return nil, fmt.Errorf("session '%s' already exists. Use 'kubectl kodama delete %s' to remove it first", opts.Name, opts.Name)
-			}
+	// 2. Check if session already exists (skip if dry-run)
+	// A session left behind by a failed or interrupted start is resumed instead
+	var previous *config.SessionConfig
+	if !opts.DryRun && store.SessionExists(opts.Name) {
+		previous, err = store.LoadSession(opts.Name)
+		if err != nil || !previous.IsResumable() {
+			return nil, fmt.Errorf("session '%s' already exists. Use 'kubectl kodama delete %s' to remove it first", opts.Name, opts.Name)
 		}
+		fmt.Fprintf(out, "♻️  Resuming session '%s' from a previous incomplete start (status: %s)\n", opts.Name, previous.Status)
 	}
 
 	// 3. Resolve config with 3-tier priority merge
@@ -144,7 +223,7 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 	// Priority: CLI flags > Template config > Global config > Hardcoded defaults
 
 	// Use ConfigResolver to merge global and template configs
-	resolver := config.NewConfigResolver(globalConfig, templateConfig)
+	resolver := config.NewConfigResolver(globalConfig, templateConfigs...)
 	resolved := resolver.Resolve()
 
 	// Apply CLI flag overrides (highest priority) using coalesce helpers
@@ -157,6 +236,8 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 	cloneDepth := config.CoalesceInt(opts.CloneDepth, resolved.CloneDepth)
 	singleBranch := config.CoalesceBool(opts.SingleBranch, resolved.SingleBranch, opts.SingleBranch)
 	gitCloneArgs := config.CoalesceString(opts.GitCloneArgs, resolved.GitCloneArgs)
+	cloneAttempts := config.CoalesceInt(opts.CloneAttempts, resolved.CloneAttempts)
+	gitMirror := config.CoalesceString(opts.GitMirror, resolved.CloneMirror)
 	repo := config.CoalesceString(opts.Repo, resolved.Repo)
 	command := config.CoalesceString(opts.Command, resolved.Command)
 
@@ -170,22 +251,71 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 	envDotenvFiles := config.CoalesceStringSlice(opts.EnvFiles, resolved.EnvDotenvFiles)
 	envExcludeVars := config.CoalesceStringSlice(opts.EnvExclude, resolved.EnvExcludeVars)
 
+	// Local containers need neither a cluster nor a namespace
+	if err := config.ValidateRuntime(opts.Runtime); err != nil {
+		return nil, err
+	}
+	runtime := opts.Runtime
+	if runtime == config.RuntimeKubernetes {
+		runtime = ""
+	}
+	local := runtime == config.RuntimeDocker || runtime == config.RuntimePodman
+	if local {
+		namespace = ""
+		if opts.DryRun {
+			return nil, fmt.Errorf("--dry-run generates Kubernetes manifests and cannot be used with --runtime %s", runtime)
+		}
+	}
+
 	// Validate required fields after merge
-	if namespace == "" {
+	if namespace == "" && !local {
 		return nil, fmt.Errorf("namespace is required. Specify via --namespace flag, template config, or set default in ~/.kodama/config.yaml")
 	}
 
-	// 4. Validate mutual exclusivity between --repo and --sync
-	if opts.SyncPath != "" && repo != "" {
-		return nil, fmt.Errorf("cannot use both --sync and --repo. Choose one mode per session")
+	// 4. Validate mutual exclusivity between --repo and --sync: a cloned
+	// repository can only take a sync into a subdirectory, which it overlays
+	syncRemote := config.SyncConfig{RemotePath: config.CoalesceString(opts.SyncRemotePath, resolved.SyncRemotePath)}
+	if err := config.ValidateSyncRemotePath(syncRemote.RemotePath); err != nil {
+		return nil, err
+	}
+	subdirSync := syncRemote.RemoteRoot() != config.WorkspaceRoot
+	syncPath := opts.SyncPath
+	if syncPath == "" && (repo == "" || subdirSync) {
+		syncPath = resolved.SyncLocalPath
+	}
+	if syncPath != "" && repo != "" && !subdirSync {
+		return nil, fmt.Errorf("cannot use both --sync and --repo. Choose one mode per session, or sync into a subdirectory of the clone with --remote-path")
+	}
+	if subdirSync && local {
+		return nil, fmt.Errorf("--remote-path is not supported with the %s runtime, which mounts the synced directory as /workspace", runtime)
+	}
+	if subdirSync && (opts.Sandbox || resolved.Sandbox) {
+		return nil, fmt.Errorf("--remote-path cannot be combined with --sandbox")
+	}
+
+	// 4b. Read the uncommitted changes to apply to the clone once it exists
+	var localChanges *gitcmd.LocalChanges
+	if opts.LocalChanges {
+		if repo == "" {
+			return nil, fmt.Errorf("--local-changes requires --repo: it applies uncommitted changes to the cloned repository")
+		}
+		localChanges, err = readLocalChanges(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	// 5. Determine sync path (only when repo is not specified)
+	// 5. Determine sync path (only when repo is not specified, or synced into a subdirectory of it)
 	var syncEnabled bool
 	var resolvedSyncPath string
-	if repo == "" {
-		if opts.SyncPath != "" {
-			resolvedSyncPath = opts.SyncPath
+	if (repo == "" || syncPath != "") && !opts.NoSync {
+		if syncPath != "" {
+			// Stored absolute so commands run inside the directory can find the session
+			absPath, absErr := filepath.Abs(syncPath)
+			if absErr != nil {
+				return nil, fmt.Errorf("failed to resolve sync path: %w", absErr)
+			}
+			resolvedSyncPath = absPath
 			syncEnabled = true
 		} else {
 			// Default to current directory when neither --repo nor --sync specified
@@ -219,6 +349,7 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 	session := &config.SessionConfig{
 		Name:      opts.Name,
 		Namespace: namespace,
+		Runtime:   runtime,
 		Repo:      repo,
 		PodName:   fmt.Sprintf("kodama-%s", opts.Name),
 		Image:     image,
@@ -227,6 +358,8 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 			Depth:        cloneDepth,
 			SingleBranch: singleBranch,
 			ExtraArgs:    gitCloneArgs,
+			Attempts:     cloneAttempts,
+			Mirror:       gitMirror,
 		},
 		Status:     config.StatusPending,
 		CreatedAt:  now,
@@ -244,12 +377,25 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 			Writable: &ttydWritable,
 		},
 		Sync: config.SyncConfig{
-			Enabled:   syncEnabled,
-			LocalPath: resolvedSyncPath,
+			Enabled:    syncEnabled,
+			LocalPath:  resolvedSyncPath,
+			RemotePath: syncRemote.RemotePath,
 		},
 	}
 
+	// Repo sessions work on a branch of their own; a resumed start keeps the one
+	// generated by the previous attempt, which the pod may already have checked out
+	session.Branch = branch
+	if repo != "" && branch == "" {
+		if previous != nil && previous.Branch != "" {
+			session.Branch = previous.Branch
+		} else {
+			session.Branch = branchNamer.BranchName(config.CoalesceString(resolved.BranchPrefix, gitcmd.DefaultBranchPrefix), opts.Name)
+		}
+	}
+
 	// Apply resolved sync config and claude auth (from global + template merge)
+	session.Sync.Include = config.CoalesceStringSlice(opts.SyncInclude, resolved.SyncInclude)
 	if len(resolved.SyncExclude) > 0 {
 		session.Sync.Exclude = resolved.SyncExclude
 	}
@@ -259,6 +405,7 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 	if len(resolved.SyncCustomDirs) > 0 {
 		session.Sync.CustomDirs = resolved.SyncCustomDirs
 	}
+	session.Sync.Compression = resolved.SyncCompression
 
 	// Apply env config (CLI > template > global)
 	session.Env.DotenvFiles = envDotenvFiles
@@ -278,16 +425,153 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 		session.SecretFile.Files = resolved.SecretFileMappings
 	}
 
+	// Apply git identity (template > global > local git config)
+	session.GitIdentity = resolveGitIdentity(ctx, resolved.GitIdentity, resolvedSyncPath)
+	session.GitSigning = resolved.GitSigning
+	session.Git = resolved.Git
+	session.Dotfiles = resolved.Dotfiles
+	session.Test.Command = resolved.TestCommand
+
+	// Apply shared dependency cache
+	session.Cache.PVC = resolved.CachePVC
+
+	// Apply shared scratch volume
+	session.Shared = config.SharedConfig{}
+	if resolved.Shared.Enabled || opts.Shared {
+		session.Shared = resolved.Shared
+		session.Shared.Enabled = true
+		session.Shared.PVC = config.CoalesceString(session.Shared.PVC, kubernetes.DefaultSharedPVC)
+	}
+
+	// Apply proxy and extra CA bundle
+	session.Proxy = resolved.Proxy
+	session.TLS = resolved.TLS
+	session.Installer = resolved.Installer
+
+	// Shell opened by attach and ttyd (empty detects one in the image)
+	session.Shell = resolved.Shell
+	session.Locale = resolved.Locale
+	session.RestartPolicy = config.CoalesceString(opts.RestartPolicy, resolved.RestartPolicy)
+
+	// Toolchains installed with mise
+	session.Tools = resolved.Tools
+
+	// Terminal recording: flag or template enables it
+	session.Record = opts.Record || resolved.Record
+
+	// Process watchdog: flag or template enables it
+	session.Watchdog = opts.Watchdog || resolved.Watchdog
+
+	// Spot-friendly mode: flag or template enables it
+	session.SpotFriendly = opts.SpotFriendly || resolved.SpotFriendly
+
+	// Sealed secrets: flag, template or global config enables it
+	session.SealedSecrets = opts.SealedSecrets || resolved.SealedSecrets
+
+	// Sandbox mode: flag or template enables it
+	session.Sandbox = opts.Sandbox || resolved.Sandbox
+
+	// Eviction protection: flags, template or global config enable it
+	session.Disruption = resolved.Disruption.Merge(config.DisruptionConfig{
+		NoEvict:             opts.NoEvict,
+		PodDisruptionBudget: opts.DisruptionBudget,
+	})
+
+	// Labels: flags are merged over template labels
+	if len(resolved.Labels) > 0 || len(opts.Labels) > 0 {
+		session.Labels = make(map[string]string, len(resolved.Labels)+len(opts.Labels))
+		maps.Copy(session.Labels, resolved.Labels)
+		maps.Copy(session.Labels, opts.Labels)
+	}
+	if err := globalConfig.CheckRequiredLabels(session.Labels); err != nil {
+		return nil, err
+	}
+	if invalid := kubernetes.InvalidLabels(session.Labels); len(invalid) > 0 && !session.IsLocalRuntime() {
+		fmt.Fprintf(out, "⚠️  Warning: Labels %s are not valid Kubernetes labels and are only used locally\n", strings.Join(invalid, ", "))
+	}
+
+	// Apply editor settings (template > global)
+	session.Editor = resolved.Editor
+
+	// Apply agent guardrails (template paths add to global)
+	session.Agent = resolved.Agent
+	if opts.PlanFile != "" {
+		if opts.Prompt != "" || opts.PromptFile != "" {
+			return nil, fmt.Errorf("--plan cannot be combined with --prompt or --prompt-file")
+		}
+		if _, err := config.LoadTaskPlan(opts.PlanFile); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Prompt != "" || opts.PromptFile != "" || opts.PlanFile != "" {
+		if err := checkContextFiles(contextFiles(session, opts.AttachFiles)); err != nil {
+			return nil, err
+		}
+	} else if len(opts.AttachFiles) > 0 {
+		return nil, fmt.Errorf("--attach-file requires --prompt, --prompt-file or --plan")
+	}
+
+	// Pod overrides are validated now rather than after secrets are created
+	podOverrides := resolved.PodOverrides
+	if len(opts.PodOverrides) > 0 {
+		podOverrides = opts.PodOverrides
+	}
+	if err := kubernetes.ApplyPodOverrides(&corev1.PodSpec{}, podOverrides); err != nil {
+		return nil, err
+	}
+	session.PodOverrides = podOverrides
+
+	// Apply template volumes and dataset (validated with the session below)
+	session.Volumes = resolved.Volumes
+	session.Data = resolved.Data
+
+	// Apply expiration
+	if opts.Expires < 0 {
+		return nil, fmt.Errorf("expiration must be positive (got %s)", opts.Expires)
+	}
+	if opts.Expires > 0 {
+		expiresAt := now.Add(opts.Expires)
+		session.ExpiresAt = &expiresAt
+	}
+
 	// Validate session
 	if validateErr := session.Validate(); validateErr != nil {
 		return nil, fmt.Errorf("invalid session configuration: %w", validateErr)
 	}
 
+	if previous != nil {
+		if previous.Runtime != session.Runtime {
+			return nil, fmt.Errorf("session '%s' was started with runtime '%s'. Use 'kubectl kodama delete %s' to remove it first",
+				opts.Name, config.CoalesceString(previous.Runtime, config.RuntimeKubernetes), opts.Name)
+		}
+		if previous.Namespace != session.Namespace {
+			return nil, fmt.Errorf("session '%s' was started in namespace '%s' (now '%s'). Use 'kubectl kodama delete %s' to remove it first",
+				opts.Name, previous.Namespace, session.Namespace, opts.Name)
+		}
+		session.CreatedAt = previous.CreatedAt
+	}
+
 	// 6. Save initial session config (skip if dry-run)
 	if !opts.DryRun {
 		if saveErr := store.SaveSession(session); saveErr != nil {
 			return nil, fmt.Errorf("failed to save session config: %w", saveErr)
 		}
+
+		message := "Session created"
+		if previous != nil {
+			message = "Session restarted"
+		}
+		recordEvent(ctx, store, session.Name, config.EventCreated, message, map[string]string{"namespace": session.Namespace, "pod": session.PodName})
+		defer func() {
+			// A failed agent task is recorded with its run
+			if err != nil && !errors.Is(err, ErrAgentFailed) {
+				recordEvent(ctx, store, session.Name, config.EventError, "Start failed: "+err.Error(), nil)
+			}
+		}()
+	}
+
+	if session.IsLocalRuntime() {
+		return startContainerSession(ctx, store, session, previous != nil, localChanges, opts)
 	}
 
 	// Track which Kubernetes resources are created for cleanup on failure
@@ -298,12 +582,25 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 		secretName        string
 		fileSecretCreated bool
 		fileSecretName    string
+		editorCMCreated   bool
+		editorCMName      string
+		pdbCreated        bool
 		startSucceeded    bool // Set to true at the very end to skip cleanup
 	)
 
 	// Setup cleanup on error - will only run if startSucceeded is false and not dry-run
 	defer func() {
 		if !opts.DryRun && !startSucceeded && k8sClient != nil {
+			// Cleanup must still run after Ctrl+C has canceled ctx
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cleanupTimeout)
+			defer cancel()
+
+			// Record the failure (including interruption) so the next start resumes
+			if session.Status != config.StatusFailed {
+				session.UpdateStatus(config.StatusFailed)
+				_ = store.SaveSession(session) // Best effort update
+			}
+
 			// Clean up file secret if created
 			if fileSecretCreated && fileSecretName != "" {
 				_ = k8sClient.DeleteSecret(ctx, fileSecretName, namespace)
@@ -312,12 +609,20 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 			if secretCreated && secretName != "" {
 				_ = k8sClient.DeleteSecret(ctx, secretName, namespace)
 			}
+			// Clean up editor config map if created
+			if editorCMCreated && editorCMName != "" {
+				_ = k8sClient.DeleteConfigMap(ctx, editorCMName, namespace)
+			}
+			// Clean up the pod disruption budget if created
+			if pdbCreated {
+				_ = k8sClient.DeletePodDisruptionBudget(ctx, namespace, session.PodName)
+			}
 			cleanupFailedStart(ctx, k8sClient, namespace, session.PodName, podCreated)
 		}
 	}()
 
 	// 7. Create K8s client
-	k8sClient, err = kubernetes.NewClient(opts.KubeconfigPath)
+	k8sClient, err = KubernetesClient(opts.KubeconfigPath)
 	if err != nil {
 		session.UpdateStatus(config.StatusFailed)
 		_ = store.SaveSession(session) // Best effort update
@@ -332,7 +637,7 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 		}
 
 		// Progress indicator
-		fmt.Printf("Creating session '%s'...\n", opts.Name)
+		fmt.Fprintf(out, "Creating session '%s'...\n", opts.Name)
 	}
 
 	// Initialize manifests collection if dry-run
@@ -345,39 +650,37 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 	var envSecret *corev1.Secret
 	if len(session.Env.DotenvFiles) > 0 {
 		if !opts.DryRun {
-			fmt.Printf("📝 Loading dotenv files...\n")
-			fmt.Printf("⚠️  Warning: Ensure .env files are not committed to version control\n")
+			fmt.Fprintf(out, "📝 Loading dotenv files...\n")
+			fmt.Fprintf(out, "⚠️  Warning: Ensure .env files are not committed to version control\n")
 		}
 
-		// Load dotenv files
 		var envVars map[string]string
-		envVars, err = env.LoadDotenvFiles(session.Env.DotenvFiles)
+		envVars, err = loadDotenvVars(session)
 		if err != nil {
-			return nil, fmt.Errorf("failed to load dotenv files: %w", err)
-		}
-
-		// Apply exclusions (default + user-specified)
-		excludeList := make([]string, len(env.DefaultExcludedVars))
-		copy(excludeList, env.DefaultExcludedVars)
-		excludeList = append(excludeList, session.Env.ExcludeVars...)
-		envVars = env.ApplyExclusions(envVars, excludeList)
-
-		// Validate variable names
-		for name := range envVars {
-			if err = env.ValidateVarName(name); err != nil {
-				return nil, fmt.Errorf("invalid variable name '%s': %w", name, err)
-			}
-		}
-
-		// Validate secret size
-		if err = env.ValidateSecretSize(envVars); err != nil {
 			return nil, err
 		}
 
 		// Create secret (only if there are variables to inject)
 		if len(envVars) > 0 {
 			secretName = fmt.Sprintf("kodama-env-%s", session.Name)
-			envSecret, err = k8sClient.CreateSecret(ctx, secretName, session.Namespace, envVars, opts.DryRun)
+
+			// Replace a secret left by the previous attempt so it reflects current files
+			if previous != nil {
+				if err = k8sClient.DeleteSecret(ctx, secretName, session.Namespace); err != nil {
+					return nil, fmt.Errorf("failed to replace environment secret: %w", err)
+				}
+			}
+
+			if !opts.DryRun {
+				if err = trackResource(store, session, kubernetes.KindSecret, secretName); err != nil {
+					return nil, err
+				}
+			}
+			if session.SealedSecrets {
+				envSecret, err = k8sClient.ApplySecret(ctx, kubernetes.NewEnvSecret(secretName, session.Namespace, envVars, session.Labels), opts.DryRun)
+			} else {
+				envSecret, err = k8sClient.CreateSecret(ctx, secretName, session.Namespace, envVars, session.Labels, opts.DryRun)
+			}
 			if err != nil {
 				return nil, fmt.Errorf("failed to create environment secret: %w", err)
 			}
@@ -394,10 +697,10 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 					return nil, fmt.Errorf("failed to save session: %w", err)
 				}
 
-				fmt.Printf("✅ Loaded %d environment variables\n", len(envVars))
+				fmt.Fprintf(out, "✅ Loaded %d environment variables\n", len(envVars))
 			}
 		} else if !opts.DryRun {
-			fmt.Printf("⚠️  All variables were excluded - no environment variables will be injected\n")
+			fmt.Fprintf(out, "⚠️  All variables were excluded - no environment variables will be injected\n")
 		}
 	}
 
@@ -405,7 +708,7 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 	var fileSecret *corev1.Secret
 	if len(session.SecretFile.Files) > 0 {
 		if !opts.DryRun {
-			fmt.Printf("🔐 Loading secret files...\n")
+			fmt.Fprintf(out, "🔐 Loading secret files...\n")
 		}
 
 		// Validate mappings
@@ -429,7 +732,22 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 		if len(fileContents) > 0 {
 			fileSecretName = fmt.Sprintf("kodama-secret-files-%s", session.Name)
 
-			fileSecret, err = k8sClient.CreateFileSecret(ctx, fileSecretName, session.Namespace, fileContents, opts.DryRun)
+			if previous != nil {
+				if err = k8sClient.DeleteSecret(ctx, fileSecretName, session.Namespace); err != nil {
+					return nil, fmt.Errorf("failed to replace secret file: %w", err)
+				}
+			}
+
+			if !opts.DryRun {
+				if err = trackResource(store, session, kubernetes.KindSecret, fileSecretName); err != nil {
+					return nil, err
+				}
+			}
+			if session.SealedSecrets {
+				fileSecret, err = k8sClient.ApplySecret(ctx, kubernetes.NewFileSecret(fileSecretName, session.Namespace, fileContents, session.Labels), opts.DryRun)
+			} else {
+				fileSecret, err = k8sClient.CreateFileSecret(ctx, fileSecretName, session.Namespace, fileContents, session.Labels, opts.DryRun)
+			}
 			if err != nil {
 				return nil, fmt.Errorf("failed to create secret file: %w", err)
 			}
@@ -446,16 +764,67 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 					return nil, fmt.Errorf("failed to save session: %w", err)
 				}
 
-				fmt.Printf("✅ Loaded %d secret files\n", len(fileContents))
+				fmt.Fprintf(out, "✅ Loaded %d secret files\n", len(fileContents))
 			}
 		} else if !opts.DryRun {
-			fmt.Printf("⚠️  No secret files were loaded (files may not exist)\n")
+			fmt.Fprintf(out, "⚠️  No secret files were loaded (files may not exist)\n")
+		}
+	}
+
+	// 8.7. Create editor config map (if editor config files exist)
+	editorConfigDir := config.CoalesceString(session.Editor.ConfigDir, config.DefaultEditorConfigDir)
+	if !filepath.IsAbs(editorConfigDir) {
+		base := resolvedSyncPath
+		if !syncEnabled {
+			base, _ = os.Getwd()
+		}
+		editorConfigDir = filepath.Join(base, editorConfigDir)
+	}
+
+	var editorFiles map[string]string
+	editorFiles, err = loadEditorConfigFiles(editorConfigDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(editorFiles) > 0 {
+		editorCMName = fmt.Sprintf("kodama-editor-%s", session.Name)
+		session.Editor.Files = slices.Sorted(maps.Keys(editorFiles))
+
+		if previous != nil {
+			if err = k8sClient.DeleteConfigMap(ctx, editorCMName, session.Namespace); err != nil {
+				return nil, fmt.Errorf("failed to replace editor config map: %w", err)
+			}
+		}
+
+		if !opts.DryRun {
+			if err = trackResource(store, session, kubernetes.KindConfigMap, editorCMName); err != nil {
+				return nil, err
+			}
+		}
+		var editorConfigMap *corev1.ConfigMap
+		editorConfigMap, err = k8sClient.CreateEditorConfigMap(ctx, editorCMName, session.Namespace, editorFiles, session.Labels, opts.DryRun)
+		if err != nil {
+			return nil, err
+		}
+
+		if opts.DryRun {
+			manifests.EditorConfigMap = editorConfigMap
+		} else {
+			editorCMCreated = true
+
+			session.Editor.ConfigMapName = editorCMName
+			session.Editor.ConfigMapCreated = true
+			if err = store.SaveSession(session); err != nil {
+				return nil, fmt.Errorf("failed to save session: %w", err)
+			}
+
+			fmt.Fprintf(out, "✅ Loaded %d editor config files from %s\n", len(editorFiles), editorConfigDir)
 		}
 	}
 
 	// 9. Create pod
 	if !opts.DryRun {
-		fmt.Println("⏳ Creating pod...")
+		fmt.Fprintln(out, "⏳ Creating pod...")
 	}
 
 	// Use image from session config (already resolved from CLI > template > global)
@@ -468,124 +837,198 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 		return nil, fmt.Errorf("container image is required. Specify via --image flag or set default in ~/.kodama/config.yaml")
 	}
 
-	// Determine branch name for init container (if repo mode)
-	effectiveBranch := branch
-	if repo != "" && effectiveBranch == "" {
-		// Generate default branch name if not specified
-		effectiveBranch = fmt.Sprintf("kodama/%s", opts.Name)
-	}
+	podSpec := buildPodSpec(session, secretName, fileSecretName, editorCMName)
 
-	// Determine command to run in pod
-	effectiveCommand := session.Command
-	if len(effectiveCommand) == 0 {
-		effectiveCommand = []string{"sleep", "infinity"}
+	// Protect the pod from node drains and autoscaler scale-downs
+	if session.Disruption.PodDisruptionBudget {
+		pdb, pdbErr := k8sClient.CreatePodDisruptionBudget(ctx, namespace, session.PodName, session.Labels, opts.DryRun)
+		if pdbErr != nil {
+			session.UpdateStatus(config.StatusFailed)
+			_ = store.SaveSession(session) // Best effort update
+			return nil, pdbErr
+		}
+		if opts.DryRun {
+			manifests.DisruptionBudget = pdb
+		} else {
+			pdbCreated = true
+			fmt.Fprintln(out, "✓ Pod disruption budget created")
+		}
 	}
 
-	// Build file mappings for pod spec (secretKey → destPath)
-	fileMappings := make(map[string]string)
-	if fileSecretName != "" {
-		for _, mapping := range session.SecretFile.Files {
-			secretKey := secretfile.EncodeSecretKey(mapping.Destination)
-			fileMappings[secretKey] = mapping.Destination
+	// Create the shared scratch volume on first use in the namespace
+	if session.Shared.Enabled && !opts.DryRun {
+		if sharedErr := ensureSharedVolume(ctx, k8sClient, session); sharedErr != nil {
+			session.UpdateStatus(config.StatusFailed)
+			_ = store.SaveSession(session) // Best effort update
+			return nil, sharedErr
 		}
 	}
 
-	podSpec := &kubernetes.PodSpec{
-		Name:            session.PodName,
-		Namespace:       namespace,
-		Image:           effectiveImage,
-		CPULimit:        cpu,
-		MemoryLimit:     memory,
-		CustomResources: customResources,
-		Command:         effectiveCommand,
-
-		// Environment variables secret
-		EnvSecretName: secretName,
+	// Reuse a pod from the previous attempt if it is still starting or running
+	podReused := false
+	if previous != nil {
+		podReused, err = preparePodForResume(ctx, k8sClient, podSpec)
+		if err != nil {
+			session.UpdateStatus(config.StatusFailed)
+			_ = store.SaveSession(session) // Best effort update
+			return nil, err
+		}
+	}
 
-		// Secret files to mount
-		FileSecretName: fileSecretName,
-		FileMappings:   fileMappings,
+	if podReused {
+		fmt.Fprintln(out, "✓ Reusing pod from previous attempt")
+	} else {
+		if !opts.DryRun {
+			warnQuotaShortfalls(ctx, k8sClient, podSpec)
+		}
+		pod, createErr := k8sClient.CreatePod(ctx, podSpec, opts.DryRun)
+		if createErr != nil {
+			session.UpdateStatus(config.StatusFailed)
+			_ = store.SaveSession(session) // Best effort update
+			return nil, fmt.Errorf("failed to create pod: %w", createErr)
+		}
 
-		// Git configuration for workspace-initializer init container
-		GitRepo:         repo,
-		GitBranch:       effectiveBranch,
-		GitCloneDepth:   cloneDepth,
-		GitSingleBranch: singleBranch,
-		GitCloneArgs:    gitCloneArgs,
+		if opts.DryRun {
+			manifests.Pod = pod
+			// Return session with manifests for dry-run
+			session.ManifestsGenerated = manifests
+			return session, nil
+		}
 
-		// Ttyd configuration
-		TtydEnabled:  ttydEnabled,
-		TtydPort:     ttydPort,
-		TtydOptions:  ttydOptions,
-		TtydWritable: ttydWritable,
+		podCreated = true
+		fmt.Fprintln(out, "✓ Pod created")
 	}
 
-	pod, err := k8sClient.CreatePod(ctx, podSpec, opts.DryRun)
-	if err != nil {
+	// 9b. Make the pod the owner of the secrets: cluster garbage collection
+	// deletes them together with the pod, also when it is deleted out of band
+	if err := ownSessionSecrets(ctx, k8sClient, session); err != nil {
 		session.UpdateStatus(config.StatusFailed)
 		_ = store.SaveSession(session) // Best effort update
-		return nil, fmt.Errorf("failed to create pod: %w", err)
-	}
-
-	if opts.DryRun {
-		manifests.Pod = pod
-		// Return session with manifests for dry-run
-		session.ManifestsGenerated = manifests
-		return session, nil
+		return nil, err
 	}
 
-	podCreated = true
-	fmt.Println("✓ Pod created")
-
 	// 10. Wait for pod ready (including init containers)
 	if repo != "" {
-		fmt.Printf("⏳ Waiting for init containers (installing Claude Code and cloning repository: %s)...\n", repo)
+		fmt.Fprintf(out, "⏳ Waiting for init containers (installing Claude Code and cloning repository: %s)...\n", repo)
 	} else {
-		fmt.Println("⏳ Waiting for init containers (installing Claude Code)...")
+		fmt.Fprintln(out, "⏳ Waiting for init containers (installing Claude Code)...")
+	}
+	waitCtx, readyTimeout := ctx, 5*time.Minute
+	if len(session.Tools) > 0 {
+		fmt.Fprintf(out, "⏳ Installing toolchains: %s...\n", strings.Join(session.Tools, ", "))
+		readyTimeout = toolchainReadyTimeout
+	}
+	if session.Data.IsEnabled() {
+		fmt.Fprintf(out, "⏳ Fetching dataset %s into %s...\n", session.Data.URI, session.Data.DestinationPath())
+		waitCtx = kubernetes.WithInitProgress(ctx, func(container, line string) {
+			fmt.Fprintf(out, "   %s\n", line)
+		})
+		readyTimeout = dataReadyTimeout
+	}
+	if err := k8sClient.WaitForPodReady(waitCtx, session.PodName, namespace, readyTimeout); err != nil {
+		session.UpdateStatus(config.StatusFailed)
+		_ = store.SaveSession(session) // Best effort update
+		logsHint := fmt.Sprintf("kubectl logs %s -c tools-installer -n %s\n  kubectl logs %s -c workspace-initializer -n %s",
+			session.PodName, namespace, session.PodName, namespace)
+		if session.Data.IsEnabled() {
+			logsHint += fmt.Sprintf("\n  kubectl logs %s -c %s -n %s", session.PodName, kubernetes.DataFetcherName, namespace)
+		}
+		if len(session.Tools) > 0 {
+			logsHint += fmt.Sprintf("\n  kubectl logs %s -c %s -n %s", session.PodName, kubernetes.ToolchainInstallerName, namespace)
+		}
+		return nil, fmt.Errorf("pod failed to start: %w\n\nTroubleshooting:\n  %s\n  kubectl describe pod %s -n %s",
+			err, logsHint, session.PodName, namespace)
+	}
+	fmt.Fprintln(out, "✓ Init containers completed")
+	recordEvent(ctx, store, session.Name, config.EventPodReady, "Pod ready", map[string]string{"pod": session.PodName})
+
+	// 10b. Verify the agent binaries run: a ready pod may still be installing them
+	fmt.Fprintln(out, "⏳ Verifying agent binaries...")
+	verifyStarted := time.Now()
+	versions, err := verifyAgentBinaries(ctx, session)
+	if err != nil {
+		session.UpdateStatus(config.StatusFailed)
+		_ = store.SaveSession(session) // Best effort update
+		return nil, fmt.Errorf("agent is not ready: %w\n\nTroubleshooting:\n  kubectl logs %s -c %s -n %s\n  kubectl exec %s -n %s -- sh -c 'echo $PATH; ls -l /kodama/bin'",
+			err, session.PodName, kubernetes.ToolsInstallerName, namespace, session.PodName, namespace)
 	}
-	if err := k8sClient.WaitForPodReady(ctx, session.PodName, namespace, 5*time.Minute); err != nil {
+	fmt.Fprintf(out, "✓ Agent ready (claude %s)\n", versions["claude"])
+	recordEvent(ctx, store, session.Name, config.EventAgentReady, "Agent binaries verified", agentReadyDetails(versions, time.Since(verifyStarted)))
+
+	// 10c. Apply local uncommitted changes to the clone
+	if err := overlayLocalChanges(ctx, sessionExecutor(session), session, localChanges); err != nil {
 		session.UpdateStatus(config.StatusFailed)
 		_ = store.SaveSession(session) // Best effort update
-		return nil, fmt.Errorf("pod failed to start: %w\n\nTroubleshooting:\n  kubectl logs %s -c claude-installer -n %s\n  kubectl logs %s -c workspace-initializer -n %s\n  kubectl describe pod %s -n %s",
-			err, session.PodName, namespace, session.PodName, namespace, session.PodName, namespace)
+		return nil, err
+	}
+
+	// 10d. Warn early when the shared volume is filling up
+	if session.Shared.Enabled {
+		warnSharedUsage(ctx, sessionExecutor(session), session)
 	}
-	fmt.Println("✓ Init containers completed")
 
 	// Store git metadata in session if repo mode
 	if repo != "" {
 		session.Repo = repo
-		session.Branch = effectiveBranch
 		// Note: Commit hash will be populated if needed via git operations in the pod later
 	}
 
 	// 11. Perform initial sync (if enabled) - runs AFTER init containers complete
 	if syncEnabled {
-		fmt.Printf("⏳ Syncing local files: %s → pod...\n", resolvedSyncPath)
+		fmt.Fprintf(out, "⏳ Syncing local files: %s → pod:%s...\n", resolvedSyncPath, session.Sync.RemoteRoot())
 
-		syncMgr := sync.NewSyncManager()
+		workspaceOpts := workspaceSyncOptions(globalConfig, session)
+		syncMgr := sync.NewSyncManagerWithOptions(workspaceOpts)
 
 		// Build exclude config
 		excludeCfg := buildExcludeConfig(resolvedSyncPath, globalConfig, session)
 
+		// A reused pod may already hold changes the agent made since the last sync
+		var conflictErr error
+		if podReused {
+			conflictErr = resolveSyncConflicts(ctx, workspaceOpts, store, session, excludeCfg, SyncConflictAbort)
+		}
+
 		// Perform one-time sync
-		if err := syncMgr.InitialSync(ctx, resolvedSyncPath, namespace, session.PodName, excludeCfg); err != nil {
-			fmt.Printf("⚠️  Warning: Failed to sync: %v\n", err)
-			fmt.Println("   Continuing without sync.")
+		syncStarted := time.Now()
+		if conflictErr != nil {
+			fmt.Fprintf(out, "⚠️  Warning: Skipping initial sync: %v\n", conflictErr)
+			fmt.Fprintf(out, "   Resolve with: kubectl kodama sync start %s --on-conflict push|pull\n", session.Name)
+		} else if err := syncMgr.InitialSync(ctx, resolvedSyncPath, namespace, session.PodName, excludeCfg); err != nil {
+			fmt.Fprintf(out, "⚠️  Warning: Failed to sync: %v\n", err)
+			fmt.Fprintln(out, "   Continuing without sync.")
 			session.Sync.Enabled = false
 		} else {
-			fmt.Println("✓ Initial sync completed")
+			fmt.Fprintln(out, "✓ Initial sync completed")
+			synced := recordSyncManifest(ctx, workspaceOpts, store, session, excludeCfg)
+			recordSyncEvent(ctx, store, session.Name, "Initial sync completed", sync.DiffManifests(nil, synced), syncStarted)
+			seedSandbox(ctx, session)
 		}
 
 		// Sync custom directories (dotfiles, configs, etc.)
 		customDirs := determineCustomDirs(globalConfig, session)
 		if len(customDirs) > 0 {
-			customSyncMgr := sync.NewCustomDirSyncManager(syncMgr)
+			// Custom directories live outside the workspace, in the main container
+			customSyncMgr := sync.NewCustomDirSyncManager(sync.NewSyncManagerWithOptions(syncOptions(globalConfig, session)))
 			if err := customSyncMgr.SyncCustomDirs(ctx, customDirs, namespace, session.PodName, globalConfig); err != nil {
-				fmt.Printf("⚠️  Warning: Failed to sync custom directories: %v\n", err)
+				fmt.Fprintf(out, "⚠️  Warning: Failed to sync custom directories: %v\n", err)
 			}
 		}
 	}
 
+	// 11.5. Install git hooks once the workspace is cloned or synced
+	if session.Git.InstallHooks {
+		fmt.Fprintln(out, "⏳ Installing git hooks...")
+		if err := installGitHooks(ctx, sessionExecutor(session), session); err != nil {
+			fmt.Fprintf(out, "⚠️  Warning: Failed to install git hooks: %v\n", err)
+		} else {
+			fmt.Fprintln(out, "✓ Git hooks installed")
+		}
+	}
+
+	// 11.6. Install dotfiles before anyone attaches
+	bootstrapDotfiles(ctx, session)
+
 	// 12. Update status to Running and save
 	session.UpdateStatus(config.StatusRunning)
 	session.UpdatedAt = time.Now()
@@ -594,55 +1037,156 @@ func StartSession(ctx context.Context, opts StartSessionOptions) (*config.Sessio
 	}
 
 	// 13. Execute coding agent task if prompt provided (skip in dry-run)
-	if opts.Prompt != "" || opts.PromptFile != "" {
-		var finalPrompt string
-		var promptErr error
-
-		if opts.PromptFile != "" {
-			fmt.Printf("\n⏳ Reading prompt from file: %s\n", opts.PromptFile)
-			finalPrompt, promptErr = config.ReadPromptFromFile(opts.PromptFile)
-			if promptErr != nil {
-				fmt.Printf("⚠️  Warning: Failed to read prompt file: %v\n", promptErr)
-				fmt.Println("   Session is running. You can manually invoke the agent later.")
-			} else {
-				fmt.Println("✓ Prompt loaded")
-			}
-		} else {
-			finalPrompt = opts.Prompt
+	agentErr := runStartPrompt(ctx, store, session, opts)
+
+	// Mark start as successful to skip cleanup
+	startSucceeded = true
+
+	if agentErr != nil && opts.FailOnAgentError {
+		return session, fmt.Errorf("%w: %w", ErrAgentFailed, agentErr)
+	}
+	return session, nil
+}
+
+// loadDotenvVars loads the session's dotenv files, leaving out excluded variables
+func loadDotenvVars(session *config.SessionConfig) (map[string]string, error) {
+	envVars, err := env.LoadDotenvFiles(session.Env.DotenvFiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load dotenv files: %w", err)
+	}
+
+	// Apply exclusions (default + user-specified)
+	excludeList := make([]string, len(env.DefaultExcludedVars))
+	copy(excludeList, env.DefaultExcludedVars)
+	excludeList = append(excludeList, session.Env.ExcludeVars...)
+	envVars = env.ApplyExclusions(envVars, excludeList)
+
+	// Validate variable names
+	for name := range envVars {
+		if err := env.ValidateVarName(name); err != nil {
+			return nil, fmt.Errorf("invalid variable name '%s': %w", name, err)
 		}
+	}
 
-		// Only proceed with agent execution if we have a valid prompt
-		if promptErr == nil && finalPrompt != "" {
-			// Create agent executor
-			agentExecutor := agent.NewCodingAgentExecutor()
+	// Validate secret size
+	if err := env.ValidateSecretSize(envVars); err != nil {
+		return nil, err
+	}
+	return envVars, nil
+}
 
-			// Start the agent through session
-			fmt.Println("\n🤖 Initiating coding agent...")
-			if agentErr := session.StartAgent(ctx, agentExecutor, finalPrompt); agentErr != nil {
-				// Don't fail the entire start command if agent fails
-				// The session is already created and running
-				fmt.Printf("⚠️  Warning: Failed to start coding agent: %v\n", agentErr)
-				fmt.Println("   Session is running. You can manually invoke the agent later.")
-			} else {
-				fmt.Println("✓ Agent task started")
-			}
+// runStartPrompt runs the coding agent on the prompt or plan given to start,
+// if any, and returns its error. A failed agent only warns: the session is running.
+func runStartPrompt(ctx context.Context, store *config.Store, session *config.SessionConfig, opts StartSessionOptions) error {
+	out := sync.OutputFor(ctx)
+	if opts.Prompt == "" && opts.PromptFile == "" && opts.PlanFile == "" {
+		return nil
+	}
 
-			// Save updated session with agent execution record
-			if err := store.SaveSession(session); err != nil {
-				fmt.Printf("⚠️  Warning: Failed to save agent execution record: %v\n", err)
-			}
+	var plan *config.TaskPlan
+	finalPrompt := opts.Prompt
+	switch {
+	case opts.PlanFile != "":
+		fmt.Fprintf(out, "\n⏳ Reading plan from file: %s\n", opts.PlanFile)
+		var err error
+		plan, err = config.LoadTaskPlan(opts.PlanFile)
+		if err != nil {
+			fmt.Fprintf(out, "⚠️  Warning: Failed to read plan: %v\n", err)
+			fmt.Fprintln(out, "   Session is running. You can manually invoke the agent later.")
+			return err
+		}
+		fmt.Fprintf(out, "✓ Plan loaded (%d steps)\n", len(plan.Steps))
+	case opts.PromptFile != "":
+		fmt.Fprintf(out, "\n⏳ Reading prompt from file: %s\n", opts.PromptFile)
+		var err error
+		finalPrompt, err = config.ReadPromptFromFile(opts.PromptFile)
+		if err != nil {
+			fmt.Fprintf(out, "⚠️  Warning: Failed to read prompt file: %v\n", err)
+			fmt.Fprintln(out, "   Session is running. You can manually invoke the agent later.")
+			return err
 		}
+		fmt.Fprintln(out, "✓ Prompt loaded")
+	}
+	if plan == nil && finalPrompt == "" {
+		return nil
 	}
 
-	// Mark start as successful to skip cleanup
-	startSucceeded = true
-	return session, nil
+	executor := sessionExecutor(session)
+	contextPaths, err := attachContextFiles(ctx, executor, session, contextFiles(session, opts.AttachFiles))
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: Failed to attach context files: %v\n", err)
+		fmt.Fprintln(out, "   Session is running. You can manually invoke the agent later.")
+		return err
+	}
+	var agentCmdExec kubernetes.CommandExecutor
+	if session.IsLocalRuntime() {
+		agentCmdExec = executor
+	}
+	agentExecutor, err := newAgentExecutor(store, agentCmdExec)
+	if err != nil {
+		return err
+	}
+
+	task := agentTask{store: store, session: session, executor: executor, agentExecutor: agentExecutor, saveLog: opts.SaveAgentLog}
+	if plan != nil {
+		return runPlan(ctx, plan, func(step config.PlanStep) error {
+			return task.run(ctx, agent.PromptWithContextFiles(step.Prompt, contextPaths), step.Name)
+		})
+	}
+	return task.run(ctx, agent.PromptWithContextFiles(finalPrompt, contextPaths), "")
+}
+
+// agentTask runs coding agent tasks in a started session
+type agentTask struct {
+	store         *config.Store
+	session       *config.SessionConfig
+	executor      kubernetes.CommandExecutor
+	agentExecutor agent.CodingAgentExecutor
+	saveLog       bool // Copy each task's output log into the session's local artifacts
+}
+
+// run runs the agent on prompt, records the execution, named after the plan
+// step if any, and returns the agent's error
+func (t agentTask) run(ctx context.Context, prompt, step string) error {
+	out := sync.OutputFor(ctx)
+	session := t.session
+
+	// Record HEAD first so changes the agent commits are reviewed as well
+	base, err := agent.HeadCommit(ctx, t.executor, session.Namespace, session.PodName)
+	if err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: %v\n", err)
+	}
+
+	// Start the agent through session
+	fmt.Fprintln(out, "\n🤖 Initiating coding agent...")
+	recordAgentStarted(ctx, t.store, session.Name)
+	agentErr := session.StartAgentWithOptions(ctx, t.agentExecutor, prompt, config.AgentRunOptions{BaseCommit: base, Step: step})
+	if agentErr != nil {
+		// Don't fail the entire start command if agent fails
+		// The session is already created and running
+		fmt.Fprintf(out, "⚠️  Warning: Failed to start coding agent: %v\n", agentErr)
+		fmt.Fprintln(out, "   Session is running. You can manually invoke the agent later.")
+	} else {
+		fmt.Fprintln(out, "✓ Agent task started")
+		reviewAgentRun(ctx, session)
+		printAgentChanges(out, session.GetLastAgentExecution())
+	}
+	recordAgentFinished(ctx, t.store, session, agentErr)
+	if t.saveLog {
+		saveAgentLog(ctx, t.store, session)
+	}
+
+	// Save updated session with agent execution record
+	if err := t.store.SaveSession(session); err != nil {
+		fmt.Fprintf(out, "⚠️  Warning: Failed to save agent execution record: %v\n", err)
+	}
+	return agentErr
 }
 
 // AttachSession attaches to an existing session
 func AttachSession(ctx context.Context, opts AttachSessionOptions) error {
 	// 1. Load session config
-	store, err := config.NewStore()
+	store, err := OpenStore()
 	if err != nil {
 		return fmt.Errorf("failed to initialize config store: %w", err)
 	}
@@ -650,26 +1194,62 @@ func AttachSession(ctx context.Context, opts AttachSessionOptions) error {
 	session, err := store.LoadSession(opts.Name)
 	if err != nil {
 		if errors.Is(err, config.ErrSessionNotFound) {
-			return fmt.Errorf("session '%s' not found\n\nAvailable sessions:\n  kubectl kodama list", opts.Name)
+			return fmt.Errorf("%w: %s", config.ErrSessionNotFound, opts.Name)
 		}
 		return fmt.Errorf("failed to load session: %w", err)
 	}
 
-	// 2. Determine attachment mode
+	// Local containers have no pod to lock or serve ttyd from
+	if session.IsLocalRuntime() {
+		if err := requireMainContainer(session, opts.Container); err != nil {
+			return err
+		}
+		ensureClaude(ctx, session)
+		ensureGitIdentity(ctx, session)
+		recordAttachEvent(ctx, store, session, opts, session.Runtime)
+		return attachToContainer(ctx, session, opts.Command)
+	}
+
+	// 2. Lock the session for interactive attaches; one-off commands do not lock
+	if opts.Command == "" {
+		release, err := lockSession(ctx, session, opts.KubeconfigPath, opts.Steal)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	// 3. Reinstall the Claude Code CLI if it went missing from the main container,
+	// and set the commit author there
+	sidecar := opts.Container != "" && opts.Container != kubernetes.MainContainerName
+	if !sidecar {
+		ensureClaude(ctx, session)
+		ensureGitIdentity(ctx, session)
+	}
+
+	// 4. Determine attachment mode
 	// Use ttyd mode if: ttyd is enabled in session AND --tty flag is not set
+	// ttyd only runs in the main container, so other containers always use TTY mode
 	ttydEnabled := session.Ttyd.Enabled != nil && *session.Ttyd.Enabled
-	if ttydEnabled && !opts.TtyMode {
+	if ttydEnabled && !opts.TtyMode && !sidecar {
+		recordAttachEvent(ctx, store, session, opts, "ttyd")
 		return attachViaTtyd(ctx, session, opts)
 	}
+	recordAttachEvent(ctx, store, session, opts, "tty")
 
 	// Fall back to traditional TTY mode
-	return AttachToSession(ctx, session, opts.Command, opts.KubeconfigPath)
+	return AttachToSession(ctx, session, opts.Command, opts.Container, opts.KubeconfigPath)
 }
 
 // AttachToSession attaches to a session using the provided session config
-func AttachToSession(ctx context.Context, session *config.SessionConfig, command, kubeconfigPath string) error {
+// An empty container attaches to the main container.
+func AttachToSession(ctx context.Context, session *config.SessionConfig, command, container, kubeconfigPath string) error {
+	if session.IsLocalRuntime() {
+		return attachToContainer(ctx, session, command)
+	}
+
 	// 1. Verify pod is running
-	k8sClient, err := kubernetes.NewClient(kubeconfigPath)
+	k8sClient, err := KubernetesClient(kubeconfigPath)
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
@@ -680,34 +1260,25 @@ func AttachToSession(ctx context.Context, session *config.SessionConfig, command
 	}
 
 	if !podStatus.Ready {
-		return fmt.Errorf("pod is not ready (status: %s)\n\nCheck pod status:\n  kubectl get pod %s -n %s\n  kubectl describe pod %s -n %s",
-			podStatus.Phase, session.PodName, session.Namespace, session.PodName, session.Namespace)
+		return kubernetes.NewPodNotReadyError(session.PodName, session.Namespace, fmt.Sprintf("(status: %s)", podStatus.Phase))
 	}
 
-	// 2. Execute kubectl exec with TTY
-	fmt.Printf("Attaching to session '%s'...\n", session.Name)
+	if container != "" {
+		if err := k8sClient.ValidateContainer(ctx, session.PodName, session.Namespace, container, false); err != nil {
+			return err
+		}
+	}
 
-	var execCmd *exec.Cmd
+	// 2. Execute kubectl exec with TTY
+	fmt.Fprintf(output, "Attaching to session '%s'...\n", session.Name)
 
-	if command != "" {
-		// Run specific command
-		//#nosec G204 -- kubectl exec with user command is the intended functionality
-		execCmd = exec.CommandContext(ctx, "kubectl", "exec", "-it",
-			"-n", session.Namespace,
-			session.PodName,
-			"--",
-			"/bin/bash", "-c", fmt.Sprintf("cd /workspace && %s", command),
-		)
-	} else {
-		// Open interactive shell
-		//#nosec G204 -- kubectl exec with session data from config store
-		execCmd = exec.CommandContext(ctx, "kubectl", "exec", "-it",
-			"-n", session.Namespace,
-			session.PodName,
-			"--",
-			"/bin/bash", "-c", "cd /workspace && exec bash",
-		)
+	args := []string{"exec", "-it", "-n", session.Namespace, session.PodName}
+	if container != "" && container != kubernetes.MainContainerName {
+		args = append(args, "-c", container)
 	}
+	args = append(append(args, "--"), terminalCommand(session, attachShellCommand(session, command, container))...)
+	//#nosec G204 -- kubectl exec with user command is the intended functionality
+	execCmd := exec.CommandContext(ctx, "kubectl", args...)
 
 	// Connect stdin/stdout/stderr
 	execCmd.Stdin = os.Stdin
@@ -717,21 +1288,230 @@ func AttachToSession(ctx context.Context, session *config.SessionConfig, command
 	return execCmd.Run()
 }
 
+// terminalCommand wraps an attach command so it runs with the local TERM, the
+// session locale and the size of the local terminal
+func terminalCommand(session *config.SessionConfig, command []string) []string {
+	cols, rows, err := term.GetSize(int(os.Stdout.Fd())) //#nosec G115 -- file descriptors fit in int
+	if err != nil {
+		cols, rows = 0, 0 // Not a terminal; the exec keeps its default size
+	}
+	return kubernetes.TerminalCommand(command, kubernetes.TerminalEnv(os.Getenv("TERM"), session.Locale), cols, rows)
+}
+
+// attachShellCommand returns the command attach runs in the pod: the session
+// shell, recorded when enabled, or command run with it
+func attachShellCommand(session *config.SessionConfig, command, container string) []string {
+	switch {
+	case container != "" && container != kubernetes.MainContainerName:
+		// Sidecar images may not have bash or a /workspace mount, and ignore the session shell
+		if command != "" {
+			return []string{"/bin/sh", "-c", "cd /workspace 2>/dev/null; " + command}
+		}
+		return []string{"/bin/sh", "-c", kubernetes.InteractiveShellScript("")}
+	case command != "":
+		return kubernetes.ShellCommand(session.Shell, command)
+	case session.Record:
+		return []string{"/bin/sh", "-c", "cd /workspace && " + kubernetes.RecordedShellScript(session.Shell)}
+	default:
+		return []string{"/bin/sh", "-c", kubernetes.InteractiveShellScript(session.Shell)}
+	}
+}
+
 // cleanupFailedStart removes Kubernetes resources created during a failed start attempt
 func cleanupFailedStart(ctx context.Context, k8sClient *kubernetes.Client, namespace, podName string, podCreated bool) {
-	fmt.Println("\n⚠️  Start command failed. Cleaning up created resources...")
+	out := sync.OutputFor(ctx)
+	fmt.Fprintln(out, "\n⚠️  Start command failed. Cleaning up created resources...")
 
 	if podCreated {
-		fmt.Println("⏳ Deleting pod...")
+		fmt.Fprintln(out, "⏳ Deleting pod...")
 		if err := k8sClient.DeletePod(ctx, podName, namespace); err != nil {
-			fmt.Printf("⚠️  Warning: Failed to delete pod: %v\n", err)
-			fmt.Printf("   Manual cleanup: kubectl delete pod %s -n %s\n", podName, namespace)
+			fmt.Fprintf(out, "⚠️  Warning: Failed to delete pod: %v\n", err)
+			fmt.Fprintf(out, "   Manual cleanup: kubectl delete pod %s -n %s\n", podName, namespace)
 		} else {
-			fmt.Println("✓ Pod deleted")
+			fmt.Fprintln(out, "✓ Pod deleted")
+		}
+	}
+
+	fmt.Fprintln(out, "✓ Cleanup completed")
+}
+
+// buildPodSpec builds the pod spec for a session from its stored config
+// Secret and ConfigMap names are passed separately because dry-run does not record them in the session.
+func buildPodSpec(session *config.SessionConfig, envSecretName, fileSecretName, editorConfigMapName string) *kubernetes.PodSpec {
+	// Determine command to run in pod
+	command := session.Command
+	if len(command) == 0 {
+		command = []string{"sleep", "infinity"}
+	}
+
+	// Build file mappings for pod spec (secretKey → destPath)
+	fileMappings := make(map[string]string)
+	if fileSecretName != "" {
+		for _, mapping := range session.SecretFile.Files {
+			secretKey := secretfile.EncodeSecretKey(mapping.Destination)
+			fileMappings[secretKey] = mapping.Destination
 		}
 	}
 
-	fmt.Println("✓ Cleanup completed")
+	return &kubernetes.PodSpec{
+		Name:            session.PodName,
+		Namespace:       session.Namespace,
+		Image:           session.Image,
+		CPULimit:        session.Resources.CPU,
+		MemoryLimit:     session.Resources.Memory,
+		CustomResources: session.Resources.CustomResources,
+		Command:         command,
+
+		// Persistent volumes (emptyDir when unset)
+		WorkspacePVC:  session.WorkspacePVC,
+		ClaudeHomePVC: session.ClaudeHomePVC,
+		CachePVC:      session.Cache.PVC,
+		SharedPVC:     sharedPVC(session),
+
+		// Proxy and extra CA bundle for init containers and the main container
+		HTTPProxy:      session.Proxy.HTTPProxy,
+		HTTPSProxy:     session.Proxy.HTTPSProxy,
+		NoProxy:        session.Proxy.NoProxy,
+		CABundleSecret: session.TLS.ExtraCABundleSecret,
+
+		// Source of Claude Code and ttyd binaries
+		InstallerSource:    session.Installer.Source,
+		InstallerConfigMap: session.Installer.ConfigMap,
+		InstallerPVC:       session.Installer.PVC,
+		InstallerImage:     session.Installer.Image,
+		InstallerPath:      session.Installer.Path,
+
+		// Environment variables secret
+		EnvSecretName: envSecretName,
+
+		// Secret files to mount
+		FileSecretName: fileSecretName,
+		FileMappings:   fileMappings,
+
+		// Git configuration for workspace-initializer init container
+		GitRepo:          session.Repo,
+		GitBranch:        session.Branch,
+		GitCloneDepth:    session.GitClone.Depth,
+		GitSingleBranch:  session.GitClone.SingleBranch,
+		GitCloneArgs:     session.GitClone.ExtraArgs,
+		GitCloneAttempts: session.GitClone.Attempts,
+		GitMirror:        session.GitClone.Mirror,
+		GitUserName:      session.GitIdentity.Name,
+		GitUserEmail:     session.GitIdentity.Email,
+		GitSigningSecret: session.GitSigning.Secret,
+		GitSigningFormat: session.GitSigning.Format,
+		GitSigningKey:    session.GitSigning.Key,
+
+		// Dataset fetched into the workspace after the clone
+		DataURI:               session.Data.URI,
+		DataDestination:       session.Data.DestinationPath(),
+		DataCredentialsSecret: session.Data.CredentialsSecret,
+		DataImage:             session.Data.Image,
+
+		// Toolchains installed with mise
+		Tools: session.Tools,
+
+		// Ttyd configuration
+		TtydEnabled:  session.Ttyd.Enabled != nil && *session.Ttyd.Enabled,
+		TtydPort:     session.Ttyd.Port,
+		TtydOptions:  session.Ttyd.Options,
+		TtydWritable: session.Ttyd.Writable != nil && *session.Ttyd.Writable,
+		Shell:        session.Shell,
+		Locale:       session.Locale,
+
+		// Editor settings
+		Editor:            session.Editor.Editor,
+		EditorConfigMap:   editorConfigMapName,
+		EditorConfigFiles: session.Editor.Files,
+		CodeServerEnabled: session.Editor.CodeServer.IsEnabled(),
+		CodeServerImage:   session.Editor.CodeServer.Image,
+		CodeServerPort:    session.Editor.CodeServer.Port,
+
+		RestartPolicy:  session.PodRestartPolicy(),
+		RecordTerminal: session.Record,
+		Watchdog:       session.Watchdog,
+		SpotFriendly:   session.SpotFriendly,
+		Sandbox:        session.Sandbox,
+		NoEvict:        session.Disruption.NoEvict,
+		Labels:         session.Labels,
+		Volumes:        volumeMountSpecs(session.Volumes),
+		PodOverrides:   session.PodOverrides,
+
+		// Expiration annotation for the reaper CronJob
+		ExpiresAt: session.ExpiresAt,
+	}
+}
+
+// volumeMountSpecs converts template volumes for the pod builder
+func volumeMountSpecs(volumes []config.VolumeConfig) []kubernetes.VolumeMountSpec {
+	if len(volumes) == 0 {
+		return nil
+	}
+	specs := make([]kubernetes.VolumeMountSpec, 0, len(volumes))
+	for _, v := range volumes {
+		specs = append(specs, kubernetes.VolumeMountSpec{
+			Name:      v.Name,
+			MountPath: v.MountPath,
+			SubPath:   v.SubPath,
+			ReadOnly:  v.ReadOnly,
+			PVC:       v.PVC,
+			ConfigMap: v.ConfigMap,
+			Secret:    v.Secret,
+			HostPath:  v.HostPath,
+		})
+	}
+	return specs
+}
+
+// warnQuotaShortfalls warns when the pod would exceed a ResourceQuota of its
+// namespace, naming the resources instead of a bare create failure
+// Clusters that don't let the user list quotas are not checked.
+func warnQuotaShortfalls(ctx context.Context, k8sClient *kubernetes.Client, spec *kubernetes.PodSpec) {
+	shortfalls, err := k8sClient.CheckQuota(ctx, spec)
+	if err != nil || len(shortfalls) == 0 {
+		return
+	}
+
+	out := sync.OutputFor(ctx)
+	fmt.Fprintf(out, "⚠️  Warning: This session exceeds the quota of namespace %s:\n", spec.Namespace)
+	for _, shortfall := range shortfalls {
+		fmt.Fprintf(out, "   %s\n", shortfall)
+	}
+	fmt.Fprintf(out, "   See: kubectl kodama quota -n %s\n", spec.Namespace)
+}
+
+// preparePodForResume checks the pod left by a previous start attempt
+// Returns true if the pod is pending or running and can be reused. A terminated
+// pod, or one whose image or resource limits differ from spec, is deleted so it
+// can be recreated.
+func preparePodForResume(ctx context.Context, k8sClient *kubernetes.Client, spec *kubernetes.PodSpec) (bool, error) {
+	out := sync.OutputFor(ctx)
+	podName, namespace := spec.Name, spec.Namespace
+	status, err := k8sClient.GetPod(ctx, podName, namespace)
+	if errors.Is(err, kubernetes.ErrPodNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check existing pod: %w", err)
+	}
+
+	if status.Phase == corev1.PodPending || status.Phase == corev1.PodRunning {
+		changes := k8sClient.PodSpecChanges(status, spec)
+		if len(changes) == 0 {
+			return true, nil
+		}
+		fmt.Fprintf(out, "⏳ Recreating pod from previous attempt (%s changed)...\n", strings.Join(changes, ", "))
+	} else {
+		fmt.Fprintf(out, "⏳ Deleting %s pod from previous attempt...\n", strings.ToLower(string(status.Phase)))
+	}
+
+	if err := k8sClient.DeletePod(ctx, podName, namespace); err != nil {
+		return false, err
+	}
+	if err := k8sClient.WaitForPodDeleted(ctx, podName, namespace, 2*time.Minute); err != nil {
+		return false, fmt.Errorf("failed waiting for old pod deletion: %w", err)
+	}
+	return false, nil
 }
 
 // determineCustomDirs returns the custom directories to sync
@@ -770,17 +1550,31 @@ func buildExcludeConfig(localPath string, globalCfg *config.GlobalConfig, sessio
 		patterns = globalCfg.Sync.Exclude
 	}
 
+	// Include patterns: session overrides global as well
+	include := config.CoalesceStringSlice(sessionCfg.Sync.Include, globalCfg.Sync.Include)
+
 	return &exclude.Config{
 		BasePath:     localPath,
 		Patterns:     patterns,
 		UseGitignore: useGitignore,
+		Include:      include,
 	}
 }
 
+// syncOptions returns the sync manager options of a session: the
+// sync.compression of the session (falling back to global), used for the tar
+// streams pushed to the pod
+func syncOptions(globalCfg *config.GlobalConfig, sessionCfg *config.SessionConfig) sync.Options {
+	// Unknown values are rejected by SessionConfig.Validate and fall back to auto
+	compression, _ := sync.ParseCompression(config.CoalesceString(sessionCfg.Sync.Compression, globalCfg.Sync.Compression))
+	return sync.Options{Compression: compression}
+}
+
 // attachViaTtyd attaches to a session using ttyd (web-based terminal)
 func attachViaTtyd(ctx context.Context, session *config.SessionConfig, opts AttachSessionOptions) error {
+	out := sync.OutputFor(ctx)
 	// 1. Create Kubernetes client
-	k8sClient, err := kubernetes.NewClient(opts.KubeconfigPath)
+	k8sClient, err := KubernetesClient(opts.KubeconfigPath)
 	if err != nil {
 		return fmt.Errorf("failed to create kubernetes client: %w", err)
 	}
@@ -792,8 +1586,7 @@ func attachViaTtyd(ctx context.Context, session *config.SessionConfig, opts Atta
 	}
 
 	if !podStatus.Ready {
-		return fmt.Errorf("pod is not ready (status: %s)\n\nCheck pod status:\n  kubectl get pod %s -n %s\n  kubectl describe pod %s -n %s",
-			podStatus.Phase, session.PodName, session.Namespace, session.PodName, session.Namespace)
+		return kubernetes.NewPodNotReadyError(session.PodName, session.Namespace, fmt.Sprintf("(status: %s)", podStatus.Phase))
 	}
 
 	// 3. Determine ports
@@ -808,9 +1601,9 @@ func attachViaTtyd(ctx context.Context, session *config.SessionConfig, opts Atta
 	}
 
 	// 4. Start port-forward
-	fmt.Printf("Starting port-forward: localhost:%d -> %s:%d...\n", localPort, session.PodName, remotePort)
+	fmt.Fprintf(out, "Starting port-forward: localhost:%d -> %s:%d...\n", localPort, session.PodName, remotePort)
 
-	portForwardCmd, err := k8sClient.StartPortForward(ctx, session.PodName, localPort, remotePort)
+	portForwardCmd, err := k8sClient.StartPortForward(ctx, session.Namespace, session.PodName, localPort, remotePort)
 	if err != nil {
 		return fmt.Errorf("failed to start port-forward: %w", err)
 	}
@@ -822,23 +1615,28 @@ func attachViaTtyd(ctx context.Context, session *config.SessionConfig, opts Atta
 		}
 	}()
 
-	fmt.Println("✓ Port-forward established")
+	fmt.Fprintln(out, "✓ Port-forward established")
 
 	// 5. Open browser if requested
 	url := fmt.Sprintf("http://localhost:%d", localPort)
 	if !opts.NoBrowser {
-		fmt.Printf("Opening browser: %s\n", url)
+		fmt.Fprintf(out, "Opening browser: %s\n", url)
 		if err := openBrowser(url); err != nil {
-			fmt.Printf("⚠️  Failed to open browser: %v\n", err)
-			fmt.Printf("   Please open manually: %s\n", url)
+			fmt.Fprintf(out, "⚠️  Failed to open browser: %v\n", err)
+			fmt.Fprintf(out, "   Please open manually: %s\n", url)
 		}
 	} else {
-		fmt.Printf("Access the terminal at: %s\n", url)
+		fmt.Fprintf(out, "Access the terminal at: %s\n", url)
 	}
 
 	// 6. Wait for port-forward process to exit (Ctrl+C or process termination)
-	fmt.Println("\nPress Ctrl+C to stop port-forward and exit")
-	return portForwardCmd.Wait()
+	fmt.Fprintln(out, "\nPress Ctrl+C to stop port-forward and exit")
+	if err := portForwardCmd.Wait(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("port-forward exited: %w", err)
+	}
+
+	fmt.Fprintln(out, "\n✓ Port-forward stopped")
+	return nil
 }
 
 // openBrowser opens a URL in the default browser
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/secretfile"
+)
+
+// sessionSecretNames returns the env and file secrets of a session
+func sessionSecretNames(session *config.SessionConfig) []string {
+	var names []string
+	if session.Env.SecretCreated && session.Env.SecretName != "" {
+		names = append(names, session.Env.SecretName)
+	}
+	if session.SecretFile.SecretCreated && session.SecretFile.SecretName != "" {
+		names = append(names, session.SecretFile.SecretName)
+	}
+	return names
+}
+
+// ownSessionSecrets makes the session pod the owner of its secrets, so cluster
+// garbage collection deletes them when the pod is deleted out of band
+func ownSessionSecrets(ctx context.Context, k8sClient *kubernetes.Client, session *config.SessionConfig) error {
+	names := sessionSecretNames(session)
+	if len(names) == 0 {
+		return nil
+	}
+	if err := k8sClient.SetSecretsOwner(ctx, session.Namespace, session.PodName, names...); err != nil {
+		return fmt.Errorf("failed to set the pod as owner of its secrets: %w", err)
+	}
+	return nil
+}
+
+// restoreSessionSecrets applies the secrets garbage collected with a lost pod
+// again from the local dotenv and secret files. Secrets orphaned by
+// DeletePodKeepingDependents or ForceDeletePod are kept as they are.
+func restoreSessionSecrets(ctx context.Context, k8sClient *kubernetes.Client, session *config.SessionConfig) error {
+	if session.Env.SecretCreated && session.Env.SecretName != "" {
+		exists, err := k8sClient.SecretExists(ctx, session.Env.SecretName, session.Namespace)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			envVars, err := loadDotenvVars(session)
+			if err != nil {
+				return err
+			}
+			secret := kubernetes.NewEnvSecret(session.Env.SecretName, session.Namespace, envVars, session.Labels)
+			if _, err := k8sClient.ApplySecret(ctx, secret, false); err != nil {
+				return fmt.Errorf("failed to restore environment secret: %w", err)
+			}
+			fmt.Fprintf(output, "✓ Environment secret restored from %s\n", strings.Join(session.Env.DotenvFiles, ", "))
+		}
+	}
+
+	if session.SecretFile.SecretCreated && session.SecretFile.SecretName != "" {
+		exists, err := k8sClient.SecretExists(ctx, session.SecretFile.SecretName, session.Namespace)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			fileContents, err := secretfile.LoadFiles(session.SecretFile.Files)
+			if err != nil {
+				return fmt.Errorf("failed to load secret files: %w", err)
+			}
+			secret := kubernetes.NewFileSecret(session.SecretFile.SecretName, session.Namespace, fileContents, session.Labels)
+			if _, err := k8sClient.ApplySecret(ctx, secret, false); err != nil {
+				return fmt.Errorf("failed to restore secret file: %w", err)
+			}
+			fmt.Fprintln(output, "✓ Secret files restored")
+		}
+	}
+
+	return nil
+}
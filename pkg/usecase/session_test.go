@@ -0,0 +1,222 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/gitcmd"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/secretfile"
+)
+
+func TestBuildPodSpec(t *testing.T) {
+	enabled := true
+	session := &config.SessionConfig{
+		Name:         "work",
+		Namespace:    "dev",
+		PodName:      "kodama-work",
+		Image:        "ghcr.io/example/dev:latest",
+		Repo:         "https://github.com/org/repo",
+		Branch:       "kodama/work",
+		WorkspacePVC: "work-pvc",
+		Resources:    config.ResourceConfig{CPU: "2", Memory: "4Gi"},
+		GitClone:     config.GitCloneConfig{Depth: 1, SingleBranch: true},
+		Ttyd:         config.TtydConfig{Enabled: &enabled, Port: 7681, Writable: &enabled},
+		Tools:        []string{"go@1.23"},
+		SecretFile: secretfile.SecretFileConfig{
+			Files: []secretfile.FileMapping{{Source: "~/.npmrc", Destination: "/root/.npmrc"}},
+		},
+	}
+
+	spec := buildPodSpec(session, "kodama-env-work", "kodama-secret-files-work", "")
+
+	assert.Equal(t, "kodama-work", spec.Name)
+	assert.Equal(t, "dev", spec.Namespace)
+	assert.Equal(t, []string{"sleep", "infinity"}, spec.Command, "default command")
+	assert.Equal(t, "work-pvc", spec.WorkspacePVC)
+	assert.Equal(t, "2", spec.CPULimit)
+	assert.Equal(t, "kodama/work", spec.GitBranch)
+	assert.Equal(t, 1, spec.GitCloneDepth)
+	assert.True(t, spec.TtydEnabled)
+	assert.True(t, spec.TtydWritable)
+	assert.Equal(t, "kodama-env-work", spec.EnvSecretName)
+	assert.Equal(t, []string{"go@1.23"}, spec.Tools)
+	assert.Len(t, spec.FileMappings, 1)
+	assert.Nil(t, spec.Volumes)
+
+	session.Volumes = []config.VolumeConfig{{Name: "datasets", MountPath: "/workspace/data", PVC: "imagenet", ReadOnly: true}}
+	spec = buildPodSpec(session, "kodama-env-work", "kodama-secret-files-work", "")
+	assert.Equal(t, []kubernetes.VolumeMountSpec{{Name: "datasets", MountPath: "/workspace/data", PVC: "imagenet", ReadOnly: true}}, spec.Volumes)
+
+	// Without a file secret no mappings are mounted
+	spec = buildPodSpec(session, "", "", "")
+	assert.Empty(t, spec.FileMappings)
+	assert.Empty(t, spec.EnvSecretName)
+	assert.Empty(t, spec.EditorConfigMap)
+}
+
+func TestAttachShellCommand(t *testing.T) {
+	session := &config.SessionConfig{Name: "work", Shell: "zsh"}
+
+	shell := attachShellCommand(session, "", "")
+	assert.Equal(t, []string{"/bin/sh", "-c", kubernetes.InteractiveShellScript("zsh")}, shell)
+
+	command := attachShellCommand(session, "make test", "")
+	assert.Equal(t, kubernetes.ShellCommand("zsh", "make test"), command)
+
+	session.Record = true
+	recorded := attachShellCommand(session, "", kubernetes.MainContainerName)
+	assert.Contains(t, recorded[2], kubernetes.RecordedShellScript("zsh"))
+
+	// Sidecars detect their own shell
+	sidecar := attachShellCommand(session, "", "code-server")
+	assert.Equal(t, []string{"/bin/sh", "-c", kubernetes.InteractiveShellScript("")}, sidecar)
+}
+
+func TestBuildPodSpec_Editor(t *testing.T) {
+	enabled := true
+	session := &config.SessionConfig{
+		Name:    "work",
+		PodName: "kodama-work",
+		Editor: config.EditorConfig{
+			Editor:     "hx",
+			Files:      []string{"helix/config.toml"},
+			CodeServer: config.CodeServerConfig{Enabled: &enabled, Port: 9000},
+		},
+	}
+
+	spec := buildPodSpec(session, "", "", "kodama-editor-work")
+
+	assert.Equal(t, "hx", spec.Editor)
+	assert.Equal(t, "kodama-editor-work", spec.EditorConfigMap)
+	assert.Equal(t, []string{"helix/config.toml"}, spec.EditorConfigFiles)
+	assert.True(t, spec.CodeServerEnabled)
+	assert.Equal(t, 9000, spec.CodeServerPort)
+}
+
+func TestStartSession_GeneratedBranch(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stubDocker(t)
+
+	orig := branchNamer
+	t.Cleanup(func() { branchNamer = orig })
+	branchNamer = &gitcmd.TimestampBranchNamer{
+		Now:     func() time.Time { return time.Date(2025, 1, 15, 14, 30, 0, 0, time.UTC) },
+		Entropy: bytes.NewReader([]byte{0xaa, 0xbb, 0xcc}),
+	}
+
+	session, err := StartSession(context.Background(), StartSessionOptions{
+		Name:    "branchy",
+		Repo:    "https://github.com/org/repo.git",
+		Image:   "ubuntu:24.04",
+		Runtime: config.RuntimeDocker,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "kodama/branchy-20250115143000-aabbcc", session.Branch)
+}
+
+func TestStartSession_Overlay(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stubDocker(t)
+
+	template := filepath.Join(t.TempDir(), ".kodama.yaml")
+	require.NoError(t, os.WriteFile(template, []byte(`image: app:dev
+labels:
+  team: payments
+overlays:
+  staging:
+    image: app:rc
+    labels:
+      stage: staging
+`), 0o600))
+
+	session, err := StartSession(context.Background(), StartSessionOptions{
+		Name:       "overlaid",
+		Repo:       "https://github.com/org/repo.git",
+		Runtime:    config.RuntimeDocker,
+		ConfigFile: template,
+		Overlay:    "staging",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "app:rc", session.Image)
+	assert.Equal(t, map[string]string{"team": "payments", "stage": "staging"}, session.Labels)
+
+	_, err = StartSession(context.Background(), StartSessionOptions{
+		Name:       "unknown-overlay",
+		Repo:       "https://github.com/org/repo.git",
+		Runtime:    config.RuntimeDocker,
+		ConfigFile: template,
+		Overlay:    "prod",
+	})
+	assert.ErrorContains(t, err, `overlay "prod" not found (available: staging)`)
+}
+
+func TestStartSession_RequiredLabels(t *testing.T) {
+	writeGlobalConfig(t, `requiredLabels:
+  - team
+  - cost-center
+`)
+	stubDocker(t)
+
+	_, err := StartSession(context.Background(), StartSessionOptions{
+		Name:    "unlabeled",
+		Repo:    "https://github.com/org/repo.git",
+		Image:   "ubuntu:24.04",
+		Runtime: config.RuntimeDocker,
+		Labels:  map[string]string{"team": "payments"},
+	})
+	require.ErrorIs(t, err, config.ErrMissingLabels)
+	assert.ErrorContains(t, err, "cost-center")
+
+	session, err := StartSession(context.Background(), StartSessionOptions{
+		Name:    "labeled",
+		Repo:    "https://github.com/org/repo.git",
+		Image:   "ubuntu:24.04",
+		Runtime: config.RuntimeDocker,
+		Labels:  map[string]string{"team": "payments", "cost-center": "cc-1234"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "cc-1234", session.Labels["cost-center"])
+}
+
+func TestStartSession_SyncRemotePath(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	stubDocker(t)
+
+	_, err := StartSession(context.Background(), StartSessionOptions{
+		Name:     "both",
+		Repo:     "https://github.com/org/repo.git",
+		SyncPath: t.TempDir(),
+		Image:    "ubuntu:24.04",
+		Runtime:  config.RuntimeDocker,
+	})
+	assert.ErrorContains(t, err, "cannot use both --sync and --repo")
+
+	_, err = StartSession(context.Background(), StartSessionOptions{
+		Name:           "outside",
+		Repo:           "https://github.com/org/repo.git",
+		SyncPath:       t.TempDir(),
+		SyncRemotePath: "/etc",
+		Image:          "ubuntu:24.04",
+		Runtime:        config.RuntimeDocker,
+	})
+	assert.ErrorContains(t, err, "sync.remotePath must be a directory inside /workspace")
+
+	_, err = StartSession(context.Background(), StartSessionOptions{
+		Name:           "local-subdir",
+		Repo:           "https://github.com/org/repo.git",
+		SyncPath:       t.TempDir(),
+		SyncRemotePath: "/workspace/services/payments",
+		Image:          "ubuntu:24.04",
+		Runtime:        config.RuntimeDocker,
+	})
+	assert.ErrorContains(t, err, "--remote-path is not supported with the docker runtime")
+}
@@ -0,0 +1,137 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+// ShareSessionOptions contains options for sharing a read-only view of a session
+type ShareSessionOptions struct {
+	Name           string
+	KubeconfigPath string
+	Expires        time.Duration // Stop sharing after this duration (0 = until revoked)
+	IngressHost    string        // Expose through an Ingress for this host (default: port-forward only)
+	IngressClass   string
+}
+
+// ShareSession starts a read-only web terminal that mirrors the session's live
+// terminal recording, so observers can watch without being able to type.
+// The terminal is protected with a random token and optionally exposed through an Ingress.
+func ShareSession(ctx context.Context, opts ShareSessionOptions) error {
+	session, k8sClient, err := loadRunningSession(ctx, opts.Name, opts.KubeconfigPath)
+	if err != nil {
+		return err
+	}
+
+	if session.Ttyd.Enabled == nil || !*session.Ttyd.Enabled {
+		return fmt.Errorf("session '%s' has no web terminal; start it with --ttyd to share it", session.Name)
+	}
+	if !session.Record {
+		return fmt.Errorf("session '%s' is not recorded; start it with --record to share its terminal", session.Name)
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return err
+	}
+
+	// 1. Start the read-only terminal in the pod
+	fmt.Fprintln(output, "⏳ Starting read-only terminal...")
+	// The token goes in on stdin so it does not show up in process listings
+	executor := &kubernetes.KubectlExecutor{}
+	_, stderr, err := executor.ExecInPodWithInput(ctx, session.Namespace, session.PodName,
+		[]string{"sh", "-c", kubernetes.ShareStartScript(opts.Expires)}, strings.NewReader(token+"\n"))
+	if err != nil {
+		return fmt.Errorf("failed to start read-only terminal: %w\n%s", err, strings.TrimSpace(stderr))
+	}
+	fmt.Fprintln(output, "✓ Read-only terminal running")
+
+	// 2. Optionally expose it through an Ingress
+	var expiresAt time.Time
+	if opts.Expires > 0 {
+		expiresAt = time.Now().Add(opts.Expires)
+	}
+
+	if opts.IngressHost != "" {
+		fmt.Fprintf(output, "⏳ Creating ingress for %s...\n", opts.IngressHost)
+		if err := k8sClient.CreateShareIngress(ctx, session.Namespace, session.PodName, opts.IngressHost, opts.IngressClass, session.Labels, expiresAt); err != nil {
+			return err
+		}
+		fmt.Fprintln(output, "✓ Ingress created")
+		fmt.Fprintf(output, "\n🔗 Share link: https://%s:%s@%s/\n", kubernetes.ShareUser, token, opts.IngressHost)
+	} else {
+		fmt.Fprintf(output, "\n🔗 Observers with cluster access can run:\n")
+		fmt.Fprintf(output, "   kubectl port-forward -n %s pod/%s %d:%d\n", session.Namespace, session.PodName, kubernetes.SharePort, kubernetes.SharePort)
+		fmt.Fprintf(output, "   and open http://%s:%s@localhost:%d/\n", kubernetes.ShareUser, token, kubernetes.SharePort)
+	}
+
+	if !expiresAt.IsZero() {
+		fmt.Fprintf(output, "\nThe link expires at %s.", expiresAt.Local().Format(time.RFC3339))
+	}
+	fmt.Fprintf(output, "\nRevoke access with: kubectl kodama share revoke %s\n", session.Name)
+	return nil
+}
+
+// RevokeShare stops the read-only terminal of a session and deletes its Ingress
+func RevokeShare(ctx context.Context, name, kubeconfigPath string) error {
+	session, k8sClient, err := loadRunningSession(ctx, name, kubeconfigPath)
+	if err != nil {
+		return err
+	}
+
+	executor := kubernetes.NewKubectlExecutor()
+	_, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName,
+		[]string{"sh", "-c", kubernetes.ShareStopScript()})
+	if err != nil {
+		return fmt.Errorf("failed to stop read-only terminal: %w\n%s", err, strings.TrimSpace(stderr))
+	}
+
+	if err := k8sClient.DeleteShareIngress(ctx, session.Namespace, session.PodName); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(output, "✓ Sharing of session '%s' revoked\n", session.Name)
+	return nil
+}
+
+// loadRunningSession loads a session and verifies that its pod is ready
+func loadRunningSession(ctx context.Context, name, kubeconfigPath string) (*config.SessionConfig, *kubernetes.Client, error) {
+	session, err := loadSession(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := requireCluster(session, "share"); err != nil {
+		return nil, nil, err
+	}
+
+	k8sClient, err := KubernetesClient(kubeconfigPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	podStatus, err := k8sClient.GetPod(ctx, session.PodName, session.Namespace)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pod not found: %w\n\nStart the session with:\n  kubectl kodama start %s", err, session.Name)
+	}
+	if !podStatus.Ready {
+		return nil, nil, kubernetes.NewPodNotReadyError(session.PodName, session.Namespace, fmt.Sprintf("(status: %s)", podStatus.Phase))
+	}
+
+	return session, k8sClient, nil
+}
+
+// generateShareToken returns a random token for observer basic auth
+func generateShareToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
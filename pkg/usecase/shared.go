@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// sharedPVC returns the shared scratch PVC the session mounts, or "" when disabled
+func sharedPVC(session *config.SessionConfig) string {
+	if !session.Shared.Enabled {
+		return ""
+	}
+	return session.Shared.PVC
+}
+
+// ensureSharedVolume creates the session's shared scratch PVC if no earlier
+// session of the namespace did
+func ensureSharedVolume(ctx context.Context, k8sClient *kubernetes.Client, session *config.SessionConfig) error {
+	created, err := k8sClient.EnsureSharedPVC(ctx, kubernetes.SharedOptions{
+		Namespace:    session.Namespace,
+		PVC:          session.Shared.PVC,
+		Size:         session.Shared.Size,
+		StorageClass: session.Shared.StorageClass,
+	})
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Fprintf(sync.OutputFor(ctx), "✓ Created shared volume PVC '%s' in namespace '%s'\n", session.Shared.PVC, session.Namespace)
+	}
+	return nil
+}
+
+// warnSharedUsage warns when the shared volume is nearly full, since a full
+// volume fails the writes of every session that mounts it
+// Failing to measure the usage is not an error.
+func warnSharedUsage(ctx context.Context, executor kubernetes.CommandExecutor, session *config.SessionConfig) {
+	stdout, _, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", kubernetes.SharedUsageScript})
+	if err != nil {
+		return
+	}
+	usage, err := kubernetes.ParseSharedUsage(stdout)
+	if err != nil || !usage.NearlyFull() {
+		return
+	}
+
+	out := sync.OutputFor(ctx)
+	fmt.Fprintf(out, "⚠️  Warning: The shared volume %s is nearly full: %s\n", session.Shared.PVC, usage)
+	fmt.Fprintf(out, "   Free space with: kubectl kodama shared clean -n %s --older-than 168h\n", session.Namespace)
+}
@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+func TestWarnSharedUsage(t *testing.T) {
+	session := &config.SessionConfig{
+		Name: "codegen", Namespace: "dev", PodName: "kodama-codegen",
+		Shared: config.SharedConfig{Enabled: true, PVC: "kodama-shared"},
+	}
+
+	var out bytes.Buffer
+	ctx := sync.WithOutput(context.Background(), &out)
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("sh -c", "nfs  1000  950  50  95% /shared\n", "", nil)
+	warnSharedUsage(ctx, executor, session)
+	if !strings.Contains(out.String(), "shared volume kodama-shared is nearly full") {
+		t.Errorf("expected a nearly full warning, got %q", out.String())
+	}
+
+	out.Reset()
+	executor = kubernetes.NewMockExecutor()
+	executor.SetResponse("sh -c", "nfs  1000  100  900  10% /shared\n", "", nil)
+	warnSharedUsage(ctx, executor, session)
+	if out.Len() != 0 {
+		t.Errorf("expected no warning below %d%%, got %q", kubernetes.SharedWarnPercent, out.String())
+	}
+
+	if got := sharedPVC(session); got != "kodama-shared" {
+		t.Errorf("sharedPVC() = %q, want kodama-shared", got)
+	}
+	session.Shared.Enabled = false
+	if got := sharedPVC(session); got != "" {
+		t.Errorf("sharedPVC() = %q for a disabled volume", got)
+	}
+}
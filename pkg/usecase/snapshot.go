@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/procutil"
+	"github.com/illumination-k/kodama/pkg/snapshot"
+)
+
+// SnapshotSessionOptions contains options for snapshotting a session workspace
+type SnapshotSessionOptions struct {
+	Name           string
+	KubeconfigPath string
+	Output         string // Local path or s3:// / gs:// URL (default: snapshot store or current directory)
+}
+
+// SnapshotSession archives a session's /workspace and returns where it was written
+// Patterns from the session's sync excludes and snapshot.exclude are skipped.
+// The .git directory is kept so the snapshot carries the branch history.
+func SnapshotSession(ctx context.Context, opts SnapshotSessionOptions) (string, error) {
+	session, err := loadSession(opts.Name)
+	if err != nil {
+		return "", err
+	}
+	if err := requireCluster(session, "snapshot"); err != nil {
+		return "", err
+	}
+	k8sClient, err := KubernetesClient(opts.KubeconfigPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	store, err := OpenStore()
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize config store: %w", err)
+	}
+	globalConfig, err := store.LoadGlobalConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	podStatus, err := k8sClient.GetPod(ctx, session.PodName, session.Namespace)
+	if err != nil {
+		return "", fmt.Errorf("pod not found: %w\n\nStart the session with:\n  kubectl kodama start %s", err, session.Name)
+	}
+	if !podStatus.Ready {
+		return "", kubernetes.NewPodNotReadyError(session.PodName, session.Namespace, fmt.Sprintf("(status: %s)", podStatus.Phase))
+	}
+
+	location := snapshot.Destination(opts.Output, globalConfig.Snapshot.Store, session.Name, time.Now())
+	excludes := snapshotExcludes(globalConfig, session)
+
+	fmt.Fprintf(output, "📦 Snapshotting %s of session '%s' → %s...\n", snapshot.WorkspacePath, session.Name, location)
+
+	writer, err := snapshot.Create(ctx, location)
+	if err != nil {
+		return "", err
+	}
+
+	counter := &byteCounter{}
+	var stderr strings.Builder
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	tarCmd := procutil.CommandContext(ctx, "kubectl", append([]string{"exec",
+		"-n", session.Namespace,
+		session.PodName,
+		"--",
+	}, snapshot.CreateArgs(excludes)...)...)
+	tarCmd.Stdout = io.MultiWriter(writer, counter)
+	tarCmd.Stderr = &stderr
+
+	runErr := tarCmd.Run()
+	closeErr := writer.Close()
+	if (runErr != nil || closeErr != nil) && !snapshot.IsRemote(location) {
+		// Do not leave a truncated snapshot behind
+		_ = os.Remove(location)
+	}
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if runErr != nil {
+		return "", fmt.Errorf("failed to archive workspace: %w: %s", runErr, strings.TrimSpace(stderr.String()))
+	}
+	if closeErr != nil {
+		return "", fmt.Errorf("failed to write snapshot: %w", closeErr)
+	}
+
+	fmt.Fprintf(output, "✓ Snapshot written (%s)\n", formatBytes(counter.n))
+	return location, nil
+}
+
+// RestoreSessionOptions contains options for seeding a new session from a snapshot
+type RestoreSessionOptions struct {
+	From  string              // Local path or s3:// / gs:// URL
+	Start StartSessionOptions // Options for the new session; sync and repo are not used
+}
+
+// RestoreSession starts a new session with an empty workspace and extracts a
+// snapshot into it
+func RestoreSession(ctx context.Context, opts RestoreSessionOptions) (*config.SessionConfig, error) {
+	if opts.From == "" {
+		return nil, fmt.Errorf("snapshot location is required (--from)")
+	}
+	if opts.Start.Runtime == config.RuntimeDocker || opts.Start.Runtime == config.RuntimePodman {
+		return nil, fmt.Errorf("restore is %w: snapshots are extracted into a pod", ErrClusterOnly)
+	}
+	if !snapshot.IsRemote(opts.From) {
+		// Fail before creating anything if a local snapshot is missing
+		if _, err := os.Stat(opts.From); err != nil {
+			return nil, fmt.Errorf("snapshot not found: %w", err)
+		}
+	}
+
+	startOpts := opts.Start
+	startOpts.Repo = ""
+	startOpts.SyncPath = ""
+	startOpts.NoSync = true
+
+	session, err := StartSession(ctx, startOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Fprintf(output, "⏳ Restoring %s → %s...\n", opts.From, snapshot.WorkspacePath)
+
+	reader, err := snapshot.Open(ctx, opts.From)
+	if err != nil {
+		return session, err
+	}
+
+	counter := &byteCounter{}
+	var stderr strings.Builder
+	//#nosec G204 -- kubectl exec with namespace/pod from session config
+	untarCmd := procutil.CommandContext(ctx, "kubectl", append([]string{"exec", "-i",
+		"-n", session.Namespace,
+		session.PodName,
+		"--",
+	}, snapshot.ExtractArgs()...)...)
+	untarCmd.Stdin = io.TeeReader(reader, counter)
+	untarCmd.Stderr = &stderr
+
+	runErr := untarCmd.Run()
+	closeErr := reader.Close()
+	if ctx.Err() != nil {
+		runErr = ctx.Err()
+	}
+	if runErr != nil {
+		return session, fmt.Errorf("failed to extract snapshot: %w: %s\n\nThe session was created; delete it with:\n  kubectl kodama delete %s",
+			runErr, strings.TrimSpace(stderr.String()), session.Name)
+	}
+	if closeErr != nil {
+		return session, closeErr
+	}
+
+	fmt.Fprintf(output, "✓ Workspace restored (%s)\n", formatBytes(counter.n))
+	return session, nil
+}
+
+// snapshotExcludes returns the tar patterns skipped by a snapshot
+func snapshotExcludes(globalConfig *config.GlobalConfig, session *config.SessionConfig) []string {
+	patterns := globalConfig.Sync.Exclude
+	if len(session.Sync.Exclude) > 0 {
+		patterns = session.Sync.Exclude
+	}
+
+	excludes := make([]string, 0, len(patterns)+len(globalConfig.Snapshot.Exclude))
+	for _, pattern := range patterns {
+		// .git is excluded from sync but is what makes a snapshot restorable
+		if pattern == ".git" || pattern == ".git/" {
+			continue
+		}
+		excludes = append(excludes, pattern)
+	}
+	return append(excludes, globalConfig.Snapshot.Exclude...)
+}
+
+// byteCounter counts bytes written to it
+type byteCounter struct {
+	n int64
+}
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// formatBytes renders a byte count for progress output
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+const (
+	sshPrivateKeyFile = "id_ecdsa"
+	sshPublicKeyFile  = "id_ecdsa.pub"
+	sshConfigFile     = "config"
+)
+
+// SSHSessionOptions contains options for exposing a session over SSH
+type SSHSessionOptions struct {
+	Name           string
+	KubeconfigPath string
+	LocalPort      int // Default: kubernetes.SSHPort
+}
+
+// SSHSession starts sshd in the session pod, authorizes the session's key pair
+// (generated on first use) and port-forwards it to localhost so that remote IDEs
+// such as JetBrains Gateway and VS Code Remote-SSH can connect. It blocks until
+// the port-forward is stopped.
+func SSHSession(ctx context.Context, opts SSHSessionOptions) error {
+	// 1. Load session
+	store, err := OpenStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize config store: %w", err)
+	}
+
+	session, err := store.LoadSession(opts.Name)
+	if err != nil {
+		if errors.Is(err, config.ErrSessionNotFound) {
+			return fmt.Errorf("%w: %s", config.ErrSessionNotFound, opts.Name)
+		}
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+	if err := requireCluster(session, "ssh"); err != nil {
+		return err
+	}
+
+	// 2. Verify pod is running
+	k8sClient, err := KubernetesClient(opts.KubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	podStatus, err := k8sClient.GetPod(ctx, session.PodName, session.Namespace)
+	if err != nil {
+		return fmt.Errorf("pod not found: %w\n\nStart the session with:\n  kubectl kodama start %s", err, session.Name)
+	}
+	if !podStatus.Ready {
+		return kubernetes.NewPodNotReadyError(session.PodName, session.Namespace, fmt.Sprintf("(status: %s)", podStatus.Phase))
+	}
+
+	// 3. Ensure key pair
+	sshDir := store.GetSSHDir(session.Name)
+	publicKey, err := ensureSSHKeyPair(sshDir, "kodama-"+session.Name)
+	if err != nil {
+		return err
+	}
+
+	// 4. Start sshd in the pod
+	fmt.Fprintln(output, "⏳ Starting sshd in the session pod...")
+	executor := kubernetes.NewKubectlExecutor()
+	_, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName,
+		[]string{"sh", "-c", kubernetes.SSHDSetupScript(), "sh", publicKey})
+	if err != nil {
+		return fmt.Errorf("failed to start sshd: %w\n%s", err, strings.TrimSpace(stderr))
+	}
+	fmt.Fprintln(output, "✓ sshd running")
+
+	// 5. Write SSH client config
+	localPort := opts.LocalPort
+	if localPort == 0 {
+		localPort = kubernetes.SSHPort
+	}
+
+	host := "kodama-" + session.Name
+	configPath := filepath.Join(sshDir, sshConfigFile)
+	if err := os.WriteFile(configPath, []byte(sshClientConfig(host, localPort, filepath.Join(sshDir, sshPrivateKeyFile))), 0o600); err != nil {
+		return fmt.Errorf("failed to write ssh config: %w", err)
+	}
+
+	// 6. Start port-forward
+	fmt.Fprintf(output, "Starting port-forward: localhost:%d -> %s:%d...\n", localPort, session.PodName, kubernetes.SSHPort)
+
+	portForwardCmd, err := k8sClient.StartPortForward(ctx, session.Namespace, session.PodName, localPort, kubernetes.SSHPort)
+	if err != nil {
+		return fmt.Errorf("failed to start port-forward: %w", err)
+	}
+
+	// Ensure port-forward is cleaned up on exit
+	defer func() {
+		if portForwardCmd.Process != nil {
+			_ = portForwardCmd.Process.Kill()
+		}
+	}()
+
+	fmt.Fprintln(output, "✓ Port-forward established")
+
+	fmt.Fprintf(output, "\n💻 Connect with:\n")
+	fmt.Fprintf(output, "   ssh -F %s %s\n", configPath, host)
+	fmt.Fprintf(output, "\n   JetBrains Gateway / VS Code Remote-SSH: add this to ~/.ssh/config\n")
+	fmt.Fprintf(output, "     Include %s\n", configPath)
+	fmt.Fprintf(output, "   then connect to host '%s' and open /workspace\n", host)
+
+	// 7. Wait for port-forward process to exit (Ctrl+C or process termination)
+	fmt.Fprintln(output, "\nPress Ctrl+C to stop port-forward and exit")
+	if err := portForwardCmd.Wait(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("port-forward exited: %w", err)
+	}
+
+	fmt.Fprintln(output, "\n✓ Port-forward stopped")
+	return nil
+}
+
+// sshClientConfig returns an ssh_config host entry for a forwarded session.
+// Host keys are regenerated with every pod, so they are not checked.
+func sshClientConfig(host string, port int, identityFile string) string {
+	return fmt.Sprintf(`Host %s
+  HostName localhost
+  Port %d
+  User root
+  IdentityFile %s
+  IdentitiesOnly yes
+  StrictHostKeyChecking no
+  UserKnownHostsFile /dev/null
+  LogLevel ERROR
+`, host, port, identityFile)
+}
+
+// ensureSSHKeyPair returns the authorized_keys line for the key pair in dir,
+// generating the pair if it does not exist yet
+func ensureSSHKeyPair(dir, comment string) (string, error) {
+	publicKeyPath := filepath.Join(dir, sshPublicKeyFile)
+	if data, err := os.ReadFile(publicKeyPath); err == nil { // #nosec G304 -- path under the kodama config directory
+		return strings.TrimSpace(string(data)), nil
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("failed to read ssh public key: %w", err)
+	}
+
+	privateKeyPEM, publicKey, err := generateSSHKeyPair(comment)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("failed to create ssh directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, sshPrivateKeyFile), privateKeyPEM, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write ssh private key: %w", err)
+	}
+	if err := os.WriteFile(publicKeyPath, []byte(publicKey+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write ssh public key: %w", err)
+	}
+
+	return publicKey, nil
+}
+
+// generateSSHKeyPair creates an ECDSA P-256 key pair, returning the private key
+// as PEM (accepted by OpenSSH) and the public key in authorized_keys format
+func generateSSHKeyPair(comment string) ([]byte, string, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate ssh key: %w", err)
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode ssh private key: %w", err)
+	}
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+
+	ecdhKey, err := key.PublicKey.ECDH()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode ssh public key: %w", err)
+	}
+
+	// RFC 5656 public key blob: key type, curve name, uncompressed point
+	const keyType = "ecdsa-sha2-nistp256"
+	var blob bytes.Buffer
+	for _, field := range [][]byte{[]byte(keyType), []byte("nistp256"), ecdhKey.Bytes()} {
+		_ = binary.Write(&blob, binary.BigEndian, uint32(len(field))) //#nosec G115 -- fields are a few dozen bytes
+		blob.Write(field)
+	}
+
+	publicKey := fmt.Sprintf("%s %s %s", keyType, base64.StdEncoding.EncodeToString(blob.Bytes()), comment)
+	return privateKeyPEM, publicKey, nil
+}
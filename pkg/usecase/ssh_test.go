@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateSSHKeyPair(t *testing.T) {
+	privateKeyPEM, publicKey, err := generateSSHKeyPair("kodama-work")
+	require.NoError(t, err)
+
+	block, _ := pem.Decode(privateKeyPEM)
+	require.NotNil(t, block)
+	assert.Equal(t, "EC PRIVATE KEY", block.Type)
+	_, err = x509.ParseECPrivateKey(block.Bytes)
+	require.NoError(t, err)
+
+	fields := strings.Fields(publicKey)
+	require.Len(t, fields, 3)
+	assert.Equal(t, "ecdsa-sha2-nistp256", fields[0])
+	assert.Equal(t, "kodama-work", fields[2])
+
+	blob, err := base64.StdEncoding.DecodeString(fields[1])
+	require.NoError(t, err)
+	// 4+19 key type, 4+8 curve name, 4+65 uncompressed point
+	assert.Len(t, blob, 104)
+	assert.Equal(t, "ecdsa-sha2-nistp256", string(blob[4:23]))
+}
+
+func TestEnsureSSHKeyPair_ReusesExistingKey(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "work")
+
+	first, err := ensureSSHKeyPair(dir, "kodama-work")
+	require.NoError(t, err)
+	second, err := ensureSSHKeyPair(dir, "kodama-work")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	info, err := os.Stat(filepath.Join(dir, sshPrivateKeyFile))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
+
+func TestSSHClientConfig(t *testing.T) {
+	cfg := sshClientConfig("kodama-work", 2222, "/home/me/.kodama/ssh/work/id_ecdsa")
+
+	assert.Contains(t, cfg, "Host kodama-work\n")
+	assert.Contains(t, cfg, "  Port 2222\n")
+	assert.Contains(t, cfg, "  IdentityFile /home/me/.kodama/ssh/work/id_ecdsa\n")
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/sync"
+	"github.com/illumination-k/kodama/pkg/sync/exclude"
+)
+
+// ErrSyncConflict is returned when a sync would overwrite files changed in the pod
+var ErrSyncConflict = errors.New("files changed in the pod since the last sync")
+
+// Ways to resolve files changed both locally and in the pod
+const (
+	// SyncConflictPush overwrites the pod's copies with the local files
+	SyncConflictPush = "push"
+	// SyncConflictPull copies the pod's versions to the local machine before syncing
+	SyncConflictPull = "pull"
+	// SyncConflictAbort stops without syncing
+	SyncConflictAbort = "abort"
+)
+
+// remoteHashBatch is how many pod files are hashed per exec
+const remoteHashBatch = 500
+
+// Pod file transfers; replaced in tests
+var (
+	pullSyncFiles       = sync.PullFiles
+	pushSyncFiles       = sync.PushFiles
+	writeRemoteManifest = sync.WriteRemoteManifest
+)
+
+// ValidateSyncConflictMode checks an --on-conflict value; "" means ask
+func ValidateSyncConflictMode(mode string) error {
+	switch mode {
+	case "", SyncConflictPush, SyncConflictPull, SyncConflictAbort:
+		return nil
+	}
+	return fmt.Errorf("invalid conflict mode %q (must be %s, %s or %s)", mode, SyncConflictPush, SyncConflictPull, SyncConflictAbort)
+}
+
+// resolveSyncConflicts compares the pod workspace with the local sync path
+// before a sync overwrites it. Files changed in the pod since the last sync are
+// listed and handled as mode says; ErrSyncConflict means the sync must not run.
+func resolveSyncConflicts(ctx context.Context, opts sync.Options, store *config.Store, session *config.SessionConfig, excludeCfg *exclude.Config, mode string) error {
+	conflicts, err := detectSyncConflicts(ctx, store, session, excludeCfg)
+	if err != nil {
+		// Without the pod's state there is nothing to compare; sync as before
+		fmt.Fprintf(output, "⚠️  Warning: Failed to check for sync conflicts: %v\n", err)
+		return nil
+	}
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	fmt.Fprintf(output, "⚠️  %d file(s) changed in the pod since the last sync:\n", len(conflicts))
+	for _, path := range conflicts {
+		fmt.Fprintf(output, "   %s\n", path)
+	}
+
+	switch mode {
+	case SyncConflictPush:
+		fmt.Fprintln(output, "🔄 Overwriting the pod's copies with local files")
+		return nil
+	case SyncConflictPull:
+		fmt.Fprintln(output, "⏳ Copying the pod's versions to the local machine...")
+		if err := pullSyncFiles(ctx, opts, session.Sync.LocalPath, session.Sync.RemoteRoot(), session.Namespace, session.PodName, conflicts); err != nil {
+			return fmt.Errorf("%w: %w", ErrSyncFailed, err)
+		}
+		fmt.Fprintf(output, "✓ Pulled %d file(s)\n", len(conflicts))
+		return nil
+	}
+	return fmt.Errorf("%w: %d file(s) would be overwritten", ErrSyncConflict, len(conflicts))
+}
+
+// detectSyncConflicts returns the files a push would overwrite although they
+// were changed in the pod
+func detectSyncConflicts(ctx context.Context, store *config.Store, session *config.SessionConfig, excludeCfg *exclude.Config) ([]string, error) {
+	remote, recorded, err := podWorkspaceManifest(ctx, session, excludeCfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(remote) == 0 {
+		return nil, nil
+	}
+
+	base, err := store.LoadSyncManifest(session.Name)
+	if err != nil {
+		return nil, err
+	}
+	local, err := sync.ScanManifest(session.Sync.LocalPath, excludeCfg, base)
+	if err != nil {
+		return nil, err
+	}
+	if recorded == nil {
+		recorded = base
+	}
+	return sync.DetectConflicts(local, remote, recorded.Hashes()), nil
+}
+
+// podWorkspaceManifest returns the files in the pod workspace along with the
+// manifest the pod recorded after the last sync (nil if it has none). Only
+// files whose size or modification time changed since then are hashed.
+func podWorkspaceManifest(ctx context.Context, session *config.SessionConfig, excludeCfg *exclude.Config) (current, recorded sync.Manifest, err error) {
+	executor := workspaceExecutor(session)
+
+	stdout, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", sync.RemoteStatScript(session.Sync.RemoteRoot())})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list workspace files: %w: %s", err, strings.TrimSpace(stderr))
+	}
+	current, err = sync.ParseRemoteStat(stdout, session.Sync.LocalPath, excludeCfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// A pod synced by an older version has no manifest; every file is hashed then
+	if data, _, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, []string{"cat", sync.RemoteManifestPath}); err == nil {
+		if recorded, err = config.ParseSyncManifest([]byte(data)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	stale := sync.FillHashes(current, recorded)
+	for start := 0; start < len(stale); start += remoteHashBatch {
+		batch := stale[start:min(start+remoteHashBatch, len(stale))]
+		command := append([]string{"sh", "-c", `cd "$0" && exec sha256sum -- "$@"`, session.Sync.RemoteRoot()}, batch...)
+		stdout, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, command)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to hash workspace files: %w: %s", err, strings.TrimSpace(stderr))
+		}
+		hashes, err := sync.ParseRemoteManifest(stdout, session.Sync.LocalPath, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		for path, state := range hashes {
+			if file, ok := current[path]; ok {
+				file.Hash = state.Hash
+				current[path] = file
+			}
+		}
+	}
+	return current, recorded, nil
+}
+
+// recordSyncManifest stores the manifest of the local files just pushed, see
+// saveSyncManifest, and returns it; it returns nil when they could not be scanned
+func recordSyncManifest(ctx context.Context, opts sync.Options, store *config.Store, session *config.SessionConfig, excludeCfg *exclude.Config) sync.Manifest {
+	// Without the previous manifest every file is hashed again
+	base, _ := store.LoadSyncManifest(session.Name)
+
+	local, err := sync.ScanManifest(session.Sync.LocalPath, excludeCfg, base)
+	if err != nil {
+		fmt.Fprintf(sync.OutputFor(ctx), "⚠️  Warning: Failed to record synced files: %v\n", err)
+		return nil
+	}
+	saveSyncManifest(ctx, opts, store, session, local)
+	return local
+}
+
+// saveSyncManifest stores the manifest of a sync in the session's config
+// directory and in the pod, so the next sync can tell pod-side changes from
+// local ones and push only what changed
+func saveSyncManifest(ctx context.Context, opts sync.Options, store *config.Store, session *config.SessionConfig, manifest sync.Manifest) {
+	err := store.SaveSyncManifest(session.Name, manifest)
+	if err == nil {
+		err = writeRemoteManifest(ctx, opts, session.Namespace, session.PodName, manifest)
+	}
+	if err != nil {
+		fmt.Fprintf(sync.OutputFor(ctx), "⚠️  Warning: Failed to record synced files: %v\n", err)
+	}
+}
@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// SyncOnceOptions contains options for a one-off push of local changes
+type SyncOnceOptions struct {
+	Name string
+	// OnConflict handles files changed in the pod since the last sync, as in WatchSyncOptions
+	OnConflict string
+}
+
+// SyncDrift counts the files changed on each side since the last sync
+type SyncDrift struct {
+	Local sync.ManifestDiff
+	Pod   sync.ManifestDiff
+	// Recorded is false when the session was never synced with a manifest
+	Recorded bool
+}
+
+// SyncOnce pushes the local changes made since the last sync to the pod. The
+// manifest recorded after the last sync tells which files to copy and delete,
+// so only those are transferred; without one the whole directory is pushed.
+func SyncOnce(ctx context.Context, opts SyncOnceOptions) (*sync.ManifestDiff, error) {
+	out := sync.OutputFor(ctx)
+
+	store, session, err := loadSyncedSession(opts.Name)
+	if err != nil {
+		return nil, err
+	}
+	globalConfig, err := store.LoadGlobalConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global config: %w", err)
+	}
+	excludeCfg := buildExcludeConfig(session.Sync.LocalPath, globalConfig, session)
+	syncOpts := workspaceSyncOptions(globalConfig, session)
+
+	if err := resolveSyncConflicts(ctx, syncOpts, store, session, excludeCfg, opts.OnConflict); err != nil {
+		return nil, err
+	}
+
+	base, err := store.LoadSyncManifest(session.Name)
+	if err != nil {
+		return nil, err
+	}
+	local, err := sync.ScanManifest(session.Sync.LocalPath, excludeCfg, base)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrSyncFailed, err)
+	}
+	started := time.Now()
+
+	if len(base) == 0 {
+		fmt.Fprintf(out, "⏳ Syncing local files: %s → pod...\n", session.Sync.LocalPath)
+		if err := sync.NewSyncManagerWithOptions(syncOpts).InitialSync(ctx, session.Sync.LocalPath, session.Namespace, session.PodName, excludeCfg); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSyncFailed, err)
+		}
+		saveSyncManifest(ctx, syncOpts, store, session, local)
+		diff := sync.DiffManifests(nil, local)
+		recordSyncEvent(ctx, store, session.Name, "Sync completed", diff, started)
+		fmt.Fprintf(out, "✓ Synced %d file(s)\n", len(diff.Added))
+		return &diff, nil
+	}
+
+	diff := sync.DiffManifests(base, local)
+	if diff.Count() == 0 {
+		fmt.Fprintln(out, "✓ Already in sync")
+		return &diff, nil
+	}
+
+	if changed := diff.Changed(); len(changed) > 0 {
+		fmt.Fprintf(out, "⏳ Pushing %d changed file(s)...\n", len(changed))
+		if err := pushSyncFiles(ctx, syncOpts, session.Sync.LocalPath, session.Sync.RemoteRoot(), session.Namespace, session.PodName, changed); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSyncFailed, err)
+		}
+	}
+	if len(diff.Deleted) > 0 {
+		fmt.Fprintf(out, "⏳ Deleting %d file(s) removed locally...\n", len(diff.Deleted))
+		if err := deletePodFiles(ctx, session, diff.Deleted); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrSyncFailed, err)
+		}
+	}
+
+	saveSyncManifest(ctx, syncOpts, store, session, local)
+	recordSyncEvent(ctx, store, session.Name, "Sync completed", diff, started)
+	fmt.Fprintf(out, "✓ Synced %d added, %d modified, %d deleted file(s)\n", len(diff.Added), len(diff.Modified), len(diff.Deleted))
+	return &diff, nil
+}
+
+// SyncStatus compares both ends of a session's sync with the manifests
+// recorded after the last sync, without transferring any files
+func SyncStatus(ctx context.Context, name string) (*SyncDrift, error) {
+	store, session, err := loadSyncedSession(name)
+	if err != nil {
+		return nil, err
+	}
+	globalConfig, err := store.LoadGlobalConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load global config: %w", err)
+	}
+	excludeCfg := buildExcludeConfig(session.Sync.LocalPath, globalConfig, session)
+
+	base, err := store.LoadSyncManifest(session.Name)
+	if err != nil {
+		return nil, err
+	}
+	local, err := sync.ScanManifest(session.Sync.LocalPath, excludeCfg, base)
+	if err != nil {
+		return nil, err
+	}
+
+	remote, recorded, err := podWorkspaceManifest(ctx, session, excludeCfg)
+	if err != nil {
+		return nil, err
+	}
+	if recorded == nil {
+		recorded = base
+	}
+
+	return &SyncDrift{
+		Local:    sync.DiffManifests(base, local),
+		Pod:      sync.DiffManifests(recorded, remote),
+		Recorded: len(base) > 0,
+	}, nil
+}
+
+// loadSyncedSession loads a session that was started with --sync
+func loadSyncedSession(name string) (*config.Store, *config.SessionConfig, error) {
+	store, err := OpenStore()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize config store: %w", err)
+	}
+
+	session, err := store.LoadSession(name)
+	if err != nil {
+		if errors.Is(err, config.ErrSessionNotFound) {
+			return nil, nil, fmt.Errorf("%w: %s", config.ErrSessionNotFound, name)
+		}
+		return nil, nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	if !session.Sync.Enabled || session.Sync.LocalPath == "" {
+		return nil, nil, fmt.Errorf("session '%s' was not started with --sync", name)
+	}
+	return store, session, nil
+}
+
+// deletePodFiles removes files, relative to the sync root, from the pod
+func deletePodFiles(ctx context.Context, session *config.SessionConfig, files []string) error {
+	executor := workspaceExecutor(session)
+	for start := 0; start < len(files); start += remoteHashBatch {
+		command := []string{"rm", "-f", "--"}
+		for _, file := range files[start:min(start+remoteHashBatch, len(files))] {
+			command = append(command, path.Join(session.Sync.RemoteRoot(), file))
+		}
+		if _, stderr, err := executor.ExecInPod(ctx, session.Namespace, session.PodName, command); err != nil {
+			return fmt.Errorf("failed to delete files: %w: %s", err, strings.TrimSpace(stderr))
+		}
+	}
+	return nil
+}
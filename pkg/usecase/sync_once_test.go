@@ -0,0 +1,168 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// setupSyncedSession saves a --sync session of localPath and stubs pod access
+func setupSyncedSession(t *testing.T, localPath string, executor *kubernetes.MockExecutor) *config.Store {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	origExec, origPush, origWrite := newRunExecutor, pushSyncFiles, writeRemoteManifest
+	t.Cleanup(func() {
+		newRunExecutor, pushSyncFiles, writeRemoteManifest = origExec, origPush, origWrite
+		SetOutput(os.Stdout)
+	})
+	SetOutput(io.Discard)
+	newRunExecutor = func() kubernetes.CommandExecutor { return executor }
+	writeRemoteManifest = func(ctx context.Context, opts sync.Options, namespace, podName string, manifest sync.Manifest) error {
+		return nil
+	}
+
+	store, err := OpenStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	session := &config.SessionConfig{Name: "demo", Namespace: "default", PodName: "kodama-demo"}
+	session.Sync.Enabled = true
+	session.Sync.LocalPath = localPath
+	if err := store.SaveSession(session); err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSyncOnce_PushesOnlyChanges(t *testing.T) {
+	local := t.TempDir()
+	writeFiles(t, local, map[string]string{"a.txt": "a", "b.txt": "b"})
+
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("cat "+sync.RemoteManifestPath, "", "No such file", errors.New("exit 1"))
+	store := setupSyncedSession(t, local, executor)
+
+	base, err := sync.LocalManifest(local, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveSyncManifest("demo", base); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Remove(filepath.Join(local, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	writeFiles(t, local, map[string]string{"b.txt": "changed", "c.txt": "c"})
+
+	var pushed []string
+	var remoteManifest sync.Manifest
+	pushSyncFiles = func(ctx context.Context, opts sync.Options, localPath, remotePath, namespace, podName string, files []string) error {
+		pushed = files
+		return nil
+	}
+	writeRemoteManifest = func(ctx context.Context, opts sync.Options, namespace, podName string, manifest sync.Manifest) error {
+		remoteManifest = manifest
+		return nil
+	}
+
+	diff, err := SyncOnce(context.Background(), SyncOnceOptions{Name: "demo"})
+	if err != nil {
+		t.Fatalf("SyncOnce() error = %v", err)
+	}
+
+	if want := []string{"b.txt", "c.txt"}; !reflect.DeepEqual(pushed, want) {
+		t.Errorf("pushed %v, want %v", pushed, want)
+	}
+	if len(diff.Added) != 1 || len(diff.Modified) != 1 || len(diff.Deleted) != 1 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+
+	var deleted bool
+	for _, cmd := range executor.Commands {
+		if strings.Join(cmd.Command, " ") == "rm -f -- /workspace/a.txt" {
+			deleted = true
+		}
+	}
+	if !deleted {
+		t.Errorf("a.txt was not deleted from the pod: %v", executor.Commands)
+	}
+
+	recorded, err := store.LoadSyncManifest("demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := recorded["c.txt"]; !ok || len(recorded) != 2 {
+		t.Errorf("manifest not updated: %v", recorded.Hashes())
+	}
+	if len(remoteManifest) != 2 {
+		t.Errorf("pod manifest not written: %v", remoteManifest)
+	}
+}
+
+func TestSyncStatus_PodDrift(t *testing.T) {
+	local := t.TempDir()
+	writeFiles(t, local, map[string]string{"x.go": "abc"})
+
+	mtime := time.Unix(1_700_000_000, 0)
+	recorded := sync.Manifest{
+		"x.go": {Hash: "h1", Size: 3, ModTime: mtime},
+		"z.go": {Hash: "h3", Size: 1, ModTime: mtime},
+	}
+
+	executor := kubernetes.NewMockExecutor()
+	executor.SetResponse("sh -c "+sync.RemoteStatScript("/workspace"), "3 1700000000 ./x.go\n5 1700000100 ./y.go\n", "", nil)
+	executor.SetResponse("cat "+sync.RemoteManifestPath, string(config.EncodeSyncManifest(recorded)), "", nil)
+	executor.SetResponse(`sh -c cd "$0" && exec sha256sum -- "$@" /workspace`, "h2  y.go\n", "", nil)
+	store := setupSyncedSession(t, local, executor)
+
+	base, err := sync.LocalManifest(local, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveSyncManifest("demo", base); err != nil {
+		t.Fatal(err)
+	}
+
+	drift, err := SyncStatus(context.Background(), "demo")
+	if err != nil {
+		t.Fatalf("SyncStatus() error = %v", err)
+	}
+
+	if drift.Local.Count() != 0 {
+		t.Errorf("expected no local drift, got %+v", drift.Local)
+	}
+	want := sync.ManifestDiff{Added: []string{"y.go"}, Deleted: []string{"z.go"}}
+	if !reflect.DeepEqual(drift.Pod, want) {
+		t.Errorf("pod drift = %+v, want %+v", drift.Pod, want)
+	}
+
+	// Only the file whose size and time changed is hashed in the pod
+	for _, cmd := range executor.Commands {
+		if len(cmd.Command) > 2 && strings.Contains(cmd.Command[2], "sha256sum") {
+			if files := cmd.Command[4:]; !reflect.DeepEqual(files, []string{"y.go"}) {
+				t.Errorf("hashed %v, want [y.go]", files)
+			}
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// ErrSyncFailed is returned when file sync between the local machine and the pod fails
+var ErrSyncFailed = errors.New("file sync failed")
+
+// onChangeOutputLines is how many trailing output lines a failed --on-change run shows
+const onChangeOutputLines = 20
+
+// WatchSyncOptions contains options for continuous file sync
+type WatchSyncOptions struct {
+	Name string
+	// OnChange runs in /workspace after each batch of changes is synced (empty = none)
+	OnChange string
+	// OnConflict handles files changed in the pod since the last sync:
+	// push, pull or abort ("" = return ErrSyncConflict so the caller can ask)
+	OnConflict string
+}
+
+// WatchSync runs continuous file sync for a session until ctx is
+// canceled. Local changes are pushed to the pod as they happen, and
+// opts.OnChange runs in the pod after each batch of synced changes.
+func WatchSync(ctx context.Context, opts WatchSyncOptions) error {
+	store, session, err := loadSyncedSession(opts.Name)
+	if err != nil {
+		return err
+	}
+
+	globalConfig, err := store.LoadGlobalConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	syncOpts := workspaceSyncOptions(globalConfig, session)
+	syncMgr := sync.NewSyncManagerWithOptions(syncOpts)
+	excludeCfg := buildExcludeConfig(session.Sync.LocalPath, globalConfig, session)
+
+	// The first push overwrites the workspace, so check it for the agent's changes first
+	if err := resolveSyncConflicts(ctx, syncOpts, store, session, excludeCfg, opts.OnConflict); err != nil {
+		return err
+	}
+
+	if opts.OnChange != "" {
+		runner := &changeRunner{run: func() { runOnChange(ctx, session, opts.OnChange) }}
+		syncMgr.OnFlush(func([]string) { runner.trigger() })
+	}
+
+	fmt.Fprintf(output, "👀 Watching %s → %s:%s\n", session.Sync.LocalPath, session.PodName, session.Sync.RemoteRoot())
+	if opts.OnChange != "" {
+		fmt.Fprintf(output, "🧪 Running %q after each change\n", opts.OnChange)
+	}
+	started := time.Now()
+	base, _ := store.LoadSyncManifest(session.Name)
+	if err := syncMgr.Start(ctx, session.Name, session.Sync.LocalPath, session.Namespace, session.PodName, excludeCfg); err != nil {
+		recordEvent(ctx, store, session.Name, config.EventError, "Watch sync failed: "+err.Error(), nil)
+		return fmt.Errorf("%w: %w", ErrSyncFailed, err)
+	}
+	recordSyncManifest(ctx, syncOpts, store, session, excludeCfg)
+
+	<-ctx.Done()
+
+	err = syncMgr.Stop(context.Background(), session.Name)
+	if synced := recordSyncManifest(context.Background(), syncOpts, store, session, excludeCfg); synced != nil {
+		recordSyncEvent(ctx, store, session.Name, "Watch sync stopped", sync.DiffManifests(base, synced), started)
+	}
+	return err
+}
+
+// changeRunner runs a command one at a time; changes synced while it runs
+// queue a single re-run, so a burst of saves does not pile up runs
+type changeRunner struct {
+	run     func()
+	mu      gosync.Mutex
+	running bool
+	pending bool
+}
+
+// trigger starts a run, or queues one if a run is in progress
+func (r *changeRunner) trigger() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running {
+		r.pending = true
+		return
+	}
+	r.running = true
+	go r.loop()
+}
+
+// loop runs until no re-run is queued
+func (r *changeRunner) loop() {
+	for {
+		r.run()
+
+		r.mu.Lock()
+		if !r.pending {
+			r.running = false
+			r.mu.Unlock()
+			return
+		}
+		r.pending = false
+		r.mu.Unlock()
+	}
+}
+
+// runOnChange runs command in the session and prints a one-line result,
+// followed by the tail of the output when it failed
+func runOnChange(ctx context.Context, session *config.SessionConfig, command string) {
+	var out bytes.Buffer
+	result := runTestCommand(ctx, session, command, &out, &out)
+	if result == nil {
+		return
+	}
+
+	// Print the result in one write so the watcher's lines cannot split it
+	var report strings.Builder
+	duration := result.Duration.Round(100 * time.Millisecond)
+	if result.Passed {
+		fmt.Fprintf(&report, "✅ %s passed (%s)\n", command, duration)
+	} else {
+		fmt.Fprintf(&report, "❌ %s failed with exit code %d (%s)\n", command, result.ExitCode, duration)
+		lines := strings.Split(strings.TrimRight(out.String(), "\n"), "\n")
+		if len(lines) > onChangeOutputLines {
+			lines = lines[len(lines)-onChangeOutputLines:]
+		}
+		for _, line := range lines {
+			fmt.Fprintf(&report, "   %s\n", line)
+		}
+	}
+	_, _ = io.WriteString(output, report.String())
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+func TestChangeRunner_CoalescesTriggers(t *testing.T) {
+	release := make(chan struct{})
+	done := make(chan struct{}, 10)
+	var runs atomic.Int32
+	runner := &changeRunner{run: func() {
+		if runs.Add(1) == 1 {
+			<-release
+		}
+		done <- struct{}{}
+	}}
+
+	// Triggers during a run queue a single re-run
+	runner.trigger()
+	for range 5 {
+		runner.trigger()
+	}
+	close(release)
+
+	for range 2 {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for runs")
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := runs.Load(); got != 2 {
+		t.Errorf("runs = %d, want 2", got)
+	}
+
+	// Once idle, the next trigger runs again
+	runner.trigger()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for run after idle")
+	}
+}
+
+func TestRunOnChange(t *testing.T) {
+	origExec := runExec
+	t.Cleanup(func() {
+		runExec = origExec
+		SetOutput(os.Stdout)
+	})
+	var out bytes.Buffer
+	SetOutput(&out)
+	session := &config.SessionConfig{Name: "demo", Namespace: "default", PodName: "kodama-demo"}
+
+	runExec = func(ctx context.Context, program string, args []string, stdout, stderr io.Writer) error {
+		_, _ = io.WriteString(stdout, "ok  ./...\n")
+		return nil
+	}
+	runOnChange(context.Background(), session, "go test ./...")
+	if !strings.HasPrefix(out.String(), "✅ go test ./... passed (") || strings.Contains(out.String(), "ok  ./...") {
+		t.Errorf("expected a compact pass line, got %q", out.String())
+	}
+
+	out.Reset()
+	runExec = func(ctx context.Context, program string, args []string, stdout, stderr io.Writer) error {
+		for i := 1; i <= 30; i++ {
+			_, _ = fmt.Fprintf(stdout, "line %d\n", i)
+		}
+		return exec.Command("sh", "-c", "exit 2").Run()
+	}
+	runOnChange(context.Background(), session, "go test ./...")
+	got := out.String()
+	if !strings.HasPrefix(got, "❌ go test ./... failed with exit code 2 (") {
+		t.Errorf("expected a failure line, got %q", got)
+	}
+	if strings.Contains(got, "   line 10\n") || !strings.Contains(got, "   line 11\n") || !strings.Contains(got, "   line 30\n") {
+		t.Errorf("expected the last %d output lines, got %q", onChangeOutputLines, got)
+	}
+}
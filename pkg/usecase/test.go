@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/procutil"
+)
+
+// ErrNoTestCommand is returned by RunTests when neither --cmd nor test.command is set
+var ErrNoTestCommand = errors.New("no test command configured")
+
+// TestFailedError is returned by RunTests when the test command exits non-zero
+type TestFailedError struct {
+	ExitCode int
+	Duration time.Duration
+}
+
+func (e *TestFailedError) Error() string {
+	return fmt.Sprintf("tests failed with exit code %d after %s", e.ExitCode, e.Duration.Round(time.Second))
+}
+
+// RunTestsOptions contains options for running a session's test suite
+type RunTestsOptions struct {
+	Name           string
+	KubeconfigPath string
+	Command        string // Overrides the session's test.command
+}
+
+// runExec runs kubectl, docker or podman exec; replaced in tests
+var runExec = func(ctx context.Context, program string, args []string, stdout, stderr io.Writer) error {
+	//#nosec G204 -- kubectl, docker or podman exec with the user's test command is the intended functionality
+	cmd := procutil.CommandContext(ctx, program, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	return procutil.Err(ctx, cmd.Run())
+}
+
+// RunTests runs the test command in /workspace of a session, streaming its
+// output, and records the result in the session. A failing command returns a
+// *TestFailedError carrying its exit code.
+func RunTests(ctx context.Context, opts RunTestsOptions, stdout, stderr io.Writer) (*config.TestResult, error) {
+	store, err := OpenStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize config store: %w", err)
+	}
+	session, err := store.LoadSession(opts.Name)
+	if err != nil {
+		if errors.Is(err, config.ErrSessionNotFound) {
+			return nil, fmt.Errorf("%w: %s", config.ErrSessionNotFound, opts.Name)
+		}
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+
+	command := config.CoalesceString(opts.Command, session.Test.Command)
+	if command == "" {
+		return nil, fmt.Errorf("%w for session %s", ErrNoTestCommand, session.Name)
+	}
+
+	if session.IsLocalRuntime() {
+		if err := requireRunningContainer(ctx, session); err != nil {
+			return nil, err
+		}
+	} else {
+		k8sClient, err := KubernetesClient(opts.KubeconfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create kubernetes client: %w", err)
+		}
+		if err := k8sClient.ValidateContainer(ctx, session.PodName, session.Namespace, kubernetes.MainContainerName, false); err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Fprintf(output, "🧪 Running tests: %s\n", command)
+	result := runTestCommand(ctx, session, command, stdout, stderr)
+	if result == nil {
+		return nil, ctx.Err()
+	}
+
+	session.LastTest = result
+	if err := store.SaveSession(session); err != nil {
+		fmt.Fprintf(output, "⚠️  Warning: Failed to save test result: %v\n", err)
+	}
+
+	if !result.Passed {
+		return result, &TestFailedError{ExitCode: result.ExitCode, Duration: result.Duration}
+	}
+	fmt.Fprintf(output, "✓ Tests passed in %s\n", result.Duration.Round(time.Second))
+	return result, nil
+}
+
+// runTestCommand runs command with sh in the main container and returns the
+// result, or nil when the run was interrupted
+func runTestCommand(ctx context.Context, session *config.SessionConfig, command string, stdout, stderr io.Writer) *config.TestResult {
+	program, args := sessionExecArgs(session, []string{"sh", "-c", "cd /workspace && " + command})
+
+	start := time.Now()
+	err := runExec(ctx, program, args, stdout, stderr)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	// kubectl, docker and podman exec exit with the remote command's exit code
+	exitCode := 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		exitCode = exitErr.ExitCode()
+	case err != nil:
+		fmt.Fprintf(stderr, "failed to run %s: %v\n", program, err)
+		exitCode = 1
+	}
+
+	return &config.TestResult{
+		StartedAt: start,
+		Command:   command,
+		Passed:    exitCode == 0,
+		ExitCode:  exitCode,
+		Duration:  time.Since(start),
+	}
+}
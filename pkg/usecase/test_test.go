@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/illumination-k/kodama/pkg/config"
+)
+
+func TestRunTestCommand(t *testing.T) {
+	orig := runExec
+	t.Cleanup(func() { runExec = orig })
+
+	session := &config.SessionConfig{Name: "demo", Namespace: "dev", PodName: "kodama-demo"}
+
+	var gotProgram string
+	var gotArgs []string
+	runExec = func(ctx context.Context, program string, args []string, stdout, stderr io.Writer) error {
+		gotProgram = program
+		gotArgs = args
+		_, _ = io.WriteString(stdout, "ok  ./...\n")
+		return nil
+	}
+	var out bytes.Buffer
+	result := runTestCommand(context.Background(), session, "go test ./...", &out, &out)
+	if result == nil || !result.Passed || result.ExitCode != 0 || result.Command != "go test ./..." {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if gotProgram != "kubectl" {
+		t.Errorf("program = %s, want kubectl", gotProgram)
+	}
+	if got := strings.Join(gotArgs, " "); got != "exec -n dev kodama-demo -c claude-code -- sh -c cd /workspace && go test ./..." {
+		t.Errorf("unexpected kubectl args: %s", got)
+	}
+	if out.String() != "ok  ./...\n" {
+		t.Errorf("output not streamed: %q", out.String())
+	}
+
+	// The remote exit code is kept
+	runExec = func(ctx context.Context, program string, args []string, stdout, stderr io.Writer) error {
+		return exec.Command("sh", "-c", "exit 3").Run()
+	}
+	result = runTestCommand(context.Background(), session, "make test", &out, &out)
+	if result == nil || result.Passed || result.ExitCode != 3 {
+		t.Errorf("expected a failed run with exit code 3, got %+v", result)
+	}
+
+	// kubectl itself failing to start counts as a failure
+	runExec = func(ctx context.Context, program string, args []string, stdout, stderr io.Writer) error {
+		return errors.New("executable file not found")
+	}
+	result = runTestCommand(context.Background(), session, "make test", &out, &out)
+	if result == nil || result.Passed || result.ExitCode != 1 {
+		t.Errorf("expected a failed run with exit code 1, got %+v", result)
+	}
+
+	// Interrupted runs are not recorded
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if result := runTestCommand(ctx, session, "make test", &out, &out); result != nil {
+		t.Errorf("expected no result for an interrupted run, got %+v", result)
+	}
+}
+
+func TestRunTests_NoCommand(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	store, err := OpenStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveSession(&config.SessionConfig{Name: "demo", Namespace: "default"}); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = RunTests(context.Background(), RunTestsOptions{Name: "demo"}, io.Discard, io.Discard)
+	if !errors.Is(err, ErrNoTestCommand) {
+		t.Errorf("expected ErrNoTestCommand, got %v", err)
+	}
+}
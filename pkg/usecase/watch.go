@@ -0,0 +1,260 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+	"github.com/illumination-k/kodama/pkg/sync"
+)
+
+// DefaultWatchInterval is how often the session pod is checked
+const DefaultWatchInterval = 15 * time.Second
+
+// WatchSessionOptions contains options for watching a session
+type WatchSessionOptions struct {
+	Name           string
+	KubeconfigPath string
+	Interval       time.Duration // Defaults to DefaultWatchInterval
+	AutoRecreate   bool          // Rebuild the pod when it is evicted or its node is lost
+}
+
+// WatchSession monitors a session pod until ctx is canceled
+// When the pod is evicted or its node disappears the session is marked Degraded;
+// with AutoRecreate the pod is rebuilt and the initial sync re-run.
+func WatchSession(ctx context.Context, opts WatchSessionOptions) error {
+	store, err := OpenStore()
+	if err != nil {
+		return fmt.Errorf("failed to initialize config store: %w", err)
+	}
+
+	session, err := store.LoadSession(opts.Name)
+	if err != nil {
+		if errors.Is(err, config.ErrSessionNotFound) {
+			return fmt.Errorf("%w: %s", config.ErrSessionNotFound, opts.Name)
+		}
+		return fmt.Errorf("failed to load session: %w", err)
+	}
+
+	k8sClient, err := KubernetesClient(opts.KubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	fmt.Fprintf(output, "👀 Watching session '%s' (pod %s/%s, every %s)\n", session.Name, session.Namespace, session.PodName, interval)
+	switch {
+	case session.SpotFriendly:
+		fmt.Fprintln(output, "   Spot-friendly session: the pod is recreated automatically when it is preempted")
+	case !opts.AutoRecreate:
+		fmt.Fprintln(output, "   Use --auto-recreate to rebuild the pod automatically if it is lost")
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Reload each time so a concurrent delete or status change is seen
+		session, err = store.LoadSession(opts.Name)
+		if errors.Is(err, config.ErrSessionNotFound) {
+			fmt.Fprintf(output, "Session '%s' was deleted, stopping\n", opts.Name)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load session: %w", err)
+		}
+
+		if err := checkSession(ctx, store, k8sClient, session, opts); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			fmt.Fprintf(output, "⚠️  Warning: %v\n", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(output, "\n✓ Stopped watching")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// checkSession checks the pod once and updates the session status
+func checkSession(ctx context.Context, store *config.Store, k8sClient *kubernetes.Client, session *config.SessionConfig, opts WatchSessionOptions) error {
+	health, err := k8sClient.GetPodHealth(ctx, session.PodName, session.Namespace)
+	if err != nil {
+		return err
+	}
+
+	problem := health.Problem()
+	if problem == "" {
+		if session.Status == config.StatusDegraded {
+			fmt.Fprintf(output, "✓ Session '%s' recovered\n", session.Name)
+			session.UpdateStatus(config.StatusRunning)
+			return store.SaveSession(session)
+		}
+		return nil
+	}
+
+	if session.Status != config.StatusDegraded {
+		fmt.Fprintf(output, "🚨 Session '%s' degraded: %s\n", session.Name, problem)
+		session.UpdateStatus(config.StatusDegraded)
+		if err := store.SaveSession(session); err != nil {
+			return fmt.Errorf("failed to save session status: %w", err)
+		}
+	}
+
+	// Spot-friendly sessions expect preemption and are always recreated
+	if !opts.AutoRecreate && !session.SpotFriendly {
+		return nil
+	}
+
+	if err := recreateSessionPod(ctx, store, k8sClient, session); err != nil {
+		return fmt.Errorf("failed to recreate pod: %w", err)
+	}
+	return nil
+}
+
+// recreateSessionPod rebuilds a lost pod from the session config, reusing its
+// secrets (restored from the local files if they were garbage collected with
+// the pod) and persistent volumes, and re-runs the initial sync
+func recreateSessionPod(ctx context.Context, store *config.Store, k8sClient *kubernetes.Client, session *config.SessionConfig) error {
+	fmt.Fprintln(output, "♻️  Recreating pod...")
+
+	// A preempted pod is still terminating while the checkpointer pushes its final checkpoint
+	if session.SpotFriendly {
+		_ = k8sClient.WaitForPodDeleted(ctx, session.PodName, session.Namespace, kubernetes.SpotGracePeriod)
+	}
+
+	// Force delete: pods on a lost node never finish graceful termination
+	if err := k8sClient.ForceDeletePod(ctx, session.PodName, session.Namespace); err != nil {
+		return err
+	}
+	if err := k8sClient.WaitForPodDeleted(ctx, session.PodName, session.Namespace, 2*time.Minute); err != nil {
+		return err
+	}
+
+	if err := rebuildSessionPod(ctx, store, k8sClient, session); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(output, "✨ Session '%s' recreated\n", session.Name)
+	if session.Repo != "" && session.WorkspacePVC == "" && !session.SpotFriendly {
+		fmt.Fprintln(output, "   Note: the workspace was re-cloned; uncommitted changes on the lost pod are gone")
+	}
+	return nil
+}
+
+// rebuildSessionPod creates the session pod from its config after the old pod
+// is gone, reusing its persistent volumes and its secrets (restored if they were
+// garbage collected with the old pod), re-runs the initial sync and marks the
+// session Running
+func rebuildSessionPod(ctx context.Context, store *config.Store, k8sClient *kubernetes.Client, session *config.SessionConfig) error {
+	var envSecretName string
+	if session.Env.SecretCreated {
+		envSecretName = session.Env.SecretName
+	}
+	var fileSecretName string
+	if session.SecretFile.SecretCreated {
+		fileSecretName = session.SecretFile.SecretName
+	}
+
+	var editorConfigMapName string
+	if session.Editor.ConfigMapCreated {
+		editorConfigMapName = session.Editor.ConfigMapName
+	}
+
+	if err := restoreSessionSecrets(ctx, k8sClient, session); err != nil {
+		return err
+	}
+	if _, err := k8sClient.CreatePod(ctx, buildPodSpec(session, envSecretName, fileSecretName, editorConfigMapName), false); err != nil {
+		return err
+	}
+	fmt.Fprintln(output, "✓ Pod created")
+	if err := ownSessionSecrets(ctx, k8sClient, session); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(output, "⏳ Waiting for pod to become ready...")
+	if err := k8sClient.WaitForPodReady(ctx, session.PodName, session.Namespace, 5*time.Minute); err != nil {
+		return err
+	}
+	fmt.Fprintln(output, "✓ Pod ready")
+	if _, err := verifyAgentBinaries(ctx, session); err != nil {
+		return err
+	}
+
+	if session.SpotFriendly && session.Repo != "" && session.WorkspacePVC == "" {
+		restoreCheckpoint(ctx, session)
+	}
+
+	if session.Sync.Enabled {
+		globalConfig, err := store.LoadGlobalConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load global config: %w", err)
+		}
+
+		workspaceOpts := workspaceSyncOptions(globalConfig, session)
+		syncMgr := sync.NewSyncManagerWithOptions(workspaceOpts)
+		excludeCfg := buildExcludeConfig(session.Sync.LocalPath, globalConfig, session)
+
+		// A kept workspace PVC may hold changes the agent made since the last sync
+		var conflictErr error
+		if session.WorkspacePVC != "" {
+			conflictErr = resolveSyncConflicts(ctx, workspaceOpts, store, session, excludeCfg, SyncConflictAbort)
+		}
+
+		if conflictErr != nil {
+			fmt.Fprintf(output, "⚠️  Warning: Skipping re-sync: %v\n", conflictErr)
+			fmt.Fprintf(output, "   Resolve with: kubectl kodama sync start %s --on-conflict push|pull\n", session.Name)
+		} else {
+			fmt.Fprintf(output, "⏳ Re-syncing local files: %s → pod...\n", session.Sync.LocalPath)
+			if err := syncMgr.InitialSync(ctx, session.Sync.LocalPath, session.Namespace, session.PodName, excludeCfg); err != nil {
+				fmt.Fprintf(output, "⚠️  Warning: Failed to sync: %v\n", err)
+			} else {
+				fmt.Fprintln(output, "✓ Initial sync completed")
+				recordSyncManifest(ctx, workspaceOpts, store, session, excludeCfg)
+				seedSandbox(ctx, session)
+			}
+		}
+
+		if customDirs := determineCustomDirs(globalConfig, session); len(customDirs) > 0 {
+			customSyncMgr := sync.NewCustomDirSyncManager(sync.NewSyncManagerWithOptions(syncOptions(globalConfig, session)))
+			if err := customSyncMgr.SyncCustomDirs(ctx, customDirs, session.Namespace, session.PodName, globalConfig); err != nil {
+				fmt.Fprintf(output, "⚠️  Warning: Failed to sync custom directories: %v\n", err)
+			}
+		}
+	}
+
+	session.UpdateStatus(config.StatusRunning)
+	if err := store.SaveSession(session); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// restoreCheckpoint applies the last workspace checkpoint pushed by the lost
+// pod's checkpointer to the freshly cloned workspace
+// Failures only warn: the session still works from the clone.
+func restoreCheckpoint(ctx context.Context, session *config.SessionConfig) {
+	script := kubernetes.RestoreCheckpointScript(kubernetes.CheckpointRef(session.PodName))
+	stdout, stderr, err := newRunExecutor().ExecInPod(ctx, session.Namespace, session.PodName, []string{"sh", "-c", script})
+	if err != nil {
+		fmt.Fprintf(output, "⚠️  Warning: Failed to restore the workspace checkpoint: %v: %s\n", err, strings.TrimSpace(stderr))
+		return
+	}
+	if strings.Contains(stdout, "restored checkpoint") {
+		fmt.Fprintln(output, "✓ Workspace restored from the last checkpoint")
+	} else {
+		fmt.Fprintln(output, "   No workspace checkpoint found; starting from a fresh clone")
+	}
+}
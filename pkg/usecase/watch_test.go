@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/illumination-k/kodama/pkg/config"
+	"github.com/illumination-k/kodama/pkg/kubernetes"
+)
+
+func TestRestoreCheckpoint(t *testing.T) {
+	origExec := newRunExecutor
+	t.Cleanup(func() {
+		newRunExecutor = origExec
+		SetOutput(os.Stdout)
+	})
+
+	session := &config.SessionConfig{Name: "work", Namespace: "dev", PodName: "kodama-work"}
+	script := kubernetes.RestoreCheckpointScript(kubernetes.CheckpointRef("kodama-work"))
+
+	tests := []struct {
+		name   string
+		stdout string
+		err    error
+		want   string
+	}{
+		{"restored", "restored checkpoint\n", nil, "Workspace restored from the last checkpoint"},
+		{"no checkpoint", "no checkpoint\n", nil, "No workspace checkpoint found"},
+		{"failed", "", errors.New("exit status 128"), "Failed to restore the workspace checkpoint"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := kubernetes.NewMockExecutor()
+			executor.SetResponse("sh -c", tt.stdout, "", tt.err)
+			newRunExecutor = func() kubernetes.CommandExecutor { return executor }
+
+			var buf bytes.Buffer
+			SetOutput(&buf)
+			restoreCheckpoint(context.Background(), session)
+
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output = %q, want %q", buf.String(), tt.want)
+			}
+			commands := executor.GetCommands()
+			if len(commands) != 1 || commands[0].Command[2] != script {
+				t.Errorf("expected the restore script to run once, got %+v", commands)
+			}
+		})
+	}
+}